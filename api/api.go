@@ -24,6 +24,7 @@ func SetUpApi(r *gin.Engine, cfg *config.Config, strg storage.StorageI, cache *r
 
 	v1 := r.Group("/v1")
 	v1.Use(handler.AuthMiddleware())
+	v1.Use(handler.ExportMiddleware())
 
 	// User ...
 	v1.POST("/user", handler.CreateUser)
@@ -34,11 +35,21 @@ func SetUpApi(r *gin.Engine, cfg *config.Config, strg storage.StorageI, cache *r
 
 	// Category ...
 	v1.POST("/category", handler.CreateCategory)
+	v1.GET("/category/tree", handler.GetCategoryTree)
 	v1.GET("/category/:id", handler.GetByIDCategory)
+	v1.GET("/category/:id/breadcrumbs", handler.GetCategoryBreadcrumbs)
+	v1.POST("/category/:id/move", handler.MoveCategory)
 	v1.GET("/category", handler.GetListCategory)
 	v1.PUT("/category/:id", handler.UpdateCategory)
 	v1.DELETE("/category/:id", handler.DeleteCategory)
 
+	//brand
+	v1.POST("/brand", handler.CreateBrand)
+	v1.GET("/brand/:id", handler.GetByIDBrand)
+	v1.GET("/brand", handler.GetListBrand)
+	v1.PUT("/brand/:id", handler.UpdateBrand)
+	v1.DELETE("/brand/:id", handler.DeleteBrand)
+
 	//branch ...
 	v1.POST("/branch", handler.Createbranch)
 	v1.GET("/branch/:id", handler.GetByIDbranch)
@@ -55,13 +66,17 @@ func SetUpApi(r *gin.Engine, cfg *config.Config, strg storage.StorageI, cache *r
 
 	//supplier
 	v1.POST("/supplier", handler.CreateSupplier)
+	v1.GET("/supplier/aging", handler.GetSupplierAgingReport)
 	v1.GET("/supplier/:id", handler.GetByIDSupplier)
 	v1.GET("/supplier", handler.GetListSupplier)
 	v1.PUT("/supplier/:id", handler.UpdateSupplier)
 	v1.DELETE("/supplier/:id", handler.DeleteSupplier)
+	v1.POST("/supplier/:id/payment", handler.CreateSupplierPayment)
+	v1.GET("/supplier/:id/statement", handler.GetSupplierStatement)
 
 	//product
 	v1.POST("/product", handler.CreateProduct)
+	v1.GET("/product/search", handler.SearchProduct)
 	v1.GET("/product/:id", handler.GetByIDProduct)
 	v1.GET("/product", handler.GetListProduct)
 	v1.PUT("/product/:id", handler.UpdateProduct)
@@ -70,7 +85,7 @@ func SetUpApi(r *gin.Engine, cfg *config.Config, strg storage.StorageI, cache *r
 	//income
 	v1.POST("/income", handler.CreateIncome)
 	v1.GET("/income/:id", handler.GetByIDIncome)
-	v1.GET("/income", handler.GetListProduct)
+	v1.GET("/income", handler.GetListIncome)
 	v1.PUT("/income/:id", handler.UpdateIncome)
 	v1.DELETE("/income/:id", handler.DeleteIncome)
 
@@ -97,7 +112,7 @@ func SetUpApi(r *gin.Engine, cfg *config.Config, strg storage.StorageI, cache *r
 	v1.PUT("/shift/:id", handler.UpdateShift)
 	v1.DELETE("/shift/:id", handler.DeleteShift)
 
-	v1.PUT("/shift_table/:shift_table:id", handler.ShiftTable)
+	v1.PUT("/shift_table/:id", handler.ShiftTable)
 
 	//sale
 	v1.POST("/sale", handler.CreateSale)
@@ -108,6 +123,9 @@ func SetUpApi(r *gin.Engine, cfg *config.Config, strg storage.StorageI, cache *r
 
 	v1.GET("/sale/scan-barcode/:sale_id", handler.SaleScanBarcode)
 	v1.GET("/dosale/:sale_id", handler.Dosale)
+	v1.POST("/sale/:id/customer", handler.AttachSaleCustomer)
+	v1.POST("/sale/:id/cancel", handler.CancelSale)
+	v1.POST("/sale/:id/return", handler.ReturnSale)
 
 	//sale_product
 	v1.POST("/sale_products", handler.CreateSaleProduct)
@@ -130,6 +148,164 @@ func SetUpApi(r *gin.Engine, cfg *config.Config, strg storage.StorageI, cache *r
 	v1.PUT("/transaction/:id", handler.UpdateTransaction)
 	v1.DELETE("/transaction/:id", handler.DeleteTransaction)
 
+	//branch_price
+	v1.POST("/branch_price", handler.CreateBranchPrice)
+	v1.GET("/branch_price/history", handler.GetBranchPriceHistory)
+	v1.GET("/branch_price/:id", handler.GetByIDBranchPrice)
+	v1.GET("/branch_price", handler.GetListBranchPrice)
+	v1.PUT("/branch_price/:id", handler.UpdateBranchPrice)
+	v1.DELETE("/branch_price/:id", handler.DeleteBranchPrice)
+
+	//price_change
+	v1.POST("/price_change", handler.CreatePriceChange)
+	v1.GET("/price_change/:id", handler.GetByIDPriceChange)
+	v1.GET("/price_change", handler.GetListPriceChange)
+	v1.PUT("/price_change/:id", handler.UpdatePriceChange)
+	v1.DELETE("/price_change/:id", handler.DeletePriceChange)
+	v1.POST("/price_change/:id/approve", handler.ApprovePriceChange)
+	v1.POST("/price_change/:id/cancel", handler.CancelPriceChange)
+
+	//promotion
+	v1.POST("/promotion", handler.CreatePromotion)
+	v1.GET("/promotion/report", handler.GetPromotionReport)
+	v1.GET("/promotion/:id", handler.GetByIDPromotion)
+	v1.GET("/promotion", handler.GetListPromotion)
+	v1.PUT("/promotion/:id", handler.UpdatePromotion)
+	v1.DELETE("/promotion/:id", handler.DeletePromotion)
+
+	//customer
+	v1.POST("/customer", handler.CreateCustomer)
+	v1.GET("/customer/report", handler.GetRepeatCustomerReport)
+	v1.GET("/customer/:id", handler.GetByIDCustomer)
+	v1.GET("/customer", handler.GetListCustomer)
+	v1.PUT("/customer/:id", handler.UpdateCustomer)
+	v1.DELETE("/customer/:id", handler.DeleteCustomer)
+	v1.GET("/customer/:id/history", handler.GetCustomerHistory)
+	v1.POST("/customer/:id/adjust", handler.AdjustCustomerPoints)
+
+	//loyalty_tier
+	v1.POST("/loyalty_tier", handler.CreateLoyaltyTier)
+	v1.GET("/loyalty_tier/:id", handler.GetByIDLoyaltyTier)
+	v1.GET("/loyalty_tier", handler.GetListLoyaltyTier)
+	v1.PUT("/loyalty_tier/:id", handler.UpdateLoyaltyTier)
+	v1.DELETE("/loyalty_tier/:id", handler.DeleteLoyaltyTier)
+
+	//loyalty_earn_rate
+	v1.PUT("/loyalty_earn_rate", handler.SetLoyaltyEarnRate)
+	v1.GET("/loyalty_earn_rate", handler.GetListLoyaltyEarnRate)
+	v1.DELETE("/loyalty_earn_rate/:id", handler.DeleteLoyaltyEarnRate)
+
+	//gift_card
+	v1.POST("/gift_card/sell", handler.SellGiftCard)
+	v1.POST("/gift_card/store_credit", handler.CreateStoreCredit)
+	v1.GET("/gift_card/report", handler.GetGiftCardLiabilityReport)
+	v1.GET("/gift_card/balance/:code", handler.GetGiftCardBalance)
+	v1.GET("/gift_card/:id", handler.GetByIDGiftCard)
+	v1.GET("/gift_card", handler.GetListGiftCard)
+	v1.GET("/gift_card/:id/history", handler.GetGiftCardHistory)
+
+	//purchase_order
+	v1.POST("/purchase_order", handler.CreatePurchaseOrder)
+	v1.GET("/purchase_order/report", handler.GetSupplierFillRateReport)
+	v1.GET("/purchase_order/:id", handler.GetByIDPurchaseOrder)
+	v1.GET("/purchase_order", handler.GetListPurchaseOrder)
+	v1.PUT("/purchase_order/:id", handler.UpdatePurchaseOrder)
+	v1.DELETE("/purchase_order/:id", handler.DeletePurchaseOrder)
+	v1.POST("/purchase_order/:id/send", handler.SendPurchaseOrder)
+	v1.POST("/purchase_order/:id/receive", handler.ReceivePurchaseOrder)
+	v1.POST("/purchase_order/:id/close", handler.ClosePurchaseOrder)
+
+	//supplier_return
+	v1.POST("/supplier_return", handler.CreateSupplierReturn)
+	v1.GET("/supplier_return/:id", handler.GetByIDSupplierReturn)
+	v1.GET("/supplier_return", handler.GetListSupplierReturn)
+	v1.PUT("/supplier_return/:id", handler.UpdateSupplierReturn)
+	v1.DELETE("/supplier_return/:id", handler.DeleteSupplierReturn)
+	v1.POST("/supplier_return/:id/post", handler.PostSupplierReturn)
+	v1.POST("/supplier_return/:id/cancel", handler.CancelSupplierReturn)
+
+	//transfer
+	v1.POST("/transfer", handler.CreateTransfer)
+	v1.GET("/transfer/in_transit", handler.GetInTransitReport)
+	v1.GET("/transfer/:id", handler.GetByIDTransfer)
+	v1.GET("/transfer", handler.GetListTransfer)
+	v1.PUT("/transfer/:id", handler.UpdateTransfer)
+	v1.DELETE("/transfer/:id", handler.DeleteTransfer)
+	v1.POST("/transfer/:id/dispatch", handler.DispatchTransfer)
+	v1.POST("/transfer/:id/receive", handler.ReceiveTransfer)
+	v1.POST("/transfer/:id/cancel", handler.CancelTransfer)
+
+	//stock_movement
+	v1.GET("/stock_movement", handler.GetListStockMovement)
+
+	//inventory_count
+	v1.POST("/inventory_count", handler.CreateInventoryCount)
+	v1.GET("/inventory_count/:id", handler.GetByIDInventoryCount)
+	v1.GET("/inventory_count", handler.GetListInventoryCount)
+	v1.POST("/inventory_count/:id/scan", handler.ScanInventoryCount)
+	v1.POST("/inventory_count/:id/post", handler.PostInventoryCount)
+	v1.POST("/inventory_count/:id/cancel", handler.CancelInventoryCount)
+
+	//write_off
+	v1.POST("/write_off", handler.CreateWriteOff)
+	v1.GET("/write_off/report", handler.GetShrinkageReport)
+	v1.GET("/write_off/:id", handler.GetByIDWriteOff)
+	v1.GET("/write_off", handler.GetListWriteOff)
+	v1.PUT("/write_off/:id", handler.UpdateWriteOff)
+	v1.DELETE("/write_off/:id", handler.DeleteWriteOff)
+	v1.POST("/write_off/:id/post", handler.PostWriteOff)
+	v1.POST("/write_off/:id/approve", handler.ApproveWriteOff)
+	v1.POST("/write_off/:id/reject", handler.RejectWriteOff)
+	v1.POST("/write_off/:id/cancel", handler.CancelWriteOff)
+
+	//stock_lot
+	v1.GET("/stock_lot", handler.GetListStockLot)
+	v1.GET("/stock_lot/expiring", handler.GetExpiringStockLots)
+
+	//stock_level
+	v1.POST("/stock_level", handler.CreateStockLevel)
+	v1.GET("/stock_level/low_stock", handler.GetLowStock)
+	v1.GET("/stock_level/:id", handler.GetByIDStockLevel)
+	v1.GET("/stock_level", handler.GetListStockLevel)
+	v1.PUT("/stock_level/:id", handler.UpdateStockLevel)
+	v1.DELETE("/stock_level/:id", handler.DeleteStockLevel)
+
+	//replenishment
+	v1.GET("/replenishment", handler.GetReplenishment)
+	v1.POST("/replenishment/purchase_order", handler.CreateReplenishmentOrders)
+
+	//report
+	v1.GET("/report/sales", handler.GetSalesReport)
+	v1.GET("/report/sales/top_products", handler.GetTopProducts)
+	v1.GET("/report/sales/heatmap", handler.GetSalesHeatmap)
+	v1.GET("/report/cashier", handler.GetCashierReport)
+	v1.GET("/report/inventory/valuation", handler.GetInventoryValuation)
+	v1.GET("/report/inventory/aging", handler.GetStockAging)
+
+	//product_class
+	v1.POST("/product_class/run", handler.RunProductClass)
+	v1.GET("/product_class/run", handler.GetListProductClassRun)
+	v1.GET("/product_class", handler.GetListProductClass)
+
+	//export
+	v1.GET("/export", handler.GetListExportJob)
+	v1.GET("/export/:id", handler.GetByIDExportJob)
+	v1.GET("/export/:id/download", handler.DownloadExportJob)
+	v1.DELETE("/export/:id", handler.DeleteExportJob)
+
+	//product_import
+	v1.POST("/product_import", handler.CreateProductImport)
+	v1.GET("/product_import", handler.GetListProductImport)
+	v1.GET("/product_import/:id", handler.GetByIDProductImport)
+	v1.GET("/product_import/:id/rows", handler.GetListProductImportRow)
+	v1.POST("/product_import/:id/run", handler.RunProductImport)
+
+	//supplier_invoice
+	v1.POST("/supplier_invoice", handler.ImportSupplierInvoice)
+	v1.GET("/supplier_invoice", handler.GetListSupplierInvoice)
+	v1.GET("/supplier_invoice/:id", handler.GetByIDSupplierInvoice)
+	v1.PUT("/supplier_invoice/:id/line/:line", handler.ResolveSupplierInvoiceLine)
+
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 }
 
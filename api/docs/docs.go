@@ -69,7 +69,7 @@ const docTemplate = `{
         },
         "/v1/branch": {
             "get": {
-                "description": "Get a list of branch with optional filtering.",
+                "description": "Get a list of branch with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, branch_code, name, address, phone, created_at, updated_at.",
                 "consumes": [
                     "application/json"
                 ],
@@ -98,6 +98,24 @@ const docTemplate = `{
                         "description": "Search term",
                         "name": "search",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)",
+                        "name": "created_at[between]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -373,9 +391,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/category": {
+        "/v1/branch_price": {
             "get": {
-                "description": "Get a list of category with optional filtering.",
+                "description": "Get the branch price list, optionally for one branch and/or product. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, branch_id, product_id, price, effective_from, price_change_id, created_at, updated_at.",
                 "consumes": [
                     "application/json"
                 ],
@@ -383,9 +401,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "category"
+                    "branch_price"
                 ],
-                "summary": "Get a list of category",
+                "summary": "Get a list of branch prices",
                 "parameters": [
                     {
                         "type": "string",
@@ -415,19 +433,34 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Search term",
-                        "name": "search",
+                        "description": "Branch ID",
+                        "name": "branch_id[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "product_id[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of category",
+                        "description": "List of branch prices",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Category"
-                            }
+                            "$ref": "#/definitions/models.GetListBranchPriceResponse"
                         }
                     },
                     "400": {
@@ -451,7 +484,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new category in the market system.",
+                "description": "Set the retail price of a product at a branch starting from effective_from (now when empty).",
                 "consumes": [
                     "application/json"
                 ],
@@ -459,9 +492,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "category"
+                    "branch_price"
                 ],
-                "summary": "Create a new category",
+                "summary": "Create a branch price",
                 "parameters": [
                     {
                         "type": "string",
@@ -478,20 +511,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Category information",
-                        "name": "category",
+                        "description": "Branch price information",
+                        "name": "branch_price",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateCategory"
+                            "$ref": "#/definitions/models.CreateBranchPrice"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created category",
+                        "description": "Created branch price",
                         "schema": {
-                            "$ref": "#/definitions/models.Category"
+                            "$ref": "#/definitions/models.BranchPrice"
                         }
                     },
                     "400": {
@@ -515,9 +548,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/category/{id}": {
+        "/v1/branch_price/history": {
             "get": {
-                "description": "Get category details by its ID.",
+                "description": "Every price a product had at a branch with its effective window, plus the price in effect at the given moment (now when omitted).",
                 "consumes": [
                     "application/json"
                 ],
@@ -525,17 +558,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "category"
+                    "branch_price"
                 ],
-                "summary": "Get a category by ID",
+                "summary": "Branch price history",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Category ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
                     {
                         "type": "string",
                         "description": "Authentication token",
@@ -549,13 +575,33 @@ const docTemplate = `{
                         "name": "Password",
                         "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch ID",
+                        "name": "branch_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "product_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Moment, e.g. 2024-01-31 09:00:00",
+                        "name": "at",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Category details",
+                        "description": "Price history",
                         "schema": {
-                            "$ref": "#/definitions/models.Category"
+                            "$ref": "#/definitions/models.BranchPriceHistoryResponse"
                         }
                     },
                     "400": {
@@ -570,12 +616,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Category not found",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -583,9 +623,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update an existing category.",
+            }
+        },
+        "/v1/branch_price/{id}": {
+            "get": {
+                "description": "Get branch price details by its ID.",
                 "consumes": [
                     "application/json"
                 ],
@@ -593,9 +635,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "category"
+                    "branch_price"
                 ],
-                "summary": "Update a category",
+                "summary": "Get a branch price by ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -611,6 +653,54 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
+                    {
+                        "type": "string",
+                        "description": "Branch price ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Branch price details",
+                        "schema": {
+                            "$ref": "#/definitions/models.BranchPrice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update the price or effective_from of a branch price entry.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "branch_price"
+                ],
+                "summary": "Update a branch price",
+                "parameters": [
                     {
                         "type": "string",
                         "description": "Authentication token",
@@ -620,26 +710,33 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Category ID",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch price ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated category information",
-                        "name": "category",
+                        "description": "Updated branch price information",
+                        "name": "branch_price",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateCategory"
+                            "$ref": "#/definitions/models.UpdateBranchPrice"
                         }
                     }
                 ],
                 "responses": {
                     "202": {
-                        "description": "Updated category",
+                        "description": "Updated branch price",
                         "schema": {
-                            "$ref": "#/definitions/models.Category"
+                            "$ref": "#/definitions/models.BranchPrice"
                         }
                     },
                     "400": {
@@ -654,12 +751,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Category not found",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -669,7 +760,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete an existing category.",
+                "description": "Delete a branch price entry.",
                 "consumes": [
                     "application/json"
                 ],
@@ -677,9 +768,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "category"
+                    "branch_price"
                 ],
-                "summary": "Delete a category",
+                "summary": "Delete a branch price",
                 "parameters": [
                     {
                         "type": "string",
@@ -697,7 +788,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Category ID",
+                        "description": "Branch price ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -728,9 +819,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/employee": {
+        "/v1/brand": {
             "get": {
-                "description": "Get a list of employees with optional filtering.",
+                "description": "Get a list of brands with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, name, created_at, updated_at.",
                 "consumes": [
                     "application/json"
                 ],
@@ -738,9 +829,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "employee"
+                    "brand"
                 ],
-                "summary": "Get a list of employees",
+                "summary": "Get a list of brands",
                 "parameters": [
                     {
                         "type": "string",
@@ -773,16 +864,31 @@ const docTemplate = `{
                         "description": "Search term",
                         "name": "search",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)",
+                        "name": "created_at[between]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of employees",
+                        "description": "List of brands",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Employee"
-                            }
+                            "$ref": "#/definitions/models.GetListBrandResponse"
                         }
                     },
                     "400": {
@@ -806,7 +912,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new employee in the market system.",
+                "description": "Create a new brand in the market system.",
                 "consumes": [
                     "application/json"
                 ],
@@ -814,9 +920,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "employee"
+                    "brand"
                 ],
-                "summary": "Create a new employee",
+                "summary": "Create a new brand",
                 "parameters": [
                     {
                         "type": "string",
@@ -833,20 +939,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Employee information",
-                        "name": "employee",
+                        "description": "Brand information",
+                        "name": "brand",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateEmployee"
+                            "$ref": "#/definitions/models.CreateBrand"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created employee",
+                        "description": "Created brand",
                         "schema": {
-                            "$ref": "#/definitions/models.Employee"
+                            "$ref": "#/definitions/models.Brand"
                         }
                     },
                     "400": {
@@ -870,9 +976,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/employee/{id}": {
+        "/v1/brand/{id}": {
             "get": {
-                "description": "Get employee details by its ID.",
+                "description": "Get brand details by its ID.",
                 "consumes": [
                     "application/json"
                 ],
@@ -880,10 +986,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "employee"
+                    "brand"
                 ],
-                "summary": "Get an employee by ID",
+                "summary": "Get a brand by ID",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Brand ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "description": "Authentication token",
@@ -897,20 +1010,13 @@ const docTemplate = `{
                         "name": "Password",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Employee ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Employee details",
+                        "description": "Brand details",
                         "schema": {
-                            "$ref": "#/definitions/models.Employee"
+                            "$ref": "#/definitions/models.Brand"
                         }
                     },
                     "400": {
@@ -925,12 +1031,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Employee not found",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -940,7 +1040,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update an existing employee.",
+                "description": "Update an existing brand.",
                 "consumes": [
                     "application/json"
                 ],
@@ -948,9 +1048,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "employee"
+                    "brand"
                 ],
-                "summary": "Update an employee",
+                "summary": "Update a brand",
                 "parameters": [
                     {
                         "type": "string",
@@ -968,26 +1068,26 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Employee ID",
+                        "description": "Brand ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated employee information",
-                        "name": "employee",
+                        "description": "Updated brand information",
+                        "name": "brand",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateEmployee"
+                            "$ref": "#/definitions/models.UpdateBrand"
                         }
                     }
                 ],
                 "responses": {
                     "202": {
-                        "description": "Updated employee",
+                        "description": "Updated brand",
                         "schema": {
-                            "$ref": "#/definitions/models.Employee"
+                            "$ref": "#/definitions/models.Brand"
                         }
                     },
                     "400": {
@@ -1002,12 +1102,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Employee not found",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1017,7 +1111,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete an existing employee.",
+                "description": "Delete a brand. A brand that products, categories or promotions refer to is only deleted with reassign_to, the brand they are moved to.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1025,9 +1119,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "employee"
+                    "brand"
                 ],
-                "summary": "Delete an employee",
+                "summary": "Delete a brand",
                 "parameters": [
                     {
                         "type": "string",
@@ -1045,10 +1139,16 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Employee ID",
+                        "description": "Brand ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Brand ID to move the products, categories and promotions of the brand to",
+                        "name": "reassign_to",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -1076,9 +1176,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/income": {
+        "/v1/category": {
             "get": {
-                "description": "Get a list of incomes with optional filtering.",
+                "description": "Get a list of category with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null, under; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. parent_id[under] selects all subcategories of a category. Filter and sort fields: id, title, parent_id, brand_id, created_at, updated_at.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1086,9 +1186,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "income"
+                    "category"
                 ],
-                "summary": "Get a list of incomes",
+                "summary": "Get a list of category",
                 "parameters": [
                     {
                         "type": "string",
@@ -1121,15 +1221,39 @@ const docTemplate = `{
                         "description": "Search term",
                         "name": "search",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)",
+                        "name": "created_at[between]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category ID whose subcategories at any depth to return",
+                        "name": "parent_id[under]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of incomes",
+                        "description": "List of category",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Income"
+                                "$ref": "#/definitions/models.Category"
                             }
                         }
                     },
@@ -1154,7 +1278,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new income in the market system.",
+                "description": "Create a new category in the market system. A subcategory is of the brand of its parent unless brand_id is given.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1162,9 +1286,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "income"
+                    "category"
                 ],
-                "summary": "Create a new income",
+                "summary": "Create a new category",
                 "parameters": [
                     {
                         "type": "string",
@@ -1181,20 +1305,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Income information",
-                        "name": "income",
+                        "description": "Category information",
+                        "name": "category",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateIncome"
+                            "$ref": "#/definitions/models.CreateCategory"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created income",
+                        "description": "Created category",
                         "schema": {
-                            "$ref": "#/definitions/models.Income"
+                            "$ref": "#/definitions/models.Category"
                         }
                     },
                     "400": {
@@ -1218,9 +1342,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/income/{id}": {
+        "/v1/category/tree": {
             "get": {
-                "description": "Get income details by its ID.",
+                "description": "Get the categories nested under their parents, from the root categories or from root_id, with the products of each category (product_count) and of it with its subcategories (total_product_count).",
                 "consumes": [
                     "application/json"
                 ],
@@ -1228,9 +1352,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "income"
+                    "category"
                 ],
-                "summary": "Get an income by ID",
+                "summary": "Get the category tree",
                 "parameters": [
                     {
                         "type": "string",
@@ -1248,17 +1372,16 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Income ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Category ID to return the tree under",
+                        "name": "root_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Income details",
+                        "description": "Category tree",
                         "schema": {
-                            "$ref": "#/definitions/models.Income"
+                            "$ref": "#/definitions/models.GetCategoryTreeResponse"
                         }
                     },
                     "400": {
@@ -1273,12 +1396,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Income not found",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1286,9 +1403,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update an existing income.",
+            }
+        },
+        "/v1/category/{id}": {
+            "get": {
+                "description": "Get category details by its ID.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1296,10 +1415,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "income"
+                    "category"
                 ],
-                "summary": "Update an income",
+                "summary": "Get a category by ID",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "description": "Authentication token",
@@ -1313,29 +1439,97 @@ const docTemplate = `{
                         "name": "Password",
                         "in": "header",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Category details",
+                        "schema": {
+                            "$ref": "#/definitions/models.Category"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Category not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
                     },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing category.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "category"
+                ],
+                "summary": "Update a category",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "Income ID",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated income information",
-                        "name": "income",
+                        "description": "Updated category information",
+                        "name": "category",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateIncome"
+                            "$ref": "#/definitions/models.UpdateCategory"
                         }
                     }
                 ],
                 "responses": {
                     "202": {
-                        "description": "Updated income",
+                        "description": "Updated category",
                         "schema": {
-                            "$ref": "#/definitions/models.Income"
+                            "$ref": "#/definitions/models.Category"
                         }
                     },
                     "400": {
@@ -1351,7 +1545,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Income not found",
+                        "description": "Category not found",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -1365,7 +1559,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete an existing income.",
+                "description": "Delete an existing category.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1373,9 +1567,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "income"
+                    "category"
                 ],
-                "summary": "Delete an income",
+                "summary": "Delete a category",
                 "parameters": [
                     {
                         "type": "string",
@@ -1393,7 +1587,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Income ID",
+                        "description": "Category ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -1424,9 +1618,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/income_product": {
+        "/v1/category/{id}/breadcrumbs": {
             "get": {
-                "description": "Get a list of income products with optional filtering.",
+                "description": "Get the path of categories from the root down to the category, which is the last.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1434,9 +1628,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "income_product"
+                    "category"
                 ],
-                "summary": "Get a list of income products",
+                "summary": "Get the breadcrumbs of a category",
                 "parameters": [
                     {
                         "type": "string",
@@ -1452,33 +1646,19 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
-                    {
-                        "type": "integer",
-                        "description": "Number of items to return (default 10)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of items to skip (default 0)",
-                        "name": "offset",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "Search term",
-                        "name": "search",
-                        "in": "query"
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of income products",
+                        "description": "Categories from the root",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.IncomeProduct"
-                            }
+                            "$ref": "#/definitions/models.GetCategoryBreadcrumbsResponse"
                         }
                     },
                     "400": {
@@ -1500,9 +1680,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/v1/category/{id}/move": {
             "post": {
-                "description": "Create a new income product in the market system.",
+                "description": "Put a category with its subcategories under another category, or at the root when parent_id is empty. A category can not be moved under itself or its subcategories.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1510,9 +1692,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "income_product"
+                    "category"
                 ],
-                "summary": "Create a new income product",
+                "summary": "Move a category",
                 "parameters": [
                     {
                         "type": "string",
@@ -1529,20 +1711,27 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Income product information",
-                        "name": "income_product",
+                        "type": "string",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New parent category",
+                        "name": "move",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateIncomeProduct"
+                            "$ref": "#/definitions/models.MoveCategory"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created income product",
+                    "202": {
+                        "description": "Moved category",
                         "schema": {
-                            "$ref": "#/definitions/models.IncomeProduct"
+                            "$ref": "#/definitions/models.Category"
                         }
                     },
                     "400": {
@@ -1566,9 +1755,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/income_product/{id}": {
+        "/v1/customer": {
             "get": {
-                "description": "Get income product details by its ID.",
+                "description": "Get a list of customers. Look a customer up by phone[eq] or card_barcode[eq]. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, phone, first_name, last_name, card_barcode, tier_id, points_balance, total_spent, created_at, updated_at.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1576,9 +1765,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "income_product"
+                    "customer"
                 ],
-                "summary": "Get an income product by ID",
+                "summary": "Get a list of customers",
                 "parameters": [
                     {
                         "type": "string",
@@ -1594,19 +1783,60 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Income Product ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Search by phone or name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exact phone",
+                        "name": "phone[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Loyalty card barcode",
+                        "name": "card_barcode[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Loyalty tier ID",
+                        "name": "tier_id[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Income product details",
+                        "description": "List of customers",
                         "schema": {
-                            "$ref": "#/definitions/models.IncomeProduct"
+                            "$ref": "#/definitions/models.GetListCustomerResponse"
                         }
                     },
                     "400": {
@@ -1621,12 +1851,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Income product not found",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1635,8 +1859,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "Update an existing income product.",
+            "post": {
+                "description": "Create a loyalty customer. The phone is the customer's key.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1644,9 +1868,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "income_product"
+                    "customer"
                 ],
-                "summary": "Update an income product",
+                "summary": "Create a customer",
                 "parameters": [
                     {
                         "type": "string",
@@ -1663,27 +1887,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Income Product ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated income product information",
-                        "name": "income_product",
+                        "description": "Customer information",
+                        "name": "customer",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateIncomeProduct"
+                            "$ref": "#/definitions/models.CreateCustomer"
                         }
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "Updated income product",
+                    "201": {
+                        "description": "Created customer",
                         "schema": {
-                            "$ref": "#/definitions/models.IncomeProduct"
+                            "$ref": "#/definitions/models.Customer"
                         }
                     },
                     "400": {
@@ -1698,12 +1915,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Income product not found",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1711,9 +1922,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete an existing income product.",
+            }
+        },
+        "/v1/customer/report": {
+            "get": {
+                "description": "Per branch: finished sales, sales with a customer, distinct customers and the share of them who came back.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1721,9 +1934,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "income_product"
+                    "customer"
                 ],
-                "summary": "Delete an income product",
+                "summary": "Repeat customer report",
                 "parameters": [
                     {
                         "type": "string",
@@ -1741,15 +1954,29 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Income Product ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "From date, e.g. 2024-01-01",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To date (exclusive), e.g. 2024-02-01",
+                        "name": "to_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch ID",
+                        "name": "branch_id",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "Repeat customer report",
+                        "schema": {
+                            "$ref": "#/definitions/models.RepeatCustomerReportResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1772,9 +1999,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/payment": {
+        "/v1/customer/{id}": {
             "get": {
-                "description": "Get a list of payments with optional filtering.",
+                "description": "Get customer details with tier and points balance.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1782,9 +2009,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "payment"
+                    "customer"
                 ],
-                "summary": "Get a list of payments",
+                "summary": "Get a customer by ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -1800,33 +2027,19 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
-                    {
-                        "type": "integer",
-                        "description": "Number of items to return (default 10)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of items to skip (default 0)",
-                        "name": "offset",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "Search term",
-                        "name": "search",
-                        "in": "query"
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of payments",
+                        "description": "Customer details",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Payment"
-                            }
+                            "$ref": "#/definitions/models.Customer"
                         }
                     },
                     "400": {
@@ -1849,8 +2062,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "description": "Create a new payment in the market system.",
+            "put": {
+                "description": "Update customer contact details and loyalty card.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1858,9 +2071,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "payment"
+                    "customer"
                 ],
-                "summary": "Create a new payment",
+                "summary": "Update a customer",
                 "parameters": [
                     {
                         "type": "string",
@@ -1877,20 +2090,27 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Payment information",
-                        "name": "payment",
+                        "type": "string",
+                        "description": "Customer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated customer information",
+                        "name": "customer",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreatePayment"
+                            "$ref": "#/definitions/models.UpdateCustomer"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created payment",
+                    "202": {
+                        "description": "Updated customer",
                         "schema": {
-                            "$ref": "#/definitions/models.Payment"
+                            "$ref": "#/definitions/models.Customer"
                         }
                     },
                     "400": {
@@ -1912,11 +2132,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/payment/{id}": {
-            "get": {
-                "description": "Get payment details by its ID.",
+            },
+            "delete": {
+                "description": "Delete a customer and their points history.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1924,9 +2142,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "payment"
+                    "customer"
                 ],
-                "summary": "Get a payment by ID",
+                "summary": "Delete a customer",
                 "parameters": [
                     {
                         "type": "string",
@@ -1944,18 +2162,15 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Payment ID",
+                        "description": "Customer ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Payment details",
-                        "schema": {
-                            "$ref": "#/definitions/models.Payment"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -1969,12 +2184,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Payment not found",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -1982,9 +2191,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update an existing payment.",
+            }
+        },
+        "/v1/customer/{id}/adjust": {
+            "post": {
+                "description": "Add points by hand, or remove them with a negative value. Not allowed for cashiers.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1992,9 +2203,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "payment"
+                    "customer"
                 ],
-                "summary": "Update a payment",
+                "summary": "Adjust customer points",
                 "parameters": [
                     {
                         "type": "string",
@@ -2012,26 +2223,26 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Payment ID",
+                        "description": "Customer ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated payment information",
-                        "name": "payment",
+                        "description": "Points and comment",
+                        "name": "adjust",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdatePayment"
+                            "$ref": "#/definitions/models.AdjustLoyaltyPoints"
                         }
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "Updated payment",
+                    "200": {
+                        "description": "Customer with the new balance",
                         "schema": {
-                            "$ref": "#/definitions/models.Payment"
+                            "$ref": "#/definitions/models.Customer"
                         }
                     },
                     "400": {
@@ -2046,8 +2257,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Payment not found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -2059,9 +2270,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete an existing payment.",
+            }
+        },
+        "/v1/customer/{id}/history": {
+            "get": {
+                "description": "Points earned, redeemed and adjusted by hand, newest first.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2069,9 +2282,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "payment"
+                    "customer"
                 ],
-                "summary": "Delete a payment",
+                "summary": "Customer points history",
                 "parameters": [
                     {
                         "type": "string",
@@ -2089,15 +2302,30 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Payment ID",
+                        "description": "Customer ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "Points history",
+                        "schema": {
+                            "$ref": "#/definitions/models.LoyaltyHistoryResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -2120,9 +2348,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/product": {
+        "/v1/export": {
             "get": {
-                "description": "Get a list of products with optional filtering.",
+                "description": "Get the background exports of the user, latest first. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, status, format, lang, path, file_name, rows, created_at, finished_at.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2130,9 +2358,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "product"
+                    "export"
                 ],
-                "summary": "Get a list of products",
+                "summary": "Get a list of export jobs",
                 "parameters": [
                     {
                         "type": "string",
@@ -2162,19 +2390,28 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Search term",
-                        "name": "search",
+                        "description": "Status (pending, running, done, failed)",
+                        "name": "status[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of products",
+                        "description": "List of export jobs",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Product"
-                            }
+                            "$ref": "#/definitions/models.GetListExportJobResponse"
                         }
                     },
                     "400": {
@@ -2196,9 +2433,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create a new product in the market system.",
+            }
+        },
+        "/v1/export/{id}": {
+            "get": {
+                "description": "Get a background export. download_url is set once its file is ready.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2206,9 +2445,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "product"
+                    "export"
                 ],
-                "summary": "Create a new product",
+                "summary": "Get export job by ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -2225,20 +2464,18 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Product information",
-                        "name": "product",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.CreateProduct"
-                        }
+                        "type": "string",
+                        "description": "Export job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created product",
+                    "200": {
+                        "description": "Export job",
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
+                            "$ref": "#/definitions/models.ExportJob"
                         }
                     },
                     "400": {
@@ -2253,6 +2490,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2260,11 +2503,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/product/{id}": {
-            "get": {
-                "description": "Get product details by its ID.",
+            },
+            "delete": {
+                "description": "Delete a background export and its file.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2272,9 +2513,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "product"
+                    "export"
                 ],
-                "summary": "Get a product by ID",
+                "summary": "Delete export job",
                 "parameters": [
                     {
                         "type": "string",
@@ -2292,18 +2533,15 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Product ID",
+                        "description": "Export job ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Product details",
-                        "schema": {
-                            "$ref": "#/definitions/models.Product"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -2317,8 +2555,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Product not found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -2330,19 +2568,18 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update an existing product.",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/v1/export/{id}/download": {
+            "get": {
+                "description": "Download the file of a finished background export.",
                 "produces": [
-                    "application/json"
+                    "application/octet-stream"
                 ],
                 "tags": [
-                    "product"
+                    "export"
                 ],
-                "summary": "Update a product",
+                "summary": "Download an export",
                 "parameters": [
                     {
                         "type": "string",
@@ -2360,26 +2597,17 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Product ID",
+                        "description": "Export job ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Updated product information",
-                        "name": "product",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateProduct"
-                        }
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "Updated product",
+                    "200": {
+                        "description": "Export file",
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
+                            "type": "file"
                         }
                     },
                     "400": {
@@ -2394,8 +2622,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Product not found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -2407,9 +2635,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete an existing product.",
+            }
+        },
+        "/v1/gift_card": {
+            "get": {
+                "description": "Get gift cards and store credit, newest first. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, code, type, status, initial_amount, balance, customer_id, sale_id, expires_at, created_at, updated_at.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2417,9 +2647,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "product"
+                    "gift_card"
                 ],
-                "summary": "Delete a product",
+                "summary": "Get a list of gift cards",
                 "parameters": [
                     {
                         "type": "string",
@@ -2435,17 +2665,61 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Product ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Card code",
+                        "name": "code[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "gift_card or store_credit",
+                        "name": "type[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, active or canceled",
+                        "name": "status[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Customer ID",
+                        "name": "customer_id[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "List of gift cards",
+                        "schema": {
+                            "$ref": "#/definitions/models.GetListGiftCardResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -2468,9 +2742,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/remainder": {
+        "/v1/gift_card/balance/{code}": {
             "get": {
-                "description": "Get a list of remainder with optional filtering.",
+                "description": "Look up the balance, status and expiry of a card by its code.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2478,9 +2752,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "remainder"
+                    "gift_card"
                 ],
-                "summary": "Get a list of remainder",
+                "summary": "Gift card balance",
                 "parameters": [
                     {
                         "type": "string",
@@ -2496,33 +2770,19 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
-                    {
-                        "type": "integer",
-                        "description": "Number of items to return (default 10)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of items to skip (default 0)",
-                        "name": "offset",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "Search term",
-                        "name": "search",
-                        "in": "query"
+                        "description": "Gift card code",
+                        "name": "code",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of remainder",
+                        "description": "Gift card",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Remainder"
-                            }
+                            "$ref": "#/definitions/models.GiftCard"
                         }
                     },
                     "400": {
@@ -2544,9 +2804,68 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/v1/gift_card/report": {
+            "get": {
+                "description": "What the store owes on gift cards and store credit as of a moment: issued, redeemed, outstanding and expired balances per type.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "gift_card"
+                ],
+                "summary": "Gift card liability report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "As of, e.g. 2024-01-31 23:59:59 (default now)",
+                        "name": "at",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Liability report",
+                        "schema": {
+                            "$ref": "#/definitions/models.GiftCardLiabilityResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/gift_card/sell": {
             "post": {
-                "description": "Create a new remainder in the market system.",
+                "description": "Add a gift card line to an open sale. The card gets a printable EAN-13 code and becomes usable when the sale is finished.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2554,9 +2873,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "remainder"
+                    "gift_card"
                 ],
-                "summary": "Create a new remainder",
+                "summary": "Sell a gift card",
                 "parameters": [
                     {
                         "type": "string",
@@ -2573,20 +2892,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Remainder information",
-                        "name": "remainder",
+                        "description": "Sale, amount and expiry",
+                        "name": "gift_card",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateRemainder"
+                            "$ref": "#/definitions/models.SellGiftCard"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created remainder",
+                        "description": "Pending gift card",
                         "schema": {
-                            "$ref": "#/definitions/models.Remainder"
+                            "$ref": "#/definitions/models.GiftCard"
                         }
                     },
                     "400": {
@@ -2610,9 +2929,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/remainder/{id}": {
-            "get": {
-                "description": "Get remainder details by its ID.",
+        "/v1/gift_card/store_credit": {
+            "post": {
+                "description": "Issue store credit, e.g. to refund a return to credit instead of cash. Not allowed for cashiers.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2620,9 +2939,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "remainder"
+                    "gift_card"
                 ],
-                "summary": "Get a remainder by ID",
+                "summary": "Issue store credit",
                 "parameters": [
                     {
                         "type": "string",
@@ -2639,18 +2958,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Remainder ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Store credit information",
+                        "name": "store_credit",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateStoreCredit"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Remainder details",
+                    "201": {
+                        "description": "Store credit",
                         "schema": {
-                            "$ref": "#/definitions/models.Remainder"
+                            "$ref": "#/definitions/models.GiftCard"
                         }
                     },
                     "400": {
@@ -2665,8 +2986,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Remainder not found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -2678,9 +2999,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update an existing remainder.",
+            }
+        },
+        "/v1/gift_card/{id}": {
+            "get": {
+                "description": "Get gift card or store credit details.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2688,9 +3011,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "remainder"
+                    "gift_card"
                 ],
-                "summary": "Update a remainder",
+                "summary": "Get a gift card by ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -2708,26 +3031,17 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Remainder ID",
+                        "description": "Gift card ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Updated remainder information",
-                        "name": "remainder",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateRemainder"
-                        }
                     }
                 ],
                 "responses": {
-                    "202": {
-                        "description": "Updated remainder",
+                    "200": {
+                        "description": "Gift card details",
                         "schema": {
-                            "$ref": "#/definitions/models.Remainder"
+                            "$ref": "#/definitions/models.GiftCard"
                         }
                     },
                     "400": {
@@ -2742,12 +3056,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Remainder not found",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2755,9 +3063,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete an existing remainder.",
+            }
+        },
+        "/v1/gift_card/{id}/history": {
+            "get": {
+                "description": "Issue and redemption history of a card.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2765,9 +3075,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "remainder"
+                    "gift_card"
                 ],
-                "summary": "Delete a remainder",
+                "summary": "Gift card history",
                 "parameters": [
                     {
                         "type": "string",
@@ -2785,15 +3095,18 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Remainder ID",
+                        "description": "Gift card ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "200": {
+                        "description": "Card history",
+                        "schema": {
+                            "$ref": "#/definitions/models.GiftCardHistoryResponse"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -2807,12 +3120,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Remainder not found",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -2822,9 +3129,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/sale": {
+        "/v1/income": {
             "get": {
-                "description": "Get a list of sales with optional filtering.",
+                "description": "Get a list of incomes with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, branch_id, supplier_id, date_time, status, purchase_order_id, created_at, updated_at.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2832,9 +3139,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sale"
+                    "income"
                 ],
-                "summary": "Get a list of sales",
+                "summary": "Get a list of incomes",
                 "parameters": [
                     {
                         "type": "string",
@@ -2867,15 +3174,33 @@ const docTemplate = `{
                         "description": "Search term",
                         "name": "search",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)",
+                        "name": "created_at[between]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of sales",
+                        "description": "List of incomes",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Sale"
+                                "$ref": "#/definitions/models.Income"
                             }
                         }
                     },
@@ -2900,7 +3225,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new sale in the market system.",
+                "description": "Create a new income in the market system. It can not be created finished; posting it with /v1/doincome finishes it.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2908,9 +3233,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sale"
+                    "income"
                 ],
-                "summary": "Create a new sale",
+                "summary": "Create a new income",
                 "parameters": [
                     {
                         "type": "string",
@@ -2927,20 +3252,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Sale information",
-                        "name": "sale",
+                        "description": "Income information",
+                        "name": "income",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateSale"
+                            "$ref": "#/definitions/models.CreateIncome"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created sale",
+                        "description": "Created income",
                         "schema": {
-                            "$ref": "#/definitions/models.Sale"
+                            "$ref": "#/definitions/models.Income"
                         }
                     },
                     "400": {
@@ -2964,9 +3289,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/sale/{id}": {
+        "/v1/income/{id}": {
             "get": {
-                "description": "Get sale details by its ID.",
+                "description": "Get income details by its ID.",
                 "consumes": [
                     "application/json"
                 ],
@@ -2974,9 +3299,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sale"
+                    "income"
                 ],
-                "summary": "Get a sale by ID",
+                "summary": "Get an income by ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -2994,7 +3319,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Sale ID",
+                        "description": "Income ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3002,9 +3327,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Sale details",
+                        "description": "Income details",
                         "schema": {
-                            "$ref": "#/definitions/models.Sale"
+                            "$ref": "#/definitions/models.Income"
                         }
                     },
                     "400": {
@@ -3020,7 +3345,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Sale not found",
+                        "description": "Income not found",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -3034,7 +3359,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update an existing sale.",
+                "description": "Update an income that is not finished yet; a finished income can not be changed and status finished is set only by posting it with /v1/doincome.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3042,9 +3367,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sale"
+                    "income"
                 ],
-                "summary": "Update a sale",
+                "summary": "Update an income",
                 "parameters": [
                     {
                         "type": "string",
@@ -3062,26 +3387,26 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Sale ID",
+                        "description": "Income ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated sale information",
-                        "name": "sale",
+                        "description": "Updated income information",
+                        "name": "income",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateSale"
+                            "$ref": "#/definitions/models.UpdateIncome"
                         }
                     }
                 ],
                 "responses": {
                     "202": {
-                        "description": "Updated sale",
+                        "description": "Updated income",
                         "schema": {
-                            "$ref": "#/definitions/models.Sale"
+                            "$ref": "#/definitions/models.Income"
                         }
                     },
                     "400": {
@@ -3097,7 +3422,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Sale not found",
+                        "description": "Income not found",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -3111,7 +3436,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete an existing sale.",
+                "description": "Delete an existing income.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3119,9 +3444,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sale"
+                    "income"
                 ],
-                "summary": "Delete a sale",
+                "summary": "Delete an income",
                 "parameters": [
                     {
                         "type": "string",
@@ -3139,7 +3464,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Sale ID",
+                        "description": "Income ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3170,9 +3495,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/sale_point": {
+        "/v1/income_product": {
             "get": {
-                "description": "Get a list of sale points with optional filtering.",
+                "description": "Get a list of income products with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, income_id, category_id, product_name, barcode, quantity, income_price, purchase_order_product_id, lot_number, expiry_date, created_at, updated_at.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3180,9 +3505,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sale_point"
+                    "income_product"
                 ],
-                "summary": "Get a list of sale points",
+                "summary": "Get a list of income products",
                 "parameters": [
                     {
                         "type": "string",
@@ -3215,15 +3540,33 @@ const docTemplate = `{
                         "description": "Search term",
                         "name": "search",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)",
+                        "name": "created_at[between]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of sale points",
+                        "description": "List of income products",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.SalePoint"
+                                "$ref": "#/definitions/models.IncomeProduct"
                             }
                         }
                     },
@@ -3248,7 +3591,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new sale point in the market system.",
+                "description": "Create a new income product in the market system.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3256,9 +3599,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sale_point"
+                    "income_product"
                 ],
-                "summary": "Create a new sale point",
+                "summary": "Create a new income product",
                 "parameters": [
                     {
                         "type": "string",
@@ -3275,20 +3618,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Sale point information",
-                        "name": "salePoint",
+                        "description": "Income product information",
+                        "name": "income_product",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateSalePoint"
+                            "$ref": "#/definitions/models.CreateIncomeProduct"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created sale point",
+                        "description": "Created income product",
                         "schema": {
-                            "$ref": "#/definitions/models.SalePoint"
+                            "$ref": "#/definitions/models.IncomeProduct"
                         }
                     },
                     "400": {
@@ -3312,9 +3655,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/sale_point/{id}": {
+        "/v1/income_product/{id}": {
             "get": {
-                "description": "Get sale point details by its ID.",
+                "description": "Get income product details by its ID.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3322,9 +3665,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sale_point"
+                    "income_product"
                 ],
-                "summary": "Get a sale point by ID",
+                "summary": "Get an income product by ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -3342,7 +3685,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Sale Point ID",
+                        "description": "Income Product ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3350,9 +3693,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Sale point details",
+                        "description": "Income product details",
                         "schema": {
-                            "$ref": "#/definitions/models.SalePoint"
+                            "$ref": "#/definitions/models.IncomeProduct"
                         }
                     },
                     "400": {
@@ -3368,7 +3711,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Sale point not found",
+                        "description": "Income product not found",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -3382,7 +3725,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update an existing sale point.",
+                "description": "Update an existing income product.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3390,9 +3733,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sale_point"
+                    "income_product"
                 ],
-                "summary": "Update a sale point",
+                "summary": "Update an income product",
                 "parameters": [
                     {
                         "type": "string",
@@ -3410,26 +3753,26 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Sale Point ID",
+                        "description": "Income Product ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated sale point information",
-                        "name": "salePoint",
+                        "description": "Updated income product information",
+                        "name": "income_product",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateSalePoint"
+                            "$ref": "#/definitions/models.UpdateIncomeProduct"
                         }
                     }
                 ],
                 "responses": {
                     "202": {
-                        "description": "Updated sale point",
+                        "description": "Updated income product",
                         "schema": {
-                            "$ref": "#/definitions/models.SalePoint"
+                            "$ref": "#/definitions/models.IncomeProduct"
                         }
                     },
                     "400": {
@@ -3445,7 +3788,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Sale point not found",
+                        "description": "Income product not found",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -3459,7 +3802,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete an existing sale point.",
+                "description": "Delete an existing income product.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3467,9 +3810,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sale_point"
+                    "income_product"
                 ],
-                "summary": "Delete a sale point",
+                "summary": "Delete an income product",
                 "parameters": [
                     {
                         "type": "string",
@@ -3487,7 +3830,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Sale Point ID",
+                        "description": "Income Product ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3518,9 +3861,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/sale_product": {
+        "/v1/inventory_count": {
             "get": {
-                "description": "Get a list of sale products with optional filtering.",
+                "description": "Get a list of inventory counts. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, branch_id, category_id, status, created_by, snapshot_at, posted_by, posted_at, created_at, updated_at.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3528,9 +3871,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sale_product"
+                    "inventory_count"
                 ],
-                "summary": "Get a list of sale products",
+                "summary": "Get a list of inventory counts",
                 "parameters": [
                     {
                         "type": "string",
@@ -3560,16 +3903,34 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Search term",
-                        "name": "search",
+                        "description": "Branch ID",
+                        "name": "branch_id[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "open, posted or canceled",
+                        "name": "status[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of sale products",
+                        "description": "List of inventory counts",
                         "schema": {
-                            "$ref": "#/definitions/models.GetListSaleProductResponse"
+                            "$ref": "#/definitions/models.GetListInventoryCountResponse"
                         }
                     },
                     "400": {
@@ -3593,7 +3954,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new sale product in the market system.",
+                "description": "Open a physical count of a branch, or of one category in it. Expected quantities are snapshotted from the branch remainder.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3601,9 +3962,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sale_product"
+                    "inventory_count"
                 ],
-                "summary": "Create a new sale product",
+                "summary": "Open an inventory count",
                 "parameters": [
                     {
                         "type": "string",
@@ -3620,20 +3981,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Sale Product information",
-                        "name": "saleProduct",
+                        "description": "Inventory count information",
+                        "name": "inventory_count",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateSaleProduct"
+                            "$ref": "#/definitions/models.CreateInventoryCount"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created sale product",
+                        "description": "Opened inventory count",
                         "schema": {
-                            "$ref": "#/definitions/models.SaleProduct"
+                            "$ref": "#/definitions/models.InventoryCount"
                         }
                     },
                     "400": {
@@ -3657,9 +4018,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/sale_product/{id}": {
+        "/v1/inventory_count/{id}": {
             "get": {
-                "description": "Get sale product details by its ID.",
+                "description": "Get a count with expected, counted and book quantity, variance and cost impact per barcode.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3667,9 +4028,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sale_product"
+                    "inventory_count"
                 ],
-                "summary": "Get a sale product by ID",
+                "summary": "Get an inventory count by ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -3687,7 +4048,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Sale Product ID",
+                        "description": "Inventory count ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -3695,9 +4056,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Sale Product details",
+                        "description": "Inventory count details",
                         "schema": {
-                            "$ref": "#/definitions/models.SaleProduct"
+                            "$ref": "#/definitions/models.InventoryCount"
                         }
                     },
                     "400": {
@@ -3712,8 +4073,66 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Sale Product not found",
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/inventory_count/{id}/cancel": {
+            "post": {
+                "description": "Cancel an open count without touching stock.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "inventory_count"
+                ],
+                "summary": "Cancel an inventory count",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Inventory count ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Canceled inventory count",
+                        "schema": {
+                            "$ref": "#/definitions/models.InventoryCount"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -3725,9 +4144,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update an existing sale product.",
+            }
+        },
+        "/v1/inventory_count/{id}/post": {
+            "post": {
+                "description": "Freeze the variance of an open count and adjust the branch stock by it. Barcodes nobody counted are taken as counted zero.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3735,9 +4156,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sale_product"
+                    "inventory_count"
                 ],
-                "summary": "Update a sale product",
+                "summary": "Post an inventory count",
                 "parameters": [
                     {
                         "type": "string",
@@ -3755,26 +4176,17 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Sale Product ID",
+                        "description": "Inventory count ID",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Updated sale product information",
-                        "name": "saleProduct",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateSaleProduct"
-                        }
                     }
                 ],
                 "responses": {
                     "202": {
-                        "description": "Updated sale product",
+                        "description": "Posted inventory count",
                         "schema": {
-                            "$ref": "#/definitions/models.SaleProduct"
+                            "$ref": "#/definitions/models.InventoryCount"
                         }
                     },
                     "400": {
@@ -3789,8 +4201,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Sale Product not found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -3802,9 +4214,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete an existing sale product.",
+            }
+        },
+        "/v1/inventory_count/{id}/scan": {
+            "post": {
+                "description": "Add counted quantities to an open count. Several counters may scan at the same time; their scans are summed per barcode. A negative quantity corrects an earlier scan.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3812,9 +4226,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "sale_product"
+                    "inventory_count"
                 ],
-                "summary": "Delete a sale product",
+                "summary": "Scan into an inventory count",
                 "parameters": [
                     {
                         "type": "string",
@@ -3832,15 +4246,27 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Sale Product ID",
+                        "description": "Inventory count ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Scanned quantities",
+                        "name": "scan",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ScanInventoryCount"
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": "No Content"
+                    "202": {
+                        "description": "Inventory count",
+                        "schema": {
+                            "$ref": "#/definitions/models.InventoryCount"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -3863,9 +4289,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/shift": {
+        "/v1/loyalty_earn_rate": {
             "get": {
-                "description": "Get a list of shifts with optional filtering.",
+                "description": "Get the default earn rate and the per category rates, the default rate first. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, category_id, category_name, is_default, percent, created_at, updated_at.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3873,9 +4299,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "shift"
+                    "loyalty"
                 ],
-                "summary": "Get a list of shifts",
+                "summary": "Get loyalty earn rates",
                 "parameters": [
                     {
                         "type": "string",
@@ -3905,19 +4331,22 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Search term",
-                        "name": "search",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of shifts",
+                        "description": "Earn rates",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Shift"
-                            }
+                            "$ref": "#/definitions/models.GetListLoyaltyEarnRateResponse"
                         }
                     },
                     "400": {
@@ -3940,8 +4369,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "post": {
-                "description": "Create a new shift in the market system.",
+            "put": {
+                "description": "Set the percent of the line total earned as points for a category. Without category_id the default rate is set.",
                 "consumes": [
                     "application/json"
                 ],
@@ -3949,9 +4378,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "shift"
+                    "loyalty"
                 ],
-                "summary": "Create a new shift",
+                "summary": "Set a loyalty earn rate",
                 "parameters": [
                     {
                         "type": "string",
@@ -3968,20 +4397,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Shift information",
-                        "name": "shift",
+                        "description": "Earn rate",
+                        "name": "earn_rate",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateShift"
+                            "$ref": "#/definitions/models.SetLoyaltyEarnRate"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created shift",
+                    "200": {
+                        "description": "Earn rate",
                         "schema": {
-                            "$ref": "#/definitions/models.Shift"
+                            "$ref": "#/definitions/models.LoyaltyEarnRate"
                         }
                     },
                     "400": {
@@ -4005,9 +4434,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/shift/{id}": {
-            "get": {
-                "description": "Get shift details by its ID.",
+        "/v1/loyalty_earn_rate/{id}": {
+            "delete": {
+                "description": "Delete an earn rate. The category falls back to the default rate.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4015,9 +4444,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "shift"
+                    "loyalty"
                 ],
-                "summary": "Get a shift by ID",
+                "summary": "Delete a loyalty earn rate",
                 "parameters": [
                     {
                         "type": "string",
@@ -4035,18 +4464,15 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Shift ID",
+                        "description": "Earn rate ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "Shift details",
-                        "schema": {
-                            "$ref": "#/definitions/models.Shift"
-                        }
+                    "204": {
+                        "description": "No Content"
                     },
                     "400": {
                         "description": "Bad Request",
@@ -4060,12 +4486,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Shift not found",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -4073,9 +4493,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update an existing shift.",
+            }
+        },
+        "/v1/loyalty_tier": {
+            "get": {
+                "description": "Get loyalty tiers ordered by threshold. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, name, min_spent, earn_multiplier, created_at, updated_at.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4083,9 +4505,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "shift"
+                    "loyalty"
                 ],
-                "summary": "Update a shift",
+                "summary": "Get a list of loyalty tiers",
                 "parameters": [
                     {
                         "type": "string",
@@ -4101,171 +4523,42 @@ const docTemplate = `{
                         "in": "header",
                         "required": true
                     },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
-                        "description": "Shift ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Updated shift information",
-                        "name": "shift",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.UpdateShift"
-                        }
-                    }
-                ],
-                "responses": {
-                    "202": {
-                        "description": "Updated shift",
-                        "schema": {
-                            "$ref": "#/definitions/models.Shift"
-                        }
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    },
-                    "404": {
-                        "description": "Shift not found",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    }
-                }
-            },
-            "delete": {
-                "description": "Delete an existing shift.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "shift"
-                ],
-                "summary": "Delete a shift",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Authentication token",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "User password",
-                        "name": "Password",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Shift ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
-                ],
-                "responses": {
-                    "204": {
-                        "description": "No Content"
-                    },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    },
-                    "401": {
-                        "description": "Unauthorized",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/v1/supplier": {
-            "get": {
-                "description": "Get a list of suppliers with optional filtering.",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "supplier"
-                ],
-                "summary": "Get a list of suppliers",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Authentication token",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
+                        "description": "Search by name",
+                        "name": "search",
+                        "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "User password",
-                        "name": "Password",
-                        "in": "header",
-                        "required": true
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of items to return (default 10)",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of items to skip (default 0)",
-                        "name": "offset",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "Search term",
-                        "name": "search",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of suppliers",
+                        "description": "List of tiers",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Supplier"
-                            }
+                            "$ref": "#/definitions/models.GetListLoyaltyTierResponse"
                         }
                     },
                     "400": {
@@ -4289,7 +4582,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new supplier in the market system.",
+                "description": "Create a tier. Customers reach it once their total spent is at least min_spent; earn_multiplier scales the points they earn.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4297,9 +4590,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "supplier"
+                    "loyalty"
                 ],
-                "summary": "Create a new supplier",
+                "summary": "Create a loyalty tier",
                 "parameters": [
                     {
                         "type": "string",
@@ -4316,20 +4609,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Supplier information",
-                        "name": "supplier",
+                        "description": "Tier information",
+                        "name": "tier",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateSupplier"
+                            "$ref": "#/definitions/models.CreateLoyaltyTier"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created supplier",
+                        "description": "Created tier",
                         "schema": {
-                            "$ref": "#/definitions/models.Supplier"
+                            "$ref": "#/definitions/models.LoyaltyTier"
                         }
                     },
                     "400": {
@@ -4353,9 +4646,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/supplier/{id}": {
+        "/v1/loyalty_tier/{id}": {
             "get": {
-                "description": "Get supplier details by its ID.",
+                "description": "Get loyalty tier details.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4363,9 +4656,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "supplier"
+                    "loyalty"
                 ],
-                "summary": "Get a supplier by ID",
+                "summary": "Get a loyalty tier by ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -4383,7 +4676,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Supplier ID",
+                        "description": "Tier ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -4391,9 +4684,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Supplier details",
+                        "description": "Tier details",
                         "schema": {
-                            "$ref": "#/definitions/models.Supplier"
+                            "$ref": "#/definitions/models.LoyaltyTier"
                         }
                     },
                     "400": {
@@ -4408,12 +4701,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Supplier not found",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -4423,7 +4710,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update an existing supplier.",
+                "description": "Update a tier. Customers are moved to the tiers matching the new thresholds.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4431,9 +4718,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "supplier"
+                    "loyalty"
                 ],
-                "summary": "Update a supplier",
+                "summary": "Update a loyalty tier",
                 "parameters": [
                     {
                         "type": "string",
@@ -4451,26 +4738,26 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Supplier ID",
+                        "description": "Tier ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated supplier information",
-                        "name": "supplier",
+                        "description": "Updated tier information",
+                        "name": "tier",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateSupplier"
+                            "$ref": "#/definitions/models.UpdateLoyaltyTier"
                         }
                     }
                 ],
                 "responses": {
                     "202": {
-                        "description": "Updated supplier",
+                        "description": "Updated tier",
                         "schema": {
-                            "$ref": "#/definitions/models.Supplier"
+                            "$ref": "#/definitions/models.LoyaltyTier"
                         }
                     },
                     "400": {
@@ -4485,12 +4772,6 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Supplier not found",
-                        "schema": {
-                            "$ref": "#/definitions/handler.ErrorResponse"
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -4500,7 +4781,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete an existing supplier.",
+                "description": "Delete a tier. Its customers fall back to the next lower tier.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4508,9 +4789,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "supplier"
+                    "loyalty"
                 ],
-                "summary": "Delete a supplier",
+                "summary": "Delete a loyalty tier",
                 "parameters": [
                     {
                         "type": "string",
@@ -4528,7 +4809,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Supplier ID",
+                        "description": "Tier ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -4559,9 +4840,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/transaction": {
+        "/v1/payment": {
             "get": {
-                "description": "Get a list of transactions with optional filtering.",
+                "description": "Get a list of payments with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, sale_id, cash, uzcard, payme, click, humo, apelsin, points, gift_card, gift_card_code, total_amount, created_at, updated_at.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4569,9 +4850,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transaction"
+                    "payment"
                 ],
-                "summary": "Get a list of transactions",
+                "summary": "Get a list of payments",
                 "parameters": [
                     {
                         "type": "string",
@@ -4604,15 +4885,33 @@ const docTemplate = `{
                         "description": "Search term",
                         "name": "search",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)",
+                        "name": "created_at[between]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of transactions",
+                        "description": "List of payments",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.Transaction"
+                                "$ref": "#/definitions/models.Payment"
                             }
                         }
                     },
@@ -4637,7 +4936,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new transaction in the market system.",
+                "description": "Create a new payment in the market system.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4645,9 +4944,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transaction"
+                    "payment"
                 ],
-                "summary": "Create a new transaction",
+                "summary": "Create a new payment",
                 "parameters": [
                     {
                         "type": "string",
@@ -4664,20 +4963,20 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Transaction information",
-                        "name": "transaction",
+                        "description": "Payment information",
+                        "name": "payment",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateTransaction"
+                            "$ref": "#/definitions/models.CreatePayment"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created transaction",
+                        "description": "Created payment",
                         "schema": {
-                            "$ref": "#/definitions/models.Transaction"
+                            "$ref": "#/definitions/models.Payment"
                         }
                     },
                     "400": {
@@ -4701,9 +5000,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/transaction/{id}": {
+        "/v1/payment/{id}": {
             "get": {
-                "description": "Get transaction details by its ID.",
+                "description": "Get payment details by its ID.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4711,9 +5010,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transaction"
+                    "payment"
                 ],
-                "summary": "Get a transaction by ID",
+                "summary": "Get a payment by ID",
                 "parameters": [
                     {
                         "type": "string",
@@ -4731,7 +5030,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Transaction ID",
+                        "description": "Payment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -4739,9 +5038,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "Transaction details",
+                        "description": "Payment details",
                         "schema": {
-                            "$ref": "#/definitions/models.Transaction"
+                            "$ref": "#/definitions/models.Payment"
                         }
                     },
                     "400": {
@@ -4757,7 +5056,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Transaction not found",
+                        "description": "Payment not found",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -4771,7 +5070,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update an existing transaction.",
+                "description": "Update an existing payment.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4779,9 +5078,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transaction"
+                    "payment"
                 ],
-                "summary": "Update a transaction",
+                "summary": "Update a payment",
                 "parameters": [
                     {
                         "type": "string",
@@ -4799,26 +5098,26 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Transaction ID",
+                        "description": "Payment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated transaction information",
-                        "name": "transaction",
+                        "description": "Updated payment information",
+                        "name": "payment",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateTransaction"
+                            "$ref": "#/definitions/models.UpdatePayment"
                         }
                     }
                 ],
                 "responses": {
                     "202": {
-                        "description": "Updated transaction",
+                        "description": "Updated payment",
                         "schema": {
-                            "$ref": "#/definitions/models.Transaction"
+                            "$ref": "#/definitions/models.Payment"
                         }
                     },
                     "400": {
@@ -4834,7 +5133,7 @@ const docTemplate = `{
                         }
                     },
                     "404": {
-                        "description": "Transaction not found",
+                        "description": "Payment not found",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -4848,7 +5147,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete an existing transaction.",
+                "description": "Delete an existing payment.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4856,9 +5155,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "transaction"
+                    "payment"
                 ],
-                "summary": "Delete a transaction",
+                "summary": "Delete a payment",
                 "parameters": [
                     {
                         "type": "string",
@@ -4876,7 +5175,7 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Transaction ID",
+                        "description": "Payment ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -4907,9 +5206,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/user": {
+        "/v1/price_change": {
             "get": {
-                "description": "Get a list of users with optional filtering.",
+                "description": "Get a list of price change documents. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, branch_id, effective_from, status, approved_by, approved_at, created_at, updated_at.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4917,10 +5216,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "price_change"
                 ],
-                "summary": "Get a list of users",
+                "summary": "Get a list of price changes",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
                     {
                         "type": "integer",
                         "description": "Number of items to return (default 10)",
@@ -4935,19 +5248,34 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Search term",
-                        "name": "search",
+                        "description": "Branch ID",
+                        "name": "branch_id[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "draft, approved or canceled",
+                        "name": "status[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
                         "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "List of users",
+                        "description": "List of price changes",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.User"
-                            }
+                            "$ref": "#/definitions/models.GetListPriceChangeResponse"
                         }
                     },
                     "400": {
@@ -4956,6 +5284,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -4965,7 +5299,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new user in the market system.",
+                "description": "Create a draft price change document for a branch. Prices apply from effective_from once approved.",
                 "consumes": [
                     "application/json"
                 ],
@@ -4973,25 +5307,39 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "price_change"
                 ],
-                "summary": "Create a new user",
+                "summary": "Create a price change",
                 "parameters": [
                     {
-                        "description": "User information",
-                        "name": "user",
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Price change information",
+                        "name": "price_change",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.CreateUser"
+                            "$ref": "#/definitions/models.CreatePriceChange"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created user",
+                        "description": "Created price change",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.PriceChange"
                         }
                     },
                     "400": {
@@ -5000,6 +5348,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -5009,9 +5363,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/user/{id}": {
+        "/v1/price_change/{id}": {
             "get": {
-                "description": "Get user details by user ID.",
+                "description": "Get a price change document with its lines.",
                 "consumes": [
                     "application/json"
                 ],
@@ -5019,13 +5373,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "price_change"
                 ],
-                "summary": "Get a user by ID",
+                "summary": "Get a price change by ID",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price change ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -5033,9 +5401,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "User details",
+                        "description": "Price change details",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.PriceChange"
                         }
                     },
                     "400": {
@@ -5044,8 +5412,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -5059,7 +5427,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "Update an existing user.",
+                "description": "Replace effective_from, comment and lines of a draft price change.",
                 "consumes": [
                     "application/json"
                 ],
@@ -5067,32 +5435,46 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "price_change"
                 ],
-                "summary": "Update a user",
+                "summary": "Update a price change",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price change ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Updated user information",
-                        "name": "user",
+                        "description": "Updated price change information",
+                        "name": "price_change",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.UpdateUser"
+                            "$ref": "#/definitions/models.UpdatePriceChange"
                         }
                     }
                 ],
                 "responses": {
                     "202": {
-                        "description": "Updated user",
+                        "description": "Updated price change",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.PriceChange"
                         }
                     },
                     "400": {
@@ -5101,8 +5483,8 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "User not found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
@@ -5116,7 +5498,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Delete an existing user.",
+                "description": "Delete a price change that has not been approved.",
                 "consumes": [
                     "application/json"
                 ],
@@ -5124,13 +5506,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "users"
+                    "price_change"
                 ],
-                "summary": "Delete a user",
+                "summary": "Delete a price change",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price change ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -5146,6 +5542,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/handler.ErrorResponse"
                         }
                     },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -5154,59 +5556,12309 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handler.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "code": {
-                    "type": "integer"
-                },
-                "message": {
-                    "type": "string"
-                }
-            }
         },
-        "models.Branch": {
-            "type": "object",
-            "properties": {
-                "address": {
-                    "type": "string"
-                },
-                "branch_code": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "phone": {
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
+        "/v1/price_change/{id}/approve": {
+            "post": {
+                "description": "Approve a draft price change. Its prices go into the branch price list and take effect at effective_from.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "price_change"
+                ],
+                "summary": "Approve a price change",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price change ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Approved price change",
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "models.Category": {
-            "type": "object",
-            "properties": {
-                "created_at": {
+        "/v1/price_change/{id}/cancel": {
+            "post": {
+                "description": "Cancel a draft price change.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "price_change"
+                ],
+                "summary": "Cancel a price change",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Price change ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Canceled price change",
+                        "schema": {
+                            "$ref": "#/definitions/models.PriceChange"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/product": {
+            "get": {
+                "description": "Get a list of products with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null, under; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. category_id[under] selects a category with its subcategories. Filter and sort fields: id, photo, title, category_id, barcode, price, unit, brand_id, created_at, updated_at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product"
+                ],
+                "summary": "Get a list of products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Exact barcode, or part of the title in Uzbek Latin, Uzbek Cyrillic or Russian",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)",
+                        "name": "created_at[between]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category ID, including its subcategories",
+                        "name": "category_id[under]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Brand ID",
+                        "name": "brand_id[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch ID the classes refer to",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ABC class in the latest classification (A, B, C)",
+                        "name": "abc",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "XYZ class in the latest classification (X, Y, Z)",
+                        "name": "xyz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of products",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Product"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new product in the market system. A product without brand_id is of the brand of its category, if that has one.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product"
+                ],
+                "summary": "Create a new product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Product information",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateProduct"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created product",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/product/search": {
+            "get": {
+                "description": "Look up products by barcode or name as it is typed at the point of sale, best matches first. A name is found whether it is written in Uzbek Latin, Uzbek Cyrillic or Russian, by the start of its words and despite typos. Filter with field[op]=value as in the product list, e.g. category_id[eq].",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product"
+                ],
+                "summary": "Search products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Barcode or name, or the start of it",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Matching products, best first",
+                        "schema": {
+                            "$ref": "#/definitions/models.SearchProductResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/product/{id}": {
+            "get": {
+                "description": "Get product details by its ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product"
+                ],
+                "summary": "Get a product by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product details",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing product.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product"
+                ],
+                "summary": "Update a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated product information",
+                        "name": "product",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateProduct"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Updated product",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete an existing product.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product"
+                ],
+                "summary": "Delete a product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/product_class": {
+            "get": {
+                "description": "Get the classes of a classification run, the latest one by default, and the number of products in every ABC/XYZ cell. The cell counts ignore the abc and xyz filters. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: branch_id, branch_name, barcode, product_name, revenue, units, share, cumulative_share, abc, weekly_mean, cv, xyz.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product_class"
+                ],
+                "summary": "Get product classes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by barcode or product name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch ID",
+                        "name": "branch_id[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Barcode",
+                        "name": "barcode[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ABC class (A, B, C)",
+                        "name": "abc[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "XYZ class (X, Y, Z)",
+                        "name": "xyz[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Run ID (default the latest run)",
+                        "name": "run_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product classes",
+                        "schema": {
+                            "$ref": "#/definitions/models.GetListProductClassResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/product_class/run": {
+            "get": {
+                "description": "Get the product classification runs, latest first. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, run_date, from_date, to_date, history_weeks, products, created_by, created_at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product_class"
+                ],
+                "summary": "Get a list of classification runs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of runs",
+                        "schema": {
+                            "$ref": "#/definitions/models.GetListProductClassRunResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Classify the products of every branch into ABC by their share of the branch revenue and into XYZ by the variability of their weekly demand, over the finished sales of the last history_weeks weeks. The result is stored as a new run.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product_class"
+                ],
+                "summary": "Classify products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Run parameters (history_weeks defaults to PRODUCT_CLASS_HISTORY_WEEKS)",
+                        "name": "run",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RunProductClass"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Classification run",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductClassRun"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/product_import": {
+            "get": {
+                "description": "Get product imports, latest first. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, status, file_name, format, total_rows, valid_rows, invalid_rows, imported_rows, created, updated, categories_created, brands_created, created_by, created_at, started_at, finished_at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product_import"
+                ],
+                "summary": "Get a list of product imports",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by file name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status (validated, running, done, failed)",
+                        "name": "status[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Author user ID",
+                        "name": "created_by[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of product imports",
+                        "schema": {
+                            "$ref": "#/definitions/models.GetListProductImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Validate a CSV or XLSX file of products and report the errors of every row. The columns are title, barcode, category (a path like \"Drinks / Tea\"), brand, price and unit. With dry_run=false the valid rows are imported in the background: missing categories and brands are created and products are created or updated by barcode.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product_import"
+                ],
+                "summary": "Import products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Product file (.csv or .xlsx)",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "File format (csv, xlsx), taken from the file name by default",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only validate the file (default true)",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Validated import with a preview of its rows",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductImport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/product_import/{id}": {
+            "get": {
+                "description": "Get a product import with its validation summary and progress.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product_import"
+                ],
+                "summary": "Get product import by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product import ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Product import",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductImport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/product_import/{id}/rows": {
+            "get": {
+                "description": "Get the rows of a product import by line, with the errors of the invalid ones. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: line, title, barcode, brand, price, unit, status, product_id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product_import"
+                ],
+                "summary": "Get the rows of a product import",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product import ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by title or barcode",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status (valid, invalid, imported)",
+                        "name": "status[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Rows of the import",
+                        "schema": {
+                            "$ref": "#/definitions/models.GetListProductImportRowResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/product_import/{id}/run": {
+            "post": {
+                "description": "Import the valid rows of a validated import in the background, or resume a failed one from the first row left.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "product_import"
+                ],
+                "summary": "Run a product import",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product import ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Running import",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductImport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/promotion": {
+            "get": {
+                "description": "Get a list of promotions, highest priority first. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, name, type, percent, category_id, brand_id, min_basket_amount, start_date, end_date, priority, stackable, is_active, created_at, updated_at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotion"
+                ],
+                "summary": "Get a list of promotions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search by name",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "true or false",
+                        "name": "is_active[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only promotions running in this branch",
+                        "name": "branch_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of promotions",
+                        "schema": {
+                            "$ref": "#/definitions/models.GetListPromotionResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a promotion rule: percent off (category, brand, barcodes or whole basket), buy X get Y, or fixed bundle price.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotion"
+                ],
+                "summary": "Create a promotion",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Promotion information",
+                        "name": "promotion",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreatePromotion"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created promotion",
+                        "schema": {
+                            "$ref": "#/definitions/models.Promotion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/promotion/report": {
+            "get": {
+                "description": "Per promotion: finished sales it touched, units, discount given and revenue of discounted lines.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotion"
+                ],
+                "summary": "Promotion effectiveness report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "From date, e.g. 2024-01-01",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To date (exclusive), e.g. 2024-02-01",
+                        "name": "to_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch ID",
+                        "name": "branch_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Promotion report",
+                        "schema": {
+                            "$ref": "#/definitions/models.PromotionReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/promotion/{id}": {
+            "get": {
+                "description": "Get promotion details with its branches and items.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotion"
+                ],
+                "summary": "Get a promotion by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Promotion details",
+                        "schema": {
+                            "$ref": "#/definitions/models.Promotion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update a promotion rule, its branches and items.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotion"
+                ],
+                "summary": "Update a promotion",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated promotion information",
+                        "name": "promotion",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdatePromotion"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Updated promotion",
+                        "schema": {
+                            "$ref": "#/definitions/models.Promotion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a promotion. Promotions already used in sales are deactivated instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "promotion"
+                ],
+                "summary": "Delete a promotion",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Promotion ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/purchase_order": {
+            "get": {
+                "description": "Get a list of purchase orders. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, supplier_id, branch_id, status, expected_date, created_by, sent_at, received_at, closed_at, created_at, updated_at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase_order"
+                ],
+                "summary": "Get a list of purchase orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Supplier ID",
+                        "name": "supplier_id[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch ID",
+                        "name": "branch_id[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "draft, sent, partially_received, received or closed",
+                        "name": "status[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of purchase orders",
+                        "schema": {
+                            "$ref": "#/definitions/models.GetListPurchaseOrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a draft purchase order to a supplier for a branch with the expected lines and prices.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase_order"
+                ],
+                "summary": "Create a purchase order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Purchase order information",
+                        "name": "purchase_order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreatePurchaseOrder"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created purchase order",
+                        "schema": {
+                            "$ref": "#/definitions/models.PurchaseOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/purchase_order/report": {
+            "get": {
+                "description": "Ordered against received quantities per supplier for purchase orders created in the period, with unit fill rate, share of lines delivered in full and share of orders received by their expected date.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase_order"
+                ],
+                "summary": "Supplier fill rate report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Orders created from (inclusive)",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Orders created before (exclusive)",
+                        "name": "to_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Supplier ID",
+                        "name": "supplier_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch ID",
+                        "name": "branch_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Fill rate per supplier",
+                        "schema": {
+                            "$ref": "#/definitions/models.SupplierFillRateResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/purchase_order/{id}": {
+            "get": {
+                "description": "Get a purchase order with its lines, received quantities and delivery status per line.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase_order"
+                ],
+                "summary": "Get a purchase order by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Purchase order details",
+                        "schema": {
+                            "$ref": "#/definitions/models.PurchaseOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace supplier, branch, expected date, comment and lines of a draft purchase order.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase_order"
+                ],
+                "summary": "Update a purchase order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated purchase order information",
+                        "name": "purchase_order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdatePurchaseOrder"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Updated purchase order",
+                        "schema": {
+                            "$ref": "#/definitions/models.PurchaseOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a purchase order that is still a draft.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase_order"
+                ],
+                "summary": "Delete a purchase order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/purchase_order/{id}/close": {
+            "post": {
+                "description": "Close a sent or (partially) received purchase order, accepting whatever was delivered. No more receipts are taken against it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase_order"
+                ],
+                "summary": "Close a purchase order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Closed purchase order",
+                        "schema": {
+                            "$ref": "#/definitions/models.PurchaseOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/purchase_order/{id}/receive": {
+            "post": {
+                "description": "Record a delivery against a sent purchase order. A finished income linked to the order is created, branch stock is increased and the order becomes partially_received or received. Quantities above the ordered ones are accepted as over-delivery.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase_order"
+                ],
+                "summary": "Receive goods against a purchase order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Received lines",
+                        "name": "receipt",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReceivePurchaseOrder"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Income and updated purchase order",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReceivePurchaseOrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/purchase_order/{id}/send": {
+            "post": {
+                "description": "Mark a draft purchase order as sent to the supplier. Only sent orders can be received.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "purchase_order"
+                ],
+                "summary": "Send a purchase order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Purchase order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Sent purchase order",
+                        "schema": {
+                            "$ref": "#/definitions/models.PurchaseOrder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/remainder": {
+            "get": {
+                "description": "Get a list of remainder with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null, under; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. category_id[under] selects a category with its subcategories. Filter and sort fields: id, branch_id, category_id, brand_id, product_name, barcode, price_income, quantity, created_at, updated_at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "remainder"
+                ],
+                "summary": "Get a list of remainder",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search term",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)",
+                        "name": "created_at[between]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category ID, including its subcategories",
+                        "name": "category_id[under]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Brand ID of the product",
+                        "name": "brand_id[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch ID the classes refer to",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ABC class in the latest classification (A, B, C)",
+                        "name": "abc",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "XYZ class in the latest classification (X, Y, Z)",
+                        "name": "xyz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of remainder",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Remainder"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new remainder in the market system.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "remainder"
+                ],
+                "summary": "Create a new remainder",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Remainder information",
+                        "name": "remainder",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateRemainder"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created remainder",
+                        "schema": {
+                            "$ref": "#/definitions/models.Remainder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/remainder/{id}": {
+            "get": {
+                "description": "Get remainder details by its ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "remainder"
+                ],
+                "summary": "Get a remainder by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Remainder ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Remainder details",
+                        "schema": {
+                            "$ref": "#/definitions/models.Remainder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Remainder not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing remainder.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "remainder"
+                ],
+                "summary": "Update a remainder",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Remainder ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated remainder information",
+                        "name": "remainder",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateRemainder"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Updated remainder",
+                        "schema": {
+                            "$ref": "#/definitions/models.Remainder"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Remainder not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete an existing remainder.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "remainder"
+                ],
+                "summary": "Delete a remainder",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Remainder ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Remainder not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/replenishment": {
+            "get": {
+                "description": "Order quantities for the products of a branch, from stock on hand, stock on order, recent sales and lead time, grouped by the supplier each product was last bought from.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "replenishment"
+                ],
+                "summary": "Replenishment suggestions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch ID",
+                        "name": "branch_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Days of sales to average (default 28)",
+                        "name": "history_days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Days an order takes to arrive (default 3)",
+                        "name": "lead_days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated supplier IDs",
+                        "name": "supplier_ids",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Replenishment suggestions",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReplenishmentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/replenishment/purchase_order": {
+            "post": {
+                "description": "Turn the current suggestions of a branch into one draft purchase order per supplier, priced at the last purchase price. Products never bought from a supplier are left out.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "replenishment"
+                ],
+                "summary": "Create purchase orders from replenishment suggestions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Branch and suggestion parameters",
+                        "name": "replenishment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateReplenishmentOrders"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created purchase orders",
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateReplenishmentOrdersResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/report/cashier": {
+            "get": {
+                "description": "Per cashier and shift: sales, revenue, items per minute, average receipt, voided and returned sales, and cash variance at shift close.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Cashier performance report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Shifts opened from, inclusive (default first day of this month)",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Shifts opened before, exclusive (default tomorrow)",
+                        "name": "to_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch ID",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cashier ID",
+                        "name": "cashier_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Cashier performance",
+                        "schema": {
+                            "$ref": "#/definitions/models.CashierReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/report/inventory/aging": {
+            "get": {
+                "description": "Stock on hand bucketed by days since it was last received, and dead stock: products received and not sold for dead_days.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Stock aging",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch ID",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category ID, including its subcategories",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated ascending bucket bounds in days (default 30,60,90,180)",
+                        "name": "buckets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Days without sales for dead stock (default 90)",
+                        "name": "dead_days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stock aging",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockAgingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/report/inventory/valuation": {
+            "get": {
+                "description": "Stock per branch and category valued at cost and at retail price, with the potential margin. A past as_of date reconstructs the stock at the end of that day from stock movements.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Inventory valuation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Value the stock at the end of this day (default now)",
+                        "name": "as_of",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch ID",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category ID, including its subcategories",
+                        "name": "category_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Inventory valuation",
+                        "schema": {
+                            "$ref": "#/definitions/models.InventoryValuationResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/report/sales": {
+            "get": {
+                "description": "Revenue, sale count, average basket, units sold and gross margin of finished sales, grouped by any of period, branch, sale_point, cashier, category and brand. The brand of a line is that of its product, or of its category when the product has none. With compare the totals, and the rows when not grouped by period, are compared with the preceding period of the same length.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Sales report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sold from, inclusive (default first day of this month)",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sold before, exclusive (default tomorrow)",
+                        "name": "to_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated: period, branch, sale_point, cashier, category, brand",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Period of the period group: day, week or month (default day)",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Compare with the previous period",
+                        "name": "compare",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch ID",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sale point ID",
+                        "name": "sale_point_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cashier ID",
+                        "name": "cashier_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category ID, including its subcategories",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Brand ID",
+                        "name": "brand_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sales report",
+                        "schema": {
+                            "$ref": "#/definitions/models.SalesReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/report/sales/heatmap": {
+            "get": {
+                "description": "Finished sales and revenue per branch and sale point by weekday (1 Monday to 7 Sunday) and hour of the day, for staffing tills by traffic.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Sales heatmap",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sold from, inclusive (default first day of this month)",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sold before, exclusive (default tomorrow)",
+                        "name": "to_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch ID",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sale point ID",
+                        "name": "sale_point_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Heatmap",
+                        "schema": {
+                            "$ref": "#/definitions/models.HeatmapResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/report/sales/top_products": {
+            "get": {
+                "description": "Best selling products of finished sales by revenue, units or gross margin, ranked within each period when an interval is given.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "report"
+                ],
+                "summary": "Top products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sold from, inclusive (default first day of this month)",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sold before, exclusive (default tomorrow)",
+                        "name": "to_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "day, week or month; rank over the whole range when empty",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "revenue, units or margin (default revenue)",
+                        "name": "order_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Products per period (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch ID",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sale point ID",
+                        "name": "sale_point_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cashier ID",
+                        "name": "cashier_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category ID, including its subcategories",
+                        "name": "category_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Brand ID",
+                        "name": "brand_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Top products",
+                        "schema": {
+                            "$ref": "#/definitions/models.TopProductsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/sale": {
+            "get": {
+                "description": "Get a list of sales with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, sale_id, branch_id, salepoint_id, shift_id, employee_id, barcode, status, customer_id, created_at, updated_at. Sorted by created_at alone (the default) the list is also paged by cursor: a page with more after it has a next_cursor to pass as cursor, which is much faster than a large offset.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale"
+                ],
+                "summary": "Get a list of sales",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search term",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)",
+                        "name": "created_at[between]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the page before, for lists sorted by created_at alone",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count all matching items (default true without a cursor, false with one)",
+                        "name": "count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of sales",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Sale"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new sale in the market system.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale"
+                ],
+                "summary": "Create a new sale",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Sale information",
+                        "name": "sale",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateSale"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created sale",
+                        "schema": {
+                            "$ref": "#/definitions/models.Sale"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/sale/{id}": {
+            "get": {
+                "description": "Get sale details by its ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale"
+                ],
+                "summary": "Get a sale by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sale ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sale details",
+                        "schema": {
+                            "$ref": "#/definitions/models.Sale"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Sale not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing sale.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale"
+                ],
+                "summary": "Update a sale",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sale ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated sale information",
+                        "name": "sale",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateSale"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Updated sale",
+                        "schema": {
+                            "$ref": "#/definitions/models.Sale"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Sale not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete an existing sale.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale"
+                ],
+                "summary": "Delete a sale",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sale ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/sale/{id}/cancel": {
+            "post": {
+                "description": "Void a sale that was not paid. A finished sale is taken back with the return instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale"
+                ],
+                "summary": "Cancel a sale",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sale ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Canceled sale",
+                        "schema": {
+                            "$ref": "#/definitions/models.Sale"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/sale/{id}/customer": {
+            "post": {
+                "description": "Identify the customer of an open sale by phone or loyalty card barcode.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale"
+                ],
+                "summary": "Attach a customer to a sale",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sale ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Phone or card barcode",
+                        "name": "customer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AttachSaleCustomer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Attached customer",
+                        "schema": {
+                            "$ref": "#/definitions/models.Customer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/sale/{id}/return": {
+            "post": {
+                "description": "Take a finished sale back. Its goods go back into the branch stock, the loyalty points it earned and redeemed are undone and what was paid for the goods in money is refunded as store credit. Gift cards sold in the sale stay active.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale"
+                ],
+                "summary": "Return a sale",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sale ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Return comment",
+                        "name": "return",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReturnSale"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Returned sale and its store credit",
+                        "schema": {
+                            "$ref": "#/definitions/models.SaleReturn"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/sale_point": {
+            "get": {
+                "description": "Get a list of sale points with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, branch_id, name, created_at, updated_at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale_point"
+                ],
+                "summary": "Get a list of sale points",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search term",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)",
+                        "name": "created_at[between]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of sale points",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SalePoint"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new sale point in the market system.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale_point"
+                ],
+                "summary": "Create a new sale point",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Sale point information",
+                        "name": "salePoint",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateSalePoint"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created sale point",
+                        "schema": {
+                            "$ref": "#/definitions/models.SalePoint"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/sale_point/{id}": {
+            "get": {
+                "description": "Get sale point details by its ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale_point"
+                ],
+                "summary": "Get a sale point by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sale Point ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sale point details",
+                        "schema": {
+                            "$ref": "#/definitions/models.SalePoint"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Sale point not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing sale point.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale_point"
+                ],
+                "summary": "Update a sale point",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sale Point ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated sale point information",
+                        "name": "salePoint",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateSalePoint"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Updated sale point",
+                        "schema": {
+                            "$ref": "#/definitions/models.SalePoint"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Sale point not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete an existing sale point.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale_point"
+                ],
+                "summary": "Delete a sale point",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sale Point ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/sale_product": {
+            "get": {
+                "description": "Get a list of sale products with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, sale_id, category_id, product_name, barcode, remaining_quantity, quantity, allow_discount, discount_type, discount, price, total_amount, created_at, updated_at. Sorted by created_at alone (the default) the list is also paged by cursor: a page with more after it has a next_cursor to pass as cursor, which is much faster than a large offset.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale_product"
+                ],
+                "summary": "Get a list of sale products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search term",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)",
+                        "name": "created_at[between]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the page before, for lists sorted by created_at alone",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count all matching items (default true without a cursor, false with one)",
+                        "name": "count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of sale products",
+                        "schema": {
+                            "$ref": "#/definitions/models.GetListSaleProductResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new sale product in the market system.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale_product"
+                ],
+                "summary": "Create a new sale product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Sale Product information",
+                        "name": "saleProduct",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateSaleProduct"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created sale product",
+                        "schema": {
+                            "$ref": "#/definitions/models.SaleProduct"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/sale_product/{id}": {
+            "get": {
+                "description": "Get sale product details by its ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale_product"
+                ],
+                "summary": "Get a sale product by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sale Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Sale Product details",
+                        "schema": {
+                            "$ref": "#/definitions/models.SaleProduct"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Sale Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing sale product.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale_product"
+                ],
+                "summary": "Update a sale product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sale Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated sale product information",
+                        "name": "saleProduct",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateSaleProduct"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Updated sale product",
+                        "schema": {
+                            "$ref": "#/definitions/models.SaleProduct"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Sale Product not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete an existing sale product.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "sale_product"
+                ],
+                "summary": "Delete a sale product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sale Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/shift": {
+            "get": {
+                "description": "Get a list of shifts with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, branch_id, user_id, sale_point_id, status, open_shift, close_shift, opening_cash, counted_cash, created_at, updated_at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shift"
+                ],
+                "summary": "Get a list of shifts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search term",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)",
+                        "name": "created_at[between]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of shifts",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Shift"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new shift in the market system.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shift"
+                ],
+                "summary": "Create a new shift",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Shift information",
+                        "name": "shift",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateShift"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created shift",
+                        "schema": {
+                            "$ref": "#/definitions/models.Shift"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/shift/{id}": {
+            "get": {
+                "description": "Get shift details by its ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shift"
+                ],
+                "summary": "Get a shift by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Shift ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Shift details",
+                        "schema": {
+                            "$ref": "#/definitions/models.Shift"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Shift not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing shift.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shift"
+                ],
+                "summary": "Update a shift",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Shift ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated shift information",
+                        "name": "shift",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateShift"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Updated shift",
+                        "schema": {
+                            "$ref": "#/definitions/models.Shift"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Shift not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete an existing shift.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shift"
+                ],
+                "summary": "Delete a shift",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Shift ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/stock_level": {
+            "get": {
+                "description": "Get a list of stock levels. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, branch_id, product_id, product_name, barcode, min_quantity, max_quantity, created_at, updated_at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock_level"
+                ],
+                "summary": "Get a list of stock levels",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch ID",
+                        "name": "branch_id[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "product_id[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of stock levels",
+                        "schema": {
+                            "$ref": "#/definitions/models.GetListStockLevelResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Set the minimum and maximum stock of a product in a branch. Setting them again for the same product and branch replaces the old levels.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock_level"
+                ],
+                "summary": "Set stock levels",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Stock level information",
+                        "name": "stock_level",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateStockLevel"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Stock level",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockLevel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/stock_level/low_stock": {
+            "get": {
+                "description": "Products at or below their minimum stock, with what open purchase orders still have to deliver.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock_level"
+                ],
+                "summary": "Low-stock alert",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch ID",
+                        "name": "branch_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Low-stock products",
+                        "schema": {
+                            "$ref": "#/definitions/models.LowStockResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/stock_level/{id}": {
+            "get": {
+                "description": "Get stock levels by ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock_level"
+                ],
+                "summary": "Get stock levels by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stock level ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stock level details",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockLevel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update the minimum and maximum stock of a stock level.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock_level"
+                ],
+                "summary": "Update stock levels",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stock level ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Stock level information",
+                        "name": "stock_level",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateStockLevel"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Updated stock level",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockLevel"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete stock levels by ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock_level"
+                ],
+                "summary": "Delete stock levels",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stock level ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/stock_lot": {
+            "get": {
+                "description": "Branch stock per lot and expiry date, in the order sales consume it (earliest expiry first). Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, branch_id, barcode, product_name, lot_number, expiry_date, quantity, cost, created_at, updated_at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock_lot"
+                ],
+                "summary": "Get a list of stock lots",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch ID",
+                        "name": "branch_id[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Barcode",
+                        "name": "barcode[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only lots with quantity left",
+                        "name": "in_stock",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of stock lots",
+                        "schema": {
+                            "$ref": "#/definitions/models.GetListStockLotResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/stock_lot/expiring": {
+            "get": {
+                "description": "Lots in stock that expire within the given number of days, already expired ones included, so staff can discount or pull them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock_lot"
+                ],
+                "summary": "Expiring goods",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch ID",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Days ahead (default 7)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Expiring lots",
+                        "schema": {
+                            "$ref": "#/definitions/models.ExpiringStockLotResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/stock_movement": {
+            "get": {
+                "description": "Every change of branch stock made by incomes, sales, supplier returns, transfers, inventory counts and write-offs, newest first. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, branch_id, barcode, product_name, type, document_id, quantity, cost, balance_after, created_at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "stock_movement"
+                ],
+                "summary": "Stock movement history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch ID",
+                        "name": "branch_id[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Barcode",
+                        "name": "barcode[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "income, sale, supplier_return, transfer_out, transfer_in, count_surplus, count_shortage or write_off",
+                        "name": "type[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "document_id[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From (inclusive)",
+                        "name": "created_at[gte]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To (exclusive)",
+                        "name": "created_at[lt]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of stock movements",
+                        "schema": {
+                            "$ref": "#/definitions/models.GetListStockMovementResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/supplier": {
+            "get": {
+                "description": "Get a list of suppliers with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, name, phone_number, is_active, created_at, updated_at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier"
+                ],
+                "summary": "Get a list of suppliers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search term",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)",
+                        "name": "created_at[between]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of suppliers",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Supplier"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new supplier in the market system.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier"
+                ],
+                "summary": "Create a new supplier",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Supplier information",
+                        "name": "supplier",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateSupplier"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created supplier",
+                        "schema": {
+                            "$ref": "#/definitions/models.Supplier"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/supplier/aging": {
+            "get": {
+                "description": "What is owed to each supplier split into 0-30, 31-60, 61-90 and over 90 days by invoice age, after payments and returns settle the oldest invoices.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier"
+                ],
+                "summary": "Supplier payables aging",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Aging date, now when empty",
+                        "name": "at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Supplier ID",
+                        "name": "supplier_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Aging per supplier",
+                        "schema": {
+                            "$ref": "#/definitions/models.SupplierAgingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/supplier/{id}": {
+            "get": {
+                "description": "Get supplier details by its ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier"
+                ],
+                "summary": "Get a supplier by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Supplier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Supplier details",
+                        "schema": {
+                            "$ref": "#/definitions/models.Supplier"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Supplier not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing supplier.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier"
+                ],
+                "summary": "Update a supplier",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Supplier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated supplier information",
+                        "name": "supplier",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateSupplier"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Updated supplier",
+                        "schema": {
+                            "$ref": "#/definitions/models.Supplier"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Supplier not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete an existing supplier.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier"
+                ],
+                "summary": "Delete a supplier",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Supplier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/supplier/{id}/payment": {
+            "post": {
+                "description": "Record a cash or bank payment to a supplier. Partial payments are allowed; they settle the oldest invoices first.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier"
+                ],
+                "summary": "Pay a supplier",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Supplier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payment information",
+                        "name": "payment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateSupplierPayment"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Recorded payment with the balance owed after it",
+                        "schema": {
+                            "$ref": "#/definitions/models.SupplierTransaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/supplier/{id}/statement": {
+            "get": {
+                "description": "Invoices from finished incomes, payments and returns of a supplier over a date range with opening, running and closing balance, and aging as of to_date.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier"
+                ],
+                "summary": "Supplier account statement",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Supplier ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Period start (inclusive), from the first entry when empty",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Period end (exclusive), now when empty",
+                        "name": "to_date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Account statement",
+                        "schema": {
+                            "$ref": "#/definitions/models.SupplierStatement"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/supplier_invoice": {
+            "get": {
+                "description": "Get imported supplier invoices, latest first, with the number of lines that are matched, have issues or are unresolved. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, income_id, income_status, supplier_id, branch_id, format, file_name, number, invoice_date, seller_tin, seller_name, total_amount, unresolved, created_by, created_at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier_invoice"
+                ],
+                "summary": "Get a list of supplier invoices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Supplier ID",
+                        "name": "supplier_id[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch ID",
+                        "name": "branch_id[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of supplier invoices",
+                        "schema": {
+                            "$ref": "#/definitions/models.GetListSupplierInvoiceResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Read a supplier invoice (CSV or XLSX with a header row, or a Soliq/Didox e-invoice as JSON or XML) into a new draft income. Lines are matched to products by barcode, then by the mapping saved for the supplier, then by name; matched lines become income products. Every line lists the issues the receiving clerk should check, and unmatched or invalid lines have to be matched or skipped before the income is posted.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier_invoice"
+                ],
+                "summary": "Import a supplier invoice",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Invoice file (.csv, .xlsx, .json or .xml)",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Receiving branch ID",
+                        "name": "branch_id",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Supplier ID",
+                        "name": "supplier_id",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "File format (csv, xlsx, json, xml), taken from the file name by default",
+                        "name": "format",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Invoice number when the file has none",
+                        "name": "number",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Invoice date (YYYY-MM-DD) when the file has none",
+                        "name": "date",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Imported invoice with its lines",
+                        "schema": {
+                            "$ref": "#/definitions/models.SupplierInvoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/supplier_invoice/{id}": {
+            "get": {
+                "description": "Get an imported supplier invoice with its lines, their matches and issues.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier_invoice"
+                ],
+                "summary": "Get supplier invoice by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Supplier invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Supplier invoice",
+                        "schema": {
+                            "$ref": "#/definitions/models.SupplierInvoice"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/supplier_invoice/{id}/line/{line}": {
+            "put": {
+                "description": "Match a line of an invoice whose income is not posted yet to a product, optionally remembering the match for the supplier's future invoices, or skip the line so it is not received.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier_invoice"
+                ],
+                "summary": "Resolve a supplier invoice line",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Supplier invoice ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Line number",
+                        "name": "line",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Product to match (product_id, remember) or skip",
+                        "name": "resolve",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ResolveSupplierInvoiceLine"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Resolved line",
+                        "schema": {
+                            "$ref": "#/definitions/models.SupplierInvoiceLine"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/supplier_return": {
+            "get": {
+                "description": "Get a list of supplier returns. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, supplier_id, branch_id, income_id, status, created_by, posted_at, created_at, updated_at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier_return"
+                ],
+                "summary": "Get a list of supplier returns",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Supplier ID",
+                        "name": "supplier_id[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch ID",
+                        "name": "branch_id[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Income ID",
+                        "name": "income_id[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "draft, posted or canceled",
+                        "name": "status[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of supplier returns",
+                        "schema": {
+                            "$ref": "#/definitions/models.GetListSupplierReturnResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a draft return of goods from a branch to a supplier, optionally linked to the income they came with.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier_return"
+                ],
+                "summary": "Create a supplier return",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Supplier return information",
+                        "name": "supplier_return",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateSupplierReturn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created supplier return",
+                        "schema": {
+                            "$ref": "#/definitions/models.SupplierReturn"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/supplier_return/{id}": {
+            "get": {
+                "description": "Get a supplier return with its lines.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier_return"
+                ],
+                "summary": "Get a supplier return by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Supplier return ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Supplier return details",
+                        "schema": {
+                            "$ref": "#/definitions/models.SupplierReturn"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the income link, comment and lines of a draft supplier return.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier_return"
+                ],
+                "summary": "Update a supplier return",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Supplier return ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated supplier return information",
+                        "name": "supplier_return",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateSupplierReturn"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Updated supplier return",
+                        "schema": {
+                            "$ref": "#/definitions/models.SupplierReturn"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a supplier return that is still a draft.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier_return"
+                ],
+                "summary": "Delete a supplier return",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Supplier return ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/supplier_return/{id}/cancel": {
+            "post": {
+                "description": "Cancel a draft supplier return.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier_return"
+                ],
+                "summary": "Cancel a supplier return",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Supplier return ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Canceled supplier return",
+                        "schema": {
+                            "$ref": "#/definitions/models.SupplierReturn"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/supplier_return/{id}/post": {
+            "post": {
+                "description": "Post a draft supplier return: its lines leave branch stock and the supplier balance is credited with the return cost.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supplier_return"
+                ],
+                "summary": "Post a supplier return",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Supplier return ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Posted supplier return",
+                        "schema": {
+                            "$ref": "#/definitions/models.SupplierReturn"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/transaction": {
+            "get": {
+                "description": "Get a list of transactions with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, shift_id, cash, uzcard, payme, click, humo, apelsin, points, gift_card, gift_card_sold, total_amount, created_at, updated_at. Sorted by created_at alone (the default) the list is also paged by cursor: a page with more after it has a next_cursor to pass as cursor, which is much faster than a large offset.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Get a list of transactions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search term",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)",
+                        "name": "created_at[between]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the page before, for lists sorted by created_at alone",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Count all matching items (default true without a cursor, false with one)",
+                        "name": "count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of transactions",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Transaction"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new transaction in the market system.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Create a new transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Transaction information",
+                        "name": "transaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTransaction"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created transaction",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/transaction/{id}": {
+            "get": {
+                "description": "Get transaction details by its ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Get a transaction by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Transaction details",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing transaction.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Update a transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated transaction information",
+                        "name": "transaction",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateTransaction"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Updated transaction",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transaction"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Transaction not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete an existing transaction.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Delete a transaction",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transaction ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/transfer": {
+            "get": {
+                "description": "Get a list of transfers. branch_id matches either end of the transfer. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, from_branch_id, to_branch_id, status, created_by, dispatched_at, received_by, received_at, created_at, updated_at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Get a list of transfers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "draft, in_transit, received or canceled",
+                        "name": "status[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Source or destination branch ID",
+                        "name": "branch_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of transfers",
+                        "schema": {
+                            "$ref": "#/definitions/models.GetListTransferResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a draft transfer of goods from one branch to another.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Create a transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Transfer information",
+                        "name": "transfer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateTransfer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created transfer",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/transfer/in_transit": {
+            "get": {
+                "description": "Quantity and cost of dispatched but not yet received transfers per branch, outgoing and incoming.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "In-transit report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch ID",
+                        "name": "branch_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "In-transit goods per branch",
+                        "schema": {
+                            "$ref": "#/definitions/models.InTransitResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/transfer/{id}": {
+            "get": {
+                "description": "Get a transfer with its lines and shortages.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Get a transfer by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Transfer details",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the destination, comment and lines of a draft transfer.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Update a transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated transfer information",
+                        "name": "transfer",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateTransfer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Updated transfer",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a transfer that is still a draft.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Delete a transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/transfer/{id}/cancel": {
+            "post": {
+                "description": "Cancel a draft transfer.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Cancel a transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Canceled transfer",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/transfer/{id}/dispatch": {
+            "post": {
+                "description": "Take the lines of a draft transfer out of the source branch stock. The goods stay in transit until the destination receives them.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Dispatch a transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Transfer in transit",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/transfer/{id}/receive": {
+            "post": {
+                "description": "Put an in-transit transfer into the destination branch stock. Lines not listed are received in full; a lower received_quantity is recorded as a shortage.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "transfer"
+                ],
+                "summary": "Receive a transfer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transfer ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Received quantities",
+                        "name": "receipt",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReceiveTransfer"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Received transfer",
+                        "schema": {
+                            "$ref": "#/definitions/models.Transfer"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user": {
+            "get": {
+                "description": "Get a list of users with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, first_name, last_name, login, active, client_type, created_at, updated_at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get a list of users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search term",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)",
+                        "name": "created_at[between]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of users",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.User"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new user in the market system.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Create a new user",
+                "parameters": [
+                    {
+                        "description": "User information",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created user",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/{id}": {
+            "get": {
+                "description": "Get user details by user ID.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Get a user by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "User details",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update an existing user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Update a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated user information",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateUser"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Updated user",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete an existing user.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "users"
+                ],
+                "summary": "Delete a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/write_off": {
+            "get": {
+                "description": "Get a list of write-offs. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, branch_id, reason, status, created_by, reviewed_by, reviewed_at, posted_at, created_at, updated_at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "write_off"
+                ],
+                "summary": "Get a list of write-offs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to return (default 10)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of items to skip (default 0)",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch ID",
+                        "name": "branch_id[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "expired, damaged, theft or internal_use",
+                        "name": "reason[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "draft, pending_approval, posted, rejected or canceled",
+                        "name": "status[eq]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item fields to return, comma separated",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "List of write-offs",
+                        "schema": {
+                            "$ref": "#/definitions/models.GetListWriteOffResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a draft write-off of branch stock for a reason: expired, damaged, theft or internal_use.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "write_off"
+                ],
+                "summary": "Create a write-off",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Write-off information",
+                        "name": "write_off",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.CreateWriteOff"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created write-off",
+                        "schema": {
+                            "$ref": "#/definitions/models.WriteOff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/write_off/report": {
+            "get": {
+                "description": "Cost and quantity of posted write-offs per period, branch, category and reason, with totals per reason.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "write_off"
+                ],
+                "summary": "Shrinkage report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Branch ID",
+                        "name": "branch_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Posted from (inclusive)",
+                        "name": "from_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Posted before (exclusive)",
+                        "name": "to_date",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "day, week or month (default month)",
+                        "name": "interval",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Shrinkage",
+                        "schema": {
+                            "$ref": "#/definitions/models.ShrinkageReportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/write_off/{id}": {
+            "get": {
+                "description": "Get a write-off with its lines.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "write_off"
+                ],
+                "summary": "Get a write-off by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Write-off ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Write-off details",
+                        "schema": {
+                            "$ref": "#/definitions/models.WriteOff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace the reason, comment and lines of a draft write-off.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "write_off"
+                ],
+                "summary": "Update a write-off",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Write-off ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Updated write-off information",
+                        "name": "write_off",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UpdateWriteOff"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Updated write-off",
+                        "schema": {
+                            "$ref": "#/definitions/models.WriteOff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a write-off that is still a draft.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "write_off"
+                ],
+                "summary": "Delete a write-off",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Write-off ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/write_off/{id}/approve": {
+            "post": {
+                "description": "Approve a write-off pending approval; its lines leave branch stock.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "write_off"
+                ],
+                "summary": "Approve a write-off",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Write-off ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Posted write-off",
+                        "schema": {
+                            "$ref": "#/definitions/models.WriteOff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/write_off/{id}/cancel": {
+            "post": {
+                "description": "Cancel a draft write-off.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "write_off"
+                ],
+                "summary": "Cancel a write-off",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Write-off ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Canceled write-off",
+                        "schema": {
+                            "$ref": "#/definitions/models.WriteOff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/write_off/{id}/post": {
+            "post": {
+                "description": "Submit a draft write-off. Up to the approval threshold its lines leave branch stock at once; above it the write-off waits for approval.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "write_off"
+                ],
+                "summary": "Post a write-off",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Write-off ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Posted or pending write-off",
+                        "schema": {
+                            "$ref": "#/definitions/models.WriteOff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/write_off/{id}/reject": {
+            "post": {
+                "description": "Reject a write-off pending approval; stock is left untouched.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "write_off"
+                ],
+                "summary": "Reject a write-off",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Authentication token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "User password",
+                        "name": "Password",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Write-off ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Rejected write-off",
+                        "schema": {
+                            "$ref": "#/definitions/models.WriteOff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handler.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AdjustLoyaltyPoints": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.AgingBucket": {
+            "type": "object",
+            "properties": {
+                "cost_value": {
+                    "type": "number"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AttachSaleCustomer": {
+            "type": "object",
+            "properties": {
+                "card_barcode": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Branch": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "branch_code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BranchPrice": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "effective_to": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "price_change_id": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BranchPriceHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BranchPrice"
+                    }
+                },
+                "in_effect": {
+                    "$ref": "#/definitions/models.ResolvedPrice"
+                }
+            }
+        },
+        "models.Brand": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CashierPerformance": {
+            "type": "object",
+            "properties": {
+                "avg_receipt": {
+                    "type": "number"
+                },
+                "cash_variance": {
+                    "type": "number"
+                },
+                "cashier_id": {
+                    "type": "string"
+                },
+                "cashier_name": {
+                    "type": "string"
+                },
+                "items": {
+                    "type": "integer"
+                },
+                "items_per_minute": {
+                    "type": "number"
+                },
+                "minutes": {
+                    "type": "number"
+                },
+                "returns": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "type": "number"
+                },
+                "sales": {
+                    "type": "integer"
+                },
+                "shifts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CashierShift"
+                    }
+                },
+                "voids": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CashierReportResponse": {
+            "type": "object",
+            "properties": {
+                "cashiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CashierPerformance"
+                    }
+                }
+            }
+        },
+        "models.CashierShift": {
+            "type": "object",
+            "properties": {
+                "avg_receipt": {
+                    "type": "number"
+                },
+                "branch_id": {
+                    "type": "string"
+                },
+                "branch_name": {
+                    "type": "string"
+                },
+                "cash_variance": {
+                    "type": "number"
+                },
+                "cashier_id": {
+                    "type": "string"
+                },
+                "cashier_name": {
+                    "type": "string"
+                },
+                "close_shift": {
+                    "type": "string"
+                },
+                "counted_cash": {
+                    "type": "number"
+                },
+                "expected_cash": {
+                    "type": "number"
+                },
+                "items": {
+                    "type": "integer"
+                },
+                "items_per_minute": {
+                    "type": "number"
+                },
+                "minutes": {
+                    "type": "number"
+                },
+                "open_shift": {
+                    "type": "string"
+                },
+                "opening_cash": {
+                    "type": "number"
+                },
+                "returns": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "type": "number"
+                },
+                "sale_point_id": {
+                    "type": "string"
+                },
+                "sale_point_name": {
+                    "type": "string"
+                },
+                "sales": {
+                    "type": "integer"
+                },
+                "shift_id": {
+                    "type": "string"
+                },
+                "voids": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Category": {
+            "type": "object",
+            "properties": {
+                "brand_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CategoryNode": {
+            "type": "object",
+            "properties": {
+                "brand_id": {
+                    "type": "string"
+                },
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CategoryNode"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "product_count": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "total_product_count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateBranch": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "branch_code": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateBranchPrice": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateBrand": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateCategory": {
+            "type": "object",
+            "properties": {
+                "brand_id": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateCustomer": {
+            "type": "object",
+            "properties": {
+                "card_barcode": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateIncome": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "date_time": {
+                    "type": "string"
+                },
+                "purchase_order_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "supplier_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateIncomeProduct": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "expiry_date": {
+                    "type": "string"
+                },
+                "income_id": {
+                    "type": "string"
+                },
+                "income_price": {
+                    "type": "number"
+                },
+                "lot_number": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateInventoryCount": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateLoyaltyTier": {
+            "type": "object",
+            "properties": {
+                "earn_multiplier": {
+                    "type": "number"
+                },
+                "min_spent": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreatePayment": {
+            "type": "object",
+            "properties": {
+                "apelsin": {
+                    "type": "number"
+                },
+                "cash": {
+                    "type": "number"
+                },
+                "click": {
+                    "type": "number"
+                },
+                "gift_card": {
+                    "type": "number"
+                },
+                "gift_card_code": {
+                    "type": "string"
+                },
+                "humo": {
+                    "type": "number"
+                },
+                "payme": {
+                    "type": "number"
+                },
+                "points": {
+                    "type": "number"
+                },
+                "sale_id": {
+                    "type": "string"
+                },
+                "total_amount": {
+                    "type": "number"
+                },
+                "uzcard": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.CreatePriceChange": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CreatePriceChangeProduct"
+                    }
+                }
+            }
+        },
+        "models.CreatePriceChangeProduct": {
+            "type": "object",
+            "properties": {
+                "new_price": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateProduct": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "brand_id": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "photo": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreatePromotion": {
+            "type": "object",
+            "properties": {
+                "branch_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "brand_id": {
+                    "type": "string"
+                },
+                "bundle_price": {
+                    "type": "number"
+                },
+                "buy_quantity": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "get_quantity": {
+                    "type": "integer"
+                },
+                "happy_hour_from": {
+                    "type": "string"
+                },
+                "happy_hour_to": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PromotionItem"
+                    }
+                },
+                "min_basket_amount": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "percent": {
+                    "type": "number"
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "stackable": {
+                    "type": "boolean"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreatePurchaseOrder": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "expected_date": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CreatePurchaseOrderProduct"
+                    }
+                },
+                "supplier_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreatePurchaseOrderProduct": {
+            "type": "object",
+            "properties": {
+                "price": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateRemainder": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "branch_id": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "price_income": {
+                    "type": "number"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateReplenishmentOrders": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "expected_date": {
+                    "type": "string"
+                },
+                "history_days": {
+                    "type": "integer"
+                },
+                "lead_days": {
+                    "type": "integer"
+                },
+                "supplier_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.CreateReplenishmentOrdersResponse": {
+            "type": "object",
+            "properties": {
+                "purchase_orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PurchaseOrder"
+                    }
+                }
+            }
+        },
+        "models.CreateSale": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "branch_id": {
+                    "type": "string"
+                },
+                "employee_id": {
+                    "type": "string"
+                },
+                "sale_id": {
+                    "type": "string"
+                },
+                "salepoint_id": {
+                    "type": "string"
+                },
+                "shift_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateSalePoint": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateSaleProduct": {
+            "type": "object",
+            "properties": {
+                "allow_discount": {
+                    "type": "boolean"
+                },
+                "barcode": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "discount": {
+                    "type": "number"
+                },
+                "discount_type": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "remaining_quantity": {
+                    "type": "integer"
+                },
+                "sale_id": {
+                    "type": "string"
+                },
+                "total_amount": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.CreateShift": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "close_shift": {
+                    "type": "string"
+                },
+                "open_shift": {
+                    "type": "string"
+                },
+                "sale_point_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateStockLevel": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "max_quantity": {
+                    "type": "integer"
+                },
+                "min_quantity": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateStoreCredit": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "sale_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateSupplier": {
+            "type": "object",
+            "properties": {
+                "is_active": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateSupplierPayment": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "branch_id": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "date_time": {
+                    "type": "string"
+                },
+                "method": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateSupplierReturn": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "income_id": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CreateSupplierReturnProduct"
+                    }
+                },
+                "supplier_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateSupplierReturnProduct": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "cost": {
+                    "type": "number"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateTransaction": {
+            "type": "object",
+            "properties": {
+                "apelsin": {
+                    "type": "number"
+                },
+                "cash": {
+                    "type": "number"
+                },
+                "click": {
+                    "type": "number"
+                },
+                "gift_card": {
+                    "type": "number"
+                },
+                "gift_card_sold": {
+                    "type": "number"
+                },
+                "humo": {
+                    "type": "number"
+                },
+                "payme": {
+                    "type": "number"
+                },
+                "points": {
+                    "type": "number"
+                },
+                "sale_id": {
+                    "type": "string"
+                },
+                "total_amount": {
+                    "type": "number"
+                },
+                "uzcard": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.CreateTransfer": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "from_branch_id": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CreateTransferProduct"
+                    }
+                },
+                "to_branch_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateTransferProduct": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CreateUser": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "client_type": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "login": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateWriteOff": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CreateWriteOffProduct"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CreateWriteOffProduct": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Customer": {
+            "type": "object",
+            "properties": {
+                "card_barcode": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "points_balance": {
+                    "type": "number"
+                },
+                "tier_id": {
+                    "type": "string"
+                },
+                "tier_name": {
+                    "type": "string"
+                },
+                "total_spent": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.DeadStock": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "branch_id": {
+                    "type": "string"
+                },
+                "branch_name": {
+                    "type": "string"
+                },
+                "cost_value": {
+                    "type": "number"
+                },
+                "days_unsold": {
+                    "type": "integer"
+                },
+                "last_receipt": {
+                    "type": "string"
+                },
+                "last_sale": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ExpiringStockLot": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "branch_id": {
+                    "type": "string"
+                },
+                "branch_name": {
+                    "type": "string"
+                },
+                "cost": {
+                    "type": "number"
+                },
+                "days_left": {
+                    "type": "integer"
+                },
+                "expired": {
+                    "type": "boolean"
+                },
+                "expiry_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lot_number": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "total_cost": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.ExpiringStockLotResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "stock_lots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExpiringStockLot"
+                    }
+                },
+                "total_cost": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.ExportJob": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "download_url": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lang": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "rows": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.GetCategoryBreadcrumbsResponse": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Category"
+                    }
+                }
+            }
+        },
+        "models.GetCategoryTreeResponse": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CategoryNode"
+                    }
+                }
+            }
+        },
+        "models.GetListBranchPriceResponse": {
+            "type": "object",
+            "properties": {
+                "branch_prices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.BranchPrice"
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.GetListBrandResponse": {
+            "type": "object",
+            "properties": {
+                "brands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Brand"
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.GetListCustomerResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "customers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Customer"
+                    }
+                }
+            }
+        },
+        "models.GetListExportJobResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "export_jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ExportJob"
+                    }
+                }
+            }
+        },
+        "models.GetListGiftCardResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "gift_cards": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GiftCard"
+                    }
+                }
+            }
+        },
+        "models.GetListInventoryCountResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "inventory_counts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InventoryCount"
+                    }
+                }
+            }
+        },
+        "models.GetListLoyaltyEarnRateResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "earn_rates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LoyaltyEarnRate"
+                    }
+                }
+            }
+        },
+        "models.GetListLoyaltyTierResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "loyalty_tiers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LoyaltyTier"
+                    }
+                }
+            }
+        },
+        "models.GetListPriceChangeResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "price_changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriceChange"
+                    }
+                }
+            }
+        },
+        "models.GetListProductClassResponse": {
+            "type": "object",
+            "properties": {
+                "classes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductClass"
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "matrix": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductClassCount"
+                    }
+                },
+                "run": {
+                    "$ref": "#/definitions/models.ProductClassRun"
+                }
+            }
+        },
+        "models.GetListProductClassRunResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "runs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductClassRun"
+                    }
+                }
+            }
+        },
+        "models.GetListProductImportResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "product_imports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductImport"
+                    }
+                }
+            }
+        },
+        "models.GetListProductImportRowResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductImportRow"
+                    }
+                }
+            }
+        },
+        "models.GetListPromotionResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "promotions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Promotion"
+                    }
+                }
+            }
+        },
+        "models.GetListPurchaseOrderResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "purchase_orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PurchaseOrder"
+                    }
+                }
+            }
+        },
+        "models.GetListSaleProductResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "sale_products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SaleProduct"
+                    }
+                }
+            }
+        },
+        "models.GetListStockLevelResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "stock_levels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StockLevel"
+                    }
+                }
+            }
+        },
+        "models.GetListStockLotResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "stock_lots": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StockLot"
+                    }
+                }
+            }
+        },
+        "models.GetListStockMovementResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "stock_movements": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StockMovement"
+                    }
+                }
+            }
+        },
+        "models.GetListSupplierInvoiceResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "supplier_invoices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SupplierInvoice"
+                    }
+                }
+            }
+        },
+        "models.GetListSupplierReturnResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "supplier_returns": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SupplierReturn"
+                    }
+                }
+            }
+        },
+        "models.GetListTransferResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "transfers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Transfer"
+                    }
+                }
+            }
+        },
+        "models.GetListWriteOffResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "write_offs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WriteOff"
+                    }
+                }
+            }
+        },
+        "models.GiftCard": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "number"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "string"
+                },
+                "expired": {
+                    "type": "boolean"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "initial_amount": {
+                    "type": "number"
+                },
+                "sale_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.GiftCardHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GiftCardTransaction"
+                    }
+                }
+            }
+        },
+        "models.GiftCardLiability": {
+            "type": "object",
+            "properties": {
+                "cards": {
+                    "type": "integer"
+                },
+                "expired": {
+                    "type": "number"
+                },
+                "issued": {
+                    "type": "number"
+                },
+                "outstanding": {
+                    "type": "number"
+                },
+                "redeemed": {
+                    "type": "number"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.GiftCardLiabilityResponse": {
+            "type": "object",
+            "properties": {
+                "outstanding": {
+                    "type": "number"
+                },
+                "types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.GiftCardLiability"
+                    }
+                }
+            }
+        },
+        "models.GiftCardTransaction": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "balance_after": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "gift_card_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "sale_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.HeatmapCell": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "branch_name": {
+                    "type": "string"
+                },
+                "hour": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "type": "number"
+                },
+                "sale_point_id": {
+                    "type": "string"
+                },
+                "sale_point_name": {
+                    "type": "string"
+                },
+                "sales": {
+                    "type": "integer"
+                },
+                "weekday": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.HeatmapResponse": {
+            "type": "object",
+            "properties": {
+                "cells": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.HeatmapCell"
+                    }
+                }
+            }
+        },
+        "models.InTransit": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "branch_name": {
+                    "type": "string"
+                },
+                "incoming_cost": {
+                    "type": "number"
+                },
+                "incoming_quantity": {
+                    "type": "integer"
+                },
+                "outgoing_cost": {
+                    "type": "number"
+                },
+                "outgoing_quantity": {
+                    "type": "integer"
+                },
+                "transfers": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.InTransitResponse": {
+            "type": "object",
+            "properties": {
+                "branches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InTransit"
+                    }
+                }
+            }
+        },
+        "models.Income": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "date_time": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "purchase_order_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "supplier_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.IncomeProduct": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expiry_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "income_id": {
+                    "type": "string"
+                },
+                "income_price": {
+                    "type": "number"
+                },
+                "lot_number": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "purchase_order_product_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.InventoryCount": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "posted_at": {
+                    "type": "string"
+                },
+                "posted_by": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InventoryCountProduct"
+                    }
+                },
+                "snapshot_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "summary": {
+                    "$ref": "#/definitions/models.InventoryCountSummary"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.InventoryCountProduct": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "book_quantity": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "cost": {
+                    "type": "number"
+                },
+                "cost_impact": {
+                    "type": "number"
+                },
+                "counted": {
+                    "type": "boolean"
+                },
+                "counted_quantity": {
+                    "type": "integer"
+                },
+                "expected_quantity": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "inventory_count_id": {
+                    "type": "string"
+                },
+                "moved_quantity": {
+                    "type": "integer"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "variance": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.InventoryCountSummary": {
+            "type": "object",
+            "properties": {
+                "counted_lines": {
+                    "type": "integer"
+                },
+                "lines": {
+                    "type": "integer"
+                },
+                "net": {
+                    "type": "number"
+                },
+                "shortage_cost": {
+                    "type": "number"
+                },
+                "shortage_quantity": {
+                    "type": "integer"
+                },
+                "surplus_cost": {
+                    "type": "number"
+                },
+                "surplus_quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.InventoryValuation": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "branch_name": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "cost_value": {
+                    "type": "number"
+                },
+                "margin_percent": {
+                    "type": "number"
+                },
+                "potential_margin": {
+                    "type": "number"
+                },
+                "products": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "retail_value": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.InventoryValuationResponse": {
+            "type": "object",
+            "properties": {
+                "as_of": {
+                    "type": "string"
+                },
+                "cost_value": {
+                    "type": "number"
+                },
+                "potential_margin": {
+                    "type": "number"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "retail_value": {
+                    "type": "number"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.InventoryValuation"
+                    }
+                }
+            }
+        },
+        "models.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "login": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.User"
+                }
+            }
+        },
+        "models.LowStock": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "branch_id": {
+                    "type": "string"
+                },
+                "branch_name": {
+                    "type": "string"
+                },
+                "max_quantity": {
+                    "type": "integer"
+                },
+                "min_quantity": {
+                    "type": "integer"
+                },
+                "on_hand": {
+                    "type": "integer"
+                },
+                "on_order": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LowStockResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LowStock"
+                    }
+                }
+            }
+        },
+        "models.LoyaltyEarnRate": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "string"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "percent": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LoyaltyHistoryResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.LoyaltyTransaction"
+                    }
+                }
+            }
+        },
+        "models.LoyaltyTier": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "earn_multiplier": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "min_spent": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.LoyaltyTransaction": {
+            "type": "object",
+            "properties": {
+                "balance_after": {
+                    "type": "number"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "number"
+                },
+                "sale_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MoveCategory": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "parent_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Payment": {
+            "type": "object",
+            "properties": {
+                "apelsin": {
+                    "type": "number"
+                },
+                "cash": {
+                    "type": "number"
+                },
+                "click": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "gift_card": {
+                    "type": "number"
+                },
+                "gift_card_code": {
+                    "type": "string"
+                },
+                "humo": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "payme": {
+                    "type": "number"
+                },
+                "points": {
+                    "type": "number"
+                },
+                "sale_id": {
+                    "type": "string"
+                },
+                "total_amount": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "uzcard": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.PriceChange": {
+            "type": "object",
+            "properties": {
+                "approved_at": {
+                    "type": "string"
+                },
+                "approved_by": {
+                    "type": "string"
+                },
+                "branch_id": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriceChangeProduct"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PriceChangeProduct": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "new_price": {
+                    "type": "number"
+                },
+                "old_price": {
+                    "type": "number"
+                },
+                "price_change_id": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Product": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "brand_id": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "photo": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProductClass": {
+            "type": "object",
+            "properties": {
+                "abc": {
+                    "type": "string"
+                },
+                "barcode": {
+                    "type": "string"
+                },
+                "branch_id": {
+                    "type": "string"
+                },
+                "branch_name": {
+                    "type": "string"
+                },
+                "cumulative_share": {
+                    "type": "number"
+                },
+                "cv": {
+                    "type": "number"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "revenue": {
+                    "type": "number"
+                },
+                "run_date": {
+                    "type": "string"
+                },
+                "run_id": {
+                    "type": "string"
+                },
+                "share": {
+                    "type": "number"
+                },
+                "units": {
+                    "type": "integer"
+                },
+                "weekly_mean": {
+                    "type": "number"
+                },
+                "xyz": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProductClassCount": {
+            "type": "object",
+            "properties": {
+                "abc": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "integer"
+                },
+                "xyz": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProductClassRun": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "from_date": {
+                    "type": "string"
+                },
+                "history_weeks": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "integer"
+                },
+                "run_date": {
+                    "type": "string"
+                },
+                "to_date": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProductImport": {
+            "type": "object",
+            "properties": {
+                "brands_created": {
+                    "type": "integer"
+                },
+                "categories_created": {
+                    "type": "integer"
+                },
+                "created": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "format": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "imported_rows": {
+                    "type": "integer"
+                },
+                "invalid_rows": {
+                    "type": "integer"
+                },
+                "new_brands": {
+                    "type": "integer"
+                },
+                "new_categories": {
+                    "type": "integer"
+                },
+                "preview": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProductImportRow"
+                    }
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "to_create": {
+                    "type": "integer"
+                },
+                "to_update": {
+                    "type": "integer"
+                },
+                "total_rows": {
+                    "type": "integer"
+                },
+                "updated": {
+                    "type": "integer"
+                },
+                "valid_rows": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ProductImportRow": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "brand": {
+                    "type": "string"
+                },
+                "category": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Promotion": {
+            "type": "object",
+            "properties": {
+                "branch_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "brand_id": {
+                    "type": "string"
+                },
+                "bundle_price": {
+                    "type": "number"
+                },
+                "buy_quantity": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "get_quantity": {
+                    "type": "integer"
+                },
+                "happy_hour_from": {
+                    "type": "string"
+                },
+                "happy_hour_to": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PromotionItem"
+                    }
+                },
+                "min_basket_amount": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "percent": {
+                    "type": "number"
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "stackable": {
+                    "type": "boolean"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PromotionItem": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PromotionReport": {
+            "type": "object",
+            "properties": {
+                "discount_amount": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "promotion_id": {
+                    "type": "string"
+                },
+                "revenue": {
+                    "type": "number"
+                },
+                "sales_count": {
+                    "type": "integer"
+                },
+                "units": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PromotionReportResponse": {
+            "type": "object",
+            "properties": {
+                "promotions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PromotionReport"
+                    }
+                }
+            }
+        },
+        "models.PurchaseOrder": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "closed_at": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "expected_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PurchaseOrderProduct"
+                    }
+                },
+                "received_at": {
+                    "type": "string"
+                },
+                "sent_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "supplier_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PurchaseOrderProduct": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivery_status": {
+                    "type": "string"
+                },
+                "difference": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "purchase_order_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "received_quantity": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReceivePurchaseOrder": {
+            "type": "object",
+            "properties": {
+                "date_time": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReceivePurchaseOrderProduct"
+                    }
+                }
+            }
+        },
+        "models.ReceivePurchaseOrderProduct": {
+            "type": "object",
+            "properties": {
+                "expiry_date": {
+                    "type": "string"
+                },
+                "lot_number": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "purchase_order_product_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ReceivePurchaseOrderResponse": {
+            "type": "object",
+            "properties": {
+                "income": {
+                    "$ref": "#/definitions/models.Income"
+                },
+                "purchase_order": {
+                    "$ref": "#/definitions/models.PurchaseOrder"
+                }
+            }
+        },
+        "models.ReceiveTransfer": {
+            "type": "object",
+            "properties": {
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReceiveTransferProduct"
+                    }
+                }
+            }
+        },
+        "models.ReceiveTransferProduct": {
+            "type": "object",
+            "properties": {
+                "discrepancy_comment": {
+                    "type": "string"
+                },
+                "received_quantity": {
+                    "type": "integer"
+                },
+                "transfer_product_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Remainder": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "branch_id": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "price_income": {
+                    "type": "number"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RepeatCustomerReport": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "branch_name": {
+                    "type": "string"
+                },
+                "customer_sales": {
+                    "type": "integer"
+                },
+                "customers": {
+                    "type": "integer"
+                },
+                "repeat_customers": {
+                    "type": "integer"
+                },
+                "repeat_rate": {
+                    "type": "number"
+                },
+                "sales": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.RepeatCustomerReportResponse": {
+            "type": "object",
+            "properties": {
+                "branches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.RepeatCustomerReport"
+                    }
+                }
+            }
+        },
+        "models.ReplenishmentLine": {
+            "type": "object",
+            "properties": {
+                "avg_daily_sales": {
+                    "type": "number"
+                },
+                "barcode": {
+                    "type": "string"
+                },
+                "max_quantity": {
+                    "type": "integer"
+                },
+                "min_quantity": {
+                    "type": "integer"
+                },
+                "on_hand": {
+                    "type": "integer"
+                },
+                "on_order": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "reorder_point": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ReplenishmentResponse": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "suppliers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReplenishmentSupplier"
+                    }
+                }
+            }
+        },
+        "models.ReplenishmentSupplier": {
+            "type": "object",
+            "properties": {
+                "cost": {
+                    "type": "number"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ReplenishmentLine"
+                    }
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "supplier_id": {
+                    "type": "string"
+                },
+                "supplier_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ResolveSupplierInvoiceLine": {
+            "type": "object",
+            "properties": {
+                "product_id": {
+                    "type": "string"
+                },
+                "remember": {
+                    "type": "boolean"
+                },
+                "skip": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.ResolvedPrice": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "branch_id": {
+                    "type": "string"
+                },
+                "branch_price_id": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReturnSale": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RunProductClass": {
+            "type": "object",
+            "properties": {
+                "history_weeks": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Sale": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "branch_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "customer_id": {
+                    "type": "string"
+                },
+                "employee_id": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "parent_id": {
+                "sale_id": {
                     "type": "string"
                 },
-                "title": {
+                "salepoint_id": {
+                    "type": "string"
+                },
+                "shift_id": {
+                    "type": "string"
+                },
+                "status": {
                     "type": "string"
                 },
                 "updated_at": {
@@ -5214,153 +17866,389 @@ const docTemplate = `{
                 }
             }
         },
-        "models.CreateBranch": {
+        "models.SalePoint": {
             "type": "object",
             "properties": {
-                "address": {
+                "branch_id": {
                     "type": "string"
                 },
-                "branch_code": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
                 },
-                "phone": {
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "models.CreateCategory": {
+        "models.SaleProduct": {
             "type": "object",
             "properties": {
-                "parent_id": {
+                "allow_discount": {
+                    "type": "boolean"
+                },
+                "barcode": {
                     "type": "string"
                 },
-                "title": {
+                "category_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "discount": {
+                    "type": "number"
+                },
+                "discount_type": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "remaining_quantity": {
+                    "type": "integer"
+                },
+                "sale_id": {
+                    "type": "string"
+                },
+                "total_amount": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SaleReturn": {
+            "type": "object",
+            "properties": {
+                "sale": {
+                    "$ref": "#/definitions/models.Sale"
+                },
+                "store_credit": {
+                    "$ref": "#/definitions/models.GiftCard"
+                }
+            }
+        },
+        "models.SalesChange": {
+            "type": "object",
+            "properties": {
+                "avg_basket": {
+                    "type": "number"
+                },
+                "gross_margin": {
+                    "type": "number"
+                },
+                "revenue": {
+                    "type": "number"
+                },
+                "sales": {
+                    "type": "number"
+                },
+                "units": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.SalesMetrics": {
+            "type": "object",
+            "properties": {
+                "avg_basket": {
+                    "type": "number"
+                },
+                "cost": {
+                    "type": "number"
+                },
+                "gross_margin": {
+                    "type": "number"
+                },
+                "margin_percent": {
+                    "type": "number"
+                },
+                "revenue": {
+                    "type": "number"
+                },
+                "sales": {
+                    "type": "integer"
+                },
+                "units": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SalesReportResponse": {
+            "type": "object",
+            "properties": {
+                "change": {
+                    "$ref": "#/definitions/models.SalesChange"
+                },
+                "from_date": {
+                    "type": "string"
+                },
+                "prev_from_date": {
+                    "type": "string"
+                },
+                "previous": {
+                    "$ref": "#/definitions/models.SalesMetrics"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SalesReportRow"
+                    }
+                },
+                "to_date": {
                     "type": "string"
+                },
+                "total": {
+                    "$ref": "#/definitions/models.SalesMetrics"
                 }
             }
         },
-        "models.CreateEmployee": {
+        "models.SalesReportRow": {
             "type": "object",
             "properties": {
                 "branch_id": {
                     "type": "string"
                 },
-                "first_name": {
+                "branch_name": {
                     "type": "string"
                 },
-                "last_name": {
+                "brand_id": {
                     "type": "string"
                 },
-                "login": {
+                "brand_name": {
                     "type": "string"
                 },
-                "password": {
+                "cashier_id": {
                     "type": "string"
                 },
-                "phone": {
+                "cashier_name": {
                     "type": "string"
                 },
-                "salepoint_id": {
+                "category_id": {
+                    "type": "string"
+                },
+                "category_name": {
+                    "type": "string"
+                },
+                "change": {
+                    "$ref": "#/definitions/models.SalesChange"
+                },
+                "metrics": {
+                    "$ref": "#/definitions/models.SalesMetrics"
+                },
+                "period": {
                     "type": "string"
                 },
-                "user_type": {
+                "previous": {
+                    "$ref": "#/definitions/models.SalesMetrics"
+                },
+                "sale_point_id": {
+                    "type": "string"
+                },
+                "sale_point_name": {
                     "type": "string"
                 }
             }
         },
-        "models.CreateIncome": {
+        "models.ScanInventoryCount": {
+            "type": "object",
+            "properties": {
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ScanInventoryCountProduct"
+                    }
+                }
+            }
+        },
+        "models.ScanInventoryCountProduct": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SearchProductResponse": {
+            "type": "object",
+            "properties": {
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Product"
+                    }
+                }
+            }
+        },
+        "models.SellGiftCard": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "customer_id": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "sale_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SetLoyaltyEarnRate": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "string"
+                },
+                "percent": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.Shift": {
             "type": "object",
             "properties": {
                 "branch_id": {
                     "type": "string"
                 },
-                "date_time": {
+                "close_shift": {
+                    "type": "string"
+                },
+                "counted_cash": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "open_shift": {
+                    "type": "string"
+                },
+                "opening_cash": {
+                    "type": "number"
+                },
+                "sale_point_id": {
                     "type": "string"
                 },
                 "status": {
                     "type": "string"
                 },
-                "supplier_id": {
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "models.CreateIncomeProduct": {
+        "models.Shrinkage": {
             "type": "object",
             "properties": {
-                "barcode": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "branch_name": {
                     "type": "string"
                 },
                 "category_id": {
                     "type": "string"
                 },
-                "income_id": {
+                "category_name": {
                     "type": "string"
                 },
-                "income_price": {
+                "cost": {
                     "type": "number"
                 },
-                "product_name": {
+                "period": {
                     "type": "string"
                 },
                 "quantity": {
                     "type": "integer"
+                },
+                "reason": {
+                    "type": "string"
                 }
             }
         },
-        "models.CreatePayment": {
+        "models.ShrinkageByReason": {
             "type": "object",
             "properties": {
-                "apelsin": {
-                    "type": "number"
-                },
-                "cash": {
-                    "type": "number"
-                },
-                "click": {
-                    "type": "number"
-                },
-                "humo": {
+                "cost": {
                     "type": "number"
                 },
-                "payme": {
-                    "type": "number"
+                "quantity": {
+                    "type": "integer"
                 },
-                "sale_id": {
+                "reason": {
                     "type": "string"
+                }
+            }
+        },
+        "models.ShrinkageReportResponse": {
+            "type": "object",
+            "properties": {
+                "by_reason": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ShrinkageByReason"
+                    }
                 },
-                "total_amount": {
-                    "type": "number"
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Shrinkage"
+                    }
                 },
-                "uzcard": {
+                "total_cost": {
                     "type": "number"
                 }
             }
         },
-        "models.CreateProduct": {
+        "models.StockAgingResponse": {
             "type": "object",
             "properties": {
-                "barcode": {
-                    "type": "string"
-                },
-                "category_id": {
-                    "type": "string"
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AgingBucket"
+                    }
                 },
-                "photo": {
-                    "type": "string"
+                "dead_stock": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DeadStock"
+                    }
                 },
-                "price": {
+                "dead_stock_cost": {
                     "type": "number"
-                },
-                "title": {
-                    "type": "string"
                 }
             }
         },
-        "models.CreateRemainder": {
+        "models.StockLevel": {
             "type": "object",
             "properties": {
                 "barcode": {
@@ -5369,21 +18257,30 @@ const docTemplate = `{
                 "branch_id": {
                     "type": "string"
                 },
-                "category_id": {
+                "created_at": {
                     "type": "string"
                 },
-                "price_income": {
-                    "type": "number"
+                "id": {
+                    "type": "string"
+                },
+                "max_quantity": {
+                    "type": "integer"
+                },
+                "min_quantity": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "string"
                 },
                 "product_name": {
                     "type": "string"
                 },
-                "quantity": {
-                    "type": "integer"
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "models.CreateSale": {
+        "models.StockLot": {
             "type": "object",
             "properties": {
                 "barcode": {
@@ -5392,54 +18289,55 @@ const docTemplate = `{
                 "branch_id": {
                     "type": "string"
                 },
-                "employee_id": {
-                    "type": "string"
+                "cost": {
+                    "type": "number"
                 },
-                "sale_id": {
+                "created_at": {
                     "type": "string"
                 },
-                "salepoint_id": {
+                "expiry_date": {
                     "type": "string"
                 },
-                "shift_id": {
+                "id": {
                     "type": "string"
                 },
-                "status": {
+                "lot_number": {
                     "type": "string"
-                }
-            }
-        },
-        "models.CreateSalePoint": {
-            "type": "object",
-            "properties": {
-                "branch_id": {
+                },
+                "product_name": {
                     "type": "string"
                 },
-                "name": {
+                "quantity": {
+                    "type": "integer"
+                },
+                "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "models.CreateSaleProduct": {
+        "models.StockMovement": {
             "type": "object",
             "properties": {
-                "allow_discount": {
-                    "type": "boolean"
+                "balance_after": {
+                    "type": "integer"
                 },
                 "barcode": {
                     "type": "string"
                 },
-                "category_id": {
+                "branch_id": {
                     "type": "string"
                 },
-                "discount": {
+                "cost": {
                     "type": "number"
                 },
-                "discount_type": {
+                "created_at": {
                     "type": "string"
                 },
-                "price": {
-                    "type": "number"
+                "document_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
                 },
                 "product_name": {
                     "type": "string"
@@ -5447,43 +18345,20 @@ const docTemplate = `{
                 "quantity": {
                     "type": "integer"
                 },
-                "remaining_quantity": {
-                    "type": "integer"
-                },
-                "sale_id": {
+                "type": {
                     "type": "string"
-                },
-                "total_amount": {
-                    "type": "number"
                 }
             }
         },
-        "models.CreateShift": {
+        "models.Supplier": {
             "type": "object",
             "properties": {
-                "branch_id": {
-                    "type": "string"
-                },
-                "close_shift": {
-                    "type": "string"
-                },
-                "employee_id": {
-                    "type": "string"
-                },
-                "open_shift": {
+                "created_at": {
                     "type": "string"
                 },
-                "sale_point_id": {
+                "id": {
                     "type": "string"
                 },
-                "status": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.CreateSupplier": {
-            "type": "object",
-            "properties": {
                 "is_active": {
                     "type": "boolean"
                 },
@@ -5492,114 +18367,102 @@ const docTemplate = `{
                 },
                 "phone_number": {
                     "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "models.CreateTransaction": {
+        "models.SupplierAging": {
             "type": "object",
             "properties": {
-                "apelsin": {
+                "advance": {
                     "type": "number"
                 },
-                "cash": {
+                "current": {
                     "type": "number"
                 },
-                "click": {
+                "days_31_60": {
                     "type": "number"
                 },
-                "humo": {
+                "days_61_90": {
                     "type": "number"
                 },
-                "payme": {
+                "over_90": {
                     "type": "number"
                 },
-                "sale_id": {
+                "supplier_id": {
                     "type": "string"
                 },
-                "total_amount": {
-                    "type": "number"
+                "supplier_name": {
+                    "type": "string"
                 },
-                "uzcard": {
+                "total": {
                     "type": "number"
                 }
             }
         },
-        "models.CreateUser": {
+        "models.SupplierAgingResponse": {
             "type": "object",
             "properties": {
-                "active": {
-                    "type": "boolean"
-                },
-                "client_type": {
-                    "type": "string"
-                },
-                "first_name": {
-                    "type": "string"
-                },
-                "last_name": {
-                    "type": "string"
-                },
-                "login": {
-                    "type": "string"
+                "suppliers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SupplierAging"
+                    }
                 },
-                "password": {
-                    "type": "string"
+                "total": {
+                    "$ref": "#/definitions/models.SupplierAging"
                 }
             }
         },
-        "models.Employee": {
+        "models.SupplierFillRate": {
             "type": "object",
             "properties": {
-                "branch_id": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
+                "fill_rate": {
+                    "type": "number"
                 },
-                "first_name": {
-                    "type": "string"
+                "line_fill_rate": {
+                    "type": "number"
                 },
-                "id": {
-                    "type": "string"
+                "on_time_rate": {
+                    "type": "number"
                 },
-                "last_name": {
-                    "type": "string"
+                "ordered_quantity": {
+                    "type": "integer"
                 },
-                "login": {
-                    "type": "string"
+                "orders": {
+                    "type": "integer"
                 },
-                "password": {
-                    "type": "string"
+                "over_quantity": {
+                    "type": "integer"
                 },
-                "phone": {
-                    "type": "string"
+                "received_quantity": {
+                    "type": "integer"
                 },
-                "salepoint_id": {
-                    "type": "string"
+                "short_quantity": {
+                    "type": "integer"
                 },
-                "updated_at": {
+                "supplier_id": {
                     "type": "string"
                 },
-                "user_type": {
+                "supplier_name": {
                     "type": "string"
                 }
             }
         },
-        "models.GetListSaleProductResponse": {
+        "models.SupplierFillRateResponse": {
             "type": "object",
             "properties": {
-                "count": {
-                    "type": "integer"
-                },
-                "sale_products": {
+                "suppliers": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/models.SaleProduct"
+                        "$ref": "#/definitions/models.SupplierFillRate"
                     }
                 }
             }
         },
-        "models.Income": {
+        "models.SupplierInvoice": {
             "type": "object",
             "properties": {
                 "branch_id": {
@@ -5608,116 +18471,166 @@ const docTemplate = `{
                 "created_at": {
                     "type": "string"
                 },
-                "date_time": {
+                "created_by": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "format": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "status": {
+                "income_id": {
                     "type": "string"
                 },
-                "supplier_id": {
+                "income_status": {
                     "type": "string"
                 },
-                "updated_at": {
+                "invoice_date": {
+                    "type": "string"
+                },
+                "invoice_lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SupplierInvoiceLine"
+                    }
+                },
+                "lines": {
+                    "type": "integer"
+                },
+                "matched": {
+                    "type": "integer"
+                },
+                "number": {
+                    "type": "string"
+                },
+                "seller_name": {
                     "type": "string"
+                },
+                "seller_tin": {
+                    "type": "string"
+                },
+                "supplier_id": {
+                    "type": "string"
+                },
+                "total_amount": {
+                    "type": "number"
+                },
+                "unresolved": {
+                    "type": "integer"
+                },
+                "with_issues": {
+                    "type": "integer"
                 }
             }
         },
-        "models.IncomeProduct": {
+        "models.SupplierInvoiceLine": {
             "type": "object",
             "properties": {
+                "amount": {
+                    "type": "number"
+                },
                 "barcode": {
                     "type": "string"
                 },
-                "category_id": {
+                "catalog_code": {
                     "type": "string"
                 },
-                "created_at": {
+                "income_price": {
+                    "type": "number"
+                },
+                "income_product_id": {
                     "type": "string"
                 },
-                "id": {
+                "issues": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "line": {
+                    "type": "integer"
+                },
+                "matched_by": {
                     "type": "string"
                 },
-                "income_id": {
+                "name": {
                     "type": "string"
                 },
-                "income_price": {
+                "price": {
                     "type": "number"
                 },
+                "product_id": {
+                    "type": "string"
+                },
                 "product_name": {
                     "type": "string"
                 },
                 "quantity": {
-                    "type": "integer"
-                },
-                "updated_at": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.LoginRequest": {
-            "type": "object",
-            "properties": {
-                "login": {
-                    "type": "string"
+                    "type": "number"
                 },
-                "password": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.LoginResponse": {
-            "type": "object",
-            "properties": {
-                "access_token": {
+                "status": {
                     "type": "string"
                 },
-                "user": {
-                    "$ref": "#/definitions/models.User"
+                "supplier_code": {
+                    "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                },
+                "vat_rate": {
+                    "type": "number"
                 }
             }
         },
-        "models.Payment": {
+        "models.SupplierReturn": {
             "type": "object",
             "properties": {
-                "apelsin": {
-                    "type": "number"
-                },
-                "cash": {
-                    "type": "number"
+                "branch_id": {
+                    "type": "string"
                 },
-                "click": {
-                    "type": "number"
+                "comment": {
+                    "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "humo": {
-                    "type": "number"
+                "created_by": {
+                    "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "payme": {
-                    "type": "number"
+                "income_id": {
+                    "type": "string"
                 },
-                "sale_id": {
+                "posted_at": {
                     "type": "string"
                 },
-                "total_amount": {
-                    "type": "number"
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SupplierReturnProduct"
+                    }
                 },
-                "updated_at": {
+                "status": {
                     "type": "string"
                 },
-                "uzcard": {
+                "supplier_id": {
+                    "type": "string"
+                },
+                "total_cost": {
                     "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
-        "models.Product": {
+        "models.SupplierReturnProduct": {
             "type": "object",
             "properties": {
                 "barcode": {
@@ -5726,150 +18639,194 @@ const docTemplate = `{
                 "category_id": {
                     "type": "string"
                 },
+                "cost": {
+                    "type": "number"
+                },
                 "created_at": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "photo": {
+                "product_name": {
                     "type": "string"
                 },
-                "price": {
-                    "type": "number"
+                "quantity": {
+                    "type": "integer"
                 },
-                "title": {
+                "supplier_return_id": {
                     "type": "string"
                 },
+                "total_cost": {
+                    "type": "number"
+                },
                 "updated_at": {
                     "type": "string"
                 }
             }
         },
-        "models.Remainder": {
+        "models.SupplierStatement": {
             "type": "object",
             "properties": {
-                "barcode": {
-                    "type": "string"
+                "aging": {
+                    "$ref": "#/definitions/models.SupplierAging"
                 },
-                "branch_id": {
-                    "type": "string"
+                "closing_balance": {
+                    "type": "number"
                 },
-                "category_id": {
+                "from_date": {
                     "type": "string"
                 },
-                "created_at": {
-                    "type": "string"
+                "invoiced": {
+                    "type": "number"
                 },
-                "id": {
-                    "type": "string"
+                "opening_balance": {
+                    "type": "number"
                 },
-                "price_income": {
+                "paid": {
                     "type": "number"
                 },
-                "product_name": {
+                "returned": {
+                    "type": "number"
+                },
+                "supplier_id": {
                     "type": "string"
                 },
-                "quantity": {
-                    "type": "integer"
+                "supplier_name": {
+                    "type": "string"
                 },
-                "updated_at": {
+                "to_date": {
                     "type": "string"
+                },
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SupplierTransaction"
+                    }
                 }
             }
         },
-        "models.Sale": {
+        "models.SupplierTransaction": {
             "type": "object",
             "properties": {
-                "barcode": {
-                    "type": "string"
+                "amount": {
+                    "type": "number"
+                },
+                "balance": {
+                    "type": "number"
                 },
                 "branch_id": {
                     "type": "string"
                 },
+                "comment": {
+                    "type": "string"
+                },
                 "created_at": {
                     "type": "string"
                 },
-                "employee_id": {
+                "created_by": {
+                    "type": "string"
+                },
+                "date_time": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "sale_id": {
+                "income_id": {
                     "type": "string"
                 },
-                "salepoint_id": {
+                "method": {
                     "type": "string"
                 },
-                "shift_id": {
+                "supplier_id": {
                     "type": "string"
                 },
-                "status": {
+                "supplier_return_id": {
                     "type": "string"
                 },
-                "updated_at": {
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "models.SalePoint": {
+        "models.TopProduct": {
             "type": "object",
             "properties": {
-                "branch_id": {
+                "barcode": {
                     "type": "string"
                 },
-                "created_at": {
-                    "type": "string"
+                "cost": {
+                    "type": "number"
                 },
-                "id": {
-                    "type": "string"
+                "gross_margin": {
+                    "type": "number"
                 },
-                "name": {
+                "margin_percent": {
+                    "type": "number"
+                },
+                "period": {
                     "type": "string"
                 },
-                "updated_at": {
+                "product_name": {
                     "type": "string"
+                },
+                "rank": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "type": "number"
+                },
+                "units": {
+                    "type": "integer"
                 }
             }
         },
-        "models.SaleProduct": {
+        "models.TopProductsResponse": {
             "type": "object",
             "properties": {
-                "allow_discount": {
-                    "type": "boolean"
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TopProduct"
+                    }
+                }
+            }
+        },
+        "models.Transaction": {
+            "type": "object",
+            "properties": {
+                "apelsin": {
+                    "type": "number"
                 },
-                "barcode": {
-                    "type": "string"
+                "cash": {
+                    "type": "number"
                 },
-                "category_id": {
-                    "type": "string"
+                "click": {
+                    "type": "number"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "discount": {
+                "gift_card": {
                     "type": "number"
                 },
-                "discount_type": {
-                    "type": "string"
-                },
-                "id": {
-                    "type": "string"
+                "gift_card_sold": {
+                    "type": "number"
                 },
-                "price": {
+                "humo": {
                     "type": "number"
                 },
-                "product_name": {
+                "id": {
                     "type": "string"
                 },
-                "quantity": {
-                    "type": "integer"
+                "payme": {
+                    "type": "number"
                 },
-                "remaining_quantity": {
-                    "type": "integer"
+                "points": {
+                    "type": "number"
                 },
-                "sale_id": {
+                "shift_id": {
                     "type": "string"
                 },
                 "total_amount": {
@@ -5877,57 +18834,49 @@ const docTemplate = `{
                 },
                 "updated_at": {
                     "type": "string"
+                },
+                "uzcard": {
+                    "type": "number"
                 }
             }
         },
-        "models.Shift": {
+        "models.Transfer": {
             "type": "object",
             "properties": {
-                "branch_id": {
-                    "type": "string"
-                },
-                "close_shift": {
+                "comment": {
                     "type": "string"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "employee_id": {
+                "created_by": {
                     "type": "string"
                 },
-                "id": {
+                "dispatched_at": {
                     "type": "string"
                 },
-                "open_shift": {
+                "from_branch_id": {
                     "type": "string"
                 },
-                "sale_point_id": {
+                "id": {
                     "type": "string"
                 },
-                "status": {
-                    "type": "string"
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TransferProduct"
+                    }
                 },
-                "updated_at": {
-                    "type": "string"
-                }
-            }
-        },
-        "models.Supplier": {
-            "type": "object",
-            "properties": {
-                "created_at": {
+                "received_at": {
                     "type": "string"
                 },
-                "id": {
+                "received_by": {
                     "type": "string"
                 },
-                "is_active": {
-                    "type": "boolean"
-                },
-                "name": {
+                "status": {
                     "type": "string"
                 },
-                "phone_number": {
+                "to_branch_id": {
                     "type": "string"
                 },
                 "updated_at": {
@@ -5935,41 +18884,44 @@ const docTemplate = `{
                 }
             }
         },
-        "models.Transaction": {
+        "models.TransferProduct": {
             "type": "object",
             "properties": {
-                "apelsin": {
-                    "type": "number"
+                "barcode": {
+                    "type": "string"
                 },
-                "cash": {
-                    "type": "number"
+                "category_id": {
+                    "type": "string"
                 },
-                "click": {
+                "cost": {
                     "type": "number"
                 },
                 "created_at": {
                     "type": "string"
                 },
-                "humo": {
-                    "type": "number"
+                "discrepancy_comment": {
+                    "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "payme": {
-                    "type": "number"
-                },
-                "shift_id": {
+                "product_name": {
                     "type": "string"
                 },
-                "total_amount": {
-                    "type": "number"
+                "quantity": {
+                    "type": "integer"
                 },
-                "updated_at": {
+                "received_quantity": {
+                    "type": "integer"
+                },
+                "shortage": {
+                    "type": "integer"
+                },
+                "transfer_id": {
                     "type": "string"
                 },
-                "uzcard": {
-                    "type": "number"
+                "updated_at": {
+                    "type": "string"
                 }
             }
         },
@@ -5993,48 +18945,61 @@ const docTemplate = `{
                 }
             }
         },
-        "models.UpdateCategory": {
+        "models.UpdateBranchPrice": {
             "type": "object",
             "properties": {
-                "id": {
+                "effective_from": {
                     "type": "string"
                 },
-                "parent_id": {
+                "id": {
                     "type": "string"
                 },
-                "title": {
-                    "type": "string"
+                "price": {
+                    "type": "number"
                 }
             }
         },
-        "models.UpdateEmployee": {
+        "models.UpdateBrand": {
             "type": "object",
             "properties": {
-                "branch_id": {
+                "id": {
                     "type": "string"
                 },
-                "first_name": {
+                "name": {
                     "type": "string"
-                },
+                }
+            }
+        },
+        "models.UpdateCategory": {
+            "type": "object",
+            "properties": {
                 "id": {
                     "type": "string"
                 },
-                "last_name": {
+                "parent_id": {
                     "type": "string"
                 },
-                "login": {
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UpdateCustomer": {
+            "type": "object",
+            "properties": {
+                "card_barcode": {
                     "type": "string"
                 },
-                "password": {
+                "first_name": {
                     "type": "string"
                 },
-                "phone": {
+                "id": {
                     "type": "string"
                 },
-                "salepoint_id": {
+                "last_name": {
                     "type": "string"
                 },
-                "user_type": {
+                "phone": {
                     "type": "string"
                 }
             }
@@ -6068,6 +19033,9 @@ const docTemplate = `{
                 "category_id": {
                     "type": "string"
                 },
+                "expiry_date": {
+                    "type": "string"
+                },
                 "id": {
                     "type": "string"
                 },
@@ -6077,6 +19045,9 @@ const docTemplate = `{
                 "income_price": {
                     "type": "number"
                 },
+                "lot_number": {
+                    "type": "string"
+                },
                 "product_name": {
                     "type": "string"
                 },
@@ -6085,6 +19056,23 @@ const docTemplate = `{
                 }
             }
         },
+        "models.UpdateLoyaltyTier": {
+            "type": "object",
+            "properties": {
+                "earn_multiplier": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "min_spent": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
         "models.UpdatePayment": {
             "type": "object",
             "properties": {
@@ -6097,6 +19085,12 @@ const docTemplate = `{
                 "click": {
                     "type": "number"
                 },
+                "gift_card": {
+                    "type": "number"
+                },
+                "gift_card_code": {
+                    "type": "string"
+                },
                 "humo": {
                     "type": "number"
                 },
@@ -6106,6 +19100,9 @@ const docTemplate = `{
                 "payme": {
                     "type": "number"
                 },
+                "points": {
+                    "type": "number"
+                },
                 "total_amount": {
                     "type": "number"
                 },
@@ -6114,12 +19111,35 @@ const docTemplate = `{
                 }
             }
         },
+        "models.UpdatePriceChange": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "effective_from": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CreatePriceChangeProduct"
+                    }
+                }
+            }
+        },
         "models.UpdateProduct": {
             "type": "object",
             "properties": {
                 "barcode": {
                     "type": "string"
                 },
+                "brand_id": {
+                    "type": "string"
+                },
                 "category_id": {
                     "type": "string"
                 },
@@ -6134,6 +19154,103 @@ const docTemplate = `{
                 },
                 "title": {
                     "type": "string"
+                },
+                "unit": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UpdatePromotion": {
+            "type": "object",
+            "properties": {
+                "branch_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "brand_id": {
+                    "type": "string"
+                },
+                "bundle_price": {
+                    "type": "number"
+                },
+                "buy_quantity": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "end_date": {
+                    "type": "string"
+                },
+                "get_quantity": {
+                    "type": "integer"
+                },
+                "happy_hour_from": {
+                    "type": "string"
+                },
+                "happy_hour_to": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_active": {
+                    "type": "boolean"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PromotionItem"
+                    }
+                },
+                "min_basket_amount": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "percent": {
+                    "type": "number"
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "stackable": {
+                    "type": "boolean"
+                },
+                "start_date": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UpdatePurchaseOrder": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "expected_date": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CreatePurchaseOrderProduct"
+                    }
+                },
+                "supplier_id": {
+                    "type": "string"
                 }
             }
         },
@@ -6235,8 +19352,8 @@ const docTemplate = `{
                 "close_shift": {
                     "type": "string"
                 },
-                "employee_id": {
-                    "type": "string"
+                "counted_cash": {
+                    "type": "number"
                 },
                 "id": {
                     "type": "string"
@@ -6244,11 +19361,31 @@ const docTemplate = `{
                 "open_shift": {
                     "type": "string"
                 },
+                "opening_cash": {
+                    "type": "number"
+                },
                 "sale_point_id": {
                     "type": "string"
                 },
                 "status": {
                     "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UpdateStockLevel": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "max_quantity": {
+                    "type": "integer"
+                },
+                "min_quantity": {
+                    "type": "integer"
                 }
             }
         },
@@ -6269,6 +19406,26 @@ const docTemplate = `{
                 }
             }
         },
+        "models.UpdateSupplierReturn": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "income_id": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CreateSupplierReturnProduct"
+                    }
+                }
+            }
+        },
         "models.UpdateTransaction": {
             "type": "object",
             "properties": {
@@ -6281,6 +19438,12 @@ const docTemplate = `{
                 "click": {
                     "type": "number"
                 },
+                "gift_card": {
+                    "type": "number"
+                },
+                "gift_card_sold": {
+                    "type": "number"
+                },
                 "humo": {
                     "type": "number"
                 },
@@ -6290,6 +19453,9 @@ const docTemplate = `{
                 "payme": {
                     "type": "number"
                 },
+                "points": {
+                    "type": "number"
+                },
                 "total_amount": {
                     "type": "number"
                 },
@@ -6298,6 +19464,26 @@ const docTemplate = `{
                 }
             }
         },
+        "models.UpdateTransfer": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CreateTransferProduct"
+                    }
+                },
+                "to_branch_id": {
+                    "type": "string"
+                }
+            }
+        },
         "models.UpdateUser": {
             "type": "object",
             "properties": {
@@ -6324,6 +19510,26 @@ const docTemplate = `{
                 }
             }
         },
+        "models.UpdateWriteOff": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CreateWriteOffProduct"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
         "models.User": {
             "type": "object",
             "properties": {
@@ -6355,6 +19561,88 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "models.WriteOff": {
+            "type": "object",
+            "properties": {
+                "branch_id": {
+                    "type": "string"
+                },
+                "comment": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "posted_at": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WriteOffProduct"
+                    }
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "reviewed_by": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total_cost": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.WriteOffProduct": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "string"
+                },
+                "cost": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "total_cost": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "write_off_id": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
@@ -4,12 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"time"
 
 	"market_system/config"
 	"market_system/models"
+	"market_system/pkg/criteria"
 	"market_system/pkg/helpers"
 
 	"github.com/gin-gonic/gin"
@@ -69,7 +70,7 @@ func (h *Handler) GetByIDbranch(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	ctx, cancel := requestContext(c)
 	defer cancel()
 
 	resp, err := h.strg.Branch().GetByID(ctx, &models.BranchPrimaryKey{Id: id})
@@ -87,13 +88,16 @@ func (h *Handler) GetByIDbranch(c *gin.Context) {
 }
 
 // @Summary Get a list of branch
-// @Description Get a list of branch with optional filtering.
+// @Description Get a list of branch with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, branch_code, name, address, phone, created_at, updated_at.
 // @Tags branch
 // @Accept json
 // @Produce json
 // @Param limit query int false "Number of items to return (default 10)"
 // @Param offset query int false "Number of items to skip (default 0)"
 // @Param search query string false "Search term"
+// @Param created_at[between] query string false "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
 // @Success 200 {array} models.Branch "List of branch"
 // @Failure 400 {object} ErrorResponse "Bad Request"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
@@ -118,7 +122,13 @@ func (h *Handler) GetListbranch(c *gin.Context) {
 		handleResponse(c, http.StatusBadRequest, "invalid query search")
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
 	defer cancel()
 
 	var (
@@ -126,7 +136,7 @@ func (h *Handler) GetListbranch(c *gin.Context) {
 		resp = &models.GetListBranchResponse{}
 	)
 
-	body, err := h.cache.GetX(ctx, key)
+	body, err := h.cachedList(ctx, c, key)
 	if err == nil {
 		err = json.Unmarshal(body, &resp)
 		if err != nil {
@@ -137,10 +147,17 @@ func (h *Handler) GetListbranch(c *gin.Context) {
 
 	if len(resp.Branches) <= 0 {
 		resp, err = h.strg.Branch().GetList(ctx, &models.GetListBranchRequest{
-			Limit:  limit,
-			Offset: offset,
-			Search: search,
+			Limit:   limit,
+			Offset:  offset,
+			Search:  search,
+			Filters: list.Filters,
+			Sort:    list.Sort,
 		})
+		var criteriaErr *criteria.Error
+		if errors.As(err, &criteriaErr) {
+			handleResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		if err != nil {
 			handleResponse(c, http.StatusInternalServerError, err)
 			return
@@ -152,10 +169,10 @@ func (h *Handler) GetListbranch(c *gin.Context) {
 			return
 		}
 
-		h.cache.SetX(ctx, key, string(body), time.Second*15)
+		h.cacheList(ctx, c, key, body)
 	}
 
-	handleResponse(c, http.StatusOK, resp)
+	handleListResponse(c, resp, list.Fields)
 }
 
 // @Summary Update a branch
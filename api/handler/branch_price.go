@@ -0,0 +1,307 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+)
+
+// @Summary Create a branch price
+// @Description Set the retail price of a product at a branch starting from effective_from (now when empty).
+// @Tags branch_price
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param branch_price body models.CreateBranchPrice true "Branch price information"
+// @Success 201 {object} models.BranchPrice "Created branch price"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/branch_price [post]
+func (h *Handler) CreateBranchPrice(c *gin.Context) {
+
+	var createBranchPrice models.CreateBranchPrice
+	err := c.ShouldBindJSON(&createBranchPrice)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	if !helpers.IsValidUUID(createBranchPrice.BranchID) {
+		handleResponse(c, http.StatusBadRequest, "branch id is not uuid")
+		return
+	}
+
+	if !helpers.IsValidUUID(createBranchPrice.ProductID) {
+		handleResponse(c, http.StatusBadRequest, "product id is not uuid")
+		return
+	}
+
+	if createBranchPrice.Price < 0 {
+		handleResponse(c, http.StatusBadRequest, "price must not be negative")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	resp, err := h.strg.BranchPrice().Create(ctx, &createBranchPrice)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusCreated, resp)
+}
+
+// @Summary Get a branch price by ID
+// @Description Get branch price details by its ID.
+// @Tags branch_price
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Branch price ID"
+// @Success 200 {object} models.BranchPrice "Branch price details"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/branch_price/{id} [get]
+func (h *Handler) GetByIDBranchPrice(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.BranchPrice().GetByID(ctx, &models.BranchPricePrimaryKey{Id: id})
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "no rows in result set")
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Get a list of branch prices
+// @Description Get the branch price list, optionally for one branch and/or product. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, branch_id, product_id, price, effective_from, price_change_id, created_at, updated_at.
+// @Tags branch_price
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param limit query int false "Number of items to return (default 10)"
+// @Param offset query int false "Number of items to skip (default 0)"
+// @Param branch_id[eq] query string false "Branch ID"
+// @Param product_id[eq] query string false "Product ID"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
+// @Success 200 {object} models.GetListBranchPriceResponse "List of branch prices"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/branch_price [get]
+func (h *Handler) GetListBranchPrice(c *gin.Context) {
+
+	limit, err := getIntegerOrDefaultValue(c.Query("limit"), 10)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query limit")
+		return
+	}
+
+	offset, err := getIntegerOrDefaultValue(c.Query("offset"), 0)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query offset")
+		return
+	}
+
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.BranchPrice().GetList(ctx, &models.GetListBranchPriceRequest{
+		Limit:   limit,
+		Offset:  offset,
+		Filters: list.Filters,
+		Sort:    list.Sort,
+	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleListResponse(c, resp, list.Fields)
+}
+
+// @Summary Update a branch price
+// @Description Update the price or effective_from of a branch price entry.
+// @Tags branch_price
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Branch price ID"
+// @Param branch_price body models.UpdateBranchPrice true "Updated branch price information"
+// @Success 202 {object} models.BranchPrice "Updated branch price"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/branch_price/{id} [put]
+func (h *Handler) UpdateBranchPrice(c *gin.Context) {
+
+	var updateBranchPrice models.UpdateBranchPrice
+
+	err := c.ShouldBindJSON(&updateBranchPrice)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+	updateBranchPrice.Id = id
+
+	if updateBranchPrice.Price < 0 {
+		handleResponse(c, http.StatusBadRequest, "price must not be negative")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	rowsAffected, err := h.strg.BranchPrice().Update(ctx, &updateBranchPrice)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "no rows affected")
+		return
+	}
+
+	resp, err := h.strg.BranchPrice().GetByID(ctx, &models.BranchPricePrimaryKey{Id: updateBranchPrice.Id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
+
+// @Summary Delete a branch price
+// @Description Delete a branch price entry.
+// @Tags branch_price
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Branch price ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/branch_price/{id} [delete]
+func (h *Handler) DeleteBranchPrice(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	err := h.strg.BranchPrice().Delete(ctx, &models.BranchPricePrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusNoContent, nil)
+}
+
+// @Summary Branch price history
+// @Description Every price a product had at a branch with its effective window, plus the price in effect at the given moment (now when omitted).
+// @Tags branch_price
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param branch_id query string true "Branch ID"
+// @Param product_id query string true "Product ID"
+// @Param at query string false "Moment, e.g. 2024-01-31 09:00:00"
+// @Success 200 {object} models.BranchPriceHistoryResponse "Price history"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/branch_price/history [get]
+func (h *Handler) GetBranchPriceHistory(c *gin.Context) {
+
+	var (
+		branchID  = c.Query("branch_id")
+		productID = c.Query("product_id")
+	)
+
+	if !helpers.IsValidUUID(branchID) {
+		handleResponse(c, http.StatusBadRequest, "branch id is not uuid")
+		return
+	}
+
+	if !helpers.IsValidUUID(productID) {
+		handleResponse(c, http.StatusBadRequest, "product id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.BranchPrice().History(ctx, &models.BranchPriceHistoryRequest{
+		BranchID:  branchID,
+		ProductID: productID,
+		At:        c.Query("at"),
+	})
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "product not found")
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
@@ -2,32 +2,34 @@ package handler
 
 import (
 	"context"
-	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"time"
 
 	"market_system/config"
 	"market_system/models"
+	"market_system/pkg/criteria"
 	"market_system/pkg/helpers"
+	"market_system/storage"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
 )
 
-// @Summary Create a new category
-// @Description Create a new category in the market system.
-// @Tags category
+// @Summary Create a new brand
+// @Description Create a new brand in the market system.
+// @Tags brand
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Authentication token"
 // @Param Password header string true "User password"
-// @Param category body models.CreateCategory true "Category information"
-// @Success 201 {object} models.Category "Created category"
+// @Param brand body models.CreateBrand true "Brand information"
+// @Success 201 {object} models.Brand "Created brand"
 // @Failure 400 {object} ErrorResponse "Bad Request"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
 // @Failure 500 {object} ErrorResponse "Internal Server Error"
-// @Router /v1/category [post]
+// @Router /v1/brand [post]
 func (h *Handler) CreateBrand(c *gin.Context) {
 
 	var createBrand models.CreateBrand
@@ -49,21 +51,20 @@ func (h *Handler) CreateBrand(c *gin.Context) {
 	handleResponse(c, http.StatusCreated, resp)
 }
 
-// @Summary Get a category by ID
-// @Description Get category details by its ID.
-// @Tags category
+// @Summary Get a brand by ID
+// @Description Get brand details by its ID.
+// @Tags brand
 // @Accept json
 // @Produce json
-// @Param id path string true "Category ID"
+// @Param id path string true "Brand ID"
 // @Param Authorization header string true "Authentication token"
 // @Param Password header string true "User password"
-// @Success 200 {object} models.Category "Category details"
+// @Success 200 {object} models.Brand "Brand details"
 // @Failure 400 {object} ErrorResponse "Bad Request"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
-// @Failure 404 {object} ErrorResponse "Category not found"
 // @Failure 500 {object} ErrorResponse "Internal Server Error"
-// @Router /v1/category/{id} [get]
-func (h *Handler) GetByIDCategory(c *gin.Context) {
+// @Router /v1/brand/{id} [get]
+func (h *Handler) GetByIDBrand(c *gin.Context) {
 
 	var id = c.Param("id")
 	if !helpers.IsValidUUID(id) {
@@ -71,11 +72,11 @@ func (h *Handler) GetByIDCategory(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	ctx, cancel := requestContext(c)
 	defer cancel()
 
-	resp, err := h.strg.Category().GetByID(ctx, &models.CategoryPrimaryKey{Id: id})
-	if err == sql.ErrNoRows {
+	resp, err := h.strg.Brand().GetByID(ctx, &models.BrandPrimaryKey{Id: id})
+	if errors.Is(err, pgx.ErrNoRows) {
 		handleResponse(c, http.StatusBadRequest, "no rows in result set")
 		return
 	}
@@ -88,9 +89,9 @@ func (h *Handler) GetByIDCategory(c *gin.Context) {
 	handleResponse(c, http.StatusOK, resp)
 }
 
-// @Summary Get a list of category
-// @Description Get a list of category with optional filtering.
-// @Tags category
+// @Summary Get a list of brands
+// @Description Get a list of brands with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, name, created_at, updated_at.
+// @Tags brand
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Authentication token"
@@ -98,12 +99,15 @@ func (h *Handler) GetByIDCategory(c *gin.Context) {
 // @Param limit query int false "Number of items to return (default 10)"
 // @Param offset query int false "Number of items to skip (default 0)"
 // @Param search query string false "Search term"
-// @Success 200 {array} models.Category "List of category"
+// @Param created_at[between] query string false "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
+// @Success 200 {object} models.GetListBrandResponse "List of brands"
 // @Failure 400 {object} ErrorResponse "Bad Request"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
 // @Failure 500 {object} ErrorResponse "Internal Server Error"
-// @Router /v1/category [get]
-func (h *Handler) GetListCategory(c *gin.Context) {
+// @Router /v1/brand [get]
+func (h *Handler) GetListBrand(c *gin.Context) {
 
 	limit, err := getIntegerOrDefaultValue(c.Query("limit"), 10)
 	if err != nil {
@@ -117,21 +121,21 @@ func (h *Handler) GetListCategory(c *gin.Context) {
 		return
 	}
 
-	search := c.Query("search")
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
 	if err != nil {
-		handleResponse(c, http.StatusBadRequest, "invalid query search")
+		handleResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	ctx, cancel := requestContext(c)
 	defer cancel()
 
 	var (
-		key  = fmt.Sprintf("category-%s", c.Request.URL.Query().Encode())
-		resp = &models.GetListCategoryResponse{}
+		key  = fmt.Sprintf("brand-%s", c.Request.URL.Query().Encode())
+		resp = &models.GetListBrandResponse{}
 	)
 
-	body, err := h.cache.GetX(ctx, key)
+	body, err := h.cachedList(ctx, c, key)
 	if err == nil {
 		err = json.Unmarshal(body, &resp)
 		if err != nil {
@@ -140,12 +144,19 @@ func (h *Handler) GetListCategory(c *gin.Context) {
 		}
 	}
 
-	if len(resp.Categories) <= 0 {
-		resp, err = h.strg.Category().GetList(ctx, &models.GetListCategoryRequest{
-			Limit:  limit,
-			Offset: offset,
-			Search: search,
+	if len(resp.Brands) <= 0 {
+		resp, err = h.strg.Brand().GetList(ctx, &models.GetListBrandRequest{
+			Limit:   limit,
+			Offset:  offset,
+			Search:  c.Query("search"),
+			Filters: list.Filters,
+			Sort:    list.Sort,
 		})
+		var criteriaErr *criteria.Error
+		if errors.As(err, &criteriaErr) {
+			handleResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		if err != nil {
 			handleResponse(c, http.StatusInternalServerError, err)
 			return
@@ -157,33 +168,31 @@ func (h *Handler) GetListCategory(c *gin.Context) {
 			return
 		}
 
-		h.cache.SetX(ctx, key, string(body), time.Second*15)
+		h.cacheList(ctx, c, key, body)
 	}
 
-	handleResponse(c, http.StatusOK, resp)
+	handleListResponse(c, resp, list.Fields)
 }
 
-// @Summary Update a category
-// @Description Update an existing category.
-// @Tags category
+// @Summary Update a brand
+// @Description Update an existing brand.
+// @Tags brand
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Authentication token"
 // @Param Password header string true "User password"
-// @Param Authorization header string true "Authentication token"
-// @Param id path string true "Category ID"
-// @Param category body models.UpdateCategory true "Updated category information"
-// @Success 202 {object} models.Category "Updated category"
+// @Param id path string true "Brand ID"
+// @Param brand body models.UpdateBrand true "Updated brand information"
+// @Success 202 {object} models.Brand "Updated brand"
 // @Failure 400 {object} ErrorResponse "Bad Request"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
-// @Failure 404 {object} ErrorResponse "Category not found"
 // @Failure 500 {object} ErrorResponse "Internal Server Error"
-// @Router /v1/category/{id} [put]
-func (h *Handler) UpdateCategory(c *gin.Context) {
+// @Router /v1/brand/{id} [put]
+func (h *Handler) UpdateBrand(c *gin.Context) {
 
-	var updateCategory models.UpdateCategory
+	var updateBrand models.UpdateBrand
 
-	err := c.ShouldBindJSON(&updateCategory)
+	err := c.ShouldBindJSON(&updateBrand)
 	if err != nil {
 		handleResponse(c, http.StatusBadRequest, err.Error())
 		return
@@ -194,19 +203,12 @@ func (h *Handler) UpdateCategory(c *gin.Context) {
 		handleResponse(c, http.StatusBadRequest, "id is not uuid")
 		return
 	}
-	updateCategory.Id = id
-
-	if updateCategory.ParentID != "" {
-		if !helpers.IsValidUUID(updateCategory.ParentID) {
-			handleResponse(c, http.StatusBadRequest, "parent id is not uuid")
-			return
-		}
-	}
+	updateBrand.Id = id
 
 	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
 	defer cancel()
 
-	rowsAffected, err := h.strg.Category().Update(ctx, &updateCategory)
+	rowsAffected, err := h.strg.Brand().Update(ctx, &updateBrand)
 	if err != nil {
 		handleResponse(c, http.StatusInternalServerError, err)
 		return
@@ -217,10 +219,7 @@ func (h *Handler) UpdateCategory(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel = context.WithTimeout(context.Background(), config.CtxTimeout)
-	defer cancel()
-
-	resp, err := h.strg.Category().GetByID(ctx, &models.CategoryPrimaryKey{Id: updateCategory.Id})
+	resp, err := h.strg.Brand().GetByID(ctx, &models.BrandPrimaryKey{Id: updateBrand.Id})
 	if err != nil {
 		handleResponse(c, http.StatusInternalServerError, err)
 		return
@@ -229,31 +228,50 @@ func (h *Handler) UpdateCategory(c *gin.Context) {
 	handleResponse(c, http.StatusAccepted, resp)
 }
 
-// @Summary Delete a category
-// @Description Delete an existing category.
-// @Tags category
+// @Summary Delete a brand
+// @Description Delete a brand. A brand that products, categories or promotions refer to is only deleted with reassign_to, the brand they are moved to.
+// @Tags brand
 // @Accept json
 // @Produce json
 // @Param Authorization header string true "Authentication token"
 // @Param Password header string true "User password"
-// @Param id path string true "Category ID"
+// @Param id path string true "Brand ID"
+// @Param reassign_to query string false "Brand ID to move the products, categories and promotions of the brand to"
 // @Success 204 "No Content"
 // @Failure 400 {object} ErrorResponse "Bad Request"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
 // @Failure 500 {object} ErrorResponse "Internal Server Error"
-// @Router /v1/category/{id} [delete]
-func (h *Handler) DeleteCategory(c *gin.Context) {
-	var id = c.Param("id")
+// @Router /v1/brand/{id} [delete]
+func (h *Handler) DeleteBrand(c *gin.Context) {
 
+	var id = c.Param("id")
 	if !helpers.IsValidUUID(id) {
 		handleResponse(c, http.StatusBadRequest, "id is not uuid")
 		return
 	}
 
+	var reassignTo = c.Query("reassign_to")
+	if reassignTo != "" && !helpers.IsValidUUID(reassignTo) {
+		handleResponse(c, http.StatusBadRequest, "reassign to is not uuid")
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
 	defer cancel()
 
-	err := h.strg.Category().Delete(ctx, &models.CategoryPrimaryKey{Id: id})
+	err := h.strg.Brand().Delete(ctx, &models.DeleteBrand{Id: id, ReassignTo: reassignTo})
+	if errors.Is(err, storage.ErrBrandInUse) {
+		handleResponse(c, http.StatusBadRequest, err.Error()+"; give reassign_to to move them to another brand")
+		return
+	}
+	if errors.Is(err, storage.ErrBrandNotFound) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		handleResponse(c, http.StatusBadRequest, "no rows in result set")
+		return
+	}
 	if err != nil {
 		handleResponse(c, http.StatusInternalServerError, err)
 		return
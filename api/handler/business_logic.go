@@ -3,14 +3,17 @@ package handler
 import (
 	"context"
 	"errors"
-	"fmt"
 	"market_system/config"
 	"market_system/models"
+	"market_system/pkg/criteria"
 	"market_system/pkg/helpers"
+	"market_system/pkg/promotion"
+	"market_system/storage"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
 )
 
 func (h *Handler) SaleScanBarcode(c *gin.Context) {
@@ -32,7 +35,10 @@ func (h *Handler) SaleScanBarcode(c *gin.Context) {
 
 	remainingTableProduct, err := h.strg.Remainder().GetList(context.Background(), &models.GetListRemainderRequest{
 		Limit: 1,
-		Query: fmt.Sprintf(" AND bracode = %s AND branch_id = %s", barcode, branchID),
+		Filters: []criteria.Filter{
+			criteria.Equal("barcode", barcode),
+			criteria.Equal("branch_id", branchID),
+		},
 	})
 
 	if err != nil {
@@ -47,7 +53,10 @@ func (h *Handler) SaleScanBarcode(c *gin.Context) {
 
 	saleProduct, err := h.strg.Sale_Product().GetList(context.Background(), &models.GetListSaleProductRequest{
 		Limit: 1,
-		Query: fmt.Sprintf(" AND bracode = %s AND sale_id = %s", barcode, saleID),
+		Filters: []criteria.Filter{
+			criteria.Equal("barcode", barcode),
+			criteria.Equal("sale_id", saleID),
+		},
 	})
 
 	if err != nil {
@@ -57,7 +66,21 @@ func (h *Handler) SaleScanBarcode(c *gin.Context) {
 
 	if len(saleProduct.SaleProducts) <= 0 {
 		var product = remainingTableProduct.Remainder[0]
-		_, err := h.strg.Sale_Product().Create(context.Background(), &models.CreateSaleProduct{
+
+		price, err := h.strg.BranchPrice().Resolve(context.Background(), &models.ResolvePriceRequest{
+			BranchID: branchID,
+			Barcode:  product.Barcode,
+		})
+		if err == pgx.ErrNoRows || errors.Is(err, storage.ErrPriceProductMissing) {
+			handleResponse(c, http.StatusBadRequest, "Цена товара не найдена")
+			return
+		}
+		if err != nil {
+			handleResponse(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		_, err = h.strg.Sale_Product().Create(context.Background(), &models.CreateSaleProduct{
 			SaleID:            saleID,
 			CategoryID:        product.CategoryID,
 			ProductName:       product.ProductName,
@@ -67,14 +90,20 @@ func (h *Handler) SaleScanBarcode(c *gin.Context) {
 			AllowDiscount:     false,
 			DiscountType:      "",
 			Discount:          0,
-			Price:             product.PriceIncome,
-			TotalAmount:       product.PriceIncome,
+			Price:             price.Price,
+			TotalAmount:       price.Price,
 		})
 		if err != nil {
 			handleResponse(c, http.StatusInternalServerError, err.Error())
 			return
 		}
 
+		err = h.applyPromotions(context.Background(), saleID)
+		if err != nil {
+			handleResponse(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+
 		handleResponse(c, http.StatusCreated, "Успешно")
 		return
 	}
@@ -100,6 +129,12 @@ func (h *Handler) SaleScanBarcode(c *gin.Context) {
 		return
 	}
 
+	err = h.applyPromotions(context.Background(), saleID)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
 	handleResponse(c, http.StatusCreated, "Успешно")
 	return
 }
@@ -126,8 +161,8 @@ func (h *Handler) Dosale(c *gin.Context) {
 	}
 
 	salePaymentResponse, err := h.strg.Payment().GetList(context.Background(), &models.GetListPaymentRequest{
-		Limit: 100,
-		Query: fmt.Sprintf(" AND sale_id = %s", saleID),
+		Limit:   100,
+		Filters: []criteria.Filter{criteria.Equal("sale_id", saleID)},
 	})
 	if err != nil {
 		handleResponse(c, http.StatusInternalServerError, err.Error())
@@ -135,8 +170,8 @@ func (h *Handler) Dosale(c *gin.Context) {
 	}
 
 	cashTransactionResponse, err := h.strg.Transaction().GetList(context.Background(), &models.GetListTransactonRequest{
-		Limit: 100,
-		Query: fmt.Sprintf(" AND shift_id = %s", saleData.ShiftID),
+		Limit:   100,
+		Filters: []criteria.Filter{criteria.Equal("shift_id", saleData.ShiftID)},
 	})
 	if err != nil {
 		handleResponse(c, http.StatusInternalServerError, err.Error())
@@ -148,77 +183,49 @@ func (h *Handler) Dosale(c *gin.Context) {
 		return
 	}
 
+	if len(salePaymentResponse.Payments) <= 0 {
+		handleResponse(c, http.StatusBadRequest, "не найден оплата")
+		return
+	}
+
 	var (
 		salePayment     = salePaymentResponse.Payments[0]
 		cashTransaction = cashTransactionResponse.Transactions[0]
 	)
-	_, err = h.strg.Transaction().Update(context.Background(), &models.UpdateTransaction{
-		Id:          cashTransaction.Id,
-		Cash:        cashTransaction.Cash + salePayment.Cash,
-		Uzcard:      cashTransaction.Uzcard + salePayment.Uzcard,
-		Payme:       cashTransaction.Payme + salePayment.Payme,
-		Click:       cashTransaction.Click + salePayment.Click,
-		Humo:        cashTransaction.Humo + salePayment.Humo,
-		Apelsin:     cashTransaction.Apelsin + salePayment.Apelsin,
-		TotalAmount: cashTransaction.TotalAmount + salePayment.TotalAmount,
-	})
-	if err != nil {
-		handleResponse(c, http.StatusInternalServerError, err.Error())
+
+	if salePayment.Points > 0 && saleData.CustomerID == "" {
+		handleResponse(c, http.StatusBadRequest, "Оплата баллами без клиента")
 		return
 	}
 
-	saleProductResponse, err := h.strg.Sale_Product().GetList(context.Background(), &models.GetListSaleProductRequest{
-		Limit: 1000,
-		Query: fmt.Sprintf(" AND sale_id = %s", saleID),
+	// the tenders, the stock and the loyalty booking are written in one
+	// transaction that locks the sale, so a sale is finished only once
+	err = h.strg.Sale().Finish(context.Background(), &models.FinishSale{
+		Id:            saleData.Id,
+		BranchID:      branchID,
+		TransactionID: cashTransaction.Id,
+		Payment:       salePayment,
 	})
-	if err != nil {
-		handleResponse(c, http.StatusInternalServerError, err.Error())
+	if errors.Is(err, storage.ErrSaleFinished) {
+		handleResponse(c, http.StatusBadRequest, "Продажа уже завершена")
 		return
 	}
-
-	var remainderQuery = fmt.Sprintf(" AND branch_id = %s AND barcode IN (", branchID)
-	for _, saleProduct := range saleProductResponse.SaleProducts {
-		remainderQuery += fmt.Sprintf("'%s',", saleProduct.Barcode)
+	if errors.Is(err, storage.ErrSaleClosed) {
+		handleResponse(c, http.StatusBadRequest, "Продажа уже отменена или возвращена")
+		return
 	}
-	remainderQuery = remainderQuery[:len(remainderQuery)-1]
-	remainderQuery += ")"
-
-	remainderResponse, err := h.strg.Remainder().GetList(context.Background(), &models.GetListRemainderRequest{
-		Limit: 1000,
-		Query: remainderQuery,
-	})
-	if err != nil {
-		handleResponse(c, http.StatusInternalServerError, err.Error())
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "Сертификат не найден")
 		return
 	}
-
-	for _, saleProduct := range saleProductResponse.SaleProducts {
-		for _, remainder := range remainderResponse.Remainder {
-			if saleProduct.Barcode == remainder.Barcode {
-				_, err := h.strg.Remainder().Update(context.Background(), &models.UpdateRemainder{
-					Id:          remainder.Id,
-					ProductName: remainder.ProductName,
-					Barcode:     remainder.Barcode,
-					PriceIncome: remainder.PriceIncome,
-					Quantity:    remainder.Quantity - saleProduct.Quantity,
-				})
-				if err != nil {
-					handleResponse(c, http.StatusInternalServerError, err.Error())
-					return
-				}
-			}
-		}
+	if errors.Is(err, storage.ErrNotEnoughPoints) {
+		handleResponse(c, http.StatusBadRequest, "Недостаточно баллов")
+		return
+	}
+	if errors.Is(err, storage.ErrGiftCardNotUsable) || errors.Is(err, storage.ErrNotEnoughGiftCardBalance) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
 	}
-
-	_, err = h.strg.Sale().Update(context.Background(), &models.UpdateSale{
-		Id:          saleData.Id,
-		BranchID:    saleData.BranchID,
-		SalePointID: saleData.SalePointID,
-		ShiftID:     saleData.ShiftID,
-		EmployeeID:  saleData.EmployeeID,
-		Barcode:     saleData.Barcode,
-		Status:      "finished",
-	})
 	if err != nil {
 		handleResponse(c, http.StatusInternalServerError, err.Error())
 		return
@@ -230,95 +237,59 @@ func (h *Handler) Dosale(c *gin.Context) {
 
 func (h *Handler) DoIncome(c *gin.Context) {
 
-	var (
-		coming_id = c.Query("coming_id")
-	)
+	var comingID = c.Param("coming_id")
+	if !helpers.IsValidUUID(comingID) {
+		handleResponse(c, http.StatusBadRequest, "coming id is not uuid")
+		return
+	}
+
 	ctx, cencel := context.WithTimeout(context.Background(), config.CtxTimeout)
 	defer cencel()
 
-	incomeTable, err := h.strg.Income().GetByID(ctx, &models.IncomePrimaryKey{Id: coming_id})
-	if err != nil {
+	rowsAffected, err := h.strg.Income().Finish(ctx, &models.IncomePrimaryKey{Id: comingID})
+	if errors.Is(err, storage.ErrSupplierInvoiceUnresolved) {
 		handleResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
-	if incomeTable.Status == "finished" {
-		handleResponse(c, http.StatusBadRequest, errors.New("coming table status finished"))
-		return
-	}
-	remainderTable, err := h.strg.Remainder().GetByID(ctx, &models.RemainderPrimaryKey{Id: incomeTable.BranchID})
 	if err != nil {
 		handleResponse(c, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	incriseRemainder, err := h.strg.Remainder().GetList(ctx, &models.GetListRemainderRequest{
-		Query: fmt.Sprintf(" AND branch_id = %s AND product_name = %s", incomeTable.BranchID, remainderTable.ProductName),
-	})
-	if err != nil {
-		handleResponse(c, http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	var (
-		remainder      = models.Remainder{}
-		income_product = models.IncomeProduct{}
-	)
-	remainder = *incriseRemainder.Remainder[0]
-	// for _, v := range incriseRemainder.Remainder {
-	// 	remainder = models.Remainder{
-	// 		Id:          v.Id,
-	// 		BranchID:    v.BranchID,
-	// 		CategoryID:  v.CategoryID,
-	// 		ProductName: v.ProductName,
-	// 		Barcode:     v.Barcode,
-	// 		PriceIncome: v.PriceIncome,
-	// 		Quantity:    v.Quantity,
-	// 		CreatedAt:   v.CreatedAt,
-	// 		UpdatedAt:   v.UpdatedAt,
-	// 	}
-	// }
-
-	incomeProductList, err := h.strg.IncomeProduct().GetList(ctx, &models.GetListIncomeProductRequest{
-		Limit: 1,
-		Query: fmt.Sprintf(" AND income_id = %s", incomeTable.Id),
-	})
-	if err != nil {
-		handleResponse(c, http.StatusInternalServerError, err.Error())
-		return
-	}
-
-	income_product = *incomeProductList.IncomeProducts[0]
-	remainder = models.Remainder{
-		Id:          remainder.Id,
-		BranchID:    remainder.BranchID,
-		CategoryID:  remainder.CategoryID,
-		ProductName: remainder.ProductName,
-		Barcode:     remainder.Barcode,
-		PriceIncome: remainder.PriceIncome,
-		Quantity:    remainder.Quantity + int(income_product.Quantity),
-	}
-
-	_, err = h.strg.Remainder().Update(ctx, &models.UpdateRemainder{
-		Id:          remainder.Id,
-		ProductName: remainder.ProductName,
-		PriceIncome: remainder.PriceIncome,
-		Barcode:     remainder.Barcode,
-		Quantity:    remainder.Quantity,
-	})
-	if err != nil {
-		handleResponse(c, http.StatusInternalServerError, err.Error())
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "coming table not found or status finished")
 		return
 	}
 
+	handleResponse(c, http.StatusOK, "Успешно")
 }
 
+// ShiftTable opens or closes a shift. Opening takes the float put into the
+// drawer (opening_cash), closing the cash the cashier counted (counted_cash),
+// from which the cashier report works out the cash variance.
 func (h *Handler) ShiftTable(c *gin.Context) {
 
 	var (
 		method      = c.Query("method")
-		cashTableId = c.Query("cash_table")
+		cashTableId = c.DefaultQuery("cash_table", c.Param("id"))
 	)
 
+	openingCash, err := getFloatOrDefaultValue(c.Query("opening_cash"), 0)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query opening_cash")
+		return
+	}
+
+	var countedCash *float64
+	if c.Query("counted_cash") != "" {
+		counted, err := getFloatOrDefaultValue(c.Query("counted_cash"), 0)
+		if err != nil {
+			handleResponse(c, http.StatusBadRequest, "invalid query counted_cash")
+			return
+		}
+		countedCash = &counted
+	}
+
 	ctx, cencel := context.WithTimeout(context.Background(), config.CtxTimeout)
 	defer cencel()
 
@@ -333,14 +304,17 @@ func (h *Handler) ShiftTable(c *gin.Context) {
 	if method == "open" {
 
 		_, err = h.strg.Transaction().Create(ctx, &models.CreateTransaction{
-			ShiftID:     shiftResp.Id,
-			Cash:        0,
-			Uzcard:      0,
-			Payme:       0,
-			Click:       0,
-			Humo:        0,
-			Apelsin:     0,
-			TotalAmount: 0,
+			ShiftID:      shiftResp.Id,
+			Cash:         0,
+			Uzcard:       0,
+			Payme:        0,
+			Click:        0,
+			Humo:         0,
+			Apelsin:      0,
+			Points:       0,
+			GiftCard:     0,
+			GiftCardSold: 0,
+			TotalAmount:  0,
 		})
 		if err != nil {
 			handleResponse(c, http.StatusInternalServerError, err.Error())
@@ -354,6 +328,8 @@ func (h *Handler) ShiftTable(c *gin.Context) {
 			Status:      "Открытая",
 			OpenShift:   time.Now().Format("2006-01-02 15:04:05"),
 			CloseShift:  shiftResp.CloseShift,
+			OpeningCash: openingCash,
+			CountedCash: shiftResp.CountedCash,
 		})
 		if err != nil {
 			handleResponse(c, http.StatusBadRequest, err.Error())
@@ -369,6 +345,8 @@ func (h *Handler) ShiftTable(c *gin.Context) {
 			Status:      "Закрытая",
 			OpenShift:   shiftResp.OpenShift,
 			CloseShift:  time.Now().Format("2006-01-02 15:04:05"),
+			OpeningCash: shiftResp.OpeningCash,
+			CountedCash: countedCash,
 		})
 		if err != nil {
 			handleResponse(c, http.StatusBadRequest, err.Error())
@@ -377,3 +355,86 @@ func (h *Handler) ShiftTable(c *gin.Context) {
 	}
 
 }
+
+// applyPromotions re-evaluates the whole basket of an open sale against the
+// promotions running in its branch and stores the discount every promotion
+// produced. Lines with a manual discount_type are left out.
+func (h *Handler) applyPromotions(ctx context.Context, saleID string) error {
+
+	sale, err := h.strg.Sale().GetByID(ctx, &models.SalePrimaryKey{Id: saleID})
+	if err != nil {
+		return err
+	}
+
+	if sale.Status == config.SaleStatusFinished {
+		return nil
+	}
+
+	promotions, err := h.strg.Promotion().GetActive(ctx, &models.GetActivePromotionRequest{BranchID: sale.BranchID})
+	if err != nil {
+		return err
+	}
+
+	basket, err := h.strg.Promotion().GetBasket(ctx, &models.SalePrimaryKey{Id: saleID})
+	if err != nil {
+		return err
+	}
+
+	var (
+		lines          []promotion.Line
+		saleProductIDs []string
+		rules          = make([]promotion.Rule, 0, len(promotions))
+		apply          = models.ApplySalePromotions{SaleID: saleID}
+	)
+
+	for _, line := range basket {
+		if line.DiscountType != "" && line.DiscountType != config.DiscountTypePromotion {
+			continue
+		}
+
+		saleProductIDs = append(saleProductIDs, line.SaleProductID)
+		lines = append(lines, promotion.Line{
+			ID:         line.SaleProductID,
+			Barcode:    line.Barcode,
+			CategoryID: line.CategoryID,
+			BrandID:    line.BrandID,
+			Quantity:   line.Quantity,
+			Price:      line.Price,
+		})
+	}
+
+	for _, rule := range promotions {
+		var items = make([]promotion.Item, 0, len(rule.Items))
+		for _, item := range rule.Items {
+			items = append(items, promotion.Item{Barcode: item.Barcode, Quantity: item.Quantity})
+		}
+
+		rules = append(rules, promotion.Rule{
+			ID:              rule.Id,
+			Type:            rule.Type,
+			Percent:         rule.Percent,
+			BuyQuantity:     rule.BuyQuantity,
+			GetQuantity:     rule.GetQuantity,
+			BundlePrice:     rule.BundlePrice,
+			CategoryID:      rule.CategoryID,
+			BrandID:         rule.BrandID,
+			Items:           items,
+			MinBasketAmount: rule.MinBasketAmount,
+			HappyHourFrom:   rule.HappyHourFrom,
+			HappyHourTo:     rule.HappyHourTo,
+			Priority:        rule.Priority,
+			Stackable:       rule.Stackable,
+		})
+	}
+
+	for _, discount := range promotion.Evaluate(lines, rules, time.Now()) {
+		apply.Discounts = append(apply.Discounts, &models.SaleProductPromotion{
+			SaleProductID: discount.LineID,
+			PromotionID:   discount.PromotionID,
+			Discount:      discount.Amount,
+		})
+	}
+	apply.SaleProductIDs = saleProductIDs
+
+	return h.strg.Promotion().ApplyToSale(ctx, &apply)
+}
@@ -4,19 +4,22 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"time"
 
 	"market_system/config"
 	"market_system/models"
+	"market_system/pkg/criteria"
 	"market_system/pkg/helpers"
+	"market_system/storage"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
 )
 
 // @Summary Create a new category
-// @Description Create a new category in the market system.
+// @Description Create a new category in the market system. A subcategory is of the brand of its parent unless brand_id is given.
 // @Tags category
 // @Accept json
 // @Produce json
@@ -37,27 +40,24 @@ func (h *Handler) CreateCategory(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
-	defer cancel()
+	if createCategory.ParentID != "" && !helpers.IsValidUUID(createCategory.ParentID) {
+		handleResponse(c, http.StatusBadRequest, "parent id is not uuid")
+		return
+	}
 
-	//  If request's Category.ID is empty then create new brand
-	if createCategory.ParentID != "" {
-		if !helpers.IsValidUUID(createCategory.ParentID) {
-			handleResponse(c, http.StatusBadRequest, "parent id is not uuid")
-			return
-		} else {
-
-			createBrand, err := h.strg.Brand().Create(ctx, &models.CreateBrand{
-				Name: createCategory.Title,
-			})
-			if err != nil {
-				handleResponse(c, http.StatusInternalServerError, err.Error())
-			}
-			createCategory.ParentID = createBrand.Id
-		}
+	if createCategory.BrandID != "" && !helpers.IsValidUUID(createCategory.BrandID) {
+		handleResponse(c, http.StatusBadRequest, "brand id is not uuid")
+		return
 	}
 
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
 	resp, err := h.strg.Category().Create(ctx, &createCategory)
+	if errors.Is(err, storage.ErrCategoryNotFound) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		handleResponse(c, http.StatusInternalServerError, err)
 		return
@@ -80,7 +80,7 @@ func (h *Handler) CreateCategory(c *gin.Context) {
 // @Failure 404 {object} ErrorResponse "Category not found"
 // @Failure 500 {object} ErrorResponse "Internal Server Error"
 // @Router /v1/category/{id} [get]
-func (h *Handler) GetByIDBrand(c *gin.Context) {
+func (h *Handler) GetByIDCategory(c *gin.Context) {
 
 	var id = c.Param("id")
 	if !helpers.IsValidUUID(id) {
@@ -88,7 +88,7 @@ func (h *Handler) GetByIDBrand(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	ctx, cancel := requestContext(c)
 	defer cancel()
 
 	resp, err := h.strg.Category().GetByID(ctx, &models.CategoryPrimaryKey{Id: id})
@@ -106,7 +106,7 @@ func (h *Handler) GetByIDBrand(c *gin.Context) {
 }
 
 // @Summary Get a list of category
-// @Description Get a list of category with optional filtering.
+// @Description Get a list of category with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null, under; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. parent_id[under] selects all subcategories of a category. Filter and sort fields: id, title, parent_id, brand_id, created_at, updated_at.
 // @Tags category
 // @Accept json
 // @Produce json
@@ -115,12 +115,16 @@ func (h *Handler) GetByIDBrand(c *gin.Context) {
 // @Param limit query int false "Number of items to return (default 10)"
 // @Param offset query int false "Number of items to skip (default 0)"
 // @Param search query string false "Search term"
+// @Param created_at[between] query string false "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)"
+// @Param parent_id[under] query string false "Category ID whose subcategories at any depth to return"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
 // @Success 200 {array} models.Category "List of category"
 // @Failure 400 {object} ErrorResponse "Bad Request"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
 // @Failure 500 {object} ErrorResponse "Internal Server Error"
 // @Router /v1/category [get]
-func (h *Handler) GetListBrand(c *gin.Context) {
+func (h *Handler) GetListCategory(c *gin.Context) {
 
 	limit, err := getIntegerOrDefaultValue(c.Query("limit"), 10)
 	if err != nil {
@@ -140,7 +144,13 @@ func (h *Handler) GetListBrand(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
 	defer cancel()
 
 	var (
@@ -148,7 +158,7 @@ func (h *Handler) GetListBrand(c *gin.Context) {
 		resp = &models.GetListCategoryResponse{}
 	)
 
-	body, err := h.cache.GetX(ctx, key)
+	body, err := h.cachedList(ctx, c, key)
 	if err == nil {
 		err = json.Unmarshal(body, &resp)
 		if err != nil {
@@ -159,10 +169,17 @@ func (h *Handler) GetListBrand(c *gin.Context) {
 
 	if len(resp.Categories) <= 0 {
 		resp, err = h.strg.Category().GetList(ctx, &models.GetListCategoryRequest{
-			Limit:  limit,
-			Offset: offset,
-			Search: search,
+			Limit:   limit,
+			Offset:  offset,
+			Search:  search,
+			Filters: list.Filters,
+			Sort:    list.Sort,
 		})
+		var criteriaErr *criteria.Error
+		if errors.As(err, &criteriaErr) {
+			handleResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		if err != nil {
 			handleResponse(c, http.StatusInternalServerError, err)
 			return
@@ -174,10 +191,10 @@ func (h *Handler) GetListBrand(c *gin.Context) {
 			return
 		}
 
-		h.cache.SetX(ctx, key, string(body), time.Second*15)
+		h.cacheList(ctx, c, key, body)
 	}
 
-	handleResponse(c, http.StatusOK, resp)
+	handleListResponse(c, resp, list.Fields)
 }
 
 // @Summary Update a category
@@ -196,7 +213,7 @@ func (h *Handler) GetListBrand(c *gin.Context) {
 // @Failure 404 {object} ErrorResponse "Category not found"
 // @Failure 500 {object} ErrorResponse "Internal Server Error"
 // @Router /v1/category/{id} [put]
-func (h *Handler) UpdateBrand(c *gin.Context) {
+func (h *Handler) UpdateCategory(c *gin.Context) {
 
 	var updateCategory models.UpdateCategory
 
@@ -224,6 +241,10 @@ func (h *Handler) UpdateBrand(c *gin.Context) {
 	defer cancel()
 
 	rowsAffected, err := h.strg.Category().Update(ctx, &updateCategory)
+	if errors.Is(err, storage.ErrCategoryNotFound) || errors.Is(err, storage.ErrCategoryCycle) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		handleResponse(c, http.StatusInternalServerError, err)
 		return
@@ -259,7 +280,7 @@ func (h *Handler) UpdateBrand(c *gin.Context) {
 // @Failure 401 {object} ErrorResponse "Unauthorized"
 // @Failure 500 {object} ErrorResponse "Internal Server Error"
 // @Router /v1/category/{id} [delete]
-func (h *Handler) DeleteBrand(c *gin.Context) {
+func (h *Handler) DeleteCategory(c *gin.Context) {
 	var id = c.Param("id")
 
 	if !helpers.IsValidUUID(id) {
@@ -278,3 +299,135 @@ func (h *Handler) DeleteBrand(c *gin.Context) {
 
 	handleResponse(c, http.StatusNoContent, nil)
 }
+
+// @Summary Get the category tree
+// @Description Get the categories nested under their parents, from the root categories or from root_id, with the products of each category (product_count) and of it with its subcategories (total_product_count).
+// @Tags category
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param root_id query string false "Category ID to return the tree under"
+// @Success 200 {object} models.GetCategoryTreeResponse "Category tree"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/category/tree [get]
+func (h *Handler) GetCategoryTree(c *gin.Context) {
+
+	var rootID = c.Query("root_id")
+	if rootID != "" && !helpers.IsValidUUID(rootID) {
+		handleResponse(c, http.StatusBadRequest, "root id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.Category().Tree(ctx, &models.GetCategoryTreeRequest{RootID: rootID})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Get the breadcrumbs of a category
+// @Description Get the path of categories from the root down to the category, which is the last.
+// @Tags category
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Category ID"
+// @Success 200 {object} models.GetCategoryBreadcrumbsResponse "Categories from the root"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/category/{id}/breadcrumbs [get]
+func (h *Handler) GetCategoryBreadcrumbs(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.Category().Breadcrumbs(ctx, &models.CategoryPrimaryKey{Id: id})
+	if errors.Is(err, pgx.ErrNoRows) {
+		handleResponse(c, http.StatusBadRequest, "no rows in result set")
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Move a category
+// @Description Put a category with its subcategories under another category, or at the root when parent_id is empty. A category can not be moved under itself or its subcategories.
+// @Tags category
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Category ID"
+// @Param move body models.MoveCategory true "New parent category"
+// @Success 202 {object} models.Category "Moved category"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/category/{id}/move [post]
+func (h *Handler) MoveCategory(c *gin.Context) {
+
+	var moveCategory models.MoveCategory
+	err := c.ShouldBindJSON(&moveCategory)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+	moveCategory.Id = id
+
+	if moveCategory.ParentID != "" && !helpers.IsValidUUID(moveCategory.ParentID) {
+		handleResponse(c, http.StatusBadRequest, "parent id is not uuid")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	rowsAffected, err := h.strg.Category().Move(ctx, &moveCategory)
+	if errors.Is(err, storage.ErrCategoryNotFound) || errors.Is(err, storage.ErrCategoryCycle) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "no rows affected")
+		return
+	}
+
+	resp, err := h.strg.Category().GetByID(ctx, &models.CategoryPrimaryKey{Id: moveCategory.Id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
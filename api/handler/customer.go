@@ -0,0 +1,477 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+	"market_system/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+)
+
+// @Summary Create a customer
+// @Description Create a loyalty customer. The phone is the customer's key.
+// @Tags customer
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param customer body models.CreateCustomer true "Customer information"
+// @Success 201 {object} models.Customer "Created customer"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/customer [post]
+func (h *Handler) CreateCustomer(c *gin.Context) {
+
+	var createCustomer models.CreateCustomer
+	err := c.ShouldBindJSON(&createCustomer)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	if !helpers.IsValidPhone(createCustomer.Phone) {
+		handleResponse(c, http.StatusBadRequest, "phone is not valid")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	resp, err := h.strg.Customer().Create(ctx, &createCustomer)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusCreated, resp)
+}
+
+// @Summary Get a customer by ID
+// @Description Get customer details with tier and points balance.
+// @Tags customer
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Customer ID"
+// @Success 200 {object} models.Customer "Customer details"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/customer/{id} [get]
+func (h *Handler) GetByIDCustomer(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.Customer().GetByID(ctx, &models.CustomerPrimaryKey{Id: id})
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "no rows in result set")
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Get a list of customers
+// @Description Get a list of customers. Look a customer up by phone[eq] or card_barcode[eq]. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, phone, first_name, last_name, card_barcode, tier_id, points_balance, total_spent, created_at, updated_at.
+// @Tags customer
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param limit query int false "Number of items to return (default 10)"
+// @Param offset query int false "Number of items to skip (default 0)"
+// @Param search query string false "Search by phone or name"
+// @Param phone[eq] query string false "Exact phone"
+// @Param card_barcode[eq] query string false "Loyalty card barcode"
+// @Param tier_id[eq] query string false "Loyalty tier ID"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
+// @Success 200 {object} models.GetListCustomerResponse "List of customers"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/customer [get]
+func (h *Handler) GetListCustomer(c *gin.Context) {
+
+	limit, err := getIntegerOrDefaultValue(c.Query("limit"), 10)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query limit")
+		return
+	}
+
+	offset, err := getIntegerOrDefaultValue(c.Query("offset"), 0)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query offset")
+		return
+	}
+
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.Customer().GetList(ctx, &models.GetListCustomerRequest{
+		Limit:   limit,
+		Offset:  offset,
+		Search:  c.Query("search"),
+		Filters: list.Filters,
+		Sort:    list.Sort,
+	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleListResponse(c, resp, list.Fields)
+}
+
+// @Summary Update a customer
+// @Description Update customer contact details and loyalty card.
+// @Tags customer
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Customer ID"
+// @Param customer body models.UpdateCustomer true "Updated customer information"
+// @Success 202 {object} models.Customer "Updated customer"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/customer/{id} [put]
+func (h *Handler) UpdateCustomer(c *gin.Context) {
+
+	var updateCustomer models.UpdateCustomer
+
+	err := c.ShouldBindJSON(&updateCustomer)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+	updateCustomer.Id = id
+
+	if !helpers.IsValidPhone(updateCustomer.Phone) {
+		handleResponse(c, http.StatusBadRequest, "phone is not valid")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	rowsAffected, err := h.strg.Customer().Update(ctx, &updateCustomer)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "no rows affected")
+		return
+	}
+
+	resp, err := h.strg.Customer().GetByID(ctx, &models.CustomerPrimaryKey{Id: updateCustomer.Id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
+
+// @Summary Delete a customer
+// @Description Delete a customer and their points history.
+// @Tags customer
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Customer ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/customer/{id} [delete]
+func (h *Handler) DeleteCustomer(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	err := h.strg.Customer().Delete(ctx, &models.CustomerPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusNoContent, nil)
+}
+
+// @Summary Attach a customer to a sale
+// @Description Identify the customer of an open sale by phone or loyalty card barcode.
+// @Tags sale
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Sale ID"
+// @Param customer body models.AttachSaleCustomer true "Phone or card barcode"
+// @Success 200 {object} models.Customer "Attached customer"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/sale/{id}/customer [post]
+func (h *Handler) AttachSaleCustomer(c *gin.Context) {
+
+	var attach models.AttachSaleCustomer
+	err := c.ShouldBindJSON(&attach)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	attach.SaleID = c.Param("id")
+	if !helpers.IsValidUUID(attach.SaleID) {
+		handleResponse(c, http.StatusBadRequest, "sale id is not uuid")
+		return
+	}
+
+	var lookup = models.GetListCustomerRequest{Limit: 1}
+	switch {
+	case attach.Phone != "":
+		if !helpers.IsValidPhone(attach.Phone) {
+			handleResponse(c, http.StatusBadRequest, "phone is not valid")
+			return
+		}
+		lookup.Filters = []criteria.Filter{criteria.Equal("phone", attach.Phone)}
+	case attach.CardBarcode != "":
+		lookup.Filters = []criteria.Filter{criteria.Equal("card_barcode", attach.CardBarcode)}
+	default:
+		handleResponse(c, http.StatusBadRequest, "phone or card_barcode is required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	customers, err := h.strg.Customer().GetList(ctx, &lookup)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if len(customers.Customers) == 0 {
+		handleResponse(c, http.StatusBadRequest, "Клиент не найден")
+		return
+	}
+
+	var customer = customers.Customers[0]
+	rowsAffected, err := h.strg.Customer().AttachToSale(ctx, &models.SaleCustomer{
+		SaleID:     attach.SaleID,
+		CustomerID: customer.Id,
+	})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "sale not found or already finished")
+		return
+	}
+
+	handleResponse(c, http.StatusOK, customer)
+}
+
+// @Summary Customer points history
+// @Description Points earned, redeemed and adjusted by hand, newest first.
+// @Tags customer
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Customer ID"
+// @Param limit query int false "Number of items to return (default 10)"
+// @Param offset query int false "Number of items to skip (default 0)"
+// @Success 200 {object} models.LoyaltyHistoryResponse "Points history"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/customer/{id}/history [get]
+func (h *Handler) GetCustomerHistory(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	limit, err := getIntegerOrDefaultValue(c.Query("limit"), 10)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query limit")
+		return
+	}
+
+	offset, err := getIntegerOrDefaultValue(c.Query("offset"), 0)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query offset")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.Loyalty().History(ctx, &models.LoyaltyHistoryRequest{
+		CustomerID: id,
+		Limit:      limit,
+		Offset:     offset,
+	})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Adjust customer points
+// @Description Add points by hand, or remove them with a negative value. Not allowed for cashiers.
+// @Tags customer
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Customer ID"
+// @Param adjust body models.AdjustLoyaltyPoints true "Points and comment"
+// @Success 200 {object} models.Customer "Customer with the new balance"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/customer/{id}/adjust [post]
+func (h *Handler) AdjustCustomerPoints(c *gin.Context) {
+
+	if c.GetString("client_type") == "CASSIER" {
+		handleResponse(c, http.StatusForbidden, "cashiers can not adjust points")
+		return
+	}
+
+	var adjust models.AdjustLoyaltyPoints
+	err := c.ShouldBindJSON(&adjust)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	adjust.CustomerID = c.Param("id")
+	if !helpers.IsValidUUID(adjust.CustomerID) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	if adjust.Points == 0 {
+		handleResponse(c, http.StatusBadRequest, "points is required")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	err = h.strg.Loyalty().Adjust(ctx, &adjust)
+	if err == pgx.ErrNoRows || errors.Is(err, storage.ErrNotEnoughPoints) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp, err := h.strg.Customer().GetByID(ctx, &models.CustomerPrimaryKey{Id: adjust.CustomerID})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Repeat customer report
+// @Description Per branch: finished sales, sales with a customer, distinct customers and the share of them who came back.
+// @Tags customer
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param from_date query string false "From date, e.g. 2024-01-01"
+// @Param to_date query string false "To date (exclusive), e.g. 2024-02-01"
+// @Param branch_id query string false "Branch ID"
+// @Success 200 {object} models.RepeatCustomerReportResponse "Repeat customer report"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/customer/report [get]
+func (h *Handler) GetRepeatCustomerReport(c *gin.Context) {
+
+	var branchID = c.Query("branch_id")
+	if branchID != "" && !helpers.IsValidUUID(branchID) {
+		handleResponse(c, http.StatusBadRequest, "branch id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.Loyalty().RepeatCustomerReport(ctx, &models.RepeatCustomerReportRequest{
+		FromDate: c.Query("from_date"),
+		ToDate:   c.Query("to_date"),
+		BranchID: branchID,
+	})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
@@ -0,0 +1,489 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/export"
+	"market_system/pkg/helpers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+)
+
+// exportKey is the context key of the exportSink of a request exported to a
+// file.
+const exportKey = "export"
+
+// exportSink takes the response of a request exported to a file in place of
+// handleResponse writing it as JSON.
+type exportSink struct {
+	format     string
+	lang       string
+	title      string
+	background bool
+	status     int
+	data       interface{}
+}
+
+// exportSinkOf returns the sink of a request exported to a file, nil for
+// other requests.
+func exportSinkOf(c *gin.Context) *exportSink {
+	value, _ := c.Get(exportKey)
+	sink, _ := value.(*exportSink)
+	return sink
+}
+
+// requestContext bounds the storage calls of a request by config.CtxTimeout,
+// or by config.ExportTimeout when it runs as a background export.
+func requestContext(c *gin.Context) (context.Context, context.CancelFunc) {
+
+	if sink := exportSinkOf(c); sink != nil && sink.background {
+		return context.WithTimeout(context.Background(), config.ExportTimeout)
+	}
+
+	return context.WithTimeout(context.Background(), config.CtxTimeout)
+}
+
+// errExportNotCached is what cachedList returns for an exported request.
+var errExportNotCached = errors.New("exported lists are not cached")
+
+// cachedList reads the cached response of a list request. Exported requests
+// skip the cache both ways: they ask for up to ExportMaxRows rows, too many
+// to keep in Redis.
+func (h *Handler) cachedList(ctx context.Context, c *gin.Context, key string) ([]byte, error) {
+
+	if exportSinkOf(c) != nil {
+		return nil, errExportNotCached
+	}
+
+	return h.cache.GetX(ctx, key)
+}
+
+// cacheList keeps the response of a list request for a short while.
+func (h *Handler) cacheList(ctx context.Context, c *gin.Context, key string, body []byte) {
+
+	if exportSinkOf(c) != nil {
+		return
+	}
+
+	h.cache.SetX(ctx, key, string(body), time.Second*15)
+}
+
+// ExportMiddleware answers GET requests with a format parameter (csv, xlsx or
+// pdf) with a file of the whole result instead of a page of JSON. Headers and
+// numbers follow the lang parameter or the Accept-Language header. Requests
+// with async=true, and requests that run out of time, are exported in the
+// background: they are answered with an export job whose download_url serves
+// the file once it is done.
+func (h *Handler) ExportMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		var query = c.Request.URL.Query()
+		if c.Request.Method != http.MethodGet || len(query.Get("format")) == 0 {
+			c.Next()
+			return
+		}
+
+		var sink = &exportSink{
+			format: query.Get("format"),
+			lang:   export.Language(query.Get("lang")),
+			title:  exportTitle(c),
+		}
+		if len(query.Get("lang")) == 0 {
+			sink.lang = export.Language(c.GetHeader("Accept-Language"))
+		}
+
+		if !helpers.Contains(export.Formats, sink.format) {
+			handleResponse(c, http.StatusBadRequest, "format must be one of "+strings.Join(export.Formats, ", "))
+			c.Abort()
+			return
+		}
+
+		var (
+			async = query.Get("async") == "true"
+			path  = c.Request.URL.RequestURI()
+		)
+
+		query.Del("format")
+		query.Del("lang")
+		query.Del("async")
+		query.Set("offset", "0")
+		query.Set("limit", strconv.Itoa(h.cfg.ExportMaxRows))
+		c.Request.URL.RawQuery = query.Encode()
+
+		if async {
+			h.startExport(c, sink, path)
+			c.Abort()
+			return
+		}
+
+		c.Set(exportKey, sink)
+		c.Next()
+		c.Set(exportKey, nil)
+
+		switch err, _ := sink.data.(error); {
+		case sink.status == 0:
+		case errors.Is(err, context.DeadlineExceeded):
+			h.startExport(c, &exportSink{format: sink.format, lang: sink.lang, title: sink.title}, path)
+		case sink.status >= 400:
+			handleResponse(c, sink.status, sink.data)
+		default:
+			var tables = export.FromData(sink.data)
+			export.Select(tables, listFields(c))
+
+			c.Header("Content-Type", export.ContentType(sink.format))
+			c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", exportFileName(sink)))
+			c.Status(http.StatusOK)
+
+			err = export.Write(c.Writer, sink.format, tables, export.Options{
+				Lang:     sink.lang,
+				Title:    sink.title,
+				FontFile: h.cfg.ExportPDFFont,
+			})
+			if err != nil {
+				log.Println(config.Error, "error while exporting:", path, err)
+			}
+		}
+	}
+}
+
+// exportTitle names an export after its route, "report/sales" for
+// /v1/report/sales.
+func exportTitle(c *gin.Context) string {
+
+	var route = strings.TrimPrefix(c.FullPath(), "/v1/")
+	if len(route) == 0 {
+		route = strings.TrimPrefix(c.Request.URL.Path, "/v1/")
+	}
+
+	return route
+}
+
+func exportFileName(sink *exportSink) string {
+
+	var name = strings.NewReplacer("/", "_", ":", "").Replace(sink.title)
+
+	return name + "_" + time.Now().Format("2006-01-02") + "." + sink.format
+}
+
+// startExport records an export job and runs the request again in the
+// background, answering with the job.
+func (h *Handler) startExport(c *gin.Context, sink *exportSink, path string) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	job, err := h.strg.ExportJob().Create(ctx, &models.CreateExportJob{
+		Format:    sink.format,
+		Lang:      sink.lang,
+		Path:      path,
+		FileName:  exportFileName(sink),
+		CreatedBy: c.GetString("user_id"),
+	})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var background = c.Copy()
+	background.Request = c.Request.Clone(context.Background())
+	background.Set(exportKey, &exportSink{
+		format:     sink.format,
+		lang:       sink.lang,
+		title:      sink.title,
+		background: true,
+	})
+
+	go h.runExport(background, c.Handler(), job.Id)
+
+	handleResponse(c, http.StatusAccepted, exportJobResponse(job))
+}
+
+// runExport runs a request whose response is exported to the file of a job,
+// at most config.ExportWorkers at a time.
+func (h *Handler) runExport(c *gin.Context, handler gin.HandlerFunc, jobID string) {
+
+	h.exports <- struct{}{}
+	defer func() { <-h.exports }()
+
+	var (
+		sink   = exportSinkOf(c)
+		update = models.UpdateExportJob{Id: jobID, Status: config.ExportJobRunning}
+	)
+
+	h.updateExportJob(&update)
+
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+
+		handler(c)
+		if sink.status == 0 || sink.status >= 400 {
+			return fmt.Errorf("%d: %v", sink.status, sink.data)
+		}
+
+		err = os.MkdirAll(h.cfg.ExportDir, 0o755)
+		if err != nil {
+			return err
+		}
+
+		file, err := os.Create(exportFilePath(h.cfg, jobID, sink.format))
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		var tables = export.FromData(sink.data)
+		export.Select(tables, listFields(c))
+		update.Rows = int64(len(tables[0].Rows))
+
+		err = export.Write(file, sink.format, tables, export.Options{
+			Lang:     sink.lang,
+			Title:    sink.title,
+			FontFile: h.cfg.ExportPDFFont,
+		})
+		if err != nil {
+			return err
+		}
+
+		return file.Close()
+	}()
+
+	update.Status = config.ExportJobDone
+	if err != nil {
+		log.Println(config.Error, "error while exporting:", jobID, err)
+		update.Status, update.Error = config.ExportJobFailed, err.Error()
+	}
+
+	h.updateExportJob(&update)
+}
+
+func (h *Handler) updateExportJob(update *models.UpdateExportJob) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	_, err := h.strg.ExportJob().Update(ctx, update)
+	if err != nil {
+		log.Println(config.Error, "error while updating export job:", update.Id, err)
+	}
+}
+
+func exportFilePath(cfg *config.Config, jobID, format string) string {
+	return filepath.Join(cfg.ExportDir, jobID+"."+format)
+}
+
+func exportJobResponse(job *models.ExportJob) *models.ExportJob {
+
+	if job.Status == config.ExportJobDone {
+		job.DownloadURL = "/v1/export/" + job.Id + "/download"
+	}
+
+	return job
+}
+
+// exportJobOf returns the job of the id parameter when the user may see it:
+// their own jobs, and every job for a super admin.
+func (h *Handler) exportJobOf(c *gin.Context) (*models.ExportJob, bool) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return nil, false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	job, err := h.strg.ExportJob().GetByID(ctx, &models.ExportJobPrimaryKey{Id: id})
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "no rows in result set")
+		return nil, false
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return nil, false
+	}
+
+	if job.CreatedBy != c.GetString("user_id") && c.GetString("client_type") != "SUPER-ADMIN" {
+		handleResponse(c, http.StatusForbidden, "export job belongs to another user")
+		return nil, false
+	}
+
+	return job, true
+}
+
+// @Summary Get export job by ID
+// @Description Get a background export. download_url is set once its file is ready.
+// @Tags export
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Export job ID"
+// @Success 200 {object} models.ExportJob "Export job"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/export/{id} [get]
+func (h *Handler) GetByIDExportJob(c *gin.Context) {
+
+	job, ok := h.exportJobOf(c)
+	if !ok {
+		return
+	}
+
+	handleResponse(c, http.StatusOK, exportJobResponse(job))
+}
+
+// @Summary Get a list of export jobs
+// @Description Get the background exports of the user, latest first. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, status, format, lang, path, file_name, rows, created_at, finished_at.
+// @Tags export
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param limit query int false "Number of items to return (default 10)"
+// @Param offset query int false "Number of items to skip (default 0)"
+// @Param status[eq] query string false "Status (pending, running, done, failed)"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
+// @Success 200 {object} models.GetListExportJobResponse "List of export jobs"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/export [get]
+func (h *Handler) GetListExportJob(c *gin.Context) {
+
+	limit, err := getIntegerOrDefaultValue(c.Query("limit"), 10)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query limit")
+		return
+	}
+
+	offset, err := getIntegerOrDefaultValue(c.Query("offset"), 0)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query offset")
+		return
+	}
+
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.ExportJob().GetList(ctx, &models.GetListExportJobRequest{
+		Limit:     limit,
+		Offset:    offset,
+		Filters:   list.Filters,
+		Sort:      list.Sort,
+		CreatedBy: c.GetString("user_id"),
+	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	for _, job := range resp.ExportJobs {
+		exportJobResponse(job)
+	}
+
+	handleListResponse(c, resp, list.Fields)
+}
+
+// @Summary Download an export
+// @Description Download the file of a finished background export.
+// @Tags export
+// @Produce application/octet-stream
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Export job ID"
+// @Success 200 {file} file "Export file"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/export/{id}/download [get]
+func (h *Handler) DownloadExportJob(c *gin.Context) {
+
+	job, ok := h.exportJobOf(c)
+	if !ok {
+		return
+	}
+
+	if job.Status != config.ExportJobDone {
+		handleResponse(c, http.StatusBadRequest, "export is "+job.Status)
+		return
+	}
+
+	c.Header("Content-Type", export.ContentType(job.Format))
+	c.FileAttachment(exportFilePath(h.cfg, job.Id, job.Format), job.FileName)
+}
+
+// @Summary Delete export job
+// @Description Delete a background export and its file.
+// @Tags export
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Export job ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/export/{id} [delete]
+func (h *Handler) DeleteExportJob(c *gin.Context) {
+
+	job, ok := h.exportJobOf(c)
+	if !ok {
+		return
+	}
+
+	if job.Status == config.ExportJobPending || job.Status == config.ExportJobRunning {
+		handleResponse(c, http.StatusBadRequest, "export is still "+job.Status)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	err := h.strg.ExportJob().Delete(ctx, &models.ExportJobPrimaryKey{Id: job.Id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = os.Remove(exportFilePath(h.cfg, job.Id, job.Format))
+	if err != nil && !os.IsNotExist(err) {
+		log.Println(config.Error, "error while removing export file:", job.Id, err)
+	}
+
+	handleResponse(c, http.StatusNoContent, nil)
+}
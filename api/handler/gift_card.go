@@ -0,0 +1,322 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/giftcard"
+	"market_system/pkg/helpers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+)
+
+// @Summary Sell a gift card
+// @Description Add a gift card line to an open sale. The card gets a printable EAN-13 code and becomes usable when the sale is finished.
+// @Tags gift_card
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param gift_card body models.SellGiftCard true "Sale, amount and expiry"
+// @Success 201 {object} models.GiftCard "Pending gift card"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/gift_card/sell [post]
+func (h *Handler) SellGiftCard(c *gin.Context) {
+
+	var sellGiftCard models.SellGiftCard
+	err := c.ShouldBindJSON(&sellGiftCard)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	if !helpers.IsValidUUID(sellGiftCard.SaleID) {
+		handleResponse(c, http.StatusBadRequest, "sale id is not uuid")
+		return
+	}
+
+	if sellGiftCard.CustomerID != "" && !helpers.IsValidUUID(sellGiftCard.CustomerID) {
+		handleResponse(c, http.StatusBadRequest, "customer id is not uuid")
+		return
+	}
+
+	if sellGiftCard.Amount <= 0 {
+		handleResponse(c, http.StatusBadRequest, "amount must be positive")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	resp, err := h.strg.GiftCard().Sell(ctx, &sellGiftCard)
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "sale not found or already finished")
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusCreated, resp)
+}
+
+// @Summary Issue store credit
+// @Description Issue store credit, e.g. to refund a return to credit instead of cash. Not allowed for cashiers.
+// @Tags gift_card
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param store_credit body models.CreateStoreCredit true "Store credit information"
+// @Success 201 {object} models.GiftCard "Store credit"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/gift_card/store_credit [post]
+func (h *Handler) CreateStoreCredit(c *gin.Context) {
+
+	if c.GetString("client_type") == "CASSIER" {
+		handleResponse(c, http.StatusForbidden, "cashiers can not issue store credit")
+		return
+	}
+
+	var createStoreCredit models.CreateStoreCredit
+	err := c.ShouldBindJSON(&createStoreCredit)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	if createStoreCredit.CustomerID != "" && !helpers.IsValidUUID(createStoreCredit.CustomerID) {
+		handleResponse(c, http.StatusBadRequest, "customer id is not uuid")
+		return
+	}
+
+	if createStoreCredit.SaleID != "" && !helpers.IsValidUUID(createStoreCredit.SaleID) {
+		handleResponse(c, http.StatusBadRequest, "sale id is not uuid")
+		return
+	}
+
+	if createStoreCredit.Amount <= 0 {
+		handleResponse(c, http.StatusBadRequest, "amount must be positive")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	resp, err := h.strg.GiftCard().CreateStoreCredit(ctx, &createStoreCredit)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusCreated, resp)
+}
+
+// @Summary Get a gift card by ID
+// @Description Get gift card or store credit details.
+// @Tags gift_card
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Gift card ID"
+// @Success 200 {object} models.GiftCard "Gift card details"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/gift_card/{id} [get]
+func (h *Handler) GetByIDGiftCard(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.GiftCard().GetByID(ctx, &models.GiftCardPrimaryKey{Id: id})
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "no rows in result set")
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Gift card balance
+// @Description Look up the balance, status and expiry of a card by its code.
+// @Tags gift_card
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param code path string true "Gift card code"
+// @Success 200 {object} models.GiftCard "Gift card"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/gift_card/balance/{code} [get]
+func (h *Handler) GetGiftCardBalance(c *gin.Context) {
+
+	var code = c.Param("code")
+	if !giftcard.ValidCode(code) {
+		handleResponse(c, http.StatusBadRequest, "code is not valid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.GiftCard().GetByCode(ctx, &models.GiftCardCode{Code: code})
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "Сертификат не найден")
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Get a list of gift cards
+// @Description Get gift cards and store credit, newest first. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, code, type, status, initial_amount, balance, customer_id, sale_id, expires_at, created_at, updated_at.
+// @Tags gift_card
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param limit query int false "Number of items to return (default 10)"
+// @Param offset query int false "Number of items to skip (default 0)"
+// @Param code[eq] query string false "Card code"
+// @Param type[eq] query string false "gift_card or store_credit"
+// @Param status[eq] query string false "pending, active or canceled"
+// @Param customer_id[eq] query string false "Customer ID"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
+// @Success 200 {object} models.GetListGiftCardResponse "List of gift cards"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/gift_card [get]
+func (h *Handler) GetListGiftCard(c *gin.Context) {
+
+	limit, err := getIntegerOrDefaultValue(c.Query("limit"), 10)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query limit")
+		return
+	}
+
+	offset, err := getIntegerOrDefaultValue(c.Query("offset"), 0)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query offset")
+		return
+	}
+
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.GiftCard().GetList(ctx, &models.GetListGiftCardRequest{
+		Limit:   limit,
+		Offset:  offset,
+		Filters: list.Filters,
+		Sort:    list.Sort,
+	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleListResponse(c, resp, list.Fields)
+}
+
+// @Summary Gift card history
+// @Description Issue and redemption history of a card.
+// @Tags gift_card
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Gift card ID"
+// @Success 200 {object} models.GiftCardHistoryResponse "Card history"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/gift_card/{id}/history [get]
+func (h *Handler) GetGiftCardHistory(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.GiftCard().History(ctx, &models.GiftCardPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Gift card liability report
+// @Description What the store owes on gift cards and store credit as of a moment: issued, redeemed, outstanding and expired balances per type.
+// @Tags gift_card
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param at query string false "As of, e.g. 2024-01-31 23:59:59 (default now)"
+// @Success 200 {object} models.GiftCardLiabilityResponse "Liability report"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/gift_card/report [get]
+func (h *Handler) GetGiftCardLiabilityReport(c *gin.Context) {
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.GiftCard().LiabilityReport(ctx, &models.GiftCardLiabilityRequest{At: c.Query("at")})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
@@ -1,10 +1,14 @@
 package handler
 
 import (
+	"errors"
 	"log"
+	"net/http"
 	"strconv"
+	"strings"
 
 	"market_system/config"
+	"market_system/pkg/criteria"
 	"market_system/storage"
 	"market_system/storage/redis"
 
@@ -12,9 +16,10 @@ import (
 )
 
 type Handler struct {
-	cfg   *config.Config
-	strg  storage.StorageI
-	cache *redis.Cache
+	cfg     *config.Config
+	strg    storage.StorageI
+	cache   *redis.Cache
+	exports chan struct{}
 }
 
 type ErrorResponse struct {
@@ -31,9 +36,10 @@ type Response struct {
 
 func NewHandler(cfg *config.Config, strg storage.StorageI, cache *redis.Cache) *Handler {
 	return &Handler{
-		cfg:   cfg,
-		strg:  strg,
-		cache: cache,
+		cfg:     cfg,
+		strg:    strg,
+		cache:   cache,
+		exports: make(chan struct{}, config.ExportWorkers),
 	}
 }
 
@@ -47,7 +53,21 @@ func getIntegerOrDefaultValue(value string, defaultValue int64) (int64, error) {
 	return int64(number), err
 }
 
+func getFloatOrDefaultValue(value string, defaultValue float64) (float64, error) {
+
+	if len(value) <= 0 {
+		return defaultValue, nil
+	}
+
+	return strconv.ParseFloat(value, 64)
+}
+
 func handleResponse(c *gin.Context, status int, data interface{}) {
+	if sink := exportSinkOf(c); sink != nil {
+		sink.status, sink.data = status, data
+		return
+	}
+
 	var description string
 	switch code := status; {
 	case code < 400:
@@ -71,3 +91,33 @@ func handleResponse(c *gin.Context, status int, data interface{}) {
 		Data:        data,
 	})
 }
+
+// handleListResponse responds with a list, its items cut down to the fields
+// the request selected. Exports get the whole list and pick their columns
+// from it.
+func handleListResponse(c *gin.Context, resp interface{}, fields []string) {
+
+	selected, err := criteria.Select(resp, fields)
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if exportSinkOf(c) != nil {
+		selected = resp
+	}
+
+	handleResponse(c, http.StatusOK, selected)
+}
+
+// listFields returns the fields a list request selected with fields=.
+func listFields(c *gin.Context) []string {
+	return strings.FieldsFunc(c.Query("fields"), func(r rune) bool {
+		return r == ',' || r == ' '
+	})
+}
@@ -4,19 +4,21 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"time"
 
 	"market_system/config"
 	"market_system/models"
+	"market_system/pkg/criteria"
 	"market_system/pkg/helpers"
+	"market_system/storage"
 
 	"github.com/gin-gonic/gin"
 )
 
 // @Summary Create a new income
-// @Description Create a new income in the market system.
+// @Description Create a new income in the market system. It can not be created finished; posting it with /v1/doincome finishes it.
 // @Tags income
 // @Accept json
 // @Produce json
@@ -50,6 +52,10 @@ func (h *Handler) CreateIncome(c *gin.Context) {
 	// }
 
 	resp, err := h.strg.Income().Create(ctx, &createIncome)
+	if errors.Is(err, storage.ErrIncomeFinishedStatus) {
+		handleResponse(c, http.StatusBadRequest, "status finished is set by posting the income with /v1/doincome")
+		return
+	}
 	if err != nil {
 		handleResponse(c, http.StatusInternalServerError, err)
 		return
@@ -80,7 +86,7 @@ func (h *Handler) GetByIDIncome(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	ctx, cancel := requestContext(c)
 	defer cancel()
 
 	resp, err := h.strg.Income().GetByID(ctx, &models.IncomePrimaryKey{Id: id})
@@ -98,7 +104,7 @@ func (h *Handler) GetByIDIncome(c *gin.Context) {
 }
 
 // @Summary Get a list of incomes
-// @Description Get a list of incomes with optional filtering.
+// @Description Get a list of incomes with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, branch_id, supplier_id, date_time, status, purchase_order_id, created_at, updated_at.
 // @Tags income
 // @Accept json
 // @Produce json
@@ -107,6 +113,9 @@ func (h *Handler) GetByIDIncome(c *gin.Context) {
 // @Param limit query int false "Number of items to return (default 10)"
 // @Param offset query int false "Number of items to skip (default 0)"
 // @Param search query string false "Search term"
+// @Param created_at[between] query string false "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
 // @Success 200 {array} models.Income "List of incomes"
 // @Failure 400 {object} ErrorResponse "Bad Request"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
@@ -132,7 +141,13 @@ func (h *Handler) GetListIncome(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
 	defer cancel()
 
 	var (
@@ -140,7 +155,7 @@ func (h *Handler) GetListIncome(c *gin.Context) {
 		resp = &models.GetListIncomeResponse{}
 	)
 
-	body, err := h.cache.GetX(ctx, key)
+	body, err := h.cachedList(ctx, c, key)
 	if err == nil {
 		err = json.Unmarshal(body, &resp)
 		if err != nil {
@@ -151,10 +166,17 @@ func (h *Handler) GetListIncome(c *gin.Context) {
 
 	if len(resp.Incomes) <= 0 {
 		resp, err = h.strg.Income().GetList(ctx, &models.GetListIncomeRequest{
-			Limit:  limit,
-			Offset: offset,
-			Search: search,
+			Limit:   limit,
+			Offset:  offset,
+			Search:  search,
+			Filters: list.Filters,
+			Sort:    list.Sort,
 		})
+		var criteriaErr *criteria.Error
+		if errors.As(err, &criteriaErr) {
+			handleResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		if err != nil {
 			handleResponse(c, http.StatusInternalServerError, err)
 			return
@@ -166,14 +188,14 @@ func (h *Handler) GetListIncome(c *gin.Context) {
 			return
 		}
 
-		h.cache.SetX(ctx, key, string(body), time.Second*15)
+		h.cacheList(ctx, c, key, body)
 	}
 
-	handleResponse(c, http.StatusOK, resp)
+	handleListResponse(c, resp, list.Fields)
 }
 
 // @Summary Update an income
-// @Description Update an existing income.
+// @Description Update an income that is not finished yet; a finished income can not be changed and status finished is set only by posting it with /v1/doincome.
 // @Tags income
 // @Accept json
 // @Produce json
@@ -207,6 +229,14 @@ func (h *Handler) UpdateIncome(c *gin.Context) {
 	defer cancel()
 
 	rowsAffected, err := h.strg.Income().Update(ctx, &updateIncome)
+	if errors.Is(err, storage.ErrIncomeFinished) {
+		handleResponse(c, http.StatusBadRequest, "income is finished")
+		return
+	}
+	if errors.Is(err, storage.ErrIncomeFinishedStatus) {
+		handleResponse(c, http.StatusBadRequest, "status finished is set by posting the income with /v1/doincome")
+		return
+	}
 	if err != nil {
 		handleResponse(c, http.StatusInternalServerError, err)
 		return
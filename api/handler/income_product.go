@@ -4,12 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"time"
 
 	"market_system/config"
 	"market_system/models"
+	"market_system/pkg/criteria"
 	"market_system/pkg/helpers"
 
 	"github.com/gin-gonic/gin"
@@ -37,6 +38,11 @@ func (h *Handler) CreateIncomeProduct(c *gin.Context) {
 		return
 	}
 
+	if createIncomeProduct.ExpiryDate != "" && !helpers.IsValidDate(createIncomeProduct.ExpiryDate) {
+		handleResponse(c, http.StatusBadRequest, "expiry date must be YYYY-MM-DD")
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
 	defer cancel()
 
@@ -70,7 +76,7 @@ func (h *Handler) GetByIDIncomeProduct(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	ctx, cancel := requestContext(c)
 	defer cancel()
 
 	resp, err := h.strg.IncomeProduct().GetByID(ctx, &models.IncomeProductPrimaryKey{Id: id})
@@ -88,7 +94,7 @@ func (h *Handler) GetByIDIncomeProduct(c *gin.Context) {
 }
 
 // @Summary Get a list of income products
-// @Description Get a list of income products with optional filtering.
+// @Description Get a list of income products with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, income_id, category_id, product_name, barcode, quantity, income_price, purchase_order_product_id, lot_number, expiry_date, created_at, updated_at.
 // @Tags income_product
 // @Accept json
 // @Produce json
@@ -97,6 +103,9 @@ func (h *Handler) GetByIDIncomeProduct(c *gin.Context) {
 // @Param limit query int false "Number of items to return (default 10)"
 // @Param offset query int false "Number of items to skip (default 0)"
 // @Param search query string false "Search term"
+// @Param created_at[between] query string false "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
 // @Success 200 {array} models.IncomeProduct "List of income products"
 // @Failure 400 {object} ErrorResponse "Bad Request"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
@@ -121,7 +130,13 @@ func (h *Handler) GetListIncomeProduct(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
 	defer cancel()
 
 	var (
@@ -129,7 +144,7 @@ func (h *Handler) GetListIncomeProduct(c *gin.Context) {
 		resp = &models.GetListIncomeProductResponse{}
 	)
 
-	body, err := h.cache.GetX(ctx, key)
+	body, err := h.cachedList(ctx, c, key)
 	if err == nil {
 		err = json.Unmarshal(body, &resp)
 		if err != nil {
@@ -140,10 +155,17 @@ func (h *Handler) GetListIncomeProduct(c *gin.Context) {
 
 	if len(resp.IncomeProducts) <= 0 {
 		resp, err = h.strg.IncomeProduct().GetList(ctx, &models.GetListIncomeProductRequest{
-			Limit:  limit,
-			Offset: offset,
-			Search: search,
+			Limit:   limit,
+			Offset:  offset,
+			Search:  search,
+			Filters: list.Filters,
+			Sort:    list.Sort,
 		})
+		var criteriaErr *criteria.Error
+		if errors.As(err, &criteriaErr) {
+			handleResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		if err != nil {
 			handleResponse(c, http.StatusInternalServerError, err)
 			return
@@ -155,10 +177,10 @@ func (h *Handler) GetListIncomeProduct(c *gin.Context) {
 			return
 		}
 
-		h.cache.SetX(ctx, key, string(body), time.Second*15)
+		h.cacheList(ctx, c, key, body)
 	}
 
-	handleResponse(c, http.StatusOK, resp)
+	handleListResponse(c, resp, list.Fields)
 
 }
 
@@ -191,6 +213,11 @@ func (h *Handler) UpdateIncomeProduct(c *gin.Context) {
 		return
 	}
 
+	if updateIncomeProduct.ExpiryDate != "" && !helpers.IsValidDate(updateIncomeProduct.ExpiryDate) {
+		handleResponse(c, http.StatusBadRequest, "expiry date must be YYYY-MM-DD")
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
 	defer cancel()
 
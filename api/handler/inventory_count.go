@@ -0,0 +1,337 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+	"market_system/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+)
+
+// @Summary Open an inventory count
+// @Description Open a physical count of a branch, or of one category in it. Expected quantities are snapshotted from the branch remainder.
+// @Tags inventory_count
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param inventory_count body models.CreateInventoryCount true "Inventory count information"
+// @Success 201 {object} models.InventoryCount "Opened inventory count"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/inventory_count [post]
+func (h *Handler) CreateInventoryCount(c *gin.Context) {
+
+	var createInventoryCount models.CreateInventoryCount
+	err := c.ShouldBindJSON(&createInventoryCount)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	if !helpers.IsValidUUID(createInventoryCount.BranchID) {
+		handleResponse(c, http.StatusBadRequest, "branch id is not uuid")
+		return
+	}
+
+	if createInventoryCount.CategoryID != "" && !helpers.IsValidUUID(createInventoryCount.CategoryID) {
+		handleResponse(c, http.StatusBadRequest, "category id is not uuid")
+		return
+	}
+	createInventoryCount.CreatedBy = c.GetString("user_id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	resp, err := h.strg.InventoryCount().Create(ctx, &createInventoryCount)
+	if errors.Is(err, storage.ErrInventoryCountInProgress) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusCreated, resp)
+}
+
+// @Summary Get an inventory count by ID
+// @Description Get a count with expected, counted and book quantity, variance and cost impact per barcode.
+// @Tags inventory_count
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Inventory count ID"
+// @Success 200 {object} models.InventoryCount "Inventory count details"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/inventory_count/{id} [get]
+func (h *Handler) GetByIDInventoryCount(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.InventoryCount().GetByID(ctx, &models.InventoryCountPrimaryKey{Id: id})
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "no rows in result set")
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Get a list of inventory counts
+// @Description Get a list of inventory counts. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, branch_id, category_id, status, created_by, snapshot_at, posted_by, posted_at, created_at, updated_at.
+// @Tags inventory_count
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param limit query int false "Number of items to return (default 10)"
+// @Param offset query int false "Number of items to skip (default 0)"
+// @Param branch_id[eq] query string false "Branch ID"
+// @Param status[eq] query string false "open, posted or canceled"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
+// @Success 200 {object} models.GetListInventoryCountResponse "List of inventory counts"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/inventory_count [get]
+func (h *Handler) GetListInventoryCount(c *gin.Context) {
+
+	limit, err := getIntegerOrDefaultValue(c.Query("limit"), 10)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query limit")
+		return
+	}
+
+	offset, err := getIntegerOrDefaultValue(c.Query("offset"), 0)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query offset")
+		return
+	}
+
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.InventoryCount().GetList(ctx, &models.GetListInventoryCountRequest{
+		Limit:   limit,
+		Offset:  offset,
+		Filters: list.Filters,
+		Sort:    list.Sort,
+	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleListResponse(c, resp, list.Fields)
+}
+
+// @Summary Scan into an inventory count
+// @Description Add counted quantities to an open count. Several counters may scan at the same time; their scans are summed per barcode. A negative quantity corrects an earlier scan.
+// @Tags inventory_count
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Inventory count ID"
+// @Param scan body models.ScanInventoryCount true "Scanned quantities"
+// @Success 202 {object} models.InventoryCount "Inventory count"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/inventory_count/{id}/scan [post]
+func (h *Handler) ScanInventoryCount(c *gin.Context) {
+
+	var scan models.ScanInventoryCount
+	err := c.ShouldBindJSON(&scan)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	if len(scan.Products) <= 0 {
+		handleResponse(c, http.StatusBadRequest, "products are required")
+		return
+	}
+
+	for _, product := range scan.Products {
+		if product.Barcode == "" {
+			handleResponse(c, http.StatusBadRequest, "barcode is required")
+			return
+		}
+
+		if product.Quantity == 0 {
+			handleResponse(c, http.StatusBadRequest, "quantity can not be zero")
+			return
+		}
+	}
+	scan.Id = id
+	scan.CountedBy = c.GetString("user_id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	rowsAffected, err := h.strg.InventoryCount().Scan(ctx, &scan)
+	if errors.Is(err, pgx.ErrNoRows) || errors.Is(err, storage.ErrNegativeCount) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "inventory count not found or not open")
+		return
+	}
+
+	resp, err := h.strg.InventoryCount().GetByID(ctx, &models.InventoryCountPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
+
+// @Summary Post an inventory count
+// @Description Freeze the variance of an open count and adjust the branch stock by it. Barcodes nobody counted are taken as counted zero.
+// @Tags inventory_count
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Inventory count ID"
+// @Success 202 {object} models.InventoryCount "Posted inventory count"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/inventory_count/{id}/post [post]
+func (h *Handler) PostInventoryCount(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	if c.GetString("client_type") == "CASSIER" {
+		handleResponse(c, http.StatusForbidden, "cashier can not post inventory counts")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	rowsAffected, err := h.strg.InventoryCount().Post(ctx, &models.PostInventoryCount{
+		Id:       id,
+		PostedBy: c.GetString("user_id"),
+	})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "inventory count not found or not open")
+		return
+	}
+
+	resp, err := h.strg.InventoryCount().GetByID(ctx, &models.InventoryCountPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
+
+// @Summary Cancel an inventory count
+// @Description Cancel an open count without touching stock.
+// @Tags inventory_count
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Inventory count ID"
+// @Success 202 {object} models.InventoryCount "Canceled inventory count"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/inventory_count/{id}/cancel [post]
+func (h *Handler) CancelInventoryCount(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	rowsAffected, err := h.strg.InventoryCount().Cancel(ctx, &models.InventoryCountPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "inventory count not found or not open")
+		return
+	}
+
+	resp, err := h.strg.InventoryCount().GetByID(ctx, &models.InventoryCountPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
@@ -0,0 +1,396 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+)
+
+// @Summary Create a loyalty tier
+// @Description Create a tier. Customers reach it once their total spent is at least min_spent; earn_multiplier scales the points they earn.
+// @Tags loyalty
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param tier body models.CreateLoyaltyTier true "Tier information"
+// @Success 201 {object} models.LoyaltyTier "Created tier"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/loyalty_tier [post]
+func (h *Handler) CreateLoyaltyTier(c *gin.Context) {
+
+	var createTier models.CreateLoyaltyTier
+	err := c.ShouldBindJSON(&createTier)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	if msg := validateLoyaltyTier(createTier.Name, createTier.MinSpent, createTier.EarnMultiplier); msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	resp, err := h.strg.LoyaltyTier().Create(ctx, &createTier)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusCreated, resp)
+}
+
+// @Summary Get a loyalty tier by ID
+// @Description Get loyalty tier details.
+// @Tags loyalty
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Tier ID"
+// @Success 200 {object} models.LoyaltyTier "Tier details"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/loyalty_tier/{id} [get]
+func (h *Handler) GetByIDLoyaltyTier(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.LoyaltyTier().GetByID(ctx, &models.LoyaltyTierPrimaryKey{Id: id})
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "no rows in result set")
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Get a list of loyalty tiers
+// @Description Get loyalty tiers ordered by threshold. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, name, min_spent, earn_multiplier, created_at, updated_at.
+// @Tags loyalty
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param limit query int false "Number of items to return (default 10)"
+// @Param offset query int false "Number of items to skip (default 0)"
+// @Param search query string false "Search by name"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
+// @Success 200 {object} models.GetListLoyaltyTierResponse "List of tiers"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/loyalty_tier [get]
+func (h *Handler) GetListLoyaltyTier(c *gin.Context) {
+
+	limit, err := getIntegerOrDefaultValue(c.Query("limit"), 10)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query limit")
+		return
+	}
+
+	offset, err := getIntegerOrDefaultValue(c.Query("offset"), 0)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query offset")
+		return
+	}
+
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.LoyaltyTier().GetList(ctx, &models.GetListLoyaltyTierRequest{
+		Limit:   limit,
+		Offset:  offset,
+		Search:  c.Query("search"),
+		Filters: list.Filters,
+		Sort:    list.Sort,
+	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleListResponse(c, resp, list.Fields)
+}
+
+// @Summary Update a loyalty tier
+// @Description Update a tier. Customers are moved to the tiers matching the new thresholds.
+// @Tags loyalty
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Tier ID"
+// @Param tier body models.UpdateLoyaltyTier true "Updated tier information"
+// @Success 202 {object} models.LoyaltyTier "Updated tier"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/loyalty_tier/{id} [put]
+func (h *Handler) UpdateLoyaltyTier(c *gin.Context) {
+
+	var updateTier models.UpdateLoyaltyTier
+
+	err := c.ShouldBindJSON(&updateTier)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+	updateTier.Id = id
+
+	if msg := validateLoyaltyTier(updateTier.Name, updateTier.MinSpent, updateTier.EarnMultiplier); msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	rowsAffected, err := h.strg.LoyaltyTier().Update(ctx, &updateTier)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "no rows affected")
+		return
+	}
+
+	resp, err := h.strg.LoyaltyTier().GetByID(ctx, &models.LoyaltyTierPrimaryKey{Id: updateTier.Id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
+
+// @Summary Delete a loyalty tier
+// @Description Delete a tier. Its customers fall back to the next lower tier.
+// @Tags loyalty
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Tier ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/loyalty_tier/{id} [delete]
+func (h *Handler) DeleteLoyaltyTier(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	err := h.strg.LoyaltyTier().Delete(ctx, &models.LoyaltyTierPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusNoContent, nil)
+}
+
+// @Summary Set a loyalty earn rate
+// @Description Set the percent of the line total earned as points for a category. Without category_id the default rate is set.
+// @Tags loyalty
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param earn_rate body models.SetLoyaltyEarnRate true "Earn rate"
+// @Success 200 {object} models.LoyaltyEarnRate "Earn rate"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/loyalty_earn_rate [put]
+func (h *Handler) SetLoyaltyEarnRate(c *gin.Context) {
+
+	var setEarnRate models.SetLoyaltyEarnRate
+	err := c.ShouldBindJSON(&setEarnRate)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	if setEarnRate.CategoryID != "" && !helpers.IsValidUUID(setEarnRate.CategoryID) {
+		handleResponse(c, http.StatusBadRequest, "category id is not uuid")
+		return
+	}
+
+	if setEarnRate.Percent < 0 || setEarnRate.Percent > 100 {
+		handleResponse(c, http.StatusBadRequest, "percent must be between 0 and 100")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	resp, err := h.strg.Loyalty().SetEarnRate(ctx, &setEarnRate)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Get loyalty earn rates
+// @Description Get the default earn rate and the per category rates, the default rate first. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, category_id, category_name, is_default, percent, created_at, updated_at.
+// @Tags loyalty
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param limit query int false "Number of items to return (default 10)"
+// @Param offset query int false "Number of items to skip (default 0)"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
+// @Success 200 {object} models.GetListLoyaltyEarnRateResponse "Earn rates"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/loyalty_earn_rate [get]
+func (h *Handler) GetListLoyaltyEarnRate(c *gin.Context) {
+
+	limit, err := getIntegerOrDefaultValue(c.Query("limit"), 10)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query limit")
+		return
+	}
+
+	offset, err := getIntegerOrDefaultValue(c.Query("offset"), 0)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query offset")
+		return
+	}
+
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.Loyalty().GetListEarnRate(ctx, &models.GetListLoyaltyEarnRateRequest{
+		Limit:   limit,
+		Offset:  offset,
+		Filters: list.Filters,
+		Sort:    list.Sort,
+	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleListResponse(c, resp, list.Fields)
+}
+
+// @Summary Delete a loyalty earn rate
+// @Description Delete an earn rate. The category falls back to the default rate.
+// @Tags loyalty
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Earn rate ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/loyalty_earn_rate/{id} [delete]
+func (h *Handler) DeleteLoyaltyEarnRate(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	err := h.strg.Loyalty().DeleteEarnRate(ctx, &models.LoyaltyEarnRatePrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusNoContent, nil)
+}
+
+func validateLoyaltyTier(name string, minSpent, earnMultiplier float64) string {
+
+	if name == "" {
+		return "name is required"
+	}
+
+	if minSpent < 0 {
+		return "min_spent can not be negative"
+	}
+
+	if earnMultiplier <= 0 {
+		return "earn_multiplier must be positive"
+	}
+
+	return ""
+}
@@ -3,10 +3,13 @@ package handler
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"net/http"
 
 	"market_system/config"
 	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/giftcard"
 	"market_system/pkg/helpers"
 
 	"github.com/gin-gonic/gin"
@@ -34,6 +37,11 @@ func (h *Handler) CreatePayment(c *gin.Context) {
 		return
 	}
 
+	if createPayment.GiftCard > 0 && !giftcard.ValidCode(createPayment.GiftCardCode) {
+		handleResponse(c, http.StatusBadRequest, "gift card code is not valid")
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
 	defer cancel()
 
@@ -68,7 +76,7 @@ func (h *Handler) GetByIDPayment(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	ctx, cancel := requestContext(c)
 	defer cancel()
 
 	resp, err := h.strg.Payment().GetByID(ctx, &models.PaymentPrimaryKey{Id: id})
@@ -86,7 +94,7 @@ func (h *Handler) GetByIDPayment(c *gin.Context) {
 }
 
 // @Summary Get a list of payments
-// @Description Get a list of payments with optional filtering.
+// @Description Get a list of payments with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, sale_id, cash, uzcard, payme, click, humo, apelsin, points, gift_card, gift_card_code, total_amount, created_at, updated_at.
 // @Tags payment
 // @Accept json
 // @Produce json
@@ -95,6 +103,9 @@ func (h *Handler) GetByIDPayment(c *gin.Context) {
 // @Param limit query int false "Number of items to return (default 10)"
 // @Param offset query int false "Number of items to skip (default 0)"
 // @Param search query string false "Search term"
+// @Param created_at[between] query string false "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
 // @Success 200 {array} models.Payment "List of payments"
 // @Failure 400 {object} ErrorResponse "Bad Request"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
@@ -120,20 +131,33 @@ func (h *Handler) GetListPayment(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
 	defer cancel()
 
 	resp, err := h.strg.Payment().GetList(ctx, &models.GetListPaymentRequest{
-		Limit:  limit,
-		Offset: offset,
-		Search: search,
+		Limit:   limit,
+		Offset:  offset,
+		Search:  search,
+		Filters: list.Filters,
+		Sort:    list.Sort,
 	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		handleResponse(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	handleResponse(c, http.StatusOK, resp)
+	handleListResponse(c, resp, list.Fields)
 }
 
 // @Summary Update a payment
@@ -168,6 +192,11 @@ func (h *Handler) UpdatePayment(c *gin.Context) {
 	}
 	updatePayment.Id = id
 
+	if updatePayment.GiftCard > 0 && !giftcard.ValidCode(updatePayment.GiftCardCode) {
+		handleResponse(c, http.StatusBadRequest, "gift card code is not valid")
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
 	defer cancel()
 
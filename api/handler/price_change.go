@@ -0,0 +1,365 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+)
+
+// @Summary Create a price change
+// @Description Create a draft price change document for a branch. Prices apply from effective_from once approved.
+// @Tags price_change
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param price_change body models.CreatePriceChange true "Price change information"
+// @Success 201 {object} models.PriceChange "Created price change"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/price_change [post]
+func (h *Handler) CreatePriceChange(c *gin.Context) {
+
+	var createPriceChange models.CreatePriceChange
+	err := c.ShouldBindJSON(&createPriceChange)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	if !helpers.IsValidUUID(createPriceChange.BranchID) {
+		handleResponse(c, http.StatusBadRequest, "branch id is not uuid")
+		return
+	}
+
+	if msg := validatePriceChangeProducts(createPriceChange.Products); msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	resp, err := h.strg.PriceChange().Create(ctx, &createPriceChange)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusCreated, resp)
+}
+
+// @Summary Get a price change by ID
+// @Description Get a price change document with its lines.
+// @Tags price_change
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Price change ID"
+// @Success 200 {object} models.PriceChange "Price change details"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/price_change/{id} [get]
+func (h *Handler) GetByIDPriceChange(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.PriceChange().GetByID(ctx, &models.PriceChangePrimaryKey{Id: id})
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "no rows in result set")
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Get a list of price changes
+// @Description Get a list of price change documents. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, branch_id, effective_from, status, approved_by, approved_at, created_at, updated_at.
+// @Tags price_change
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param limit query int false "Number of items to return (default 10)"
+// @Param offset query int false "Number of items to skip (default 0)"
+// @Param branch_id[eq] query string false "Branch ID"
+// @Param status[eq] query string false "draft, approved or canceled"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
+// @Success 200 {object} models.GetListPriceChangeResponse "List of price changes"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/price_change [get]
+func (h *Handler) GetListPriceChange(c *gin.Context) {
+
+	limit, err := getIntegerOrDefaultValue(c.Query("limit"), 10)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query limit")
+		return
+	}
+
+	offset, err := getIntegerOrDefaultValue(c.Query("offset"), 0)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query offset")
+		return
+	}
+
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.PriceChange().GetList(ctx, &models.GetListPriceChangeRequest{
+		Limit:   limit,
+		Offset:  offset,
+		Filters: list.Filters,
+		Sort:    list.Sort,
+	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleListResponse(c, resp, list.Fields)
+}
+
+// @Summary Update a price change
+// @Description Replace effective_from, comment and lines of a draft price change.
+// @Tags price_change
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Price change ID"
+// @Param price_change body models.UpdatePriceChange true "Updated price change information"
+// @Success 202 {object} models.PriceChange "Updated price change"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/price_change/{id} [put]
+func (h *Handler) UpdatePriceChange(c *gin.Context) {
+
+	var updatePriceChange models.UpdatePriceChange
+
+	err := c.ShouldBindJSON(&updatePriceChange)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+	updatePriceChange.Id = id
+
+	if msg := validatePriceChangeProducts(updatePriceChange.Products); msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	rowsAffected, err := h.strg.PriceChange().Update(ctx, &updatePriceChange)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "price change not found or not a draft")
+		return
+	}
+
+	resp, err := h.strg.PriceChange().GetByID(ctx, &models.PriceChangePrimaryKey{Id: updatePriceChange.Id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
+
+// @Summary Delete a price change
+// @Description Delete a price change that has not been approved.
+// @Tags price_change
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Price change ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/price_change/{id} [delete]
+func (h *Handler) DeletePriceChange(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	err := h.strg.PriceChange().Delete(ctx, &models.PriceChangePrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusNoContent, nil)
+}
+
+// @Summary Approve a price change
+// @Description Approve a draft price change. Its prices go into the branch price list and take effect at effective_from.
+// @Tags price_change
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Price change ID"
+// @Success 202 {object} models.PriceChange "Approved price change"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/price_change/{id}/approve [post]
+func (h *Handler) ApprovePriceChange(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	if c.GetString("client_type") == "CASSIER" {
+		handleResponse(c, http.StatusForbidden, "cashier can not approve price changes")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	rowsAffected, err := h.strg.PriceChange().Approve(ctx, &models.ApprovePriceChange{
+		Id:         id,
+		ApprovedBy: c.GetString("user_id"),
+	})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "price change not found or not a draft")
+		return
+	}
+
+	resp, err := h.strg.PriceChange().GetByID(ctx, &models.PriceChangePrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
+
+// @Summary Cancel a price change
+// @Description Cancel a draft price change.
+// @Tags price_change
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Price change ID"
+// @Success 202 {object} models.PriceChange "Canceled price change"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/price_change/{id}/cancel [post]
+func (h *Handler) CancelPriceChange(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	rowsAffected, err := h.strg.PriceChange().Cancel(ctx, &models.PriceChangePrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "price change not found or not a draft")
+		return
+	}
+
+	resp, err := h.strg.PriceChange().GetByID(ctx, &models.PriceChangePrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
+
+func validatePriceChangeProducts(products []*models.CreatePriceChangeProduct) string {
+
+	if len(products) <= 0 {
+		return "products are required"
+	}
+
+	for _, product := range products {
+		if !helpers.IsValidUUID(product.ProductID) {
+			return "product id is not uuid"
+		}
+
+		if product.NewPrice < 0 {
+			return "new price must not be negative"
+		}
+	}
+
+	return ""
+}
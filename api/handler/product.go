@@ -4,19 +4,22 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"time"
+	"strings"
 
 	"market_system/config"
 	"market_system/models"
+	"market_system/pkg/criteria"
 	"market_system/pkg/helpers"
+	"market_system/pkg/productimport"
 
 	"github.com/gin-gonic/gin"
 )
 
 // @Summary Create a new product
-// @Description Create a new product in the market system.
+// @Description Create a new product in the market system. A product without brand_id is of the brand of its category, if that has one.
 // @Tags product
 // @Accept json
 // @Produce json
@@ -37,6 +40,16 @@ func (h *Handler) CreateProduct(c *gin.Context) {
 		return
 	}
 
+	if len(createProduct.Unit) > 0 && !helpers.Contains(productimport.Units, createProduct.Unit) {
+		handleResponse(c, http.StatusBadRequest, "unit must be one of "+strings.Join(productimport.Units, ", "))
+		return
+	}
+
+	if len(createProduct.BrandID) > 0 && !helpers.IsValidUUID(createProduct.BrandID) {
+		handleResponse(c, http.StatusBadRequest, "brand id is not uuid")
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
 	defer cancel()
 
@@ -71,7 +84,7 @@ func (h *Handler) GetByIDProduct(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	ctx, cancel := requestContext(c)
 	defer cancel()
 
 	resp, err := h.strg.Product().GetByID(ctx, &models.ProductPrimaryKey{Id: id})
@@ -89,7 +102,7 @@ func (h *Handler) GetByIDProduct(c *gin.Context) {
 }
 
 // @Summary Get a list of products
-// @Description Get a list of products with optional filtering.
+// @Description Get a list of products with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null, under; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. category_id[under] selects a category with its subcategories. Filter and sort fields: id, photo, title, category_id, barcode, price, unit, brand_id, created_at, updated_at.
 // @Tags product
 // @Accept json
 // @Produce json
@@ -97,7 +110,15 @@ func (h *Handler) GetByIDProduct(c *gin.Context) {
 // @Param Password header string true "User password"
 // @Param limit query int false "Number of items to return (default 10)"
 // @Param offset query int false "Number of items to skip (default 0)"
-// @Param search query string false "Search term"
+// @Param search query string false "Exact barcode, or part of the title in Uzbek Latin, Uzbek Cyrillic or Russian"
+// @Param created_at[between] query string false "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)"
+// @Param category_id[under] query string false "Category ID, including its subcategories"
+// @Param brand_id[eq] query string false "Brand ID"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
+// @Param branch_id query string false "Branch ID the classes refer to"
+// @Param abc query string false "ABC class in the latest classification (A, B, C)"
+// @Param xyz query string false "XYZ class in the latest classification (X, Y, Z)"
 // @Success 200 {array} models.Product "List of products"
 // @Failure 400 {object} ErrorResponse "Bad Request"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
@@ -123,7 +144,19 @@ func (h *Handler) GetListProduct(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	branchID, abc, xyz, msg := productClassFilter(c)
+	if len(msg) > 0 {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
 	defer cancel()
 
 	var (
@@ -131,7 +164,7 @@ func (h *Handler) GetListProduct(c *gin.Context) {
 		resp = &models.GetListProductResponse{}
 	)
 
-	body, err := h.cache.GetX(ctx, key)
+	body, err := h.cachedList(ctx, c, key)
 	if err == nil {
 		err = json.Unmarshal(body, &resp)
 		if err != nil {
@@ -142,10 +175,20 @@ func (h *Handler) GetListProduct(c *gin.Context) {
 
 	if len(resp.Products) <= 0 {
 		resp, err = h.strg.Product().GetList(ctx, &models.GetListProductRequest{
-			Limit:  limit,
-			Offset: offset,
-			Search: search,
+			Limit:    limit,
+			Offset:   offset,
+			Search:   search,
+			Filters:  list.Filters,
+			Sort:     list.Sort,
+			BranchID: branchID,
+			ABC:      abc,
+			XYZ:      xyz,
 		})
+		var criteriaErr *criteria.Error
+		if errors.As(err, &criteriaErr) {
+			handleResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		if err != nil {
 			handleResponse(c, http.StatusInternalServerError, err)
 			return
@@ -157,10 +200,66 @@ func (h *Handler) GetListProduct(c *gin.Context) {
 			return
 		}
 
-		h.cache.SetX(ctx, key, string(body), time.Second*15)
+		h.cacheList(ctx, c, key, body)
 	}
 
-	handleResponse(c, http.StatusOK, resp)
+	handleListResponse(c, resp, list.Fields)
+}
+
+// @Summary Search products
+// @Description Look up products by barcode or name as it is typed at the point of sale, best matches first. A name is found whether it is written in Uzbek Latin, Uzbek Cyrillic or Russian, by the start of its words and despite typos. Filter with field[op]=value as in the product list, e.g. category_id[eq].
+// @Tags product
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param q query string true "Barcode or name, or the start of it"
+// @Param limit query int false "Number of items to return (default 10)"
+// @Param fields query string false "Item fields to return, comma separated"
+// @Success 200 {object} models.SearchProductResponse "Matching products, best first"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/product/search [get]
+func (h *Handler) SearchProduct(c *gin.Context) {
+
+	var q = strings.TrimSpace(c.Query("q"))
+	if len(q) == 0 {
+		handleResponse(c, http.StatusBadRequest, "query q is required")
+		return
+	}
+
+	limit, err := getIntegerOrDefaultValue(c.Query("limit"), 10)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query limit")
+		return
+	}
+
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.Product().Search(ctx, &models.SearchProductRequest{
+		Query:   q,
+		Limit:   limit,
+		Filters: list.Filters,
+	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleListResponse(c, resp, list.Fields)
 }
 
 // @Summary Update a product
@@ -194,6 +293,16 @@ func (h *Handler) UpdateProduct(c *gin.Context) {
 		return
 	}
 
+	if len(updateProduct.Unit) > 0 && !helpers.Contains(productimport.Units, updateProduct.Unit) {
+		handleResponse(c, http.StatusBadRequest, "unit must be one of "+strings.Join(productimport.Units, ", "))
+		return
+	}
+
+	if len(updateProduct.BrandID) > 0 && !helpers.IsValidUUID(updateProduct.BrandID) {
+		handleResponse(c, http.StatusBadRequest, "brand id is not uuid")
+		return
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
 	defer cancel()
 
@@ -0,0 +1,222 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+)
+
+// @Summary Classify products
+// @Description Classify the products of every branch into ABC by their share of the branch revenue and into XYZ by the variability of their weekly demand, over the finished sales of the last history_weeks weeks. The result is stored as a new run.
+// @Tags product_class
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param run body models.RunProductClass true "Run parameters (history_weeks defaults to PRODUCT_CLASS_HISTORY_WEEKS)"
+// @Success 201 {object} models.ProductClassRun "Classification run"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/product_class/run [post]
+func (h *Handler) RunProductClass(c *gin.Context) {
+
+	var run models.RunProductClass
+	err := c.ShouldBindJSON(&run)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	if c.GetString("client_type") == "CASSIER" {
+		handleResponse(c, http.StatusForbidden, "cashier can not classify products")
+		return
+	}
+
+	if run.HistoryWeeks == 0 {
+		run.HistoryWeeks = h.cfg.ProductClassHistoryWeeks
+	}
+
+	if run.HistoryWeeks < config.MinProductClassHistoryWeeks || run.HistoryWeeks > config.MaxProductClassHistoryWeeks {
+		handleResponse(c, http.StatusBadRequest, fmt.Sprintf("history weeks must be between %d and %d",
+			config.MinProductClassHistoryWeeks, config.MaxProductClassHistoryWeeks))
+		return
+	}
+
+	run.CreatedBy = c.GetString("user_id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.ProductClassTimeout)
+	defer cancel()
+
+	resp, err := h.strg.ProductClass().Run(ctx, &run)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusCreated, resp)
+}
+
+// @Summary Get a list of classification runs
+// @Description Get the product classification runs, latest first. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, run_date, from_date, to_date, history_weeks, products, created_by, created_at.
+// @Tags product_class
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param limit query int false "Number of items to return (default 10)"
+// @Param offset query int false "Number of items to skip (default 0)"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
+// @Success 200 {object} models.GetListProductClassRunResponse "List of runs"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/product_class/run [get]
+func (h *Handler) GetListProductClassRun(c *gin.Context) {
+
+	limit, err := getIntegerOrDefaultValue(c.Query("limit"), 10)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query limit")
+		return
+	}
+
+	offset, err := getIntegerOrDefaultValue(c.Query("offset"), 0)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query offset")
+		return
+	}
+
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.ProductClass().GetListRuns(ctx, &models.GetListProductClassRunRequest{
+		Limit:   limit,
+		Offset:  offset,
+		Filters: list.Filters,
+		Sort:    list.Sort,
+	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleListResponse(c, resp, list.Fields)
+}
+
+// @Summary Get product classes
+// @Description Get the classes of a classification run, the latest one by default, and the number of products in every ABC/XYZ cell. The cell counts ignore the abc and xyz filters. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: branch_id, branch_name, barcode, product_name, revenue, units, share, cumulative_share, abc, weekly_mean, cv, xyz.
+// @Tags product_class
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param limit query int false "Number of items to return (default 10)"
+// @Param offset query int false "Number of items to skip (default 0)"
+// @Param search query string false "Search by barcode or product name"
+// @Param branch_id[eq] query string false "Branch ID"
+// @Param barcode[eq] query string false "Barcode"
+// @Param abc[eq] query string false "ABC class (A, B, C)"
+// @Param xyz[eq] query string false "XYZ class (X, Y, Z)"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param run_id query string false "Run ID (default the latest run)"
+// @Success 200 {object} models.GetListProductClassResponse "Product classes"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/product_class [get]
+func (h *Handler) GetListProductClass(c *gin.Context) {
+
+	limit, err := getIntegerOrDefaultValue(c.Query("limit"), 10)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query limit")
+		return
+	}
+
+	offset, err := getIntegerOrDefaultValue(c.Query("offset"), 0)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query offset")
+		return
+	}
+
+	runID := c.Query("run_id")
+	if runID != "" && !helpers.IsValidUUID(runID) {
+		handleResponse(c, http.StatusBadRequest, "run id is not uuid")
+		return
+	}
+
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.ProductClass().GetList(ctx, &models.GetListProductClassRequest{
+		Limit:   limit,
+		Offset:  offset,
+		Search:  c.Query("search"),
+		Filters: list.Filters,
+		Sort:    list.Sort,
+		RunID:   runID,
+	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "no rows in result set")
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// productClassFilter reads the branch_id, abc and xyz query parameters that
+// filter lists by product class, returning a message when one is invalid.
+func productClassFilter(c *gin.Context) (branchID, abc, xyz, msg string) {
+
+	branchID, abc, xyz = c.Query("branch_id"), c.Query("abc"), c.Query("xyz")
+
+	if branchID != "" && !helpers.IsValidUUID(branchID) {
+		return "", "", "", "branch id is not uuid"
+	}
+
+	if abc != "" && !helpers.Contains(config.ABCClasses, abc) {
+		return "", "", "", "abc must be one of A, B, C"
+	}
+
+	if xyz != "" && !helpers.Contains(config.XYZClasses, xyz) {
+		return "", "", "", "xyz must be one of X, Y, Z"
+	}
+
+	return branchID, abc, xyz, ""
+}
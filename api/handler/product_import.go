@@ -0,0 +1,349 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+	"market_system/pkg/productimport"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+)
+
+// productImportPreviewRows is how many rows a new import shows.
+const productImportPreviewRows = 20
+
+// @Summary Import products
+// @Description Validate a CSV or XLSX file of products and report the errors of every row. The columns are title, barcode, category (a path like "Drinks / Tea"), brand, price and unit. With dry_run=false the valid rows are imported in the background: missing categories and brands are created and products are created or updated by barcode.
+// @Tags product_import
+// @Accept multipart/form-data
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param file formData file true "Product file (.csv or .xlsx)"
+// @Param format formData string false "File format (csv, xlsx), taken from the file name by default"
+// @Param dry_run query bool false "Only validate the file (default true)"
+// @Success 201 {object} models.ProductImport "Validated import with a preview of its rows"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/product_import [post]
+func (h *Handler) CreateProductImport(c *gin.Context) {
+
+	if c.GetString("client_type") == "CASSIER" {
+		handleResponse(c, http.StatusForbidden, "cashier can not import products")
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "file is required")
+		return
+	}
+
+	var format = c.PostForm("format")
+	if len(format) == 0 {
+		format = strings.ToLower(strings.TrimPrefix(filepath.Ext(header.Filename), "."))
+	}
+	if !helpers.Contains(productimport.Formats, format) {
+		handleResponse(c, http.StatusBadRequest, "format must be one of "+strings.Join(productimport.Formats, ", "))
+		return
+	}
+
+	var dryRun = c.DefaultQuery("dry_run", "true") != "false"
+
+	file, err := header.Open()
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer file.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.ProductImportTimeout)
+	defer cancel()
+
+	resp, err := h.strg.ProductImport().Create(ctx, &models.CreateProductImport{
+		FileName:  header.Filename,
+		Format:    format,
+		File:      file,
+		CreatedBy: c.GetString("user_id"),
+	})
+	var fileErr *productimport.FileError
+	if errors.As(err, &fileErr) {
+		handleResponse(c, http.StatusBadRequest, fileErr.Message)
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if !dryRun && resp.ValidRows > 0 {
+		started, err := h.strg.ProductImport().Start(ctx, &models.ProductImportPrimaryKey{Id: resp.Id})
+		if err != nil {
+			handleResponse(c, http.StatusInternalServerError, err)
+			return
+		}
+		if started > 0 {
+			resp.Status = config.ProductImportRunning
+			go h.runProductImport(resp.Id)
+		}
+	}
+
+	rows, err := h.strg.ProductImport().GetRows(ctx, &models.GetListProductImportRowRequest{
+		ImportID: resp.Id,
+		Limit:    productImportPreviewRows,
+	})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+	resp.Preview = rows.Rows
+
+	handleResponse(c, http.StatusCreated, resp)
+}
+
+// runProductImport runs a started import; a failure is recorded on the import.
+func (h *Handler) runProductImport(importID string) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.ProductImportTimeout)
+	defer cancel()
+
+	_, err := h.strg.ProductImport().Run(ctx, &models.ProductImportPrimaryKey{Id: importID})
+	if err != nil {
+		log.Println("product import", importID+":", err)
+	}
+}
+
+// @Summary Run a product import
+// @Description Import the valid rows of a validated import in the background, or resume a failed one from the first row left.
+// @Tags product_import
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Product import ID"
+// @Success 202 {object} models.ProductImport "Running import"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/product_import/{id}/run [post]
+func (h *Handler) RunProductImport(c *gin.Context) {
+
+	if c.GetString("client_type") == "CASSIER" {
+		handleResponse(c, http.StatusForbidden, "cashier can not import products")
+		return
+	}
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	started, err := h.strg.ProductImport().Start(ctx, &models.ProductImportPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp, err := h.strg.ProductImport().GetByID(ctx, &models.ProductImportPrimaryKey{Id: id})
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "no rows in result set")
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if started == 0 {
+		handleResponse(c, http.StatusBadRequest, "import is "+resp.Status)
+		return
+	}
+
+	go h.runProductImport(id)
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
+
+// @Summary Get product import by ID
+// @Description Get a product import with its validation summary and progress.
+// @Tags product_import
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Product import ID"
+// @Success 200 {object} models.ProductImport "Product import"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/product_import/{id} [get]
+func (h *Handler) GetByIDProductImport(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.ProductImport().GetByID(ctx, &models.ProductImportPrimaryKey{Id: id})
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "no rows in result set")
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Get a list of product imports
+// @Description Get product imports, latest first. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, status, file_name, format, total_rows, valid_rows, invalid_rows, imported_rows, created, updated, categories_created, brands_created, created_by, created_at, started_at, finished_at.
+// @Tags product_import
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param limit query int false "Number of items to return (default 10)"
+// @Param offset query int false "Number of items to skip (default 0)"
+// @Param search query string false "Search by file name"
+// @Param status[eq] query string false "Status (validated, running, done, failed)"
+// @Param created_by[eq] query string false "Author user ID"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
+// @Success 200 {object} models.GetListProductImportResponse "List of product imports"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/product_import [get]
+func (h *Handler) GetListProductImport(c *gin.Context) {
+
+	limit, err := getIntegerOrDefaultValue(c.Query("limit"), 10)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query limit")
+		return
+	}
+
+	offset, err := getIntegerOrDefaultValue(c.Query("offset"), 0)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query offset")
+		return
+	}
+
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.ProductImport().GetList(ctx, &models.GetListProductImportRequest{
+		Limit:   limit,
+		Offset:  offset,
+		Search:  c.Query("search"),
+		Filters: list.Filters,
+		Sort:    list.Sort,
+	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleListResponse(c, resp, list.Fields)
+}
+
+// @Summary Get the rows of a product import
+// @Description Get the rows of a product import by line, with the errors of the invalid ones. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: line, title, barcode, brand, price, unit, status, product_id.
+// @Tags product_import
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Product import ID"
+// @Param limit query int false "Number of items to return (default 10)"
+// @Param offset query int false "Number of items to skip (default 0)"
+// @Param search query string false "Search by title or barcode"
+// @Param status[eq] query string false "Status (valid, invalid, imported)"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
+// @Success 200 {object} models.GetListProductImportRowResponse "Rows of the import"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/product_import/{id}/rows [get]
+func (h *Handler) GetListProductImportRow(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	limit, err := getIntegerOrDefaultValue(c.Query("limit"), 10)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query limit")
+		return
+	}
+
+	offset, err := getIntegerOrDefaultValue(c.Query("offset"), 0)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query offset")
+		return
+	}
+
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.ProductImport().GetRows(ctx, &models.GetListProductImportRowRequest{
+		Limit:    limit,
+		Offset:   offset,
+		Search:   c.Query("search"),
+		Filters:  list.Filters,
+		Sort:     list.Sort,
+		ImportID: id,
+	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleListResponse(c, resp, list.Fields)
+}
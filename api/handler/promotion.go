@@ -0,0 +1,367 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+	"market_system/pkg/promotion"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+)
+
+// @Summary Create a promotion
+// @Description Create a promotion rule: percent off (category, brand, barcodes or whole basket), buy X get Y, or fixed bundle price.
+// @Tags promotion
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param promotion body models.CreatePromotion true "Promotion information"
+// @Success 201 {object} models.Promotion "Created promotion"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/promotion [post]
+func (h *Handler) CreatePromotion(c *gin.Context) {
+
+	var createPromotion models.CreatePromotion
+	err := c.ShouldBindJSON(&createPromotion)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	if msg := validatePromotion(&createPromotion); msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	resp, err := h.strg.Promotion().Create(ctx, &createPromotion)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusCreated, resp)
+}
+
+// @Summary Get a promotion by ID
+// @Description Get promotion details with its branches and items.
+// @Tags promotion
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Promotion ID"
+// @Success 200 {object} models.Promotion "Promotion details"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/promotion/{id} [get]
+func (h *Handler) GetByIDPromotion(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.Promotion().GetByID(ctx, &models.PromotionPrimaryKey{Id: id})
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "no rows in result set")
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Get a list of promotions
+// @Description Get a list of promotions, highest priority first. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, name, type, percent, category_id, brand_id, min_basket_amount, start_date, end_date, priority, stackable, is_active, created_at, updated_at.
+// @Tags promotion
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param limit query int false "Number of items to return (default 10)"
+// @Param offset query int false "Number of items to skip (default 0)"
+// @Param search query string false "Search by name"
+// @Param is_active[eq] query string false "true or false"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
+// @Param branch_id query string false "Only promotions running in this branch"
+// @Success 200 {object} models.GetListPromotionResponse "List of promotions"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/promotion [get]
+func (h *Handler) GetListPromotion(c *gin.Context) {
+
+	limit, err := getIntegerOrDefaultValue(c.Query("limit"), 10)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query limit")
+		return
+	}
+
+	offset, err := getIntegerOrDefaultValue(c.Query("offset"), 0)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query offset")
+		return
+	}
+
+	var branchID = c.Query("branch_id")
+	if branchID != "" && !helpers.IsValidUUID(branchID) {
+		handleResponse(c, http.StatusBadRequest, "branch id is not uuid")
+		return
+	}
+
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.Promotion().GetList(ctx, &models.GetListPromotionRequest{
+		Limit:    limit,
+		Offset:   offset,
+		Search:   c.Query("search"),
+		Filters:  list.Filters,
+		Sort:     list.Sort,
+		BranchID: branchID,
+	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleListResponse(c, resp, list.Fields)
+}
+
+// @Summary Update a promotion
+// @Description Update a promotion rule, its branches and items.
+// @Tags promotion
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Promotion ID"
+// @Param promotion body models.UpdatePromotion true "Updated promotion information"
+// @Success 202 {object} models.Promotion "Updated promotion"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/promotion/{id} [put]
+func (h *Handler) UpdatePromotion(c *gin.Context) {
+
+	var updatePromotion models.UpdatePromotion
+
+	err := c.ShouldBindJSON(&updatePromotion)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+	updatePromotion.Id = id
+
+	var promotionRule models.CreatePromotion
+	err = helpers.StructToStruct(&promotionRule, updatePromotion)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if msg := validatePromotion(&promotionRule); msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	rowsAffected, err := h.strg.Promotion().Update(ctx, &updatePromotion)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "no rows affected")
+		return
+	}
+
+	resp, err := h.strg.Promotion().GetByID(ctx, &models.PromotionPrimaryKey{Id: updatePromotion.Id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
+
+// @Summary Delete a promotion
+// @Description Delete a promotion. Promotions already used in sales are deactivated instead.
+// @Tags promotion
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Promotion ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/promotion/{id} [delete]
+func (h *Handler) DeletePromotion(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	err := h.strg.Promotion().Delete(ctx, &models.PromotionPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusNoContent, nil)
+}
+
+// @Summary Promotion effectiveness report
+// @Description Per promotion: finished sales it touched, units, discount given and revenue of discounted lines.
+// @Tags promotion
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param from_date query string false "From date, e.g. 2024-01-01"
+// @Param to_date query string false "To date (exclusive), e.g. 2024-02-01"
+// @Param branch_id query string false "Branch ID"
+// @Success 200 {object} models.PromotionReportResponse "Promotion report"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/promotion/report [get]
+func (h *Handler) GetPromotionReport(c *gin.Context) {
+
+	var branchID = c.Query("branch_id")
+	if branchID != "" && !helpers.IsValidUUID(branchID) {
+		handleResponse(c, http.StatusBadRequest, "branch id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.Promotion().Report(ctx, &models.PromotionReportRequest{
+		FromDate: c.Query("from_date"),
+		ToDate:   c.Query("to_date"),
+		BranchID: branchID,
+	})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+func validatePromotion(req *models.CreatePromotion) string {
+
+	if req.Name == "" {
+		return "name is required"
+	}
+
+	switch req.Type {
+	case promotion.TypePercent:
+		if req.Percent <= 0 || req.Percent > 100 {
+			return "percent must be between 0 and 100"
+		}
+	case promotion.TypeBuyXGetY:
+		if req.BuyQuantity <= 0 || req.GetQuantity <= 0 {
+			return "buy_quantity and get_quantity are required"
+		}
+		if req.Percent < 0 || req.Percent > 100 {
+			return "percent must be between 0 and 100"
+		}
+	case promotion.TypeBundle:
+		if len(req.Items) < 2 {
+			return "bundle needs at least two items"
+		}
+		if req.BundlePrice <= 0 {
+			return "bundle_price is required"
+		}
+	default:
+		return "type must be percent, buy_x_get_y or bundle"
+	}
+
+	if req.CategoryID != "" && !helpers.IsValidUUID(req.CategoryID) {
+		return "category id is not uuid"
+	}
+
+	if req.BrandID != "" && !helpers.IsValidUUID(req.BrandID) {
+		return "brand id is not uuid"
+	}
+
+	for _, branchID := range req.BranchIDs {
+		if !helpers.IsValidUUID(branchID) {
+			return "branch id is not uuid"
+		}
+	}
+
+	for _, item := range req.Items {
+		if item.Barcode == "" {
+			return "item barcode is required"
+		}
+	}
+
+	if (req.HappyHourFrom == "") != (req.HappyHourTo == "") {
+		return "happy_hour_from and happy_hour_to go together"
+	}
+
+	for _, clock := range []string{req.HappyHourFrom, req.HappyHourTo} {
+		if clock == "" {
+			continue
+		}
+
+		if _, err := time.Parse(promotion.ClockLayout, clock); err != nil {
+			return "happy hour must look like 15:04"
+		}
+	}
+
+	return ""
+}
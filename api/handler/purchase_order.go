@@ -0,0 +1,496 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+	"market_system/pkg/purchaseorder"
+	"market_system/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+)
+
+// @Summary Create a purchase order
+// @Description Create a draft purchase order to a supplier for a branch with the expected lines and prices.
+// @Tags purchase_order
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param purchase_order body models.CreatePurchaseOrder true "Purchase order information"
+// @Success 201 {object} models.PurchaseOrder "Created purchase order"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/purchase_order [post]
+func (h *Handler) CreatePurchaseOrder(c *gin.Context) {
+
+	var createPurchaseOrder models.CreatePurchaseOrder
+	err := c.ShouldBindJSON(&createPurchaseOrder)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	if msg := validatePurchaseOrder(createPurchaseOrder.SupplierID, createPurchaseOrder.BranchID, createPurchaseOrder.Products); msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+	createPurchaseOrder.CreatedBy = c.GetString("user_id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	resp, err := h.strg.PurchaseOrder().Create(ctx, &createPurchaseOrder)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusCreated, resp)
+}
+
+// @Summary Get a purchase order by ID
+// @Description Get a purchase order with its lines, received quantities and delivery status per line.
+// @Tags purchase_order
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Purchase order ID"
+// @Success 200 {object} models.PurchaseOrder "Purchase order details"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/purchase_order/{id} [get]
+func (h *Handler) GetByIDPurchaseOrder(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.PurchaseOrder().GetByID(ctx, &models.PurchaseOrderPrimaryKey{Id: id})
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "no rows in result set")
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Get a list of purchase orders
+// @Description Get a list of purchase orders. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, supplier_id, branch_id, status, expected_date, created_by, sent_at, received_at, closed_at, created_at, updated_at.
+// @Tags purchase_order
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param limit query int false "Number of items to return (default 10)"
+// @Param offset query int false "Number of items to skip (default 0)"
+// @Param supplier_id[eq] query string false "Supplier ID"
+// @Param branch_id[eq] query string false "Branch ID"
+// @Param status[eq] query string false "draft, sent, partially_received, received or closed"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
+// @Success 200 {object} models.GetListPurchaseOrderResponse "List of purchase orders"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/purchase_order [get]
+func (h *Handler) GetListPurchaseOrder(c *gin.Context) {
+
+	limit, err := getIntegerOrDefaultValue(c.Query("limit"), 10)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query limit")
+		return
+	}
+
+	offset, err := getIntegerOrDefaultValue(c.Query("offset"), 0)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query offset")
+		return
+	}
+
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.PurchaseOrder().GetList(ctx, &models.GetListPurchaseOrderRequest{
+		Limit:   limit,
+		Offset:  offset,
+		Filters: list.Filters,
+		Sort:    list.Sort,
+	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleListResponse(c, resp, list.Fields)
+}
+
+// @Summary Update a purchase order
+// @Description Replace supplier, branch, expected date, comment and lines of a draft purchase order.
+// @Tags purchase_order
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Purchase order ID"
+// @Param purchase_order body models.UpdatePurchaseOrder true "Updated purchase order information"
+// @Success 202 {object} models.PurchaseOrder "Updated purchase order"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/purchase_order/{id} [put]
+func (h *Handler) UpdatePurchaseOrder(c *gin.Context) {
+
+	var updatePurchaseOrder models.UpdatePurchaseOrder
+
+	err := c.ShouldBindJSON(&updatePurchaseOrder)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+	updatePurchaseOrder.Id = id
+
+	if msg := validatePurchaseOrder(updatePurchaseOrder.SupplierID, updatePurchaseOrder.BranchID, updatePurchaseOrder.Products); msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	rowsAffected, err := h.strg.PurchaseOrder().Update(ctx, &updatePurchaseOrder)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "purchase order not found or not a draft")
+		return
+	}
+
+	resp, err := h.strg.PurchaseOrder().GetByID(ctx, &models.PurchaseOrderPrimaryKey{Id: updatePurchaseOrder.Id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
+
+// @Summary Delete a purchase order
+// @Description Delete a purchase order that is still a draft.
+// @Tags purchase_order
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Purchase order ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/purchase_order/{id} [delete]
+func (h *Handler) DeletePurchaseOrder(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	err := h.strg.PurchaseOrder().Delete(ctx, &models.PurchaseOrderPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusNoContent, nil)
+}
+
+// @Summary Send a purchase order
+// @Description Mark a draft purchase order as sent to the supplier. Only sent orders can be received.
+// @Tags purchase_order
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Purchase order ID"
+// @Success 202 {object} models.PurchaseOrder "Sent purchase order"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/purchase_order/{id}/send [post]
+func (h *Handler) SendPurchaseOrder(c *gin.Context) {
+	h.setPurchaseOrderStatus(c, config.PurchaseOrderSent)
+}
+
+// @Summary Close a purchase order
+// @Description Close a sent or (partially) received purchase order, accepting whatever was delivered. No more receipts are taken against it.
+// @Tags purchase_order
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Purchase order ID"
+// @Success 202 {object} models.PurchaseOrder "Closed purchase order"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/purchase_order/{id}/close [post]
+func (h *Handler) ClosePurchaseOrder(c *gin.Context) {
+	h.setPurchaseOrderStatus(c, config.PurchaseOrderClosed)
+}
+
+func (h *Handler) setPurchaseOrderStatus(c *gin.Context, status string) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	if c.GetString("client_type") == "CASSIER" {
+		handleResponse(c, http.StatusForbidden, "cashier can not change purchase order status")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	rowsAffected, err := h.strg.PurchaseOrder().SetStatus(ctx, &models.SetPurchaseOrderStatus{
+		Id:   id,
+		From: purchaseorder.From(status),
+		To:   status,
+	})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "purchase order not found or can not be "+status)
+		return
+	}
+
+	resp, err := h.strg.PurchaseOrder().GetByID(ctx, &models.PurchaseOrderPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
+
+// @Summary Receive goods against a purchase order
+// @Description Record a delivery against a sent purchase order. A finished income linked to the order is created, branch stock is increased and the order becomes partially_received or received. Quantities above the ordered ones are accepted as over-delivery.
+// @Tags purchase_order
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Purchase order ID"
+// @Param receipt body models.ReceivePurchaseOrder true "Received lines"
+// @Success 201 {object} models.ReceivePurchaseOrderResponse "Income and updated purchase order"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/purchase_order/{id}/receive [post]
+func (h *Handler) ReceivePurchaseOrder(c *gin.Context) {
+
+	var receive models.ReceivePurchaseOrder
+	err := c.ShouldBindJSON(&receive)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+	receive.PurchaseOrderID = id
+
+	if len(receive.Products) <= 0 {
+		handleResponse(c, http.StatusBadRequest, "products are required")
+		return
+	}
+
+	for _, product := range receive.Products {
+		if !helpers.IsValidUUID(product.PurchaseOrderProductID) {
+			handleResponse(c, http.StatusBadRequest, "purchase order product id is not uuid")
+			return
+		}
+
+		if product.Quantity <= 0 {
+			handleResponse(c, http.StatusBadRequest, "quantity must be positive")
+			return
+		}
+
+		if product.Price < 0 {
+			handleResponse(c, http.StatusBadRequest, "price must not be negative")
+			return
+		}
+
+		if product.ExpiryDate != "" && !helpers.IsValidDate(product.ExpiryDate) {
+			handleResponse(c, http.StatusBadRequest, "expiry date must be YYYY-MM-DD")
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	incomeID, err := h.strg.PurchaseOrder().Receive(ctx, &receive)
+	if errors.Is(err, pgx.ErrNoRows) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if errors.Is(err, storage.ErrPurchaseOrderNotReceivable) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	income, err := h.strg.Income().GetByID(ctx, &models.IncomePrimaryKey{Id: incomeID})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	order, err := h.strg.PurchaseOrder().GetByID(ctx, &models.PurchaseOrderPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusCreated, models.ReceivePurchaseOrderResponse{
+		Income:        income,
+		PurchaseOrder: order,
+	})
+}
+
+// @Summary Supplier fill rate report
+// @Description Ordered against received quantities per supplier for purchase orders created in the period, with unit fill rate, share of lines delivered in full and share of orders received by their expected date.
+// @Tags purchase_order
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param from_date query string false "Orders created from (inclusive)"
+// @Param to_date query string false "Orders created before (exclusive)"
+// @Param supplier_id query string false "Supplier ID"
+// @Param branch_id query string false "Branch ID"
+// @Success 200 {object} models.SupplierFillRateResponse "Fill rate per supplier"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/purchase_order/report [get]
+func (h *Handler) GetSupplierFillRateReport(c *gin.Context) {
+
+	var supplierID = c.Query("supplier_id")
+	if supplierID != "" && !helpers.IsValidUUID(supplierID) {
+		handleResponse(c, http.StatusBadRequest, "supplier id is not uuid")
+		return
+	}
+
+	var branchID = c.Query("branch_id")
+	if branchID != "" && !helpers.IsValidUUID(branchID) {
+		handleResponse(c, http.StatusBadRequest, "branch id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.PurchaseOrder().FillRateReport(ctx, &models.SupplierFillRateRequest{
+		FromDate:   c.Query("from_date"),
+		ToDate:     c.Query("to_date"),
+		SupplierID: supplierID,
+		BranchID:   branchID,
+	})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+func validatePurchaseOrder(supplierID, branchID string, products []*models.CreatePurchaseOrderProduct) string {
+
+	if !helpers.IsValidUUID(supplierID) {
+		return "supplier id is not uuid"
+	}
+
+	if !helpers.IsValidUUID(branchID) {
+		return "branch id is not uuid"
+	}
+
+	if len(products) <= 0 {
+		return "products are required"
+	}
+
+	for _, product := range products {
+		if !helpers.IsValidUUID(product.ProductID) {
+			return "product id is not uuid"
+		}
+
+		if product.Quantity <= 0 {
+			return "quantity must be positive"
+		}
+
+		if product.Price < 0 {
+			return "price must not be negative"
+		}
+	}
+
+	return ""
+}
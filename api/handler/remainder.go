@@ -4,12 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"time"
 
 	"market_system/config"
 	"market_system/models"
+	"market_system/pkg/criteria"
 	"market_system/pkg/helpers"
 
 	"github.com/gin-gonic/gin"
@@ -70,7 +71,7 @@ func (h *Handler) GetByIDRemainder(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	ctx, cancel := requestContext(c)
 	defer cancel()
 
 	resp, err := h.strg.Remainder().GetByID(ctx, &models.RemainderPrimaryKey{Id: id})
@@ -88,7 +89,7 @@ func (h *Handler) GetByIDRemainder(c *gin.Context) {
 }
 
 // @Summary Get a list of remainder
-// @Description Get a list of remainder with optional filtering.
+// @Description Get a list of remainder with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null, under; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. category_id[under] selects a category with its subcategories. Filter and sort fields: id, branch_id, category_id, brand_id, product_name, barcode, price_income, quantity, created_at, updated_at.
 // @Tags remainder
 // @Accept json
 // @Produce json
@@ -97,6 +98,14 @@ func (h *Handler) GetByIDRemainder(c *gin.Context) {
 // @Param limit query int false "Number of items to return (default 10)"
 // @Param offset query int false "Number of items to skip (default 0)"
 // @Param search query string false "Search term"
+// @Param created_at[between] query string false "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)"
+// @Param category_id[under] query string false "Category ID, including its subcategories"
+// @Param brand_id[eq] query string false "Brand ID of the product"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
+// @Param branch_id query string false "Branch ID the classes refer to"
+// @Param abc query string false "ABC class in the latest classification (A, B, C)"
+// @Param xyz query string false "XYZ class in the latest classification (X, Y, Z)"
 // @Success 200 {array} models.Remainder "List of remainder"
 // @Failure 400 {object} ErrorResponse "Bad Request"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
@@ -121,7 +130,19 @@ func (h *Handler) GetListRemainder(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	branchID, abc, xyz, msg := productClassFilter(c)
+	if len(msg) > 0 {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
 	defer cancel()
 
 	var (
@@ -129,7 +150,7 @@ func (h *Handler) GetListRemainder(c *gin.Context) {
 		resp = &models.GetListRemainderResponse{}
 	)
 
-	body, err := h.cache.GetX(ctx, key)
+	body, err := h.cachedList(ctx, c, key)
 	if err == nil {
 		err = json.Unmarshal(body, &resp)
 		if err != nil {
@@ -140,10 +161,20 @@ func (h *Handler) GetListRemainder(c *gin.Context) {
 
 	if len(resp.Remainder) <= 0 {
 		resp, err = h.strg.Remainder().GetList(ctx, &models.GetListRemainderRequest{
-			Limit:  limit,
-			Offset: offset,
-			Search: search,
+			Limit:    limit,
+			Offset:   offset,
+			Search:   search,
+			Filters:  list.Filters,
+			Sort:     list.Sort,
+			BranchID: branchID,
+			ABC:      abc,
+			XYZ:      xyz,
 		})
+		var criteriaErr *criteria.Error
+		if errors.As(err, &criteriaErr) {
+			handleResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
 		if err != nil {
 			handleResponse(c, http.StatusInternalServerError, err)
 			return
@@ -155,10 +186,10 @@ func (h *Handler) GetListRemainder(c *gin.Context) {
 			return
 		}
 
-		h.cache.SetX(ctx, key, string(body), time.Second*15)
+		h.cacheList(ctx, c, key, body)
 	}
 
-	handleResponse(c, http.StatusOK, resp)
+	handleListResponse(c, resp, list.Fields)
 }
 
 // @Summary Update a remainder
@@ -0,0 +1,405 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/helpers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Sales report
+// @Description Revenue, sale count, average basket, units sold and gross margin of finished sales, grouped by any of period, branch, sale_point, cashier, category and brand. The brand of a line is that of its product, or of its category when the product has none. With compare the totals, and the rows when not grouped by period, are compared with the preceding period of the same length.
+// @Tags report
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param from_date query string false "Sold from, inclusive (default first day of this month)"
+// @Param to_date query string false "Sold before, exclusive (default tomorrow)"
+// @Param group_by query string false "Comma separated: period, branch, sale_point, cashier, category, brand"
+// @Param interval query string false "Period of the period group: day, week or month (default day)"
+// @Param compare query bool false "Compare with the previous period"
+// @Param branch_id query string false "Branch ID"
+// @Param sale_point_id query string false "Sale point ID"
+// @Param cashier_id query string false "Cashier ID"
+// @Param category_id query string false "Category ID, including its subcategories"
+// @Param brand_id query string false "Brand ID"
+// @Success 200 {object} models.SalesReportResponse "Sales report"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/report/sales [get]
+func (h *Handler) GetSalesReport(c *gin.Context) {
+
+	fromDate, toDate, msg := reportDates(c)
+	if msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	var groupBy []string
+	if c.Query("group_by") != "" {
+		groupBy = helpers.RemoveDuplicatesStrings(strings.Split(c.Query("group_by"), ","))
+	}
+
+	for _, group := range groupBy {
+		if !helpers.Contains(config.SalesReportGroups, group) {
+			handleResponse(c, http.StatusBadRequest, "group_by must be period, branch, sale_point, cashier, category or brand")
+			return
+		}
+	}
+
+	var interval = c.DefaultQuery("interval", "day")
+	if !helpers.Contains(config.ReportIntervals, interval) {
+		handleResponse(c, http.StatusBadRequest, "interval must be day, week or month")
+		return
+	}
+
+	var req = models.SalesReportRequest{
+		FromDate: fromDate,
+		ToDate:   toDate,
+		Interval: interval,
+		GroupBy:  groupBy,
+		Compare:  c.Query("compare") == "true",
+	}
+	if msg := reportFilters(c, map[string]*string{
+		"branch_id":     &req.BranchID,
+		"sale_point_id": &req.SalePointID,
+		"cashier_id":    &req.CashierID,
+		"category_id":   &req.CategoryID,
+		"brand_id":      &req.BrandID,
+	}); msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.Report().Sales(ctx, &req)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Top products
+// @Description Best selling products of finished sales by revenue, units or gross margin, ranked within each period when an interval is given.
+// @Tags report
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param from_date query string false "Sold from, inclusive (default first day of this month)"
+// @Param to_date query string false "Sold before, exclusive (default tomorrow)"
+// @Param interval query string false "day, week or month; rank over the whole range when empty"
+// @Param order_by query string false "revenue, units or margin (default revenue)"
+// @Param limit query int false "Products per period (default 10)"
+// @Param branch_id query string false "Branch ID"
+// @Param sale_point_id query string false "Sale point ID"
+// @Param cashier_id query string false "Cashier ID"
+// @Param category_id query string false "Category ID, including its subcategories"
+// @Param brand_id query string false "Brand ID"
+// @Success 200 {object} models.TopProductsResponse "Top products"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/report/sales/top_products [get]
+func (h *Handler) GetTopProducts(c *gin.Context) {
+
+	fromDate, toDate, msg := reportDates(c)
+	if msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	var interval = c.Query("interval")
+	if interval != "" && !helpers.Contains(config.ReportIntervals, interval) {
+		handleResponse(c, http.StatusBadRequest, "interval must be day, week or month")
+		return
+	}
+
+	var orderBy = c.DefaultQuery("order_by", "revenue")
+	if !helpers.Contains(config.TopProductsOrderBy, orderBy) {
+		handleResponse(c, http.StatusBadRequest, "order_by must be revenue, units or margin")
+		return
+	}
+
+	limit, err := getIntegerOrDefaultValue(c.Query("limit"), 10)
+	if err != nil || limit <= 0 {
+		handleResponse(c, http.StatusBadRequest, "invalid query limit")
+		return
+	}
+
+	var req = models.TopProductsRequest{
+		FromDate: fromDate,
+		ToDate:   toDate,
+		Interval: interval,
+		OrderBy:  orderBy,
+		Limit:    limit,
+	}
+	if msg := reportFilters(c, map[string]*string{
+		"branch_id":     &req.BranchID,
+		"sale_point_id": &req.SalePointID,
+		"cashier_id":    &req.CashierID,
+		"category_id":   &req.CategoryID,
+		"brand_id":      &req.BrandID,
+	}); msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.Report().TopProducts(ctx, &req)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Sales heatmap
+// @Description Finished sales and revenue per branch and sale point by weekday (1 Monday to 7 Sunday) and hour of the day, for staffing tills by traffic.
+// @Tags report
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param from_date query string false "Sold from, inclusive (default first day of this month)"
+// @Param to_date query string false "Sold before, exclusive (default tomorrow)"
+// @Param branch_id query string false "Branch ID"
+// @Param sale_point_id query string false "Sale point ID"
+// @Success 200 {object} models.HeatmapResponse "Heatmap"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/report/sales/heatmap [get]
+func (h *Handler) GetSalesHeatmap(c *gin.Context) {
+
+	fromDate, toDate, msg := reportDates(c)
+	if msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	var req = models.HeatmapRequest{
+		FromDate: fromDate,
+		ToDate:   toDate,
+	}
+	if msg := reportFilters(c, map[string]*string{
+		"branch_id":     &req.BranchID,
+		"sale_point_id": &req.SalePointID,
+	}); msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.Report().Heatmap(ctx, &req)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Cashier performance report
+// @Description Per cashier and shift: sales, revenue, items per minute, average receipt, voided and returned sales, and cash variance at shift close.
+// @Tags report
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param from_date query string false "Shifts opened from, inclusive (default first day of this month)"
+// @Param to_date query string false "Shifts opened before, exclusive (default tomorrow)"
+// @Param branch_id query string false "Branch ID"
+// @Param cashier_id query string false "Cashier ID"
+// @Success 200 {object} models.CashierReportResponse "Cashier performance"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/report/cashier [get]
+func (h *Handler) GetCashierReport(c *gin.Context) {
+
+	fromDate, toDate, msg := reportDates(c)
+	if msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	var req = models.CashierReportRequest{
+		FromDate: fromDate,
+		ToDate:   toDate,
+	}
+	if msg := reportFilters(c, map[string]*string{
+		"branch_id":  &req.BranchID,
+		"cashier_id": &req.CashierID,
+	}); msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.Report().Cashiers(ctx, &req)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Inventory valuation
+// @Description Stock per branch and category valued at cost and at retail price, with the potential margin. A past as_of date reconstructs the stock at the end of that day from stock movements.
+// @Tags report
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param as_of query string false "Value the stock at the end of this day (default now)"
+// @Param branch_id query string false "Branch ID"
+// @Param category_id query string false "Category ID, including its subcategories"
+// @Success 200 {object} models.InventoryValuationResponse "Inventory valuation"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/report/inventory/valuation [get]
+func (h *Handler) GetInventoryValuation(c *gin.Context) {
+
+	var asOf = c.Query("as_of")
+	if asOf != "" && !helpers.IsValidDate(asOf) {
+		handleResponse(c, http.StatusBadRequest, "as_of must be YYYY-MM-DD")
+		return
+	}
+
+	var req = models.InventoryValuationRequest{AsOf: asOf}
+	if msg := reportFilters(c, map[string]*string{
+		"branch_id":   &req.BranchID,
+		"category_id": &req.CategoryID,
+	}); msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.Report().InventoryValuation(ctx, &req)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Stock aging
+// @Description Stock on hand bucketed by days since it was last received, and dead stock: products received and not sold for dead_days.
+// @Tags report
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param branch_id query string false "Branch ID"
+// @Param category_id query string false "Category ID, including its subcategories"
+// @Param buckets query string false "Comma separated ascending bucket bounds in days (default 30,60,90,180)"
+// @Param dead_days query int false "Days without sales for dead stock (default 90)"
+// @Success 200 {object} models.StockAgingResponse "Stock aging"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/report/inventory/aging [get]
+func (h *Handler) GetStockAging(c *gin.Context) {
+
+	var buckets = config.AgingBuckets
+	if c.Query("buckets") != "" {
+		buckets = nil
+		for _, value := range strings.Split(c.Query("buckets"), ",") {
+			bound, err := getIntegerOrDefaultValue(value, 0)
+			if err != nil || bound <= 0 || (len(buckets) > 0 && bound <= buckets[len(buckets)-1]) {
+				handleResponse(c, http.StatusBadRequest, "buckets must be ascending positive days")
+				return
+			}
+			buckets = append(buckets, bound)
+		}
+	}
+
+	deadDays, err := getIntegerOrDefaultValue(c.Query("dead_days"), 90)
+	if err != nil || deadDays <= 0 {
+		handleResponse(c, http.StatusBadRequest, "invalid query dead_days")
+		return
+	}
+
+	var req = models.StockAgingRequest{
+		Buckets:  buckets,
+		DeadDays: deadDays,
+	}
+	if msg := reportFilters(c, map[string]*string{
+		"branch_id":   &req.BranchID,
+		"category_id": &req.CategoryID,
+	}); msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.Report().StockAging(ctx, &req)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// reportDates reads the [from_date, to_date) range of a report, which
+// defaults to this month up to and including today.
+func reportDates(c *gin.Context) (string, string, string) {
+
+	var (
+		now      = time.Now()
+		fromDate = c.DefaultQuery("from_date", now.Format("2006-01")+"-01")
+		toDate   = c.DefaultQuery("to_date", now.AddDate(0, 0, 1).Format("2006-01-02"))
+	)
+
+	if !helpers.IsValidDate(fromDate) || !helpers.IsValidDate(toDate) {
+		return "", "", "dates must be YYYY-MM-DD"
+	}
+
+	if fromDate >= toDate {
+		return "", "", "from_date must be before to_date"
+	}
+
+	return fromDate, toDate, ""
+}
+
+// reportFilters reads the ids a report is narrowed to, keyed by query
+// parameter.
+func reportFilters(c *gin.Context, filters map[string]*string) string {
+
+	for name, value := range filters {
+		*value = c.Query(name)
+		if *value != "" && !helpers.IsValidUUID(*value) {
+			return strings.ReplaceAll(name, "_", " ") + " is not uuid"
+		}
+	}
+
+	return ""
+}
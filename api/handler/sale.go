@@ -3,14 +3,18 @@ package handler
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"market_system/config"
 	"market_system/models"
+	"market_system/pkg/criteria"
 	"market_system/pkg/helpers"
+	"market_system/storage"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
 )
 
 // @Summary Create a new sale
@@ -34,9 +38,14 @@ func (h *Handler) CreateSale(c *gin.Context) {
 		return
 	}
 
+	if !helpers.IsValidUUID(createSale.BranchID) {
+		handleResponse(c, http.StatusBadRequest, "branch_id is not uuid")
+		return
+	}
+
 	cashTable, err := h.strg.Shift().GetList(context.Background(), &models.GetListShiftRequest{
-		Limit: 1,
-		Query: fmt.Sprintf(" AND branch_id = %s", createSale.BranchID),
+		Limit:   1,
+		Filters: []criteria.Filter{criteria.Equal("branch_id", createSale.BranchID)},
 	})
 	if err != nil {
 		handleResponse(c, http.StatusInternalServerError, err)
@@ -84,7 +93,7 @@ func (h *Handler) GetByIDSale(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	ctx, cancel := requestContext(c)
 	defer cancel()
 
 	resp, err := h.strg.Sale().GetByID(ctx, &models.SalePrimaryKey{Id: id})
@@ -102,7 +111,7 @@ func (h *Handler) GetByIDSale(c *gin.Context) {
 }
 
 // @Summary Get a list of sales
-// @Description Get a list of sales with optional filtering.
+// @Description Get a list of sales with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, sale_id, branch_id, salepoint_id, shift_id, employee_id, barcode, status, customer_id, created_at, updated_at. Sorted by created_at alone (the default) the list is also paged by cursor: a page with more after it has a next_cursor to pass as cursor, which is much faster than a large offset.
 // @Tags sale
 // @Accept json
 // @Produce json
@@ -111,6 +120,11 @@ func (h *Handler) GetByIDSale(c *gin.Context) {
 // @Param limit query int false "Number of items to return (default 10)"
 // @Param offset query int false "Number of items to skip (default 0)"
 // @Param search query string false "Search term"
+// @Param created_at[between] query string false "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
+// @Param cursor query string false "next_cursor of the page before, for lists sorted by created_at alone"
+// @Param count query bool false "Count all matching items (default true without a cursor, false with one)"
 // @Success 200 {array} models.Sale "List of sales"
 // @Failure 400 {object} ErrorResponse "Bad Request"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
@@ -136,20 +150,44 @@ func (h *Handler) GetListSale(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	var count = len(c.Query("cursor")) == 0
+	if len(c.Query("count")) > 0 {
+		count, err = strconv.ParseBool(c.Query("count"))
+		if err != nil {
+			handleResponse(c, http.StatusBadRequest, "invalid query count")
+			return
+		}
+	}
+
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
 	defer cancel()
 
 	resp, err := h.strg.Sale().GetList(ctx, &models.GetListSaleRequest{
-		Limit:  limit,
-		Offset: offset,
-		Search: search,
+		Limit:   limit,
+		Offset:  offset,
+		Search:  search,
+		Filters: list.Filters,
+		Sort:    list.Sort,
+		Cursor:  c.Query("cursor"),
+		Count:   count,
 	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		handleResponse(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	handleResponse(c, http.StatusOK, resp)
+	handleListResponse(c, resp, list.Fields)
 }
 
 // @Summary Update a sale
@@ -242,3 +280,123 @@ func (h *Handler) DeleteSale(c *gin.Context) {
 
 	handleResponse(c, http.StatusNoContent, nil)
 }
+
+// @Summary Cancel a sale
+// @Description Void a sale that was not paid. A finished sale is taken back with the return instead.
+// @Tags sale
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Sale ID"
+// @Success 202 {object} models.Sale "Canceled sale"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/sale/{id}/cancel [post]
+func (h *Handler) CancelSale(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	err := h.strg.Sale().Cancel(ctx, &models.SalePrimaryKey{Id: id})
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "Продажа не найдена")
+		return
+	}
+	if errors.Is(err, storage.ErrSaleFinished) {
+		handleResponse(c, http.StatusBadRequest, "Продажа уже завершена")
+		return
+	}
+	if errors.Is(err, storage.ErrSaleClosed) {
+		handleResponse(c, http.StatusBadRequest, "Продажа уже отменена или возвращена")
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp, err := h.strg.Sale().GetByID(ctx, &models.SalePrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
+
+// @Summary Return a sale
+// @Description Take a finished sale back. Its goods go back into the branch stock, the loyalty points it earned and redeemed are undone and what was paid for the goods in money is refunded as store credit. Gift cards sold in the sale stay active.
+// @Tags sale
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Sale ID"
+// @Param return body models.ReturnSale true "Return comment"
+// @Success 202 {object} models.SaleReturn "Returned sale and its store credit"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/sale/{id}/return [post]
+func (h *Handler) ReturnSale(c *gin.Context) {
+
+	var ret models.ReturnSale
+	err := c.ShouldBindJSON(&ret)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	ret.Id = c.Param("id")
+	if !helpers.IsValidUUID(ret.Id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	storeCreditID, err := h.strg.Sale().Return(ctx, &ret)
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "Продажа не найдена")
+		return
+	}
+	if errors.Is(err, storage.ErrSaleNotFinished) {
+		handleResponse(c, http.StatusBadRequest, "Продажа не завершена")
+		return
+	}
+	if errors.Is(err, storage.ErrSaleClosed) {
+		handleResponse(c, http.StatusBadRequest, "Продажа уже отменена или возвращена")
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var resp models.SaleReturn
+
+	resp.Sale, err = h.strg.Sale().GetByID(ctx, &models.SalePrimaryKey{Id: ret.Id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if storeCreditID != "" {
+		resp.StoreCredit, err = h.strg.GiftCard().GetByID(ctx, &models.GiftCardPrimaryKey{Id: storeCreditID})
+		if err != nil {
+			handleResponse(c, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
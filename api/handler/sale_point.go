@@ -3,10 +3,12 @@ package handler
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"net/http"
 
 	"market_system/config"
 	"market_system/models"
+	"market_system/pkg/criteria"
 	"market_system/pkg/helpers"
 
 	"github.com/gin-gonic/gin"
@@ -66,7 +68,7 @@ func (h *Handler) GetByIDSalePoint(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	ctx, cancel := requestContext(c)
 	defer cancel()
 
 	resp, err := h.strg.Sale_Point().GetByID(ctx, &models.SalePointPrimaryKey{Id: id})
@@ -82,7 +84,7 @@ func (h *Handler) GetByIDSalePoint(c *gin.Context) {
 }
 
 // @Summary Get a list of sale points
-// @Description Get a list of sale points with optional filtering.
+// @Description Get a list of sale points with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, branch_id, name, created_at, updated_at.
 // @Tags sale_point
 // @Accept json
 // @Produce json
@@ -91,6 +93,9 @@ func (h *Handler) GetByIDSalePoint(c *gin.Context) {
 // @Param limit query int false "Number of items to return (default 10)"
 // @Param offset query int false "Number of items to skip (default 0)"
 // @Param search query string false "Search term"
+// @Param created_at[between] query string false "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
 // @Success 200 {array} models.SalePoint "List of sale points"
 // @Failure 400 {object} ErrorResponse "Bad Request"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
@@ -111,20 +116,33 @@ func (h *Handler) GetListSalePoint(c *gin.Context) {
 
 	search := c.Query("search")
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
 	defer cancel()
 
 	resp, err := h.strg.Sale_Point().GetList(ctx, &models.GetListSalePointRequest{
-		Limit:  limit,
-		Offset: offset,
-		Search: search,
+		Limit:   limit,
+		Offset:  offset,
+		Search:  search,
+		Filters: list.Filters,
+		Sort:    list.Sort,
 	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		handleResponse(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	handleResponse(c, http.StatusOK, resp)
+	handleListResponse(c, resp, list.Fields)
 }
 
 // @Summary Update a sale point
@@ -3,10 +3,13 @@ package handler
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"market_system/config"
 	"market_system/models"
+	"market_system/pkg/criteria"
 	"market_system/pkg/helpers"
 
 	"github.com/gin-gonic/gin"
@@ -43,6 +46,18 @@ func (h *Handler) CreateSaleProduct(c *gin.Context) {
 		return
 	}
 
+	err = h.applyPromotions(ctx, resp.SaleID)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp, err = h.strg.Sale_Product().GetByID(ctx, &models.SaleProductPrimaryKey{Id: resp.Id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
 	handleResponse(c, http.StatusCreated, resp)
 }
 
@@ -67,7 +82,7 @@ func (h *Handler) GetByIDSaleProduct(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	ctx, cancel := requestContext(c)
 	defer cancel()
 
 	resp, err := h.strg.Sale_Product().GetByID(ctx, &models.SaleProductPrimaryKey{Id: id})
@@ -85,7 +100,7 @@ func (h *Handler) GetByIDSaleProduct(c *gin.Context) {
 }
 
 // @Summary Get a list of sale products
-// @Description Get a list of sale products with optional filtering.
+// @Description Get a list of sale products with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, sale_id, category_id, product_name, barcode, remaining_quantity, quantity, allow_discount, discount_type, discount, price, total_amount, created_at, updated_at. Sorted by created_at alone (the default) the list is also paged by cursor: a page with more after it has a next_cursor to pass as cursor, which is much faster than a large offset.
 // @Tags sale_product
 // @Accept json
 // @Produce json
@@ -94,6 +109,11 @@ func (h *Handler) GetByIDSaleProduct(c *gin.Context) {
 // @Param limit query int false "Number of items to return (default 10)"
 // @Param offset query int false "Number of items to skip (default 0)"
 // @Param search query string false "Search term"
+// @Param created_at[between] query string false "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
+// @Param cursor query string false "next_cursor of the page before, for lists sorted by created_at alone"
+// @Param count query bool false "Count all matching items (default true without a cursor, false with one)"
 // @Success 200 {object} models.GetListSaleProductResponse "List of sale products"
 // @Failure 400 {object} ErrorResponse "Bad Request"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
@@ -114,20 +134,44 @@ func (h *Handler) GetListSaleProduct(c *gin.Context) {
 
 	search := c.Query("search")
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	var count = len(c.Query("cursor")) == 0
+	if len(c.Query("count")) > 0 {
+		count, err = strconv.ParseBool(c.Query("count"))
+		if err != nil {
+			handleResponse(c, http.StatusBadRequest, "invalid query count")
+			return
+		}
+	}
+
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
 	defer cancel()
 
 	resp, err := h.strg.Sale_Product().GetList(ctx, &models.GetListSaleProductRequest{
-		Limit:  limit,
-		Offset: offset,
-		Search: search,
+		Limit:   limit,
+		Offset:  offset,
+		Search:  search,
+		Filters: list.Filters,
+		Sort:    list.Sort,
+		Cursor:  c.Query("cursor"),
+		Count:   count,
 	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		handleResponse(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	handleResponse(c, http.StatusOK, resp)
+	handleListResponse(c, resp, list.Fields)
 }
 
 // @Summary Update a sale product
@@ -184,6 +228,18 @@ func (h *Handler) UpdateSaleProduct(c *gin.Context) {
 		return
 	}
 
+	err = h.applyPromotions(ctx, resp.SaleID)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp, err = h.strg.Sale_Product().GetByID(ctx, &models.SaleProductPrimaryKey{Id: resp.Id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
 	handleResponse(c, http.StatusAccepted, resp)
 }
 
@@ -211,7 +267,19 @@ func (h *Handler) DeleteSaleProduct(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
 	defer cancel()
 
-	err := h.strg.Sale_Product().Delete(ctx, &models.SaleProductPrimaryKey{Id: id})
+	saleProduct, err := h.strg.Sale_Product().GetByID(ctx, &models.SaleProductPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = h.strg.Sale_Product().Delete(ctx, &models.SaleProductPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	err = h.applyPromotions(ctx, saleProduct.SaleID)
 	if err != nil {
 		handleResponse(c, http.StatusInternalServerError, err)
 		return
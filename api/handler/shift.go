@@ -3,10 +3,11 @@ package handler
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"errors"
 	"net/http"
 
 	"market_system/config"
+	"market_system/pkg/criteria"
 	"market_system/pkg/helpers"
 
 	"market_system/models"
@@ -35,8 +36,14 @@ func (h *Handler) CreateShift(c *gin.Context) {
 		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
 		return
 	}
+
+	if !helpers.IsValidUUID(createShift.BranchID) {
+		handleResponse(c, http.StatusBadRequest, "branch_id is not uuid")
+		return
+	}
+
 	shiftsList, err := h.strg.Shift().GetList(c, &models.GetListShiftRequest{
-		Query: fmt.Sprintf(" AND brand_id = %s", createShift.BranchID),
+		Filters: []criteria.Filter{criteria.Equal("branch_id", createShift.BranchID)},
 	})
 	if err != nil {
 		handleResponse(c, http.StatusInternalServerError, err.Error())
@@ -86,7 +93,7 @@ func (h *Handler) GetByIDShift(c *gin.Context) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	ctx, cancel := requestContext(c)
 	defer cancel()
 
 	resp, err := h.strg.Shift().GetByID(ctx, &models.ShiftPrimaryKey{Id: id})
@@ -104,7 +111,7 @@ func (h *Handler) GetByIDShift(c *gin.Context) {
 }
 
 // @Summary Get a list of shifts
-// @Description Get a list of shifts with optional filtering.
+// @Description Get a list of shifts with optional filtering. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, branch_id, user_id, sale_point_id, status, open_shift, close_shift, opening_cash, counted_cash, created_at, updated_at.
 // @Tags shift
 // @Accept json
 // @Produce json
@@ -113,6 +120,9 @@ func (h *Handler) GetByIDShift(c *gin.Context) {
 // @Param limit query int false "Number of items to return (default 10)"
 // @Param offset query int false "Number of items to skip (default 0)"
 // @Param search query string false "Search term"
+// @Param created_at[between] query string false "Created date range, from and to (YYYY-MM-DD,YYYY-MM-DD)"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
 // @Success 200 {array} models.Shift "List of shifts"
 // @Failure 400 {object} ErrorResponse "Bad Request"
 // @Failure 401 {object} ErrorResponse "Unauthorized"
@@ -134,20 +144,33 @@ func (h *Handler) GetListShift(c *gin.Context) {
 
 	search := c.Query("search")
 
-	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
 	defer cancel()
 
 	resp, err := h.strg.Shift().GetList(ctx, &models.GetListShiftRequest{
-		Limit:  limit,
-		Offset: offset,
-		Search: search,
+		Limit:   limit,
+		Offset:  offset,
+		Search:  search,
+		Filters: list.Filters,
+		Sort:    list.Sort,
 	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		handleResponse(c, http.StatusInternalServerError, err)
 		return
 	}
 
-	handleResponse(c, http.StatusOK, resp)
+	handleListResponse(c, resp, list.Fields)
 }
 
 // @Summary Update a shift
@@ -0,0 +1,476 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+)
+
+// @Summary Set stock levels
+// @Description Set the minimum and maximum stock of a product in a branch. Setting them again for the same product and branch replaces the old levels.
+// @Tags stock_level
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param stock_level body models.CreateStockLevel true "Stock level information"
+// @Success 201 {object} models.StockLevel "Stock level"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/stock_level [post]
+func (h *Handler) CreateStockLevel(c *gin.Context) {
+
+	var createStockLevel models.CreateStockLevel
+	err := c.ShouldBindJSON(&createStockLevel)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	if !helpers.IsValidUUID(createStockLevel.BranchID) {
+		handleResponse(c, http.StatusBadRequest, "branch id is not uuid")
+		return
+	}
+
+	if !helpers.IsValidUUID(createStockLevel.ProductID) {
+		handleResponse(c, http.StatusBadRequest, "product id is not uuid")
+		return
+	}
+
+	if msg := validateStockLevel(createStockLevel.MinQuantity, createStockLevel.MaxQuantity); msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	resp, err := h.strg.StockLevel().Create(ctx, &createStockLevel)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusCreated, resp)
+}
+
+// @Summary Get stock levels by ID
+// @Description Get stock levels by ID.
+// @Tags stock_level
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Stock level ID"
+// @Success 200 {object} models.StockLevel "Stock level details"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/stock_level/{id} [get]
+func (h *Handler) GetByIDStockLevel(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.StockLevel().GetByID(ctx, &models.StockLevelPrimaryKey{Id: id})
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "no rows in result set")
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Get a list of stock levels
+// @Description Get a list of stock levels. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, branch_id, product_id, product_name, barcode, min_quantity, max_quantity, created_at, updated_at.
+// @Tags stock_level
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param limit query int false "Number of items to return (default 10)"
+// @Param offset query int false "Number of items to skip (default 0)"
+// @Param branch_id[eq] query string false "Branch ID"
+// @Param product_id[eq] query string false "Product ID"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
+// @Success 200 {object} models.GetListStockLevelResponse "List of stock levels"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/stock_level [get]
+func (h *Handler) GetListStockLevel(c *gin.Context) {
+
+	limit, err := getIntegerOrDefaultValue(c.Query("limit"), 10)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query limit")
+		return
+	}
+
+	offset, err := getIntegerOrDefaultValue(c.Query("offset"), 0)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query offset")
+		return
+	}
+
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.StockLevel().GetList(ctx, &models.GetListStockLevelRequest{
+		Limit:   limit,
+		Offset:  offset,
+		Filters: list.Filters,
+		Sort:    list.Sort,
+	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleListResponse(c, resp, list.Fields)
+}
+
+// @Summary Update stock levels
+// @Description Update the minimum and maximum stock of a stock level.
+// @Tags stock_level
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Stock level ID"
+// @Param stock_level body models.UpdateStockLevel true "Stock level information"
+// @Success 202 {object} models.StockLevel "Updated stock level"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/stock_level/{id} [put]
+func (h *Handler) UpdateStockLevel(c *gin.Context) {
+
+	var updateStockLevel models.UpdateStockLevel
+	err := c.ShouldBindJSON(&updateStockLevel)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	if msg := validateStockLevel(updateStockLevel.MinQuantity, updateStockLevel.MaxQuantity); msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+	updateStockLevel.Id = id
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	rowsAffected, err := h.strg.StockLevel().Update(ctx, &updateStockLevel)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "stock level not found")
+		return
+	}
+
+	resp, err := h.strg.StockLevel().GetByID(ctx, &models.StockLevelPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
+
+// @Summary Delete stock levels
+// @Description Delete stock levels by ID.
+// @Tags stock_level
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Stock level ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/stock_level/{id} [delete]
+func (h *Handler) DeleteStockLevel(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	err := h.strg.StockLevel().Delete(ctx, &models.StockLevelPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusNoContent, nil)
+}
+
+// @Summary Low-stock alert
+// @Description Products at or below their minimum stock, with what open purchase orders still have to deliver.
+// @Tags stock_level
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param branch_id query string false "Branch ID"
+// @Success 200 {object} models.LowStockResponse "Low-stock products"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/stock_level/low_stock [get]
+func (h *Handler) GetLowStock(c *gin.Context) {
+
+	var branchID = c.Query("branch_id")
+	if branchID != "" && !helpers.IsValidUUID(branchID) {
+		handleResponse(c, http.StatusBadRequest, "branch id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.StockLevel().LowStock(ctx, &models.LowStockRequest{BranchID: branchID})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Replenishment suggestions
+// @Description Order quantities for the products of a branch, from stock on hand, stock on order, recent sales and lead time, grouped by the supplier each product was last bought from.
+// @Tags replenishment
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param branch_id query string true "Branch ID"
+// @Param history_days query int false "Days of sales to average (default 28)"
+// @Param lead_days query int false "Days an order takes to arrive (default 3)"
+// @Param supplier_ids query string false "Comma separated supplier IDs"
+// @Success 200 {object} models.ReplenishmentResponse "Replenishment suggestions"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/replenishment [get]
+func (h *Handler) GetReplenishment(c *gin.Context) {
+
+	historyDays, err := getIntegerOrDefaultValue(c.Query("history_days"), 28)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query history_days")
+		return
+	}
+
+	leadDays, err := getIntegerOrDefaultValue(c.Query("lead_days"), 3)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query lead_days")
+		return
+	}
+
+	var supplierIDs []string
+	if c.Query("supplier_ids") != "" {
+		supplierIDs = strings.Split(c.Query("supplier_ids"), ",")
+	}
+
+	var req = models.ReplenishmentRequest{
+		BranchID:    c.Query("branch_id"),
+		HistoryDays: historyDays,
+		LeadDays:    leadDays,
+		SupplierIDs: supplierIDs,
+	}
+	if msg := validateReplenishment(&req); msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.StockLevel().Replenishment(ctx, &req)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Create purchase orders from replenishment suggestions
+// @Description Turn the current suggestions of a branch into one draft purchase order per supplier, priced at the last purchase price. Products never bought from a supplier are left out.
+// @Tags replenishment
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param replenishment body models.CreateReplenishmentOrders true "Branch and suggestion parameters"
+// @Success 201 {object} models.CreateReplenishmentOrdersResponse "Created purchase orders"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/replenishment/purchase_order [post]
+func (h *Handler) CreateReplenishmentOrders(c *gin.Context) {
+
+	var createOrders models.CreateReplenishmentOrders
+	err := c.ShouldBindJSON(&createOrders)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	if c.GetString("client_type") == "CASSIER" {
+		handleResponse(c, http.StatusForbidden, "cashier can not create purchase orders")
+		return
+	}
+
+	if createOrders.HistoryDays == 0 {
+		createOrders.HistoryDays = 28
+	}
+
+	if createOrders.LeadDays == 0 {
+		createOrders.LeadDays = 3
+	}
+
+	var req = models.ReplenishmentRequest{
+		BranchID:    createOrders.BranchID,
+		HistoryDays: createOrders.HistoryDays,
+		LeadDays:    createOrders.LeadDays,
+		SupplierIDs: createOrders.SupplierIDs,
+	}
+	if msg := validateReplenishment(&req); msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	if createOrders.ExpectedDate != "" && !helpers.IsValidDate(createOrders.ExpectedDate) {
+		handleResponse(c, http.StatusBadRequest, "expected date must be YYYY-MM-DD")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	suggestions, err := h.strg.StockLevel().Replenishment(ctx, &req)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var resp = models.CreateReplenishmentOrdersResponse{PurchaseOrders: []*models.PurchaseOrder{}}
+	for _, supplier := range suggestions.Suppliers {
+		if supplier.SupplierID == "" {
+			continue
+		}
+
+		var products []*models.CreatePurchaseOrderProduct
+		for _, line := range supplier.Lines {
+			products = append(products, &models.CreatePurchaseOrderProduct{
+				ProductID: line.ProductID,
+				Quantity:  line.Quantity,
+				Price:     line.Price,
+			})
+		}
+
+		purchaseOrder, err := h.strg.PurchaseOrder().Create(ctx, &models.CreatePurchaseOrder{
+			SupplierID:   supplier.SupplierID,
+			BranchID:     createOrders.BranchID,
+			ExpectedDate: createOrders.ExpectedDate,
+			Comment:      "replenishment",
+			CreatedBy:    c.GetString("user_id"),
+			Products:     products,
+		})
+		if err != nil {
+			handleResponse(c, http.StatusInternalServerError, err)
+			return
+		}
+
+		resp.PurchaseOrders = append(resp.PurchaseOrders, purchaseOrder)
+	}
+
+	handleResponse(c, http.StatusCreated, resp)
+}
+
+func validateStockLevel(min, max int64) string {
+
+	if min < 0 {
+		return "min quantity can not be negative"
+	}
+
+	if max < min {
+		return "max quantity can not be less than min quantity"
+	}
+
+	return ""
+}
+
+func validateReplenishment(req *models.ReplenishmentRequest) string {
+
+	if !helpers.IsValidUUID(req.BranchID) {
+		return "branch id is not uuid"
+	}
+
+	if req.HistoryDays <= 0 {
+		return "history days must be positive"
+	}
+
+	if req.LeadDays < 0 {
+		return "lead days can not be negative"
+	}
+
+	for _, supplierID := range req.SupplierIDs {
+		if !helpers.IsValidUUID(supplierID) {
+			return "supplier id is not uuid"
+		}
+	}
+
+	return ""
+}
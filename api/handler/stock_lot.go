@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get a list of stock lots
+// @Description Branch stock per lot and expiry date, in the order sales consume it (earliest expiry first). Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, branch_id, barcode, product_name, lot_number, expiry_date, quantity, cost, created_at, updated_at.
+// @Tags stock_lot
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param limit query int false "Number of items to return (default 10)"
+// @Param offset query int false "Number of items to skip (default 0)"
+// @Param branch_id[eq] query string false "Branch ID"
+// @Param barcode[eq] query string false "Barcode"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
+// @Param in_stock query bool false "Only lots with quantity left"
+// @Success 200 {object} models.GetListStockLotResponse "List of stock lots"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/stock_lot [get]
+func (h *Handler) GetListStockLot(c *gin.Context) {
+
+	limit, err := getIntegerOrDefaultValue(c.Query("limit"), 10)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query limit")
+		return
+	}
+
+	offset, err := getIntegerOrDefaultValue(c.Query("offset"), 0)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query offset")
+		return
+	}
+
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.StockLot().GetList(ctx, &models.GetListStockLotRequest{
+		Limit:   limit,
+		Offset:  offset,
+		Filters: list.Filters,
+		Sort:    list.Sort,
+		InStock: c.Query("in_stock") == "true",
+	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleListResponse(c, resp, list.Fields)
+}
+
+// @Summary Expiring goods
+// @Description Lots in stock that expire within the given number of days, already expired ones included, so staff can discount or pull them.
+// @Tags stock_lot
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param branch_id query string false "Branch ID"
+// @Param days query int false "Days ahead (default 7)"
+// @Success 200 {object} models.ExpiringStockLotResponse "Expiring lots"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/stock_lot/expiring [get]
+func (h *Handler) GetExpiringStockLots(c *gin.Context) {
+
+	days, err := getIntegerOrDefaultValue(c.Query("days"), 7)
+	if err != nil || days < 0 {
+		handleResponse(c, http.StatusBadRequest, "invalid query days")
+		return
+	}
+
+	var branchID = c.Query("branch_id")
+	if branchID != "" && !helpers.IsValidUUID(branchID) {
+		handleResponse(c, http.StatusBadRequest, "branch id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.StockLot().Expiring(ctx, &models.ExpiringStockLotRequest{
+		BranchID: branchID,
+		Days:     days,
+	})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
@@ -0,0 +1,276 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/einvoice"
+	"market_system/pkg/helpers"
+	"market_system/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+)
+
+// @Summary Import a supplier invoice
+// @Description Read a supplier invoice (CSV or XLSX with a header row, or a Soliq/Didox e-invoice as JSON or XML) into a new draft income. Lines are matched to products by barcode, then by the mapping saved for the supplier, then by name; matched lines become income products. Every line lists the issues the receiving clerk should check, and unmatched or invalid lines have to be matched or skipped before the income is posted.
+// @Tags supplier_invoice
+// @Accept multipart/form-data
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param file formData file true "Invoice file (.csv, .xlsx, .json or .xml)"
+// @Param branch_id formData string true "Receiving branch ID"
+// @Param supplier_id formData string true "Supplier ID"
+// @Param format formData string false "File format (csv, xlsx, json, xml), taken from the file name by default"
+// @Param number formData string false "Invoice number when the file has none"
+// @Param date formData string false "Invoice date (YYYY-MM-DD) when the file has none"
+// @Success 201 {object} models.SupplierInvoice "Imported invoice with its lines"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/supplier_invoice [post]
+func (h *Handler) ImportSupplierInvoice(c *gin.Context) {
+
+	if c.GetString("client_type") == "CASSIER" {
+		handleResponse(c, http.StatusForbidden, "cashier can not import invoices")
+		return
+	}
+
+	var req = models.ImportSupplierInvoice{
+		BranchID:   c.PostForm("branch_id"),
+		SupplierID: c.PostForm("supplier_id"),
+		Format:     c.PostForm("format"),
+		Number:     c.PostForm("number"),
+		Date:       c.PostForm("date"),
+		CreatedBy:  c.GetString("user_id"),
+	}
+
+	if !helpers.IsValidUUID(req.BranchID) {
+		handleResponse(c, http.StatusBadRequest, "branch_id is not uuid")
+		return
+	}
+
+	if !helpers.IsValidUUID(req.SupplierID) {
+		handleResponse(c, http.StatusBadRequest, "supplier_id is not uuid")
+		return
+	}
+
+	if req.Date != "" && !helpers.IsValidDate(req.Date) {
+		handleResponse(c, http.StatusBadRequest, "date must be YYYY-MM-DD")
+		return
+	}
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "file is required")
+		return
+	}
+
+	req.FileName = header.Filename
+	if len(req.Format) == 0 {
+		req.Format = strings.ToLower(strings.TrimPrefix(filepath.Ext(header.Filename), "."))
+	}
+	if !helpers.Contains(einvoice.Formats, req.Format) {
+		handleResponse(c, http.StatusBadRequest, "format must be one of "+strings.Join(einvoice.Formats, ", "))
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer file.Close()
+	req.File = file
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.ProductImportTimeout)
+	defer cancel()
+
+	resp, err := h.strg.SupplierInvoice().Import(ctx, &req)
+	var fileErr *einvoice.FileError
+	if errors.As(err, &fileErr) {
+		handleResponse(c, http.StatusBadRequest, fileErr.Message)
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusCreated, resp)
+}
+
+// @Summary Get supplier invoice by ID
+// @Description Get an imported supplier invoice with its lines, their matches and issues.
+// @Tags supplier_invoice
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Supplier invoice ID"
+// @Success 200 {object} models.SupplierInvoice "Supplier invoice"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/supplier_invoice/{id} [get]
+func (h *Handler) GetByIDSupplierInvoice(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.SupplierInvoice().GetByID(ctx, &models.SupplierInvoicePrimaryKey{Id: id})
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "no rows in result set")
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Get a list of supplier invoices
+// @Description Get imported supplier invoices, latest first, with the number of lines that are matched, have issues or are unresolved. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, income_id, income_status, supplier_id, branch_id, format, file_name, number, invoice_date, seller_tin, seller_name, total_amount, unresolved, created_by, created_at.
+// @Tags supplier_invoice
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param limit query int false "Number of items to return (default 10)"
+// @Param offset query int false "Number of items to skip (default 0)"
+// @Param supplier_id[eq] query string false "Supplier ID"
+// @Param branch_id[eq] query string false "Branch ID"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
+// @Success 200 {object} models.GetListSupplierInvoiceResponse "List of supplier invoices"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/supplier_invoice [get]
+func (h *Handler) GetListSupplierInvoice(c *gin.Context) {
+
+	limit, err := getIntegerOrDefaultValue(c.Query("limit"), 10)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query limit")
+		return
+	}
+
+	offset, err := getIntegerOrDefaultValue(c.Query("offset"), 0)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query offset")
+		return
+	}
+
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.SupplierInvoice().GetList(ctx, &models.GetListSupplierInvoiceRequest{
+		Limit:   limit,
+		Offset:  offset,
+		Filters: list.Filters,
+		Sort:    list.Sort,
+	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleListResponse(c, resp, list.Fields)
+}
+
+// @Summary Resolve a supplier invoice line
+// @Description Match a line of an invoice whose income is not posted yet to a product, optionally remembering the match for the supplier's future invoices, or skip the line so it is not received.
+// @Tags supplier_invoice
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Supplier invoice ID"
+// @Param line path int true "Line number"
+// @Param resolve body models.ResolveSupplierInvoiceLine true "Product to match (product_id, remember) or skip"
+// @Success 200 {object} models.SupplierInvoiceLine "Resolved line"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/supplier_invoice/{id}/line/{line} [put]
+func (h *Handler) ResolveSupplierInvoiceLine(c *gin.Context) {
+
+	var resolve models.ResolveSupplierInvoiceLine
+	err := c.ShouldBindJSON(&resolve)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	if c.GetString("client_type") == "CASSIER" {
+		handleResponse(c, http.StatusForbidden, "cashier can not resolve invoices")
+		return
+	}
+
+	resolve.InvoiceID = c.Param("id")
+	if !helpers.IsValidUUID(resolve.InvoiceID) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	resolve.Line, err = strconv.ParseInt(c.Param("line"), 10, 64)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "line is not a number")
+		return
+	}
+
+	if !resolve.Skip && !helpers.IsValidUUID(resolve.ProductID) {
+		handleResponse(c, http.StatusBadRequest, "product_id is not uuid")
+		return
+	}
+
+	resolve.CreatedBy = c.GetString("user_id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	resp, err := h.strg.SupplierInvoice().ResolveLine(ctx, &resolve)
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "no rows in result set")
+		return
+	}
+	if errors.Is(err, storage.ErrIncomeFinished) || errors.Is(err, storage.ErrSupplierInvoiceLineInvalid) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/helpers"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+)
+
+// @Summary Pay a supplier
+// @Description Record a cash or bank payment to a supplier. Partial payments are allowed; they settle the oldest invoices first.
+// @Tags supplier
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Supplier ID"
+// @Param payment body models.CreateSupplierPayment true "Payment information"
+// @Success 201 {object} models.SupplierTransaction "Recorded payment with the balance owed after it"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/supplier/{id}/payment [post]
+func (h *Handler) CreateSupplierPayment(c *gin.Context) {
+
+	var createPayment models.CreateSupplierPayment
+	err := c.ShouldBindJSON(&createPayment)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+	createPayment.SupplierID = id
+
+	if c.GetString("client_type") == "CASSIER" {
+		handleResponse(c, http.StatusForbidden, "cashier can not pay suppliers")
+		return
+	}
+
+	if createPayment.BranchID != "" && !helpers.IsValidUUID(createPayment.BranchID) {
+		handleResponse(c, http.StatusBadRequest, "branch id is not uuid")
+		return
+	}
+
+	if createPayment.Amount <= 0 {
+		handleResponse(c, http.StatusBadRequest, "amount must be positive")
+		return
+	}
+
+	if createPayment.Method != config.SupplierPaymentCash && createPayment.Method != config.SupplierPaymentBank {
+		handleResponse(c, http.StatusBadRequest, "method must be cash or bank")
+		return
+	}
+	createPayment.CreatedBy = c.GetString("user_id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	_, err = h.strg.Supplier().GetByID(ctx, &models.SupplierPrimaryKey{Id: id})
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "no rows in result set")
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp, err := h.strg.SupplierPayable().CreatePayment(ctx, &createPayment)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusCreated, resp)
+}
+
+// @Summary Supplier account statement
+// @Description Invoices from finished incomes, payments and returns of a supplier over a date range with opening, running and closing balance, and aging as of to_date.
+// @Tags supplier
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Supplier ID"
+// @Param from_date query string false "Period start (inclusive), from the first entry when empty"
+// @Param to_date query string false "Period end (exclusive), now when empty"
+// @Success 200 {object} models.SupplierStatement "Account statement"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/supplier/{id}/statement [get]
+func (h *Handler) GetSupplierStatement(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.SupplierPayable().Statement(ctx, &models.SupplierStatementRequest{
+		SupplierID: id,
+		FromDate:   c.Query("from_date"),
+		ToDate:     c.Query("to_date"),
+	})
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "no rows in result set")
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Supplier payables aging
+// @Description What is owed to each supplier split into 0-30, 31-60, 61-90 and over 90 days by invoice age, after payments and returns settle the oldest invoices.
+// @Tags supplier
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param at query string false "Aging date, now when empty"
+// @Param supplier_id query string false "Supplier ID"
+// @Success 200 {object} models.SupplierAgingResponse "Aging per supplier"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/supplier/aging [get]
+func (h *Handler) GetSupplierAgingReport(c *gin.Context) {
+
+	var supplierID = c.Query("supplier_id")
+	if supplierID != "" && !helpers.IsValidUUID(supplierID) {
+		handleResponse(c, http.StatusBadRequest, "supplier id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.SupplierPayable().Aging(ctx, &models.SupplierAgingRequest{
+		At:         c.Query("at"),
+		SupplierID: supplierID,
+	})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
@@ -0,0 +1,446 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+	"market_system/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+)
+
+// @Summary Create a supplier return
+// @Description Create a draft return of goods from a branch to a supplier, optionally linked to the income they came with.
+// @Tags supplier_return
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param supplier_return body models.CreateSupplierReturn true "Supplier return information"
+// @Success 201 {object} models.SupplierReturn "Created supplier return"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/supplier_return [post]
+func (h *Handler) CreateSupplierReturn(c *gin.Context) {
+
+	var createSupplierReturn models.CreateSupplierReturn
+	err := c.ShouldBindJSON(&createSupplierReturn)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	if !helpers.IsValidUUID(createSupplierReturn.SupplierID) {
+		handleResponse(c, http.StatusBadRequest, "supplier id is not uuid")
+		return
+	}
+
+	if !helpers.IsValidUUID(createSupplierReturn.BranchID) {
+		handleResponse(c, http.StatusBadRequest, "branch id is not uuid")
+		return
+	}
+
+	if msg := validateSupplierReturnProducts(createSupplierReturn.Products); msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+	createSupplierReturn.CreatedBy = c.GetString("user_id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	if msg, err := h.checkSupplierReturnIncome(ctx, createSupplierReturn.IncomeID, createSupplierReturn.SupplierID, createSupplierReturn.BranchID); err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	} else if msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	resp, err := h.strg.SupplierReturn().Create(ctx, &createSupplierReturn)
+	if errors.Is(err, pgx.ErrNoRows) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusCreated, resp)
+}
+
+// @Summary Get a supplier return by ID
+// @Description Get a supplier return with its lines.
+// @Tags supplier_return
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Supplier return ID"
+// @Success 200 {object} models.SupplierReturn "Supplier return details"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/supplier_return/{id} [get]
+func (h *Handler) GetByIDSupplierReturn(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.SupplierReturn().GetByID(ctx, &models.SupplierReturnPrimaryKey{Id: id})
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "no rows in result set")
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Get a list of supplier returns
+// @Description Get a list of supplier returns. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, supplier_id, branch_id, income_id, status, created_by, posted_at, created_at, updated_at.
+// @Tags supplier_return
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param limit query int false "Number of items to return (default 10)"
+// @Param offset query int false "Number of items to skip (default 0)"
+// @Param supplier_id[eq] query string false "Supplier ID"
+// @Param branch_id[eq] query string false "Branch ID"
+// @Param income_id[eq] query string false "Income ID"
+// @Param status[eq] query string false "draft, posted or canceled"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
+// @Success 200 {object} models.GetListSupplierReturnResponse "List of supplier returns"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/supplier_return [get]
+func (h *Handler) GetListSupplierReturn(c *gin.Context) {
+
+	limit, err := getIntegerOrDefaultValue(c.Query("limit"), 10)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query limit")
+		return
+	}
+
+	offset, err := getIntegerOrDefaultValue(c.Query("offset"), 0)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query offset")
+		return
+	}
+
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.SupplierReturn().GetList(ctx, &models.GetListSupplierReturnRequest{
+		Limit:   limit,
+		Offset:  offset,
+		Filters: list.Filters,
+		Sort:    list.Sort,
+	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleListResponse(c, resp, list.Fields)
+}
+
+// @Summary Update a supplier return
+// @Description Replace the income link, comment and lines of a draft supplier return.
+// @Tags supplier_return
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Supplier return ID"
+// @Param supplier_return body models.UpdateSupplierReturn true "Updated supplier return information"
+// @Success 202 {object} models.SupplierReturn "Updated supplier return"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/supplier_return/{id} [put]
+func (h *Handler) UpdateSupplierReturn(c *gin.Context) {
+
+	var updateSupplierReturn models.UpdateSupplierReturn
+
+	err := c.ShouldBindJSON(&updateSupplierReturn)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+	updateSupplierReturn.Id = id
+
+	if msg := validateSupplierReturnProducts(updateSupplierReturn.Products); msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	current, err := h.strg.SupplierReturn().GetByID(ctx, &models.SupplierReturnPrimaryKey{Id: id})
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "no rows in result set")
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if msg, err := h.checkSupplierReturnIncome(ctx, updateSupplierReturn.IncomeID, current.SupplierID, current.BranchID); err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	} else if msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	rowsAffected, err := h.strg.SupplierReturn().Update(ctx, &updateSupplierReturn)
+	if errors.Is(err, pgx.ErrNoRows) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "supplier return not found or not a draft")
+		return
+	}
+
+	resp, err := h.strg.SupplierReturn().GetByID(ctx, &models.SupplierReturnPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
+
+// @Summary Delete a supplier return
+// @Description Delete a supplier return that is still a draft.
+// @Tags supplier_return
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Supplier return ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/supplier_return/{id} [delete]
+func (h *Handler) DeleteSupplierReturn(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	err := h.strg.SupplierReturn().Delete(ctx, &models.SupplierReturnPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusNoContent, nil)
+}
+
+// @Summary Post a supplier return
+// @Description Post a draft supplier return: its lines leave branch stock and the supplier balance is credited with the return cost.
+// @Tags supplier_return
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Supplier return ID"
+// @Success 202 {object} models.SupplierReturn "Posted supplier return"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/supplier_return/{id}/post [post]
+func (h *Handler) PostSupplierReturn(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	if c.GetString("client_type") == "CASSIER" {
+		handleResponse(c, http.StatusForbidden, "cashier can not post supplier returns")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	rowsAffected, err := h.strg.SupplierReturn().Post(ctx, &models.SupplierReturnPrimaryKey{Id: id})
+	if errors.Is(err, storage.ErrNotEnoughStock) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "supplier return not found or not a draft")
+		return
+	}
+
+	resp, err := h.strg.SupplierReturn().GetByID(ctx, &models.SupplierReturnPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
+
+// @Summary Cancel a supplier return
+// @Description Cancel a draft supplier return.
+// @Tags supplier_return
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Supplier return ID"
+// @Success 202 {object} models.SupplierReturn "Canceled supplier return"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/supplier_return/{id}/cancel [post]
+func (h *Handler) CancelSupplierReturn(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	rowsAffected, err := h.strg.SupplierReturn().Cancel(ctx, &models.SupplierReturnPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "supplier return not found or not a draft")
+		return
+	}
+
+	resp, err := h.strg.SupplierReturn().GetByID(ctx, &models.SupplierReturnPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
+
+// checkSupplierReturnIncome makes sure a linked income came from the same
+// supplier into the same branch. It returns a message for the client, or an
+// error when the income could not be read.
+func (h *Handler) checkSupplierReturnIncome(ctx context.Context, incomeID, supplierID, branchID string) (string, error) {
+
+	if incomeID == "" {
+		return "", nil
+	}
+
+	if !helpers.IsValidUUID(incomeID) {
+		return "income id is not uuid", nil
+	}
+
+	income, err := h.strg.Income().GetByID(ctx, &models.IncomePrimaryKey{Id: incomeID})
+	if err == pgx.ErrNoRows {
+		return "income not found", nil
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	if income.SupplierID != supplierID || income.BranchID != branchID {
+		return "income is from another supplier or branch", nil
+	}
+
+	return "", nil
+}
+
+func validateSupplierReturnProducts(products []*models.CreateSupplierReturnProduct) string {
+
+	if len(products) <= 0 {
+		return "products are required"
+	}
+
+	for _, product := range products {
+		if product.Barcode == "" {
+			return "barcode is required"
+		}
+
+		if product.Quantity <= 0 {
+			return "quantity must be positive"
+		}
+
+		if product.Cost < 0 {
+			return "cost must not be negative"
+		}
+	}
+
+	return ""
+}
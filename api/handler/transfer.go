@@ -0,0 +1,514 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+	"market_system/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+)
+
+// @Summary Create a transfer
+// @Description Create a draft transfer of goods from one branch to another.
+// @Tags transfer
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param transfer body models.CreateTransfer true "Transfer information"
+// @Success 201 {object} models.Transfer "Created transfer"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/transfer [post]
+func (h *Handler) CreateTransfer(c *gin.Context) {
+
+	var createTransfer models.CreateTransfer
+	err := c.ShouldBindJSON(&createTransfer)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	if msg := validateTransfer(createTransfer.FromBranchID, createTransfer.ToBranchID, createTransfer.Products); msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+	createTransfer.CreatedBy = c.GetString("user_id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	resp, err := h.strg.Transfer().Create(ctx, &createTransfer)
+	if errors.Is(err, pgx.ErrNoRows) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusCreated, resp)
+}
+
+// @Summary Get a transfer by ID
+// @Description Get a transfer with its lines and shortages.
+// @Tags transfer
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Transfer ID"
+// @Success 200 {object} models.Transfer "Transfer details"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/transfer/{id} [get]
+func (h *Handler) GetByIDTransfer(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.Transfer().GetByID(ctx, &models.TransferPrimaryKey{Id: id})
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "no rows in result set")
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Get a list of transfers
+// @Description Get a list of transfers. branch_id matches either end of the transfer. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, from_branch_id, to_branch_id, status, created_by, dispatched_at, received_by, received_at, created_at, updated_at.
+// @Tags transfer
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param limit query int false "Number of items to return (default 10)"
+// @Param offset query int false "Number of items to skip (default 0)"
+// @Param status[eq] query string false "draft, in_transit, received or canceled"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
+// @Param branch_id query string false "Source or destination branch ID"
+// @Success 200 {object} models.GetListTransferResponse "List of transfers"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/transfer [get]
+func (h *Handler) GetListTransfer(c *gin.Context) {
+
+	limit, err := getIntegerOrDefaultValue(c.Query("limit"), 10)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query limit")
+		return
+	}
+
+	offset, err := getIntegerOrDefaultValue(c.Query("offset"), 0)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query offset")
+		return
+	}
+
+	var branchID = c.Query("branch_id")
+	if branchID != "" && !helpers.IsValidUUID(branchID) {
+		handleResponse(c, http.StatusBadRequest, "branch id is not uuid")
+		return
+	}
+
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.Transfer().GetList(ctx, &models.GetListTransferRequest{
+		Limit:    limit,
+		Offset:   offset,
+		Filters:  list.Filters,
+		Sort:     list.Sort,
+		BranchID: branchID,
+	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleListResponse(c, resp, list.Fields)
+}
+
+// @Summary Update a transfer
+// @Description Replace the destination, comment and lines of a draft transfer.
+// @Tags transfer
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Transfer ID"
+// @Param transfer body models.UpdateTransfer true "Updated transfer information"
+// @Success 202 {object} models.Transfer "Updated transfer"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/transfer/{id} [put]
+func (h *Handler) UpdateTransfer(c *gin.Context) {
+
+	var updateTransfer models.UpdateTransfer
+
+	err := c.ShouldBindJSON(&updateTransfer)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+	updateTransfer.Id = id
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	current, err := h.strg.Transfer().GetByID(ctx, &models.TransferPrimaryKey{Id: id})
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "no rows in result set")
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if msg := validateTransfer(current.FromBranchID, updateTransfer.ToBranchID, updateTransfer.Products); msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	rowsAffected, err := h.strg.Transfer().Update(ctx, &updateTransfer)
+	if errors.Is(err, pgx.ErrNoRows) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "transfer not found or not a draft")
+		return
+	}
+
+	resp, err := h.strg.Transfer().GetByID(ctx, &models.TransferPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
+
+// @Summary Delete a transfer
+// @Description Delete a transfer that is still a draft.
+// @Tags transfer
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Transfer ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/transfer/{id} [delete]
+func (h *Handler) DeleteTransfer(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	err := h.strg.Transfer().Delete(ctx, &models.TransferPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusNoContent, nil)
+}
+
+// @Summary Dispatch a transfer
+// @Description Take the lines of a draft transfer out of the source branch stock. The goods stay in transit until the destination receives them.
+// @Tags transfer
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Transfer ID"
+// @Success 202 {object} models.Transfer "Transfer in transit"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/transfer/{id}/dispatch [post]
+func (h *Handler) DispatchTransfer(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	rowsAffected, err := h.strg.Transfer().Dispatch(ctx, &models.TransferPrimaryKey{Id: id})
+	if errors.Is(err, storage.ErrNotEnoughStock) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "transfer not found or not a draft")
+		return
+	}
+
+	resp, err := h.strg.Transfer().GetByID(ctx, &models.TransferPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
+
+// @Summary Receive a transfer
+// @Description Put an in-transit transfer into the destination branch stock. Lines not listed are received in full; a lower received_quantity is recorded as a shortage.
+// @Tags transfer
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Transfer ID"
+// @Param receipt body models.ReceiveTransfer true "Received quantities"
+// @Success 202 {object} models.Transfer "Received transfer"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/transfer/{id}/receive [post]
+func (h *Handler) ReceiveTransfer(c *gin.Context) {
+
+	var receive models.ReceiveTransfer
+	err := c.ShouldBindJSON(&receive)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+	receive.Id = id
+	receive.ReceivedBy = c.GetString("user_id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	transfer, err := h.strg.Transfer().GetByID(ctx, &models.TransferPrimaryKey{Id: id})
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "no rows in result set")
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	var dispatched = make(map[string]int64, len(transfer.Products))
+	for _, product := range transfer.Products {
+		dispatched[product.Id] = product.Quantity
+	}
+
+	for _, product := range receive.Products {
+		quantity, ok := dispatched[product.TransferProductID]
+		if !ok {
+			handleResponse(c, http.StatusBadRequest, "transfer product "+product.TransferProductID+" not found")
+			return
+		}
+
+		if product.ReceivedQuantity < 0 || product.ReceivedQuantity > quantity {
+			handleResponse(c, http.StatusBadRequest, "received quantity must be between 0 and the dispatched quantity")
+			return
+		}
+	}
+
+	rowsAffected, err := h.strg.Transfer().Receive(ctx, &receive)
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "transfer not found or not in transit")
+		return
+	}
+
+	resp, err := h.strg.Transfer().GetByID(ctx, &models.TransferPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
+
+// @Summary Cancel a transfer
+// @Description Cancel a draft transfer.
+// @Tags transfer
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Transfer ID"
+// @Success 202 {object} models.Transfer "Canceled transfer"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/transfer/{id}/cancel [post]
+func (h *Handler) CancelTransfer(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	rowsAffected, err := h.strg.Transfer().Cancel(ctx, &models.TransferPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "transfer not found or not a draft")
+		return
+	}
+
+	resp, err := h.strg.Transfer().GetByID(ctx, &models.TransferPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
+
+// @Summary In-transit report
+// @Description Quantity and cost of dispatched but not yet received transfers per branch, outgoing and incoming.
+// @Tags transfer
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param branch_id query string false "Branch ID"
+// @Success 200 {object} models.InTransitResponse "In-transit goods per branch"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/transfer/in_transit [get]
+func (h *Handler) GetInTransitReport(c *gin.Context) {
+
+	var branchID = c.Query("branch_id")
+	if branchID != "" && !helpers.IsValidUUID(branchID) {
+		handleResponse(c, http.StatusBadRequest, "branch id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.Transfer().InTransit(ctx, &models.InTransitRequest{BranchID: branchID})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+func validateTransfer(fromBranchID, toBranchID string, products []*models.CreateTransferProduct) string {
+
+	if !helpers.IsValidUUID(fromBranchID) {
+		return "from branch id is not uuid"
+	}
+
+	if !helpers.IsValidUUID(toBranchID) {
+		return "to branch id is not uuid"
+	}
+
+	if fromBranchID == toBranchID {
+		return "from and to branch must differ"
+	}
+
+	if len(products) <= 0 {
+		return "products are required"
+	}
+
+	for _, product := range products {
+		if product.Barcode == "" {
+			return "barcode is required"
+		}
+
+		if product.Quantity <= 0 {
+			return "quantity must be positive"
+		}
+	}
+
+	return ""
+}
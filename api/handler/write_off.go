@@ -0,0 +1,508 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+	"market_system/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+)
+
+// @Summary Create a write-off
+// @Description Create a draft write-off of branch stock for a reason: expired, damaged, theft or internal_use.
+// @Tags write_off
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param write_off body models.CreateWriteOff true "Write-off information"
+// @Success 201 {object} models.WriteOff "Created write-off"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/write_off [post]
+func (h *Handler) CreateWriteOff(c *gin.Context) {
+
+	var createWriteOff models.CreateWriteOff
+	err := c.ShouldBindJSON(&createWriteOff)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "ShouldBindJSON err:"+err.Error())
+		return
+	}
+
+	if !helpers.IsValidUUID(createWriteOff.BranchID) {
+		handleResponse(c, http.StatusBadRequest, "branch id is not uuid")
+		return
+	}
+
+	if msg := validateWriteOff(createWriteOff.Reason, createWriteOff.Products); msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+	createWriteOff.CreatedBy = c.GetString("user_id")
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	resp, err := h.strg.WriteOff().Create(ctx, &createWriteOff)
+	if errors.Is(err, pgx.ErrNoRows) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusCreated, resp)
+}
+
+// @Summary Get a write-off by ID
+// @Description Get a write-off with its lines.
+// @Tags write_off
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Write-off ID"
+// @Success 200 {object} models.WriteOff "Write-off details"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/write_off/{id} [get]
+func (h *Handler) GetByIDWriteOff(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.WriteOff().GetByID(ctx, &models.WriteOffPrimaryKey{Id: id})
+	if err == pgx.ErrNoRows {
+		handleResponse(c, http.StatusBadRequest, "no rows in result set")
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+// @Summary Get a list of write-offs
+// @Description Get a list of write-offs. Filter with field[op]=value, op one of eq, ne, lt, lte, gt, gte, in, between, contains, is_null, not_null; in and between take comma separated values and dates are YYYY-MM-DD or YYYY-MM-DD HH:MM:SS. Filter and sort fields: id, branch_id, reason, status, created_by, reviewed_by, reviewed_at, posted_at, created_at, updated_at.
+// @Tags write_off
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param limit query int false "Number of items to return (default 10)"
+// @Param offset query int false "Number of items to skip (default 0)"
+// @Param branch_id[eq] query string false "Branch ID"
+// @Param reason[eq] query string false "expired, damaged, theft or internal_use"
+// @Param status[eq] query string false "draft, pending_approval, posted, rejected or canceled"
+// @Param sort query string false "Sort fields, comma separated, descending when prefixed with - (e.g. -created_at,id)"
+// @Param fields query string false "Item fields to return, comma separated"
+// @Success 200 {object} models.GetListWriteOffResponse "List of write-offs"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/write_off [get]
+func (h *Handler) GetListWriteOff(c *gin.Context) {
+
+	limit, err := getIntegerOrDefaultValue(c.Query("limit"), 10)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query limit")
+		return
+	}
+
+	offset, err := getIntegerOrDefaultValue(c.Query("offset"), 0)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, "invalid query offset")
+		return
+	}
+
+	list, err := criteria.ParseQuery(c.Request.URL.Query())
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.WriteOff().GetList(ctx, &models.GetListWriteOffRequest{
+		Limit:   limit,
+		Offset:  offset,
+		Filters: list.Filters,
+		Sort:    list.Sort,
+	})
+	var criteriaErr *criteria.Error
+	if errors.As(err, &criteriaErr) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleListResponse(c, resp, list.Fields)
+}
+
+// @Summary Update a write-off
+// @Description Replace the reason, comment and lines of a draft write-off.
+// @Tags write_off
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Write-off ID"
+// @Param write_off body models.UpdateWriteOff true "Updated write-off information"
+// @Success 202 {object} models.WriteOff "Updated write-off"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/write_off/{id} [put]
+func (h *Handler) UpdateWriteOff(c *gin.Context) {
+
+	var updateWriteOff models.UpdateWriteOff
+
+	err := c.ShouldBindJSON(&updateWriteOff)
+	if err != nil {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+	updateWriteOff.Id = id
+
+	if msg := validateWriteOff(updateWriteOff.Reason, updateWriteOff.Products); msg != "" {
+		handleResponse(c, http.StatusBadRequest, msg)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	rowsAffected, err := h.strg.WriteOff().Update(ctx, &updateWriteOff)
+	if errors.Is(err, pgx.ErrNoRows) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "write-off not found or not a draft")
+		return
+	}
+
+	resp, err := h.strg.WriteOff().GetByID(ctx, &models.WriteOffPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
+
+// @Summary Delete a write-off
+// @Description Delete a write-off that is still a draft.
+// @Tags write_off
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Write-off ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/write_off/{id} [delete]
+func (h *Handler) DeleteWriteOff(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	err := h.strg.WriteOff().Delete(ctx, &models.WriteOffPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusNoContent, nil)
+}
+
+// @Summary Post a write-off
+// @Description Submit a draft write-off. Up to the approval threshold its lines leave branch stock at once; above it the write-off waits for approval.
+// @Tags write_off
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Write-off ID"
+// @Success 202 {object} models.WriteOff "Posted or pending write-off"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/write_off/{id}/post [post]
+func (h *Handler) PostWriteOff(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	rowsAffected, err := h.strg.WriteOff().Post(ctx, &models.PostWriteOff{
+		Id:                id,
+		ApprovalThreshold: h.cfg.WriteOffApprovalThreshold,
+	})
+	if errors.Is(err, storage.ErrNotEnoughStock) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "write-off not found or not a draft")
+		return
+	}
+
+	resp, err := h.strg.WriteOff().GetByID(ctx, &models.WriteOffPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
+
+// @Summary Approve a write-off
+// @Description Approve a write-off pending approval; its lines leave branch stock.
+// @Tags write_off
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Write-off ID"
+// @Success 202 {object} models.WriteOff "Posted write-off"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/write_off/{id}/approve [post]
+func (h *Handler) ApproveWriteOff(c *gin.Context) {
+	h.reviewWriteOff(c, h.strg.WriteOff().Approve)
+}
+
+// @Summary Reject a write-off
+// @Description Reject a write-off pending approval; stock is left untouched.
+// @Tags write_off
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Write-off ID"
+// @Success 202 {object} models.WriteOff "Rejected write-off"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 403 {object} ErrorResponse "Forbidden"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/write_off/{id}/reject [post]
+func (h *Handler) RejectWriteOff(c *gin.Context) {
+	h.reviewWriteOff(c, h.strg.WriteOff().Reject)
+}
+
+func (h *Handler) reviewWriteOff(c *gin.Context, review func(context.Context, *models.ReviewWriteOff) (int64, error)) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	if c.GetString("client_type") == "CASSIER" {
+		handleResponse(c, http.StatusForbidden, "cashier can not review write-offs")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	rowsAffected, err := review(ctx, &models.ReviewWriteOff{
+		Id:         id,
+		ReviewedBy: c.GetString("user_id"),
+	})
+	if errors.Is(err, storage.ErrNotEnoughStock) {
+		handleResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "write-off not found or not pending approval")
+		return
+	}
+
+	resp, err := h.strg.WriteOff().GetByID(ctx, &models.WriteOffPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
+
+// @Summary Cancel a write-off
+// @Description Cancel a draft write-off.
+// @Tags write_off
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param id path string true "Write-off ID"
+// @Success 202 {object} models.WriteOff "Canceled write-off"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/write_off/{id}/cancel [post]
+func (h *Handler) CancelWriteOff(c *gin.Context) {
+
+	var id = c.Param("id")
+	if !helpers.IsValidUUID(id) {
+		handleResponse(c, http.StatusBadRequest, "id is not uuid")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+	defer cancel()
+
+	rowsAffected, err := h.strg.WriteOff().Cancel(ctx, &models.WriteOffPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	if rowsAffected == 0 {
+		handleResponse(c, http.StatusBadRequest, "write-off not found or not a draft")
+		return
+	}
+
+	resp, err := h.strg.WriteOff().GetByID(ctx, &models.WriteOffPrimaryKey{Id: id})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusAccepted, resp)
+}
+
+// @Summary Shrinkage report
+// @Description Cost and quantity of posted write-offs per period, branch, category and reason, with totals per reason.
+// @Tags write_off
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Authentication token"
+// @Param Password header string true "User password"
+// @Param branch_id query string false "Branch ID"
+// @Param from_date query string false "Posted from (inclusive)"
+// @Param to_date query string false "Posted before (exclusive)"
+// @Param interval query string false "day, week or month (default month)"
+// @Success 200 {object} models.ShrinkageReportResponse "Shrinkage"
+// @Failure 400 {object} ErrorResponse "Bad Request"
+// @Failure 401 {object} ErrorResponse "Unauthorized"
+// @Failure 500 {object} ErrorResponse "Internal Server Error"
+// @Router /v1/write_off/report [get]
+func (h *Handler) GetShrinkageReport(c *gin.Context) {
+
+	var branchID = c.Query("branch_id")
+	if branchID != "" && !helpers.IsValidUUID(branchID) {
+		handleResponse(c, http.StatusBadRequest, "branch id is not uuid")
+		return
+	}
+
+	var interval = c.DefaultQuery("interval", "month")
+	if !helpers.Contains([]string{"day", "week", "month"}, interval) {
+		handleResponse(c, http.StatusBadRequest, "interval must be day, week or month")
+		return
+	}
+
+	ctx, cancel := requestContext(c)
+	defer cancel()
+
+	resp, err := h.strg.WriteOff().ShrinkageReport(ctx, &models.ShrinkageReportRequest{
+		BranchID: branchID,
+		FromDate: c.Query("from_date"),
+		ToDate:   c.Query("to_date"),
+		Interval: interval,
+	})
+	if err != nil {
+		handleResponse(c, http.StatusInternalServerError, err)
+		return
+	}
+
+	handleResponse(c, http.StatusOK, resp)
+}
+
+func validateWriteOff(reason string, products []*models.CreateWriteOffProduct) string {
+
+	if !helpers.Contains(config.WriteOffReasons, reason) {
+		return "reason must be expired, damaged, theft or internal_use"
+	}
+
+	if len(products) <= 0 {
+		return "products are required"
+	}
+
+	for _, product := range products {
+		if product.Barcode == "" {
+			return "barcode is required"
+		}
+
+		if product.Quantity <= 0 {
+			return "quantity must be positive"
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,128 @@
+// Command import validates a CSV or XLSX file of products and imports it.
+//
+//	go run ./cmd/import -file products.xlsx -dry-run
+//	go run ./cmd/import -file products.xlsx
+//	go run ./cmd/import -resume <import id>
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/productimport"
+	"market_system/storage/postgres"
+)
+
+func main() {
+
+	var (
+		file   = flag.String("file", "", "product file (.csv or .xlsx)")
+		format = flag.String("format", "", "file format (csv, xlsx), taken from the file name by default")
+		dryRun = flag.Bool("dry-run", false, "only validate the file")
+		resume = flag.String("resume", "", "id of a failed import to resume")
+	)
+	flag.Parse()
+
+	if (len(*file) == 0) == (len(*resume) == 0) {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var cfg = config.Load()
+
+	pgStorage, err := postgres.NewConnectionPostgres(&cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var importID = *resume
+	if len(importID) == 0 {
+		if len(*format) == 0 {
+			*format = strings.ToLower(strings.TrimPrefix(filepath.Ext(*file), "."))
+		}
+
+		f, err := os.Open(*file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer f.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), config.ProductImportTimeout)
+		defer cancel()
+
+		productImport, err := pgStorage.ProductImport().Create(ctx, &models.CreateProductImport{
+			FileName: filepath.Base(*file),
+			Format:   *format,
+			File:     f,
+		})
+		var fileErr *productimport.FileError
+		if errors.As(err, &fileErr) {
+			log.Fatal(fileErr.Message)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		printImport(productImport)
+
+		rows, err := pgStorage.ProductImport().GetRows(ctx, &models.GetListProductImportRowRequest{
+			Limit:    productImport.InvalidRows,
+			Filters:  []criteria.Filter{criteria.Equal("status", config.ProductImportRowInvalid)},
+			ImportID: productImport.Id,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, row := range rows.Rows {
+			fmt.Printf("line %d: %s\n", row.Line, strings.Join(row.Errors, "; "))
+		}
+
+		if *dryRun || productImport.ValidRows == 0 {
+			return
+		}
+
+		importID = productImport.Id
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.ProductImportTimeout)
+	defer cancel()
+
+	started, err := pgStorage.ProductImport().Start(ctx, &models.ProductImportPrimaryKey{Id: importID})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if started == 0 {
+		log.Fatal("import ", importID, " is not validated or failed")
+	}
+
+	productImport, err := pgStorage.ProductImport().Run(ctx, &models.ProductImportPrimaryKey{Id: importID})
+	if err != nil {
+		log.Fatal(err, " (resume with -resume ", importID, ")")
+	}
+
+	printImport(productImport)
+}
+
+func printImport(productImport *models.ProductImport) {
+
+	fmt.Printf("import %s: %s\n", productImport.Id, productImport.Status)
+	fmt.Printf("rows: %d, valid: %d, invalid: %d\n", productImport.TotalRows, productImport.ValidRows, productImport.InvalidRows)
+
+	if productImport.Status == config.ProductImportValidated {
+		fmt.Printf("to create: %d, to update: %d, new categories: %d, new brands: %d\n",
+			productImport.ToCreate, productImport.ToUpdate, productImport.NewCategories, productImport.NewBrands)
+		return
+	}
+
+	fmt.Printf("imported: %d, created: %d, updated: %d, categories created: %d, brands created: %d\n",
+		productImport.ImportedRows, productImport.Created, productImport.Updated, productImport.CategoriesCreated, productImport.BrandsCreated)
+}
@@ -1,12 +1,16 @@
 package main
 
 import (
+	"context"
 	"log"
+	"time"
 
 	"github.com/gin-gonic/gin"
 
 	"market_system/api"
 	"market_system/config"
+	"market_system/models"
+	"market_system/storage"
 	"market_system/storage/postgres"
 	"market_system/storage/redis"
 )
@@ -25,6 +29,31 @@ func main() {
 		panic(err)
 	}
 
+	failed, err := pgStorage.ExportJob().FailUnfinished(context.Background())
+	if err != nil {
+		log.Println("export jobs:", err)
+	} else if failed > 0 {
+		log.Println("export jobs interrupted by restart:", failed)
+	}
+
+	failed, err = pgStorage.ProductImport().FailUnfinished(context.Background())
+	if err != nil {
+		log.Println("product imports:", err)
+	} else if failed > 0 {
+		log.Println("product imports interrupted by restart:", failed)
+	}
+
+	indexed, err := pgStorage.Product().IndexSearch(context.Background())
+	if err != nil {
+		log.Println("product search:", err)
+	} else if indexed > 0 {
+		log.Println("product search keys written:", indexed)
+	}
+
+	if cfg.ProductClassInterval > 0 {
+		go classifyProducts(&cfg, pgStorage)
+	}
+
 	// gin.SetMode(gin.ReleaseMode)
 
 	r := gin.New()
@@ -38,3 +67,30 @@ func main() {
 		panic("Listent and service panic:" + err.Error())
 	}
 }
+
+// classifyProducts runs the ABC/XYZ product classification at start and
+// then every cfg.ProductClassInterval hours.
+func classifyProducts(cfg *config.Config, strg storage.StorageI) {
+
+	var ticker = time.NewTicker(time.Duration(cfg.ProductClassInterval) * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		classifyProductsOnce(cfg, strg)
+		<-ticker.C
+	}
+}
+
+func classifyProductsOnce(cfg *config.Config, strg storage.StorageI) {
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.ProductClassTimeout)
+	defer cancel()
+
+	run, err := strg.ProductClass().Run(ctx, &models.RunProductClass{HistoryWeeks: cfg.ProductClassHistoryWeeks})
+	if err != nil {
+		log.Println("product class run:", err)
+		return
+	}
+
+	log.Println("product class run:", run.Id, run.Products, "products")
+}
@@ -31,6 +31,23 @@ type Config struct {
 	ServiceHTTPPort string
 
 	SecretKey string
+
+	// WriteOffApprovalThreshold is the cost above which a write-off has to be
+	// approved before it leaves stock.
+	WriteOffApprovalThreshold float64
+
+	// ProductClassInterval is how often, in hours, products are classified
+	// into ABC/XYZ in the background; 0 turns the job off.
+	// ProductClassHistoryWeeks is the number of weeks of sales a run reads.
+	ProductClassInterval     int
+	ProductClassHistoryWeeks int64
+
+	// ExportDir keeps the files of background exports, ExportMaxRows caps the
+	// rows of an export and ExportPDFFont is the TrueType font PDF exports are
+	// written in, needed for text beyond Latin-1.
+	ExportDir     string
+	ExportMaxRows int
+	ExportPDFFont string
 }
 
 func Load() Config {
@@ -58,6 +75,19 @@ func Load() Config {
 
 	cfg.SecretKey = cast.ToString(getValueOrDefault("SECRET_KEY", "q6T6LlwdRk"))
 
+	cfg.WriteOffApprovalThreshold = cast.ToFloat64(getValueOrDefault("WRITE_OFF_APPROVAL_THRESHOLD", 1000000))
+
+	cfg.ProductClassInterval = cast.ToInt(getValueOrDefault("PRODUCT_CLASS_INTERVAL", 24))
+	cfg.ProductClassHistoryWeeks = cast.ToInt64(getValueOrDefault("PRODUCT_CLASS_HISTORY_WEEKS", 13))
+	if cfg.ProductClassHistoryWeeks < MinProductClassHistoryWeeks || cfg.ProductClassHistoryWeeks > MaxProductClassHistoryWeeks {
+		log.Fatalf("PRODUCT_CLASS_HISTORY_WEEKS must be between %d and %d, got %d",
+			MinProductClassHistoryWeeks, MaxProductClassHistoryWeeks, cfg.ProductClassHistoryWeeks)
+	}
+
+	cfg.ExportDir = cast.ToString(getValueOrDefault("EXPORT_DIR", "./exports"))
+	cfg.ExportMaxRows = cast.ToInt(getValueOrDefault("EXPORT_MAX_ROWS", 1000000))
+	cfg.ExportPDFFont = cast.ToString(getValueOrDefault("EXPORT_PDF_FONT", ""))
+
 	return cfg
 }
 
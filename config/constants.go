@@ -6,6 +6,208 @@ const (
 	CtxTimeout = time.Second * 2
 
 	ExpiredTime = time.Hour * 24
+
+	// ProductClassTimeout bounds a classification run, which reads the sales
+	// of every branch.
+	ProductClassTimeout = time.Minute
+
+	// ExportTimeout bounds the storage calls of a background export and
+	// ExportWorkers is how many of them run at once.
+	ExportTimeout = time.Minute * 10
+	ExportWorkers = 2
+
+	// ProductImportTimeout bounds validating or running a product import and
+	// ProductImportBatchSize is how many rows a run writes per transaction.
+	ProductImportTimeout   = time.Minute * 30
+	ProductImportBatchSize = 1000
 )
 
 var ClientTypes = []string{"SUPER-ADMIN", "CASSIER", "BRANCH"}
+
+// price_change.status
+const (
+	PriceChangeDraft    = "draft"
+	PriceChangeApproved = "approved"
+	PriceChangeCanceled = "canceled"
+)
+
+// sale.status. A canceled sale was voided before payment; a returned one was
+// paid and then taken back.
+const (
+	SaleStatusFinished = "finished"
+	SaleStatusCanceled = "canceled"
+	SaleStatusReturned = "returned"
+)
+
+// sale_products.discount_type set by the promotions engine. Lines with any
+// other non-empty discount_type carry a manual discount and are left alone.
+const DiscountTypePromotion = "promotion"
+
+// loyalty_transaction.type
+const (
+	LoyaltyEarn   = "earn"
+	LoyaltyRedeem = "redeem"
+	LoyaltyAdjust = "adjust"
+)
+
+// gift_card.type
+const (
+	GiftCardTypeGiftCard    = "gift_card"
+	GiftCardTypeStoreCredit = "store_credit"
+)
+
+// gift_card.status. A gift card sold in a sale stays pending until the sale
+// is finished.
+const (
+	GiftCardPending  = "pending"
+	GiftCardActive   = "active"
+	GiftCardCanceled = "canceled"
+)
+
+// gift_card_transaction.type
+const (
+	GiftCardIssue  = "issue"
+	GiftCardRedeem = "redeem"
+)
+
+// purchase_order.status
+const (
+	PurchaseOrderDraft             = "draft"
+	PurchaseOrderSent              = "sent"
+	PurchaseOrderPartiallyReceived = "partially_received"
+	PurchaseOrderReceived          = "received"
+	PurchaseOrderClosed            = "closed"
+)
+
+// income.status
+const (
+	IncomeStatusDraft    = "draft"
+	IncomeStatusFinished = "finished"
+)
+
+// supplier_transaction.type
+const (
+	SupplierInvoice = "invoice"
+	SupplierPayment = "payment"
+	SupplierReturn  = "return"
+)
+
+// supplier_transaction.method
+const (
+	SupplierPaymentCash = "cash"
+	SupplierPaymentBank = "bank"
+)
+
+// supplier_return.status
+const (
+	SupplierReturnDraft    = "draft"
+	SupplierReturnPosted   = "posted"
+	SupplierReturnCanceled = "canceled"
+)
+
+// stock_movement.type
+const (
+	StockMovementIncome         = "income"
+	StockMovementSale           = "sale"
+	StockMovementSaleReturn     = "sale_return"
+	StockMovementSupplierReturn = "supplier_return"
+	StockMovementTransferOut    = "transfer_out"
+	StockMovementTransferIn     = "transfer_in"
+	StockMovementCountSurplus   = "count_surplus"
+	StockMovementCountShortage  = "count_shortage"
+	StockMovementWriteOff       = "write_off"
+	StockMovementAdjustment     = "adjustment"
+)
+
+// transfer.status
+const (
+	TransferDraft     = "draft"
+	TransferInTransit = "in_transit"
+	TransferReceived  = "received"
+	TransferCanceled  = "canceled"
+)
+
+// inventory_count.status
+const (
+	InventoryCountOpen     = "open"
+	InventoryCountPosted   = "posted"
+	InventoryCountCanceled = "canceled"
+)
+
+// write_off.reason
+var WriteOffReasons = []string{"expired", "damaged", "theft", "internal_use"}
+
+// write_off.status
+const (
+	WriteOffDraft           = "draft"
+	WriteOffPendingApproval = "pending_approval"
+	WriteOffPosted          = "posted"
+	WriteOffRejected        = "rejected"
+	WriteOffCanceled        = "canceled"
+)
+
+// sales report
+var (
+	ReportIntervals    = []string{"day", "week", "month"}
+	SalesReportGroups  = []string{"period", "branch", "sale_point", "cashier", "category", "brand"}
+	TopProductsOrderBy = []string{"revenue", "units", "margin"}
+)
+
+// AgingBuckets are the default bounds, in days since the last receipt, of
+// the stock aging report.
+var AgingBuckets = []int64{30, 60, 90, 180}
+
+// bounds of the weeks of sales a product classification run reads
+const (
+	MinProductClassHistoryWeeks = 2
+	MaxProductClassHistoryWeeks = 104
+)
+
+// product_class.abc and product_class.xyz
+var (
+	ABCClasses = []string{"A", "B", "C"}
+	XYZClasses = []string{"X", "Y", "Z"}
+)
+
+// export_job.status
+const (
+	ExportJobPending = "pending"
+	ExportJobRunning = "running"
+	ExportJobDone    = "done"
+	ExportJobFailed  = "failed"
+)
+
+// product_import.status
+const (
+	ProductImportValidated = "validated"
+	ProductImportRunning   = "running"
+	ProductImportDone      = "done"
+	ProductImportFailed    = "failed"
+)
+
+// product_import_row.status
+const (
+	ProductImportRowValid    = "valid"
+	ProductImportRowInvalid  = "invalid"
+	ProductImportRowImported = "imported"
+)
+
+// supplier_invoice_line.matched_by
+const (
+	SupplierInvoiceMatchBarcode = "barcode"
+	SupplierInvoiceMatchMapping = "mapping"
+	SupplierInvoiceMatchName    = "name"
+	SupplierInvoiceMatchManual  = "manual"
+)
+
+// supplier_invoice_line.status
+const (
+	SupplierInvoiceLineMatched   = "matched"
+	SupplierInvoiceLineUnmatched = "unmatched"
+	SupplierInvoiceLineInvalid   = "invalid"
+	SupplierInvoiceLineSkipped   = "skipped"
+)
+
+// SupplierInvoicePriceTolerance is how far, as a fraction, an invoice price
+// may be from the last income price of the product before it is flagged.
+const SupplierInvoicePriceTolerance = 0.1
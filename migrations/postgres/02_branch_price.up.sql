@@ -0,0 +1,37 @@
+-- price_change (document that moves branch prices on a schedule)
+CREATE TABLE price_change (
+    id UUID PRIMARY KEY,
+    branch_id UUID NOT NULL REFERENCES branch(id),
+    effective_from TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'approved', 'canceled')),
+    comment VARCHAR(255),
+    approved_by UUID REFERENCES "user"(id),
+    approved_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+-- price_change_product
+CREATE TABLE price_change_product (
+    id UUID PRIMARY KEY,
+    price_change_id UUID NOT NULL REFERENCES price_change(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES product(id),
+    old_price DECIMAL(10, 2),
+    new_price DECIMAL(10, 2) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+-- branch_price (price list: the row with the latest effective_from <= now wins)
+CREATE TABLE branch_price (
+    id UUID PRIMARY KEY,
+    branch_id UUID NOT NULL REFERENCES branch(id),
+    product_id UUID NOT NULL REFERENCES product(id),
+    price DECIMAL(10, 2) NOT NULL,
+    effective_from TIMESTAMP NOT NULL,
+    price_change_id UUID REFERENCES price_change(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX branch_price_lookup_idx ON branch_price (branch_id, product_id, effective_from DESC);
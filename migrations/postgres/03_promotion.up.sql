@@ -0,0 +1,53 @@
+-- promotion
+CREATE TABLE promotion (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('percent', 'buy_x_get_y', 'bundle')),
+    percent DECIMAL(5, 2) DEFAULT 0,
+    buy_quantity INT DEFAULT 0,
+    get_quantity INT DEFAULT 0,
+    bundle_price DECIMAL(10, 2) DEFAULT 0,
+    category_id UUID REFERENCES category(id),
+    brand_id UUID REFERENCES brand(id),
+    min_basket_amount DECIMAL(10, 2) DEFAULT 0,
+    start_date TIMESTAMP,
+    end_date TIMESTAMP,
+    happy_hour_from VARCHAR(5),
+    happy_hour_to VARCHAR(5),
+    priority INT NOT NULL DEFAULT 0,
+    stackable BOOLEAN NOT NULL DEFAULT false,
+    is_active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+-- promotion_branch (no rows means the promotion runs in every branch)
+CREATE TABLE promotion_branch (
+    promotion_id UUID NOT NULL REFERENCES promotion(id) ON DELETE CASCADE,
+    branch_id UUID NOT NULL REFERENCES branch(id),
+    PRIMARY KEY (promotion_id, branch_id)
+);
+
+-- promotion_item (barcodes a promotion is limited to, bundle contents)
+CREATE TABLE promotion_item (
+    promotion_id UUID NOT NULL REFERENCES promotion(id) ON DELETE CASCADE,
+    barcode VARCHAR(50) NOT NULL,
+    quantity INT NOT NULL DEFAULT 1,
+    PRIMARY KEY (promotion_id, barcode)
+);
+
+-- sale_product_promotion (which promotion produced which discount)
+CREATE TABLE sale_product_promotion (
+    id UUID PRIMARY KEY,
+    sale_id UUID NOT NULL REFERENCES sale(id) ON DELETE CASCADE,
+    sale_product_id UUID NOT NULL REFERENCES sale_products(id) ON DELETE CASCADE,
+    promotion_id UUID NOT NULL REFERENCES promotion(id),
+    discount DECIMAL(12, 2) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- a promotion discount is stored on the line as an amount, not a percent
+ALTER TABLE sale_products ALTER COLUMN discount TYPE DECIMAL(12, 2);
+
+CREATE INDEX sale_product_promotion_sale_idx ON sale_product_promotion (sale_id);
+CREATE INDEX sale_product_promotion_promotion_idx ON sale_product_promotion (promotion_id);
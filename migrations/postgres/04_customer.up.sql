@@ -0,0 +1,57 @@
+-- loyalty_tier (a customer sits in the highest tier whose min_spent they reached)
+CREATE TABLE loyalty_tier (
+    id UUID PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    min_spent DECIMAL(14, 2) NOT NULL DEFAULT 0,
+    earn_multiplier DECIMAL(5, 2) NOT NULL DEFAULT 1,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+-- customer
+CREATE TABLE customer (
+    id UUID PRIMARY KEY,
+    phone VARCHAR(20) NOT NULL UNIQUE,
+    first_name VARCHAR(100),
+    last_name VARCHAR(100),
+    card_barcode VARCHAR(50) UNIQUE,
+    tier_id UUID REFERENCES loyalty_tier(id) ON DELETE SET NULL,
+    points_balance DECIMAL(14, 2) NOT NULL DEFAULT 0,
+    total_spent DECIMAL(14, 2) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+-- loyalty_earn_rate (percent of the line total earned as points; the row
+-- without category_id is the default rate)
+CREATE TABLE loyalty_earn_rate (
+    id UUID PRIMARY KEY,
+    category_id UUID REFERENCES category(id) ON DELETE CASCADE,
+    percent DECIMAL(5, 2) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX loyalty_earn_rate_category_idx ON loyalty_earn_rate (category_id);
+CREATE UNIQUE INDEX loyalty_earn_rate_default_idx ON loyalty_earn_rate ((category_id IS NULL)) WHERE category_id IS NULL;
+
+-- loyalty_transaction (points balance history)
+CREATE TABLE loyalty_transaction (
+    id UUID PRIMARY KEY,
+    customer_id UUID NOT NULL REFERENCES customer(id) ON DELETE CASCADE,
+    sale_id UUID REFERENCES sale(id) ON DELETE SET NULL,
+    type VARCHAR(10) NOT NULL CHECK (type IN ('earn', 'redeem', 'adjust')),
+    points DECIMAL(14, 2) NOT NULL,
+    balance_after DECIMAL(14, 2) NOT NULL,
+    comment TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX loyalty_transaction_customer_idx ON loyalty_transaction (customer_id, created_at);
+
+ALTER TABLE sale ADD COLUMN customer_id UUID REFERENCES customer(id);
+CREATE INDEX sale_customer_idx ON sale (customer_id);
+
+-- points redeemed as a tender (1 point = 1 sum)
+ALTER TABLE payment ADD COLUMN points DECIMAL(10, 2) DEFAULT 0;
+ALTER TABLE transaction ADD COLUMN points DECIMAL(10, 2) DEFAULT 0;
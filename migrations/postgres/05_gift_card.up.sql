@@ -0,0 +1,40 @@
+-- gift_card (gift certificates sold at the till and store credit given on returns)
+CREATE TABLE gift_card (
+    id UUID PRIMARY KEY,
+    code VARCHAR(13) NOT NULL UNIQUE,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('gift_card', 'store_credit')),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('pending', 'active', 'canceled')),
+    initial_amount DECIMAL(14, 2) NOT NULL CHECK (initial_amount > 0),
+    balance DECIMAL(14, 2) NOT NULL CHECK (balance >= 0),
+    customer_id UUID REFERENCES customer(id) ON DELETE SET NULL,
+    sale_id UUID REFERENCES sale(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP,
+    comment TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX gift_card_sale_idx ON gift_card (sale_id);
+
+-- gift_card_transaction (issue and redemption history)
+CREATE TABLE gift_card_transaction (
+    id UUID PRIMARY KEY,
+    gift_card_id UUID NOT NULL REFERENCES gift_card(id) ON DELETE CASCADE,
+    sale_id UUID REFERENCES sale(id) ON DELETE SET NULL,
+    type VARCHAR(10) NOT NULL CHECK (type IN ('issue', 'redeem')),
+    amount DECIMAL(14, 2) NOT NULL,
+    balance_after DECIMAL(14, 2) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX gift_card_transaction_card_idx ON gift_card_transaction (gift_card_id, created_at);
+
+-- a gift card sold in a sale is a line without a product
+ALTER TABLE sale_products ALTER COLUMN category_id DROP NOT NULL;
+ALTER TABLE sale_products ADD COLUMN gift_card_id UUID REFERENCES gift_card(id);
+
+-- gift card redeemed as a tender, and gift cards sold during the shift
+ALTER TABLE payment ADD COLUMN gift_card DECIMAL(10, 2) DEFAULT 0;
+ALTER TABLE payment ADD COLUMN gift_card_code VARCHAR(13);
+ALTER TABLE transaction ADD COLUMN gift_card DECIMAL(10, 2) DEFAULT 0;
+ALTER TABLE transaction ADD COLUMN gift_card_sold DECIMAL(10, 2) DEFAULT 0;
@@ -0,0 +1,35 @@
+-- purchase_order (order to a supplier for one branch)
+CREATE TABLE purchase_order (
+    id UUID PRIMARY KEY,
+    supplier_id UUID NOT NULL REFERENCES supplier(id),
+    branch_id UUID NOT NULL REFERENCES branch(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'sent', 'partially_received', 'received', 'closed')),
+    expected_date DATE,
+    comment VARCHAR(255),
+    created_by UUID REFERENCES "user"(id),
+    sent_at TIMESTAMP,
+    received_at TIMESTAMP,
+    closed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX purchase_order_supplier_idx ON purchase_order (supplier_id, created_at);
+
+-- purchase_order_product (expected lines; received_quantity grows with every receipt)
+CREATE TABLE purchase_order_product (
+    id UUID PRIMARY KEY,
+    purchase_order_id UUID NOT NULL REFERENCES purchase_order(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES product(id),
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    price DECIMAL(10, 2) NOT NULL,
+    received_quantity BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+-- receipts against an order
+ALTER TABLE income ADD COLUMN purchase_order_id UUID REFERENCES purchase_order(id);
+ALTER TABLE income_product ADD COLUMN purchase_order_product_id UUID REFERENCES purchase_order_product(id);
+
+CREATE INDEX income_purchase_order_idx ON income (purchase_order_id);
@@ -0,0 +1,19 @@
+-- supplier_transaction (accounts payable ledger; amount is always positive,
+-- invoices raise what we owe, payments and returns reduce it)
+CREATE TABLE supplier_transaction (
+    id UUID PRIMARY KEY,
+    supplier_id UUID NOT NULL REFERENCES supplier(id),
+    branch_id UUID REFERENCES branch(id),
+    type VARCHAR(20) NOT NULL CHECK (type IN ('invoice', 'payment', 'return')),
+    amount DECIMAL(12, 2) NOT NULL CHECK (amount > 0),
+    method VARCHAR(10) CHECK (method IN ('cash', 'bank')),
+    income_id UUID REFERENCES income(id),
+    date_time TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    comment VARCHAR(255),
+    created_by UUID REFERENCES "user"(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX supplier_transaction_supplier_idx ON supplier_transaction (supplier_id, date_time);
+CREATE UNIQUE INDEX supplier_transaction_income_idx ON supplier_transaction (income_id) WHERE type = 'invoice';
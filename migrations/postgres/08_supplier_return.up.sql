@@ -0,0 +1,32 @@
+-- supplier_return (goods sent back to a supplier from a branch)
+CREATE TABLE supplier_return (
+    id UUID PRIMARY KEY,
+    supplier_id UUID NOT NULL REFERENCES supplier(id),
+    branch_id UUID NOT NULL REFERENCES branch(id),
+    income_id UUID REFERENCES income(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'posted', 'canceled')),
+    comment VARCHAR(255),
+    created_by UUID REFERENCES "user"(id),
+    posted_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX supplier_return_supplier_idx ON supplier_return (supplier_id, created_at);
+
+-- supplier_return_product
+CREATE TABLE supplier_return_product (
+    id UUID PRIMARY KEY,
+    supplier_return_id UUID NOT NULL REFERENCES supplier_return(id) ON DELETE CASCADE,
+    category_id UUID REFERENCES category(id),
+    product_name VARCHAR(255),
+    barcode VARCHAR(50) NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    cost DECIMAL(10, 2) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+-- a posted return credits the supplier once
+ALTER TABLE supplier_transaction ADD COLUMN supplier_return_id UUID REFERENCES supplier_return(id);
+CREATE UNIQUE INDEX supplier_transaction_return_idx ON supplier_transaction (supplier_return_id) WHERE type = 'return';
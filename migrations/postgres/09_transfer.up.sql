@@ -0,0 +1,50 @@
+-- stock_movement (every change of branch stock made by a document;
+-- quantity is positive into the branch and negative out of it)
+CREATE TABLE stock_movement (
+    id UUID PRIMARY KEY,
+    branch_id UUID NOT NULL REFERENCES branch(id),
+    barcode VARCHAR(50) NOT NULL,
+    product_name VARCHAR(255),
+    type VARCHAR(30) NOT NULL,
+    document_id UUID,
+    quantity BIGINT NOT NULL,
+    cost DECIMAL(10, 2),
+    balance_after BIGINT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX stock_movement_branch_idx ON stock_movement (branch_id, barcode, created_at);
+CREATE INDEX stock_movement_document_idx ON stock_movement (document_id);
+
+-- transfer (goods moved between branches)
+CREATE TABLE transfer (
+    id UUID PRIMARY KEY,
+    from_branch_id UUID NOT NULL REFERENCES branch(id),
+    to_branch_id UUID NOT NULL REFERENCES branch(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'in_transit', 'received', 'canceled')),
+    comment VARCHAR(255),
+    created_by UUID REFERENCES "user"(id),
+    dispatched_at TIMESTAMP,
+    received_by UUID REFERENCES "user"(id),
+    received_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    CHECK (from_branch_id <> to_branch_id)
+);
+
+CREATE INDEX transfer_status_idx ON transfer (status);
+
+-- transfer_product (shortage = quantity - received_quantity once received)
+CREATE TABLE transfer_product (
+    id UUID PRIMARY KEY,
+    transfer_id UUID NOT NULL REFERENCES transfer(id) ON DELETE CASCADE,
+    category_id UUID REFERENCES category(id),
+    product_name VARCHAR(255),
+    barcode VARCHAR(50) NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    cost DECIMAL(10, 2),
+    received_quantity BIGINT,
+    discrepancy_comment VARCHAR(255),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
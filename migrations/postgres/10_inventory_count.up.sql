@@ -0,0 +1,48 @@
+-- inventory_count (physical count of a branch, or of one category in it;
+-- expected quantities are snapshotted at snapshot_at)
+CREATE TABLE inventory_count (
+    id UUID PRIMARY KEY,
+    branch_id UUID NOT NULL REFERENCES branch(id),
+    category_id UUID REFERENCES category(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'posted', 'canceled')),
+    comment VARCHAR(255),
+    created_by UUID REFERENCES "user"(id),
+    snapshot_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    posted_by UUID REFERENCES "user"(id),
+    posted_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+-- one open count per branch, so two counts never post over each other
+CREATE UNIQUE INDEX inventory_count_open_idx ON inventory_count (branch_id) WHERE status = 'open';
+
+-- inventory_count_product (moved, counted and variance are frozen on posting)
+CREATE TABLE inventory_count_product (
+    id UUID PRIMARY KEY,
+    inventory_count_id UUID NOT NULL REFERENCES inventory_count(id) ON DELETE CASCADE,
+    category_id UUID REFERENCES category(id),
+    product_name VARCHAR(255),
+    barcode VARCHAR(50) NOT NULL,
+    cost DECIMAL(10, 2),
+    expected_quantity BIGINT NOT NULL DEFAULT 0,
+    moved_quantity BIGINT,
+    counted_quantity BIGINT,
+    variance BIGINT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    UNIQUE (inventory_count_id, barcode)
+);
+
+-- inventory_count_scan (what each counter scanned; summed per barcode,
+-- a negative quantity corrects a miscount)
+CREATE TABLE inventory_count_scan (
+    id UUID PRIMARY KEY,
+    inventory_count_id UUID NOT NULL REFERENCES inventory_count(id) ON DELETE CASCADE,
+    barcode VARCHAR(50) NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity <> 0),
+    counted_by UUID REFERENCES "user"(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX inventory_count_scan_barcode_idx ON inventory_count_scan (inventory_count_id, barcode);
@@ -0,0 +1,30 @@
+-- write_off (goods taken out of a branch as shrinkage; documents worth more
+-- than the approval threshold wait in pending_approval until approved)
+CREATE TABLE write_off (
+    id UUID PRIMARY KEY,
+    branch_id UUID NOT NULL REFERENCES branch(id),
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('expired', 'damaged', 'theft', 'internal_use')),
+    status VARCHAR(20) NOT NULL DEFAULT 'draft' CHECK (status IN ('draft', 'pending_approval', 'posted', 'rejected', 'canceled')),
+    comment VARCHAR(255),
+    created_by UUID REFERENCES "user"(id),
+    reviewed_by UUID REFERENCES "user"(id),
+    reviewed_at TIMESTAMP,
+    posted_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX write_off_posted_idx ON write_off (posted_at) WHERE status = 'posted';
+
+-- write_off_product (cost is the branch income price when the line was added)
+CREATE TABLE write_off_product (
+    id UUID PRIMARY KEY,
+    write_off_id UUID NOT NULL REFERENCES write_off(id) ON DELETE CASCADE,
+    category_id UUID REFERENCES category(id),
+    product_name VARCHAR(255),
+    barcode VARCHAR(50) NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    cost DECIMAL(10, 2),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
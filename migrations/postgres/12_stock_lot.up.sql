@@ -0,0 +1,31 @@
+-- lot number and expiry date captured on receiving
+ALTER TABLE income_product ADD COLUMN lot_number VARCHAR(50);
+ALTER TABLE income_product ADD COLUMN expiry_date DATE;
+
+-- stock_lot (branch stock per lot; remainder keeps the branch total, and
+-- whatever of it is not covered by lots was received without one)
+CREATE TABLE stock_lot (
+    id UUID PRIMARY KEY,
+    branch_id UUID NOT NULL REFERENCES branch(id),
+    barcode VARCHAR(50) NOT NULL,
+    product_name VARCHAR(255),
+    lot_number VARCHAR(50) NOT NULL DEFAULT '',
+    expiry_date DATE,
+    quantity BIGINT NOT NULL DEFAULT 0,
+    cost DECIMAL(10, 2),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX stock_lot_key_idx ON stock_lot (branch_id, barcode, lot_number, COALESCE(expiry_date, 'infinity'::date));
+CREATE INDEX stock_lot_expiry_idx ON stock_lot (branch_id, expiry_date) WHERE quantity > 0;
+
+-- transfer_product_lot (lots a transfer line took from the source branch,
+-- put into the destination when received)
+CREATE TABLE transfer_product_lot (
+    id UUID PRIMARY KEY,
+    transfer_product_id UUID NOT NULL REFERENCES transfer_product(id) ON DELETE CASCADE,
+    lot_number VARCHAR(50) NOT NULL DEFAULT '',
+    expiry_date DATE,
+    quantity BIGINT NOT NULL CHECK (quantity > 0)
+);
@@ -0,0 +1,15 @@
+-- stock_level (per-branch reorder levels of a product)
+CREATE TABLE stock_level (
+    id UUID PRIMARY KEY,
+    branch_id UUID NOT NULL REFERENCES branch(id),
+    product_id UUID NOT NULL REFERENCES product(id),
+    min_quantity BIGINT NOT NULL DEFAULT 0 CHECK (min_quantity >= 0),
+    max_quantity BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    UNIQUE (branch_id, product_id),
+    CHECK (max_quantity >= min_quantity)
+);
+
+CREATE INDEX sale_products_barcode_idx ON sale_products (barcode);
+CREATE INDEX income_product_barcode_idx ON income_product (barcode);
@@ -0,0 +1,5 @@
+-- indexes for the sales reports, which aggregate finished sales by date and
+-- price their lines at the cost recorded by the sale's stock movement
+CREATE INDEX sale_status_created_idx ON sale (status, created_at);
+CREATE INDEX sale_products_sale_idx ON sale_products (sale_id);
+CREATE INDEX remainder_branch_barcode_idx ON remainder (branch_id, barcode);
@@ -0,0 +1,7 @@
+-- cash in the drawer: the float put in at open and what was counted at close
+ALTER TABLE shift ADD COLUMN opening_cash DECIMAL(12, 2) DEFAULT 0;
+ALTER TABLE shift ADD COLUMN counted_cash DECIMAL(12, 2);
+
+CREATE INDEX shift_open_idx ON shift (open_shift);
+CREATE INDEX sale_shift_idx ON sale (shift_id);
+CREATE INDEX transaction_shift_idx ON transaction (shift_id);
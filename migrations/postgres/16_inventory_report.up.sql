@@ -0,0 +1,3 @@
+-- indexes for the inventory valuation and aging reports
+CREATE INDEX stock_movement_type_idx ON stock_movement (branch_id, barcode, type, created_at);
+CREATE INDEX income_branch_status_idx ON income (branch_id, status);
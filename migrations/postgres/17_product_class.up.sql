@@ -0,0 +1,34 @@
+-- product_class_run (one ABC/XYZ classification over the sales of
+-- [from_date, to_date))
+CREATE TABLE product_class_run (
+    id UUID PRIMARY KEY,
+    run_date DATE NOT NULL DEFAULT CURRENT_DATE,
+    from_date DATE NOT NULL,
+    to_date DATE NOT NULL,
+    history_weeks INT NOT NULL CHECK (history_weeks > 0),
+    products INT NOT NULL DEFAULT 0,
+    created_by UUID REFERENCES "user"(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX product_class_run_created_idx ON product_class_run (created_at DESC);
+
+-- product_class (class of a barcode in a branch; shares are fractions of the
+-- branch revenue, cv the coefficient of variation of weekly units sold)
+CREATE TABLE product_class (
+    run_id UUID NOT NULL REFERENCES product_class_run(id) ON DELETE CASCADE,
+    branch_id UUID NOT NULL REFERENCES branch(id),
+    barcode VARCHAR(50) NOT NULL,
+    product_name VARCHAR(255),
+    revenue DECIMAL(14, 2) NOT NULL DEFAULT 0,
+    units BIGINT NOT NULL DEFAULT 0,
+    share DECIMAL(7, 4) NOT NULL DEFAULT 0,
+    cumulative_share DECIMAL(7, 4) NOT NULL DEFAULT 0,
+    abc CHAR(1) NOT NULL CHECK (abc IN ('A', 'B', 'C')),
+    weekly_mean DECIMAL(12, 4) NOT NULL DEFAULT 0,
+    cv DECIMAL(10, 4) NOT NULL DEFAULT 0,
+    xyz CHAR(1) NOT NULL CHECK (xyz IN ('X', 'Y', 'Z')),
+    PRIMARY KEY (run_id, branch_id, barcode)
+);
+
+CREATE INDEX product_class_barcode_idx ON product_class (run_id, barcode);
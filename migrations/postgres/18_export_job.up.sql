@@ -0,0 +1,17 @@
+-- export_job (a list or report exported in the background; path is the
+-- request that produced it and the file is kept under EXPORT_DIR)
+CREATE TABLE export_job (
+    id UUID PRIMARY KEY,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'done', 'failed')),
+    format VARCHAR(10) NOT NULL CHECK (format IN ('csv', 'xlsx', 'pdf')),
+    lang VARCHAR(5) NOT NULL,
+    path TEXT NOT NULL,
+    file_name VARCHAR(255) NOT NULL,
+    rows INT,
+    error TEXT,
+    created_by UUID REFERENCES "user"(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX export_job_created_by_idx ON export_job (created_by, created_at DESC);
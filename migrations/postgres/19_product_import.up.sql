@@ -0,0 +1,75 @@
+-- product unit; a barcode names one product so imports can upsert by it
+-- (duplicate barcodes have to be merged before this migration, which stops
+-- naming a few of them when it finds any)
+ALTER TABLE product ADD COLUMN unit VARCHAR(10) NOT NULL DEFAULT 'pcs';
+
+DO $$
+DECLARE
+    duplicates INT;
+    examples TEXT;
+BEGIN
+    SELECT COUNT(*), string_agg(barcode, ', ' ORDER BY barcode) FILTER (WHERE n <= 10)
+        INTO duplicates, examples
+    FROM (
+        SELECT barcode, ROW_NUMBER() OVER (ORDER BY barcode) AS n
+        FROM product
+        WHERE barcode IS NOT NULL AND barcode <> ''
+        GROUP BY barcode
+        HAVING COUNT(*) > 1
+    ) AS d;
+
+    IF duplicates > 0 THEN
+        RAISE EXCEPTION '% barcodes are used by more than one product (%); merge them before running this migration', duplicates, examples;
+    END IF;
+END
+$$;
+
+CREATE UNIQUE INDEX product_barcode_key ON product (barcode) WHERE barcode IS NOT NULL AND barcode <> '';
+
+-- product_import (a product file; validated until it is run, the to_* and
+-- new_* counts preview what running it does)
+CREATE TABLE product_import (
+    id UUID PRIMARY KEY,
+    status VARCHAR(20) NOT NULL DEFAULT 'validated' CHECK (status IN ('validated', 'running', 'done', 'failed')),
+    file_name VARCHAR(255) NOT NULL,
+    format VARCHAR(10) NOT NULL CHECK (format IN ('csv', 'xlsx')),
+    total_rows INT NOT NULL DEFAULT 0,
+    valid_rows INT NOT NULL DEFAULT 0,
+    invalid_rows INT NOT NULL DEFAULT 0,
+    to_create INT NOT NULL DEFAULT 0,
+    to_update INT NOT NULL DEFAULT 0,
+    new_categories INT NOT NULL DEFAULT 0,
+    new_brands INT NOT NULL DEFAULT 0,
+    imported_rows INT NOT NULL DEFAULT 0,
+    created INT NOT NULL DEFAULT 0,
+    updated INT NOT NULL DEFAULT 0,
+    categories_created INT NOT NULL DEFAULT 0,
+    brands_created INT NOT NULL DEFAULT 0,
+    error TEXT,
+    created_by UUID REFERENCES "user"(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP,
+    finished_at TIMESTAMP
+);
+
+CREATE INDEX product_import_created_idx ON product_import (created_at DESC);
+
+-- product_import_row (a row of the file as read, invalid ones included;
+-- category_path holds the titles from the root, product_id is set once the
+-- row is imported)
+CREATE TABLE product_import_row (
+    import_id UUID NOT NULL REFERENCES product_import(id) ON DELETE CASCADE,
+    line INT NOT NULL,
+    title TEXT,
+    barcode TEXT,
+    category_path TEXT[] NOT NULL DEFAULT '{}',
+    brand TEXT,
+    price DECIMAL(10, 2),
+    unit TEXT,
+    errors TEXT[] NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL CHECK (status IN ('valid', 'invalid', 'imported')),
+    product_id UUID REFERENCES product(id) ON DELETE SET NULL,
+    PRIMARY KEY (import_id, line)
+);
+
+CREATE INDEX product_import_row_status_idx ON product_import_row (import_id, status, line);
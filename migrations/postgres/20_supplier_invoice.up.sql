@@ -0,0 +1,56 @@
+-- supplier_invoice (a supplier invoice file imported into a draft income;
+-- number, invoice_date and seller_* are as the file gives them)
+CREATE TABLE supplier_invoice (
+    id UUID PRIMARY KEY,
+    income_id UUID NOT NULL UNIQUE REFERENCES income(id) ON DELETE CASCADE,
+    supplier_id UUID NOT NULL REFERENCES supplier(id),
+    branch_id UUID NOT NULL REFERENCES branch(id),
+    format VARCHAR(10) NOT NULL CHECK (format IN ('csv', 'xlsx', 'json', 'xml')),
+    file_name VARCHAR(255) NOT NULL,
+    number VARCHAR(100),
+    invoice_date DATE,
+    seller_tin VARCHAR(20),
+    seller_name VARCHAR(255),
+    total_amount DECIMAL(14, 2) NOT NULL DEFAULT 0,
+    created_by UUID REFERENCES "user"(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX supplier_invoice_supplier_idx ON supplier_invoice (supplier_id, created_at DESC);
+
+-- supplier_invoice_line (a line of the invoice; a matched line has the
+-- income_product it was received as, issues are what the clerk should check)
+CREATE TABLE supplier_invoice_line (
+    invoice_id UUID NOT NULL REFERENCES supplier_invoice(id) ON DELETE CASCADE,
+    line INT NOT NULL,
+    name TEXT,
+    barcode VARCHAR(50),
+    catalog_code VARCHAR(50),
+    unit VARCHAR(50),
+    quantity DECIMAL(14, 3) NOT NULL DEFAULT 0,
+    price DECIMAL(14, 2) NOT NULL DEFAULT 0,
+    vat_rate DECIMAL(5, 2) NOT NULL DEFAULT 0,
+    amount DECIMAL(14, 2) NOT NULL DEFAULT 0,
+    supplier_code TEXT NOT NULL,
+    product_id UUID REFERENCES product(id) ON DELETE SET NULL,
+    matched_by VARCHAR(20) CHECK (matched_by IN ('barcode', 'mapping', 'name', 'manual')),
+    status VARCHAR(20) NOT NULL CHECK (status IN ('matched', 'unmatched', 'invalid', 'skipped')),
+    issues TEXT[] NOT NULL DEFAULT '{}',
+    income_product_id UUID REFERENCES income_product(id) ON DELETE SET NULL,
+    PRIMARY KEY (invoice_id, line)
+);
+
+-- supplier_item_map (the product a supplier item is received as; the code is
+-- the supplier barcode, or "name:" and the normalized name without one)
+CREATE TABLE supplier_item_map (
+    supplier_id UUID NOT NULL REFERENCES supplier(id) ON DELETE CASCADE,
+    supplier_code TEXT NOT NULL,
+    product_id UUID NOT NULL REFERENCES product(id) ON DELETE CASCADE,
+    created_by UUID REFERENCES "user"(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP,
+    PRIMARY KEY (supplier_id, supplier_code)
+);
+
+-- invoice lines without a barcode or mapping are matched by product title
+CREATE INDEX product_title_lower_idx ON product (LOWER(title));
@@ -0,0 +1,15 @@
+-- cursor paging of the high-volume lists, newest first by (created_at, id),
+-- on their own and within a branch, shift or sale; the new indexes cover the
+-- single-column ones they replace
+CREATE INDEX sale_created_id_idx ON sale (created_at DESC, id DESC);
+CREATE INDEX sale_branch_created_id_idx ON sale (branch_id, created_at DESC, id DESC);
+CREATE INDEX sale_shift_created_id_idx ON sale (shift_id, created_at DESC, id DESC);
+DROP INDEX IF EXISTS sale_shift_idx;
+
+CREATE INDEX sale_products_created_id_idx ON sale_products (created_at DESC, id DESC);
+CREATE INDEX sale_products_sale_created_id_idx ON sale_products (sale_id, created_at DESC, id DESC);
+DROP INDEX IF EXISTS sale_products_sale_idx;
+
+CREATE INDEX transaction_created_id_idx ON transaction (created_at DESC, id DESC);
+CREATE INDEX transaction_shift_created_id_idx ON transaction (shift_id, created_at DESC, id DESC);
+DROP INDEX IF EXISTS transaction_shift_idx;
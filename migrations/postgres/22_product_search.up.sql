@@ -0,0 +1,14 @@
+-- product search: search_key is the title normalized by package textsearch,
+-- written by the application (NULL until then), and search_vector its words
+-- for prefix lookup as the name is typed; the trigram index serves fuzzy
+-- matching and substring search, the pattern index prefix ranking
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE product ADD COLUMN search_key TEXT;
+ALTER TABLE product ADD COLUMN search_vector TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('simple', COALESCE(search_key, ''))) STORED;
+
+CREATE INDEX product_search_key_trgm_idx ON product USING GIN (search_key gin_trgm_ops);
+CREATE INDEX product_search_key_prefix_idx ON product (search_key text_pattern_ops);
+CREATE INDEX product_search_vector_idx ON product USING GIN (search_vector);
+CREATE INDEX product_search_key_null_idx ON product (id) WHERE search_key IS NULL;
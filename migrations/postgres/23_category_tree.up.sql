@@ -0,0 +1,19 @@
+-- category_subtree returns a category and all of its descendants, for the
+-- filters that take a category to include its subcategories; depth guards
+-- against a cycle written before moves were checked
+CREATE INDEX category_parent_idx ON category (parent_id);
+
+CREATE FUNCTION category_subtree(root UUID) RETURNS TABLE (id UUID)
+LANGUAGE SQL STABLE AS $$
+    WITH RECURSIVE subtree AS (
+        SELECT c.id, 0 AS depth
+        FROM category AS c
+        WHERE c.id = root
+        UNION ALL
+        SELECT c.id, s.depth + 1
+        FROM category AS c
+        JOIN subtree AS s ON c.parent_id = s.id
+        WHERE s.depth < 100
+    )
+    SELECT DISTINCT subtree.id FROM subtree
+$$;
@@ -0,0 +1,12 @@
+-- the brand of a product is product.brand_id; products that have none take
+-- the brand their category was given, which was the only link before
+ALTER TABLE product ADD COLUMN brand_id UUID REFERENCES brand(id);
+
+UPDATE product AS p
+    SET brand_id = c.brand_id
+FROM category AS c
+WHERE c.id = p.category_id AND p.brand_id IS NULL AND c.brand_id IS NOT NULL;
+
+CREATE INDEX product_brand_idx ON product (brand_id);
+CREATE INDEX category_brand_idx ON category (brand_id);
+CREATE INDEX promotion_brand_idx ON promotion (brand_id);
@@ -1,5 +1,7 @@
 package models
 
+import "market_system/pkg/criteria"
+
 type BranchPrimaryKey struct {
 	Id string `json:"id"`
 }
@@ -31,10 +33,11 @@ type UpdateBranch struct {
 }
 
 type GetListBranchRequest struct {
-	Offset int64  `json:"offset"`
-	Limit  int64  `json:"limit"`
-	Search string `json:"search"`
-	Query  string `json:"query"`
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Search  string            `json:"search"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
 }
 
 type GetListBranchResponse struct {
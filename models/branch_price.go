@@ -0,0 +1,76 @@
+package models
+
+import "market_system/pkg/criteria"
+
+type BranchPricePrimaryKey struct {
+	Id string `json:"id"`
+}
+
+type CreateBranchPrice struct {
+	BranchID      string  `json:"branch_id"`
+	ProductID     string  `json:"product_id"`
+	Price         float64 `json:"price"`
+	EffectiveFrom string  `json:"effective_from"`
+}
+
+type BranchPrice struct {
+	Id            string  `json:"id"`
+	BranchID      string  `json:"branch_id"`
+	ProductID     string  `json:"product_id"`
+	Price         float64 `json:"price"`
+	EffectiveFrom string  `json:"effective_from"`
+	EffectiveTo   string  `json:"effective_to,omitempty"`
+	PriceChangeID string  `json:"price_change_id"`
+	CreatedAt     string  `json:"created_at"`
+	UpdatedAt     string  `json:"updated_at"`
+}
+
+type UpdateBranchPrice struct {
+	Id            string  `json:"id"`
+	Price         float64 `json:"price"`
+	EffectiveFrom string  `json:"effective_from"`
+}
+
+type GetListBranchPriceRequest struct {
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
+}
+
+type GetListBranchPriceResponse struct {
+	Count        int            `json:"count"`
+	BranchPrices []*BranchPrice `json:"branch_prices"`
+}
+
+// ResolvePriceRequest identifies the product either by id or by barcode.
+// An empty At means "now".
+type ResolvePriceRequest struct {
+	BranchID  string `json:"branch_id"`
+	ProductID string `json:"product_id"`
+	Barcode   string `json:"barcode"`
+	At        string `json:"at"`
+}
+
+// ResolvedPrice is the retail price in effect for a product at a branch.
+// BranchPriceID is empty when no branch price applies and the global
+// product.price was used.
+type ResolvedPrice struct {
+	BranchID      string  `json:"branch_id"`
+	ProductID     string  `json:"product_id"`
+	Barcode       string  `json:"barcode"`
+	Price         float64 `json:"price"`
+	BranchPriceID string  `json:"branch_price_id"`
+	EffectiveFrom string  `json:"effective_from"`
+}
+
+type BranchPriceHistoryRequest struct {
+	BranchID  string `json:"branch_id"`
+	ProductID string `json:"product_id"`
+	At        string `json:"at"`
+}
+
+type BranchPriceHistoryResponse struct {
+	InEffect *ResolvedPrice `json:"in_effect"`
+	History  []*BranchPrice `json:"history"`
+}
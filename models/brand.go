@@ -1,5 +1,7 @@
 package models
 
+import "market_system/pkg/criteria"
+
 type BrandPrimaryKey struct {
 	Id string `json:"id"`
 }
@@ -15,16 +17,22 @@ type Brand struct {
 	UpdatedAt string `json:"updated_at"`
 }
 
+type DeleteBrand struct {
+	Id         string `json:"id"`
+	ReassignTo string `json:"reassign_to"`
+}
+
 type UpdateBrand struct {
 	Id   string `json:"id"`
 	Name string `json:"name"`
 }
 
 type GetListBrandRequest struct {
-	Offset int64  `json:"offset"`
-	Limit  int64  `json:"limit"`
-	Search string `json:"search"`
-	Query  string `json:"query"`
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Search  string            `json:"search"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
 }
 
 type GetListBrandResponse struct {
@@ -1,11 +1,14 @@
 package models
 
+import "market_system/pkg/criteria"
+
 type CategoryPrimaryKey struct {
 	Id string `json:"id"`
 }
 
 type CreateCategory struct {
 	Title    string `json:"title"`
+	BrandID  string `json:"brand_id"`
 	ParentID string `json:"parent_id"`
 }
 
@@ -25,13 +28,46 @@ type UpdateCategory struct {
 }
 
 type GetListCategoryRequest struct {
-	Offset int64  `json:"offset"`
-	Limit  int64  `json:"limit"`
-	Search string `json:"search"`
-	Query  string `json:"query"`
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Search  string            `json:"search"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
 }
 
 type GetListCategoryResponse struct {
 	Count      int         `json:"count"`
 	Categories []*Category `json:"categories"`
 }
+
+type MoveCategory struct {
+	Id       string `json:"id"`
+	ParentID string `json:"parent_id"`
+}
+
+type GetCategoryTreeRequest struct {
+	RootID string `json:"root_id"`
+}
+
+// CategoryNode is a category in the tree with its subcategories.
+// ProductCount counts the products of the category itself, TotalProductCount
+// those of its subcategories too.
+type CategoryNode struct {
+	Id                string          `json:"id"`
+	Title             string          `json:"title"`
+	BrandID           string          `json:"brand_id"`
+	ParentID          string          `json:"parent_id"`
+	ProductCount      int             `json:"product_count"`
+	TotalProductCount int             `json:"total_product_count"`
+	Children          []*CategoryNode `json:"children"`
+}
+
+type GetCategoryTreeResponse struct {
+	Categories []*CategoryNode `json:"categories"`
+}
+
+// GetCategoryBreadcrumbsResponse lists the categories from the root down to
+// the category asked for, which is the last.
+type GetCategoryBreadcrumbsResponse struct {
+	Categories []*Category `json:"categories"`
+}
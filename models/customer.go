@@ -0,0 +1,62 @@
+package models
+
+import "market_system/pkg/criteria"
+
+type CustomerPrimaryKey struct {
+	Id string `json:"id"`
+}
+
+type CreateCustomer struct {
+	Phone       string `json:"phone"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	CardBarcode string `json:"card_barcode"`
+}
+
+type Customer struct {
+	Id            string  `json:"id"`
+	Phone         string  `json:"phone"`
+	FirstName     string  `json:"first_name"`
+	LastName      string  `json:"last_name"`
+	CardBarcode   string  `json:"card_barcode"`
+	TierID        string  `json:"tier_id"`
+	TierName      string  `json:"tier_name"`
+	PointsBalance float64 `json:"points_balance"`
+	TotalSpent    float64 `json:"total_spent"`
+	CreatedAt     string  `json:"created_at"`
+	UpdatedAt     string  `json:"updated_at"`
+}
+
+type UpdateCustomer struct {
+	Id          string `json:"id"`
+	Phone       string `json:"phone"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	CardBarcode string `json:"card_barcode"`
+}
+
+type GetListCustomerRequest struct {
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Search  string            `json:"search"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
+}
+
+type GetListCustomerResponse struct {
+	Count     int         `json:"count"`
+	Customers []*Customer `json:"customers"`
+}
+
+// AttachSaleCustomer identifies the customer of an open sale by phone or
+// loyalty card barcode.
+type AttachSaleCustomer struct {
+	SaleID      string `json:"-"`
+	Phone       string `json:"phone"`
+	CardBarcode string `json:"card_barcode"`
+}
+
+type SaleCustomer struct {
+	SaleID     string `json:"sale_id"`
+	CustomerID string `json:"customer_id"`
+}
@@ -1,5 +1,7 @@
 package models
 
+import "market_system/pkg/criteria"
+
 type EmployeePrimaryKey struct {
 	Id string `json:"id"`
 }
@@ -42,10 +44,11 @@ type UpdateEmployee struct {
 }
 
 type GetListEmployeeRequest struct {
-	Offset int64  `json:"offset"`
-	Limit  int64  `json:"limit"`
-	Search string `json:"search"`
-	Query  string `json:"query"`
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Search  string            `json:"search"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
 }
 
 type GetListEmployeeResponse struct {
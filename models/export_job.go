@@ -0,0 +1,52 @@
+package models
+
+import "market_system/pkg/criteria"
+
+type ExportJobPrimaryKey struct {
+	Id string `json:"id"`
+}
+
+type CreateExportJob struct {
+	Format    string `json:"format"`
+	Lang      string `json:"lang"`
+	Path      string `json:"path"`
+	FileName  string `json:"file_name"`
+	CreatedBy string `json:"created_by"`
+}
+
+// ExportJob is a list or report exported in the background. DownloadURL is
+// set once the file is ready.
+type ExportJob struct {
+	Id          string `json:"id"`
+	Status      string `json:"status"`
+	Format      string `json:"format"`
+	Lang        string `json:"lang"`
+	Path        string `json:"path"`
+	FileName    string `json:"file_name"`
+	Rows        int64  `json:"rows"`
+	Error       string `json:"error"`
+	DownloadURL string `json:"download_url"`
+	CreatedBy   string `json:"created_by"`
+	CreatedAt   string `json:"created_at"`
+	FinishedAt  string `json:"finished_at"`
+}
+
+type UpdateExportJob struct {
+	Id     string `json:"id"`
+	Status string `json:"status"`
+	Rows   int64  `json:"rows"`
+	Error  string `json:"error"`
+}
+
+type GetListExportJobRequest struct {
+	Offset    int64             `json:"offset"`
+	Limit     int64             `json:"limit"`
+	Filters   []criteria.Filter `json:"filters"`
+	Sort      []criteria.Sort   `json:"sort"`
+	CreatedBy string            `json:"created_by"`
+}
+
+type GetListExportJobResponse struct {
+	Count      int          `json:"count"`
+	ExportJobs []*ExportJob `json:"export_jobs"`
+}
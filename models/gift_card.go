@@ -0,0 +1,96 @@
+package models
+
+import "market_system/pkg/criteria"
+
+type GiftCardPrimaryKey struct {
+	Id string `json:"id"`
+}
+
+type GiftCardCode struct {
+	Code string `json:"code"`
+}
+
+// SellGiftCard adds a gift card line to an open sale. The card becomes
+// usable once the sale is finished.
+type SellGiftCard struct {
+	SaleID     string  `json:"sale_id"`
+	Amount     float64 `json:"amount"`
+	CustomerID string  `json:"customer_id"`
+	ExpiresAt  string  `json:"expires_at"`
+}
+
+// CreateStoreCredit issues store credit, e.g. for a return refunded to
+// credit instead of cash. SaleID is the returned sale.
+type CreateStoreCredit struct {
+	Amount     float64 `json:"amount"`
+	CustomerID string  `json:"customer_id"`
+	SaleID     string  `json:"sale_id"`
+	ExpiresAt  string  `json:"expires_at"`
+	Comment    string  `json:"comment"`
+}
+
+type GiftCard struct {
+	Id            string  `json:"id"`
+	Code          string  `json:"code"`
+	Type          string  `json:"type"`
+	Status        string  `json:"status"`
+	InitialAmount float64 `json:"initial_amount"`
+	Balance       float64 `json:"balance"`
+	CustomerID    string  `json:"customer_id"`
+	SaleID        string  `json:"sale_id"`
+	ExpiresAt     string  `json:"expires_at"`
+	Expired       bool    `json:"expired"`
+	Comment       string  `json:"comment"`
+	CreatedAt     string  `json:"created_at"`
+	UpdatedAt     string  `json:"updated_at"`
+}
+
+type GetListGiftCardRequest struct {
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
+}
+
+type GetListGiftCardResponse struct {
+	Count     int         `json:"count"`
+	GiftCards []*GiftCard `json:"gift_cards"`
+}
+
+type RedeemGiftCard struct {
+	Code   string  `json:"code"`
+	SaleID string  `json:"sale_id"`
+	Amount float64 `json:"amount"`
+}
+
+type GiftCardTransaction struct {
+	Id           string  `json:"id"`
+	GiftCardID   string  `json:"gift_card_id"`
+	SaleID       string  `json:"sale_id"`
+	Type         string  `json:"type"`
+	Amount       float64 `json:"amount"`
+	BalanceAfter float64 `json:"balance_after"`
+	CreatedAt    string  `json:"created_at"`
+}
+
+type GiftCardHistoryResponse struct {
+	Transactions []*GiftCardTransaction `json:"transactions"`
+}
+
+type GiftCardLiabilityRequest struct {
+	At string `json:"at"`
+}
+
+type GiftCardLiability struct {
+	Type        string  `json:"type"`
+	Cards       int     `json:"cards"`
+	Issued      float64 `json:"issued"`
+	Redeemed    float64 `json:"redeemed"`
+	Outstanding float64 `json:"outstanding"`
+	Expired     float64 `json:"expired"`
+}
+
+type GiftCardLiabilityResponse struct {
+	Types       []*GiftCardLiability `json:"types"`
+	Outstanding float64              `json:"outstanding"`
+}
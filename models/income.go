@@ -1,24 +1,28 @@
 package models
 
+import "market_system/pkg/criteria"
+
 type IncomePrimaryKey struct {
 	Id string `json:"id"`
 }
 
 type CreateIncome struct {
-	BranchID   string `json:"branch_id"`
-	SupplierID string `json:"supplier_id"`
-	DateTime   string `json:"date_time"`
-	Status     string `json:"status"`
+	BranchID        string `json:"branch_id"`
+	SupplierID      string `json:"supplier_id"`
+	DateTime        string `json:"date_time"`
+	Status          string `json:"status"`
+	PurchaseOrderID string `json:"purchase_order_id"`
 }
 
 type Income struct {
-	Id         string `json:"id"`
-	BranchID   string `json:"branch_id"`
-	SupplierID string `json:"supplier_id"`
-	DateTime   string `json:"date_time"`
-	Status     string `json:"status"`
-	CreatedAt  string `json:"created_at"`
-	UpdatedAt  string `json:"updated_at"`
+	Id              string `json:"id"`
+	BranchID        string `json:"branch_id"`
+	SupplierID      string `json:"supplier_id"`
+	DateTime        string `json:"date_time"`
+	Status          string `json:"status"`
+	PurchaseOrderID string `json:"purchase_order_id"`
+	CreatedAt       string `json:"created_at"`
+	UpdatedAt       string `json:"updated_at"`
 }
 
 type UpdateIncome struct {
@@ -30,10 +34,11 @@ type UpdateIncome struct {
 }
 
 type GetListIncomeRequest struct {
-	Offset int64  `json:"offset"`
-	Limit  int64  `json:"limit"`
-	Search string `json:"search"`
-	Query  string `json:"query"`
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Search  string            `json:"search"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
 }
 
 type GetListIncomeResponse struct {
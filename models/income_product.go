@@ -1,5 +1,7 @@
 package models
 
+import "market_system/pkg/criteria"
+
 type IncomeProductPrimaryKey struct {
 	Id string `json:"id"`
 }
@@ -11,18 +13,23 @@ type CreateIncomeProduct struct {
 	Barcode     string  `json:"barcode"`
 	Quantity    int64   `json:"quantity"`
 	IncomePrice float64 `json:"income_price"`
+	LotNumber   string  `json:"lot_number"`
+	ExpiryDate  string  `json:"expiry_date"`
 }
 
 type IncomeProduct struct {
-	Id          string  `json:"id"`
-	IncomeID    string  `json:"income_id"`
-	CategoryID  string  `json:"category_id"`
-	ProductName string  `json:"product_name"`
-	Barcode     string  `json:"barcode"`
-	Quantity    int64   `json:"quantity"`
-	IncomePrice float64 `json:"income_price"`
-	CreatedAt   string  `json:"created_at"`
-	UpdatedAt   string  `json:"updated_at"`
+	Id                     string  `json:"id"`
+	IncomeID               string  `json:"income_id"`
+	CategoryID             string  `json:"category_id"`
+	ProductName            string  `json:"product_name"`
+	Barcode                string  `json:"barcode"`
+	Quantity               int64   `json:"quantity"`
+	IncomePrice            float64 `json:"income_price"`
+	PurchaseOrderProductID string  `json:"purchase_order_product_id"`
+	LotNumber              string  `json:"lot_number"`
+	ExpiryDate             string  `json:"expiry_date"`
+	CreatedAt              string  `json:"created_at"`
+	UpdatedAt              string  `json:"updated_at"`
 }
 
 type UpdateIncomeProduct struct {
@@ -33,13 +40,16 @@ type UpdateIncomeProduct struct {
 	IncomePrice float64 `json:"income_price"`
 	IncomeID    string  `json:"income_id"`
 	CategoryID  string  `json:"category_id"`
+	LotNumber   string  `json:"lot_number"`
+	ExpiryDate  string  `json:"expiry_date"`
 }
 
 type GetListIncomeProductRequest struct {
-	Offset int64  `json:"offset"`
-	Limit  int64  `json:"limit"`
-	Search string `json:"search"`
-	Query  string `json:"query"`
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Search  string            `json:"search"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
 }
 
 type GetListIncomeProductResponse struct {
@@ -0,0 +1,94 @@
+package models
+
+import "market_system/pkg/criteria"
+
+type InventoryCountPrimaryKey struct {
+	Id string `json:"id"`
+}
+
+// CreateInventoryCount opens a count for a branch. With a category only that
+// category is snapshotted and may be scanned.
+type CreateInventoryCount struct {
+	BranchID   string `json:"branch_id"`
+	CategoryID string `json:"category_id"`
+	Comment    string `json:"comment"`
+	CreatedBy  string `json:"-"`
+}
+
+// InventoryCountProduct is one barcode of a count. BookQuantity is what the
+// branch should have had when the barcode was counted: the expected quantity
+// plus MovedQuantity, the net stock movement (sales, incomes, transfers)
+// between the snapshot and the last scan. Variance is counted minus book.
+type InventoryCountProduct struct {
+	Id               string  `json:"id"`
+	InventoryCountID string  `json:"inventory_count_id"`
+	CategoryID       string  `json:"category_id"`
+	ProductName      string  `json:"product_name"`
+	Barcode          string  `json:"barcode"`
+	Cost             float64 `json:"cost"`
+	ExpectedQuantity int64   `json:"expected_quantity"`
+	MovedQuantity    int64   `json:"moved_quantity"`
+	BookQuantity     int64   `json:"book_quantity"`
+	CountedQuantity  int64   `json:"counted_quantity"`
+	Counted          bool    `json:"counted"`
+	Variance         int64   `json:"variance"`
+	CostImpact       float64 `json:"cost_impact"`
+}
+
+// InventoryCountSummary totals the variance of a count. Shortages are given
+// as positive numbers; Net is surplus cost minus shortage cost.
+type InventoryCountSummary struct {
+	Lines            int     `json:"lines"`
+	CountedLines     int     `json:"counted_lines"`
+	SurplusQuantity  int64   `json:"surplus_quantity"`
+	SurplusCost      float64 `json:"surplus_cost"`
+	ShortageQuantity int64   `json:"shortage_quantity"`
+	ShortageCost     float64 `json:"shortage_cost"`
+	Net              float64 `json:"net"`
+}
+
+type InventoryCount struct {
+	Id         string                   `json:"id"`
+	BranchID   string                   `json:"branch_id"`
+	CategoryID string                   `json:"category_id"`
+	Status     string                   `json:"status"`
+	Comment    string                   `json:"comment"`
+	CreatedBy  string                   `json:"created_by"`
+	SnapshotAt string                   `json:"snapshot_at"`
+	PostedBy   string                   `json:"posted_by"`
+	PostedAt   string                   `json:"posted_at"`
+	Summary    *InventoryCountSummary   `json:"summary,omitempty"`
+	Products   []*InventoryCountProduct `json:"products,omitempty"`
+	CreatedAt  string                   `json:"created_at"`
+	UpdatedAt  string                   `json:"updated_at"`
+}
+
+type GetListInventoryCountRequest struct {
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
+}
+
+type GetListInventoryCountResponse struct {
+	Count           int               `json:"count"`
+	InventoryCounts []*InventoryCount `json:"inventory_counts"`
+}
+
+type ScanInventoryCountProduct struct {
+	Barcode  string `json:"barcode"`
+	Quantity int64  `json:"quantity"`
+}
+
+// ScanInventoryCount adds what a counter scanned. Scans of all counters are
+// summed per barcode; a negative quantity corrects an earlier scan.
+type ScanInventoryCount struct {
+	Id        string                       `json:"-"`
+	CountedBy string                       `json:"-"`
+	Products  []*ScanInventoryCountProduct `json:"products"`
+}
+
+type PostInventoryCount struct {
+	Id       string `json:"-"`
+	PostedBy string `json:"-"`
+}
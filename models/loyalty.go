@@ -0,0 +1,131 @@
+package models
+
+import "market_system/pkg/criteria"
+
+type LoyaltyTierPrimaryKey struct {
+	Id string `json:"id"`
+}
+
+type CreateLoyaltyTier struct {
+	Name           string  `json:"name"`
+	MinSpent       float64 `json:"min_spent"`
+	EarnMultiplier float64 `json:"earn_multiplier"`
+}
+
+type LoyaltyTier struct {
+	Id             string  `json:"id"`
+	Name           string  `json:"name"`
+	MinSpent       float64 `json:"min_spent"`
+	EarnMultiplier float64 `json:"earn_multiplier"`
+	CreatedAt      string  `json:"created_at"`
+	UpdatedAt      string  `json:"updated_at"`
+}
+
+type UpdateLoyaltyTier struct {
+	Id             string  `json:"id"`
+	Name           string  `json:"name"`
+	MinSpent       float64 `json:"min_spent"`
+	EarnMultiplier float64 `json:"earn_multiplier"`
+}
+
+type GetListLoyaltyTierRequest struct {
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Search  string            `json:"search"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
+}
+
+type GetListLoyaltyTierResponse struct {
+	Count        int            `json:"count"`
+	LoyaltyTiers []*LoyaltyTier `json:"loyalty_tiers"`
+}
+
+type LoyaltyEarnRatePrimaryKey struct {
+	Id string `json:"id"`
+}
+
+// SetLoyaltyEarnRate creates or replaces the earn rate of a category. An
+// empty CategoryID sets the default rate.
+type SetLoyaltyEarnRate struct {
+	CategoryID string  `json:"category_id"`
+	Percent    float64 `json:"percent"`
+}
+
+type LoyaltyEarnRate struct {
+	Id           string  `json:"id"`
+	CategoryID   string  `json:"category_id"`
+	CategoryName string  `json:"category_name"`
+	Percent      float64 `json:"percent"`
+	CreatedAt    string  `json:"created_at"`
+	UpdatedAt    string  `json:"updated_at"`
+}
+
+type GetListLoyaltyEarnRateRequest struct {
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
+}
+
+type GetListLoyaltyEarnRateResponse struct {
+	Count     int                `json:"count"`
+	EarnRates []*LoyaltyEarnRate `json:"earn_rates"`
+}
+
+type LoyaltyTransaction struct {
+	Id           string  `json:"id"`
+	CustomerID   string  `json:"customer_id"`
+	SaleID       string  `json:"sale_id"`
+	Type         string  `json:"type"`
+	Points       float64 `json:"points"`
+	BalanceAfter float64 `json:"balance_after"`
+	Comment      string  `json:"comment"`
+	CreatedAt    string  `json:"created_at"`
+}
+
+type LoyaltyHistoryRequest struct {
+	CustomerID string `json:"customer_id"`
+	Offset     int64  `json:"offset"`
+	Limit      int64  `json:"limit"`
+}
+
+type LoyaltyHistoryResponse struct {
+	Count        int                   `json:"count"`
+	Transactions []*LoyaltyTransaction `json:"transactions"`
+}
+
+// ApplySaleLoyalty books a finished sale on its customer: RedeemPoints paid
+// with points are taken off the balance and points are earned on the rest.
+type ApplySaleLoyalty struct {
+	SaleID       string  `json:"sale_id"`
+	CustomerID   string  `json:"customer_id"`
+	RedeemPoints float64 `json:"redeem_points"`
+}
+
+// AdjustLoyaltyPoints adds (or with a negative value removes) points by hand.
+type AdjustLoyaltyPoints struct {
+	CustomerID string  `json:"-"`
+	Points     float64 `json:"points"`
+	Comment    string  `json:"comment"`
+}
+
+type RepeatCustomerReportRequest struct {
+	FromDate string `json:"from_date"`
+	ToDate   string `json:"to_date"`
+	BranchID string `json:"branch_id"`
+}
+
+type RepeatCustomerReport struct {
+	BranchID        string  `json:"branch_id"`
+	BranchName      string  `json:"branch_name"`
+	Sales           int     `json:"sales"`
+	CustomerSales   int     `json:"customer_sales"`
+	Customers       int     `json:"customers"`
+	RepeatCustomers int     `json:"repeat_customers"`
+	RepeatRate      float64 `json:"repeat_rate"`
+}
+
+type RepeatCustomerReportResponse struct {
+	Branches []*RepeatCustomerReport `json:"branches"`
+}
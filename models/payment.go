@@ -1,50 +1,62 @@
 package models
 
+import "market_system/pkg/criteria"
+
 type PaymentPrimaryKey struct {
 	Id string `json:"id"`
 }
 
 type CreatePayment struct {
-	SaleID      string  `json:"sale_id"`
-	Cash        float64 `json:"cash"`
-	Uzcard      float64 `json:"uzcard"`
-	Payme       float64 `json:"payme"`
-	Click       float64 `json:"click"`
-	Humo        float64 `json:"humo"`
-	Apelsin     float64 `json:"apelsin"`
-	TotalAmount float64 `json:"total_amount"`
+	SaleID       string  `json:"sale_id"`
+	Cash         float64 `json:"cash"`
+	Uzcard       float64 `json:"uzcard"`
+	Payme        float64 `json:"payme"`
+	Click        float64 `json:"click"`
+	Humo         float64 `json:"humo"`
+	Apelsin      float64 `json:"apelsin"`
+	Points       float64 `json:"points"`
+	GiftCard     float64 `json:"gift_card"`
+	GiftCardCode string  `json:"gift_card_code"`
+	TotalAmount  float64 `json:"total_amount"`
 }
 
 type Payment struct {
-	Id          string  `json:"id"`
-	SaleID      string  `json:"sale_id"`
-	Cash        float64 `json:"cash"`
-	Uzcard      float64 `json:"uzcard"`
-	Payme       float64 `json:"payme"`
-	Click       float64 `json:"click"`
-	Humo        float64 `json:"humo"`
-	Apelsin     float64 `json:"apelsin"`
-	TotalAmount float64 `json:"total_amount"`
-	CreatedAt   string  `json:"created_at"`
-	UpdatedAt   string  `json:"updated_at"`
+	Id           string  `json:"id"`
+	SaleID       string  `json:"sale_id"`
+	Cash         float64 `json:"cash"`
+	Uzcard       float64 `json:"uzcard"`
+	Payme        float64 `json:"payme"`
+	Click        float64 `json:"click"`
+	Humo         float64 `json:"humo"`
+	Apelsin      float64 `json:"apelsin"`
+	Points       float64 `json:"points"`
+	GiftCard     float64 `json:"gift_card"`
+	GiftCardCode string  `json:"gift_card_code"`
+	TotalAmount  float64 `json:"total_amount"`
+	CreatedAt    string  `json:"created_at"`
+	UpdatedAt    string  `json:"updated_at"`
 }
 
 type UpdatePayment struct {
-	Id          string  `json:"id"`
-	Cash        float64 `json:"cash"`
-	Uzcard      float64 `json:"uzcard"`
-	Payme       float64 `json:"payme"`
-	Click       float64 `json:"click"`
-	Humo        float64 `json:"humo"`
-	Apelsin     float64 `json:"apelsin"`
-	TotalAmount float64 `json:"total_amount"`
+	Id           string  `json:"id"`
+	Cash         float64 `json:"cash"`
+	Uzcard       float64 `json:"uzcard"`
+	Payme        float64 `json:"payme"`
+	Click        float64 `json:"click"`
+	Humo         float64 `json:"humo"`
+	Apelsin      float64 `json:"apelsin"`
+	Points       float64 `json:"points"`
+	GiftCard     float64 `json:"gift_card"`
+	GiftCardCode string  `json:"gift_card_code"`
+	TotalAmount  float64 `json:"total_amount"`
 }
 
 type GetListPaymentRequest struct {
-	Offset int64  `json:"offset"`
-	Limit  int64  `json:"limit"`
-	Search string `json:"search"`
-	Query  string `json:"query"`
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Search  string            `json:"search"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
 }
 
 type GetListPaymentResponse struct {
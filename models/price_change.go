@@ -0,0 +1,67 @@
+package models
+
+import "market_system/pkg/criteria"
+
+type PriceChangePrimaryKey struct {
+	Id string `json:"id"`
+}
+
+type CreatePriceChangeProduct struct {
+	ProductID string  `json:"product_id"`
+	NewPrice  float64 `json:"new_price"`
+}
+
+type PriceChangeProduct struct {
+	Id            string  `json:"id"`
+	PriceChangeID string  `json:"price_change_id"`
+	ProductID     string  `json:"product_id"`
+	OldPrice      float64 `json:"old_price"`
+	NewPrice      float64 `json:"new_price"`
+	CreatedAt     string  `json:"created_at"`
+	UpdatedAt     string  `json:"updated_at"`
+}
+
+type CreatePriceChange struct {
+	BranchID      string                      `json:"branch_id"`
+	EffectiveFrom string                      `json:"effective_from"`
+	Comment       string                      `json:"comment"`
+	Products      []*CreatePriceChangeProduct `json:"products"`
+}
+
+type PriceChange struct {
+	Id            string                `json:"id"`
+	BranchID      string                `json:"branch_id"`
+	EffectiveFrom string                `json:"effective_from"`
+	Status        string                `json:"status"`
+	Comment       string                `json:"comment"`
+	ApprovedBy    string                `json:"approved_by"`
+	ApprovedAt    string                `json:"approved_at"`
+	Products      []*PriceChangeProduct `json:"products"`
+	CreatedAt     string                `json:"created_at"`
+	UpdatedAt     string                `json:"updated_at"`
+}
+
+// UpdatePriceChange replaces the header and all lines of a draft document.
+type UpdatePriceChange struct {
+	Id            string                      `json:"id"`
+	EffectiveFrom string                      `json:"effective_from"`
+	Comment       string                      `json:"comment"`
+	Products      []*CreatePriceChangeProduct `json:"products"`
+}
+
+type ApprovePriceChange struct {
+	Id         string `json:"id"`
+	ApprovedBy string `json:"approved_by"`
+}
+
+type GetListPriceChangeRequest struct {
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
+}
+
+type GetListPriceChangeResponse struct {
+	Count        int            `json:"count"`
+	PriceChanges []*PriceChange `json:"price_changes"`
+}
@@ -1,5 +1,7 @@
 package models
 
+import "market_system/pkg/criteria"
+
 type ProductPrimaryKey struct {
 	Id string `json:"id"`
 }
@@ -10,6 +12,8 @@ type CreateProduct struct {
 	CategoryID string  `json:"category_id"`
 	Barcode    string  `json:"barcode"`
 	Price      float64 `json:"price"`
+	Unit       string  `json:"unit"`
+	BrandID    string  `json:"brand_id"`
 }
 
 type Product struct {
@@ -19,6 +23,8 @@ type Product struct {
 	CategoryID string  `json:"category_id"`
 	Barcode    string  `json:"barcode"`
 	Price      float64 `json:"price"`
+	Unit       string  `json:"unit"`
+	BrandID    string  `json:"brand_id"`
 	CreatedAt  string  `json:"created_at"`
 	UpdatedAt  string  `json:"updated_at"`
 }
@@ -30,16 +36,32 @@ type UpdateProduct struct {
 	CategoryID string  `json:"category_id"`
 	Barcode    string  `json:"barcode"`
 	Price      float64 `json:"price"`
+	Unit       string  `json:"unit"`
+	BrandID    string  `json:"brand_id"`
 }
 
 type GetListProductRequest struct {
-	Offset int64  `json:"offset"`
-	Limit  int64  `json:"limit"`
-	Search string `json:"search"`
-	Query  string `json:"query"`
+	Offset   int64             `json:"offset"`
+	Limit    int64             `json:"limit"`
+	Search   string            `json:"search"`
+	Filters  []criteria.Filter `json:"filters"`
+	Sort     []criteria.Sort   `json:"sort"`
+	BranchID string            `json:"branch_id"`
+	ABC      string            `json:"abc"`
+	XYZ      string            `json:"xyz"`
 }
 
 type GetListProductResponse struct {
 	Count    int        `json:"count"`
 	Products []*Product `json:"products"`
 }
+
+type SearchProductRequest struct {
+	Query   string            `json:"query"`
+	Limit   int64             `json:"limit"`
+	Filters []criteria.Filter `json:"filters"`
+}
+
+type SearchProductResponse struct {
+	Products []*Product `json:"products"`
+}
@@ -0,0 +1,84 @@
+package models
+
+import "market_system/pkg/criteria"
+
+type ProductClassRunPrimaryKey struct {
+	Id string `json:"id"`
+}
+
+// RunProductClass classifies the products of every branch on the finished
+// sales of the last HistoryWeeks full weeks up to today.
+type RunProductClass struct {
+	HistoryWeeks int64  `json:"history_weeks"`
+	CreatedBy    string `json:"-"`
+}
+
+// ProductClassRun is one classification. ToDate is exclusive.
+type ProductClassRun struct {
+	Id           string `json:"id"`
+	RunDate      string `json:"run_date"`
+	FromDate     string `json:"from_date"`
+	ToDate       string `json:"to_date"`
+	HistoryWeeks int64  `json:"history_weeks"`
+	Products     int64  `json:"products"`
+	CreatedBy    string `json:"created_by"`
+	CreatedAt    string `json:"created_at"`
+}
+
+type GetListProductClassRunRequest struct {
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
+}
+
+type GetListProductClassRunResponse struct {
+	Count int                `json:"count"`
+	Runs  []*ProductClassRun `json:"runs"`
+}
+
+// ProductClass is the class of a product in a branch. Share and
+// CumulativeShare are fractions of the branch revenue; CV is the coefficient
+// of variation of the units sold per week.
+type ProductClass struct {
+	RunID           string  `json:"run_id"`
+	RunDate         string  `json:"run_date"`
+	BranchID        string  `json:"branch_id"`
+	BranchName      string  `json:"branch_name"`
+	Barcode         string  `json:"barcode"`
+	ProductName     string  `json:"product_name"`
+	Revenue         float64 `json:"revenue"`
+	Units           int64   `json:"units"`
+	Share           float64 `json:"share"`
+	CumulativeShare float64 `json:"cumulative_share"`
+	ABC             string  `json:"abc"`
+	WeeklyMean      float64 `json:"weekly_mean"`
+	CV              float64 `json:"cv"`
+	XYZ             string  `json:"xyz"`
+}
+
+// GetListProductClassRequest lists the classes of a run, the latest one when
+// RunID is empty.
+type GetListProductClassRequest struct {
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Search  string            `json:"search"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
+	RunID   string            `json:"run_id"`
+}
+
+// ProductClassCount is the number of products in one cell of the ABC/XYZ
+// matrix.
+type ProductClassCount struct {
+	ABC      string `json:"abc"`
+	XYZ      string `json:"xyz"`
+	Products int64  `json:"products"`
+}
+
+type GetListProductClassResponse struct {
+	Count   int                  `json:"count"`
+	Run     *ProductClassRun     `json:"run"`
+	Matrix  []*ProductClassCount `json:"matrix"`
+	Classes []*ProductClass      `json:"classes"`
+}
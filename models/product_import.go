@@ -0,0 +1,89 @@
+package models
+
+import (
+	"io"
+
+	"market_system/pkg/criteria"
+)
+
+type ProductImportPrimaryKey struct {
+	Id string `json:"id"`
+}
+
+// CreateProductImport is a product file to validate. File is read once.
+type CreateProductImport struct {
+	FileName  string    `json:"file_name"`
+	Format    string    `json:"format"`
+	File      io.Reader `json:"-"`
+	CreatedBy string    `json:"created_by"`
+}
+
+// ProductImport is a validated product file. ToCreate, ToUpdate,
+// NewCategories and NewBrands preview what running it does; Created,
+// Updated, CategoriesCreated and BrandsCreated are what it has done so far.
+type ProductImport struct {
+	Id                string              `json:"id"`
+	Status            string              `json:"status"`
+	FileName          string              `json:"file_name"`
+	Format            string              `json:"format"`
+	TotalRows         int64               `json:"total_rows"`
+	ValidRows         int64               `json:"valid_rows"`
+	InvalidRows       int64               `json:"invalid_rows"`
+	ToCreate          int64               `json:"to_create"`
+	ToUpdate          int64               `json:"to_update"`
+	NewCategories     int64               `json:"new_categories"`
+	NewBrands         int64               `json:"new_brands"`
+	ImportedRows      int64               `json:"imported_rows"`
+	Created           int64               `json:"created"`
+	Updated           int64               `json:"updated"`
+	CategoriesCreated int64               `json:"categories_created"`
+	BrandsCreated     int64               `json:"brands_created"`
+	Error             string              `json:"error"`
+	CreatedBy         string              `json:"created_by"`
+	CreatedAt         string              `json:"created_at"`
+	StartedAt         string              `json:"started_at"`
+	FinishedAt        string              `json:"finished_at"`
+	Preview           []*ProductImportRow `json:"preview,omitempty"`
+}
+
+// ProductImportRow is a row of a product file; Category is the path of
+// category titles from the root.
+type ProductImportRow struct {
+	Line      int64    `json:"line"`
+	Title     string   `json:"title"`
+	Barcode   string   `json:"barcode"`
+	Category  []string `json:"category"`
+	Brand     string   `json:"brand"`
+	Price     float64  `json:"price"`
+	Unit      string   `json:"unit"`
+	Errors    []string `json:"errors"`
+	Status    string   `json:"status"`
+	ProductID string   `json:"product_id"`
+}
+
+type GetListProductImportRequest struct {
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Search  string            `json:"search"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
+}
+
+type GetListProductImportResponse struct {
+	Count          int              `json:"count"`
+	ProductImports []*ProductImport `json:"product_imports"`
+}
+
+type GetListProductImportRowRequest struct {
+	Offset   int64             `json:"offset"`
+	Limit    int64             `json:"limit"`
+	Search   string            `json:"search"`
+	Filters  []criteria.Filter `json:"filters"`
+	Sort     []criteria.Sort   `json:"sort"`
+	ImportID string            `json:"import_id"`
+}
+
+type GetListProductImportRowResponse struct {
+	Count int                 `json:"count"`
+	Rows  []*ProductImportRow `json:"rows"`
+}
@@ -0,0 +1,145 @@
+package models
+
+import "market_system/pkg/criteria"
+
+type PromotionPrimaryKey struct {
+	Id string `json:"id"`
+}
+
+type PromotionItem struct {
+	Barcode  string `json:"barcode"`
+	Quantity int    `json:"quantity"`
+}
+
+type CreatePromotion struct {
+	Name            string           `json:"name"`
+	Type            string           `json:"type"`
+	Percent         float64          `json:"percent"`
+	BuyQuantity     int              `json:"buy_quantity"`
+	GetQuantity     int              `json:"get_quantity"`
+	BundlePrice     float64          `json:"bundle_price"`
+	CategoryID      string           `json:"category_id"`
+	BrandID         string           `json:"brand_id"`
+	MinBasketAmount float64          `json:"min_basket_amount"`
+	StartDate       string           `json:"start_date"`
+	EndDate         string           `json:"end_date"`
+	HappyHourFrom   string           `json:"happy_hour_from"`
+	HappyHourTo     string           `json:"happy_hour_to"`
+	Priority        int              `json:"priority"`
+	Stackable       bool             `json:"stackable"`
+	IsActive        bool             `json:"is_active"`
+	BranchIDs       []string         `json:"branch_ids"`
+	Items           []*PromotionItem `json:"items"`
+}
+
+type Promotion struct {
+	Id              string           `json:"id"`
+	Name            string           `json:"name"`
+	Type            string           `json:"type"`
+	Percent         float64          `json:"percent"`
+	BuyQuantity     int              `json:"buy_quantity"`
+	GetQuantity     int              `json:"get_quantity"`
+	BundlePrice     float64          `json:"bundle_price"`
+	CategoryID      string           `json:"category_id"`
+	BrandID         string           `json:"brand_id"`
+	MinBasketAmount float64          `json:"min_basket_amount"`
+	StartDate       string           `json:"start_date"`
+	EndDate         string           `json:"end_date"`
+	HappyHourFrom   string           `json:"happy_hour_from"`
+	HappyHourTo     string           `json:"happy_hour_to"`
+	Priority        int              `json:"priority"`
+	Stackable       bool             `json:"stackable"`
+	IsActive        bool             `json:"is_active"`
+	BranchIDs       []string         `json:"branch_ids"`
+	Items           []*PromotionItem `json:"items"`
+	CreatedAt       string           `json:"created_at"`
+	UpdatedAt       string           `json:"updated_at"`
+}
+
+type UpdatePromotion struct {
+	Id              string           `json:"id"`
+	Name            string           `json:"name"`
+	Type            string           `json:"type"`
+	Percent         float64          `json:"percent"`
+	BuyQuantity     int              `json:"buy_quantity"`
+	GetQuantity     int              `json:"get_quantity"`
+	BundlePrice     float64          `json:"bundle_price"`
+	CategoryID      string           `json:"category_id"`
+	BrandID         string           `json:"brand_id"`
+	MinBasketAmount float64          `json:"min_basket_amount"`
+	StartDate       string           `json:"start_date"`
+	EndDate         string           `json:"end_date"`
+	HappyHourFrom   string           `json:"happy_hour_from"`
+	HappyHourTo     string           `json:"happy_hour_to"`
+	Priority        int              `json:"priority"`
+	Stackable       bool             `json:"stackable"`
+	IsActive        bool             `json:"is_active"`
+	BranchIDs       []string         `json:"branch_ids"`
+	Items           []*PromotionItem `json:"items"`
+}
+
+type GetListPromotionRequest struct {
+	Offset   int64             `json:"offset"`
+	Limit    int64             `json:"limit"`
+	Search   string            `json:"search"`
+	Filters  []criteria.Filter `json:"filters"`
+	Sort     []criteria.Sort   `json:"sort"`
+	BranchID string            `json:"branch_id"`
+}
+
+type GetListPromotionResponse struct {
+	Count      int          `json:"count"`
+	Promotions []*Promotion `json:"promotions"`
+}
+
+// GetActivePromotionRequest selects active promotions that target the branch
+// and whose date range contains At (now when empty).
+type GetActivePromotionRequest struct {
+	BranchID string `json:"branch_id"`
+	At       string `json:"at"`
+}
+
+// BasketLine is a sale_products row with what the promotion rules match on.
+type BasketLine struct {
+	SaleProductID string  `json:"sale_product_id"`
+	Barcode       string  `json:"barcode"`
+	CategoryID    string  `json:"category_id"`
+	BrandID       string  `json:"brand_id"`
+	Quantity      int     `json:"quantity"`
+	Price         float64 `json:"price"`
+	DiscountType  string  `json:"discount_type"`
+	Discount      float64 `json:"discount"`
+}
+
+type SaleProductPromotion struct {
+	SaleProductID string  `json:"sale_product_id"`
+	PromotionID   string  `json:"promotion_id"`
+	Discount      float64 `json:"discount"`
+}
+
+// ApplySalePromotions replaces the promotion discounts of a sale. Lines in
+// SaleProductIDs get their discount and total recalculated from Discounts.
+type ApplySalePromotions struct {
+	SaleID         string                  `json:"sale_id"`
+	SaleProductIDs []string                `json:"sale_product_ids"`
+	Discounts      []*SaleProductPromotion `json:"discounts"`
+}
+
+type PromotionReportRequest struct {
+	FromDate string `json:"from_date"`
+	ToDate   string `json:"to_date"`
+	BranchID string `json:"branch_id"`
+}
+
+type PromotionReport struct {
+	PromotionID    string  `json:"promotion_id"`
+	Name           string  `json:"name"`
+	SalesCount     int     `json:"sales_count"`
+	Units          int     `json:"units"`
+	DiscountAmount float64 `json:"discount_amount"`
+	Revenue        float64 `json:"revenue"`
+}
+
+type PromotionReportResponse struct {
+	Promotions []*PromotionReport `json:"promotions"`
+}
@@ -0,0 +1,132 @@
+package models
+
+import "market_system/pkg/criteria"
+
+type PurchaseOrderPrimaryKey struct {
+	Id string `json:"id"`
+}
+
+type CreatePurchaseOrderProduct struct {
+	ProductID string  `json:"product_id"`
+	Quantity  int64   `json:"quantity"`
+	Price     float64 `json:"price"`
+}
+
+type CreatePurchaseOrder struct {
+	SupplierID   string                        `json:"supplier_id"`
+	BranchID     string                        `json:"branch_id"`
+	ExpectedDate string                        `json:"expected_date"`
+	Comment      string                        `json:"comment"`
+	CreatedBy    string                        `json:"-"`
+	Products     []*CreatePurchaseOrderProduct `json:"products"`
+}
+
+// PurchaseOrderProduct is an expected line. Difference is received minus
+// ordered: negative for under-delivery, positive for over-delivery.
+type PurchaseOrderProduct struct {
+	Id               string  `json:"id"`
+	PurchaseOrderID  string  `json:"purchase_order_id"`
+	ProductID        string  `json:"product_id"`
+	ProductName      string  `json:"product_name"`
+	Barcode          string  `json:"barcode"`
+	Quantity         int64   `json:"quantity"`
+	Price            float64 `json:"price"`
+	ReceivedQuantity int64   `json:"received_quantity"`
+	Difference       int64   `json:"difference"`
+	DeliveryStatus   string  `json:"delivery_status"`
+	CreatedAt        string  `json:"created_at"`
+	UpdatedAt        string  `json:"updated_at"`
+}
+
+type PurchaseOrder struct {
+	Id           string                  `json:"id"`
+	SupplierID   string                  `json:"supplier_id"`
+	BranchID     string                  `json:"branch_id"`
+	Status       string                  `json:"status"`
+	ExpectedDate string                  `json:"expected_date"`
+	Comment      string                  `json:"comment"`
+	CreatedBy    string                  `json:"created_by"`
+	SentAt       string                  `json:"sent_at"`
+	ReceivedAt   string                  `json:"received_at"`
+	ClosedAt     string                  `json:"closed_at"`
+	Products     []*PurchaseOrderProduct `json:"products,omitempty"`
+	CreatedAt    string                  `json:"created_at"`
+	UpdatedAt    string                  `json:"updated_at"`
+}
+
+type UpdatePurchaseOrder struct {
+	Id           string                        `json:"id"`
+	SupplierID   string                        `json:"supplier_id"`
+	BranchID     string                        `json:"branch_id"`
+	ExpectedDate string                        `json:"expected_date"`
+	Comment      string                        `json:"comment"`
+	Products     []*CreatePurchaseOrderProduct `json:"products"`
+}
+
+type GetListPurchaseOrderRequest struct {
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
+}
+
+type GetListPurchaseOrderResponse struct {
+	Count          int              `json:"count"`
+	PurchaseOrders []*PurchaseOrder `json:"purchase_orders"`
+}
+
+// SetPurchaseOrderStatus moves an order to To if it is in one of From.
+type SetPurchaseOrderStatus struct {
+	Id   string   `json:"id"`
+	From []string `json:"from"`
+	To   string   `json:"to"`
+}
+
+type ReceivePurchaseOrderProduct struct {
+	PurchaseOrderProductID string  `json:"purchase_order_product_id"`
+	Quantity               int64   `json:"quantity"`
+	Price                  float64 `json:"price"`
+	LotNumber              string  `json:"lot_number"`
+	ExpiryDate             string  `json:"expiry_date"`
+}
+
+// ReceivePurchaseOrder records a delivery against an order as a finished
+// income. Price defaults to the ordered price when zero.
+type ReceivePurchaseOrder struct {
+	PurchaseOrderID string                         `json:"-"`
+	DateTime        string                         `json:"date_time"`
+	Products        []*ReceivePurchaseOrderProduct `json:"products"`
+}
+
+type ReceivePurchaseOrderResponse struct {
+	Income        *Income        `json:"income"`
+	PurchaseOrder *PurchaseOrder `json:"purchase_order"`
+}
+
+type SupplierFillRateRequest struct {
+	FromDate   string `json:"from_date"`
+	ToDate     string `json:"to_date"`
+	SupplierID string `json:"supplier_id"`
+	BranchID   string `json:"branch_id"`
+}
+
+// SupplierFillRate compares what was ordered from a supplier with what
+// arrived. FillRate counts received units up to the ordered quantity,
+// LineFillRate the share of lines delivered in full, OnTimeRate the share
+// of orders fully received by their expected date.
+type SupplierFillRate struct {
+	SupplierID       string  `json:"supplier_id"`
+	SupplierName     string  `json:"supplier_name"`
+	Orders           int     `json:"orders"`
+	OrderedQuantity  int64   `json:"ordered_quantity"`
+	ReceivedQuantity int64   `json:"received_quantity"`
+	ShortQuantity    int64   `json:"short_quantity"`
+	OverQuantity     int64   `json:"over_quantity"`
+	FillRate         float64 `json:"fill_rate"`
+	LineFillRate     float64 `json:"line_fill_rate"`
+	OnTimeRate       float64 `json:"on_time_rate"`
+}
+
+type SupplierFillRateResponse struct {
+	Suppliers []*SupplierFillRate `json:"suppliers"`
+}
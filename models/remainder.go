@@ -1,5 +1,7 @@
 package models
 
+import "market_system/pkg/criteria"
+
 type RemainderPrimaryKey struct {
 	Id string `json:"id"`
 }
@@ -34,13 +36,29 @@ type UpdateRemainder struct {
 }
 
 type GetListRemainderRequest struct {
-	Offset int64  `json:"offset"`
-	Limit  int64  `json:"limit"`
-	Search string `json:"search"`
-	Query  string `json:"query"`
+	Offset   int64             `json:"offset"`
+	Limit    int64             `json:"limit"`
+	Search   string            `json:"search"`
+	Filters  []criteria.Filter `json:"filters"`
+	Sort     []criteria.Sort   `json:"sort"`
+	BranchID string            `json:"branch_id"`
+	ABC      string            `json:"abc"`
+	XYZ      string            `json:"xyz"`
 }
 
 type GetListRemainderResponse struct {
 	Count     int         `json:"count"`
 	Remainder []*Remainder `json:"remainder"`
 }
+
+type DeductRemainderProduct struct {
+	Barcode  string `json:"barcode"`
+	Quantity int    `json:"quantity"`
+}
+
+// DeductRemainder takes sold goods out of a branch stock.
+type DeductRemainder struct {
+	SaleID   string                    `json:"sale_id"`
+	BranchID string                    `json:"branch_id"`
+	Products []*DeductRemainderProduct `json:"products"`
+}
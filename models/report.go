@@ -0,0 +1,252 @@
+package models
+
+// SalesReportRequest selects finished sales by created_at in
+// [FromDate, ToDate) and groups them by the dimensions in GroupBy: period
+// (by Interval), branch, sale_point, cashier, category and brand. With
+// Compare the same totals are computed for the preceding period of equal
+// length.
+type SalesReportRequest struct {
+	FromDate    string   `json:"from_date"`
+	ToDate      string   `json:"to_date"`
+	Interval    string   `json:"interval"`
+	GroupBy     []string `json:"group_by"`
+	BranchID    string   `json:"branch_id"`
+	SalePointID string   `json:"sale_point_id"`
+	CashierID   string   `json:"cashier_id"`
+	CategoryID  string   `json:"category_id"`
+	BrandID     string   `json:"brand_id"`
+	Compare     bool     `json:"compare"`
+}
+
+// SalesMetrics are the figures of a group of sold lines. Cost is what the
+// goods cost when they left stock; AvgBasket is revenue per sale.
+type SalesMetrics struct {
+	Revenue       float64 `json:"revenue"`
+	Cost          float64 `json:"cost"`
+	GrossMargin   float64 `json:"gross_margin"`
+	MarginPercent float64 `json:"margin_percent"`
+	Sales         int64   `json:"sales"`
+	AvgBasket     float64 `json:"avg_basket"`
+	Units         int64   `json:"units"`
+}
+
+// SalesChange is the growth over the previous period in percent; a field is
+// null when the previous value was zero.
+type SalesChange struct {
+	Revenue     *float64 `json:"revenue"`
+	GrossMargin *float64 `json:"gross_margin"`
+	Sales       *float64 `json:"sales"`
+	AvgBasket   *float64 `json:"avg_basket"`
+	Units       *float64 `json:"units"`
+}
+
+// SalesReportRow is one group. Only the dimensions asked for are filled.
+// Previous and Change are set for rows that are not grouped by period.
+type SalesReportRow struct {
+	Period        string        `json:"period,omitempty"`
+	BranchID      string        `json:"branch_id,omitempty"`
+	BranchName    string        `json:"branch_name,omitempty"`
+	SalePointID   string        `json:"sale_point_id,omitempty"`
+	SalePointName string        `json:"sale_point_name,omitempty"`
+	CashierID     string        `json:"cashier_id,omitempty"`
+	CashierName   string        `json:"cashier_name,omitempty"`
+	CategoryID    string        `json:"category_id,omitempty"`
+	CategoryName  string        `json:"category_name,omitempty"`
+	BrandID       string        `json:"brand_id,omitempty"`
+	BrandName     string        `json:"brand_name,omitempty"`
+	Metrics       SalesMetrics  `json:"metrics"`
+	Previous      *SalesMetrics `json:"previous,omitempty"`
+	Change        *SalesChange  `json:"change,omitempty"`
+}
+
+type SalesReportResponse struct {
+	FromDate     string            `json:"from_date"`
+	ToDate       string            `json:"to_date"`
+	PrevFromDate string            `json:"prev_from_date,omitempty"`
+	Rows         []*SalesReportRow `json:"rows"`
+	Total        SalesMetrics      `json:"total"`
+	Previous     *SalesMetrics     `json:"previous,omitempty"`
+	Change       *SalesChange      `json:"change,omitempty"`
+}
+
+// TopProductsRequest ranks products sold in [FromDate, ToDate) by OrderBy
+// (revenue, units or margin), per period when Interval is set.
+type TopProductsRequest struct {
+	FromDate    string `json:"from_date"`
+	ToDate      string `json:"to_date"`
+	Interval    string `json:"interval"`
+	OrderBy     string `json:"order_by"`
+	Limit       int64  `json:"limit"`
+	BranchID    string `json:"branch_id"`
+	SalePointID string `json:"sale_point_id"`
+	CashierID   string `json:"cashier_id"`
+	CategoryID  string `json:"category_id"`
+	BrandID     string `json:"brand_id"`
+}
+
+type TopProduct struct {
+	Period        string  `json:"period,omitempty"`
+	Rank          int64   `json:"rank"`
+	Barcode       string  `json:"barcode"`
+	ProductName   string  `json:"product_name"`
+	Units         int64   `json:"units"`
+	Revenue       float64 `json:"revenue"`
+	Cost          float64 `json:"cost"`
+	GrossMargin   float64 `json:"gross_margin"`
+	MarginPercent float64 `json:"margin_percent"`
+}
+
+type TopProductsResponse struct {
+	Products []*TopProduct `json:"products"`
+}
+
+// HeatmapRequest selects finished sales by created_at in [FromDate, ToDate).
+type HeatmapRequest struct {
+	FromDate    string `json:"from_date"`
+	ToDate      string `json:"to_date"`
+	BranchID    string `json:"branch_id"`
+	SalePointID string `json:"sale_point_id"`
+}
+
+// HeatmapCell is the traffic of one sale point in one hour of one weekday.
+// Weekday runs from 1 (Monday) to 7 (Sunday), Hour from 0 to 23.
+type HeatmapCell struct {
+	BranchID      string  `json:"branch_id"`
+	BranchName    string  `json:"branch_name"`
+	SalePointID   string  `json:"sale_point_id"`
+	SalePointName string  `json:"sale_point_name"`
+	Weekday       int     `json:"weekday"`
+	Hour          int     `json:"hour"`
+	Sales         int64   `json:"sales"`
+	Revenue       float64 `json:"revenue"`
+}
+
+type HeatmapResponse struct {
+	Cells []*HeatmapCell `json:"cells"`
+}
+
+// CashierReportRequest selects shifts opened in [FromDate, ToDate).
+type CashierReportRequest struct {
+	FromDate  string `json:"from_date"`
+	ToDate    string `json:"to_date"`
+	BranchID  string `json:"branch_id"`
+	CashierID string `json:"cashier_id"`
+}
+
+// CashierShift is the performance of a cashier in one shift. Minutes run
+// from open to close, or to now for a shift still open. ExpectedCash is the
+// opening float plus cash taken; CountedCash and CashVariance stay null
+// until the shift is closed with a count.
+type CashierShift struct {
+	ShiftID        string   `json:"shift_id"`
+	BranchID       string   `json:"branch_id"`
+	BranchName     string   `json:"branch_name"`
+	SalePointID    string   `json:"sale_point_id"`
+	SalePointName  string   `json:"sale_point_name"`
+	CashierID      string   `json:"cashier_id"`
+	CashierName    string   `json:"cashier_name"`
+	OpenShift      string   `json:"open_shift"`
+	CloseShift     string   `json:"close_shift"`
+	Minutes        float64  `json:"minutes"`
+	Sales          int64    `json:"sales"`
+	Revenue        float64  `json:"revenue"`
+	Items          int64    `json:"items"`
+	ItemsPerMinute float64  `json:"items_per_minute"`
+	AvgReceipt     float64  `json:"avg_receipt"`
+	Voids          int64    `json:"voids"`
+	Returns        int64    `json:"returns"`
+	OpeningCash    float64  `json:"opening_cash"`
+	ExpectedCash   float64  `json:"expected_cash"`
+	CountedCash    *float64 `json:"counted_cash"`
+	CashVariance   *float64 `json:"cash_variance"`
+}
+
+// CashierPerformance totals the shifts of one cashier. CashVariance sums the
+// shifts closed with a count.
+type CashierPerformance struct {
+	CashierID      string          `json:"cashier_id"`
+	CashierName    string          `json:"cashier_name"`
+	Shifts         []*CashierShift `json:"shifts"`
+	Minutes        float64         `json:"minutes"`
+	Sales          int64           `json:"sales"`
+	Revenue        float64         `json:"revenue"`
+	Items          int64           `json:"items"`
+	ItemsPerMinute float64         `json:"items_per_minute"`
+	AvgReceipt     float64         `json:"avg_receipt"`
+	Voids          int64           `json:"voids"`
+	Returns        int64           `json:"returns"`
+	CashVariance   float64         `json:"cash_variance"`
+}
+
+type CashierReportResponse struct {
+	Cashiers []*CashierPerformance `json:"cashiers"`
+}
+
+// InventoryValuationRequest values stock as of the end of AsOf, or now when
+// AsOf is empty.
+type InventoryValuationRequest struct {
+	AsOf       string `json:"as_of"`
+	BranchID   string `json:"branch_id"`
+	CategoryID string `json:"category_id"`
+}
+
+// InventoryValuation is the stock of one category in one branch, valued at
+// cost and at the retail price in force at the time.
+type InventoryValuation struct {
+	BranchID        string  `json:"branch_id"`
+	BranchName      string  `json:"branch_name"`
+	CategoryID      string  `json:"category_id"`
+	CategoryName    string  `json:"category_name"`
+	Products        int64   `json:"products"`
+	Quantity        int64   `json:"quantity"`
+	CostValue       float64 `json:"cost_value"`
+	RetailValue     float64 `json:"retail_value"`
+	PotentialMargin float64 `json:"potential_margin"`
+	MarginPercent   float64 `json:"margin_percent"`
+}
+
+type InventoryValuationResponse struct {
+	AsOf            string                `json:"as_of"`
+	Rows            []*InventoryValuation `json:"rows"`
+	Quantity        int64                 `json:"quantity"`
+	CostValue       float64               `json:"cost_value"`
+	RetailValue     float64               `json:"retail_value"`
+	PotentialMargin float64               `json:"potential_margin"`
+}
+
+// StockAgingRequest ages the stock on hand by its last receipt. Buckets are
+// the bounds in days; stock received more than DeadDays ago and not sold for
+// DeadDays is dead.
+type StockAgingRequest struct {
+	BranchID   string  `json:"branch_id"`
+	CategoryID string  `json:"category_id"`
+	Buckets    []int64 `json:"buckets"`
+	DeadDays   int64   `json:"dead_days"`
+}
+
+type AgingBucket struct {
+	Label     string  `json:"label"`
+	Products  int64   `json:"products"`
+	Quantity  int64   `json:"quantity"`
+	CostValue float64 `json:"cost_value"`
+}
+
+// DeadStock is a product of a branch that has not sold for a long time.
+// LastSale is empty when it never sold in the branch.
+type DeadStock struct {
+	BranchID    string  `json:"branch_id"`
+	BranchName  string  `json:"branch_name"`
+	Barcode     string  `json:"barcode"`
+	ProductName string  `json:"product_name"`
+	Quantity    int64   `json:"quantity"`
+	CostValue   float64 `json:"cost_value"`
+	LastReceipt string  `json:"last_receipt"`
+	LastSale    string  `json:"last_sale"`
+	DaysUnsold  int64   `json:"days_unsold"`
+}
+
+type StockAgingResponse struct {
+	Buckets       []*AgingBucket `json:"buckets"`
+	DeadStock     []*DeadStock   `json:"dead_stock"`
+	DeadStockCost float64        `json:"dead_stock_cost"`
+}
@@ -1,5 +1,7 @@
 package models
 
+import "market_system/pkg/criteria"
+
 type SalePrimaryKey struct {
 	Id string `json:"id"`
 }
@@ -23,6 +25,7 @@ type Sale struct {
 	EmployeeID  string `json:"employee_id"`
 	Barcode     string `json:"barcode"`
 	Status      string `json:"status"`
+	CustomerID  string `json:"customer_id"`
 	CreatedAt   string `json:"created_at"`
 	UpdatedAt   string `json:"updated_at"`
 }
@@ -37,14 +40,42 @@ type UpdateSale struct {
 	Status      string `json:"status"`
 }
 
+// FinishSale closes a paid sale: the payment is added to the shift
+// transaction, gift cards are redeemed and activated, the goods leave the
+// branch stock and the sale is booked on its customer.
+type FinishSale struct {
+	Id            string   `json:"id"`
+	BranchID      string   `json:"branch_id"`
+	TransactionID string   `json:"transaction_id"`
+	Payment       *Payment `json:"payment"`
+}
+
+// ReturnSale takes a finished sale back: its goods go back into the branch
+// stock and the money paid for them is refunded as store credit.
+type ReturnSale struct {
+	Id      string `json:"-"`
+	Comment string `json:"comment"`
+}
+
+// SaleReturn is a returned sale with the store credit it was refunded on,
+// nil when nothing was paid in money.
+type SaleReturn struct {
+	Sale        *Sale     `json:"sale"`
+	StoreCredit *GiftCard `json:"store_credit"`
+}
+
 type GetListSaleRequest struct {
-	Offset int64  `json:"offset"`
-	Limit  int64  `json:"limit"`
-	Search string `json:"search"`
-	Query  string `json:"query"`
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Search  string            `json:"search"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
+	Cursor  string            `json:"cursor"`
+	Count   bool              `json:"count"`
 }
 
 type GetListSaleResponse struct {
-	Count int     `json:"count"`
-	Sales []*Sale `json:"sales"`
+	Count      int     `json:"count"`
+	Sales      []*Sale `json:"sales"`
+	NextCursor string  `json:"next_cursor,omitempty"`
 }
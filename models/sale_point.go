@@ -1,5 +1,7 @@
 package models
 
+import "market_system/pkg/criteria"
+
 type SalePointPrimaryKey struct {
 	Id string `json:"id"`
 }
@@ -24,10 +26,11 @@ type UpdateSalePoint struct {
 }
 
 type GetListSalePointRequest struct {
-	Offset int64  `json:"offset"`
-	Limit  int64  `json:"limit"`
-	Search string `json:"search"`
-	Query  string `json:"query"`
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Search  string            `json:"search"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
 }
 
 type GetListSalePointResponse struct {
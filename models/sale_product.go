@@ -1,5 +1,7 @@
 package models
 
+import "market_system/pkg/criteria"
+
 type SaleProductPrimaryKey struct {
 	Id string `json:"id"`
 }
@@ -47,13 +49,17 @@ type UpdateSaleProduct struct {
 }
 
 type GetListSaleProductRequest struct {
-	Offset int64  `json:"offset"`
-	Limit  int64  `json:"limit"`
-	Search string `json:"search"`
-	Query  string `json:"query"`
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Search  string            `json:"search"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
+	Cursor  string            `json:"cursor"`
+	Count   bool              `json:"count"`
 }
 
 type GetListSaleProductResponse struct {
-	Count        int             `json:"count"`
+	Count        int            `json:"count"`
 	SaleProducts []*SaleProduct `json:"sale_products"`
+	NextCursor   string         `json:"next_cursor,omitempty"`
 }
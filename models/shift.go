@@ -1,5 +1,7 @@
 package models
 
+import "market_system/pkg/criteria"
+
 type ShiftPrimaryKey struct {
 	Id string `json:"id"`
 }
@@ -14,32 +16,39 @@ type CreateShift struct {
 }
 
 type Shift struct {
-	Id          string `json:"id"`
-	BranchID    string `json:"branch_id"`
-	UserID      string `json:"user_id"`
-	SalePointID string `json:"sale_point_id"`
-	Status      string `json:"status"`
-	OpenShift   string `json:"open_shift"`
-	CloseShift  string `json:"close_shift"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
+	Id          string   `json:"id"`
+	BranchID    string   `json:"branch_id"`
+	UserID      string   `json:"user_id"`
+	SalePointID string   `json:"sale_point_id"`
+	Status      string   `json:"status"`
+	OpenShift   string   `json:"open_shift"`
+	CloseShift  string   `json:"close_shift"`
+	OpeningCash float64  `json:"opening_cash"`
+	CountedCash *float64 `json:"counted_cash"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
 }
 
+// UpdateShift carries the cash in the drawer: OpeningCash is the float put
+// in when the shift opens, CountedCash what the cashier counted at close.
 type UpdateShift struct {
-	Id          string `json:"id"`
-	BranchID    string `json:"branch_id"`
-	UserID      string `json:"user_id"`
-	SalePointID string `json:"sale_point_id"`
-	Status      string `json:"status"`
-	OpenShift   string `json:"open_shift"`
-	CloseShift  string `json:"close_shift"`
+	Id          string   `json:"id"`
+	BranchID    string   `json:"branch_id"`
+	UserID      string   `json:"user_id"`
+	SalePointID string   `json:"sale_point_id"`
+	Status      string   `json:"status"`
+	OpenShift   string   `json:"open_shift"`
+	CloseShift  string   `json:"close_shift"`
+	OpeningCash float64  `json:"opening_cash"`
+	CountedCash *float64 `json:"counted_cash"`
 }
 
 type GetListShiftRequest struct {
-	Offset int64  `json:"offset"`
-	Limit  int64  `json:"limit"`
-	Search string `json:"search"`
-	Query  string `json:"query"`
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Search  string            `json:"search"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
 }
 
 type GetListShiftResponse struct {
@@ -0,0 +1,125 @@
+package models
+
+import "market_system/pkg/criteria"
+
+type StockLevelPrimaryKey struct {
+	Id string `json:"id"`
+}
+
+// CreateStockLevel sets the reorder levels of a product in a branch: it is
+// reordered at MinQuantity and up to MaxQuantity.
+type CreateStockLevel struct {
+	BranchID    string `json:"branch_id"`
+	ProductID   string `json:"product_id"`
+	MinQuantity int64  `json:"min_quantity"`
+	MaxQuantity int64  `json:"max_quantity"`
+}
+
+type StockLevel struct {
+	Id          string `json:"id"`
+	BranchID    string `json:"branch_id"`
+	ProductID   string `json:"product_id"`
+	ProductName string `json:"product_name"`
+	Barcode     string `json:"barcode"`
+	MinQuantity int64  `json:"min_quantity"`
+	MaxQuantity int64  `json:"max_quantity"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+type UpdateStockLevel struct {
+	Id          string `json:"id"`
+	MinQuantity int64  `json:"min_quantity"`
+	MaxQuantity int64  `json:"max_quantity"`
+}
+
+type GetListStockLevelRequest struct {
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
+}
+
+type GetListStockLevelResponse struct {
+	Count       int           `json:"count"`
+	StockLevels []*StockLevel `json:"stock_levels"`
+}
+
+type LowStockRequest struct {
+	BranchID string `json:"branch_id"`
+}
+
+// LowStock is a product whose stock on hand is at or below its minimum.
+// OnOrder is what open purchase orders still have to deliver.
+type LowStock struct {
+	BranchID    string `json:"branch_id"`
+	BranchName  string `json:"branch_name"`
+	ProductID   string `json:"product_id"`
+	ProductName string `json:"product_name"`
+	Barcode     string `json:"barcode"`
+	OnHand      int64  `json:"on_hand"`
+	OnOrder     int64  `json:"on_order"`
+	MinQuantity int64  `json:"min_quantity"`
+	MaxQuantity int64  `json:"max_quantity"`
+}
+
+type LowStockResponse struct {
+	Count    int         `json:"count"`
+	Products []*LowStock `json:"products"`
+}
+
+// ReplenishmentRequest asks for order suggestions of a branch. Sales of the
+// last HistoryDays days give the average daily sales; LeadDays is how long
+// an order takes to arrive. SupplierIDs, when given, limit the suggestions
+// to those suppliers.
+type ReplenishmentRequest struct {
+	BranchID    string   `json:"branch_id"`
+	HistoryDays int64    `json:"history_days"`
+	LeadDays    int64    `json:"lead_days"`
+	SupplierIDs []string `json:"supplier_ids"`
+}
+
+// ReplenishmentLine is a product to order. Price is what it was last bought
+// for.
+type ReplenishmentLine struct {
+	ProductID     string  `json:"product_id"`
+	ProductName   string  `json:"product_name"`
+	Barcode       string  `json:"barcode"`
+	OnHand        int64   `json:"on_hand"`
+	OnOrder       int64   `json:"on_order"`
+	AvgDailySales float64 `json:"avg_daily_sales"`
+	MinQuantity   int64   `json:"min_quantity"`
+	MaxQuantity   int64   `json:"max_quantity"`
+	ReorderPoint  int64   `json:"reorder_point"`
+	Quantity      int64   `json:"quantity"`
+	Price         float64 `json:"price"`
+}
+
+// ReplenishmentSupplier groups the lines by the supplier the product was
+// last bought from; products never bought have an empty SupplierID.
+type ReplenishmentSupplier struct {
+	SupplierID   string               `json:"supplier_id"`
+	SupplierName string               `json:"supplier_name"`
+	Quantity     int64                `json:"quantity"`
+	Cost         float64              `json:"cost"`
+	Lines        []*ReplenishmentLine `json:"lines"`
+}
+
+type ReplenishmentResponse struct {
+	BranchID  string                   `json:"branch_id"`
+	Suppliers []*ReplenishmentSupplier `json:"suppliers"`
+}
+
+// CreateReplenishmentOrders turns the suggestions of a branch into one draft
+// purchase order per supplier.
+type CreateReplenishmentOrders struct {
+	BranchID     string   `json:"branch_id"`
+	HistoryDays  int64    `json:"history_days"`
+	LeadDays     int64    `json:"lead_days"`
+	SupplierIDs  []string `json:"supplier_ids"`
+	ExpectedDate string   `json:"expected_date"`
+}
+
+type CreateReplenishmentOrdersResponse struct {
+	PurchaseOrders []*PurchaseOrder `json:"purchase_orders"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import "market_system/pkg/criteria"
+
+// StockLot is the stock of one lot of a barcode in a branch. ExpiryDate is
+// YYYY-MM-DD, empty for a lot without one.
+type StockLot struct {
+	Id          string  `json:"id"`
+	BranchID    string  `json:"branch_id"`
+	Barcode     string  `json:"barcode"`
+	ProductName string  `json:"product_name"`
+	LotNumber   string  `json:"lot_number"`
+	ExpiryDate  string  `json:"expiry_date"`
+	Quantity    int64   `json:"quantity"`
+	Cost        float64 `json:"cost"`
+	CreatedAt   string  `json:"created_at"`
+	UpdatedAt   string  `json:"updated_at"`
+}
+
+type GetListStockLotRequest struct {
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
+	InStock bool              `json:"in_stock"`
+}
+
+type GetListStockLotResponse struct {
+	Count     int         `json:"count"`
+	StockLots []*StockLot `json:"stock_lots"`
+}
+
+// ExpiringStockLotRequest asks for lots in stock that expire within Days
+// days from today, already expired ones included.
+type ExpiringStockLotRequest struct {
+	BranchID string `json:"branch_id"`
+	Days     int64  `json:"days"`
+}
+
+// ExpiringStockLot is a lot to discount or pull. DaysLeft is negative once
+// the lot has expired.
+type ExpiringStockLot struct {
+	Id          string  `json:"id"`
+	BranchID    string  `json:"branch_id"`
+	BranchName  string  `json:"branch_name"`
+	Barcode     string  `json:"barcode"`
+	ProductName string  `json:"product_name"`
+	LotNumber   string  `json:"lot_number"`
+	ExpiryDate  string  `json:"expiry_date"`
+	DaysLeft    int64   `json:"days_left"`
+	Expired     bool    `json:"expired"`
+	Quantity    int64   `json:"quantity"`
+	Cost        float64 `json:"cost"`
+	TotalCost   float64 `json:"total_cost"`
+}
+
+type ExpiringStockLotResponse struct {
+	Count     int                 `json:"count"`
+	TotalCost float64             `json:"total_cost"`
+	StockLots []*ExpiringStockLot `json:"stock_lots"`
+}
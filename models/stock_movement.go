@@ -0,0 +1,30 @@
+package models
+
+import "market_system/pkg/criteria"
+
+// StockMovement is one change of branch stock. Quantity is positive into the
+// branch and negative out of it; BalanceAfter is the remainder after it.
+type StockMovement struct {
+	Id           string  `json:"id"`
+	BranchID     string  `json:"branch_id"`
+	Barcode      string  `json:"barcode"`
+	ProductName  string  `json:"product_name"`
+	Type         string  `json:"type"`
+	DocumentID   string  `json:"document_id"`
+	Quantity     int64   `json:"quantity"`
+	Cost         float64 `json:"cost"`
+	BalanceAfter int64   `json:"balance_after"`
+	CreatedAt    string  `json:"created_at"`
+}
+
+type GetListStockMovementRequest struct {
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
+}
+
+type GetListStockMovementResponse struct {
+	Count          int              `json:"count"`
+	StockMovements []*StockMovement `json:"stock_movements"`
+}
@@ -1,5 +1,7 @@
 package models
 
+import "market_system/pkg/criteria"
+
 type SupplierPrimaryKey struct {
 	Id string `json:"id"`
 }
@@ -27,10 +29,11 @@ type UpdateSupplier struct {
 }
 
 type GetListSupplierRequest struct {
-	Offset int64  `json:"offset"`
-	Limit  int64  `json:"limit"`
-	Search string `json:"search"`
-	Query  string `json:"query"`
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Search  string            `json:"search"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
 }
 
 type GetListSupplierResponse struct {
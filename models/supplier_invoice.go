@@ -0,0 +1,94 @@
+package models
+
+import (
+	"io"
+	"market_system/pkg/criteria"
+)
+
+type SupplierInvoicePrimaryKey struct {
+	Id string `json:"id"`
+}
+
+// ImportSupplierInvoice is a supplier invoice file to receive into a new draft
+// income. Number and Date stand in for those of a spreadsheet, which has none.
+type ImportSupplierInvoice struct {
+	BranchID   string    `json:"branch_id"`
+	SupplierID string    `json:"supplier_id"`
+	FileName   string    `json:"file_name"`
+	Format     string    `json:"format"`
+	File       io.Reader `json:"-"`
+	Number     string    `json:"number"`
+	Date       string    `json:"date"`
+	CreatedBy  string    `json:"created_by"`
+}
+
+// SupplierInvoice is an imported supplier invoice and the draft income it was
+// received into. Unresolved counts the unmatched and invalid lines, which
+// keep the income from being posted.
+type SupplierInvoice struct {
+	Id           string                 `json:"id"`
+	IncomeID     string                 `json:"income_id"`
+	IncomeStatus string                 `json:"income_status"`
+	SupplierID   string                 `json:"supplier_id"`
+	BranchID     string                 `json:"branch_id"`
+	Format       string                 `json:"format"`
+	FileName     string                 `json:"file_name"`
+	Number       string                 `json:"number"`
+	InvoiceDate  string                 `json:"invoice_date"`
+	SellerTIN    string                 `json:"seller_tin"`
+	SellerName   string                 `json:"seller_name"`
+	TotalAmount  float64                `json:"total_amount"`
+	Lines        int64                  `json:"lines"`
+	Matched      int64                  `json:"matched"`
+	WithIssues   int64                  `json:"with_issues"`
+	Unresolved   int64                  `json:"unresolved"`
+	CreatedBy    string                 `json:"created_by"`
+	CreatedAt    string                 `json:"created_at"`
+	InvoiceLines []*SupplierInvoiceLine `json:"invoice_lines,omitempty"`
+}
+
+// SupplierInvoiceLine is a line of a supplier invoice. Price is the price of
+// one unit with VAT as the file gives it, IncomePrice the one the line is
+// received at.
+type SupplierInvoiceLine struct {
+	Line            int64    `json:"line"`
+	Name            string   `json:"name"`
+	Barcode         string   `json:"barcode"`
+	CatalogCode     string   `json:"catalog_code"`
+	Unit            string   `json:"unit"`
+	Quantity        float64  `json:"quantity"`
+	Price           float64  `json:"price"`
+	VATRate         float64  `json:"vat_rate"`
+	Amount          float64  `json:"amount"`
+	IncomePrice     float64  `json:"income_price"`
+	SupplierCode    string   `json:"supplier_code"`
+	ProductID       string   `json:"product_id"`
+	ProductName     string   `json:"product_name"`
+	MatchedBy       string   `json:"matched_by"`
+	Status          string   `json:"status"`
+	Issues          []string `json:"issues"`
+	IncomeProductID string   `json:"income_product_id"`
+}
+
+// ResolveSupplierInvoiceLine matches a line to ProductID, remembering the
+// match for the supplier when Remember is set, or skips the line.
+type ResolveSupplierInvoiceLine struct {
+	InvoiceID string `json:"-"`
+	Line      int64  `json:"-"`
+	ProductID string `json:"product_id"`
+	Skip      bool   `json:"skip"`
+	Remember  bool   `json:"remember"`
+	CreatedBy string `json:"-"`
+}
+
+type GetListSupplierInvoiceRequest struct {
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
+}
+
+type GetListSupplierInvoiceResponse struct {
+	Count            int                `json:"count"`
+	SupplierInvoices []*SupplierInvoice `json:"supplier_invoices"`
+}
@@ -0,0 +1,72 @@
+package models
+
+type CreateSupplierPayment struct {
+	SupplierID string  `json:"-"`
+	BranchID   string  `json:"branch_id"`
+	Amount     float64 `json:"amount"`
+	Method     string  `json:"method"`
+	DateTime   string  `json:"date_time"`
+	Comment    string  `json:"comment"`
+	CreatedBy  string  `json:"-"`
+}
+
+// SupplierTransaction is one line of the supplier ledger. Balance is what is
+// owed to the supplier after the line.
+type SupplierTransaction struct {
+	Id               string  `json:"id"`
+	SupplierID       string  `json:"supplier_id"`
+	BranchID         string  `json:"branch_id"`
+	Type             string  `json:"type"`
+	Amount           float64 `json:"amount"`
+	Method           string  `json:"method"`
+	IncomeID         string  `json:"income_id"`
+	SupplierReturnID string  `json:"supplier_return_id"`
+	DateTime         string  `json:"date_time"`
+	Comment          string  `json:"comment"`
+	CreatedBy        string  `json:"created_by"`
+	Balance          float64 `json:"balance"`
+	CreatedAt        string  `json:"created_at"`
+}
+
+type SupplierAging struct {
+	SupplierID   string  `json:"supplier_id"`
+	SupplierName string  `json:"supplier_name"`
+	Current      float64 `json:"current"`
+	Days31To60   float64 `json:"days_31_60"`
+	Days61To90   float64 `json:"days_61_90"`
+	Over90       float64 `json:"over_90"`
+	Advance      float64 `json:"advance"`
+	Total        float64 `json:"total"`
+}
+
+type SupplierStatementRequest struct {
+	SupplierID string `json:"supplier_id"`
+	FromDate   string `json:"from_date"`
+	ToDate     string `json:"to_date"`
+}
+
+// SupplierStatement lists the ledger lines in [from_date, to_date) between
+// the balance owed before and after the period. Aging is as of to_date.
+type SupplierStatement struct {
+	SupplierID     string                 `json:"supplier_id"`
+	SupplierName   string                 `json:"supplier_name"`
+	FromDate       string                 `json:"from_date"`
+	ToDate         string                 `json:"to_date"`
+	OpeningBalance float64                `json:"opening_balance"`
+	Invoiced       float64                `json:"invoiced"`
+	Paid           float64                `json:"paid"`
+	Returned       float64                `json:"returned"`
+	ClosingBalance float64                `json:"closing_balance"`
+	Transactions   []*SupplierTransaction `json:"transactions"`
+	Aging          *SupplierAging         `json:"aging"`
+}
+
+type SupplierAgingRequest struct {
+	At         string `json:"at"`
+	SupplierID string `json:"supplier_id"`
+}
+
+type SupplierAgingResponse struct {
+	Suppliers []*SupplierAging `json:"suppliers"`
+	Total     *SupplierAging   `json:"total"`
+}
@@ -0,0 +1,71 @@
+package models
+
+import "market_system/pkg/criteria"
+
+type SupplierReturnPrimaryKey struct {
+	Id string `json:"id"`
+}
+
+// CreateSupplierReturnProduct is a returned line. Cost defaults to the
+// price on the linked income, then to the branch income price.
+type CreateSupplierReturnProduct struct {
+	Barcode  string  `json:"barcode"`
+	Quantity int64   `json:"quantity"`
+	Cost     float64 `json:"cost"`
+}
+
+type CreateSupplierReturn struct {
+	SupplierID string                         `json:"supplier_id"`
+	BranchID   string                         `json:"branch_id"`
+	IncomeID   string                         `json:"income_id"`
+	Comment    string                         `json:"comment"`
+	CreatedBy  string                         `json:"-"`
+	Products   []*CreateSupplierReturnProduct `json:"products"`
+}
+
+type SupplierReturnProduct struct {
+	Id               string  `json:"id"`
+	SupplierReturnID string  `json:"supplier_return_id"`
+	CategoryID       string  `json:"category_id"`
+	ProductName      string  `json:"product_name"`
+	Barcode          string  `json:"barcode"`
+	Quantity         int64   `json:"quantity"`
+	Cost             float64 `json:"cost"`
+	TotalCost        float64 `json:"total_cost"`
+	CreatedAt        string  `json:"created_at"`
+	UpdatedAt        string  `json:"updated_at"`
+}
+
+type SupplierReturn struct {
+	Id         string                   `json:"id"`
+	SupplierID string                   `json:"supplier_id"`
+	BranchID   string                   `json:"branch_id"`
+	IncomeID   string                   `json:"income_id"`
+	Status     string                   `json:"status"`
+	Comment    string                   `json:"comment"`
+	CreatedBy  string                   `json:"created_by"`
+	PostedAt   string                   `json:"posted_at"`
+	TotalCost  float64                  `json:"total_cost"`
+	Products   []*SupplierReturnProduct `json:"products,omitempty"`
+	CreatedAt  string                   `json:"created_at"`
+	UpdatedAt  string                   `json:"updated_at"`
+}
+
+type UpdateSupplierReturn struct {
+	Id       string                         `json:"id"`
+	IncomeID string                         `json:"income_id"`
+	Comment  string                         `json:"comment"`
+	Products []*CreateSupplierReturnProduct `json:"products"`
+}
+
+type GetListSupplierReturnRequest struct {
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
+}
+
+type GetListSupplierReturnResponse struct {
+	Count           int               `json:"count"`
+	SupplierReturns []*SupplierReturn `json:"supplier_returns"`
+}
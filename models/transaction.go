@@ -1,53 +1,68 @@
 package models
 
+import "market_system/pkg/criteria"
+
 type TransactionPrimaryKey struct {
 	Id string `json:"id"`
 }
 
 type CreateTransaction struct {
-	ShiftID     string  `json:"sale_id"`
-	Cash        float64 `json:"cash"`
-	Uzcard      float64 `json:"uzcard"`
-	Payme       float64 `json:"payme"`
-	Click       float64 `json:"click"`
-	Humo        float64 `json:"humo"`
-	Apelsin     float64 `json:"apelsin"`
-	TotalAmount float64 `json:"total_amount"`
+	ShiftID      string  `json:"sale_id"`
+	Cash         float64 `json:"cash"`
+	Uzcard       float64 `json:"uzcard"`
+	Payme        float64 `json:"payme"`
+	Click        float64 `json:"click"`
+	Humo         float64 `json:"humo"`
+	Apelsin      float64 `json:"apelsin"`
+	Points       float64 `json:"points"`
+	GiftCard     float64 `json:"gift_card"`
+	GiftCardSold float64 `json:"gift_card_sold"`
+	TotalAmount  float64 `json:"total_amount"`
 }
 
 type Transaction struct {
-	Id          string  `json:"id"`
-	ShiftID     string  `json:"shift_id"`
-	Cash        float64 `json:"cash"`
-	Uzcard      float64 `json:"uzcard"`
-	Payme       float64 `json:"payme"`
-	Click       float64 `json:"click"`
-	Humo        float64 `json:"humo"`
-	Apelsin     float64 `json:"apelsin"`
-	TotalAmount float64 `json:"total_amount"`
-	CreatedAt   string  `json:"created_at"`
-	UpdatedAt   string  `json:"updated_at"`
+	Id           string  `json:"id"`
+	ShiftID      string  `json:"shift_id"`
+	Cash         float64 `json:"cash"`
+	Uzcard       float64 `json:"uzcard"`
+	Payme        float64 `json:"payme"`
+	Click        float64 `json:"click"`
+	Humo         float64 `json:"humo"`
+	Apelsin      float64 `json:"apelsin"`
+	Points       float64 `json:"points"`
+	GiftCard     float64 `json:"gift_card"`
+	GiftCardSold float64 `json:"gift_card_sold"`
+	TotalAmount  float64 `json:"total_amount"`
+	CreatedAt    string  `json:"created_at"`
+	UpdatedAt    string  `json:"updated_at"`
 }
 
 type UpdateTransaction struct {
-	Id          string  `json:"id"`
-	Cash        float64 `json:"cash"`
-	Uzcard      float64 `json:"uzcard"`
-	Payme       float64 `json:"payme"`
-	Click       float64 `json:"click"`
-	Humo        float64 `json:"humo"`
-	Apelsin     float64 `json:"apelsin"`
-	TotalAmount float64 `json:"total_amount"`
+	Id           string  `json:"id"`
+	Cash         float64 `json:"cash"`
+	Uzcard       float64 `json:"uzcard"`
+	Payme        float64 `json:"payme"`
+	Click        float64 `json:"click"`
+	Humo         float64 `json:"humo"`
+	Apelsin      float64 `json:"apelsin"`
+	Points       float64 `json:"points"`
+	GiftCard     float64 `json:"gift_card"`
+	GiftCardSold float64 `json:"gift_card_sold"`
+	TotalAmount  float64 `json:"total_amount"`
 }
 
 type GetListTransactonRequest struct {
-	Offset int64  `json:"offset"`
-	Limit  int64  `json:"limit"`
-	Search string `json:"search"`
-	Query  string `json:"query"`
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Search  string            `json:"search"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
+	Cursor  string            `json:"cursor"`
+	Count   bool              `json:"count"`
 }
 
 type GetListTransactionResponse struct {
 	Count        int            `json:"count"`
 	Transactions []*Transaction `json:"transactions"`
+	NextCursor   string         `json:"next_cursor,omitempty"`
 }
@@ -0,0 +1,106 @@
+package models
+
+import "market_system/pkg/criteria"
+
+type TransferPrimaryKey struct {
+	Id string `json:"id"`
+}
+
+type CreateTransferProduct struct {
+	Barcode  string `json:"barcode"`
+	Quantity int64  `json:"quantity"`
+}
+
+type CreateTransfer struct {
+	FromBranchID string                   `json:"from_branch_id"`
+	ToBranchID   string                   `json:"to_branch_id"`
+	Comment      string                   `json:"comment"`
+	CreatedBy    string                   `json:"-"`
+	Products     []*CreateTransferProduct `json:"products"`
+}
+
+// TransferProduct is a transferred line. Shortage is what was dispatched but
+// did not arrive; it is set once the transfer is received.
+type TransferProduct struct {
+	Id                 string  `json:"id"`
+	TransferID         string  `json:"transfer_id"`
+	CategoryID         string  `json:"category_id"`
+	ProductName        string  `json:"product_name"`
+	Barcode            string  `json:"barcode"`
+	Quantity           int64   `json:"quantity"`
+	Cost               float64 `json:"cost"`
+	ReceivedQuantity   int64   `json:"received_quantity"`
+	Shortage           int64   `json:"shortage"`
+	DiscrepancyComment string  `json:"discrepancy_comment"`
+	CreatedAt          string  `json:"created_at"`
+	UpdatedAt          string  `json:"updated_at"`
+}
+
+type Transfer struct {
+	Id           string             `json:"id"`
+	FromBranchID string             `json:"from_branch_id"`
+	ToBranchID   string             `json:"to_branch_id"`
+	Status       string             `json:"status"`
+	Comment      string             `json:"comment"`
+	CreatedBy    string             `json:"created_by"`
+	DispatchedAt string             `json:"dispatched_at"`
+	ReceivedBy   string             `json:"received_by"`
+	ReceivedAt   string             `json:"received_at"`
+	Products     []*TransferProduct `json:"products,omitempty"`
+	CreatedAt    string             `json:"created_at"`
+	UpdatedAt    string             `json:"updated_at"`
+}
+
+type UpdateTransfer struct {
+	Id         string                   `json:"id"`
+	ToBranchID string                   `json:"to_branch_id"`
+	Comment    string                   `json:"comment"`
+	Products   []*CreateTransferProduct `json:"products"`
+}
+
+type GetListTransferRequest struct {
+	Offset   int64             `json:"offset"`
+	Limit    int64             `json:"limit"`
+	Filters  []criteria.Filter `json:"filters"`
+	Sort     []criteria.Sort   `json:"sort"`
+	BranchID string            `json:"branch_id"`
+}
+
+type GetListTransferResponse struct {
+	Count     int         `json:"count"`
+	Transfers []*Transfer `json:"transfers"`
+}
+
+type ReceiveTransferProduct struct {
+	TransferProductID  string `json:"transfer_product_id"`
+	ReceivedQuantity   int64  `json:"received_quantity"`
+	DiscrepancyComment string `json:"discrepancy_comment"`
+}
+
+// ReceiveTransfer accepts an in-transit transfer at the destination. Lines
+// left out are taken as received in full.
+type ReceiveTransfer struct {
+	Id         string                    `json:"-"`
+	ReceivedBy string                    `json:"-"`
+	Products   []*ReceiveTransferProduct `json:"products"`
+}
+
+type InTransitRequest struct {
+	BranchID string `json:"branch_id"`
+}
+
+// InTransit is what sits between branches for one branch: goods it sent that
+// have not arrived yet and goods on their way to it.
+type InTransit struct {
+	BranchID         string  `json:"branch_id"`
+	BranchName       string  `json:"branch_name"`
+	OutgoingQuantity int64   `json:"outgoing_quantity"`
+	OutgoingCost     float64 `json:"outgoing_cost"`
+	IncomingQuantity int64   `json:"incoming_quantity"`
+	IncomingCost     float64 `json:"incoming_cost"`
+	Transfers        int     `json:"transfers"`
+}
+
+type InTransitResponse struct {
+	Branches []*InTransit `json:"branches"`
+}
@@ -1,5 +1,7 @@
 package models
 
+import "market_system/pkg/criteria"
+
 type UserPrimaryKey struct {
 	Id string `json:"id"`
 }
@@ -36,9 +38,11 @@ type UpdateUser struct {
 }
 
 type GetListUserRequest struct {
-	Offset int64  `json:"offset"`
-	Limit  int64  `json:"limit"`
-	Search string `json:"search"`
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Search  string            `json:"search"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
 }
 
 type GetListUserResponse struct {
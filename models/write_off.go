@@ -0,0 +1,117 @@
+package models
+
+import "market_system/pkg/criteria"
+
+type WriteOffPrimaryKey struct {
+	Id string `json:"id"`
+}
+
+type CreateWriteOffProduct struct {
+	Barcode  string `json:"barcode"`
+	Quantity int64  `json:"quantity"`
+}
+
+// CreateWriteOff is a draft write-off of a branch. Reason is one of expired,
+// damaged, theft or internal_use.
+type CreateWriteOff struct {
+	BranchID  string                   `json:"branch_id"`
+	Reason    string                   `json:"reason"`
+	Comment   string                   `json:"comment"`
+	CreatedBy string                   `json:"-"`
+	Products  []*CreateWriteOffProduct `json:"products"`
+}
+
+type WriteOffProduct struct {
+	Id          string  `json:"id"`
+	WriteOffID  string  `json:"write_off_id"`
+	CategoryID  string  `json:"category_id"`
+	ProductName string  `json:"product_name"`
+	Barcode     string  `json:"barcode"`
+	Quantity    int64   `json:"quantity"`
+	Cost        float64 `json:"cost"`
+	TotalCost   float64 `json:"total_cost"`
+	CreatedAt   string  `json:"created_at"`
+	UpdatedAt   string  `json:"updated_at"`
+}
+
+type WriteOff struct {
+	Id         string             `json:"id"`
+	BranchID   string             `json:"branch_id"`
+	Reason     string             `json:"reason"`
+	Status     string             `json:"status"`
+	Comment    string             `json:"comment"`
+	CreatedBy  string             `json:"created_by"`
+	ReviewedBy string             `json:"reviewed_by"`
+	ReviewedAt string             `json:"reviewed_at"`
+	PostedAt   string             `json:"posted_at"`
+	TotalCost  float64            `json:"total_cost"`
+	Products   []*WriteOffProduct `json:"products,omitempty"`
+	CreatedAt  string             `json:"created_at"`
+	UpdatedAt  string             `json:"updated_at"`
+}
+
+type UpdateWriteOff struct {
+	Id       string                   `json:"id"`
+	Reason   string                   `json:"reason"`
+	Comment  string                   `json:"comment"`
+	Products []*CreateWriteOffProduct `json:"products"`
+}
+
+type GetListWriteOffRequest struct {
+	Offset  int64             `json:"offset"`
+	Limit   int64             `json:"limit"`
+	Filters []criteria.Filter `json:"filters"`
+	Sort    []criteria.Sort   `json:"sort"`
+}
+
+type GetListWriteOffResponse struct {
+	Count     int         `json:"count"`
+	WriteOffs []*WriteOff `json:"write_offs"`
+}
+
+// PostWriteOff submits a draft. A write-off worth more than
+// ApprovalThreshold goes to pending_approval instead of leaving stock.
+type PostWriteOff struct {
+	Id                string  `json:"-"`
+	ApprovalThreshold float64 `json:"-"`
+}
+
+// ReviewWriteOff approves or rejects a write-off pending approval.
+type ReviewWriteOff struct {
+	Id         string `json:"-"`
+	ReviewedBy string `json:"-"`
+}
+
+// ShrinkageReportRequest selects posted write-offs by posted_at in
+// [FromDate, ToDate). Interval is day, week or month.
+type ShrinkageReportRequest struct {
+	BranchID string `json:"branch_id"`
+	FromDate string `json:"from_date"`
+	ToDate   string `json:"to_date"`
+	Interval string `json:"interval"`
+}
+
+// Shrinkage is what was written off in one period for one branch, category
+// and reason.
+type Shrinkage struct {
+	Period       string  `json:"period"`
+	BranchID     string  `json:"branch_id"`
+	BranchName   string  `json:"branch_name"`
+	CategoryID   string  `json:"category_id"`
+	CategoryName string  `json:"category_name"`
+	Reason       string  `json:"reason"`
+	Quantity     int64   `json:"quantity"`
+	Cost         float64 `json:"cost"`
+}
+
+type ShrinkageByReason struct {
+	Reason   string  `json:"reason"`
+	Quantity int64   `json:"quantity"`
+	Cost     float64 `json:"cost"`
+}
+
+type ShrinkageReportResponse struct {
+	Rows      []*Shrinkage         `json:"rows"`
+	ByReason  []*ShrinkageByReason `json:"by_reason"`
+	TotalCost float64              `json:"total_cost"`
+}
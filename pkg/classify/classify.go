@@ -0,0 +1,138 @@
+// Package classify sorts the products of a branch into ABC and XYZ classes.
+//
+// ABC ranks products by revenue: the products that together bring the first
+// 80% of revenue are A, the next 15% B and the rest C. The product that
+// crosses a threshold still belongs to the higher class, so a branch with any
+// revenue always has an A product.
+//
+// XYZ measures how steady demand is by the coefficient of variation (standard
+// deviation over mean) of the units sold per week: up to 0.5 is X, up to 1.0
+// Y and above that, or with no demand at all, Z.
+package classify
+
+import (
+	"math"
+	"sort"
+)
+
+// Thresholds are the cumulative revenue shares closing classes A and B and
+// the coefficients of variation closing classes X and Y.
+type Thresholds struct {
+	A float64
+	B float64
+	X float64
+	Y float64
+}
+
+var DefaultThresholds = Thresholds{A: 0.8, B: 0.95, X: 0.5, Y: 1.0}
+
+// Item is one product of a branch: its revenue and units sold per week, the
+// weeks without sales included as zero.
+type Item struct {
+	Revenue float64
+	Demand  []float64
+}
+
+// Class is the classification of an item. Share and CumulativeShare are
+// fractions of the branch revenue.
+type Class struct {
+	Share           float64
+	CumulativeShare float64
+	ABC             string
+	Mean            float64
+	CV              float64
+	XYZ             string
+}
+
+// Classify returns the class of every item, in the order of items.
+func Classify(items []Item, t Thresholds) []Class {
+
+	var (
+		classes = make([]Class, len(items))
+		order   = make([]int, len(items))
+		total   float64
+	)
+
+	for i, item := range items {
+		order[i] = i
+		total += item.Revenue
+		classes[i].Mean, classes[i].CV = Variation(item.Demand)
+		classes[i].XYZ = XYZ(classes[i].Mean, classes[i].CV, t)
+	}
+
+	sort.SliceStable(order, func(i, j int) bool {
+		return items[order[i]].Revenue > items[order[j]].Revenue
+	})
+
+	var cumulative float64
+	for _, i := range order {
+		var class = &classes[i]
+		if total > 0 {
+			class.Share = round(items[i].Revenue / total)
+		}
+
+		switch {
+		case total <= 0 || items[i].Revenue <= 0:
+			class.ABC = "C"
+		case cumulative < t.A:
+			class.ABC = "A"
+		case cumulative < t.B:
+			class.ABC = "B"
+		default:
+			class.ABC = "C"
+		}
+
+		if total > 0 {
+			cumulative += items[i].Revenue / total
+		}
+		class.CumulativeShare = round(cumulative)
+	}
+
+	return classes
+}
+
+// Variation is the mean and the coefficient of variation of demand. The
+// coefficient is zero when there was no demand.
+func Variation(demand []float64) (float64, float64) {
+
+	if len(demand) == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, units := range demand {
+		sum += units
+	}
+	mean := sum / float64(len(demand))
+
+	if mean == 0 {
+		return 0, 0
+	}
+
+	var squares float64
+	for _, units := range demand {
+		squares += (units - mean) * (units - mean)
+	}
+
+	return round(mean), round(math.Sqrt(squares/float64(len(demand))) / mean)
+}
+
+// XYZ is the class of a demand with the given mean and coefficient of
+// variation.
+func XYZ(mean, cv float64, t Thresholds) string {
+
+	switch {
+	case mean <= 0:
+		return "Z"
+	case cv <= t.X:
+		return "X"
+	case cv <= t.Y:
+		return "Y"
+	default:
+		return "Z"
+	}
+}
+
+func round(x float64) float64 {
+	return math.Round(x*10000) / 10000
+}
@@ -0,0 +1,70 @@
+package classify
+
+import "testing"
+
+func TestClassifyABC(t *testing.T) {
+
+	items := []Item{
+		{Revenue: 50},
+		{Revenue: 5},
+		{Revenue: 30},
+		{Revenue: 10},
+		{Revenue: 5},
+		{Revenue: 0},
+	}
+
+	want := []string{"A", "B", "A", "B", "C", "C"}
+
+	classes := Classify(items, DefaultThresholds)
+	for i, class := range classes {
+		if class.ABC != want[i] {
+			t.Errorf("item %d: ABC = %s, want %s", i, class.ABC, want[i])
+		}
+	}
+
+	if classes[0].Share != 0.5 || classes[2].CumulativeShare != 0.8 {
+		t.Errorf("shares = %v, %v, want 0.5, 0.8", classes[0].Share, classes[2].CumulativeShare)
+	}
+}
+
+func TestClassifySingleProduct(t *testing.T) {
+
+	classes := Classify([]Item{{Revenue: 10}}, DefaultThresholds)
+	if classes[0].ABC != "A" {
+		t.Errorf("only product with revenue: ABC = %s, want A", classes[0].ABC)
+	}
+
+	classes = Classify([]Item{{Revenue: 0}, {Revenue: 0}}, DefaultThresholds)
+	if classes[0].ABC != "C" || classes[1].ABC != "C" {
+		t.Errorf("no revenue: ABC = %s, %s, want C, C", classes[0].ABC, classes[1].ABC)
+	}
+}
+
+func TestVariation(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		demand []float64
+		mean   float64
+		cv     float64
+		xyz    string
+	}{
+		{"steady", []float64{10, 10, 10, 10}, 10, 0, "X"},
+		{"moderate", []float64{5, 15, 5, 15}, 10, 0.5, "X"},
+		{"variable", []float64{0, 20, 2, 18}, 10, 0.9055, "Y"},
+		{"erratic", []float64{0, 0, 0, 40}, 10, 1.7321, "Z"},
+		{"no demand", []float64{0, 0, 0}, 0, 0, "Z"},
+		{"no history", nil, 0, 0, "Z"},
+	}
+
+	for _, test := range tests {
+		mean, cv := Variation(test.demand)
+		if mean != test.mean || cv != test.cv {
+			t.Errorf("%s: Variation() = %v, %v, want %v, %v", test.name, mean, cv, test.mean, test.cv)
+		}
+
+		if got := XYZ(mean, cv, DefaultThresholds); got != test.xyz {
+			t.Errorf("%s: XYZ() = %s, want %s", test.name, got, test.xyz)
+		}
+	}
+}
@@ -0,0 +1,432 @@
+// Package criteria compiles typed list filters, search and sorting into
+// parameterized SQL, and reads them from the query string of a list request.
+//
+// Every list has a Schema naming the fields it can be filtered and sorted by
+// and the columns behind them. Field names and operators are checked against
+// the schema and values are passed as query arguments, so nothing a client
+// sends is ever written into the SQL text.
+package criteria
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"market_system/pkg/helpers"
+)
+
+// Operator compares a field to a filter value.
+type Operator string
+
+const (
+	Eq       Operator = "eq"
+	Ne       Operator = "ne"
+	Lt       Operator = "lt"
+	Lte      Operator = "lte"
+	Gt       Operator = "gt"
+	Gte      Operator = "gte"
+	In       Operator = "in"
+	Between  Operator = "between"
+	Contains Operator = "contains"
+	IsNull   Operator = "is_null"
+	NotNull  Operator = "not_null"
+	Under    Operator = "under"
+)
+
+var comparisons = map[Operator]string{
+	Eq:  "=",
+	Ne:  "<>",
+	Lt:  "<",
+	Lte: "<=",
+	Gt:  ">",
+	Gte: ">=",
+}
+
+// Operators lists the operators a filter can use.
+var Operators = []Operator{Eq, Ne, Lt, Lte, Gt, Gte, In, Between, Contains, IsNull, NotNull, Under}
+
+// Filter selects the rows whose field compares to Value. Value is a slice
+// for In, a slice of the lower and upper bound for Between and is not used by
+// IsNull and NotNull. Under selects the rows at a node of a hierarchy or below
+// it, for the fields that have one.
+type Filter struct {
+	Field    string      `json:"field"`
+	Operator Operator    `json:"operator"`
+	Value    interface{} `json:"value,omitempty"`
+}
+
+// Equal is the filter selecting the rows whose field equals value.
+func Equal(field string, value interface{}) Filter {
+	return Filter{Field: field, Operator: Eq, Value: value}
+}
+
+// Sort orders a list by a field.
+type Sort struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc"`
+}
+
+// Type is the type of a field, which decides how filter values are read.
+type Type int
+
+const (
+	Text Type = iota
+	UUID
+	Number
+	Time
+	Bool
+)
+
+// Field is a field of a list and the column or expression behind it. Tree is
+// the condition selecting the rows at a node of the hierarchy the field refers
+// to or below it, with %s for the node, for the fields that have one.
+type Field struct {
+	Column string
+	Type   Type
+	Tree   string
+}
+
+// Schema lists what a list can be filtered, searched and sorted by. Search
+// names the fields the search text is matched against and Sort is the order
+// used when a request gives none. Keyset names the field a large list can be
+// paged by cursor on, together with its id column; it must not be null.
+type Schema struct {
+	Fields map[string]Field
+	Search []string
+	Sort   []Sort
+	Keyset string
+}
+
+// DefaultLimit is the page size of a request that gives no limit.
+const DefaultLimit = 10
+
+// Error is a filter or sort a schema does not allow.
+type Error struct {
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Query collects the conditions of a list query and their arguments.
+type Query struct {
+	schema     *Schema
+	conditions []string
+	args       []interface{}
+	keyset     *keyset
+}
+
+// Query starts a query on the list.
+func (s *Schema) Query() *Query {
+	return &Query{schema: s}
+}
+
+// Arg adds a query argument and returns its placeholder.
+func (q *Query) Arg(value interface{}) string {
+	q.args = append(q.args, value)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+// Args returns the query arguments in placeholder order.
+func (q *Query) Args() []interface{} {
+	return q.args
+}
+
+// And adds a condition written by the caller. Values in it must be passed
+// through Arg.
+func (q *Query) And(condition string) {
+	q.conditions = append(q.conditions, condition)
+}
+
+// SQL returns the WHERE clause of the query.
+func (q *Query) SQL() string {
+
+	var where = " WHERE TRUE"
+	for _, condition := range q.conditions {
+		where += " AND " + condition
+	}
+
+	if q.keyset != nil && len(q.keyset.after) > 0 {
+		where += " AND " + q.keyset.after
+	}
+
+	return where
+}
+
+// Filter adds the filters to the query.
+func (q *Query) Filter(filters ...Filter) error {
+
+	for _, filter := range filters {
+		field, ok := q.schema.Fields[filter.Field]
+		if !ok {
+			return &Error{Message: fmt.Sprintf("can not filter by %q", filter.Field)}
+		}
+
+		condition, err := q.condition(field, filter)
+		if err != nil {
+			return err
+		}
+
+		q.And(condition)
+	}
+
+	return nil
+}
+
+func (q *Query) condition(field Field, filter Filter) (string, error) {
+
+	if _, ok := comparisons[filter.Operator]; ok {
+		return q.compare(field, filter.Field, filter.Operator, filter.Value)
+	}
+
+	switch filter.Operator {
+	case In:
+		values, ok := list(filter.Value)
+		if !ok || len(values) == 0 {
+			return "", &Error{Message: fmt.Sprintf("%s: in needs a list of values", filter.Field)}
+		}
+
+		var placeholders = make([]string, 0, len(values))
+		for _, v := range values {
+			value, err := convert(filter.Field, field.Type, v)
+			if err != nil {
+				return "", err
+			}
+			placeholders = append(placeholders, q.Arg(value))
+		}
+		return field.Column + " IN (" + strings.Join(placeholders, ", ") + ")", nil
+
+	case Between:
+		values, ok := list(filter.Value)
+		if !ok || len(values) != 2 {
+			return "", &Error{Message: fmt.Sprintf("%s: between needs a lower and an upper bound", filter.Field)}
+		}
+
+		lower, err := q.compare(field, filter.Field, Gte, values[0])
+		if err != nil {
+			return "", err
+		}
+		upper, err := q.compare(field, filter.Field, Lte, values[1])
+		if err != nil {
+			return "", err
+		}
+		return lower + " AND " + upper, nil
+
+	case Contains:
+		text, ok := filter.Value.(string)
+		if !ok {
+			return "", &Error{Message: fmt.Sprintf("%s: contains needs a text value", filter.Field)}
+		}
+		return contains(field) + " ILIKE " + q.Arg(pattern(text)), nil
+
+	case IsNull:
+		return field.Column + " IS NULL", nil
+
+	case NotNull:
+		return field.Column + " IS NOT NULL", nil
+
+	case Under:
+		if len(field.Tree) == 0 {
+			return "", &Error{Message: fmt.Sprintf("%s: under needs a field with a hierarchy", filter.Field)}
+		}
+		value, err := convert(filter.Field, field.Type, filter.Value)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf(field.Tree, q.Arg(value)), nil
+	}
+
+	return "", &Error{Message: fmt.Sprintf("%s: unknown operator %q", filter.Field, filter.Operator)}
+}
+
+// compare returns the condition comparing a field to value. A date given
+// without a time stands for the whole day, so lte and gt compare to the start
+// of the next day.
+func (q *Query) compare(field Field, name string, op Operator, value interface{}) (string, error) {
+
+	converted, err := convert(name, field.Type, value)
+	if err != nil {
+		return "", err
+	}
+
+	if day, ok := value.(string); ok && field.Type == Time && len(day) == len("2006-01-02") {
+		if t, err := time.Parse("2006-01-02", day); err == nil {
+			switch op {
+			case Lte:
+				op, converted = Lt, t.AddDate(0, 0, 1).Format(timestampLayout)
+			case Gt:
+				op, converted = Gte, t.AddDate(0, 0, 1).Format(timestampLayout)
+			}
+		}
+	}
+
+	return field.Column + " " + comparisons[op] + " " + q.Arg(converted), nil
+}
+
+// list returns the values of an In or Between filter.
+func list(value interface{}) ([]interface{}, bool) {
+
+	switch v := value.(type) {
+	case []interface{}:
+		return v, true
+	case []string:
+		var values = make([]interface{}, 0, len(v))
+		for _, s := range v {
+			values = append(values, s)
+		}
+		return values, true
+	}
+
+	return nil, false
+}
+
+// Search adds a condition matching the text anywhere in the search fields of
+// the list. Empty text matches everything.
+func (q *Query) Search(text string) {
+
+	if len(text) == 0 || len(q.schema.Search) == 0 {
+		return
+	}
+
+	var (
+		arg     = q.Arg(pattern(text))
+		matches = make([]string, 0, len(q.schema.Search))
+	)
+	for _, name := range q.schema.Search {
+		matches = append(matches, contains(q.schema.Fields[name])+" ILIKE "+arg)
+	}
+
+	q.And("(" + strings.Join(matches, " OR ") + ")")
+}
+
+// OrderBy returns the ORDER BY clause for sorts, or for the default order of
+// the list when there are none.
+func (q *Query) OrderBy(sorts []Sort) (string, error) {
+
+	if len(sorts) == 0 {
+		sorts = q.schema.Sort
+	}
+	if len(sorts) == 0 {
+		return "", nil
+	}
+
+	var order = make([]string, 0, len(sorts))
+	for _, sort := range sorts {
+		field, ok := q.schema.Fields[sort.Field]
+		if !ok {
+			return "", &Error{Message: fmt.Sprintf("can not sort by %q", sort.Field)}
+		}
+
+		if sort.Desc {
+			order = append(order, field.Column+" DESC")
+		} else {
+			order = append(order, field.Column+" ASC")
+		}
+	}
+
+	return " ORDER BY " + strings.Join(order, ", "), nil
+}
+
+// Page returns the OFFSET and LIMIT clauses of a page.
+func Page(offset, limit int64) string {
+
+	if offset < 0 {
+		offset = 0
+	}
+
+	return fmt.Sprintf(" OFFSET %d LIMIT %d", offset, Limit(limit))
+}
+
+// contains returns the column of a field as text for ILIKE.
+func contains(field Field) string {
+
+	if field.Type == Text {
+		return field.Column
+	}
+
+	return field.Column + "::text"
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// pattern returns the ILIKE pattern matching text anywhere, with the
+// wildcards in text matched literally.
+func pattern(text string) string {
+	return "%" + likeEscaper.Replace(text) + "%"
+}
+
+// timeLayouts are the layouts a time value can be given in.
+var timeLayouts = []string{"2006-01-02", "2006-01-02 15:04:05", time.RFC3339}
+
+// timestampLayout is how time values are passed to postgres, to the
+// microsecond it stores.
+const timestampLayout = "2006-01-02 15:04:05.999999"
+
+// convert checks a filter value against the type of its field. Values may be
+// given as text, as they come in a query string.
+func convert(name string, typ Type, value interface{}) (interface{}, error) {
+
+	var invalid = func(what string) error {
+		return &Error{Message: fmt.Sprintf("%s must be %s", name, what)}
+	}
+
+	switch typ {
+	case Text:
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+		return nil, invalid("text")
+
+	case UUID:
+		if s, ok := value.(string); ok && helpers.IsValidUUID(s) {
+			return s, nil
+		}
+		return nil, invalid("a uuid")
+
+	case Number:
+		switch v := value.(type) {
+		case int:
+			return int64(v), nil
+		case int64:
+			return v, nil
+		case float64:
+			return v, nil
+		case string:
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return n, nil
+			}
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return f, nil
+			}
+		}
+		return nil, invalid("a number")
+
+	case Time:
+		switch v := value.(type) {
+		case time.Time:
+			return v, nil
+		case string:
+			for _, layout := range timeLayouts {
+				if t, err := time.Parse(layout, v); err == nil {
+					return t.Format(timestampLayout), nil
+				}
+			}
+		}
+		return nil, invalid("a date (YYYY-MM-DD) or time")
+
+	case Bool:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b, nil
+			}
+		}
+		return nil, invalid("true or false")
+	}
+
+	return nil, invalid("a known type")
+}
@@ -0,0 +1,361 @@
+package criteria
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var saleSchema = &Schema{
+	Fields: map[string]Field{
+		"id":         {Column: "id", Type: UUID},
+		"sale_id":    {Column: "sale_id", Type: Text},
+		"branch_id":  {Column: "branch_id", Type: UUID},
+		"status":     {Column: "status", Type: Text},
+		"total":      {Column: "total_amount", Type: Number},
+		"created_at": {Column: "created_at", Type: Time},
+	},
+	Search: []string{"sale_id", "branch_id"},
+	Sort:   []Sort{{Field: "created_at", Desc: true}},
+}
+
+var payloads = []string{
+	"'; DROP TABLE sale; --",
+	"x' OR '1'='1",
+	"1) OR (1=1",
+	`\'; SELECT pg_sleep(10); --`,
+}
+
+func TestFilter(t *testing.T) {
+
+	q := saleSchema.Query()
+	err := q.Filter(
+		Equal("branch_id", "8b0d4f3e-3f0a-4c55-9a43-5d4a2b1c0e11"),
+		Filter{Field: "status", Operator: In, Value: []string{"success", "cancel"}},
+		Filter{Field: "total", Operator: Gte, Value: "1000.5"},
+		Filter{Field: "created_at", Operator: Lt, Value: "2024-02-01"},
+		Filter{Field: "sale_id", Operator: Contains, Value: "50%_off"},
+		Filter{Field: "id", Operator: NotNull},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := " WHERE TRUE AND branch_id = $1 AND status IN ($2, $3) AND total_amount >= $4" +
+		" AND created_at < $5 AND sale_id ILIKE $6 AND id IS NOT NULL"
+	if q.SQL() != want {
+		t.Errorf("SQL() = %q, want %q", q.SQL(), want)
+	}
+
+	args := []interface{}{
+		"8b0d4f3e-3f0a-4c55-9a43-5d4a2b1c0e11", "success", "cancel", 1000.5,
+		"2024-02-01 00:00:00", `%50\%\_off%`,
+	}
+	if !reflect.DeepEqual(q.Args(), args) {
+		t.Errorf("Args() = %#v, want %#v", q.Args(), args)
+	}
+}
+
+func TestUnder(t *testing.T) {
+
+	var schema = &Schema{
+		Fields: map[string]Field{
+			"category_id": {Column: "category_id", Type: UUID, Tree: "category_id IN (SELECT id FROM category_subtree(%s))"},
+		},
+	}
+
+	q := schema.Query()
+	err := q.Filter(Filter{Field: "category_id", Operator: Under, Value: "8b0d4f3e-3f0a-4c55-9a43-5d4a2b1c0e11"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := " WHERE TRUE AND category_id IN (SELECT id FROM category_subtree($1))"; q.SQL() != want {
+		t.Errorf("SQL() = %q, want %q", q.SQL(), want)
+	}
+
+	err = schema.Query().Filter(Filter{Field: "category_id", Operator: Under, Value: payloads[0]})
+	var criteriaErr *Error
+	if !errors.As(err, &criteriaErr) {
+		t.Errorf("Filter() error = %v, want *Error", err)
+	}
+}
+
+func TestInjectionIsInert(t *testing.T) {
+
+	for _, payload := range payloads {
+		q := saleSchema.Query()
+		err := q.Filter(
+			Equal("sale_id", payload),
+			Filter{Field: "status", Operator: In, Value: []interface{}{payload}},
+			Filter{Field: "sale_id", Operator: Contains, Value: payload},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q.Search(payload)
+
+		sql := q.SQL()
+		want := " WHERE TRUE AND sale_id = $1 AND status IN ($2) AND sale_id ILIKE $3" +
+			" AND (sale_id ILIKE $4 OR branch_id::text ILIKE $4)"
+		if sql != want {
+			t.Errorf("payload %q: SQL() = %q, want %q", payload, sql, want)
+		}
+
+		if strings.Contains(sql, payload) {
+			t.Errorf("payload %q is in the sql", payload)
+		}
+
+		like := "%" + likeEscaper.Replace(payload) + "%"
+		args := []interface{}{payload, payload, like, like}
+		if !reflect.DeepEqual(q.Args(), args) {
+			t.Errorf("payload %q: Args() = %#v, want %#v", payload, q.Args(), args)
+		}
+	}
+}
+
+func TestRejectsUnknownFieldsAndValues(t *testing.T) {
+
+	tests := []struct {
+		name   string
+		filter Filter
+	}{
+		{"unknown field", Equal("sale_id = sale_id; DROP TABLE sale; --", "x")},
+		{"column name", Equal("total_amount", 1)},
+		{"unknown operator", Filter{Field: "sale_id", Operator: "= 1 OR 1=1 --", Value: "x"}},
+		{"uuid payload", Equal("branch_id", "'; DROP TABLE sale; --")},
+		{"number payload", Filter{Field: "total", Operator: Gt, Value: "1; DROP TABLE sale"}},
+		{"time payload", Filter{Field: "created_at", Operator: Gt, Value: "2024-01-01'; --"}},
+		{"empty in", Filter{Field: "status", Operator: In, Value: []string{}}},
+		{"contains number", Filter{Field: "sale_id", Operator: Contains, Value: 1}},
+		{"under without hierarchy", Filter{Field: "branch_id", Operator: Under, Value: "8b0d4f3e-3f0a-4c55-9a43-5d4a2b1c0e11"}},
+	}
+
+	for _, tt := range tests {
+		q := saleSchema.Query()
+		err := q.Filter(tt.filter)
+
+		var criteriaErr *Error
+		if !errors.As(err, &criteriaErr) {
+			t.Errorf("%s: Filter() error = %v, want *Error", tt.name, err)
+		}
+		if len(q.Args()) != 0 || q.SQL() != " WHERE TRUE" {
+			t.Errorf("%s: query = %q %v, want it unchanged", tt.name, q.SQL(), q.Args())
+		}
+	}
+}
+
+func TestOrderBy(t *testing.T) {
+
+	q := saleSchema.Query()
+
+	order, err := q.OrderBy(nil)
+	if err != nil || order != " ORDER BY created_at DESC" {
+		t.Errorf("OrderBy(nil) = %q, %v", order, err)
+	}
+
+	order, err = q.OrderBy([]Sort{{Field: "total"}, {Field: "id", Desc: true}})
+	if err != nil || order != " ORDER BY total_amount ASC, id DESC" {
+		t.Errorf("OrderBy() = %q, %v", order, err)
+	}
+
+	for _, payload := range append(payloads, "created_at; DROP TABLE sale") {
+		if _, err := q.OrderBy([]Sort{{Field: payload}}); err == nil {
+			t.Errorf("OrderBy(%q) error = nil", payload)
+		}
+	}
+}
+
+func TestPage(t *testing.T) {
+
+	tests := []struct {
+		offset, limit int64
+		want          string
+	}{
+		{0, 0, " OFFSET 0 LIMIT 10"},
+		{20, 5, " OFFSET 20 LIMIT 5"},
+		{-1, -1, " OFFSET 0 LIMIT 10"},
+	}
+
+	for _, tt := range tests {
+		if got := Page(tt.offset, tt.limit); got != tt.want {
+			t.Errorf("Page(%d, %d) = %q, want %q", tt.offset, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestDateBounds(t *testing.T) {
+
+	q := saleSchema.Query()
+	err := q.Filter(
+		Filter{Field: "created_at", Operator: Between, Value: []string{"2024-01-01", "2024-01-31"}},
+		Filter{Field: "created_at", Operator: Gt, Value: "2023-12-31"},
+		Filter{Field: "created_at", Operator: Lte, Value: "2024-01-31 18:00:00"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := " WHERE TRUE AND created_at >= $1 AND created_at < $2 AND created_at >= $3 AND created_at <= $4"
+	if q.SQL() != want {
+		t.Errorf("SQL() = %q, want %q", q.SQL(), want)
+	}
+
+	args := []interface{}{"2024-01-01 00:00:00", "2024-02-01 00:00:00", "2024-01-01 00:00:00", "2024-01-31 18:00:00"}
+	if !reflect.DeepEqual(q.Args(), args) {
+		t.Errorf("Args() = %#v, want %#v", q.Args(), args)
+	}
+
+	if err := saleSchema.Query().Filter(Filter{Field: "created_at", Operator: Between, Value: []string{"2024-01-01"}}); err == nil {
+		t.Error("between with one bound: error = nil")
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+
+	values, err := url.ParseQuery("status[in]=success,cancel&created_at[between]=2024-01-01,2024-01-31" +
+		"&branch_id[eq]=b1&sort=-created_at,+total,id&fields=id,status&limit=5&search=x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list, err := ParseQuery(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &List{
+		Filters: []Filter{
+			{Field: "branch_id", Operator: Eq, Value: "b1"},
+			{Field: "created_at", Operator: Between, Value: []string{"2024-01-01", "2024-01-31"}},
+			{Field: "status", Operator: In, Value: []string{"success", "cancel"}},
+		},
+		Sort:   []Sort{{Field: "created_at", Desc: true}, {Field: "total"}, {Field: "id"}},
+		Fields: []string{"id", "status"},
+	}
+	if !reflect.DeepEqual(list, want) {
+		t.Errorf("ParseQuery() = %+v, want %+v", list, want)
+	}
+
+	if _, err := ParseQuery(url.Values{"status[like]": {"x"}}); err == nil {
+		t.Error("unknown operator: error = nil")
+	}
+
+	// a filter on a field the schema does not have fails when it is applied
+	list, _ = ParseQuery(url.Values{"password[eq]": {"x"}})
+	if err := saleSchema.Query().Filter(list.Filters...); err == nil {
+		t.Error("unknown field: error = nil")
+	}
+}
+
+func TestSelect(t *testing.T) {
+
+	type item struct {
+		ID     string  `json:"id"`
+		Status string  `json:"status"`
+		Total  float64 `json:"total_amount"`
+	}
+	type response struct {
+		Count int     `json:"count"`
+		Items []*item `json:"items"`
+	}
+
+	resp := &response{Count: 2, Items: []*item{{"1", "success", 10}, {"2", "cancel", 20}}}
+
+	selected, err := Select(resp, []string{"id", "total_amount"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := json.Marshal(selected)
+	want := `{"count":2,"items":[{"id":"1","total_amount":10},{"id":"2","total_amount":20}]}`
+	if string(body) != want {
+		t.Errorf("Select() = %s, want %s", body, want)
+	}
+
+	if same, _ := Select(resp, nil); same != resp {
+		t.Error("Select() without fields changed the response")
+	}
+
+	if _, err := Select(resp, []string{"id", "count"}); err == nil {
+		t.Error("unknown field: error = nil")
+	}
+}
+
+func TestCursor(t *testing.T) {
+
+	schema := *saleSchema
+	schema.Keyset = "created_at"
+
+	// first page: newest first, one row more than the page
+	q := schema.Query()
+	if err := q.Filter(Equal("status", "success")); err != nil {
+		t.Fatal(err)
+	}
+
+	order, err := q.Cursor(nil, "")
+	if err != nil || order != " ORDER BY created_at DESC, id DESC" {
+		t.Fatalf("Cursor() = %q, %v", order, err)
+	}
+	if page := q.Page(0, 20); page != " OFFSET 0 LIMIT 21" {
+		t.Errorf("Page() = %q", page)
+	}
+	if q.More(20, 20) || !q.More(21, 20) {
+		t.Error("More() is wrong about the extra row")
+	}
+
+	next := q.NextCursor("2024-01-31T18:00:00.123456Z", "8b0d4f3e-3f0a-4c55-9a43-5d4a2b1c0e11")
+
+	// next page continues after the last row and ignores the offset
+	q = schema.Query()
+	if err := q.Filter(Equal("status", "success")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := q.Cursor(nil, next); err != nil {
+		t.Fatal(err)
+	}
+
+	want := " WHERE TRUE AND status = $1 AND (created_at, id) < ($2, $3)"
+	if q.SQL() != want {
+		t.Errorf("SQL() = %q, want %q", q.SQL(), want)
+	}
+	args := []interface{}{"success", "2024-01-31 18:00:00.123456", "8b0d4f3e-3f0a-4c55-9a43-5d4a2b1c0e11"}
+	if !reflect.DeepEqual(q.Args(), args) {
+		t.Errorf("Args() = %#v, want %#v", q.Args(), args)
+	}
+	if page := q.Page(40, 20); page != " OFFSET 0 LIMIT 21" {
+		t.Errorf("Page() = %q", page)
+	}
+
+	total, totalArgs := q.Total()
+	if total != " WHERE TRUE AND status = $1" || !reflect.DeepEqual(totalArgs, args[:1]) {
+		t.Errorf("Total() = %q, %#v", total, totalArgs)
+	}
+	if q.SQL() != want {
+		t.Errorf("SQL() after Total() = %q", q.SQL())
+	}
+
+	// a cursor is only good for the sort it was made for
+	if _, err := schema.Query().Cursor([]Sort{{Field: "created_at"}}, next); err == nil {
+		t.Error("cursor for another direction: error = nil")
+	}
+	if _, err := schema.Query().Cursor([]Sort{{Field: "total"}}, next); err == nil {
+		t.Error("cursor for another sort: error = nil")
+	}
+
+	// other sorts page by offset
+	q = schema.Query()
+	order, err = q.Cursor([]Sort{{Field: "total"}}, "")
+	if err != nil || order != " ORDER BY total_amount ASC" || q.Page(40, 20) != " OFFSET 40 LIMIT 20" || q.NextCursor("1", "2") != "" {
+		t.Errorf("Cursor(total) = %q, %v", order, err)
+	}
+
+	for _, payload := range append(payloads, "eyJmIjoiY3JlYXRlZF9hdCIsImQiOnRydWUsImsiOiInOyBEUk9QIFRBQkxFIHNhbGU7IC0tIiwiaWQiOiIxIn0") {
+		if _, err := schema.Query().Cursor(nil, payload); err == nil {
+			t.Errorf("Cursor(%q) error = nil", payload)
+		}
+	}
+}
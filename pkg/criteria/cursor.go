@@ -0,0 +1,152 @@
+package criteria
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+
+	"market_system/pkg/helpers"
+)
+
+// keyset is the cursor paging of a query: rows are ordered by the keyset
+// field and id, and a page continues after the last row of the one before.
+type keyset struct {
+	name     string
+	column   string
+	idColumn string
+	desc     bool
+	// after is the condition continuing after the cursor, empty on the
+	// first page; args is the number of arguments before its own
+	after string
+	args  int
+}
+
+// cursor is what a next_cursor encodes: the keyset field and direction it
+// was made for and the keyset value and id of the last row of its page.
+type cursor struct {
+	Field string `json:"f"`
+	Desc  bool   `json:"d"`
+	Key   string `json:"k"`
+	ID    string `json:"id"`
+}
+
+// Cursor returns the ORDER BY clause for sorts, like OrderBy, and when the
+// list is sorted by its keyset field alone makes the query cursor paged,
+// continuing after cursor unless it is empty. A cursor on any other sort is
+// an error.
+func (q *Query) Cursor(sorts []Sort, after string) (string, error) {
+
+	if len(sorts) == 0 {
+		sorts = q.schema.Sort
+	}
+
+	var name = q.schema.Keyset
+	if len(name) == 0 || len(sorts) != 1 || sorts[0].Field != name {
+		if len(after) > 0 {
+			return "", &Error{Message: "cursor paging needs the list sorted by " + name + " alone"}
+		}
+		return q.OrderBy(sorts)
+	}
+
+	var k = &keyset{
+		name:     name,
+		column:   q.schema.Fields[name].Column,
+		idColumn: q.schema.Fields["id"].Column,
+		desc:     sorts[0].Desc,
+		args:     len(q.args),
+	}
+
+	if len(after) > 0 {
+		var c cursor
+		body, err := base64.RawURLEncoding.DecodeString(after)
+		if err == nil {
+			err = json.Unmarshal(body, &c)
+		}
+		if err != nil || c.Field != name || !helpers.IsValidUUID(c.ID) {
+			return "", &Error{Message: "invalid cursor"}
+		}
+		if c.Desc != k.desc {
+			return "", &Error{Message: "cursor was made for another sort"}
+		}
+
+		key, err := convert(name, q.schema.Fields[name].Type, c.Key)
+		if err != nil {
+			return "", &Error{Message: "invalid cursor"}
+		}
+
+		var op = ">"
+		if k.desc {
+			op = "<"
+		}
+		k.after = fmt.Sprintf("(%s, %s) %s (%s, %s)", k.column, k.idColumn, op, q.Arg(key), q.Arg(c.ID))
+	}
+
+	q.keyset = k
+
+	var direction = " ASC"
+	if k.desc {
+		direction = " DESC"
+	}
+
+	return " ORDER BY " + k.column + direction + ", " + k.idColumn + direction, nil
+}
+
+// Page returns the OFFSET and LIMIT clauses of a page of the query. A cursor
+// paged query fetches one row more than the page, which tells More there is
+// a next page, and skips by offset only when it has no cursor.
+func (q *Query) Page(offset, limit int64) string {
+
+	if q.keyset == nil {
+		return Page(offset, limit)
+	}
+
+	if len(q.keyset.after) > 0 {
+		offset = 0
+	}
+
+	return Page(offset, Limit(limit)+1)
+}
+
+// More reports whether a cursor paged query fetched more than a page of
+// limit rows, in which case the extra last row is to be dropped and the page
+// has a next cursor.
+func (q *Query) More(rows int, limit int64) bool {
+	return q.keyset != nil && int64(rows) > Limit(limit)
+}
+
+// NextCursor returns the cursor of the page after the row with keyset value
+// key and id.
+func (q *Query) NextCursor(key, id string) string {
+
+	if q.keyset == nil {
+		return ""
+	}
+
+	body, _ := json.Marshal(cursor{Field: q.keyset.name, Desc: q.keyset.desc, Key: key, ID: id})
+	return base64.RawURLEncoding.EncodeToString(body)
+}
+
+// Total returns the WHERE clause and arguments of the query without its
+// cursor, for counting the rows of all pages.
+func (q *Query) Total() (string, []interface{}) {
+
+	if q.keyset == nil {
+		return q.SQL(), q.args
+	}
+
+	var k = q.keyset
+	q.keyset = nil
+	defer func() { q.keyset = k }()
+
+	return q.SQL(), q.args[:k.args]
+}
+
+// Limit returns the page size of a request, DefaultLimit when it gives none.
+func Limit(limit int64) int64 {
+
+	if limit <= 0 {
+		return DefaultLimit
+	}
+
+	return limit
+}
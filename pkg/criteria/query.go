@@ -0,0 +1,210 @@
+package criteria
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// List is what a list request asks for besides its page: the filters, the
+// sort and the fields of each item to return, all of them empty when it
+// gives none.
+type List struct {
+	Filters []Filter
+	Sort    []Sort
+	Fields  []string
+}
+
+var filterKey = regexp.MustCompile(`^([a-z_]+)\[([a-z_]+)\]$`)
+
+// ParseQuery reads a list request from a query string:
+//
+//	status[in]=success,cancel&created_at[between]=2024-01-01,2024-01-31
+//	sort=-created_at,name
+//	fields=id,name,created_at
+//
+// A filter is field[operator]=value. In and between take comma separated
+// values. Sort names fields, comma separated, descending when prefixed with
+// a minus. Other keys are left to the handler. Whether the fields exist is
+// up to the schema of the list.
+func ParseQuery(values url.Values) (*List, error) {
+
+	var (
+		list = &List{}
+		keys = make([]string, 0, len(values))
+	)
+	for key := range values {
+		keys = append(keys, key)
+	}
+	// sorted so the same request always gives the same sql
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		match := filterKey.FindStringSubmatch(key)
+		if match == nil {
+			continue
+		}
+
+		var op = Operator(match[2])
+		if !knownOperator(op) {
+			return nil, &Error{Message: fmt.Sprintf("%s: unknown operator %q", match[1], match[2])}
+		}
+
+		for _, value := range values[key] {
+			var filter = Filter{Field: match[1], Operator: op, Value: value}
+			if op == In || op == Between {
+				filter.Value = strings.Split(value, ",")
+			}
+			list.Filters = append(list.Filters, filter)
+		}
+	}
+
+	for _, field := range splitList(values.Get("sort")) {
+		if strings.HasPrefix(field, "-") {
+			list.Sort = append(list.Sort, Sort{Field: field[1:], Desc: true})
+		} else {
+			list.Sort = append(list.Sort, Sort{Field: strings.TrimPrefix(field, "+")})
+		}
+	}
+
+	list.Fields = splitList(values.Get("fields"))
+
+	return list, nil
+}
+
+func knownOperator(op Operator) bool {
+	for _, known := range Operators {
+		if op == known {
+			return true
+		}
+	}
+	return false
+}
+
+func splitList(value string) []string {
+
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// Select returns a list response with the items of its lists cut down to
+// fields, named by their json keys. The lists are the fields of the response
+// holding slices of structs; the other fields, such as the count, are kept.
+// No fields return the response as it is.
+func Select(resp interface{}, fields []string) (interface{}, error) {
+
+	if len(fields) == 0 {
+		return resp, nil
+	}
+
+	var (
+		lists, keys = itemKeys(reflect.TypeOf(resp))
+		keep        = map[string]bool{}
+	)
+	for _, field := range fields {
+		if !keys[field] {
+			return nil, &Error{Message: fmt.Sprintf("unknown field %q", field)}
+		}
+		keep[field] = true
+	}
+
+	body, err := json.Marshal(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected map[string]json.RawMessage
+	if err = json.Unmarshal(body, &selected); err != nil {
+		return nil, err
+	}
+
+	for _, key := range lists {
+		var items []map[string]json.RawMessage
+		if err = json.Unmarshal(selected[key], &items); err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			for name := range item {
+				if !keep[name] {
+					delete(item, name)
+				}
+			}
+		}
+
+		if selected[key], err = json.Marshal(items); err != nil {
+			return nil, err
+		}
+	}
+
+	return selected, nil
+}
+
+// itemKeys returns the json keys of the lists of a response and the json
+// keys of their items.
+func itemKeys(t reflect.Type) ([]string, map[string]bool) {
+
+	var (
+		lists []string
+		keys  = map[string]bool{}
+	)
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, keys
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		var field = t.Field(i)
+		key, ok := jsonKey(field)
+		if !ok || field.Type.Kind() != reflect.Slice {
+			continue
+		}
+
+		var item = field.Type.Elem()
+		for item.Kind() == reflect.Ptr {
+			item = item.Elem()
+		}
+		if item.Kind() != reflect.Struct {
+			continue
+		}
+
+		lists = append(lists, key)
+		for j := 0; j < item.NumField(); j++ {
+			if name, ok := jsonKey(item.Field(j)); ok {
+				keys[name] = true
+			}
+		}
+	}
+
+	return lists, keys
+}
+
+func jsonKey(field reflect.StructField) (string, bool) {
+
+	if !field.IsExported() {
+		return "", false
+	}
+
+	var name = strings.Split(field.Tag.Get("json"), ",")[0]
+	switch name {
+	case "-":
+		return "", false
+	case "":
+		return field.Name, true
+	}
+
+	return name, true
+}
@@ -0,0 +1,377 @@
+// Package einvoice reads supplier invoices and checks their lines against the
+// catalog before they are received.
+//
+// An invoice is a spreadsheet (CSV or XLSX) with a header row naming its
+// columns, or an electronic invoice (factura) in the JSON or XML layout of
+// the Soliq e-invoicing service that Didox and the other operators exchange.
+package einvoice
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+
+	"market_system/config"
+	"market_system/pkg/spreadsheet"
+)
+
+// Formats lists the file formats an invoice can be read from.
+var Formats = []string{"csv", "xlsx", "json", "xml"}
+
+// MaxLines caps the lines of an invoice.
+const MaxLines = 5000
+
+// maxQuantity and maxAmount are the largest quantity and amount a line is
+// stored with.
+const (
+	maxQuantity = 1e9
+	maxAmount   = 1e11
+)
+
+// Invoice is a supplier invoice. Date is YYYY-MM-DD when the file gives it.
+type Invoice struct {
+	Number     string
+	Date       string
+	SellerTIN  string
+	SellerName string
+	Lines      []*Line
+}
+
+// Line is a line of an invoice. Price is the price of one unit with VAT and
+// Amount the line total with VAT, as the file gives them. Errors lists what
+// keeps the line from being received.
+type Line struct {
+	Line        int
+	Name        string
+	Barcode     string
+	CatalogCode string
+	Unit        string
+	Quantity    float64
+	Price       float64
+	VATRate     float64
+	Amount      float64
+	Errors      []string
+}
+
+// FileError is an invoice that can not be read at all.
+type FileError struct {
+	Message string
+}
+
+func (e *FileError) Error() string {
+	return e.Message
+}
+
+// Parse reads an invoice in format.
+func Parse(r io.Reader, format string) (*Invoice, error) {
+
+	var (
+		invoice *Invoice
+		err     error
+	)
+
+	switch format {
+	case "csv", "xlsx":
+		invoice, err = parseSpreadsheet(r, format)
+	case "json":
+		invoice, err = parseJSON(r)
+	case "xml":
+		invoice, err = parseXML(r)
+	default:
+		return nil, &FileError{Message: "format must be one of " + strings.Join(Formats, ", ")}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(invoice.Lines) == 0 {
+		return nil, &FileError{Message: "invoice has no lines"}
+	}
+
+	if len(invoice.Lines) > MaxLines {
+		return nil, &FileError{Message: fmt.Sprintf("invoice has more than %d lines", MaxLines)}
+	}
+
+	for _, line := range invoice.Lines {
+		Validate(line)
+	}
+
+	return invoice, nil
+}
+
+// Validate records on a line what keeps it from being received.
+func Validate(line *Line) {
+
+	line.Name = strings.TrimSpace(line.Name)
+	line.Barcode = strings.TrimSpace(line.Barcode)
+
+	if len(line.Name) == 0 && len(line.Barcode) == 0 {
+		line.Errors = append(line.Errors, "name or barcode is required")
+	}
+
+	if len(line.Barcode) > 50 {
+		line.Errors = append(line.Errors, "barcode is longer than 50 characters")
+	}
+
+	switch {
+	case hasError(line, "quantity"):
+	case line.Quantity <= 0:
+		line.Errors = append(line.Errors, "quantity must be positive")
+	case line.Quantity > maxQuantity:
+		line.Errors = append(line.Errors, "quantity is too large")
+	}
+
+	switch {
+	case hasError(line, "price") || hasError(line, "amount"):
+	case line.Price < 0 || line.Amount < 0:
+		line.Errors = append(line.Errors, "price can not be negative")
+	case line.Price > maxAmount || line.Amount > maxAmount:
+		line.Errors = append(line.Errors, "price is too large")
+	case line.Price == 0 && line.Amount == 0:
+		line.Errors = append(line.Errors, "price is required")
+	}
+}
+
+// hasError reports whether the line already has an error about column.
+func hasError(line *Line, column string) bool {
+	for _, err := range line.Errors {
+		if strings.HasPrefix(err, column+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+// IncomePrice is the price of one unit the line is received at: the line
+// total over the quantity when the file gives a total, rounded to cents.
+func (l *Line) IncomePrice() float64 {
+
+	if l.Amount > 0 && l.Quantity > 0 {
+		return round(l.Amount / l.Quantity)
+	}
+
+	return round(l.Price)
+}
+
+// SupplierCode is what a supplier item is remembered by: its barcode, or its
+// name when it has none.
+func (l *Line) SupplierCode() string {
+
+	if len(l.Barcode) > 0 {
+		return l.Barcode
+	}
+
+	return "name:" + NormalizeName(l.Name)
+}
+
+// NormalizeName lower-cases a product name and collapses its spaces.
+func NormalizeName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// Match is the catalog product a line was matched to. By is how it was
+// matched; LastPrice is the price the product was last received at, zero
+// when it never was.
+type Match struct {
+	ProductID string
+	Title     string
+	By        string
+	LastPrice float64
+}
+
+// Issues lists what the receiving clerk should check on a line before the
+// income is posted. match is nil for a line that matched no product.
+func Issues(line *Line, match *Match, priceTolerance float64) []string {
+
+	var issues = append([]string{}, line.Errors...)
+
+	if match == nil {
+		if len(line.Errors) == 0 {
+			issues = append(issues, "not matched to a product")
+		}
+		return issues
+	}
+
+	switch match.By {
+	case config.SupplierInvoiceMatchBarcode:
+		if len(line.Name) > 0 && !similarNames(line.Name, match.Title) {
+			issues = append(issues, "name differs from the catalog: "+match.Title)
+		}
+	case config.SupplierInvoiceMatchName:
+		issues = append(issues, "matched by name only")
+	}
+
+	if line.Quantity != math.Trunc(line.Quantity) {
+		issues = append(issues, "quantity is not a whole number")
+	}
+
+	if line.Price > 0 && line.Amount > 0 {
+		var expected = line.Price * line.Quantity
+		if math.Abs(expected-line.Amount) > math.Max(1, line.Amount*0.01) {
+			issues = append(issues, fmt.Sprintf("amount %.2f differs from quantity × price %.2f", line.Amount, expected))
+		}
+	}
+
+	if match.LastPrice > 0 {
+		var change = (line.IncomePrice() - match.LastPrice) / match.LastPrice
+		if math.Abs(change) > priceTolerance {
+			var direction = "above"
+			if change < 0 {
+				direction = "below"
+			}
+			issues = append(issues, fmt.Sprintf("price is %.0f%% %s the last income price %.2f", math.Abs(change)*100, direction, match.LastPrice))
+		}
+	}
+
+	return issues
+}
+
+// similarNames reports whether two product names are the same up to case and
+// spacing, or one contains the other.
+func similarNames(a, b string) bool {
+	a, b = NormalizeName(a), NormalizeName(b)
+	return strings.Contains(a, b) || strings.Contains(b, a)
+}
+
+// columns maps the names a spreadsheet header may give a column to the
+// column.
+var columns = map[string]string{
+	"name":         "name",
+	"product":      "name",
+	"title":        "name",
+	"наименование": "name",
+	"товар":        "name",
+	"название":     "name",
+	"nomi":         "name",
+	"mahsulot":     "name",
+	"barcode":      "barcode",
+	"штрихкод":     "barcode",
+	"штрих-код":    "barcode",
+	"shtrix-kod":   "barcode",
+	"shtrixkod":    "barcode",
+	"code":         "catalog_code",
+	"catalog_code": "catalog_code",
+	"ikpu":         "catalog_code",
+	"икпу":         "catalog_code",
+	"mxik":         "catalog_code",
+	"мхик":         "catalog_code",
+	"код":          "catalog_code",
+	"unit":         "unit",
+	"ед. изм.":     "unit",
+	"ед.изм.":      "unit",
+	"o'lchov":      "unit",
+	"quantity":     "quantity",
+	"qty":          "quantity",
+	"количество":   "quantity",
+	"кол-во":       "quantity",
+	"miqdor":       "quantity",
+	"soni":         "quantity",
+	"price":        "price",
+	"цена":         "price",
+	"narx":         "price",
+	"amount":       "amount",
+	"total":        "amount",
+	"сумма":        "amount",
+	"стоимость":    "amount",
+	"summa":        "amount",
+	"vat":          "vat_rate",
+	"vat_rate":     "vat_rate",
+	"ндс":          "vat_rate",
+	"ндс, %":       "vat_rate",
+	"qqs":          "vat_rate",
+}
+
+// headerRows is how many rows above the table a spreadsheet may have.
+const headerRows = 20
+
+func parseSpreadsheet(r io.Reader, format string) (*Invoice, error) {
+
+	records, err := spreadsheet.Read(r, format)
+	if err != nil {
+		return nil, &FileError{Message: err.Error()}
+	}
+
+	// invoices often have the supplier details above the table, so the
+	// header is the first row naming the name and quantity columns
+	var (
+		header = -1
+		index  map[string]int
+	)
+	for i := 0; i < len(records) && i < headerRows && header < 0; i++ {
+		index = map[string]int{}
+		for j, name := range records[i] {
+			if column, ok := columns[strings.ToLower(strings.TrimSpace(name))]; ok {
+				if _, ok := index[column]; !ok {
+					index[column] = j
+				}
+			}
+		}
+
+		_, name := index["name"]
+		_, quantity := index["quantity"]
+		if name && quantity {
+			header = i
+		}
+	}
+	if header < 0 {
+		return nil, &FileError{Message: "no header row with name and quantity columns"}
+	}
+
+	var invoice = &Invoice{}
+	for i, record := range records[header+1:] {
+		var cell = func(column string) string {
+			if j, ok := index[column]; ok && j < len(record) {
+				return strings.TrimSpace(record[j])
+			}
+			return ""
+		}
+
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		var line = &Line{
+			Line:        header + i + 2,
+			Name:        cell("name"),
+			Barcode:     cell("barcode"),
+			CatalogCode: cell("catalog_code"),
+			Unit:        cell("unit"),
+		}
+
+		// a totals row closes the table
+		if len(line.Barcode) == 0 && len(cell("quantity")) == 0 && len(invoice.Lines) > 0 {
+			break
+		}
+
+		line.Quantity = number(line, "quantity", cell("quantity"))
+		line.Price = number(line, "price", cell("price"))
+		line.Amount = number(line, "amount", cell("amount"))
+		line.VATRate = number(line, "vat rate", strings.TrimSuffix(cell("vat_rate"), "%"))
+
+		invoice.Lines = append(invoice.Lines, line)
+	}
+
+	return invoice, nil
+}
+
+// number reads the cell of a column, recording an error on the line when it
+// is not a number.
+func number(line *Line, column, cell string) float64 {
+
+	if len(cell) == 0 {
+		return 0
+	}
+
+	value, err := spreadsheet.ParseNumber(cell)
+	if err != nil {
+		line.Errors = append(line.Errors, column+" is not a number")
+		return 0
+	}
+
+	return value
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
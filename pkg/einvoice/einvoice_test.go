@@ -0,0 +1,124 @@
+package einvoice
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"market_system/config"
+)
+
+func TestParseSpreadsheet(t *testing.T) {
+
+	file := "ООО Поставщик;;;;\n" +
+		"Счёт-фактура № 15;;;;\n" +
+		"Наименование;Штрихкод;Кол-во;Цена;Сумма\n" +
+		"Молоко 1л;4780001;10;12 000;120 000\n" +
+		"Кефир;;2,5;8000;\n" +
+		";;abc;;\n" +
+		"Итого;;;;140 000\n" +
+		"Подпись;;;;\n"
+
+	invoice, err := Parse(strings.NewReader(file), "csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(invoice.Lines) != 3 {
+		t.Fatalf("Parse() lines = %d, want 3", len(invoice.Lines))
+	}
+
+	milk := invoice.Lines[0]
+	if milk.Line != 4 || milk.Quantity != 10 || milk.IncomePrice() != 12000 || len(milk.Errors) != 0 {
+		t.Errorf("line 1 = %+v", milk)
+	}
+
+	kefir := invoice.Lines[1]
+	if kefir.SupplierCode() != "name:кефир" || kefir.IncomePrice() != 8000 {
+		t.Errorf("line 2 = %+v", kefir)
+	}
+
+	want := []string{"quantity is not a number", "name or barcode is required", "price is required"}
+	if !reflect.DeepEqual(invoice.Lines[2].Errors, want) {
+		t.Errorf("line 3 errors = %v, want %v", invoice.Lines[2].Errors, want)
+	}
+}
+
+func TestParseFactura(t *testing.T) {
+
+	doc := `{
+		"FacturaDoc": {"FacturaNo": "A-7", "FacturaDate": "15.01.2024"},
+		"SellerTin": "301234567",
+		"Seller": {"Name": "Supplier LLC"},
+		"ProductList": {"Products": [
+			{"OrdNo": 1, "Name": "Tea", "Barcode": "4780002", "CatalogCode": "0123", "Count": "4", "Summa": 1000, "VatRate": 12, "VatSum": 480, "DeliverySum": 4000, "DeliverySumWithVat": "4480"}
+		]}
+	}`
+
+	quoted, _ := json.Marshal(doc)
+
+	for name, body := range map[string]string{
+		"plain":   doc,
+		"wrapped": `{"data": {"json": ` + doc + `}}`,
+		"string":  `{"document": ` + string(quoted) + `}`,
+	} {
+		invoice, err := Parse(strings.NewReader(body), "json")
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		if invoice.Number != "A-7" || invoice.Date != "2024-01-15" || invoice.SellerTIN != "301234567" {
+			t.Errorf("%s: invoice = %+v", name, invoice)
+		}
+
+		line := invoice.Lines[0]
+		if line.Price != 1120 || line.Amount != 4480 || line.IncomePrice() != 1120 || len(line.Errors) != 0 {
+			t.Errorf("%s: line = %+v", name, line)
+		}
+	}
+
+	xmlDoc := `<Factura>
+		<FacturaDoc><FacturaNo>A-8</FacturaNo><FacturaDate>2024-02-01</FacturaDate></FacturaDoc>
+		<ProductList><Products>
+			<Product><OrdNo>1</OrdNo><Name>Tea</Name><Count>2</Count><Summa>1000</Summa><DeliverySum>2000</DeliverySum></Product>
+		</Products></ProductList>
+	</Factura>`
+
+	invoice, err := Parse(strings.NewReader(xmlDoc), "xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if invoice.Number != "A-8" || len(invoice.Lines) != 1 || invoice.Lines[0].IncomePrice() != 1000 {
+		t.Errorf("xml invoice = %+v", invoice)
+	}
+
+	if _, err = Parse(strings.NewReader(`{"data": {}}`), "json"); err == nil {
+		t.Errorf("Parse() of a document without ProductList should fail")
+	}
+}
+
+func TestIssues(t *testing.T) {
+
+	line := &Line{Name: "Milk 1l", Barcode: "1", Quantity: 2.5, Price: 1000, Amount: 3000}
+
+	got := Issues(line, &Match{Title: "Juice", By: config.SupplierInvoiceMatchBarcode, LastPrice: 1000}, 0.1)
+	want := []string{
+		"name differs from the catalog: Juice",
+		"quantity is not a whole number",
+		"amount 3000.00 differs from quantity × price 2500.00",
+		"price is 20% above the last income price 1000.00",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Issues() = %v, want %v", got, want)
+	}
+
+	line = &Line{Name: "Milk  1L", Quantity: 2, Price: 1000}
+	if got = Issues(line, &Match{Title: "milk 1l", By: config.SupplierInvoiceMatchBarcode, LastPrice: 950}, 0.1); len(got) != 0 {
+		t.Errorf("Issues() = %v, want none", got)
+	}
+
+	if got = Issues(line, nil, 0.1); !reflect.DeepEqual(got, []string{"not matched to a product"}) {
+		t.Errorf("Issues() = %v", got)
+	}
+}
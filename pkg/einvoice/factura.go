@@ -0,0 +1,184 @@
+package einvoice
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+	"time"
+
+	"market_system/pkg/spreadsheet"
+)
+
+// factura is the e-invoice document of the Soliq service. Operators such as
+// Didox hand it out as JSON, sometimes wrapped in their own envelope, or as
+// XML with the same element names.
+type factura struct {
+	FacturaDoc struct {
+		FacturaNo   string `json:"FacturaNo" xml:"FacturaNo"`
+		FacturaDate string `json:"FacturaDate" xml:"FacturaDate"`
+	} `json:"FacturaDoc" xml:"FacturaDoc"`
+	SellerTin string `json:"SellerTin" xml:"SellerTin"`
+	Seller    struct {
+		Name string `json:"Name" xml:"Name"`
+	} `json:"Seller" xml:"Seller"`
+	ProductList struct {
+		Products []facturaProduct `json:"Products" xml:"Products>Product"`
+	} `json:"ProductList" xml:"ProductList"`
+}
+
+// facturaProduct is a line of a factura. Summa is the unit price and
+// DeliverySum the line total, both without VAT.
+type facturaProduct struct {
+	OrdNo              flexNumber `json:"OrdNo" xml:"OrdNo"`
+	Name               string     `json:"Name" xml:"Name"`
+	CatalogCode        string     `json:"CatalogCode" xml:"CatalogCode"`
+	Barcode            string     `json:"Barcode" xml:"Barcode"`
+	PackageName        string     `json:"PackageName" xml:"PackageName"`
+	Count              flexNumber `json:"Count" xml:"Count"`
+	Summa              flexNumber `json:"Summa" xml:"Summa"`
+	DeliverySum        flexNumber `json:"DeliverySum" xml:"DeliverySum"`
+	VatRate            flexNumber `json:"VatRate" xml:"VatRate"`
+	VatSum             flexNumber `json:"VatSum" xml:"VatSum"`
+	DeliverySumWithVat flexNumber `json:"DeliverySumWithVat" xml:"DeliverySumWithVat"`
+}
+
+// flexNumber is a number a factura writes either as a number or as a string.
+type flexNumber float64
+
+func (n *flexNumber) UnmarshalJSON(b []byte) error {
+
+	if string(b) == "null" {
+		*n = 0
+		return nil
+	}
+
+	return n.UnmarshalText(bytes.Trim(b, `"`))
+}
+
+func (n *flexNumber) UnmarshalText(b []byte) error {
+
+	var s = strings.TrimSpace(string(b))
+	if len(s) == 0 {
+		*n = 0
+		return nil
+	}
+
+	value, err := spreadsheet.ParseNumber(s)
+	if err != nil {
+		return err
+	}
+
+	*n = flexNumber(value)
+	return nil
+}
+
+// envelopes are the keys operators wrap a factura in.
+var envelopes = []string{"data", "document", "json", "factura", "Factura"}
+
+func parseJSON(r io.Reader) (*Invoice, error) {
+
+	var body json.RawMessage
+	if err := json.NewDecoder(r).Decode(&body); err != nil {
+		return nil, &FileError{Message: "invalid json: " + err.Error()}
+	}
+
+	for depth := 0; depth < 3; depth++ {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(body, &fields); err != nil {
+			return nil, &FileError{Message: "invalid json: " + err.Error()}
+		}
+
+		if _, ok := fields["ProductList"]; ok {
+			var doc factura
+			if err := json.Unmarshal(body, &doc); err != nil {
+				return nil, &FileError{Message: "invalid factura: " + err.Error()}
+			}
+			return doc.invoice(), nil
+		}
+
+		var inner json.RawMessage
+		for _, key := range envelopes {
+			value, ok := fields[key]
+			if !ok {
+				continue
+			}
+
+			// some envelopes carry the document as a json string
+			var text string
+			if json.Unmarshal(value, &text) == nil {
+				value = json.RawMessage(text)
+			}
+
+			if bytes.HasPrefix(bytes.TrimSpace(value), []byte("{")) {
+				inner = value
+				break
+			}
+		}
+		if inner == nil {
+			break
+		}
+		body = inner
+	}
+
+	return nil, &FileError{Message: "json is not a factura: ProductList is missing"}
+}
+
+func parseXML(r io.Reader) (*Invoice, error) {
+
+	var doc factura
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, &FileError{Message: "invalid xml: " + err.Error()}
+	}
+
+	return doc.invoice(), nil
+}
+
+func (f *factura) invoice() *Invoice {
+
+	var invoice = &Invoice{
+		Number:     strings.TrimSpace(f.FacturaDoc.FacturaNo),
+		Date:       facturaDate(f.FacturaDoc.FacturaDate),
+		SellerTIN:  strings.TrimSpace(f.SellerTin),
+		SellerName: strings.TrimSpace(f.Seller.Name),
+	}
+
+	for i, product := range f.ProductList.Products {
+		var line = &Line{
+			Line:        int(product.OrdNo),
+			Name:        product.Name,
+			Barcode:     product.Barcode,
+			CatalogCode: strings.TrimSpace(product.CatalogCode),
+			Unit:        strings.TrimSpace(product.PackageName),
+			Quantity:    float64(product.Count),
+			VATRate:     float64(product.VatRate),
+			Price:       round(float64(product.Summa) * (1 + float64(product.VatRate)/100)),
+			Amount:      float64(product.DeliverySumWithVat),
+		}
+		if line.Line == 0 {
+			line.Line = i + 1
+		}
+		if line.Amount == 0 && product.DeliverySum > 0 {
+			line.Amount = float64(product.DeliverySum + product.VatSum)
+		}
+
+		invoice.Lines = append(invoice.Lines, line)
+	}
+
+	return invoice
+}
+
+// facturaDate returns a factura date as YYYY-MM-DD, empty when it can not be
+// read.
+func facturaDate(s string) string {
+
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{"2006-01-02", "02.01.2006", "2006-01-02T15:04:05", time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Format("2006-01-02")
+		}
+	}
+
+	return ""
+}
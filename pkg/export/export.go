@@ -0,0 +1,274 @@
+// Package export turns API responses into CSV, XLSX and PDF files.
+//
+// A response is read by reflection: every slice of structs in it becomes a
+// table whose columns are the scalar fields of the struct, named by their
+// json tags. Nested structs are flattened into prefixed columns, other
+// slices and maps are left out. A response without such a slice is exported
+// as a table of one row.
+package export
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Formats lists the file formats a response can be exported to.
+var Formats = []string{"csv", "xlsx", "pdf"}
+
+// Kind is how the values of a column are formatted.
+type Kind int
+
+const (
+	Text Kind = iota
+	Integer
+	Amount
+	Ratio
+	Bool
+)
+
+type Column struct {
+	Key  string
+	Kind Kind
+}
+
+// Table is one list of a response. Title is the json name of the list, empty
+// for the response itself.
+type Table struct {
+	Title   string
+	Columns []Column
+	Rows    [][]interface{}
+}
+
+// ratioKeys are the float fields that are fractions or statistics rather than
+// money and keep four decimals.
+var ratioKeys = []string{"share", "percent", "cv", "mean", "rate", "ratio", "per_minute", "avg_daily_sales"}
+
+// FromData returns the tables of a response, the longest first.
+func FromData(data interface{}) []*Table {
+
+	var value = indirect(reflect.ValueOf(data))
+	if !value.IsValid() {
+		return []*Table{{}}
+	}
+
+	if value.Kind() == reflect.Slice {
+		if table := fromSlice("", value); table != nil {
+			return []*Table{table}
+		}
+	}
+
+	var tables []*Table
+	if value.Kind() == reflect.Struct {
+		for i := 0; i < value.NumField(); i++ {
+			var field = value.Type().Field(i)
+			key, ok := jsonKey(field)
+			if !ok || indirectType(field.Type).Kind() != reflect.Slice {
+				continue
+			}
+
+			if table := fromSlice(key, indirect(value.Field(i))); table != nil {
+				tables = append(tables, table)
+			}
+		}
+	}
+
+	if len(tables) == 0 {
+		var table = &Table{Columns: columns("", value.Type())}
+		table.Rows = append(table.Rows, row(value))
+		return []*Table{table}
+	}
+
+	// the longest table is the one a single-table format writes
+	for i := 1; i < len(tables); i++ {
+		for j := i; j > 0 && len(tables[j].Rows) > len(tables[j-1].Rows); j-- {
+			tables[j], tables[j-1] = tables[j-1], tables[j]
+		}
+	}
+
+	return tables
+}
+
+// Select keeps the columns of the tables named by keys, in the order of keys.
+// A nested struct is kept whole by its key. Tables with none of the columns,
+// and all tables when there are no keys, are left as they are.
+func Select(tables []*Table, keys []string) {
+
+	if len(keys) == 0 {
+		return
+	}
+
+	for _, table := range tables {
+		var (
+			index   []int
+			columns []Column
+		)
+		for _, key := range keys {
+			for i, column := range table.Columns {
+				if column.Key == key || strings.HasPrefix(column.Key, key+".") {
+					index = append(index, i)
+					columns = append(columns, column)
+				}
+			}
+		}
+		if len(columns) == 0 {
+			continue
+		}
+
+		for r, values := range table.Rows {
+			var selected = make([]interface{}, 0, len(index))
+			for _, i := range index {
+				selected = append(selected, values[i])
+			}
+			table.Rows[r] = selected
+		}
+		table.Columns = columns
+	}
+}
+
+// fromSlice returns the table of a slice of structs, nil for other slices.
+func fromSlice(title string, value reflect.Value) *Table {
+
+	if !value.IsValid() {
+		return nil
+	}
+
+	var elem = indirectType(value.Type().Elem())
+	if elem.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var table = &Table{Title: title, Columns: columns("", elem)}
+	for i := 0; i < value.Len(); i++ {
+		table.Rows = append(table.Rows, row(indirect(value.Index(i))))
+	}
+
+	return table
+}
+
+func columns(prefix string, t reflect.Type) []Column {
+
+	t = indirectType(t)
+	if t.Kind() != reflect.Struct {
+		return []Column{{Key: prefix, Kind: kindOf(prefix, t)}}
+	}
+
+	var cols []Column
+	for i := 0; i < t.NumField(); i++ {
+		key, ok := jsonKey(t.Field(i))
+		if !ok {
+			continue
+		}
+		if len(prefix) > 0 {
+			key = prefix + "." + key
+		}
+
+		var field = indirectType(t.Field(i).Type)
+		switch field.Kind() {
+		case reflect.Struct:
+			cols = append(cols, columns(key, field)...)
+		case reflect.Slice, reflect.Array, reflect.Map, reflect.Interface, reflect.Func, reflect.Chan:
+		default:
+			cols = append(cols, Column{Key: key, Kind: kindOf(key, field)})
+		}
+	}
+
+	return cols
+}
+
+// row returns the values of a struct in the order of columns, nil for nil
+// pointers.
+func row(value reflect.Value) []interface{} {
+
+	if value.Kind() != reflect.Struct {
+		if !value.IsValid() {
+			return []interface{}{nil}
+		}
+		return []interface{}{value.Interface()}
+	}
+
+	var values []interface{}
+	for i := 0; i < value.NumField(); i++ {
+		var field = value.Type().Field(i)
+		if _, ok := jsonKey(field); !ok {
+			continue
+		}
+
+		var fieldType = indirectType(field.Type)
+		switch fieldType.Kind() {
+		case reflect.Struct:
+			var nested = indirect(value.Field(i))
+			if !nested.IsValid() {
+				values = append(values, make([]interface{}, len(columns("", fieldType)))...)
+				continue
+			}
+			values = append(values, row(nested)...)
+		case reflect.Slice, reflect.Array, reflect.Map, reflect.Interface, reflect.Func, reflect.Chan:
+		default:
+			var v = indirect(value.Field(i))
+			if !v.IsValid() {
+				values = append(values, nil)
+				continue
+			}
+			values = append(values, v.Interface())
+		}
+	}
+
+	return values
+}
+
+func kindOf(key string, t reflect.Type) Kind {
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Integer
+	case reflect.Float32, reflect.Float64:
+		var name = key[strings.LastIndex(key, ".")+1:]
+		for _, ratio := range ratioKeys {
+			if name == ratio || strings.HasSuffix(name, "_"+ratio) || strings.HasPrefix(name, ratio+"_") {
+				return Ratio
+			}
+		}
+		return Amount
+	case reflect.Bool:
+		return Bool
+	}
+
+	return Text
+}
+
+// jsonKey returns the json name of an exported field, false for fields json
+// leaves out.
+func jsonKey(field reflect.StructField) (string, bool) {
+
+	if field.PkgPath != "" {
+		return "", false
+	}
+
+	var tag = strings.Split(field.Tag.Get("json"), ",")[0]
+	switch tag {
+	case "-":
+		return "", false
+	case "":
+		return field.Name, true
+	}
+
+	return tag, true
+}
+
+func indirect(value reflect.Value) reflect.Value {
+	for value.IsValid() && (value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface) {
+		if value.IsNil() {
+			return reflect.Value{}
+		}
+		value = value.Elem()
+	}
+	return value
+}
+
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
@@ -0,0 +1,194 @@
+package export
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type testMetrics struct {
+	Revenue float64 `json:"revenue"`
+	Share   float64 `json:"share"`
+}
+
+type testRow struct {
+	Name    string       `json:"name"`
+	Units   int64        `json:"units"`
+	Change  *float64     `json:"change"`
+	Metrics testMetrics  `json:"metrics"`
+	Lines   []string     `json:"lines"`
+	Secret  string       `json:"-"`
+	Parent  *testMetrics `json:"parent"`
+}
+
+type testResponse struct {
+	Count   int        `json:"count"`
+	Rows    []*testRow `json:"rows"`
+	Summary []testRow  `json:"summary"`
+}
+
+func TestFromData(t *testing.T) {
+
+	change := 0.5
+	resp := testResponse{
+		Count: 2,
+		Rows: []*testRow{
+			{Name: "milk", Units: 3, Change: &change, Metrics: testMetrics{Revenue: 12.5, Share: 0.25}},
+			{Name: "bread", Units: 1},
+		},
+		Summary: []testRow{{Name: "total"}},
+	}
+
+	tables := FromData(&resp)
+	if len(tables) != 2 || tables[0].Title != "rows" || tables[1].Title != "summary" {
+		t.Fatalf("FromData() tables = %+v", tables)
+	}
+
+	var keys []string
+	var kinds []Kind
+	for _, column := range tables[0].Columns {
+		keys = append(keys, column.Key)
+		kinds = append(kinds, column.Kind)
+	}
+
+	wantKeys := []string{"name", "units", "change", "metrics.revenue", "metrics.share", "parent.revenue", "parent.share"}
+	if !reflect.DeepEqual(keys, wantKeys) {
+		t.Errorf("columns = %v, want %v", keys, wantKeys)
+	}
+
+	wantKinds := []Kind{Text, Integer, Amount, Amount, Ratio, Amount, Ratio}
+	if !reflect.DeepEqual(kinds, wantKinds) {
+		t.Errorf("kinds = %v, want %v", kinds, wantKinds)
+	}
+
+	wantRow := []interface{}{"bread", int64(1), nil, 0.0, 0.0, nil, nil}
+	if !reflect.DeepEqual(tables[0].Rows[1], wantRow) {
+		t.Errorf("row = %#v, want %#v", tables[0].Rows[1], wantRow)
+	}
+
+	single := FromData(testMetrics{Revenue: 1})
+	if len(single) != 1 || len(single[0].Rows) != 1 || single[0].Rows[0][0] != 1.0 {
+		t.Errorf("FromData(struct) = %+v", single[0])
+	}
+}
+
+func TestSelect(t *testing.T) {
+
+	resp := testResponse{
+		Rows:    []*testRow{{Name: "milk", Units: 3, Metrics: testMetrics{Revenue: 12.5, Share: 0.25}}},
+		Summary: []testRow{{Name: "total"}},
+	}
+
+	tables := FromData(&resp)
+	Select(tables, []string{"metrics", "name", "missing"})
+
+	var keys []string
+	for _, column := range tables[0].Columns {
+		keys = append(keys, column.Key)
+	}
+
+	wantKeys := []string{"metrics.revenue", "metrics.share", "name"}
+	if !reflect.DeepEqual(keys, wantKeys) {
+		t.Errorf("columns = %v, want %v", keys, wantKeys)
+	}
+
+	wantRow := []interface{}{12.5, 0.25, "milk"}
+	if !reflect.DeepEqual(tables[0].Rows[0], wantRow) {
+		t.Errorf("row = %#v, want %#v", tables[0].Rows[0], wantRow)
+	}
+
+	tables = FromData(&resp)
+	Select(tables, nil)
+	if len(tables[0].Columns) != 7 {
+		t.Errorf("Select(nil) columns = %d, want 7", len(tables[0].Columns))
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+
+	tests := []struct {
+		v        float64
+		decimals int
+		group    bool
+		lang     string
+		want     string
+	}{
+		{1234567.891, 2, true, "en", "1,234,567.89"},
+		{1234567.891, 2, true, "ru", "1\u00a0234\u00a0567,89"},
+		{-1234.5, 2, false, "uz", "-1234,50"},
+		{-0.001, 2, true, "en", "0.00"},
+		{999, 0, true, "en", "999"},
+		{1000, 0, true, "ru", "1\u00a0000"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatNumber(tt.v, tt.decimals, tt.group, tt.lang); got != tt.want {
+			t.Errorf("FormatNumber(%v, %d, %v, %s) = %q, want %q", tt.v, tt.decimals, tt.group, tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestLanguageAndHeader(t *testing.T) {
+
+	if got := Language("ru-RU,ru;q=0.9,en;q=0.8"); got != "ru" {
+		t.Errorf("Language() = %q, want ru", got)
+	}
+
+	if got := Language("de"); got != "en" {
+		t.Errorf("Language() = %q, want en", got)
+	}
+
+	if got := Header("metrics.revenue", "ru"); got != "Показатели / Выручка" {
+		t.Errorf("Header() = %q", got)
+	}
+
+	if got := Header("sale_point_id", "en"); got != "Sale point ID" {
+		t.Errorf("Header() = %q", got)
+	}
+
+	if got := Header("abc", "en"); got != "ABC" {
+		t.Errorf("Header() = %q", got)
+	}
+}
+
+func TestWrite(t *testing.T) {
+
+	tables := FromData([]testMetrics{{Revenue: 1234.5, Share: 0.5}})
+
+	var csv bytes.Buffer
+	if err := Write(&csv, "csv", tables, Options{Lang: "ru"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "\ufeffВыручка;Доля\n1234,50;0,5000\n"; csv.String() != want {
+		t.Errorf("csv = %q, want %q", csv.String(), want)
+	}
+
+	var xlsx bytes.Buffer
+	if err := Write(&xlsx, "xlsx", tables, Options{Lang: "en"}); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := excelize.OpenReader(&xlsx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	rows, err := file.GetRows("Data")
+	if err != nil || len(rows) != 2 || rows[0][0] != "Revenue" {
+		t.Errorf("xlsx rows = %v, %v", rows, err)
+	}
+
+	var pdf bytes.Buffer
+	if err := Write(&pdf, "pdf", tables, Options{Lang: "en", Title: "Sales"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(pdf.String(), "%PDF") {
+		t.Errorf("pdf does not start with %%PDF")
+	}
+}
@@ -0,0 +1,267 @@
+package export
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Languages lists the languages headers and numbers are localized to, the
+// default first.
+var Languages = []string{"en", "ru", "uz"}
+
+// headers translates the most common json keys. Other keys are written as
+// words, nested keys joined by " / ".
+var headers = map[string]map[string]string{
+	"ru": {
+		"id":                "ID",
+		"name":              "Наименование",
+		"title":             "Наименование",
+		"product_name":      "Товар",
+		"product":           "Товар",
+		"barcode":           "Штрихкод",
+		"category_id":       "Категория",
+		"category_name":     "Категория",
+		"brand_name":        "Бренд",
+		"branch_id":         "Филиал",
+		"branch_name":       "Филиал",
+		"branch":            "Филиал",
+		"sale_point_id":     "Касса",
+		"sale_point_name":   "Касса",
+		"supplier_id":       "Поставщик",
+		"supplier_name":     "Поставщик",
+		"customer_id":       "Покупатель",
+		"cashier_id":        "Кассир",
+		"cashier_name":      "Кассир",
+		"employee_id":       "Сотрудник",
+		"user_id":           "Пользователь",
+		"client_name":       "Клиент",
+		"phone":             "Телефон",
+		"address":           "Адрес",
+		"status":            "Статус",
+		"type":              "Тип",
+		"comment":           "Комментарий",
+		"reason":            "Причина",
+		"price":             "Цена",
+		"price_income":      "Цена закупки",
+		"income_price":      "Цена закупки",
+		"cost":              "Себестоимость",
+		"quantity":          "Количество",
+		"units":             "Штук",
+		"amount":            "Сумма",
+		"total_amount":      "Итого",
+		"total":             "Итого",
+		"revenue":           "Выручка",
+		"gross_margin":      "Валовая прибыль",
+		"margin_percent":    "Маржа, %",
+		"discount":          "Скидка",
+		"balance":           "Остаток",
+		"on_hand":           "На складе",
+		"on_order":          "В заказе",
+		"sales":             "Продаж",
+		"avg_basket":        "Средний чек",
+		"period":            "Период",
+		"date":              "Дата",
+		"from_date":         "С",
+		"to_date":           "По",
+		"created_at":        "Создано",
+		"updated_at":        "Изменено",
+		"created_by":        "Автор",
+		"count":             "Количество",
+		"share":             "Доля",
+		"cumulative_share":  "Накопленная доля",
+		"metrics":           "Показатели",
+		"previous":          "Прошлый период",
+		"change":            "Изменение",
+		"days":              "Дней",
+		"value":             "Стоимость",
+		"retail_value":      "Розничная стоимость",
+		"cash_variance":     "Расхождение кассы",
+		"opening_cash":      "Наличные на начало",
+		"counted_cash":      "Пересчитано",
+		"expected_date":     "Ожидаемая дата",
+		"received_quantity": "Получено",
+	},
+	"uz": {
+		"id":                "ID",
+		"name":              "Nomi",
+		"title":             "Nomi",
+		"product_name":      "Mahsulot",
+		"product":           "Mahsulot",
+		"barcode":           "Shtrix-kod",
+		"category_id":       "Kategoriya",
+		"category_name":     "Kategoriya",
+		"brand_name":        "Brend",
+		"branch_id":         "Filial",
+		"branch_name":       "Filial",
+		"branch":            "Filial",
+		"sale_point_id":     "Kassa",
+		"sale_point_name":   "Kassa",
+		"supplier_id":       "Yetkazib beruvchi",
+		"supplier_name":     "Yetkazib beruvchi",
+		"customer_id":       "Xaridor",
+		"cashier_id":        "Kassir",
+		"cashier_name":      "Kassir",
+		"employee_id":       "Xodim",
+		"user_id":           "Foydalanuvchi",
+		"client_name":       "Mijoz",
+		"phone":             "Telefon",
+		"address":           "Manzil",
+		"status":            "Holat",
+		"type":              "Turi",
+		"comment":           "Izoh",
+		"reason":            "Sabab",
+		"price":             "Narx",
+		"price_income":      "Kirim narxi",
+		"income_price":      "Kirim narxi",
+		"cost":              "Tannarx",
+		"quantity":          "Miqdor",
+		"units":             "Dona",
+		"amount":            "Summa",
+		"total_amount":      "Jami",
+		"total":             "Jami",
+		"revenue":           "Tushum",
+		"gross_margin":      "Yalpi foyda",
+		"margin_percent":    "Marja, %",
+		"discount":          "Chegirma",
+		"balance":           "Qoldiq",
+		"on_hand":           "Omborda",
+		"on_order":          "Buyurtmada",
+		"sales":             "Sotuvlar",
+		"avg_basket":        "O'rtacha chek",
+		"period":            "Davr",
+		"date":              "Sana",
+		"from_date":         "Dan",
+		"to_date":           "Gacha",
+		"created_at":        "Yaratilgan",
+		"updated_at":        "O'zgartirilgan",
+		"created_by":        "Muallif",
+		"count":             "Soni",
+		"share":             "Ulush",
+		"cumulative_share":  "Yig'ma ulush",
+		"metrics":           "Ko'rsatkichlar",
+		"previous":          "O'tgan davr",
+		"change":            "O'zgarish",
+		"days":              "Kun",
+		"value":             "Qiymat",
+		"retail_value":      "Chakana qiymat",
+		"cash_variance":     "Kassa farqi",
+		"opening_cash":      "Boshlang'ich naqd",
+		"counted_cash":      "Sanalgan naqd",
+		"expected_date":     "Kutilgan sana",
+		"received_quantity": "Qabul qilingan",
+	},
+}
+
+// acronyms are the words of keys written in capitals.
+var acronyms = map[string]bool{"id": true, "abc": true, "xyz": true, "cv": true, "url": true}
+
+// Language returns the supported language of an Accept-Language header or
+// lang parameter, the default one when none is supported.
+func Language(accept string) string {
+
+	for _, part := range strings.Split(accept, ",") {
+		var tag = strings.ToLower(strings.TrimSpace(strings.Split(part, ";")[0]))
+		if len(tag) > 2 {
+			tag = tag[:2]
+		}
+
+		for _, lang := range Languages {
+			if tag == lang {
+				return lang
+			}
+		}
+	}
+
+	return Languages[0]
+}
+
+// Header returns the column header of a key in lang.
+func Header(key, lang string) string {
+
+	var parts = strings.Split(key, ".")
+	for i, part := range parts {
+		if header, ok := headers[lang][part]; ok {
+			parts[i] = header
+			continue
+		}
+
+		var words = strings.Split(part, "_")
+		for j, word := range words {
+			if acronyms[word] {
+				words[j] = strings.ToUpper(word)
+			}
+		}
+		if len(words[0]) > 0 {
+			words[0] = strings.ToUpper(words[0][:1]) + words[0][1:]
+		}
+		parts[i] = strings.Join(words, " ")
+	}
+
+	return strings.Join(parts, " / ")
+}
+
+// Decimals returns the number of decimals a column of kind is written with.
+func Decimals(kind Kind) int {
+
+	switch kind {
+	case Amount:
+		return 2
+	case Ratio:
+		return 4
+	}
+
+	return 0
+}
+
+// separators returns the decimal and thousands separators of lang. Thousands
+// are grouped by a no-break space where a space groups them.
+func separators(lang string) (decimal, thousands string) {
+
+	if lang == "en" {
+		return ".", ","
+	}
+
+	return ",", "\u00a0"
+}
+
+// FormatNumber writes v with decimals decimals, grouping thousands when group
+// is set, the way lang writes numbers.
+func FormatNumber(v float64, decimals int, group bool, lang string) string {
+
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return ""
+	}
+
+	var (
+		decimal, thousands = separators(lang)
+		text               = strconv.FormatFloat(math.Abs(v), 'f', decimals, 64)
+		whole, fraction    = text, ""
+		sign               string
+	)
+
+	if i := strings.IndexByte(text, '.'); i >= 0 {
+		whole, fraction = text[:i], text[i+1:]
+	}
+
+	if v < 0 && strings.Trim(text, "0.") != "" {
+		sign = "-"
+	}
+
+	if group {
+		var grouped strings.Builder
+		for i, digit := range whole {
+			if i > 0 && (len(whole)-i)%3 == 0 {
+				grouped.WriteString(thousands)
+			}
+			grouped.WriteRune(digit)
+		}
+		whole = grouped.String()
+	}
+
+	if len(fraction) > 0 {
+		return sign + whole + decimal + fraction
+	}
+
+	return sign + whole
+}
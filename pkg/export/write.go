@@ -0,0 +1,298 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/xuri/excelize/v2"
+)
+
+// Options are how a file is written. Title heads PDF files. FontFile is a
+// TrueType font for PDF files; without it PDF text is limited to the
+// Latin-1 characters of the standard fonts.
+type Options struct {
+	Lang     string
+	Title    string
+	FontFile string
+}
+
+// ContentType returns the MIME type of format.
+func ContentType(format string) string {
+
+	switch format {
+	case "xlsx":
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case "pdf":
+		return "application/pdf"
+	}
+
+	return "text/csv; charset=utf-8"
+}
+
+// Write writes tables to w in format. CSV holds only the first table, XLSX a
+// sheet per table and PDF a section per table.
+func Write(w io.Writer, format string, tables []*Table, opts Options) error {
+
+	switch format {
+	case "csv":
+		return writeCSV(w, tables[0], opts)
+	case "xlsx":
+		return writeXLSX(w, tables, opts)
+	case "pdf":
+		return writePDF(w, tables, opts)
+	}
+
+	return fmt.Errorf("unknown export format %q", format)
+}
+
+// text formats a value of a column for a text file.
+func text(value interface{}, kind Kind, group bool, lang string) string {
+
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case bool:
+		return strconv.FormatBool(v)
+	case float64:
+		return FormatNumber(v, Decimals(kind), group, lang)
+	case float32:
+		return FormatNumber(float64(v), Decimals(kind), group, lang)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		number, _ := strconv.ParseFloat(fmt.Sprint(v), 64)
+		return FormatNumber(number, 0, group, lang)
+	}
+
+	return fmt.Sprint(value)
+}
+
+// writeCSV writes a table the way spreadsheets of lang open it: with a byte
+// order mark, and separated by semicolons where the decimal separator is a
+// comma. Numbers are not grouped so that they stay numbers.
+func writeCSV(w io.Writer, table *Table, opts Options) error {
+
+	_, err := io.WriteString(w, "\ufeff")
+	if err != nil {
+		return err
+	}
+
+	var writer = csv.NewWriter(w)
+	if decimal, _ := separators(opts.Lang); decimal == "," {
+		writer.Comma = ';'
+	}
+
+	var record = make([]string, len(table.Columns))
+	for i, column := range table.Columns {
+		record[i] = Header(column.Key, opts.Lang)
+	}
+	if err = writer.Write(record); err != nil {
+		return err
+	}
+
+	for _, row := range table.Rows {
+		for i, column := range table.Columns {
+			record[i] = text(row[i], column.Kind, false, opts.Lang)
+		}
+		if err = writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// xlsxFormats are the number formats of XLSX columns. Spreadsheets show them
+// with the separators of their own locale.
+var xlsxFormats = map[Kind]string{
+	Integer: "#,##0",
+	Amount:  "#,##0.00",
+	Ratio:   "0.0000",
+}
+
+func writeXLSX(w io.Writer, tables []*Table, opts Options) error {
+
+	var file = excelize.NewFile()
+	defer file.Close()
+
+	header, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+
+	var styles = map[Kind]int{}
+	for kind, format := range xlsxFormats {
+		var format = format
+		styles[kind], err = file.NewStyle(&excelize.Style{CustomNumFmt: &format})
+		if err != nil {
+			return err
+		}
+	}
+
+	var names = map[string]bool{}
+	for i, table := range tables {
+		var name = sheetName(table.Title, opts.Lang, names)
+		if i == 0 {
+			err = file.SetSheetName("Sheet1", name)
+		} else {
+			_, err = file.NewSheet(name)
+		}
+		if err != nil {
+			return err
+		}
+
+		stream, err := file.NewStreamWriter(name)
+		if err != nil {
+			return err
+		}
+
+		var cells = make([]interface{}, len(table.Columns))
+		for i, column := range table.Columns {
+			cells[i] = excelize.Cell{StyleID: header, Value: Header(column.Key, opts.Lang)}
+		}
+		if err = stream.SetRow("A1", cells); err != nil {
+			return err
+		}
+
+		for r, row := range table.Rows {
+			cells = make([]interface{}, len(table.Columns))
+			for i, column := range table.Columns {
+				cells[i] = excelize.Cell{StyleID: styles[column.Kind], Value: row[i]}
+			}
+
+			cell, err := excelize.CoordinatesToCellName(1, r+2)
+			if err != nil {
+				return err
+			}
+			if err = stream.SetRow(cell, cells); err != nil {
+				return err
+			}
+		}
+
+		if err = stream.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return file.Write(w)
+}
+
+// sheetName returns a unique sheet name of at most 31 characters for a table.
+func sheetName(title, lang string, used map[string]bool) string {
+
+	var name = "Data"
+	if len(title) > 0 {
+		name = Header(title, lang)
+	}
+
+	var runes = []rune(name)
+	if len(runes) > 28 {
+		name = string(runes[:28])
+	}
+
+	var unique = name
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s %d", name, i)
+	}
+	used[unique] = true
+
+	return unique
+}
+
+// writePDF writes tables on landscape A4 pages, the columns sharing the page
+// width and the header repeated on every page.
+func writePDF(w io.Writer, tables []*Table, opts Options) error {
+
+	var (
+		pdf       = gofpdf.New("L", "mm", "A4", "")
+		family    = "Helvetica"
+		translate = func(s string) string { return s }
+		lineH     = 6.0
+	)
+
+	if len(opts.FontFile) > 0 {
+		pdf.AddUTF8Font("export", "", opts.FontFile)
+		pdf.AddUTF8Font("export", "B", opts.FontFile)
+		family = "export"
+	} else {
+		translate = pdf.UnicodeTranslatorFromDescriptor("")
+	}
+
+	pdf.SetMargins(10, 10, 10)
+	pdf.SetAutoPageBreak(false, 10)
+	pdf.AddPage()
+
+	var (
+		pageW, pageH           = pdf.GetPageSize()
+		left, _, right, bottom = pdf.GetMargins()
+		width                  = pageW - left - right
+	)
+
+	if len(opts.Title) > 0 {
+		pdf.SetFont(family, "B", 12)
+		pdf.CellFormat(width, lineH*1.5, translate(opts.Title), "", 1, "L", false, 0, "")
+	}
+
+	for _, table := range tables {
+		if len(table.Columns) == 0 {
+			continue
+		}
+
+		var (
+			cellW  = width / float64(len(table.Columns))
+			header = func() {
+				pdf.SetFont(family, "B", 7)
+				for _, column := range table.Columns {
+					pdf.CellFormat(cellW, lineH, fit(pdf, translate, Header(column.Key, opts.Lang), cellW), "1", 0, "C", true, 0, "")
+				}
+				pdf.Ln(-1)
+				pdf.SetFont(family, "", 7)
+			}
+		)
+
+		pdf.SetFillColor(230, 230, 230)
+		if len(table.Title) > 0 {
+			pdf.SetFont(family, "B", 10)
+			pdf.CellFormat(width, lineH*1.5, translate(Header(table.Title, opts.Lang)), "", 1, "L", false, 0, "")
+		}
+		header()
+
+		for _, row := range table.Rows {
+			if pdf.GetY()+lineH > pageH-bottom {
+				pdf.AddPage()
+				header()
+			}
+
+			for i, column := range table.Columns {
+				var align = "L"
+				if column.Kind == Integer || column.Kind == Amount || column.Kind == Ratio {
+					align = "R"
+				}
+
+				var value = text(row[i], column.Kind, true, opts.Lang)
+				pdf.CellFormat(cellW, lineH, fit(pdf, translate, value, cellW), "1", 0, align, false, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+
+		pdf.Ln(lineH)
+	}
+
+	return pdf.Output(w)
+}
+
+// fit translates s to the encoding of the font and cuts it to the width of a
+// cell.
+func fit(pdf *gofpdf.Fpdf, translate func(string) string, s string, width float64) string {
+
+	var runes = []rune(s)
+	for len(runes) > 0 && pdf.GetStringWidth(translate(string(runes)))+2 > width {
+		runes = runes[:len(runes)-1]
+	}
+
+	return translate(string(runes))
+}
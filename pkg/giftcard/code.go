@@ -0,0 +1,66 @@
+// Package giftcard generates gift card and store credit codes.
+//
+// Codes are EAN-13 numbers in the 20-29 prefix range GS1 leaves for in-store
+// use, so any label printer or scanner that handles product barcodes can
+// print and read them.
+package giftcard
+
+import (
+	"crypto/rand"
+	"math/big"
+)
+
+// Prefix is the in-store EAN-13 prefix every generated code starts with.
+const Prefix = "29"
+
+// CodeLength is the length of a code including the check digit.
+const CodeLength = 13
+
+// GenerateCode returns a random code with a valid EAN-13 check digit.
+func GenerateCode() (string, error) {
+
+	var digits = []byte(Prefix)
+	for len(digits) < CodeLength-1 {
+		n, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+
+		digits = append(digits, byte('0'+n.Int64()))
+	}
+
+	return string(append(digits, checkDigit(digits))), nil
+}
+
+// ValidCode reports whether code is a 13 digit number with a correct check
+// digit.
+func ValidCode(code string) bool {
+
+	if len(code) != CodeLength {
+		return false
+	}
+
+	for i := 0; i < len(code); i++ {
+		if code[i] < '0' || code[i] > '9' {
+			return false
+		}
+	}
+
+	return checkDigit([]byte(code[:CodeLength-1])) == code[CodeLength-1]
+}
+
+// checkDigit computes the EAN-13 check digit of the first 12 digits.
+func checkDigit(digits []byte) byte {
+
+	var sum int
+	for i, digit := range digits {
+		var weight = 1
+		if i%2 == 1 {
+			weight = 3
+		}
+
+		sum += int(digit-'0') * weight
+	}
+
+	return byte('0' + (10-sum%10)%10)
+}
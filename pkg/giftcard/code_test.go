@@ -0,0 +1,39 @@
+package giftcard
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidCode(t *testing.T) {
+
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{code: "4006381333931", want: true},
+		{code: "4006381333932", want: false},
+		{code: "400638133393", want: false},
+		{code: "40063813339a1", want: false},
+	}
+
+	for _, test := range tests {
+		if got := ValidCode(test.code); got != test.want {
+			t.Errorf("ValidCode(%q) = %v, want %v", test.code, got, test.want)
+		}
+	}
+}
+
+func TestGenerateCode(t *testing.T) {
+
+	for i := 0; i < 100; i++ {
+		code, err := GenerateCode()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.HasPrefix(code, Prefix) || !ValidCode(code) {
+			t.Fatalf("GenerateCode() = %q, not a valid in-store code", code)
+		}
+	}
+}
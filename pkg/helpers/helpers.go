@@ -2,6 +2,7 @@ package helpers
 
 import (
 	"regexp"
+	"time"
 )
 
 // IsValidPhone ...
@@ -28,6 +29,12 @@ func IsValidUUID(uuid string) bool {
 	return r.MatchString(uuid)
 }
 
+// IsValidDate reports whether date is a YYYY-MM-DD calendar date.
+func IsValidDate(date string) bool {
+	_, err := time.Parse("2006-01-02", date)
+	return err == nil
+}
+
 func Contains(s []string, e string) bool {
 	for _, a := range s {
 		if a == e {
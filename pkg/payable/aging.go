@@ -0,0 +1,72 @@
+// Package payable ages what is owed to a supplier.
+package payable
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Invoice is a payable raised by a finished income.
+type Invoice struct {
+	Date   time.Time
+	Amount float64
+}
+
+// Aging splits the open balance by the age of the invoices it is made of.
+// Advance is what was paid or credited beyond all invoices.
+type Aging struct {
+	Current    float64
+	Days31To60 float64
+	Days61To90 float64
+	Over90     float64
+	Advance    float64
+	Total      float64
+}
+
+// Age settles credits (payments and returns) against the oldest invoices
+// first and buckets what is left by days between the invoice date and at.
+func Age(invoices []Invoice, credits float64, at time.Time) Aging {
+
+	var aging Aging
+
+	sorted := make([]Invoice, len(invoices))
+	copy(sorted, invoices)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Date.Before(sorted[j].Date) })
+
+	for _, invoice := range sorted {
+		open := invoice.Amount
+		if credits > 0 {
+			settled := math.Min(credits, open)
+			credits -= settled
+			open -= settled
+		}
+		if open <= 0 {
+			continue
+		}
+
+		switch days := int(at.Sub(invoice.Date).Hours() / 24); {
+		case days <= 30:
+			aging.Current += open
+		case days <= 60:
+			aging.Days31To60 += open
+		case days <= 90:
+			aging.Days61To90 += open
+		default:
+			aging.Over90 += open
+		}
+	}
+
+	aging.Advance = round(credits)
+	aging.Current = round(aging.Current)
+	aging.Days31To60 = round(aging.Days31To60)
+	aging.Days61To90 = round(aging.Days61To90)
+	aging.Over90 = round(aging.Over90)
+	aging.Total = round(aging.Current + aging.Days31To60 + aging.Days61To90 + aging.Over90 - aging.Advance)
+
+	return aging
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,33 @@
+package payable
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAge(t *testing.T) {
+
+	at := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	invoices := []Invoice{
+		{Date: at.AddDate(0, 0, -10), Amount: 100},
+		{Date: at.AddDate(0, 0, -100), Amount: 300},
+		{Date: at.AddDate(0, 0, -45), Amount: 200},
+	}
+
+	got := Age(invoices, 350, at)
+	want := Aging{Current: 100, Days31To60: 150, Total: 250}
+	if got != want {
+		t.Errorf("Age() = %+v, want %+v", got, want)
+	}
+}
+
+func TestAgeAdvance(t *testing.T) {
+
+	at := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+
+	got := Age([]Invoice{{Date: at, Amount: 100}}, 150, at)
+	want := Aging{Advance: 50, Total: -50}
+	if got != want {
+		t.Errorf("Age() = %+v, want %+v", got, want)
+	}
+}
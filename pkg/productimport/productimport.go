@@ -0,0 +1,254 @@
+// Package productimport reads and validates product files for bulk import.
+//
+// A file is CSV or XLSX, read by package spreadsheet. Its first row names the
+// columns: title, barcode, category, brand, price and unit, in any order and
+// in English, Russian or Uzbek. The category is a path of titles from the
+// root, separated by "/" or ">".
+package productimport
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strings"
+
+	"market_system/pkg/spreadsheet"
+)
+
+// Formats lists the file formats that can be imported.
+var Formats = []string{"csv", "xlsx"}
+
+// Units lists the units a product is sold in, the default first.
+var Units = []string{"pcs", "kg", "g", "l", "ml", "m", "pack"}
+
+// MaxRows caps the rows of a file.
+const MaxRows = 100000
+
+// maxPrice is the largest price product.price (DECIMAL(10, 2)) holds.
+const maxPrice = 99999999.99
+
+// Row is a row of a file. Line is its line in the file, the header being
+// line 1. Errors lists what is wrong with the row; a row without errors can
+// be imported.
+type Row struct {
+	Line     int
+	Title    string
+	Barcode  string
+	Category []string
+	Brand    string
+	Price    float64
+	Unit     string
+	Errors   []string
+}
+
+// FileError is a file that can not be imported at all.
+type FileError struct {
+	Message string
+}
+
+func (e *FileError) Error() string {
+	return e.Message
+}
+
+// columns maps the names a header may give a column to the column.
+var columns = map[string]string{
+	"title":        "title",
+	"name":         "title",
+	"product":      "title",
+	"product_name": "title",
+	"наименование": "title",
+	"название":     "title",
+	"товар":        "title",
+	"nomi":         "title",
+	"mahsulot":     "title",
+	"barcode":      "barcode",
+	"штрихкод":     "barcode",
+	"штрих-код":    "barcode",
+	"shtrix-kod":   "barcode",
+	"shtrixkod":    "barcode",
+	"category":     "category",
+	"категория":    "category",
+	"kategoriya":   "category",
+	"brand":        "brand",
+	"бренд":        "brand",
+	"brend":        "brand",
+	"price":        "price",
+	"цена":         "price",
+	"narx":         "price",
+	"unit":         "unit",
+	"ед. изм.":     "unit",
+	"единица":      "unit",
+	"o'lchov":      "unit",
+}
+
+var required = []string{"title", "barcode", "category", "price"}
+
+var barcodePattern = regexp.MustCompile(`^[0-9A-Za-z\-]{1,50}$`)
+
+// Parse reads and validates the rows of a file in format.
+func Parse(r io.Reader, format string) ([]*Row, error) {
+
+	if !contains(Formats, format) {
+		return nil, &FileError{Message: "format must be one of " + strings.Join(Formats, ", ")}
+	}
+
+	records, err := spreadsheet.Read(r, format)
+	if err != nil {
+		return nil, &FileError{Message: err.Error()}
+	}
+
+	if len(records) == 0 {
+		return nil, &FileError{Message: "file is empty"}
+	}
+
+	if len(records)-1 > MaxRows {
+		return nil, &FileError{Message: fmt.Sprintf("file has more than %d rows", MaxRows)}
+	}
+
+	var index = map[string]int{}
+	for i, name := range records[0] {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if column, ok := columns[name]; ok {
+			if _, ok := index[column]; !ok {
+				index[column] = i
+			}
+		}
+	}
+
+	var missing []string
+	for _, column := range required {
+		if _, ok := index[column]; !ok {
+			missing = append(missing, column)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, &FileError{Message: "missing columns: " + strings.Join(missing, ", ")}
+	}
+
+	var (
+		rows     []*Row
+		barcodes = map[string]int{}
+	)
+	for i, record := range records[1:] {
+		var cell = func(column string) string {
+			if j, ok := index[column]; ok && j < len(record) {
+				return strings.TrimSpace(record[j])
+			}
+			return ""
+		}
+
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		var row = validate(i+2, cell("title"), cell("barcode"), cell("category"), cell("brand"), cell("price"), cell("unit"))
+
+		if len(row.Barcode) > 0 {
+			if line, ok := barcodes[row.Barcode]; ok {
+				row.Errors = append(row.Errors, fmt.Sprintf("barcode repeats line %d", line))
+			} else {
+				barcodes[row.Barcode] = row.Line
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// validate builds the row of line from its cells.
+func validate(line int, title, barcode, category, brand, price, unit string) *Row {
+
+	var row = &Row{
+		Line:    line,
+		Title:   title,
+		Barcode: barcode,
+		Brand:   brand,
+		Unit:    strings.ToLower(unit),
+	}
+
+	switch {
+	case len(title) == 0:
+		row.Errors = append(row.Errors, "title is required")
+	case len([]rune(title)) > 255:
+		row.Errors = append(row.Errors, "title is longer than 255 characters")
+	}
+
+	switch {
+	case len(barcode) == 0:
+		row.Errors = append(row.Errors, "barcode is required")
+	case !barcodePattern.MatchString(barcode):
+		row.Errors = append(row.Errors, "barcode must be up to 50 letters, digits or dashes")
+	}
+
+	row.Category = CategoryPath(category)
+	if len(row.Category) == 0 {
+		row.Errors = append(row.Errors, "category is required")
+	}
+	for _, title := range row.Category {
+		if len([]rune(title)) > 255 {
+			row.Errors = append(row.Errors, "category title is longer than 255 characters")
+			break
+		}
+	}
+
+	if len([]rune(brand)) > 255 {
+		row.Errors = append(row.Errors, "brand is longer than 255 characters")
+	}
+
+	value, err := ParsePrice(price)
+	switch {
+	case len(price) == 0:
+		row.Errors = append(row.Errors, "price is required")
+	case err != nil:
+		row.Errors = append(row.Errors, "price is not a number")
+	case value < 0 || value > maxPrice:
+		row.Errors = append(row.Errors, fmt.Sprintf("price must be between 0 and %.2f", maxPrice))
+	default:
+		row.Price = value
+	}
+
+	if len(row.Unit) == 0 {
+		row.Unit = Units[0]
+	} else if !contains(Units, row.Unit) {
+		row.Errors = append(row.Errors, "unit must be one of "+strings.Join(Units, ", "))
+	}
+
+	return row
+}
+
+// CategoryPath splits a category path into the titles of its categories.
+func CategoryPath(path string) []string {
+
+	var titles []string
+	for _, title := range strings.FieldsFunc(path, func(r rune) bool { return r == '/' || r == '>' }) {
+		if title = strings.TrimSpace(title); len(title) > 0 {
+			titles = append(titles, title)
+		}
+	}
+
+	return titles
+}
+
+// ParsePrice reads a price the way spreadsheet.ParseNumber reads numbers,
+// rounded to cents.
+func ParsePrice(s string) (float64, error) {
+
+	value, err := spreadsheet.ParseNumber(s)
+	if err != nil {
+		return 0, err
+	}
+
+	return math.Round(value*100) / 100, nil
+}
+
+func contains(s []string, e string) bool {
+	for _, a := range s {
+		if a == e {
+			return true
+		}
+	}
+	return false
+}
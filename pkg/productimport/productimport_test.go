@@ -0,0 +1,107 @@
+package productimport
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func TestParseCSV(t *testing.T) {
+
+	file := "\ufeffНаименование;Штрихкод;Категория;Бренд;Цена;Ед. изм.\n" +
+		"Молоко 1л;4780001;Продукты / Молочные;Nestle;12 500,50;l\n" +
+		";;;;;\n" +
+		"Хлеб;4780002;Продукты>Хлеб;;abc;шт\n" +
+		"Кефир;4780001;;;-1;\n"
+
+	rows, err := Parse(strings.NewReader(file), "csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows) != 3 {
+		t.Fatalf("Parse() rows = %d, want 3", len(rows))
+	}
+
+	milk := rows[0]
+	if milk.Line != 2 || milk.Price != 12500.5 || milk.Unit != "l" || milk.Brand != "Nestle" || len(milk.Errors) != 0 {
+		t.Errorf("row 1 = %+v", milk)
+	}
+	if !reflect.DeepEqual(milk.Category, []string{"Продукты", "Молочные"}) {
+		t.Errorf("row 1 category = %v", milk.Category)
+	}
+
+	bread := rows[1]
+	if bread.Line != 4 || !reflect.DeepEqual(bread.Errors, []string{"price is not a number", "unit must be one of pcs, kg, g, l, ml, m, pack"}) {
+		t.Errorf("row 2 = %+v", bread)
+	}
+
+	kefir := rows[2]
+	want := []string{"category is required", "price must be between 0 and 99999999.99", "barcode repeats line 2"}
+	if kefir.Unit != "pcs" || !reflect.DeepEqual(kefir.Errors, want) {
+		t.Errorf("row 3 errors = %v, want %v", kefir.Errors, want)
+	}
+}
+
+func TestParseXLSX(t *testing.T) {
+
+	file := excelize.NewFile()
+	file.SetSheetRow("Sheet1", "A1", &[]interface{}{"barcode", "title", "price", "category"})
+	file.SetSheetRow("Sheet1", "A2", &[]interface{}{"123", "Tea", 9.99, "Drinks"})
+
+	var body bytes.Buffer
+	if err := file.Write(&body); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := Parse(&body, "xlsx")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rows) != 1 || rows[0].Title != "Tea" || rows[0].Price != 9.99 || len(rows[0].Errors) != 0 {
+		t.Errorf("Parse() = %+v", rows[0])
+	}
+}
+
+func TestParseFileErrors(t *testing.T) {
+
+	_, err := Parse(strings.NewReader("title,price\nTea,1\n"), "csv")
+
+	var fileErr *FileError
+	if !errors.As(err, &fileErr) || fileErr.Message != "missing columns: barcode, category" {
+		t.Errorf("Parse() error = %v", err)
+	}
+
+	if _, err = Parse(strings.NewReader(""), "txt"); !errors.As(err, &fileErr) {
+		t.Errorf("Parse() error = %v, want FileError", err)
+	}
+}
+
+func TestParsePrice(t *testing.T) {
+
+	tests := map[string]float64{
+		"1234.5":     1234.5,
+		"1 234,56":   1234.56,
+		"1.234,56":   1234.56,
+		"1,234.56":   1234.56,
+		"1\u00a0000": 1000,
+		"0,005":      0.01,
+		"12500":      12500,
+	}
+
+	for s, want := range tests {
+		got, err := ParsePrice(s)
+		if err != nil || got != want {
+			t.Errorf("ParsePrice(%q) = %v, %v, want %v", s, got, err, want)
+		}
+	}
+
+	if _, err := ParsePrice("12a"); err == nil {
+		t.Errorf("ParsePrice(12a) should fail")
+	}
+}
@@ -0,0 +1,346 @@
+package promotion
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Rule types
+const (
+	TypePercent  = "percent"
+	TypeBuyXGetY = "buy_x_get_y"
+	TypeBundle   = "bundle"
+)
+
+// ClockLayout is the format of happy hour bounds.
+const ClockLayout = "15:04"
+
+const percentDivisor = 100
+
+// Item is a barcode a rule is limited to. Quantity is only used by bundles.
+type Item struct {
+	Barcode  string
+	Quantity int
+}
+
+// Rule is one promotion as the engine sees it.
+//
+// Percent rules take Percent off every matching line. Buy-X-get-Y rules give
+// GetQuantity of every BuyQuantity+GetQuantity matching units away, cheapest
+// first, at Percent off (free when Percent is 0). Bundle rules sell each
+// complete set of Items for BundlePrice.
+//
+// Matching lines are limited by Items, CategoryID and BrandID when set. A rule
+// only fires when the basket is worth at least MinBasketAmount, now is within
+// StartsAt..EndsAt and, when HappyHourFrom/HappyHourTo are set, the clock is
+// within that window (windows may wrap midnight).
+type Rule struct {
+	ID              string
+	Type            string
+	Percent         float64
+	BuyQuantity     int
+	GetQuantity     int
+	BundlePrice     float64
+	CategoryID      string
+	BrandID         string
+	Items           []Item
+	MinBasketAmount float64
+	StartsAt        time.Time
+	EndsAt          time.Time
+	HappyHourFrom   string
+	HappyHourTo     string
+	Priority        int
+	Stackable       bool
+}
+
+// Line is one sale_products row.
+type Line struct {
+	ID         string
+	Barcode    string
+	CategoryID string
+	BrandID    string
+	Quantity   int
+	Price      float64
+}
+
+// Discount is the amount a rule took off a line.
+type Discount struct {
+	LineID      string
+	PromotionID string
+	Amount      float64
+}
+
+type lineState struct {
+	Line
+	net       float64
+	applied   int
+	exclusive bool
+}
+
+// Evaluate applies rules to the basket in priority order (highest first) and
+// returns the discounts they produce.
+//
+// A line touched by a non-stackable rule takes no further discounts, and a
+// non-stackable rule only takes lines nobody has discounted yet. Stackable
+// rules apply on top of each other, each on what is left of the line amount.
+func Evaluate(lines []Line, rules []Rule, now time.Time) []Discount {
+
+	var (
+		basket    float64
+		states    = make([]*lineState, 0, len(lines))
+		discounts []Discount
+	)
+
+	for _, line := range lines {
+		if line.Quantity <= 0 {
+			continue
+		}
+
+		gross := line.Price * float64(line.Quantity)
+		basket += gross
+		states = append(states, &lineState{Line: line, net: gross})
+	}
+
+	sorted := make([]Rule, len(rules))
+	copy(sorted, rules)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority > sorted[j].Priority
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	for _, rule := range sorted {
+		if !rule.activeAt(now) || basket < rule.MinBasketAmount {
+			continue
+		}
+
+		var eligible []*lineState
+		for _, state := range states {
+			if rule.matches(state.Line) && rule.canTake(state) {
+				eligible = append(eligible, state)
+			}
+		}
+
+		if len(eligible) == 0 {
+			continue
+		}
+
+		var amounts map[*lineState]float64
+		switch rule.Type {
+		case TypePercent:
+			amounts = rule.percent(eligible)
+		case TypeBuyXGetY:
+			amounts = rule.buyXGetY(eligible)
+		case TypeBundle:
+			amounts = rule.bundle(eligible)
+		}
+
+		for _, state := range eligible {
+			amount := round(math.Min(amounts[state], state.net))
+			if amount <= 0 {
+				continue
+			}
+
+			state.net -= amount
+			state.applied++
+			if !rule.Stackable {
+				state.exclusive = true
+			}
+
+			discounts = append(discounts, Discount{
+				LineID:      state.ID,
+				PromotionID: rule.ID,
+				Amount:      amount,
+			})
+		}
+	}
+
+	return discounts
+}
+
+func (r Rule) activeAt(now time.Time) bool {
+
+	if !r.StartsAt.IsZero() && now.Before(r.StartsAt) {
+		return false
+	}
+
+	if !r.EndsAt.IsZero() && now.After(r.EndsAt) {
+		return false
+	}
+
+	if r.HappyHourFrom == "" || r.HappyHourTo == "" {
+		return true
+	}
+
+	from, err := time.Parse(ClockLayout, r.HappyHourFrom)
+	if err != nil {
+		return false
+	}
+
+	to, err := time.Parse(ClockLayout, r.HappyHourTo)
+	if err != nil {
+		return false
+	}
+
+	var (
+		clock = now.Hour()*60 + now.Minute()
+		start = from.Hour()*60 + from.Minute()
+		end   = to.Hour()*60 + to.Minute()
+	)
+
+	if start <= end {
+		return clock >= start && clock < end
+	}
+
+	return clock >= start || clock < end
+}
+
+func (r Rule) matches(line Line) bool {
+
+	if len(r.Items) > 0 {
+		found := false
+		for _, item := range r.Items {
+			if item.Barcode == line.Barcode {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	if r.CategoryID != "" && r.CategoryID != line.CategoryID {
+		return false
+	}
+
+	if r.BrandID != "" && r.BrandID != line.BrandID {
+		return false
+	}
+
+	return true
+}
+
+func (r Rule) canTake(state *lineState) bool {
+
+	if state.exclusive || state.net <= 0 {
+		return false
+	}
+
+	return state.applied == 0 || r.Stackable
+}
+
+func (r Rule) percent(lines []*lineState) map[*lineState]float64 {
+
+	var amounts = make(map[*lineState]float64, len(lines))
+	for _, line := range lines {
+		amounts[line] = line.net * r.Percent / percentDivisor
+	}
+
+	return amounts
+}
+
+func (r Rule) buyXGetY(lines []*lineState) map[*lineState]float64 {
+
+	var (
+		amounts = make(map[*lineState]float64, len(lines))
+		units   int
+		group   = r.BuyQuantity + r.GetQuantity
+		percent = r.Percent
+	)
+
+	if r.BuyQuantity <= 0 || r.GetQuantity <= 0 {
+		return amounts
+	}
+
+	if percent <= 0 {
+		percent = percentDivisor
+	}
+
+	for _, line := range lines {
+		units += line.Quantity
+	}
+
+	free := (units / group) * r.GetQuantity
+	if free == 0 {
+		return amounts
+	}
+
+	cheapest := make([]*lineState, len(lines))
+	copy(cheapest, lines)
+	sort.SliceStable(cheapest, func(i, j int) bool {
+		return cheapest[i].unitNet() < cheapest[j].unitNet()
+	})
+
+	for _, line := range cheapest {
+		if free == 0 {
+			break
+		}
+
+		quantity := line.Quantity
+		if quantity > free {
+			quantity = free
+		}
+		free -= quantity
+
+		amounts[line] = line.unitNet() * float64(quantity) * percent / percentDivisor
+	}
+
+	return amounts
+}
+
+func (r Rule) bundle(lines []*lineState) map[*lineState]float64 {
+
+	var (
+		amounts   = make(map[*lineState]float64, len(lines))
+		byBarcode = make(map[string]*lineState, len(lines))
+		sets      = -1
+		regular   float64
+	)
+
+	if len(r.Items) == 0 {
+		return amounts
+	}
+
+	for _, line := range lines {
+		byBarcode[line.Barcode] = line
+	}
+
+	for _, item := range r.Items {
+		line, ok := byBarcode[item.Barcode]
+		if !ok || item.Quantity <= 0 {
+			return amounts
+		}
+
+		count := line.Quantity / item.Quantity
+		if sets < 0 || count < sets {
+			sets = count
+		}
+
+		regular += line.unitNet() * float64(item.Quantity)
+	}
+
+	if sets <= 0 || regular <= r.BundlePrice {
+		return amounts
+	}
+
+	var total = (regular - r.BundlePrice) * float64(sets)
+	for _, item := range r.Items {
+		line := byBarcode[item.Barcode]
+		share := line.unitNet() * float64(item.Quantity) / regular
+		amounts[line] += total * share
+	}
+
+	return amounts
+}
+
+func (s *lineState) unitNet() float64 {
+	return s.net / float64(s.Quantity)
+}
+
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,125 @@
+package promotion
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvaluate(t *testing.T) {
+
+	var now = time.Date(2024, 3, 15, 16, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		lines []Line
+		rules []Rule
+		want  map[string]float64
+	}{
+		{
+			name: "percent off category",
+			lines: []Line{
+				{ID: "milk", Barcode: "1", CategoryID: "dairy", Quantity: 2, Price: 10000},
+				{ID: "bread", Barcode: "2", CategoryID: "bakery", Quantity: 1, Price: 4000},
+			},
+			rules: []Rule{
+				{ID: "p1", Type: TypePercent, Percent: 10, CategoryID: "dairy"},
+			},
+			want: map[string]float64{"milk/p1": 2000},
+		},
+		{
+			name: "buy 2 get 1 gives the cheapest unit away",
+			lines: []Line{
+				{ID: "a", Barcode: "1", Quantity: 2, Price: 5000},
+				{ID: "b", Barcode: "2", Quantity: 1, Price: 3000},
+			},
+			rules: []Rule{
+				{ID: "b2g1", Type: TypeBuyXGetY, BuyQuantity: 2, GetQuantity: 1},
+			},
+			want: map[string]float64{"b/b2g1": 3000},
+		},
+		{
+			name: "bundle price spread across its items",
+			lines: []Line{
+				{ID: "chips", Barcode: "10", Quantity: 1, Price: 6000},
+				{ID: "cola", Barcode: "20", Quantity: 1, Price: 4000},
+			},
+			rules: []Rule{
+				{ID: "combo", Type: TypeBundle, BundlePrice: 8000, Items: []Item{{Barcode: "10", Quantity: 1}, {Barcode: "20", Quantity: 1}}},
+			},
+			want: map[string]float64{"chips/combo": 1200, "cola/combo": 800},
+		},
+		{
+			name: "min basket not reached",
+			lines: []Line{
+				{ID: "a", Barcode: "1", Quantity: 1, Price: 5000},
+			},
+			rules: []Rule{
+				{ID: "p1", Type: TypePercent, Percent: 5, MinBasketAmount: 100000},
+			},
+			want: map[string]float64{},
+		},
+		{
+			name: "happy hour outside the window",
+			lines: []Line{
+				{ID: "a", Barcode: "1", Quantity: 1, Price: 5000},
+			},
+			rules: []Rule{
+				{ID: "p1", Type: TypePercent, Percent: 50, HappyHourFrom: "18:00", HappyHourTo: "20:00"},
+			},
+			want: map[string]float64{},
+		},
+		{
+			name: "exclusive higher priority blocks the rest",
+			lines: []Line{
+				{ID: "a", Barcode: "1", CategoryID: "dairy", Quantity: 1, Price: 10000},
+			},
+			rules: []Rule{
+				{ID: "low", Type: TypePercent, Percent: 5, Priority: 1, Stackable: true},
+				{ID: "high", Type: TypePercent, Percent: 20, Priority: 10},
+			},
+			want: map[string]float64{"a/high": 2000},
+		},
+		{
+			name: "stackable rules apply on the remaining amount",
+			lines: []Line{
+				{ID: "a", Barcode: "1", Quantity: 1, Price: 10000},
+			},
+			rules: []Rule{
+				{ID: "first", Type: TypePercent, Percent: 10, Priority: 2, Stackable: true},
+				{ID: "second", Type: TypePercent, Percent: 10, Priority: 1, Stackable: true},
+			},
+			want: map[string]float64{"a/first": 1000, "a/second": 900},
+		},
+		{
+			// the line discount stored is the sum, well past 999.99
+			name: "stacked discounts on a line in millions of sum",
+			lines: []Line{
+				{ID: "tv", Barcode: "1", Quantity: 2, Price: 4500000},
+			},
+			rules: []Rule{
+				{ID: "first", Type: TypePercent, Percent: 15, Priority: 2, Stackable: true},
+				{ID: "second", Type: TypePercent, Percent: 5, Priority: 1, Stackable: true},
+			},
+			want: map[string]float64{"tv/first": 1350000, "tv/second": 382500},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := map[string]float64{}
+			for _, discount := range Evaluate(test.lines, test.rules, now) {
+				got[discount.LineID+"/"+discount.PromotionID] = discount.Amount
+			}
+
+			if len(got) != len(test.want) {
+				t.Fatalf("Evaluate() = %v, want %v", got, test.want)
+			}
+
+			for key, amount := range test.want {
+				if got[key] != amount {
+					t.Errorf("Evaluate()[%s] = %v, want %v", key, got[key], amount)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+// Package purchaseorder holds the purchase order state machine and the
+// delivery arithmetic used when goods are received against an order.
+package purchaseorder
+
+import (
+	"math"
+
+	"market_system/config"
+)
+
+// Delivery status of a single order line.
+const (
+	LinePending  = "pending"
+	LineShort    = "short"
+	LineComplete = "complete"
+	LineOver     = "over"
+)
+
+// transitions lists the statuses an order may move to from each status.
+// Receiving moves an order between sent, partially_received and received;
+// closing accepts whatever was delivered.
+var transitions = map[string][]string{
+	config.PurchaseOrderDraft:             {config.PurchaseOrderSent},
+	config.PurchaseOrderSent:              {config.PurchaseOrderPartiallyReceived, config.PurchaseOrderReceived, config.PurchaseOrderClosed},
+	config.PurchaseOrderPartiallyReceived: {config.PurchaseOrderPartiallyReceived, config.PurchaseOrderReceived, config.PurchaseOrderClosed},
+	config.PurchaseOrderReceived:          {config.PurchaseOrderReceived, config.PurchaseOrderClosed},
+}
+
+// CanTransition reports whether an order in status from may move to to.
+func CanTransition(from, to string) bool {
+	for _, status := range transitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// From returns the statuses an order may move to status from.
+func From(status string) []string {
+	var from []string
+	for current, next := range transitions {
+		for _, s := range next {
+			if s == status {
+				from = append(from, current)
+			}
+		}
+	}
+	return from
+}
+
+// Line is what was ordered and what has been received so far.
+type Line struct {
+	Ordered  int64
+	Received int64
+}
+
+// LineStatus tells whether a line is still waiting, short, complete or
+// over-delivered.
+func LineStatus(line Line) string {
+	switch {
+	case line.Received == 0:
+		return LinePending
+	case line.Received < line.Ordered:
+		return LineShort
+	case line.Received == line.Ordered:
+		return LineComplete
+	default:
+		return LineOver
+	}
+}
+
+// ReceiptStatus is the order status after a receipt: received once every
+// line got at least what was ordered, partially received otherwise.
+func ReceiptStatus(lines []Line) string {
+	for _, line := range lines {
+		if line.Received < line.Ordered {
+			return config.PurchaseOrderPartiallyReceived
+		}
+	}
+	return config.PurchaseOrderReceived
+}
+
+// Rate is part/whole rounded to four decimals, zero when nothing was expected.
+func Rate(part, whole int64) float64 {
+	if whole <= 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(whole)*10000) / 10000
+}
@@ -0,0 +1,90 @@
+package purchaseorder
+
+import (
+	"sort"
+	"testing"
+
+	"market_system/config"
+)
+
+func TestCanTransition(t *testing.T) {
+
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{config.PurchaseOrderDraft, config.PurchaseOrderSent, true},
+		{config.PurchaseOrderDraft, config.PurchaseOrderReceived, false},
+		{config.PurchaseOrderSent, config.PurchaseOrderPartiallyReceived, true},
+		{config.PurchaseOrderPartiallyReceived, config.PurchaseOrderReceived, true},
+		{config.PurchaseOrderReceived, config.PurchaseOrderClosed, true},
+		{config.PurchaseOrderClosed, config.PurchaseOrderSent, false},
+		{config.PurchaseOrderReceived, config.PurchaseOrderDraft, false},
+	}
+
+	for _, test := range tests {
+		if got := CanTransition(test.from, test.to); got != test.want {
+			t.Errorf("CanTransition(%s, %s) = %v, want %v", test.from, test.to, got, test.want)
+		}
+	}
+}
+
+func TestFrom(t *testing.T) {
+
+	got := From(config.PurchaseOrderClosed)
+	sort.Strings(got)
+
+	want := []string{config.PurchaseOrderPartiallyReceived, config.PurchaseOrderReceived, config.PurchaseOrderSent}
+	if len(got) != len(want) {
+		t.Fatalf("From(closed) = %v, want %v", got, want)
+	}
+
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("From(closed) = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestLineStatus(t *testing.T) {
+
+	tests := []struct {
+		line Line
+		want string
+	}{
+		{Line{Ordered: 10, Received: 0}, LinePending},
+		{Line{Ordered: 10, Received: 4}, LineShort},
+		{Line{Ordered: 10, Received: 10}, LineComplete},
+		{Line{Ordered: 10, Received: 12}, LineOver},
+	}
+
+	for _, test := range tests {
+		if got := LineStatus(test.line); got != test.want {
+			t.Errorf("LineStatus(%+v) = %s, want %s", test.line, got, test.want)
+		}
+	}
+}
+
+func TestReceiptStatus(t *testing.T) {
+
+	partial := ReceiptStatus([]Line{{Ordered: 10, Received: 10}, {Ordered: 5, Received: 2}})
+	if partial != config.PurchaseOrderPartiallyReceived {
+		t.Errorf("ReceiptStatus(short line) = %s, want %s", partial, config.PurchaseOrderPartiallyReceived)
+	}
+
+	full := ReceiptStatus([]Line{{Ordered: 10, Received: 12}, {Ordered: 5, Received: 5}})
+	if full != config.PurchaseOrderReceived {
+		t.Errorf("ReceiptStatus(all delivered) = %s, want %s", full, config.PurchaseOrderReceived)
+	}
+}
+
+func TestRate(t *testing.T) {
+
+	if got := Rate(2, 3); got != 0.6667 {
+		t.Errorf("Rate(2, 3) = %v, want 0.6667", got)
+	}
+
+	if got := Rate(5, 0); got != 0 {
+		t.Errorf("Rate(5, 0) = %v, want 0", got)
+	}
+}
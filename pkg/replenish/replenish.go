@@ -0,0 +1,55 @@
+// Package replenish turns branch stock levels into order quantities.
+//
+// A product is reordered once its stock position (on hand plus still on
+// order) falls to the reorder point, and is ordered up to the target level.
+// The reorder point is the configured minimum, raised to what the branch is
+// expected to sell while an order is on its way; the target is the
+// configured maximum, never below the reorder point.
+package replenish
+
+import "math"
+
+// Item is one product of a branch.
+type Item struct {
+	OnHand        int64
+	OnOrder       int64
+	AvgDailySales float64
+	Min           int64
+	Max           int64
+}
+
+// AvgDailySales is sold spread over days, rounded to two decimals.
+func AvgDailySales(sold int64, days int64) float64 {
+	if days <= 0 {
+		return 0
+	}
+	return math.Round(float64(sold)/float64(days)*100) / 100
+}
+
+// ReorderPoint is the stock position at or below which item is reordered.
+func ReorderPoint(item Item, leadDays int64) int64 {
+	demand := int64(math.Ceil(item.AvgDailySales * float64(leadDays)))
+	if demand > item.Min {
+		return demand
+	}
+	return item.Min
+}
+
+// Target is the stock position an order brings item up to.
+func Target(item Item, leadDays int64) int64 {
+	point := ReorderPoint(item, leadDays)
+	if item.Max > point {
+		return item.Max
+	}
+	return point
+}
+
+// Suggest is the quantity to order for item, zero while its stock position
+// is above the reorder point.
+func Suggest(item Item, leadDays int64) int64 {
+	position := item.OnHand + item.OnOrder
+	if position > ReorderPoint(item, leadDays) {
+		return 0
+	}
+	return Target(item, leadDays) - position
+}
@@ -0,0 +1,39 @@
+package replenish
+
+import "testing"
+
+func TestAvgDailySales(t *testing.T) {
+
+	if got := AvgDailySales(100, 30); got != 3.33 {
+		t.Errorf("AvgDailySales(100, 30) = %v, want 3.33", got)
+	}
+
+	if got := AvgDailySales(10, 0); got != 0 {
+		t.Errorf("AvgDailySales(10, 0) = %v, want 0", got)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		item     Item
+		leadDays int64
+		want     int64
+	}{
+		{"above min", Item{OnHand: 12, Min: 10, Max: 40}, 0, 0},
+		{"at min", Item{OnHand: 10, Min: 10, Max: 40}, 0, 30},
+		{"below min", Item{OnHand: 3, Min: 10, Max: 40}, 0, 37},
+		{"covered by open order", Item{OnHand: 3, OnOrder: 20, Min: 10, Max: 40}, 0, 0},
+		{"partly on order", Item{OnHand: 3, OnOrder: 5, Min: 10, Max: 40}, 0, 32},
+		{"negative stock", Item{OnHand: -4, Min: 0, Max: 10}, 0, 14},
+		{"lead time demand raises reorder point", Item{OnHand: 15, AvgDailySales: 4.5, Min: 10, Max: 40}, 4, 25},
+		{"lead time demand above max", Item{OnHand: 5, AvgDailySales: 10, Min: 0, Max: 20}, 3, 25},
+	}
+
+	for _, test := range tests {
+		if got := Suggest(test.item, test.leadDays); got != test.want {
+			t.Errorf("%s: Suggest() = %d, want %d", test.name, got, test.want)
+		}
+	}
+}
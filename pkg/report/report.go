@@ -0,0 +1,94 @@
+// Package report holds the arithmetic shared by the sales reports: derived
+// metrics of an aggregate and the comparison with the preceding period.
+package report
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Metrics are the additive totals of a group of sold lines. Sales counts
+// distinct sales, so it can not be summed across groups that share a sale.
+type Metrics struct {
+	Revenue float64
+	Cost    float64
+	Sales   int64
+	Units   int64
+}
+
+// Previous is the period of the same length that ends where [from, to)
+// starts.
+func Previous(from, to time.Time) (time.Time, time.Time) {
+	return from.Add(-to.Sub(from)), from
+}
+
+// AvgBasket is the revenue per sale.
+func AvgBasket(m Metrics) float64 {
+	if m.Sales == 0 {
+		return 0
+	}
+
+	return round(m.Revenue / float64(m.Sales))
+}
+
+// GrossMargin is revenue minus cost of the goods sold.
+func GrossMargin(m Metrics) float64 {
+	return round(m.Revenue - m.Cost)
+}
+
+// MarginPercent is the gross margin as a percentage of revenue.
+func MarginPercent(m Metrics) float64 {
+	if m.Revenue == 0 {
+		return 0
+	}
+
+	return round((m.Revenue - m.Cost) / m.Revenue * 100)
+}
+
+// Change is the growth of current over previous in percent. It reports false
+// when previous is zero and growth is undefined.
+func Change(current, previous float64) (float64, bool) {
+	if previous == 0 {
+		return 0, false
+	}
+
+	return round((current - previous) / math.Abs(previous) * 100), true
+}
+
+// ItemsPerMinute is the scanning speed of a cashier over the minutes a shift
+// was open.
+func ItemsPerMinute(items int64, minutes float64) float64 {
+	if minutes <= 0 {
+		return 0
+	}
+
+	return round(float64(items) / minutes)
+}
+
+// CashVariance is counted cash minus the cash the drawer should hold: the
+// opening float plus the cash taken during the shift. Negative is a shortage.
+func CashVariance(opening, taken, counted float64) float64 {
+	return round(counted - opening - taken)
+}
+
+// AgingLabel names bucket i of the age bounds in days: with bounds 30, 60,
+// 90 the buckets are 0-29, 30-59, 60-89 and 90+. Bucket numbers are those
+// of PostgreSQL's width_bucket over the same bounds.
+func AgingLabel(bounds []int64, i int) string {
+
+	var from int64
+	if i > 0 {
+		from = bounds[i-1]
+	}
+
+	if i >= len(bounds) {
+		return fmt.Sprintf("%d+", from)
+	}
+
+	return fmt.Sprintf("%d-%d", from, bounds[i]-1)
+}
+
+func round(x float64) float64 {
+	return math.Round(x*100) / 100
+}
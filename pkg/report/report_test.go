@@ -0,0 +1,105 @@
+package report
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPrevious(t *testing.T) {
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 8, 0, 0, 0, 0, time.UTC)
+
+	prevFrom, prevTo := Previous(from, to)
+	if !prevFrom.Equal(time.Date(2024, 2, 23, 0, 0, 0, 0, time.UTC)) || !prevTo.Equal(from) {
+		t.Errorf("Previous() = %v, %v", prevFrom, prevTo)
+	}
+}
+
+func TestMetrics(t *testing.T) {
+
+	m := Metrics{Revenue: 1000, Cost: 750, Sales: 3, Units: 12}
+
+	if got := AvgBasket(m); got != 333.33 {
+		t.Errorf("AvgBasket() = %v, want 333.33", got)
+	}
+
+	if got := GrossMargin(m); got != 250 {
+		t.Errorf("GrossMargin() = %v, want 250", got)
+	}
+
+	if got := MarginPercent(m); got != 25 {
+		t.Errorf("MarginPercent() = %v, want 25", got)
+	}
+
+	if AvgBasket(Metrics{}) != 0 || MarginPercent(Metrics{}) != 0 {
+		t.Errorf("empty metrics should give zero ratios")
+	}
+}
+
+func TestChange(t *testing.T) {
+
+	tests := []struct {
+		current, previous float64
+		want              float64
+		ok                bool
+	}{
+		{150, 100, 50, true},
+		{50, 100, -50, true},
+		{100, 100, 0, true},
+		{10, 0, 0, false},
+		{-50, -100, 50, true},
+	}
+
+	for _, test := range tests {
+		got, ok := Change(test.current, test.previous)
+		if got != test.want || ok != test.ok {
+			t.Errorf("Change(%v, %v) = %v, %v, want %v, %v", test.current, test.previous, got, ok, test.want, test.ok)
+		}
+	}
+}
+
+func TestItemsPerMinute(t *testing.T) {
+
+	if got := ItemsPerMinute(90, 60); got != 1.5 {
+		t.Errorf("ItemsPerMinute() = %v, want 1.5", got)
+	}
+
+	if got := ItemsPerMinute(10, 0); got != 0 {
+		t.Errorf("ItemsPerMinute() of an unopened shift = %v, want 0", got)
+	}
+}
+
+func TestCashVariance(t *testing.T) {
+
+	tests := []struct {
+		opening, taken, counted float64
+		want                    float64
+	}{
+		{100, 500, 600, 0},
+		{100, 500, 580.5, -19.5},
+		{0, 250, 260, 10},
+	}
+
+	for _, test := range tests {
+		if got := CashVariance(test.opening, test.taken, test.counted); got != test.want {
+			t.Errorf("CashVariance(%v, %v, %v) = %v, want %v", test.opening, test.taken, test.counted, got, test.want)
+		}
+	}
+}
+
+func TestAgingLabel(t *testing.T) {
+
+	bounds := []int64{30, 60, 90}
+	want := []string{"0-29", "30-59", "60-89", "90+"}
+
+	for i, label := range want {
+		if got := AgingLabel(bounds, i); got != label {
+			t.Errorf("AgingLabel(%d) = %q, want %q", i, got, label)
+		}
+	}
+
+	if got := AgingLabel(nil, 0); got != "0+" {
+		t.Errorf("AgingLabel() without bounds = %q, want 0+", got)
+	}
+}
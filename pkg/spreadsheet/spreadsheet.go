@@ -0,0 +1,105 @@
+// Package spreadsheet reads the rows of CSV and XLSX files people export
+// from spreadsheets.
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Read returns the rows of a csv or xlsx file. A CSV file is comma or
+// semicolon separated and may start with a byte order mark; of an XLSX file
+// the first sheet is read.
+func Read(r io.Reader, format string) ([][]string, error) {
+
+	switch format {
+	case "csv":
+		return readCSV(r)
+	case "xlsx":
+		return readXLSX(r)
+	}
+
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+// ParseNumber reads a number written with a comma or a point for decimals and
+// spaces, no-break spaces or the other separator grouping thousands.
+func ParseNumber(s string) (float64, error) {
+
+	s = strings.NewReplacer(" ", "", "\u00a0", "", "\u202f", "").Replace(s)
+
+	var comma, point = strings.LastIndex(s, ","), strings.LastIndex(s, ".")
+	switch {
+	case comma >= 0 && point >= 0 && comma > point:
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.Replace(s, ",", ".", 1)
+	case comma >= 0 && point >= 0:
+		s = strings.ReplaceAll(s, ",", "")
+	case comma >= 0:
+		s = strings.Replace(s, ",", ".", 1)
+	}
+
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+
+	return value, nil
+}
+
+func readCSV(r io.Reader) ([][]string, error) {
+
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	body = bytes.TrimPrefix(body, []byte("\ufeff"))
+
+	var reader = csv.NewReader(bytes.NewReader(body))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var header = body
+	if i := bytes.IndexByte(body, '\n'); i >= 0 {
+		header = body[:i]
+	}
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv: %w", err)
+	}
+
+	return records, nil
+}
+
+func readXLSX(r io.Reader) ([][]string, error) {
+
+	file, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("invalid xlsx: %w", err)
+	}
+	defer file.Close()
+
+	var sheets = file.GetSheetList()
+	if len(sheets) == 0 {
+		return nil, errors.New("xlsx has no sheets")
+	}
+
+	records, err := file.GetRows(sheets[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid xlsx: %w", err)
+	}
+
+	return records, nil
+}
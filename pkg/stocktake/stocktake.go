@@ -0,0 +1,72 @@
+// Package stocktake holds the variance arithmetic of a physical inventory
+// count.
+//
+// Expected quantities are snapshotted when a count is opened, but the branch
+// keeps selling while the shelves are counted. Each line therefore carries
+// the net stock movement between the snapshot and the moment the barcode was
+// counted, so a sale made during the count is not mistaken for a shortage.
+package stocktake
+
+import "math"
+
+// Line is one barcode of a count.
+type Line struct {
+	Expected int64   // quantity in stock when the count was opened
+	Moved    int64   // net movement between the snapshot and the count
+	Counted  int64   // merged quantity scanned by all counters
+	Cost     float64 // income price of one item
+}
+
+// Summary totals the variance of a count. Shortage quantities and costs are
+// positive numbers; Net is surplus cost minus shortage cost.
+type Summary struct {
+	Lines            int
+	SurplusQuantity  int64
+	SurplusCost      float64
+	ShortageQuantity int64
+	ShortageCost     float64
+	Net              float64
+}
+
+// Book is the quantity the branch should have had when the line was counted.
+func Book(line Line) int64 {
+	return line.Expected + line.Moved
+}
+
+// Variance is counted minus book quantity: positive for a surplus, negative
+// for a shortage.
+func Variance(line Line) int64 {
+	return line.Counted - Book(line)
+}
+
+// CostImpact is the variance valued at the line cost, rounded to cents.
+func CostImpact(line Line) float64 {
+	return round(float64(Variance(line)) * line.Cost)
+}
+
+// Summarize totals the variance of lines.
+func Summarize(lines []Line) Summary {
+
+	var summary = Summary{Lines: len(lines)}
+	for _, line := range lines {
+		variance := Variance(line)
+		switch {
+		case variance > 0:
+			summary.SurplusQuantity += variance
+			summary.SurplusCost += CostImpact(line)
+		case variance < 0:
+			summary.ShortageQuantity -= variance
+			summary.ShortageCost -= CostImpact(line)
+		}
+	}
+
+	summary.SurplusCost = round(summary.SurplusCost)
+	summary.ShortageCost = round(summary.ShortageCost)
+	summary.Net = round(summary.SurplusCost - summary.ShortageCost)
+
+	return summary
+}
+
+func round(x float64) float64 {
+	return math.Round(x*100) / 100
+}
@@ -0,0 +1,57 @@
+package stocktake
+
+import "testing"
+
+func TestVariance(t *testing.T) {
+
+	tests := []struct {
+		name string
+		line Line
+		want int64
+	}{
+		{"exact", Line{Expected: 10, Counted: 10}, 0},
+		{"shortage", Line{Expected: 10, Counted: 7}, -3},
+		{"surplus", Line{Expected: 10, Counted: 12}, 2},
+		{"sold during count", Line{Expected: 10, Moved: -4, Counted: 6}, 0},
+		{"received during count", Line{Expected: 10, Moved: 5, Counted: 14}, -1},
+		{"found item", Line{Expected: 0, Counted: 3}, 3},
+	}
+
+	for _, test := range tests {
+		if got := Variance(test.line); got != test.want {
+			t.Errorf("%s: Variance() = %d, want %d", test.name, got, test.want)
+		}
+	}
+}
+
+func TestCostImpact(t *testing.T) {
+
+	if got := CostImpact(Line{Expected: 10, Counted: 7, Cost: 12.5}); got != -37.5 {
+		t.Errorf("CostImpact() = %v, want -37.5", got)
+	}
+
+	if got := CostImpact(Line{Expected: 1, Counted: 4, Cost: 0.1}); got != 0.3 {
+		t.Errorf("CostImpact() = %v, want 0.3", got)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+
+	got := Summarize([]Line{
+		{Expected: 10, Counted: 7, Cost: 2},
+		{Expected: 5, Moved: -1, Counted: 6, Cost: 3},
+		{Expected: 8, Moved: -2, Counted: 6, Cost: 100},
+	})
+
+	want := Summary{
+		Lines:            3,
+		SurplusQuantity:  2,
+		SurplusCost:      6,
+		ShortageQuantity: 3,
+		ShortageCost:     6,
+		Net:              0,
+	}
+	if got != want {
+		t.Errorf("Summarize() = %+v, want %+v", got, want)
+	}
+}
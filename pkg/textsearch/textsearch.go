@@ -0,0 +1,99 @@
+// Package textsearch normalizes product names for search, so that a name
+// written in Uzbek Latin, Uzbek Cyrillic or Russian is found by a query in
+// any of them.
+//
+// Key turns a text into its search key: lower case Latin letters and digits
+// separated by single spaces. Cyrillic is transliterated the Uzbek way and
+// letters that are spelled differently across the alphabets are folded
+// together, so "молоко" and "moloko", "ўрик" and "o‘rik", "қатиқ", "катык"
+// and "qatiq" give the same key. Postgres indexes the keys for trigram and
+// full-text matching; queries are keyed the same way before they are run.
+package textsearch
+
+import (
+	"strings"
+	"unicode"
+)
+
+// cyrillic is the Latin spelling of the Cyrillic letters, following the
+// Uzbek Latin alphabet. Ъ and ь are dropped.
+var cyrillic = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "j", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "x", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "sh", 'ъ': "",
+	'ы': "i", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+	'ў': "o", 'қ': "q", 'ғ': "g", 'ҳ': "h",
+}
+
+// apostrophes are the marks written in oʻ, gʻ and the Uzbek tutuq belgisi.
+// They are dropped, as the Cyrillic ў and ғ give plain o and g.
+const apostrophes = "'`ʻʼ‘’´"
+
+// fold spells alike what the alphabets spell differently: Russian kh and zh
+// for х and ж, q and h of Uzbek for қ and ҳ that Russian writes as к and х,
+// and c and w of foreign names. Sh and ch are kept as they are.
+var fold = strings.NewReplacer(
+	"sh", "sh",
+	"ch", "ch",
+	"kh", "x",
+	"zh", "j",
+	"h", "x",
+	"q", "k",
+	"c", "k",
+	"w", "v",
+)
+
+// Key returns the search key of a text.
+func Key(text string) string {
+
+	var b strings.Builder
+	b.Grow(len(text))
+
+	var space = true
+	for _, r := range strings.ToLower(text) {
+		if strings.ContainsRune(apostrophes, r) {
+			continue
+		}
+
+		if latin, ok := cyrillic[r]; ok {
+			b.WriteString(latin)
+			space = false
+			continue
+		}
+
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+			space = false
+			continue
+		}
+
+		if !space {
+			b.WriteByte(' ')
+			space = true
+		}
+	}
+
+	return strings.TrimSpace(fold.Replace(b.String()))
+}
+
+// Words returns the words of the search key of a text.
+func Words(text string) []string {
+	return strings.Fields(Key(text))
+}
+
+// Prefix returns the full-text query, for to_tsquery with the simple
+// configuration, matching the texts that have a word starting with each
+// word of text, and an empty string when text has no words. It is the query
+// of an as-you-type lookup, where the last word is still being typed. Words
+// are made of letters and digits only, so the query holds no operators of
+// its own.
+func Prefix(text string) string {
+
+	var words = Words(text)
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+
+	return strings.Join(words, " & ")
+}
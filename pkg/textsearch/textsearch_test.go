@@ -0,0 +1,58 @@
+package textsearch
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Молоко 1л", "moloko 1l"},
+		{"moloko 1L", "moloko 1l"},
+		{"Ўсимлик ёғи", "osimlik yogi"},
+		{"O‘simlik yog‘i", "osimlik yogi"},
+		{"O'simlik yog`i", "osimlik yogi"},
+		{"Қатиқ", "katik"},
+		{"Катык", "katik"},
+		{"qatiq", "katik"},
+		{"Ҳолва", "xolva"},
+		{"Халва", "xalva"},
+		{"halva", "xalva"},
+		{"Khalva", "xalva"},
+		{"Шоколад", "shokolad"},
+		{"shokolad", "shokolad"},
+		{"Чой", "choy"},
+		{"Журнал", "jurnal"},
+		{"zhurnal", "jurnal"},
+		{"Coca-Cola 0,5", "koka kola 0 5"},
+		{"Кока-кола 0,5", "koka kola 0 5"},
+		{"  Подъезд   №5 ", "podezd 5"},
+		{"", ""},
+		{"%_\\", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Key(tt.text); got != tt.want {
+			t.Errorf("Key(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestPrefix(t *testing.T) {
+
+	if got, want := Prefix("Молоко 3,2"), "moloko:* & 3:* & 2:*"; got != want {
+		t.Errorf("Prefix() = %q, want %q", got, want)
+	}
+
+	if got := Prefix("'): | & !"); got != "" {
+		t.Errorf("Prefix() = %q, want empty", got)
+	}
+
+	if got, want := Words("сут sut"), []string{"sut", "sut"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Words() = %q, want %q", got, want)
+	}
+}
@@ -3,9 +3,9 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	"fmt"
 
 	"market_system/models"
+	"market_system/pkg/criteria"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v4/pgxpool"
@@ -102,29 +102,36 @@ func (r *branchRepo) GetByID(ctx context.Context, req *models.BranchPrimaryKey)
 		UpdatedAt:  UpdatedAt.String,
 	}, nil
 }
+
+var branchSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":          {Column: "id", Type: criteria.UUID},
+		"branch_code": {Column: "branch_code", Type: criteria.Text},
+		"name":        {Column: "name", Type: criteria.Text},
+		"address":     {Column: "address", Type: criteria.Text},
+		"phone":       {Column: "phone", Type: criteria.Text},
+		"created_at":  {Column: "created_at", Type: criteria.Time},
+		"updated_at":  {Column: "updated_at", Type: criteria.Time},
+	},
+	Search: []string{"name", "phone"},
+	Sort:   []criteria.Sort{{Field: "name", Desc: true}},
+}
+
 func (r *branchRepo) GetList(ctx context.Context, req *models.GetListBranchRequest) (*models.GetListBranchResponse, error) {
 	var (
-		resp   models.GetListBranchResponse
-		where  = " WHERE TRUE"
-		offset = " OFFSET 0"
-		limit  = " LIMIT 10"
-		sort   = " ORDER BY name DESC"
+		resp  models.GetListBranchResponse
+		where = branchSchema.Query()
 	)
 
-	if req.Offset > 0 {
-		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
-	}
-
-	if req.Limit > 0 {
-		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
-	}
-
-	if len(req.Search) > 0 {
-		where += " AND (name ILIKE '%" + req.Search + "%' OR phone ILIKE '%" + req.Search + "%')"
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
 	}
+	where.Search(req.Search)
 
-	if len(req.Query) > 0 {
-		where += req.Query
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
 	}
 
 	var query = `
@@ -140,8 +147,8 @@ func (r *branchRepo) GetList(ctx context.Context, req *models.GetListBranchReque
 		FROM branch
 	`
 
-	query += where + sort + offset + limit
-	rows, err := r.db.Query(ctx, query)
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,388 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+	"market_system/storage"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type branchPriceRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewBranchPriceRepo(db *pgxpool.Pool) *branchPriceRepo {
+	return &branchPriceRepo{
+		db: db,
+	}
+}
+
+func (r *branchPriceRepo) Create(ctx context.Context, req *models.CreateBranchPrice) (*models.BranchPrice, error) {
+
+	var (
+		branchPriceID = uuid.New().String()
+		query         = `
+			INSERT INTO branch_price(
+				id,
+				branch_id,
+				product_id,
+				price,
+				effective_from,
+				updated_at
+			) VALUES ($1, $2, $3, $4, COALESCE($5::timestamp, NOW()), NOW())`
+	)
+
+	_, err := r.db.Exec(ctx,
+		query,
+		branchPriceID,
+		req.BranchID,
+		req.ProductID,
+		req.Price,
+		helpers.NewNullString(req.EffectiveFrom),
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, &models.BranchPricePrimaryKey{Id: branchPriceID})
+}
+
+func (r *branchPriceRepo) GetByID(ctx context.Context, req *models.BranchPricePrimaryKey) (*models.BranchPrice, error) {
+
+	var (
+		query = `
+			SELECT
+				id,
+				branch_id,
+				product_id,
+				price,
+				effective_from,
+				price_change_id,
+				created_at,
+				updated_at
+			FROM branch_price
+			WHERE id = $1
+		`
+	)
+
+	var (
+		ID            sql.NullString
+		BranchID      sql.NullString
+		ProductID     sql.NullString
+		Price         sql.NullFloat64
+		EffectiveFrom sql.NullString
+		PriceChangeID sql.NullString
+		CreatedAt     sql.NullString
+		UpdatedAt     sql.NullString
+	)
+
+	err := r.db.QueryRow(ctx, query, req.Id).Scan(
+		&ID,
+		&BranchID,
+		&ProductID,
+		&Price,
+		&EffectiveFrom,
+		&PriceChangeID,
+		&CreatedAt,
+		&UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BranchPrice{
+		Id:            ID.String,
+		BranchID:      BranchID.String,
+		ProductID:     ProductID.String,
+		Price:         Price.Float64,
+		EffectiveFrom: EffectiveFrom.String,
+		PriceChangeID: PriceChangeID.String,
+		CreatedAt:     CreatedAt.String,
+		UpdatedAt:     UpdatedAt.String,
+	}, nil
+}
+
+var branchPriceSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":              {Column: "id", Type: criteria.UUID},
+		"branch_id":       {Column: "branch_id", Type: criteria.UUID},
+		"product_id":      {Column: "product_id", Type: criteria.UUID},
+		"price":           {Column: "price", Type: criteria.Number},
+		"effective_from":  {Column: "effective_from", Type: criteria.Time},
+		"price_change_id": {Column: "price_change_id", Type: criteria.UUID},
+		"created_at":      {Column: "created_at", Type: criteria.Time},
+		"updated_at":      {Column: "updated_at", Type: criteria.Time},
+	},
+	Sort: []criteria.Sort{{Field: "effective_from", Desc: true}},
+}
+
+func (r *branchPriceRepo) GetList(ctx context.Context, req *models.GetListBranchPriceRequest) (*models.GetListBranchPriceResponse, error) {
+	var (
+		resp  models.GetListBranchPriceResponse
+		where = branchPriceSchema.Query()
+	)
+
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var query = `
+		SELECT
+			COUNT(*) OVER(),
+			id,
+			branch_id,
+			product_id,
+			price,
+			effective_from,
+			price_change_id,
+			created_at,
+			updated_at
+		FROM branch_price
+	`
+
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			ID            sql.NullString
+			BranchID      sql.NullString
+			ProductID     sql.NullString
+			Price         sql.NullFloat64
+			EffectiveFrom sql.NullString
+			PriceChangeID sql.NullString
+			CreatedAt     sql.NullString
+			UpdatedAt     sql.NullString
+		)
+
+		err = rows.Scan(
+			&resp.Count,
+			&ID,
+			&BranchID,
+			&ProductID,
+			&Price,
+			&EffectiveFrom,
+			&PriceChangeID,
+			&CreatedAt,
+			&UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.BranchPrices = append(resp.BranchPrices, &models.BranchPrice{
+			Id:            ID.String,
+			BranchID:      BranchID.String,
+			ProductID:     ProductID.String,
+			Price:         Price.Float64,
+			EffectiveFrom: EffectiveFrom.String,
+			PriceChangeID: PriceChangeID.String,
+			CreatedAt:     CreatedAt.String,
+			UpdatedAt:     UpdatedAt.String,
+		})
+	}
+
+	return &resp, nil
+}
+
+func (r *branchPriceRepo) Update(ctx context.Context, req *models.UpdateBranchPrice) (int64, error) {
+
+	query := `
+		UPDATE branch_price
+			SET
+				price = $2,
+				effective_from = COALESCE($3::timestamp, effective_from),
+				updated_at = NOW()
+		WHERE id = $1
+	`
+	rowsAffected, err := r.db.Exec(ctx,
+		query,
+		req.Id,
+		req.Price,
+		helpers.NewNullString(req.EffectiveFrom),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
+
+func (r *branchPriceRepo) Delete(ctx context.Context, req *models.BranchPricePrimaryKey) error {
+	_, err := r.db.Exec(ctx, "DELETE FROM branch_price WHERE id = $1", req.Id)
+	return err
+}
+
+// Resolve returns the price in effect at req.At (now when empty). The branch
+// price with the latest effective_from not after that moment wins; when the
+// branch has no price for the product the global product.price is used. A
+// request naming neither a product id nor a barcode fails with
+// storage.ErrPriceProductMissing.
+func (r *branchPriceRepo) Resolve(ctx context.Context, req *models.ResolvePriceRequest) (*models.ResolvedPrice, error) {
+
+	if len(req.ProductID) == 0 && len(req.Barcode) == 0 {
+		return nil, storage.ErrPriceProductMissing
+	}
+
+	var (
+		query = `
+			SELECT
+				p.id,
+				COALESCE(p.barcode, ''),
+				COALESCE(bp.price, p.price, 0),
+				bp.id,
+				bp.effective_from
+			FROM product AS p
+			LEFT JOIN LATERAL (
+				SELECT
+					id,
+					price,
+					effective_from
+				FROM branch_price
+				WHERE branch_id = $1 AND product_id = p.id
+					AND effective_from <= COALESCE($4::timestamp, NOW())
+				ORDER BY effective_from DESC, created_at DESC
+				LIMIT 1
+			) AS bp ON TRUE
+			WHERE ($2 = '' OR p.id::text = $2) AND ($3 = '' OR p.barcode = $3)
+			LIMIT 1
+		`
+	)
+
+	var (
+		ProductID     sql.NullString
+		Barcode       sql.NullString
+		Price         sql.NullFloat64
+		BranchPriceID sql.NullString
+		EffectiveFrom sql.NullString
+	)
+
+	err := r.db.QueryRow(ctx, query,
+		req.BranchID,
+		req.ProductID,
+		req.Barcode,
+		helpers.NewNullString(req.At),
+	).Scan(
+		&ProductID,
+		&Barcode,
+		&Price,
+		&BranchPriceID,
+		&EffectiveFrom,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ResolvedPrice{
+		BranchID:      req.BranchID,
+		ProductID:     ProductID.String,
+		Barcode:       Barcode.String,
+		Price:         Price.Float64,
+		BranchPriceID: BranchPriceID.String,
+		EffectiveFrom: EffectiveFrom.String,
+	}, nil
+}
+
+// History lists every price a product had at a branch with the window it was
+// in effect, newest first, together with the price in effect at req.At.
+func (r *branchPriceRepo) History(ctx context.Context, req *models.BranchPriceHistoryRequest) (*models.BranchPriceHistoryResponse, error) {
+
+	var (
+		resp  models.BranchPriceHistoryResponse
+		query = `
+			SELECT
+				id,
+				branch_id,
+				product_id,
+				price,
+				effective_from,
+				LEAD(effective_from) OVER (ORDER BY effective_from, created_at),
+				price_change_id,
+				created_at,
+				updated_at
+			FROM branch_price
+			WHERE branch_id = $1 AND product_id = $2
+			ORDER BY effective_from DESC, created_at DESC
+		`
+	)
+
+	rows, err := r.db.Query(ctx, query, req.BranchID, req.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			ID            sql.NullString
+			BranchID      sql.NullString
+			ProductID     sql.NullString
+			Price         sql.NullFloat64
+			EffectiveFrom sql.NullString
+			EffectiveTo   sql.NullString
+			PriceChangeID sql.NullString
+			CreatedAt     sql.NullString
+			UpdatedAt     sql.NullString
+		)
+
+		err = rows.Scan(
+			&ID,
+			&BranchID,
+			&ProductID,
+			&Price,
+			&EffectiveFrom,
+			&EffectiveTo,
+			&PriceChangeID,
+			&CreatedAt,
+			&UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.History = append(resp.History, &models.BranchPrice{
+			Id:            ID.String,
+			BranchID:      BranchID.String,
+			ProductID:     ProductID.String,
+			Price:         Price.Float64,
+			EffectiveFrom: EffectiveFrom.String,
+			EffectiveTo:   EffectiveTo.String,
+			PriceChangeID: PriceChangeID.String,
+			CreatedAt:     CreatedAt.String,
+			UpdatedAt:     UpdatedAt.String,
+		})
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	resp.InEffect, err = r.Resolve(ctx, &models.ResolvePriceRequest{
+		BranchID:  req.BranchID,
+		ProductID: req.ProductID,
+		At:        req.At,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
@@ -0,0 +1,138 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/storage"
+
+	"github.com/google/uuid"
+)
+
+func Test_branchPriceRepo_Resolve(t *testing.T) {
+
+	cfg := config.Load()
+
+	strg, err := NewConnectionPostgres(&cfg)
+	if err != nil {
+		t.Fatalf("failed to connect to PostgreSQL: %v", err)
+	}
+
+	var ctx = context.Background()
+
+	branch, err := strg.Branch().Create(ctx, &models.CreateBranch{Name: "resolve " + uuid.NewString()})
+	if err != nil {
+		t.Fatalf("branchRepo.Create() error = %v", err)
+	}
+
+	other, err := strg.Branch().Create(ctx, &models.CreateBranch{Name: "resolve " + uuid.NewString()})
+	if err != nil {
+		t.Fatalf("branchRepo.Create() error = %v", err)
+	}
+
+	product, err := strg.Product().Create(ctx, &models.CreateProduct{
+		Title:   "resolve " + uuid.NewString(),
+		Barcode: uuid.NewString(),
+		Price:   100,
+	})
+	if err != nil {
+		t.Fatalf("productRepo.Create() error = %v", err)
+	}
+
+	override, err := strg.BranchPrice().Create(ctx, &models.CreateBranchPrice{
+		BranchID:      branch.Id,
+		ProductID:     product.Id,
+		Price:         90,
+		EffectiveFrom: "2024-01-01 00:00:00",
+	})
+	if err != nil {
+		t.Fatalf("branchPriceRepo.Create() error = %v", err)
+	}
+
+	change, err := strg.PriceChange().Create(ctx, &models.CreatePriceChange{
+		BranchID:      branch.Id,
+		EffectiveFrom: "2030-01-01 00:00:00",
+		Products:      []*models.CreatePriceChangeProduct{{ProductID: product.Id, NewPrice: 120}},
+	})
+	if err != nil {
+		t.Fatalf("priceChangeRepo.Create() error = %v", err)
+	}
+
+	_, err = strg.PriceChange().Approve(ctx, &models.ApprovePriceChange{Id: change.Id})
+	if err != nil {
+		t.Fatalf("priceChangeRepo.Approve() error = %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		req           *models.ResolvePriceRequest
+		price         float64
+		override      bool
+		branchPriceID string
+	}{
+		{
+			name:  "no branch price falls back to the product price",
+			req:   &models.ResolvePriceRequest{BranchID: other.Id, ProductID: product.Id},
+			price: 100,
+		},
+		{
+			name:  "before the branch price falls back to the product price",
+			req:   &models.ResolvePriceRequest{BranchID: branch.Id, ProductID: product.Id, At: "2023-12-31 23:59:59"},
+			price: 100,
+		},
+		{
+			name:          "branch price overrides the product price",
+			req:           &models.ResolvePriceRequest{BranchID: branch.Id, Barcode: product.Barcode, At: "2025-06-01 00:00:00"},
+			price:         90,
+			override:      true,
+			branchPriceID: override.Id,
+		},
+		{
+			name:          "scheduled change is not in effect before its date",
+			req:           &models.ResolvePriceRequest{BranchID: branch.Id, ProductID: product.Id, At: "2029-12-31 23:59:59"},
+			price:         90,
+			override:      true,
+			branchPriceID: override.Id,
+		},
+		{
+			name:     "scheduled change is in effect from its date",
+			req:      &models.ResolvePriceRequest{BranchID: branch.Id, ProductID: product.Id, At: "2030-01-01 00:00:00"},
+			price:    120,
+			override: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := strg.BranchPrice().Resolve(ctx, tt.req)
+			if err != nil {
+				t.Fatalf("branchPriceRepo.Resolve() error = %v", err)
+			}
+			if got.ProductID != product.Id {
+				t.Errorf("branchPriceRepo.Resolve() product = %v, want %v", got.ProductID, product.Id)
+			}
+			if got.Price != tt.price {
+				t.Errorf("branchPriceRepo.Resolve() price = %v, want %v", got.Price, tt.price)
+			}
+			if (got.BranchPriceID != "") != tt.override {
+				t.Errorf("branchPriceRepo.Resolve() branch price = %q, want override %v", got.BranchPriceID, tt.override)
+			}
+			if tt.branchPriceID != "" && got.BranchPriceID != tt.branchPriceID {
+				t.Errorf("branchPriceRepo.Resolve() branch price = %v, want %v", got.BranchPriceID, tt.branchPriceID)
+			}
+		})
+	}
+}
+
+func Test_branchPriceRepo_Resolve_NoProduct(t *testing.T) {
+
+	var repo = NewBranchPriceRepo(nil)
+
+	_, err := repo.Resolve(context.Background(), &models.ResolvePriceRequest{BranchID: uuid.NewString()})
+	if !errors.Is(err, storage.ErrPriceProductMissing) {
+		t.Errorf("branchPriceRepo.Resolve() error = %v, want %v", err, storage.ErrPriceProductMissing)
+	}
+}
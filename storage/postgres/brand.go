@@ -6,6 +6,8 @@ import (
 	"fmt"
 
 	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/storage"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v4/pgxpool"
@@ -54,6 +56,8 @@ func (r *brandRepo) GetByID(ctx context.Context, req *models.BrandPrimaryKey) (*
 			SELECT
 				 id,
 				 name,
+				 created_at,
+				 updated_at
 			FROM brand
 			WHERE id = $1
 		`
@@ -84,29 +88,33 @@ func (r *brandRepo) GetByID(ctx context.Context, req *models.BrandPrimaryKey) (*
 		UpdatedAt: UpdatedAt.String,
 	}, nil
 }
+
+var brandSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":         {Column: "id", Type: criteria.UUID},
+		"name":       {Column: "name", Type: criteria.Text},
+		"created_at": {Column: "created_at", Type: criteria.Time},
+		"updated_at": {Column: "updated_at", Type: criteria.Time},
+	},
+	Search: []string{"name"},
+	Sort:   []criteria.Sort{{Field: "name", Desc: true}},
+}
+
 func (r *brandRepo) GetList(ctx context.Context, req *models.GetListBrandRequest) (*models.GetListBrandResponse, error) {
 	var (
-		resp   models.GetListBrandResponse
-		where  = " WHERE TRUE"
-		offset = " OFFSET 0"
-		limit  = " LIMIT 10"
-		sort   = " ORDER BY name DESC"
+		resp  models.GetListBrandResponse
+		where = brandSchema.Query()
 	)
 
-	if req.Offset > 0 {
-		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
-	}
-
-	if req.Limit > 0 {
-		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
-	}
-
-	if len(req.Search) > 0 {
-		where += " AND (name ILIKE '%" + req.Search + "%' OR phone ILIKE '%" + req.Search + "%')"
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
 	}
+	where.Search(req.Search)
 
-	if len(req.Query) > 0 {
-		where += req.Query
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
 	}
 
 	var query = `
@@ -119,8 +127,8 @@ func (r *brandRepo) GetList(ctx context.Context, req *models.GetListBrandRequest
 		FROM brand
 	`
 
-	query += where + sort + offset + limit
-	rows, err := r.db.Query(ctx, query)
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
 	if err != nil {
 		return nil, err
 	}
@@ -161,6 +169,7 @@ func (r *brandRepo) Update(ctx context.Context, req *models.UpdateBrand) (int64,
 		UPDATE brand
 			SET
 				name = $2,
+				updated_at = NOW()
 		WHERE id = $1
 	`
 	rowsAffected, err := r.db.Exec(ctx,
@@ -175,7 +184,62 @@ func (r *brandRepo) Update(ctx context.Context, req *models.UpdateBrand) (int64,
 	return rowsAffected.RowsAffected(), nil
 }
 
-func (r *brandRepo) Delete(ctx context.Context, req *models.BrandPrimaryKey) error {
-	_, err := r.db.Exec(ctx, "DELETE FROM brand WHERE id = $1", req.Id)
-	return err
+// Delete deletes a brand. A brand that products, categories or promotions
+// refer to is only deleted with req.ReassignTo, the brand they are moved to
+// first.
+func (r *brandRepo) Delete(ctx context.Context, req *models.DeleteBrand) error {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// locked so nothing starts to refer to the brand until it is deleted
+	var id string
+	err = tx.QueryRow(ctx, "SELECT id FROM brand WHERE id = $1 FOR UPDATE", req.Id).Scan(&id)
+	if err != nil {
+		return err
+	}
+
+	if req.ReassignTo == "" {
+		var products, categories, promotions int64
+		err = tx.QueryRow(ctx,
+			`SELECT
+				(SELECT COUNT(*) FROM product WHERE brand_id = $1),
+				(SELECT COUNT(*) FROM category WHERE brand_id = $1),
+				(SELECT COUNT(*) FROM promotion WHERE brand_id = $1)`,
+			req.Id,
+		).Scan(&products, &categories, &promotions)
+		if err != nil {
+			return err
+		}
+
+		if products+categories+promotions > 0 {
+			return fmt.Errorf("%w by %d products, %d categories and %d promotions", storage.ErrBrandInUse, products, categories, promotions)
+		}
+	} else {
+		var found bool
+		err = tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM brand WHERE id = $1 AND id <> $2)", req.ReassignTo, req.Id).Scan(&found)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return storage.ErrBrandNotFound
+		}
+
+		for _, table := range []string{"product", "category", "promotion"} {
+			_, err = tx.Exec(ctx, "UPDATE "+table+" SET brand_id = $2, updated_at = NOW() WHERE brand_id = $1", req.Id, req.ReassignTo)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = tx.Exec(ctx, "DELETE FROM brand WHERE id = $1", req.Id)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/storage"
+
+	"github.com/google/uuid"
+)
+
+func Test_brandRepo_Products(t *testing.T) {
+
+	cfg := config.Load()
+
+	strg, err := NewConnectionPostgres(&cfg)
+	if err != nil {
+		t.Fatalf("failed to connect to PostgreSQL: %v", err)
+	}
+
+	var (
+		ctx  = context.Background()
+		item = newTestStockItem(t, strg, 10)
+	)
+
+	brand, err := strg.Brand().Create(ctx, &models.CreateBrand{Name: "brand " + uuid.NewString()})
+	if err != nil {
+		t.Fatalf("brandRepo.Create() error = %v", err)
+	}
+
+	other, err := strg.Brand().Create(ctx, &models.CreateBrand{Name: "brand " + uuid.NewString()})
+	if err != nil {
+		t.Fatalf("brandRepo.Create() error = %v", err)
+	}
+
+	_, err = strg.Product().Update(ctx, &models.UpdateProduct{
+		Id:         item.ProductID,
+		Title:      item.ProductName,
+		CategoryID: item.CategoryID,
+		Barcode:    item.Barcode,
+		Price:      100,
+		BrandID:    brand.Id,
+	})
+	if err != nil {
+		t.Fatalf("productRepo.Update() error = %v", err)
+	}
+
+	// brandStock is the stock of the item the branch lists under a brand
+	brandStock := func(brandID string) int {
+		resp, err := strg.Remainder().GetList(ctx, &models.GetListRemainderRequest{
+			Limit: 10,
+			Filters: []criteria.Filter{
+				criteria.Equal("branch_id", item.BranchID),
+				criteria.Equal("brand_id", brandID),
+			},
+		})
+		if err != nil {
+			t.Fatalf("remainderRepo.GetList() error = %v", err)
+		}
+
+		var quantity int
+		for _, remainder := range resp.Remainder {
+			quantity += remainder.Quantity
+		}
+		return quantity
+	}
+
+	if got := brandStock(brand.Id); got != 10 {
+		t.Errorf("remainderRepo.GetList() brand stock = %v, want 10", got)
+	}
+	if got := brandStock(other.Id); got != 0 {
+		t.Errorf("remainderRepo.GetList() other brand stock = %v, want 0", got)
+	}
+
+	products, err := strg.Product().GetList(ctx, &models.GetListProductRequest{
+		Limit:   10,
+		Filters: []criteria.Filter{criteria.Equal("brand_id", brand.Id)},
+	})
+	if err != nil {
+		t.Fatalf("productRepo.GetList() error = %v", err)
+	}
+	if len(products.Products) != 1 || products.Products[0].Id != item.ProductID {
+		t.Errorf("productRepo.GetList() brand products = %d, want %v alone", len(products.Products), item.ProductID)
+	}
+
+	sale, transactionID := newTestSale(t, strg, item.BranchID)
+	addTestSaleProduct(t, strg, sale.Id, item, 2, 100)
+
+	err = strg.Sale().Finish(ctx, &models.FinishSale{
+		Id:            sale.Id,
+		BranchID:      item.BranchID,
+		TransactionID: transactionID,
+		Payment:       &models.Payment{Cash: 200, TotalAmount: 200},
+	})
+	if err != nil {
+		t.Fatalf("saleRepo.Finish() error = %v", err)
+	}
+
+	if got := brandStock(brand.Id); got != 8 {
+		t.Errorf("saleRepo.Finish() brand stock = %v, want 8", got)
+	}
+
+	var movements = testMovements(t, strg, sale.Id)
+	checkMovements(t, movements, config.StockMovementSale, -2)
+	if movements[0].Cost != item.PriceIncome {
+		t.Errorf("stock movement cost = %v, want %v", movements[0].Cost, item.PriceIncome)
+	}
+
+	report, err := strg.Report().Sales(ctx, &models.SalesReportRequest{
+		FromDate: time.Now().Format("2006-01-02"),
+		ToDate:   time.Now().AddDate(0, 0, 1).Format("2006-01-02"),
+		GroupBy:  []string{"brand"},
+		BrandID:  brand.Id,
+	})
+	if err != nil {
+		t.Fatalf("reportRepo.Sales() error = %v", err)
+	}
+	if len(report.Rows) != 1 || report.Rows[0].BrandID != brand.Id {
+		t.Fatalf("reportRepo.Sales() rows = %d, want the brand alone", len(report.Rows))
+	}
+	if got := report.Rows[0].Metrics; got.Revenue != 200 || got.Cost != 100 || got.GrossMargin != 100 {
+		t.Errorf("reportRepo.Sales() revenue = %v, cost = %v, margin = %v, want 200, 100 and 100", got.Revenue, got.Cost, got.GrossMargin)
+	}
+
+	err = strg.Brand().Delete(ctx, &models.DeleteBrand{Id: brand.Id})
+	if !errors.Is(err, storage.ErrBrandInUse) {
+		t.Errorf("brandRepo.Delete() error = %v, want %v", err, storage.ErrBrandInUse)
+	}
+
+	err = strg.Brand().Delete(ctx, &models.DeleteBrand{Id: brand.Id, ReassignTo: uuid.NewString()})
+	if !errors.Is(err, storage.ErrBrandNotFound) {
+		t.Errorf("brandRepo.Delete() error = %v, want %v", err, storage.ErrBrandNotFound)
+	}
+
+	err = strg.Brand().Delete(ctx, &models.DeleteBrand{Id: brand.Id, ReassignTo: other.Id})
+	if err != nil {
+		t.Fatalf("brandRepo.Delete() error = %v", err)
+	}
+
+	product, err := strg.Product().GetByID(ctx, &models.ProductPrimaryKey{Id: item.ProductID})
+	if err != nil {
+		t.Fatalf("productRepo.GetByID() error = %v", err)
+	}
+	if product.BrandID != other.Id {
+		t.Errorf("brandRepo.Delete() product brand = %v, want %v", product.BrandID, other.Id)
+	}
+	if got := brandStock(other.Id); got != 8 {
+		t.Errorf("remainderRepo.GetList() reassigned brand stock = %v, want 8", got)
+	}
+}
@@ -3,15 +3,22 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"errors"
 
 	"market_system/models"
+	"market_system/pkg/criteria"
 	"market_system/pkg/helpers"
+	"market_system/storage"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
+// categoryMaxDepth bounds the walks up and down the category tree, so a
+// cycle written before moves were checked can not loop them forever.
+const categoryMaxDepth = 100
+
 type categoryRepo struct {
 	db *pgxpool.Pool
 }
@@ -26,7 +33,7 @@ func (r *categoryRepo) Create(ctx context.Context, req *models.CreateCategory) (
 
 	var (
 		categoryId = uuid.New().String()
-		brandId    = ""
+		brandId    = req.BrandID
 		query      = `
 			INSERT INTO category(
 				id,
@@ -36,21 +43,27 @@ func (r *categoryRepo) Create(ctx context.Context, req *models.CreateCategory) (
 				updated_at
 			) VALUES ($1, $2, $3, $4, NOW())`
 	)
-	if req.ParentID == "" {
+
+	// a subcategory is of the brand of its parent unless it names its own
+	if req.ParentID != "" {
 		parentCategory, err := r.GetByID(ctx, &models.CategoryPrimaryKey{Id: req.ParentID})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, storage.ErrCategoryNotFound
+		}
 		if err != nil {
 			return nil, err
 		}
-		brandId = parentCategory.BrandID
+		if brandId == "" {
+			brandId = parentCategory.BrandID
+		}
 	}
 
 	_, err := r.db.Exec(ctx,
 		query,
 		categoryId,
 		req.Title,
-		req.ParentID,
-		brandId,
 		helpers.NewNullString(req.ParentID),
+		helpers.NewNullString(brandId),
 	)
 
 	if err != nil {
@@ -80,6 +93,7 @@ func (r *categoryRepo) GetByID(ctx context.Context, req *models.CategoryPrimaryK
 		Id        sql.NullString
 		Title     sql.NullString
 		ParentID  sql.NullString
+		BrandID   sql.NullString
 		CreatedAt sql.NullString
 		UpdatedAt sql.NullString
 	)
@@ -88,6 +102,7 @@ func (r *categoryRepo) GetByID(ctx context.Context, req *models.CategoryPrimaryK
 		&Id,
 		&Title,
 		&ParentID,
+		&BrandID,
 		&CreatedAt,
 		&UpdatedAt,
 	)
@@ -99,35 +114,41 @@ func (r *categoryRepo) GetByID(ctx context.Context, req *models.CategoryPrimaryK
 	return &models.Category{
 		Id:        Id.String,
 		Title:     Title.String,
+		BrandID:   BrandID.String,
 		ParentID:  ParentID.String,
 		CreatedAt: CreatedAt.String,
 		UpdatedAt: UpdatedAt.String,
 	}, nil
 }
 
+var categorySchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":         {Column: "id", Type: criteria.UUID},
+		"title":      {Column: "title", Type: criteria.Text},
+		"parent_id":  {Column: "parent_id", Type: criteria.UUID, Tree: "parent_id IN (SELECT id FROM category_subtree(%s))"},
+		"brand_id":   {Column: "brand_id", Type: criteria.UUID},
+		"created_at": {Column: "created_at", Type: criteria.Time},
+		"updated_at": {Column: "updated_at", Type: criteria.Time},
+	},
+	Search: []string{"title"},
+	Sort:   []criteria.Sort{{Field: "created_at", Desc: true}},
+}
+
 func (r *categoryRepo) GetList(ctx context.Context, req *models.GetListCategoryRequest) (*models.GetListCategoryResponse, error) {
 	var (
-		resp   models.GetListCategoryResponse
-		where  = " WHERE TRUE"
-		offset = " OFFSET 0"
-		limit  = " LIMIT 10"
-		sort   = " ORDER BY created_at DESC"
+		resp  models.GetListCategoryResponse
+		where = categorySchema.Query()
 	)
 
-	if req.Offset > 0 {
-		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
-	}
-
-	if req.Limit > 0 {
-		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
-	}
-
-	if len(req.Search) > 0 {
-		where += " AND title ILIKE" + " '%" + req.Search + "%'"
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
 	}
+	where.Search(req.Search)
 
-	if len(req.Query) > 0 {
-		where += req.Query
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
 	}
 
 	var query = `
@@ -136,13 +157,14 @@ func (r *categoryRepo) GetList(ctx context.Context, req *models.GetListCategoryR
 			id,
 			title,
 			parent_id,
+			brand_id,
 			created_at,
 			updated_at
 		FROM category
 	`
 
-	query += where + sort + offset + limit
-	rows, err := r.db.Query(ctx, query)
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
 	if err != nil {
 		return nil, err
 	}
@@ -152,6 +174,7 @@ func (r *categoryRepo) GetList(ctx context.Context, req *models.GetListCategoryR
 			Id        sql.NullString
 			Title     sql.NullString
 			ParentID  sql.NullString
+			BrandID   sql.NullString
 			CreatedAt sql.NullString
 			UpdatedAt sql.NullString
 		)
@@ -161,6 +184,7 @@ func (r *categoryRepo) GetList(ctx context.Context, req *models.GetListCategoryR
 			&Id,
 			&Title,
 			&ParentID,
+			&BrandID,
 			&CreatedAt,
 			&UpdatedAt,
 		)
@@ -171,6 +195,7 @@ func (r *categoryRepo) GetList(ctx context.Context, req *models.GetListCategoryR
 		resp.Categories = append(resp.Categories, &models.Category{
 			Id:        Id.String,
 			Title:     Title.String,
+			BrandID:   BrandID.String,
 			ParentID:  ParentID.String,
 			CreatedAt: CreatedAt.String,
 			UpdatedAt: UpdatedAt.String,
@@ -190,7 +215,19 @@ func (r *categoryRepo) Update(ctx context.Context, req *models.UpdateCategory) (
 				updated_at = NOW()
 		WHERE id = $1
 	`
-	rowsAffected, err := r.db.Exec(ctx,
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	err = checkCategoryParent(ctx, tx, req.Id, req.ParentID)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := tx.Exec(ctx,
 		query,
 		req.Id,
 		req.Title,
@@ -200,7 +237,265 @@ func (r *categoryRepo) Update(ctx context.Context, req *models.UpdateCategory) (
 		return 0, err
 	}
 
-	return rowsAffected.RowsAffected(), nil
+	return rowsAffected.RowsAffected(), tx.Commit(ctx)
+}
+
+// Move puts a category under another, or at the root when req.ParentID is
+// empty, taking its subcategories along.
+func (r *categoryRepo) Move(ctx context.Context, req *models.MoveCategory) (int64, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	err = checkCategoryParent(ctx, tx, req.Id, req.ParentID)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := tx.Exec(ctx,
+		"UPDATE category SET parent_id = $2, updated_at = NOW() WHERE id = $1",
+		req.Id,
+		helpers.NewNullString(req.ParentID),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), tx.Commit(ctx)
+}
+
+// checkCategoryParent checks that the category id can be put under parentID:
+// the parent exists and is neither the category nor below it. It locks the
+// category table against other moves until tx ends, as two moves checked at
+// once could together make a cycle that neither makes alone.
+func checkCategoryParent(ctx context.Context, tx pgx.Tx, id, parentID string) error {
+
+	if parentID == "" {
+		return nil
+	}
+
+	_, err := tx.Exec(ctx, "LOCK TABLE category IN SHARE ROW EXCLUSIVE MODE")
+	if err != nil {
+		return err
+	}
+
+	var found, cycle bool
+	err = tx.QueryRow(ctx,
+		`WITH RECURSIVE ancestors AS (
+			SELECT id, parent_id, 0 AS depth
+			FROM category
+			WHERE id = $2
+			UNION ALL
+			SELECT c.id, c.parent_id, a.depth + 1
+			FROM category AS c
+			JOIN ancestors AS a ON c.id = a.parent_id
+			WHERE a.depth < $3
+		)
+		SELECT COUNT(*) > 0, COALESCE(BOOL_OR(id = $1), FALSE)
+		FROM ancestors`,
+		id,
+		parentID,
+		categoryMaxDepth,
+	).Scan(&found, &cycle)
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		return storage.ErrCategoryNotFound
+	}
+	if cycle {
+		return storage.ErrCategoryCycle
+	}
+
+	return nil
+}
+
+// Tree returns the categories nested under their parents, from the roots or
+// from req.RootID, with the products of each and of its subcategories
+// counted.
+func (r *categoryRepo) Tree(ctx context.Context, req *models.GetCategoryTreeRequest) (*models.GetCategoryTreeResponse, error) {
+
+	var (
+		resp  = models.GetCategoryTreeResponse{Categories: []*models.CategoryNode{}}
+		query = `
+			WITH RECURSIVE tree AS (
+				SELECT id, 0 AS depth
+				FROM category
+				WHERE CASE WHEN $1::uuid IS NULL THEN parent_id IS NULL ELSE id = $1 END
+				UNION ALL
+				SELECT c.id, t.depth + 1
+				FROM category AS c
+				JOIN tree AS t ON c.parent_id = t.id
+				WHERE t.depth < $2
+			)
+			SELECT
+				c.id,
+				c.title,
+				c.brand_id,
+				c.parent_id,
+				t.depth,
+				COALESCE(p.count, 0)
+			FROM tree AS t
+			JOIN category AS c ON c.id = t.id
+			LEFT JOIN (
+				SELECT category_id, COUNT(*) AS count
+				FROM product
+				GROUP BY category_id
+			) AS p ON p.category_id = c.id
+			ORDER BY t.depth, c.title
+		`
+	)
+
+	rows, err := r.db.Query(ctx, query, helpers.NewNullString(req.RootID), categoryMaxDepth)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var nodes = map[string]*models.CategoryNode{}
+	for rows.Next() {
+		var (
+			Id       sql.NullString
+			Title    sql.NullString
+			BrandID  sql.NullString
+			ParentID sql.NullString
+			depth    int
+			node     = models.CategoryNode{Children: []*models.CategoryNode{}}
+		)
+
+		err = rows.Scan(
+			&Id,
+			&Title,
+			&BrandID,
+			&ParentID,
+			&depth,
+			&node.ProductCount,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		// a node seen before is on a cycle and its first place is kept
+		if _, ok := nodes[Id.String]; ok {
+			continue
+		}
+
+		node.Id = Id.String
+		node.Title = Title.String
+		node.BrandID = BrandID.String
+		node.ParentID = ParentID.String
+		nodes[node.Id] = &node
+
+		// rows come by depth, so the parent of a node below the top is in
+		// nodes already
+		if depth == 0 {
+			resp.Categories = append(resp.Categories, &node)
+		} else if parent, ok := nodes[node.ParentID]; ok {
+			parent.Children = append(parent.Children, &node)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, node := range resp.Categories {
+		countCategoryProducts(node)
+	}
+
+	return &resp, nil
+}
+
+// countCategoryProducts sets the total product counts of a node and the nodes
+// below it, and returns that of the node.
+func countCategoryProducts(node *models.CategoryNode) int {
+
+	node.TotalProductCount = node.ProductCount
+	for _, child := range node.Children {
+		node.TotalProductCount += countCategoryProducts(child)
+	}
+
+	return node.TotalProductCount
+}
+
+// Breadcrumbs returns the path from the root category down to the category.
+func (r *categoryRepo) Breadcrumbs(ctx context.Context, req *models.CategoryPrimaryKey) (*models.GetCategoryBreadcrumbsResponse, error) {
+
+	var (
+		resp  models.GetCategoryBreadcrumbsResponse
+		query = `
+			WITH RECURSIVE ancestors AS (
+				SELECT id, parent_id, 0 AS depth
+				FROM category
+				WHERE id = $1
+				UNION ALL
+				SELECT c.id, c.parent_id, a.depth + 1
+				FROM category AS c
+				JOIN ancestors AS a ON c.id = a.parent_id
+				WHERE a.depth < $2
+			)
+			SELECT
+				c.id,
+				c.title,
+				c.parent_id,
+				c.brand_id,
+				c.created_at,
+				c.updated_at
+			FROM ancestors AS a
+			JOIN category AS c ON c.id = a.id
+			ORDER BY a.depth DESC
+		`
+	)
+
+	rows, err := r.db.Query(ctx, query, req.Id, categoryMaxDepth)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			Id        sql.NullString
+			Title     sql.NullString
+			ParentID  sql.NullString
+			BrandID   sql.NullString
+			CreatedAt sql.NullString
+			UpdatedAt sql.NullString
+		)
+
+		err = rows.Scan(
+			&Id,
+			&Title,
+			&ParentID,
+			&BrandID,
+			&CreatedAt,
+			&UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Categories = append(resp.Categories, &models.Category{
+			Id:        Id.String,
+			Title:     Title.String,
+			BrandID:   BrandID.String,
+			ParentID:  ParentID.String,
+			CreatedAt: CreatedAt.String,
+			UpdatedAt: UpdatedAt.String,
+		})
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(resp.Categories) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+
+	return &resp, nil
 }
 
 func (r *categoryRepo) Delete(ctx context.Context, req *models.CategoryPrimaryKey) error {
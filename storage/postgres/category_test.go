@@ -2,13 +2,16 @@ package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"market_system/config"
 	"market_system/models"
+	"market_system/storage"
 	"reflect"
 	"testing"
 
 	faker "github.com/bxcodec/faker/v4"
+	"github.com/google/uuid"
 )
 
 func Test_categoryRepo_Create(t *testing.T) {
@@ -296,3 +299,107 @@ func TestUpdateCategory(t *testing.T) {
 		})
 	}
 }
+
+func Test_categoryRepo_Move(t *testing.T) {
+
+	cfg := config.Load()
+
+	strg, err := NewConnectionPostgres(&cfg)
+	if err != nil {
+		t.Fatalf("failed to connect to PostgreSQL: %v", err)
+	}
+
+	var ctx = context.Background()
+
+	create := func(parentID string) *models.Category {
+		category, err := strg.Category().Create(ctx, &models.CreateCategory{
+			Title:    "move " + uuid.NewString(),
+			ParentID: parentID,
+		})
+		if err != nil {
+			t.Fatalf("categoryRepo.Create() error = %v", err)
+		}
+		return category
+	}
+
+	var (
+		root       = create("")
+		child      = create(root.Id)
+		grandchild = create(child.Id)
+		other      = create("")
+	)
+
+	tests := []struct {
+		name    string
+		req     *models.MoveCategory
+		wantErr error
+		parent  string
+	}{
+		{
+			name:    "under itself",
+			req:     &models.MoveCategory{Id: root.Id, ParentID: root.Id},
+			wantErr: storage.ErrCategoryCycle,
+		},
+		{
+			name:    "under its own descendant",
+			req:     &models.MoveCategory{Id: root.Id, ParentID: grandchild.Id},
+			wantErr: storage.ErrCategoryCycle,
+		},
+		{
+			name:    "under a missing parent",
+			req:     &models.MoveCategory{Id: child.Id, ParentID: uuid.NewString()},
+			wantErr: storage.ErrCategoryNotFound,
+		},
+		{
+			name:   "under another tree",
+			req:    &models.MoveCategory{Id: child.Id, ParentID: other.Id},
+			parent: other.Id,
+		},
+		{
+			name: "to the root",
+			req:  &models.MoveCategory{Id: child.Id},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before, err := strg.Category().GetByID(ctx, &models.CategoryPrimaryKey{Id: tt.req.Id})
+			if err != nil {
+				t.Fatalf("categoryRepo.GetByID() error = %v", err)
+			}
+
+			rowsAffected, err := strg.Category().Move(ctx, tt.req)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("categoryRepo.Move() error = %v, want %v", err, tt.wantErr)
+				}
+			} else if err != nil {
+				t.Fatalf("categoryRepo.Move() error = %v", err)
+			} else if rowsAffected != 1 {
+				t.Errorf("categoryRepo.Move() rows affected = %d, want 1", rowsAffected)
+			}
+
+			got, err := strg.Category().GetByID(ctx, &models.CategoryPrimaryKey{Id: tt.req.Id})
+			if err != nil {
+				t.Fatalf("categoryRepo.GetByID() error = %v", err)
+			}
+
+			var want = tt.parent
+			if tt.wantErr != nil {
+				want = before.ParentID
+			}
+			if got.ParentID != want {
+				t.Errorf("categoryRepo.Move() parent = %q, want %q", got.ParentID, want)
+			}
+		})
+	}
+
+	// child took grandchild along through both moves
+	tree, err := strg.Category().Tree(ctx, &models.GetCategoryTreeRequest{RootID: child.Id})
+	if err != nil {
+		t.Fatalf("categoryRepo.Tree() error = %v", err)
+	}
+	if len(tree.Categories) != 1 || len(tree.Categories[0].Children) != 1 || tree.Categories[0].Children[0].Id != grandchild.Id {
+		t.Errorf("categoryRepo.Tree() = %+v, want %v under %v", tree.Categories, grandchild.Id, child.Id)
+	}
+}
@@ -0,0 +1,243 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type customerRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewCustomerRepo(db *pgxpool.Pool) *customerRepo {
+	return &customerRepo{
+		db: db,
+	}
+}
+
+const customerColumns = `
+	c.id,
+	c.phone,
+	c.first_name,
+	c.last_name,
+	c.card_barcode,
+	c.tier_id,
+	t.name,
+	c.points_balance,
+	c.total_spent,
+	c.created_at,
+	c.updated_at
+`
+
+func (r *customerRepo) Create(ctx context.Context, req *models.CreateCustomer) (*models.Customer, error) {
+
+	var (
+		customerID = uuid.New().String()
+		query      = `
+			INSERT INTO customer(
+				id,
+				phone,
+				first_name,
+				last_name,
+				card_barcode,
+				tier_id,
+				updated_at
+			) VALUES ($1, $2, $3, $4, $5, ` + tierForSpentQuery("0") + `, NOW())`
+	)
+
+	_, err := r.db.Exec(ctx,
+		query,
+		customerID,
+		req.Phone,
+		helpers.NewNullString(req.FirstName),
+		helpers.NewNullString(req.LastName),
+		helpers.NewNullString(req.CardBarcode),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, &models.CustomerPrimaryKey{Id: customerID})
+}
+
+func (r *customerRepo) GetByID(ctx context.Context, req *models.CustomerPrimaryKey) (*models.Customer, error) {
+
+	var query = `
+		SELECT ` + customerColumns + `
+		FROM customer AS c
+		LEFT JOIN loyalty_tier AS t ON t.id = c.tier_id
+		WHERE c.id = $1
+	`
+
+	return scanCustomer(r.db.QueryRow(ctx, query, req.Id))
+}
+
+var customerSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":             {Column: "c.id", Type: criteria.UUID},
+		"phone":          {Column: "c.phone", Type: criteria.Text},
+		"first_name":     {Column: "c.first_name", Type: criteria.Text},
+		"last_name":      {Column: "c.last_name", Type: criteria.Text},
+		"card_barcode":   {Column: "c.card_barcode", Type: criteria.Text},
+		"tier_id":        {Column: "c.tier_id", Type: criteria.UUID},
+		"points_balance": {Column: "c.points_balance", Type: criteria.Number},
+		"total_spent":    {Column: "c.total_spent", Type: criteria.Number},
+		"created_at":     {Column: "c.created_at", Type: criteria.Time},
+		"updated_at":     {Column: "c.updated_at", Type: criteria.Time},
+	},
+	Search: []string{"phone", "first_name", "last_name"},
+	Sort:   []criteria.Sort{{Field: "created_at", Desc: true}},
+}
+
+func (r *customerRepo) GetList(ctx context.Context, req *models.GetListCustomerRequest) (*models.GetListCustomerResponse, error) {
+	var (
+		resp  models.GetListCustomerResponse
+		where = customerSchema.Query()
+	)
+
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
+	}
+	where.Search(req.Search)
+
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var query = `
+		SELECT COUNT(*) OVER(), ` + customerColumns + `
+		FROM customer AS c
+		LEFT JOIN loyalty_tier AS t ON t.id = c.tier_id
+	`
+
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var count int
+		customer, err := scanCustomer(rows, &count)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Count = count
+		resp.Customers = append(resp.Customers, customer)
+	}
+
+	return &resp, rows.Err()
+}
+
+func (r *customerRepo) Update(ctx context.Context, req *models.UpdateCustomer) (int64, error) {
+
+	query := `
+		UPDATE customer
+			SET
+				phone = $2,
+				first_name = $3,
+				last_name = $4,
+				card_barcode = $5,
+				updated_at = NOW()
+		WHERE id = $1
+	`
+	rowsAffected, err := r.db.Exec(ctx,
+		query,
+		req.Id,
+		req.Phone,
+		helpers.NewNullString(req.FirstName),
+		helpers.NewNullString(req.LastName),
+		helpers.NewNullString(req.CardBarcode),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
+
+func (r *customerRepo) Delete(ctx context.Context, req *models.CustomerPrimaryKey) error {
+	_, err := r.db.Exec(ctx, "DELETE FROM customer WHERE id = $1", req.Id)
+	return err
+}
+
+// AttachToSale sets the customer of a sale that is not finished yet.
+func (r *customerRepo) AttachToSale(ctx context.Context, req *models.SaleCustomer) (int64, error) {
+
+	rowsAffected, err := r.db.Exec(ctx,
+		"UPDATE sale SET customer_id = $2, updated_at = NOW() WHERE id = $1 AND status IS DISTINCT FROM $3",
+		req.SaleID,
+		req.CustomerID,
+		config.SaleStatusFinished,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
+
+// scanCustomer reads customerColumns, optionally preceded by extra columns
+// such as COUNT(*) OVER().
+func scanCustomer(row pgx.Row, extra ...interface{}) (*models.Customer, error) {
+
+	var (
+		ID            sql.NullString
+		Phone         sql.NullString
+		FirstName     sql.NullString
+		LastName      sql.NullString
+		CardBarcode   sql.NullString
+		TierID        sql.NullString
+		TierName      sql.NullString
+		PointsBalance sql.NullFloat64
+		TotalSpent    sql.NullFloat64
+		CreatedAt     sql.NullString
+		UpdatedAt     sql.NullString
+	)
+
+	dest := append(extra,
+		&ID,
+		&Phone,
+		&FirstName,
+		&LastName,
+		&CardBarcode,
+		&TierID,
+		&TierName,
+		&PointsBalance,
+		&TotalSpent,
+		&CreatedAt,
+		&UpdatedAt,
+	)
+
+	err := row.Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Customer{
+		Id:            ID.String,
+		Phone:         Phone.String,
+		FirstName:     FirstName.String,
+		LastName:      LastName.String,
+		CardBarcode:   CardBarcode.String,
+		TierID:        TierID.String,
+		TierName:      TierName.String,
+		PointsBalance: PointsBalance.Float64,
+		TotalSpent:    TotalSpent.Float64,
+		CreatedAt:     CreatedAt.String,
+		UpdatedAt:     UpdatedAt.String,
+	}, nil
+}
@@ -0,0 +1,236 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type exportJobRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewExportJobRepo(db *pgxpool.Pool) *exportJobRepo {
+	return &exportJobRepo{
+		db: db,
+	}
+}
+
+const exportJobColumns = `
+	id,
+	status,
+	format,
+	lang,
+	path,
+	file_name,
+	rows,
+	error,
+	created_by,
+	TO_CHAR(created_at, 'YYYY-MM-DD HH24:MI:SS'),
+	TO_CHAR(finished_at, 'YYYY-MM-DD HH24:MI:SS')
+`
+
+func scanExportJob(row pgx.Row, extra ...interface{}) (*models.ExportJob, error) {
+
+	var (
+		ID         sql.NullString
+		Status     sql.NullString
+		Format     sql.NullString
+		Lang       sql.NullString
+		Path       sql.NullString
+		FileName   sql.NullString
+		Rows       sql.NullInt64
+		Error      sql.NullString
+		CreatedBy  sql.NullString
+		CreatedAt  sql.NullString
+		FinishedAt sql.NullString
+	)
+
+	dest := append(extra,
+		&ID,
+		&Status,
+		&Format,
+		&Lang,
+		&Path,
+		&FileName,
+		&Rows,
+		&Error,
+		&CreatedBy,
+		&CreatedAt,
+		&FinishedAt,
+	)
+
+	err := row.Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ExportJob{
+		Id:         ID.String,
+		Status:     Status.String,
+		Format:     Format.String,
+		Lang:       Lang.String,
+		Path:       Path.String,
+		FileName:   FileName.String,
+		Rows:       Rows.Int64,
+		Error:      Error.String,
+		CreatedBy:  CreatedBy.String,
+		CreatedAt:  CreatedAt.String,
+		FinishedAt: FinishedAt.String,
+	}, nil
+}
+
+func (r *exportJobRepo) Create(ctx context.Context, req *models.CreateExportJob) (*models.ExportJob, error) {
+
+	var (
+		exportJobID = uuid.New().String()
+		query       = `
+			INSERT INTO export_job(
+				id,
+				status,
+				format,
+				lang,
+				path,
+				file_name,
+				created_by
+			) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	)
+
+	_, err := r.db.Exec(ctx,
+		query,
+		exportJobID,
+		config.ExportJobPending,
+		req.Format,
+		req.Lang,
+		req.Path,
+		req.FileName,
+		helpers.NewNullString(req.CreatedBy),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, &models.ExportJobPrimaryKey{Id: exportJobID})
+}
+
+func (r *exportJobRepo) GetByID(ctx context.Context, req *models.ExportJobPrimaryKey) (*models.ExportJob, error) {
+
+	var query = "SELECT " + exportJobColumns + " FROM export_job WHERE id = $1"
+
+	return scanExportJob(r.db.QueryRow(ctx, query, req.Id))
+}
+
+var exportJobSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":          {Column: "id", Type: criteria.UUID},
+		"status":      {Column: "status", Type: criteria.Text},
+		"format":      {Column: "format", Type: criteria.Text},
+		"lang":        {Column: "lang", Type: criteria.Text},
+		"path":        {Column: "path", Type: criteria.Text},
+		"file_name":   {Column: "file_name", Type: criteria.Text},
+		"rows":        {Column: "rows", Type: criteria.Number},
+		"created_at":  {Column: "created_at", Type: criteria.Time},
+		"finished_at": {Column: "finished_at", Type: criteria.Time},
+	},
+	Sort: []criteria.Sort{{Field: "created_at", Desc: true}},
+}
+
+func (r *exportJobRepo) GetList(ctx context.Context, req *models.GetListExportJobRequest) (*models.GetListExportJobResponse, error) {
+	var (
+		resp  models.GetListExportJobResponse
+		where = exportJobSchema.Query()
+	)
+
+	if len(req.CreatedBy) > 0 {
+		where.And("created_by = " + where.Arg(req.CreatedBy))
+	}
+
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var query = "SELECT COUNT(*) OVER(), " + exportJobColumns + " FROM export_job"
+
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		exportJob, err := scanExportJob(rows, &resp.Count)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.ExportJobs = append(resp.ExportJobs, exportJob)
+	}
+
+	return &resp, rows.Err()
+}
+
+// Update records the progress of a job. A job that is done or failed gets its
+// finish time.
+func (r *exportJobRepo) Update(ctx context.Context, req *models.UpdateExportJob) (int64, error) {
+
+	query := `
+		UPDATE export_job
+			SET
+				status = $2,
+				rows = $3,
+				error = $4,
+				finished_at = CASE WHEN $2 IN ($5, $6) THEN NOW() END
+		WHERE id = $1
+	`
+
+	rowsAffected, err := r.db.Exec(ctx,
+		query,
+		req.Id,
+		req.Status,
+		req.Rows,
+		helpers.NewNullString(req.Error),
+		config.ExportJobDone,
+		config.ExportJobFailed,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
+
+func (r *exportJobRepo) Delete(ctx context.Context, req *models.ExportJobPrimaryKey) error {
+	_, err := r.db.Exec(ctx, "DELETE FROM export_job WHERE id = $1", req.Id)
+	return err
+}
+
+// FailUnfinished fails the jobs a restart interrupted.
+func (r *exportJobRepo) FailUnfinished(ctx context.Context) (int64, error) {
+
+	rowsAffected, err := r.db.Exec(ctx,
+		"UPDATE export_job SET status = $1, error = 'interrupted by restart', finished_at = NOW() WHERE status IN ($2, $3)",
+		config.ExportJobFailed,
+		config.ExportJobPending,
+		config.ExportJobRunning,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
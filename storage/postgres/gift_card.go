@@ -0,0 +1,609 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/giftcard"
+	"market_system/pkg/helpers"
+	"market_system/storage"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type giftCardRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewGiftCardRepo(db *pgxpool.Pool) *giftCardRepo {
+	return &giftCardRepo{
+		db: db,
+	}
+}
+
+const giftCardColumns = `
+	id,
+	code,
+	type,
+	status,
+	initial_amount,
+	balance,
+	customer_id,
+	sale_id,
+	expires_at,
+	COALESCE(expires_at <= NOW(), false),
+	comment,
+	created_at,
+	updated_at
+`
+
+// Sell creates a pending gift card and adds it to an open sale as a line
+// priced at the card amount.
+func (r *giftCardRepo) Sell(ctx context.Context, req *models.SellGiftCard) (*models.GiftCard, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var status sql.NullString
+	err = tx.QueryRow(ctx, "SELECT status FROM sale WHERE id = $1 FOR UPDATE", req.SaleID).Scan(&status)
+	if err != nil {
+		return nil, err
+	}
+
+	if status.String == config.SaleStatusFinished {
+		return nil, pgx.ErrNoRows
+	}
+
+	giftCardID, code, err := insertGiftCard(ctx, tx, config.GiftCardTypeGiftCard, config.GiftCardPending, req.Amount, req.CustomerID, req.SaleID, req.ExpiresAt, "")
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO sale_products(
+			id,
+			sale_id,
+			product_name,
+			barcode,
+			quantity,
+			allow_discount,
+			discount_type,
+			discount,
+			price,
+			total_amount,
+			gift_card_id,
+			updated_at
+		) VALUES ($1, $2, $3, $4, 1, false, '', 0, $5, $5, $6, NOW())`,
+		uuid.New().String(),
+		req.SaleID,
+		"Подарочный сертификат "+code,
+		code,
+		req.Amount,
+		giftCardID,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, &models.GiftCardPrimaryKey{Id: giftCardID})
+}
+
+// CreateStoreCredit issues active store credit right away.
+func (r *giftCardRepo) CreateStoreCredit(ctx context.Context, req *models.CreateStoreCredit) (*models.GiftCard, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	giftCardID, err := issueStoreCredit(ctx, tx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, &models.GiftCardPrimaryKey{Id: giftCardID})
+}
+
+func issueStoreCredit(ctx context.Context, tx pgx.Tx, req *models.CreateStoreCredit) (string, error) {
+
+	giftCardID, _, err := insertGiftCard(ctx, tx, config.GiftCardTypeStoreCredit, config.GiftCardActive, req.Amount, req.CustomerID, req.SaleID, req.ExpiresAt, req.Comment)
+	if err != nil {
+		return "", err
+	}
+
+	err = insertGiftCardTransaction(ctx, tx, giftCardID, req.SaleID, config.GiftCardIssue, req.Amount, req.Amount)
+	if err != nil {
+		return "", err
+	}
+
+	return giftCardID, nil
+}
+
+// insertGiftCard stores a new card under a freshly generated code.
+func insertGiftCard(ctx context.Context, tx pgx.Tx, cardType, status string, amount float64, customerID, saleID, expiresAt, comment string) (string, string, error) {
+
+	var code string
+	for {
+		var err error
+		code, err = giftcard.GenerateCode()
+		if err != nil {
+			return "", "", err
+		}
+
+		var exists bool
+		err = tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM gift_card WHERE code = $1)", code).Scan(&exists)
+		if err != nil {
+			return "", "", err
+		}
+
+		if !exists {
+			break
+		}
+	}
+
+	var giftCardID = uuid.New().String()
+	_, err := tx.Exec(ctx, `
+		INSERT INTO gift_card(
+			id,
+			code,
+			type,
+			status,
+			initial_amount,
+			balance,
+			customer_id,
+			sale_id,
+			expires_at,
+			comment,
+			updated_at
+		) VALUES ($1, $2, $3, $4, $5, $5, $6, $7, $8, $9, NOW())`,
+		giftCardID,
+		code,
+		cardType,
+		status,
+		amount,
+		helpers.NewNullString(customerID),
+		helpers.NewNullString(saleID),
+		helpers.NewNullString(expiresAt),
+		helpers.NewNullString(comment),
+	)
+	if err != nil {
+		return "", "", err
+	}
+
+	return giftCardID, code, nil
+}
+
+func (r *giftCardRepo) GetByID(ctx context.Context, req *models.GiftCardPrimaryKey) (*models.GiftCard, error) {
+
+	var query = `SELECT ` + giftCardColumns + ` FROM gift_card WHERE id = $1`
+
+	return scanGiftCard(r.db.QueryRow(ctx, query, req.Id))
+}
+
+func (r *giftCardRepo) GetByCode(ctx context.Context, req *models.GiftCardCode) (*models.GiftCard, error) {
+
+	var query = `SELECT ` + giftCardColumns + ` FROM gift_card WHERE code = $1`
+
+	return scanGiftCard(r.db.QueryRow(ctx, query, req.Code))
+}
+
+var giftCardSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":             {Column: "id", Type: criteria.UUID},
+		"code":           {Column: "code", Type: criteria.Text},
+		"type":           {Column: "type", Type: criteria.Text},
+		"status":         {Column: "status", Type: criteria.Text},
+		"initial_amount": {Column: "initial_amount", Type: criteria.Number},
+		"balance":        {Column: "balance", Type: criteria.Number},
+		"customer_id":    {Column: "customer_id", Type: criteria.UUID},
+		"sale_id":        {Column: "sale_id", Type: criteria.UUID},
+		"expires_at":     {Column: "expires_at", Type: criteria.Time},
+		"created_at":     {Column: "created_at", Type: criteria.Time},
+		"updated_at":     {Column: "updated_at", Type: criteria.Time},
+	},
+	Sort: []criteria.Sort{{Field: "created_at", Desc: true}},
+}
+
+func (r *giftCardRepo) GetList(ctx context.Context, req *models.GetListGiftCardRequest) (*models.GetListGiftCardResponse, error) {
+	var (
+		resp  models.GetListGiftCardResponse
+		where = giftCardSchema.Query()
+	)
+
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var query = `SELECT COUNT(*) OVER(), ` + giftCardColumns + ` FROM gift_card`
+
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var count int
+		giftCard, err := scanGiftCard(rows, &count)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Count = count
+		resp.GiftCards = append(resp.GiftCards, giftCard)
+	}
+
+	return &resp, rows.Err()
+}
+
+// ActivateSale activates the gift cards sold in a sale that is being
+// finished and returns their total amount. Cards whose sale line was removed
+// are canceled.
+func (r *giftCardRepo) ActivateSale(ctx context.Context, req *models.SalePrimaryKey) (float64, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	total, err := activateSaleGiftCards(ctx, tx, req.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	return total, tx.Commit(ctx)
+}
+
+func activateSaleGiftCards(ctx context.Context, tx pgx.Tx, saleID string) (float64, error) {
+
+	_, err := tx.Exec(ctx, `
+		UPDATE gift_card SET status = $3, updated_at = NOW()
+		WHERE sale_id = $1 AND status = $2
+			AND NOT EXISTS (SELECT 1 FROM sale_products AS sp WHERE sp.gift_card_id = gift_card.id)`,
+		saleID,
+		config.GiftCardPending,
+		config.GiftCardCanceled,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := tx.Query(ctx, `
+		UPDATE gift_card SET status = $3, updated_at = NOW()
+		WHERE sale_id = $1 AND status = $2
+		RETURNING id, balance`,
+		saleID,
+		config.GiftCardPending,
+		config.GiftCardActive,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		activated = map[string]float64{}
+		total     float64
+	)
+	for rows.Next() {
+		var (
+			giftCardID string
+			amount     float64
+		)
+
+		err = rows.Scan(&giftCardID, &amount)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+
+		activated[giftCardID] = amount
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for giftCardID, amount := range activated {
+		err = insertGiftCardTransaction(ctx, tx, giftCardID, saleID, config.GiftCardIssue, amount, amount)
+		if err != nil {
+			return 0, err
+		}
+
+		total += amount
+	}
+
+	return total, nil
+}
+
+// Redeem takes Amount off an active, unexpired card. Partial redemption
+// leaves the rest on the card.
+func (r *giftCardRepo) Redeem(ctx context.Context, req *models.RedeemGiftCard) (*models.GiftCard, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	giftCardID, err := redeemGiftCard(ctx, tx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, &models.GiftCardPrimaryKey{Id: giftCardID})
+}
+
+func redeemGiftCard(ctx context.Context, tx pgx.Tx, req *models.RedeemGiftCard) (string, error) {
+
+	var (
+		giftCardID string
+		status     string
+		balance    float64
+		expired    bool
+	)
+	err := tx.QueryRow(ctx,
+		"SELECT id, status, balance, COALESCE(expires_at <= NOW(), false) FROM gift_card WHERE code = $1 FOR UPDATE",
+		req.Code,
+	).Scan(&giftCardID, &status, &balance, &expired)
+	if err != nil {
+		return "", err
+	}
+
+	if status != config.GiftCardActive || expired {
+		return "", storage.ErrGiftCardNotUsable
+	}
+
+	if req.Amount > balance {
+		return "", storage.ErrNotEnoughGiftCardBalance
+	}
+
+	balance -= req.Amount
+	_, err = tx.Exec(ctx, "UPDATE gift_card SET balance = $2, updated_at = NOW() WHERE id = $1", giftCardID, balance)
+	if err != nil {
+		return "", err
+	}
+
+	err = insertGiftCardTransaction(ctx, tx, giftCardID, req.SaleID, config.GiftCardRedeem, -req.Amount, balance)
+	if err != nil {
+		return "", err
+	}
+
+	return giftCardID, nil
+}
+
+func insertGiftCardTransaction(ctx context.Context, tx pgx.Tx, giftCardID, saleID, transactionType string, amount, balanceAfter float64) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO gift_card_transaction(
+			id,
+			gift_card_id,
+			sale_id,
+			type,
+			amount,
+			balance_after
+		) VALUES ($1, $2, $3, $4, $5, $6)`,
+		uuid.New().String(),
+		giftCardID,
+		helpers.NewNullString(saleID),
+		transactionType,
+		amount,
+		balanceAfter,
+	)
+	return err
+}
+
+func (r *giftCardRepo) History(ctx context.Context, req *models.GiftCardPrimaryKey) (*models.GiftCardHistoryResponse, error) {
+
+	var (
+		resp  models.GiftCardHistoryResponse
+		query = `
+			SELECT
+				id,
+				gift_card_id,
+				sale_id,
+				type,
+				amount,
+				balance_after,
+				created_at
+			FROM gift_card_transaction
+			WHERE gift_card_id = $1
+			ORDER BY created_at
+		`
+	)
+
+	rows, err := r.db.Query(ctx, query, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			ID           sql.NullString
+			GiftCardID   sql.NullString
+			SaleID       sql.NullString
+			Type         sql.NullString
+			Amount       sql.NullFloat64
+			BalanceAfter sql.NullFloat64
+			CreatedAt    sql.NullString
+		)
+
+		err = rows.Scan(
+			&ID,
+			&GiftCardID,
+			&SaleID,
+			&Type,
+			&Amount,
+			&BalanceAfter,
+			&CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Transactions = append(resp.Transactions, &models.GiftCardTransaction{
+			Id:           ID.String,
+			GiftCardID:   GiftCardID.String,
+			SaleID:       SaleID.String,
+			Type:         Type.String,
+			Amount:       Amount.Float64,
+			BalanceAfter: BalanceAfter.Float64,
+			CreatedAt:    CreatedAt.String,
+		})
+	}
+
+	return &resp, rows.Err()
+}
+
+// LiabilityReport rebuilds card balances as of At (now when empty) from the
+// transaction history. Outstanding is what the store still owes on cards
+// that have not expired by then, Expired what was left on expired cards.
+func (r *giftCardRepo) LiabilityReport(ctx context.Context, req *models.GiftCardLiabilityRequest) (*models.GiftCardLiabilityResponse, error) {
+
+	var (
+		resp  models.GiftCardLiabilityResponse
+		query = `
+			WITH card AS (
+				SELECT
+					g.type,
+					g.expires_at,
+					COALESCE(SUM(t.amount) FILTER (WHERE t.type = $2), 0) AS issued,
+					COALESCE(-SUM(t.amount) FILTER (WHERE t.type = $3), 0) AS redeemed
+				FROM gift_card AS g
+				JOIN gift_card_transaction AS t ON t.gift_card_id = g.id
+				WHERE t.created_at <= COALESCE($1::timestamp, NOW())
+				GROUP BY g.id, g.type, g.expires_at
+			)
+			SELECT
+				type,
+				COUNT(*),
+				SUM(issued),
+				SUM(redeemed),
+				COALESCE(SUM(issued - redeemed) FILTER (WHERE expires_at IS NULL OR expires_at > COALESCE($1::timestamp, NOW())), 0),
+				COALESCE(SUM(issued - redeemed) FILTER (WHERE expires_at <= COALESCE($1::timestamp, NOW())), 0)
+			FROM card
+			GROUP BY type
+			ORDER BY type
+		`
+	)
+
+	rows, err := r.db.Query(ctx, query,
+		helpers.NewNullString(req.At),
+		config.GiftCardIssue,
+		config.GiftCardRedeem,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var liability models.GiftCardLiability
+
+		err = rows.Scan(
+			&liability.Type,
+			&liability.Cards,
+			&liability.Issued,
+			&liability.Redeemed,
+			&liability.Outstanding,
+			&liability.Expired,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Outstanding += liability.Outstanding
+		resp.Types = append(resp.Types, &liability)
+	}
+
+	return &resp, rows.Err()
+}
+
+// scanGiftCard reads giftCardColumns, optionally preceded by extra columns
+// such as COUNT(*) OVER().
+func scanGiftCard(row pgx.Row, extra ...interface{}) (*models.GiftCard, error) {
+
+	var (
+		ID            sql.NullString
+		Code          sql.NullString
+		Type          sql.NullString
+		Status        sql.NullString
+		InitialAmount sql.NullFloat64
+		Balance       sql.NullFloat64
+		CustomerID    sql.NullString
+		SaleID        sql.NullString
+		ExpiresAt     sql.NullString
+		Expired       sql.NullBool
+		Comment       sql.NullString
+		CreatedAt     sql.NullString
+		UpdatedAt     sql.NullString
+	)
+
+	dest := append(extra,
+		&ID,
+		&Code,
+		&Type,
+		&Status,
+		&InitialAmount,
+		&Balance,
+		&CustomerID,
+		&SaleID,
+		&ExpiresAt,
+		&Expired,
+		&Comment,
+		&CreatedAt,
+		&UpdatedAt,
+	)
+
+	err := row.Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.GiftCard{
+		Id:            ID.String,
+		Code:          Code.String,
+		Type:          Type.String,
+		Status:        Status.String,
+		InitialAmount: InitialAmount.Float64,
+		Balance:       Balance.Float64,
+		CustomerID:    CustomerID.String,
+		SaleID:        SaleID.String,
+		ExpiresAt:     ExpiresAt.String,
+		Expired:       Expired.Bool,
+		Comment:       Comment.String,
+		CreatedAt:     CreatedAt.String,
+		UpdatedAt:     UpdatedAt.String,
+	}, nil
+}
@@ -5,10 +5,14 @@ import (
 	"database/sql"
 	"fmt"
 
+	"market_system/config"
 	"market_system/models"
+	"market_system/pkg/criteria"
 	"market_system/pkg/helpers"
+	"market_system/storage"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
@@ -22,8 +26,14 @@ func NewIncomeRepo(db *pgxpool.Pool) *incomeRepo {
 	}
 }
 
+// Create saves a new income. It can not be created finished: posting it with
+// Finish books its stock and payable.
 func (r *incomeRepo) Create(ctx context.Context, req *models.CreateIncome) (*models.Income, error) {
 
+	if req.Status == config.IncomeStatusFinished {
+		return nil, storage.ErrIncomeFinishedStatus
+	}
+
 	var (
 		incomeId = uuid.New().String()
 		query    = `
@@ -33,8 +43,9 @@ func (r *incomeRepo) Create(ctx context.Context, req *models.CreateIncome) (*mod
 				supplier_id,
 				date_time,
 				status,
+				purchase_order_id,
 				updated_at
-			) VALUES ($1, $2, $3, $4, $5, NOW())`
+			) VALUES ($1, $2, $3, $4, $5, $6, NOW())`
 	)
 
 	_, err := r.db.Exec(ctx,
@@ -44,6 +55,7 @@ func (r *incomeRepo) Create(ctx context.Context, req *models.CreateIncome) (*mod
 		helpers.NewNullString(req.SupplierID),
 		req.DateTime,
 		req.Status,
+		helpers.NewNullString(req.PurchaseOrderID),
 	)
 
 	if err != nil {
@@ -63,6 +75,7 @@ func (r *incomeRepo) GetByID(ctx context.Context, req *models.IncomePrimaryKey)
 				 supplier_id,
 				 date_time,
 				 status,
+				 purchase_order_id,
 				 created_at,
 				 updated_at
 			FROM income
@@ -71,13 +84,14 @@ func (r *incomeRepo) GetByID(ctx context.Context, req *models.IncomePrimaryKey)
 	)
 
 	var (
-		Id         sql.NullString
-		BranchID   sql.NullString
-		SupplierID sql.NullString
-		DateTime   sql.NullString
-		Status     sql.NullString
-		CreatedAt  sql.NullString
-		UpdatedAt  sql.NullString
+		Id              sql.NullString
+		BranchID        sql.NullString
+		SupplierID      sql.NullString
+		DateTime        sql.NullString
+		Status          sql.NullString
+		PurchaseOrderID sql.NullString
+		CreatedAt       sql.NullString
+		UpdatedAt       sql.NullString
 	)
 
 	err := r.db.QueryRow(ctx, query, req.Id).Scan(
@@ -86,6 +100,7 @@ func (r *incomeRepo) GetByID(ctx context.Context, req *models.IncomePrimaryKey)
 		&SupplierID,
 		&DateTime,
 		&Status,
+		&PurchaseOrderID,
 		&CreatedAt,
 		&UpdatedAt,
 	)
@@ -95,39 +110,47 @@ func (r *incomeRepo) GetByID(ctx context.Context, req *models.IncomePrimaryKey)
 	}
 
 	return &models.Income{
-		Id:         Id.String,
-		BranchID:   BranchID.String,
-		SupplierID: SupplierID.String,
-		DateTime:   DateTime.String,
-		Status:     Status.String,
-		CreatedAt:  CreatedAt.String,
-		UpdatedAt:  UpdatedAt.String,
+		Id:              Id.String,
+		BranchID:        BranchID.String,
+		SupplierID:      SupplierID.String,
+		DateTime:        DateTime.String,
+		Status:          Status.String,
+		PurchaseOrderID: PurchaseOrderID.String,
+		CreatedAt:       CreatedAt.String,
+		UpdatedAt:       UpdatedAt.String,
 	}, nil
 }
 
+var incomeSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":                {Column: "id", Type: criteria.UUID},
+		"branch_id":         {Column: "branch_id", Type: criteria.UUID},
+		"supplier_id":       {Column: "supplier_id", Type: criteria.UUID},
+		"date_time":         {Column: "date_time", Type: criteria.Time},
+		"status":            {Column: "status", Type: criteria.Text},
+		"purchase_order_id": {Column: "purchase_order_id", Type: criteria.UUID},
+		"created_at":        {Column: "created_at", Type: criteria.Time},
+		"updated_at":        {Column: "updated_at", Type: criteria.Time},
+	},
+	Search: []string{"branch_id", "supplier_id", "status"},
+	Sort:   []criteria.Sort{{Field: "created_at", Desc: true}},
+}
+
 func (r *incomeRepo) GetList(ctx context.Context, req *models.GetListIncomeRequest) (*models.GetListIncomeResponse, error) {
 	var (
-		resp   models.GetListIncomeResponse
-		where  = " WHERE TRUE"
-		offset = " OFFSET 0"
-		limit  = " LIMIT 10"
-		sort   = " ORDER BY created_at DESC"
+		resp  models.GetListIncomeResponse
+		where = incomeSchema.Query()
 	)
 
-	if req.Offset > 0 {
-		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
-	}
-
-	if req.Limit > 0 {
-		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
-	}
-
-	if len(req.Search) > 0 {
-		where += " AND (branch_id ILIKE '%" + req.Search + "%' OR category_id ILIKE '%" + req.Search + "%' OR barcode ILIKE '%" + req.Search + "%' OR income_id ILIKE '%" + req.Search + "%')"
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
 	}
+	where.Search(req.Search)
 
-	if len(req.Query) > 0 {
-		where += req.Query
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
 	}
 
 	var query = `
@@ -138,26 +161,28 @@ func (r *incomeRepo) GetList(ctx context.Context, req *models.GetListIncomeReque
 			supplier_id,
 			date_time,
 			status,
+			purchase_order_id,
 			created_at,
 			updated_at
 		FROM income
 	`
 
-	query += where + sort + offset + limit
-	rows, err := r.db.Query(ctx, query)
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
 	if err != nil {
 		return nil, err
 	}
 
 	for rows.Next() {
 		var (
-			Id         sql.NullString
-			BranchID   sql.NullString
-			SupplierID sql.NullString
-			DateTime   sql.NullString
-			Status     sql.NullString
-			CreatedAt  sql.NullString
-			UpdatedAt  sql.NullString
+			Id              sql.NullString
+			BranchID        sql.NullString
+			SupplierID      sql.NullString
+			DateTime        sql.NullString
+			Status          sql.NullString
+			PurchaseOrderID sql.NullString
+			CreatedAt       sql.NullString
+			UpdatedAt       sql.NullString
 		)
 
 		err = rows.Scan(
@@ -167,6 +192,7 @@ func (r *incomeRepo) GetList(ctx context.Context, req *models.GetListIncomeReque
 			&SupplierID,
 			&DateTime,
 			&Status,
+			&PurchaseOrderID,
 			&CreatedAt,
 			&UpdatedAt,
 		)
@@ -174,21 +200,30 @@ func (r *incomeRepo) GetList(ctx context.Context, req *models.GetListIncomeReque
 			return nil, err
 		}
 		resp.Incomes = append(resp.Incomes, &models.Income{
-			Id:         Id.String,
-			BranchID:   BranchID.String,
-			SupplierID: SupplierID.String,
-			DateTime:   DateTime.String,
-			Status:     Status.String,
-			CreatedAt:  CreatedAt.String,
-			UpdatedAt:  UpdatedAt.String,
+			Id:              Id.String,
+			BranchID:        BranchID.String,
+			SupplierID:      SupplierID.String,
+			DateTime:        DateTime.String,
+			Status:          Status.String,
+			PurchaseOrderID: PurchaseOrderID.String,
+			CreatedAt:       CreatedAt.String,
+			UpdatedAt:       UpdatedAt.String,
 		})
 	}
 
 	return &resp, nil
 }
 
+// Update saves an income that is not finished yet; a finished one fails with
+// storage.ErrIncomeFinished, since its stock and payable are already booked.
+// Like Create it does not finish an income: only Finish does, once the lines
+// of its supplier invoice are resolved.
 func (r *incomeRepo) Update(ctx context.Context, req *models.UpdateIncome) (int64, error) {
 
+	if req.Status == config.IncomeStatusFinished {
+		return 0, storage.ErrIncomeFinishedStatus
+	}
+
 	query := `
 		UPDATE income
 			SET
@@ -199,7 +234,26 @@ func (r *incomeRepo) Update(ctx context.Context, req *models.UpdateIncome) (int6
 				updated_at = NOW()
 		WHERE id = $1
 	`
-	rowsAffected, err := r.db.Exec(ctx,
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var status string
+	err = tx.QueryRow(ctx, "SELECT COALESCE(status, '') FROM income WHERE id = $1 FOR UPDATE", req.Id).Scan(&status)
+	if err == pgx.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if status == config.IncomeStatusFinished {
+		return 0, storage.ErrIncomeFinished
+	}
+
+	rowsAffected, err := tx.Exec(ctx,
 		query,
 		req.Id,
 		helpers.NewNullString(req.BranchID),
@@ -211,6 +265,11 @@ func (r *incomeRepo) Update(ctx context.Context, req *models.UpdateIncome) (int6
 		return 0, err
 	}
 
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
 	return rowsAffected.RowsAffected(), nil
 }
 
@@ -218,3 +277,117 @@ func (r *incomeRepo) Delete(ctx context.Context, req *models.IncomePrimaryKey) e
 	_, err := r.db.Exec(ctx, "DELETE FROM income WHERE id = $1", req.Id)
 	return err
 }
+
+// Finish puts every line of an income that is not finished yet into the
+// branch stock, in its lot when the line has one, marks the income finished
+// and raises the supplier payable. 0 rows affected means the income is
+// already finished.
+func (r *incomeRepo) Finish(ctx context.Context, req *models.IncomePrimaryKey) (int64, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var branchID string
+	err = tx.QueryRow(ctx,
+		"SELECT branch_id FROM income WHERE id = $1 AND status IS DISTINCT FROM $2 FOR UPDATE",
+		req.Id,
+		config.IncomeStatusFinished,
+	).Scan(&branchID)
+	if err == pgx.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	// an imported supplier invoice is posted once every line is matched or
+	// skipped; resolving a line locks the income too
+	unresolved, err := unresolvedInvoiceLines(ctx, tx, req.Id)
+	if err != nil {
+		return 0, err
+	}
+	if unresolved > 0 {
+		return 0, fmt.Errorf("%d %w", unresolved, storage.ErrSupplierInvoiceUnresolved)
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT
+			category_id,
+			COALESCE(product_name, ''),
+			COALESCE(barcode, ''),
+			COALESCE(quantity, 0),
+			COALESCE(income_price, 0),
+			COALESCE(lot_number, ''),
+			COALESCE(TO_CHAR(expiry_date, 'YYYY-MM-DD'), '')
+		FROM income_product
+		WHERE income_id = $1
+		`,
+		req.Id,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	var lines []stockLine
+	for rows.Next() {
+		var (
+			categoryID sql.NullString
+			line       = stockLine{BranchID: branchID, Type: config.StockMovementIncome, DocumentID: req.Id}
+		)
+		err = rows.Scan(
+			&categoryID,
+			&line.ProductName,
+			&line.Barcode,
+			&line.Quantity,
+			&line.PriceIncome,
+			&line.LotNumber,
+			&line.ExpiryDate,
+		)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		line.CategoryID = categoryID.String
+
+		lines = append(lines, line)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, line := range lines {
+		if line.Barcode == "" || line.Quantity <= 0 {
+			continue
+		}
+
+		err = addStock(ctx, tx, line)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	rowsAffected, err := tx.Exec(ctx,
+		"UPDATE income SET status = $2, updated_at = NOW() WHERE id = $1",
+		req.Id,
+		config.IncomeStatusFinished,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	err = postIncomeInvoice(ctx, tx, req.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
@@ -3,9 +3,9 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	"fmt"
 
 	"market_system/models"
+	"market_system/pkg/criteria"
 	"market_system/pkg/helpers"
 
 	"github.com/google/uuid"
@@ -35,8 +35,10 @@ func (r *incomeProductRepo) Create(ctx context.Context, req *models.CreateIncome
 				barcode,
 				quantity,
 				income_price,
+				lot_number,
+				expiry_date,
 				updated_at
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())`
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9::date, NOW())`
 	)
 
 	_, err := r.db.Exec(ctx,
@@ -48,6 +50,8 @@ func (r *incomeProductRepo) Create(ctx context.Context, req *models.CreateIncome
 		req.Barcode,
 		req.Quantity,
 		req.IncomePrice,
+		helpers.NewNullString(req.LotNumber),
+		helpers.NewNullString(req.ExpiryDate),
 	)
 
 	if err != nil {
@@ -69,6 +73,9 @@ func (r *incomeProductRepo) GetByID(ctx context.Context, req *models.IncomeProdu
 				barcode,
 				quantity,
 				income_price,
+				purchase_order_product_id,
+				lot_number,
+				TO_CHAR(expiry_date, 'YYYY-MM-DD'),
 				created_at,
 				updated_at	
 			FROM  income_product
@@ -77,15 +84,18 @@ func (r *incomeProductRepo) GetByID(ctx context.Context, req *models.IncomeProdu
 	)
 
 	var (
-		Id          sql.NullString
-		IncomeID    sql.NullString
-		CategoryID  sql.NullString
-		ProductName sql.NullString
-		Barcode     sql.NullString
-		Quantity    sql.NullInt64
-		IncomePrice sql.NullFloat64
-		CreatedAt   sql.NullString
-		UpdatedAt   sql.NullString
+		Id                     sql.NullString
+		IncomeID               sql.NullString
+		CategoryID             sql.NullString
+		ProductName            sql.NullString
+		Barcode                sql.NullString
+		Quantity               sql.NullInt64
+		IncomePrice            sql.NullFloat64
+		PurchaseOrderProductID sql.NullString
+		LotNumber              sql.NullString
+		ExpiryDate             sql.NullString
+		CreatedAt              sql.NullString
+		UpdatedAt              sql.NullString
 	)
 
 	err := r.db.QueryRow(ctx, query, req.Id).Scan(
@@ -96,6 +106,9 @@ func (r *incomeProductRepo) GetByID(ctx context.Context, req *models.IncomeProdu
 		&Barcode,
 		&Quantity,
 		&IncomePrice,
+		&PurchaseOrderProductID,
+		&LotNumber,
+		&ExpiryDate,
 		&CreatedAt,
 		&UpdatedAt,
 	)
@@ -105,41 +118,55 @@ func (r *incomeProductRepo) GetByID(ctx context.Context, req *models.IncomeProdu
 	}
 
 	return &models.IncomeProduct{
-		Id:          Id.String,
-		IncomeID:    IncomeID.String,
-		CategoryID:  CategoryID.String,
-		ProductName: ProductName.String,
-		Barcode:     Barcode.String,
-		Quantity:    Quantity.Int64,
-		IncomePrice: IncomePrice.Float64,
-		CreatedAt:   CreatedAt.String,
-		UpdatedAt:   UpdatedAt.String,
+		Id:                     Id.String,
+		IncomeID:               IncomeID.String,
+		CategoryID:             CategoryID.String,
+		ProductName:            ProductName.String,
+		Barcode:                Barcode.String,
+		Quantity:               Quantity.Int64,
+		IncomePrice:            IncomePrice.Float64,
+		PurchaseOrderProductID: PurchaseOrderProductID.String,
+		LotNumber:              LotNumber.String,
+		ExpiryDate:             ExpiryDate.String,
+		CreatedAt:              CreatedAt.String,
+		UpdatedAt:              UpdatedAt.String,
 	}, nil
 }
 
+var incomeProductSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":                        {Column: "id", Type: criteria.UUID},
+		"income_id":                 {Column: "income_id", Type: criteria.UUID},
+		"category_id":               {Column: "category_id", Type: criteria.UUID},
+		"product_name":              {Column: "product_name", Type: criteria.Text},
+		"barcode":                   {Column: "barcode", Type: criteria.Text},
+		"quantity":                  {Column: "quantity", Type: criteria.Number},
+		"income_price":              {Column: "income_price", Type: criteria.Number},
+		"purchase_order_product_id": {Column: "purchase_order_product_id", Type: criteria.UUID},
+		"lot_number":                {Column: "lot_number", Type: criteria.Text},
+		"expiry_date":               {Column: "expiry_date", Type: criteria.Time},
+		"created_at":                {Column: "created_at", Type: criteria.Time},
+		"updated_at":                {Column: "updated_at", Type: criteria.Time},
+	},
+	Search: []string{"category_id", "barcode"},
+	Sort:   []criteria.Sort{{Field: "created_at", Desc: true}},
+}
+
 func (r *incomeProductRepo) GetList(ctx context.Context, req *models.GetListIncomeProductRequest) (*models.GetListIncomeProductResponse, error) {
 	var (
-		resp   models.GetListIncomeProductResponse
-		where  = " WHERE TRUE"
-		offset = " OFFSET 0"
-		limit  = " LIMIT 10"
-		sort   = " ORDER BY created_at DESC"
+		resp  models.GetListIncomeProductResponse
+		where = incomeProductSchema.Query()
 	)
 
-	if req.Offset > 0 {
-		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
-	}
-
-	if req.Limit > 0 {
-		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
-	}
-
-	if len(req.Search) > 0 {
-		where += " AND (category_id ILIKE '%" + req.Search + "%' OR barcode ILIKE '%" + req.Search + "%')"
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
 	}
+	where.Search(req.Search)
 
-	if len(req.Query) > 0 {
-		where += req.Query
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
 	}
 
 	var query = `
@@ -152,31 +179,39 @@ func (r *incomeProductRepo) GetList(ctx context.Context, req *models.GetListInco
 			 barcode,
 			 quantity,
 			 income_price,
+			 purchase_order_product_id,
+			 lot_number,
+			 TO_CHAR(expiry_date, 'YYYY-MM-DD'),
 			 created_at,
 			 updated_at
 		FROM income_product
 	`
 
-	query += where + sort + offset + limit
-	rows, err := r.db.Query(ctx, query)
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var (
-			Id          sql.NullString
-			IncomeID    sql.NullString
-			CategoryID  sql.NullString
-			ProductName sql.NullString
-			Barcode     sql.NullString
-			Quantity    sql.NullInt64
-			IncomePrice sql.NullFloat64
-			CreatedAt   sql.NullString
-			UpdatedAt   sql.NullString
+			Id                     sql.NullString
+			IncomeID               sql.NullString
+			CategoryID             sql.NullString
+			ProductName            sql.NullString
+			Barcode                sql.NullString
+			Quantity               sql.NullInt64
+			IncomePrice            sql.NullFloat64
+			PurchaseOrderProductID sql.NullString
+			LotNumber              sql.NullString
+			ExpiryDate             sql.NullString
+			CreatedAt              sql.NullString
+			UpdatedAt              sql.NullString
 		)
 
 		err = rows.Scan(
+			&resp.Count,
 			&Id,
 			&IncomeID,
 			&CategoryID,
@@ -184,6 +219,9 @@ func (r *incomeProductRepo) GetList(ctx context.Context, req *models.GetListInco
 			&Barcode,
 			&Quantity,
 			&IncomePrice,
+			&PurchaseOrderProductID,
+			&LotNumber,
+			&ExpiryDate,
 			&CreatedAt,
 			&UpdatedAt,
 		)
@@ -192,19 +230,22 @@ func (r *incomeProductRepo) GetList(ctx context.Context, req *models.GetListInco
 		}
 
 		resp.IncomeProducts = append(resp.IncomeProducts, &models.IncomeProduct{
-			Id:          Id.String,
-			IncomeID:    IncomeID.String,
-			CategoryID:  CategoryID.String,
-			ProductName: ProductName.String,
-			Barcode:     Barcode.String,
-			Quantity:    Quantity.Int64,
-			IncomePrice: IncomePrice.Float64,
-			CreatedAt:   CreatedAt.String,
-			UpdatedAt:   UpdatedAt.String,
+			Id:                     Id.String,
+			IncomeID:               IncomeID.String,
+			CategoryID:             CategoryID.String,
+			ProductName:            ProductName.String,
+			Barcode:                Barcode.String,
+			Quantity:               Quantity.Int64,
+			IncomePrice:            IncomePrice.Float64,
+			PurchaseOrderProductID: PurchaseOrderProductID.String,
+			LotNumber:              LotNumber.String,
+			ExpiryDate:             ExpiryDate.String,
+			CreatedAt:              CreatedAt.String,
+			UpdatedAt:              UpdatedAt.String,
 		})
 	}
 
-	return &resp, nil
+	return &resp, rows.Err()
 }
 
 func (r *incomeProductRepo) Update(ctx context.Context, req *models.UpdateIncomeProduct) (int64, error) {
@@ -218,6 +259,8 @@ func (r *incomeProductRepo) Update(ctx context.Context, req *models.UpdateIncome
 				income_price = $5,
 				category_id = $6,
 				income_id = $7,
+				lot_number = $8,
+				expiry_date = $9::date,
 				updated_at = NOW()
 		WHERE id = $1
 	`
@@ -230,6 +273,8 @@ func (r *incomeProductRepo) Update(ctx context.Context, req *models.UpdateIncome
 		req.IncomePrice,
 		helpers.NewNullString(req.CategoryID),
 		helpers.NewNullString(req.IncomeID),
+		helpers.NewNullString(req.LotNumber),
+		helpers.NewNullString(req.ExpiryDate),
 	)
 	if err != nil {
 		return 0, err
@@ -0,0 +1,652 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+	"market_system/pkg/stocktake"
+	"market_system/storage"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type inventoryCountRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewInventoryCountRepo(db *pgxpool.Pool) *inventoryCountRepo {
+	return &inventoryCountRepo{
+		db: db,
+	}
+}
+
+// querier is what the count lines are read through: the pool for reports and
+// the posting transaction when they are frozen.
+type querier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+}
+
+const inventoryCountColumns = `
+	id,
+	branch_id,
+	category_id,
+	status,
+	comment,
+	created_by,
+	snapshot_at,
+	posted_by,
+	posted_at,
+	created_at,
+	updated_at
+`
+
+func scanInventoryCount(row pgx.Row, extra ...interface{}) (*models.InventoryCount, error) {
+
+	var (
+		ID         sql.NullString
+		BranchID   sql.NullString
+		CategoryID sql.NullString
+		Status     sql.NullString
+		Comment    sql.NullString
+		CreatedBy  sql.NullString
+		SnapshotAt sql.NullString
+		PostedBy   sql.NullString
+		PostedAt   sql.NullString
+		CreatedAt  sql.NullString
+		UpdatedAt  sql.NullString
+	)
+
+	dest := append(extra,
+		&ID,
+		&BranchID,
+		&CategoryID,
+		&Status,
+		&Comment,
+		&CreatedBy,
+		&SnapshotAt,
+		&PostedBy,
+		&PostedAt,
+		&CreatedAt,
+		&UpdatedAt,
+	)
+
+	err := row.Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.InventoryCount{
+		Id:         ID.String,
+		BranchID:   BranchID.String,
+		CategoryID: CategoryID.String,
+		Status:     Status.String,
+		Comment:    Comment.String,
+		CreatedBy:  CreatedBy.String,
+		SnapshotAt: SnapshotAt.String,
+		PostedBy:   PostedBy.String,
+		PostedAt:   PostedAt.String,
+		CreatedAt:  CreatedAt.String,
+		UpdatedAt:  UpdatedAt.String,
+	}, nil
+}
+
+// Create opens a count and snapshots the branch remainder (of one category
+// when given) as the expected quantities. A branch has at most one open
+// count; another one fails with storage.ErrInventoryCountInProgress.
+func (r *inventoryCountRepo) Create(ctx context.Context, req *models.CreateInventoryCount) (*models.InventoryCount, error) {
+
+	var (
+		countID = uuid.New().String()
+		query   = `
+			INSERT INTO inventory_count(
+				id,
+				branch_id,
+				category_id,
+				status,
+				comment,
+				created_by,
+				snapshot_at,
+				updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, NOW(), NOW())`
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var open bool
+	err = tx.QueryRow(ctx,
+		"SELECT EXISTS(SELECT 1 FROM inventory_count WHERE branch_id = $1 AND status = $2)",
+		req.BranchID,
+		config.InventoryCountOpen,
+	).Scan(&open)
+	if err != nil {
+		return nil, err
+	}
+
+	if open {
+		return nil, storage.ErrInventoryCountInProgress
+	}
+
+	_, err = tx.Exec(ctx,
+		query,
+		countID,
+		req.BranchID,
+		helpers.NewNullString(req.CategoryID),
+		config.InventoryCountOpen,
+		helpers.NewNullString(req.Comment),
+		helpers.NewNullString(req.CreatedBy),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.snapshot(ctx, tx, countID, req.BranchID, req.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, &models.InventoryCountPrimaryKey{Id: countID})
+}
+
+// snapshot stores one line per barcode the branch stocks with the quantity it
+// has right now.
+func (r *inventoryCountRepo) snapshot(ctx context.Context, tx pgx.Tx, countID, branchID, categoryID string) error {
+
+	rows, err := tx.Query(ctx, `
+		SELECT DISTINCT ON (barcode)
+			category_id,
+			product_name,
+			barcode,
+			COALESCE(price_income, 0),
+			SUM(COALESCE(quantity, 0)) OVER (PARTITION BY barcode)
+		FROM remainder
+		WHERE branch_id = $1 AND ($2::uuid IS NULL OR category_id = $2) AND COALESCE(barcode, '') <> ''
+		ORDER BY barcode, created_at
+		`,
+		branchID,
+		helpers.NewNullString(categoryID),
+	)
+	if err != nil {
+		return err
+	}
+
+	var lines []stockLine
+	for rows.Next() {
+		var (
+			categoryID  sql.NullString
+			productName sql.NullString
+			line        stockLine
+		)
+
+		err = rows.Scan(&categoryID, &productName, &line.Barcode, &line.PriceIncome, &line.Quantity)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		line.CategoryID = categoryID.String
+		line.ProductName = productName.String
+
+		lines = append(lines, line)
+	}
+	rows.Close()
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, line := range lines {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO inventory_count_product(
+				id,
+				inventory_count_id,
+				category_id,
+				product_name,
+				barcode,
+				cost,
+				expected_quantity,
+				updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())`,
+			uuid.New().String(),
+			countID,
+			helpers.NewNullString(line.CategoryID),
+			line.ProductName,
+			line.Barcode,
+			line.PriceIncome,
+			line.Quantity,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *inventoryCountRepo) GetByID(ctx context.Context, req *models.InventoryCountPrimaryKey) (*models.InventoryCount, error) {
+
+	var query = "SELECT " + inventoryCountColumns + " FROM inventory_count WHERE id = $1"
+
+	count, err := scanInventoryCount(r.db.QueryRow(ctx, query, req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	count.Products, err = r.getProducts(ctx, r.db, count.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	count.Summary = summarizeInventoryCount(count.Products)
+
+	return count, nil
+}
+
+// getProducts returns the count lines with their variance. While the count is
+// open counted quantities are the merged scans and the movement since the
+// snapshot is taken up to the last scan of the barcode (up to now for a
+// barcode nobody has counted yet); once posted the frozen values are read.
+func (r *inventoryCountRepo) getProducts(ctx context.Context, q querier, countID string) ([]*models.InventoryCountProduct, error) {
+
+	var (
+		products []*models.InventoryCountProduct
+		query    = `
+			SELECT
+				p.id,
+				p.inventory_count_id,
+				p.category_id,
+				p.product_name,
+				p.barcode,
+				COALESCE(p.cost, 0),
+				p.expected_quantity,
+				COALESCE(p.moved_quantity, (
+					SELECT COALESCE(SUM(m.quantity), 0)
+					FROM stock_movement AS m
+					WHERE m.branch_id = ic.branch_id
+						AND m.barcode = p.barcode
+						AND m.created_at > ic.snapshot_at
+						AND m.created_at <= COALESCE(s.last_scan, NOW())
+				)),
+				COALESCE(p.counted_quantity, s.counted, 0),
+				s.counted IS NOT NULL
+			FROM inventory_count_product AS p
+			JOIN inventory_count AS ic ON ic.id = p.inventory_count_id
+			LEFT JOIN (
+				SELECT barcode, SUM(quantity) AS counted, MAX(created_at) AS last_scan
+				FROM inventory_count_scan
+				WHERE inventory_count_id = $1
+				GROUP BY barcode
+			) AS s ON s.barcode = p.barcode
+			WHERE p.inventory_count_id = $1
+			ORDER BY p.product_name, p.barcode
+		`
+	)
+
+	rows, err := q.Query(ctx, query, countID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			product     models.InventoryCountProduct
+			categoryID  sql.NullString
+			productName sql.NullString
+		)
+
+		err = rows.Scan(
+			&product.Id,
+			&product.InventoryCountID,
+			&categoryID,
+			&productName,
+			&product.Barcode,
+			&product.Cost,
+			&product.ExpectedQuantity,
+			&product.MovedQuantity,
+			&product.CountedQuantity,
+			&product.Counted,
+		)
+		if err != nil {
+			return nil, err
+		}
+		product.CategoryID = categoryID.String
+		product.ProductName = productName.String
+
+		line := inventoryCountLine(&product)
+		product.BookQuantity = stocktake.Book(line)
+		product.Variance = stocktake.Variance(line)
+		product.CostImpact = stocktake.CostImpact(line)
+
+		products = append(products, &product)
+	}
+
+	return products, rows.Err()
+}
+
+func inventoryCountLine(product *models.InventoryCountProduct) stocktake.Line {
+	return stocktake.Line{
+		Expected: product.ExpectedQuantity,
+		Moved:    product.MovedQuantity,
+		Counted:  product.CountedQuantity,
+		Cost:     product.Cost,
+	}
+}
+
+func summarizeInventoryCount(products []*models.InventoryCountProduct) *models.InventoryCountSummary {
+
+	var (
+		lines   = make([]stocktake.Line, 0, len(products))
+		counted int
+	)
+	for _, product := range products {
+		lines = append(lines, inventoryCountLine(product))
+		if product.Counted {
+			counted++
+		}
+	}
+
+	summary := stocktake.Summarize(lines)
+
+	return &models.InventoryCountSummary{
+		Lines:            summary.Lines,
+		CountedLines:     counted,
+		SurplusQuantity:  summary.SurplusQuantity,
+		SurplusCost:      summary.SurplusCost,
+		ShortageQuantity: summary.ShortageQuantity,
+		ShortageCost:     summary.ShortageCost,
+		Net:              summary.Net,
+	}
+}
+
+var inventoryCountSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":          {Column: "id", Type: criteria.UUID},
+		"branch_id":   {Column: "branch_id", Type: criteria.UUID},
+		"category_id": {Column: "category_id", Type: criteria.UUID},
+		"status":      {Column: "status", Type: criteria.Text},
+		"created_by":  {Column: "created_by", Type: criteria.UUID},
+		"snapshot_at": {Column: "snapshot_at", Type: criteria.Time},
+		"posted_by":   {Column: "posted_by", Type: criteria.UUID},
+		"posted_at":   {Column: "posted_at", Type: criteria.Time},
+		"created_at":  {Column: "created_at", Type: criteria.Time},
+		"updated_at":  {Column: "updated_at", Type: criteria.Time},
+	},
+	Sort: []criteria.Sort{{Field: "created_at", Desc: true}},
+}
+
+func (r *inventoryCountRepo) GetList(ctx context.Context, req *models.GetListInventoryCountRequest) (*models.GetListInventoryCountResponse, error) {
+	var (
+		resp  models.GetListInventoryCountResponse
+		where = inventoryCountSchema.Query()
+	)
+
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var query = "SELECT COUNT(*) OVER(), " + inventoryCountColumns + " FROM inventory_count"
+
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		count, err := scanInventoryCount(rows, &resp.Count)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.InventoryCounts = append(resp.InventoryCounts, count)
+	}
+
+	return &resp, rows.Err()
+}
+
+// Scan records what a counter scanned into an open count; 0 rows affected
+// means the count is not open. A barcode missing from the snapshot is added
+// with nothing expected, named after any branch that stocks it; a barcode no
+// branch stocks (or outside the count category) is wrapped pgx.ErrNoRows.
+func (r *inventoryCountRepo) Scan(ctx context.Context, req *models.ScanInventoryCount) (int64, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var countID string
+	err = tx.QueryRow(ctx,
+		"SELECT id FROM inventory_count WHERE id = $1 AND status = $2 FOR UPDATE",
+		req.Id,
+		config.InventoryCountOpen,
+	).Scan(&countID)
+	if err == pgx.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	for _, product := range req.Products {
+		err = r.addLine(ctx, tx, countID, product.Barcode)
+		if err != nil {
+			return 0, err
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO inventory_count_scan(
+				id,
+				inventory_count_id,
+				barcode,
+				quantity,
+				counted_by
+			) VALUES ($1, $2, $3, $4, $5)`,
+			uuid.New().String(),
+			countID,
+			product.Barcode,
+			product.Quantity,
+			helpers.NewNullString(req.CountedBy),
+		)
+		if err != nil {
+			return 0, err
+		}
+
+		var counted int64
+		err = tx.QueryRow(ctx,
+			"SELECT SUM(quantity) FROM inventory_count_scan WHERE inventory_count_id = $1 AND barcode = $2",
+			countID,
+			product.Barcode,
+		).Scan(&counted)
+		if err != nil {
+			return 0, err
+		}
+
+		if counted < 0 {
+			return 0, fmt.Errorf("barcode %s: %w", product.Barcode, storage.ErrNegativeCount)
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return 1, nil
+}
+
+// addLine makes sure the count has a line for barcode.
+func (r *inventoryCountRepo) addLine(ctx context.Context, tx pgx.Tx, countID, barcode string) error {
+
+	var exists bool
+	err := tx.QueryRow(ctx,
+		"SELECT EXISTS(SELECT 1 FROM inventory_count_product WHERE inventory_count_id = $1 AND barcode = $2)",
+		countID,
+		barcode,
+	).Scan(&exists)
+	if err != nil || exists {
+		return err
+	}
+
+	result, err := tx.Exec(ctx, `
+		INSERT INTO inventory_count_product(
+			id,
+			inventory_count_id,
+			category_id,
+			product_name,
+			barcode,
+			cost,
+			expected_quantity,
+			updated_at
+		)
+		SELECT $1, ic.id, rm.category_id, rm.product_name, rm.barcode, rm.price_income, 0, NOW()
+		FROM inventory_count AS ic
+		JOIN remainder AS rm ON rm.barcode = $3 AND (ic.category_id IS NULL OR rm.category_id = ic.category_id)
+		WHERE ic.id = $2
+		ORDER BY rm.branch_id = ic.branch_id DESC, rm.created_at
+		LIMIT 1
+		`,
+		uuid.New().String(),
+		countID,
+		barcode,
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("barcode %s is not stocked in the count scope: %w", barcode, pgx.ErrNoRows)
+	}
+
+	return nil
+}
+
+// Post freezes the variance of an open count and adjusts the branch stock by
+// it: a surplus is added as count_surplus, a shortage written off as
+// count_shortage. Lines nobody counted are taken as counted zero. Stock moved
+// after a barcode was counted is kept, because the adjustment is the variance
+// rather than the counted quantity.
+func (r *inventoryCountRepo) Post(ctx context.Context, req *models.PostInventoryCount) (int64, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var branchID string
+	err = tx.QueryRow(ctx,
+		"SELECT branch_id FROM inventory_count WHERE id = $1 AND status = $2 FOR UPDATE",
+		req.Id,
+		config.InventoryCountOpen,
+	).Scan(&branchID)
+	if err == pgx.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	products, err := r.getProducts(ctx, tx, req.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, product := range products {
+		_, err = tx.Exec(ctx, `
+			UPDATE inventory_count_product
+				SET
+					moved_quantity = $2,
+					counted_quantity = $3,
+					variance = $4,
+					updated_at = NOW()
+			WHERE id = $1
+			`,
+			product.Id,
+			product.MovedQuantity,
+			product.CountedQuantity,
+			product.Variance,
+		)
+		if err != nil {
+			return 0, err
+		}
+
+		line := stockLine{
+			BranchID:    branchID,
+			CategoryID:  product.CategoryID,
+			ProductName: product.ProductName,
+			Barcode:     product.Barcode,
+			PriceIncome: product.Cost,
+			DocumentID:  req.Id,
+		}
+
+		switch {
+		case product.Variance > 0:
+			line.Quantity = product.Variance
+			line.Type = config.StockMovementCountSurplus
+			err = addStock(ctx, tx, line)
+		case product.Variance < 0:
+			line.Quantity = -product.Variance
+			line.Type = config.StockMovementCountShortage
+			_, err = removeStock(ctx, tx, line)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	rowsAffected, err := tx.Exec(ctx,
+		"UPDATE inventory_count SET status = $2, posted_by = $3, posted_at = NOW(), updated_at = NOW() WHERE id = $1",
+		req.Id,
+		config.InventoryCountPosted,
+		helpers.NewNullString(req.PostedBy),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
+
+func (r *inventoryCountRepo) Cancel(ctx context.Context, req *models.InventoryCountPrimaryKey) (int64, error) {
+
+	rowsAffected, err := r.db.Exec(ctx,
+		"UPDATE inventory_count SET status = $2, updated_at = NOW() WHERE id = $1 AND status = $3",
+		req.Id,
+		config.InventoryCountCanceled,
+		config.InventoryCountOpen,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
@@ -0,0 +1,556 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+	"market_system/storage"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type loyaltyRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewLoyaltyRepo(db *pgxpool.Pool) *loyaltyRepo {
+	return &loyaltyRepo{
+		db: db,
+	}
+}
+
+// SetEarnRate creates or replaces the earn rate of a category, or the
+// default rate when CategoryID is empty.
+func (r *loyaltyRepo) SetEarnRate(ctx context.Context, req *models.SetLoyaltyEarnRate) (*models.LoyaltyEarnRate, error) {
+
+	var categoryID = helpers.NewNullString(req.CategoryID)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx,
+		"UPDATE loyalty_earn_rate SET percent = $2, updated_at = NOW() WHERE category_id IS NOT DISTINCT FROM $1",
+		categoryID,
+		req.Percent,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if result.RowsAffected() == 0 {
+		_, err = tx.Exec(ctx,
+			"INSERT INTO loyalty_earn_rate(id, category_id, percent, updated_at) VALUES ($1, $2, $3, NOW())",
+			uuid.New().String(),
+			categoryID,
+			req.Percent,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rates, _, err := r.getEarnRates(ctx, " WHERE r.category_id IS NOT DISTINCT FROM $1", categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rates) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+
+	return rates[0], nil
+}
+
+var loyaltyEarnRateSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":            {Column: "r.id", Type: criteria.UUID},
+		"category_id":   {Column: "r.category_id", Type: criteria.UUID},
+		"category_name": {Column: "c.title", Type: criteria.Text},
+		"is_default":    {Column: "(r.category_id IS NULL)", Type: criteria.Bool},
+		"percent":       {Column: "r.percent", Type: criteria.Number},
+		"created_at":    {Column: "r.created_at", Type: criteria.Time},
+		"updated_at":    {Column: "r.updated_at", Type: criteria.Time},
+	},
+	Sort: []criteria.Sort{{Field: "is_default", Desc: true}, {Field: "category_name"}},
+}
+
+func (r *loyaltyRepo) GetListEarnRate(ctx context.Context, req *models.GetListLoyaltyEarnRateRequest) (*models.GetListLoyaltyEarnRateResponse, error) {
+	var (
+		resp  models.GetListLoyaltyEarnRateResponse
+		where = loyaltyEarnRateSchema.Query()
+	)
+
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.EarnRates, resp.Count, err = r.getEarnRates(ctx, where.SQL()+sort+criteria.Page(req.Offset, req.Limit), where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func (r *loyaltyRepo) DeleteEarnRate(ctx context.Context, req *models.LoyaltyEarnRatePrimaryKey) error {
+	_, err := r.db.Exec(ctx, "DELETE FROM loyalty_earn_rate WHERE id = $1", req.Id)
+	return err
+}
+
+// getEarnRates reads the earn rates matching tail, the WHERE, ORDER BY and
+// page clauses of the query, with the count of all the matching rates.
+func (r *loyaltyRepo) getEarnRates(ctx context.Context, tail string, params ...interface{}) ([]*models.LoyaltyEarnRate, int, error) {
+
+	var query = `
+		SELECT
+			COUNT(*) OVER(),
+			r.id,
+			r.category_id,
+			c.title,
+			r.percent,
+			r.created_at,
+			r.updated_at
+		FROM loyalty_earn_rate AS r
+		LEFT JOIN category AS c ON c.id = r.category_id
+	` + tail
+
+	rows, err := r.db.Query(ctx, query, params...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	var (
+		rates []*models.LoyaltyEarnRate
+		count int
+	)
+	for rows.Next() {
+		var (
+			ID           sql.NullString
+			CategoryID   sql.NullString
+			CategoryName sql.NullString
+			Percent      sql.NullFloat64
+			CreatedAt    sql.NullString
+			UpdatedAt    sql.NullString
+		)
+
+		err = rows.Scan(
+			&count,
+			&ID,
+			&CategoryID,
+			&CategoryName,
+			&Percent,
+			&CreatedAt,
+			&UpdatedAt,
+		)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		rates = append(rates, &models.LoyaltyEarnRate{
+			Id:           ID.String,
+			CategoryID:   CategoryID.String,
+			CategoryName: CategoryName.String,
+			Percent:      Percent.Float64,
+			CreatedAt:    CreatedAt.String,
+			UpdatedAt:    UpdatedAt.String,
+		})
+	}
+
+	return rates, count, rows.Err()
+}
+
+// ApplySale books a finished sale on its customer. Redeemed points are taken
+// off the balance, points are earned on the part of the basket paid with
+// money (category rate or the default rate, times the tier multiplier) and
+// the customer is moved to the tier their new total spent reaches. Gift
+// cards sold in the sale earn nothing. A sale is booked only once.
+func (r *loyaltyRepo) ApplySale(ctx context.Context, req *models.ApplySaleLoyalty) error {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = applySaleLoyalty(ctx, tx, req)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func applySaleLoyalty(ctx context.Context, tx pgx.Tx, req *models.ApplySaleLoyalty) error {
+
+	var balance, multiplier float64
+	err := tx.QueryRow(ctx, `
+		SELECT c.points_balance, COALESCE(t.earn_multiplier, 1)
+		FROM customer AS c
+		LEFT JOIN loyalty_tier AS t ON t.id = c.tier_id
+		WHERE c.id = $1
+		FOR UPDATE OF c`,
+		req.CustomerID,
+	).Scan(&balance, &multiplier)
+	if err != nil {
+		return err
+	}
+
+	var booked bool
+	err = tx.QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM loyalty_transaction WHERE sale_id = $1 AND type IN ($2, $3))",
+		req.SaleID,
+		config.LoyaltyEarn,
+		config.LoyaltyRedeem,
+	).Scan(&booked)
+	if err != nil {
+		return err
+	}
+
+	if booked {
+		return nil
+	}
+
+	if req.RedeemPoints > balance {
+		return storage.ErrNotEnoughPoints
+	}
+
+	var total, base float64
+	err = tx.QueryRow(ctx, `
+		SELECT
+			COALESCE(SUM(sp.total_amount), 0),
+			COALESCE(SUM(sp.total_amount * COALESCE(cr.percent, dr.percent, 0) / 100), 0)
+		FROM sale_products AS sp
+		LEFT JOIN loyalty_earn_rate AS cr ON cr.category_id = sp.category_id
+		LEFT JOIN loyalty_earn_rate AS dr ON dr.category_id IS NULL
+		WHERE sp.sale_id = $1 AND sp.gift_card_id IS NULL`,
+		req.SaleID,
+	).Scan(&total, &base)
+	if err != nil {
+		return err
+	}
+
+	var paid, earned = math.Max(total-req.RedeemPoints, 0), 0.0
+	if total > 0 {
+		earned = math.Floor(base*multiplier*paid/total*100) / 100
+	}
+
+	if req.RedeemPoints > 0 {
+		balance -= req.RedeemPoints
+		err = insertLoyaltyTransaction(ctx, tx, req.CustomerID, req.SaleID, config.LoyaltyRedeem, -req.RedeemPoints, balance, "")
+		if err != nil {
+			return err
+		}
+	}
+
+	if earned > 0 {
+		balance += earned
+		err = insertLoyaltyTransaction(ctx, tx, req.CustomerID, req.SaleID, config.LoyaltyEarn, earned, balance, "")
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE customer
+			SET
+				points_balance = $2,
+				total_spent = total_spent + $3,
+				tier_id = `+tierForSpentQuery("customer.total_spent + $3")+`,
+				updated_at = NOW()
+		WHERE id = $1`,
+		req.CustomerID,
+		balance,
+		paid,
+	)
+	return err
+}
+
+// reverseSaleLoyalty undoes the booking of a returned sale on its customer:
+// the points it redeemed come back, the points it earned go (the balance
+// never goes below zero) and its money part, total less the redeemed
+// points, comes off the total spent, which may move the customer down a
+// tier. It returns the redeemed points.
+func reverseSaleLoyalty(ctx context.Context, tx pgx.Tx, saleID, customerID string, total float64) (float64, error) {
+
+	var balance float64
+	err := tx.QueryRow(ctx,
+		"SELECT points_balance FROM customer WHERE id = $1 FOR UPDATE",
+		customerID,
+	).Scan(&balance)
+	if err != nil {
+		return 0, err
+	}
+
+	var earned, redeemed float64
+	err = tx.QueryRow(ctx, `
+		SELECT
+			COALESCE(SUM(points) FILTER (WHERE type = $2), 0),
+			COALESCE(-SUM(points) FILTER (WHERE type = $3), 0)
+		FROM loyalty_transaction
+		WHERE sale_id = $1`,
+		saleID,
+		config.LoyaltyEarn,
+		config.LoyaltyRedeem,
+	).Scan(&earned, &redeemed)
+	if err != nil {
+		return 0, err
+	}
+
+	var newBalance = math.Max(balance+redeemed-earned, 0)
+	if newBalance != balance {
+		err = insertLoyaltyTransaction(ctx, tx, customerID, saleID, config.LoyaltyAdjust, newBalance-balance, newBalance, "sale return")
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE customer
+			SET
+				points_balance = $2,
+				total_spent = GREATEST(total_spent - $3, 0),
+				tier_id = `+tierForSpentQuery("GREATEST(customer.total_spent - $3, 0)")+`,
+				updated_at = NOW()
+		WHERE id = $1`,
+		customerID,
+		newBalance,
+		math.Max(total-redeemed, 0),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return redeemed, nil
+}
+
+// Adjust changes the points balance by hand. The balance never goes below
+// zero.
+func (r *loyaltyRepo) Adjust(ctx context.Context, req *models.AdjustLoyaltyPoints) error {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var balance float64
+	err = tx.QueryRow(ctx, "SELECT points_balance FROM customer WHERE id = $1 FOR UPDATE", req.CustomerID).Scan(&balance)
+	if err != nil {
+		return err
+	}
+
+	balance += req.Points
+	if balance < 0 {
+		return storage.ErrNotEnoughPoints
+	}
+
+	err = insertLoyaltyTransaction(ctx, tx, req.CustomerID, "", config.LoyaltyAdjust, req.Points, balance, req.Comment)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, "UPDATE customer SET points_balance = $2, updated_at = NOW() WHERE id = $1", req.CustomerID, balance)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func insertLoyaltyTransaction(ctx context.Context, tx pgx.Tx, customerID, saleID, transactionType string, points, balanceAfter float64, comment string) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO loyalty_transaction(
+			id,
+			customer_id,
+			sale_id,
+			type,
+			points,
+			balance_after,
+			comment
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		uuid.New().String(),
+		customerID,
+		helpers.NewNullString(saleID),
+		transactionType,
+		points,
+		balanceAfter,
+		helpers.NewNullString(comment),
+	)
+	return err
+}
+
+// History lists the points movements of a customer, newest first.
+func (r *loyaltyRepo) History(ctx context.Context, req *models.LoyaltyHistoryRequest) (*models.LoyaltyHistoryResponse, error) {
+	var (
+		resp   models.LoyaltyHistoryResponse
+		offset = " OFFSET 0"
+		limit  = " LIMIT 10"
+	)
+
+	if req.Offset > 0 {
+		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
+	}
+
+	if req.Limit > 0 {
+		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
+	}
+
+	var query = `
+		SELECT
+			COUNT(*) OVER(),
+			id,
+			customer_id,
+			sale_id,
+			type,
+			points,
+			balance_after,
+			comment,
+			created_at
+		FROM loyalty_transaction
+		WHERE customer_id = $1
+		ORDER BY created_at DESC
+	`
+
+	query += offset + limit
+	rows, err := r.db.Query(ctx, query, req.CustomerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			ID           sql.NullString
+			CustomerID   sql.NullString
+			SaleID       sql.NullString
+			Type         sql.NullString
+			Points       sql.NullFloat64
+			BalanceAfter sql.NullFloat64
+			Comment      sql.NullString
+			CreatedAt    sql.NullString
+		)
+
+		err = rows.Scan(
+			&resp.Count,
+			&ID,
+			&CustomerID,
+			&SaleID,
+			&Type,
+			&Points,
+			&BalanceAfter,
+			&Comment,
+			&CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Transactions = append(resp.Transactions, &models.LoyaltyTransaction{
+			Id:           ID.String,
+			CustomerID:   CustomerID.String,
+			SaleID:       SaleID.String,
+			Type:         Type.String,
+			Points:       Points.Float64,
+			BalanceAfter: BalanceAfter.Float64,
+			Comment:      Comment.String,
+			CreatedAt:    CreatedAt.String,
+		})
+	}
+
+	return &resp, rows.Err()
+}
+
+// RepeatCustomerReport counts, per branch, the finished sales, how many of
+// them had a customer attached, the distinct customers and how many of those
+// came back more than once in the period.
+func (r *loyaltyRepo) RepeatCustomerReport(ctx context.Context, req *models.RepeatCustomerReportRequest) (*models.RepeatCustomerReportResponse, error) {
+
+	var (
+		resp  models.RepeatCustomerReportResponse
+		query = `
+			WITH sales AS (
+				SELECT branch_id, customer_id
+				FROM sale
+				WHERE status = $1
+					AND ($2::timestamp IS NULL OR created_at >= $2)
+					AND ($3::timestamp IS NULL OR created_at < $3)
+					AND ($4::uuid IS NULL OR branch_id = $4)
+			), visits AS (
+				SELECT branch_id, customer_id, COUNT(*) AS visits
+				FROM sales
+				WHERE customer_id IS NOT NULL
+				GROUP BY branch_id, customer_id
+			)
+			SELECT
+				b.id,
+				b.name,
+				(SELECT COUNT(*) FROM sales AS s WHERE s.branch_id = b.id),
+				COALESCE(SUM(v.visits), 0),
+				COUNT(v.customer_id),
+				COUNT(v.customer_id) FILTER (WHERE v.visits > 1)
+			FROM branch AS b
+			LEFT JOIN visits AS v ON v.branch_id = b.id
+			WHERE EXISTS (SELECT 1 FROM sales AS s WHERE s.branch_id = b.id)
+			GROUP BY b.id, b.name
+			ORDER BY b.name
+		`
+	)
+
+	rows, err := r.db.Query(ctx, query,
+		config.SaleStatusFinished,
+		helpers.NewNullString(req.FromDate),
+		helpers.NewNullString(req.ToDate),
+		helpers.NewNullString(req.BranchID),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var report models.RepeatCustomerReport
+
+		err = rows.Scan(
+			&report.BranchID,
+			&report.BranchName,
+			&report.Sales,
+			&report.CustomerSales,
+			&report.Customers,
+			&report.RepeatCustomers,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if report.Customers > 0 {
+			report.RepeatRate = math.Round(float64(report.RepeatCustomers)/float64(report.Customers)*10000) / 100
+		}
+
+		resp.Branches = append(resp.Branches, &report)
+	}
+
+	return &resp, rows.Err()
+}
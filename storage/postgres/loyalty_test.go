@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/storage"
+)
+
+func Test_saleRepo_Finish_Loyalty(t *testing.T) {
+
+	cfg := config.Load()
+
+	strg, err := NewConnectionPostgres(&cfg)
+	if err != nil {
+		t.Fatalf("failed to connect to PostgreSQL: %v", err)
+	}
+
+	var (
+		ctx  = context.Background()
+		item = newTestStockItem(t, strg, 10)
+	)
+
+	_, err = strg.Loyalty().SetEarnRate(ctx, &models.SetLoyaltyEarnRate{CategoryID: item.CategoryID, Percent: 10})
+	if err != nil {
+		t.Fatalf("loyaltyRepo.SetEarnRate() error = %v", err)
+	}
+
+	customer, err := strg.Customer().Create(ctx, &models.CreateCustomer{Phone: fmt.Sprintf("+998%09d", rand.Intn(1e9))})
+	if err != nil {
+		t.Fatalf("customerRepo.Create() error = %v", err)
+	}
+
+	err = strg.Loyalty().Adjust(ctx, &models.AdjustLoyaltyPoints{CustomerID: customer.Id, Points: 30})
+	if err != nil {
+		t.Fatalf("loyaltyRepo.Adjust() error = %v", err)
+	}
+
+	// the sale earns 10% of the 170 paid in cash, times the tier multiplier
+	var multiplier = 1.0
+	if customer.TierID != "" {
+		tier, err := strg.LoyaltyTier().GetByID(ctx, &models.LoyaltyTierPrimaryKey{Id: customer.TierID})
+		if err != nil {
+			t.Fatalf("loyaltyTierRepo.GetByID() error = %v", err)
+		}
+		multiplier = tier.EarnMultiplier
+	}
+
+	tests := []struct {
+		name    string
+		points  float64
+		wantErr error
+		stock   int
+		balance float64
+	}{
+		{
+			name:    "more points than the balance",
+			points:  1000,
+			wantErr: storage.ErrNotEnoughPoints,
+			stock:   10,
+			balance: 30,
+		},
+		{
+			name:    "points and cash",
+			points:  30,
+			stock:   8,
+			balance: math.Floor(17*multiplier*100) / 100,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sale, transactionID := newTestSale(t, strg, item.BranchID)
+			addTestSaleProduct(t, strg, sale.Id, item, 2, 100)
+
+			_, err := strg.Customer().AttachToSale(ctx, &models.SaleCustomer{SaleID: sale.Id, CustomerID: customer.Id})
+			if err != nil {
+				t.Fatalf("customerRepo.AttachToSale() error = %v", err)
+			}
+
+			err = strg.Sale().Finish(ctx, &models.FinishSale{
+				Id:            sale.Id,
+				BranchID:      item.BranchID,
+				TransactionID: transactionID,
+				Payment:       &models.Payment{Points: tt.points, Cash: 200 - tt.points, TotalAmount: 200},
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("saleRepo.Finish() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got := testStock(t, strg, item.BranchID, item.Barcode); got != tt.stock {
+				t.Errorf("saleRepo.Finish() stock = %v, want %v", got, tt.stock)
+			}
+
+			got, err := strg.Customer().GetByID(ctx, &models.CustomerPrimaryKey{Id: customer.Id})
+			if err != nil {
+				t.Fatalf("customerRepo.GetByID() error = %v", err)
+			}
+			if got.PointsBalance != tt.balance {
+				t.Errorf("saleRepo.Finish() points balance = %v, want %v", got.PointsBalance, tt.balance)
+			}
+
+			if tt.wantErr != nil {
+				checkMovements(t, testMovements(t, strg, sale.Id), config.StockMovementSale)
+				return
+			}
+
+			checkMovements(t, testMovements(t, strg, sale.Id), config.StockMovementSale, -2)
+
+			if got.TotalSpent != 170 {
+				t.Errorf("saleRepo.Finish() total spent = %v, want 170", got.TotalSpent)
+			}
+
+			history, err := strg.Loyalty().History(ctx, &models.LoyaltyHistoryRequest{CustomerID: customer.Id, Limit: 10})
+			if err != nil {
+				t.Fatalf("loyaltyRepo.History() error = %v", err)
+			}
+
+			var redeemed, earned float64
+			for _, transaction := range history.Transactions {
+				if transaction.SaleID != sale.Id {
+					continue
+				}
+				switch transaction.Type {
+				case config.LoyaltyRedeem:
+					redeemed += transaction.Points
+				case config.LoyaltyEarn:
+					earned += transaction.Points
+				}
+			}
+			if redeemed != -30 || earned != tt.balance {
+				t.Errorf("loyaltyRepo.History() redeemed = %v, earned = %v, want -30 and %v", redeemed, earned, tt.balance)
+			}
+
+			err = strg.Sale().Finish(ctx, &models.FinishSale{
+				Id:            sale.Id,
+				BranchID:      item.BranchID,
+				TransactionID: transactionID,
+				Payment:       &models.Payment{Cash: 200, TotalAmount: 200},
+			})
+			if !errors.Is(err, storage.ErrSaleFinished) {
+				t.Errorf("saleRepo.Finish() again error = %v, want %v", err, storage.ErrSaleFinished)
+			}
+			if got := testStock(t, strg, item.BranchID, item.Barcode); got != tt.stock {
+				t.Errorf("saleRepo.Finish() again stock = %v, want %v", got, tt.stock)
+			}
+		})
+	}
+}
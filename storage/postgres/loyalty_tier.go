@@ -0,0 +1,240 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"market_system/models"
+	"market_system/pkg/criteria"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type loyaltyTierRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewLoyaltyTierRepo(db *pgxpool.Pool) *loyaltyTierRepo {
+	return &loyaltyTierRepo{
+		db: db,
+	}
+}
+
+func (r *loyaltyTierRepo) Create(ctx context.Context, req *models.CreateLoyaltyTier) (*models.LoyaltyTier, error) {
+
+	var (
+		tierID = uuid.New().String()
+		query  = `
+			INSERT INTO loyalty_tier(
+				id,
+				name,
+				min_spent,
+				earn_multiplier,
+				updated_at
+			) VALUES ($1, $2, $3, $4, NOW())`
+	)
+
+	_, err := r.db.Exec(ctx,
+		query,
+		tierID,
+		req.Name,
+		req.MinSpent,
+		req.EarnMultiplier,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.regrade(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, &models.LoyaltyTierPrimaryKey{Id: tierID})
+}
+
+func (r *loyaltyTierRepo) GetByID(ctx context.Context, req *models.LoyaltyTierPrimaryKey) (*models.LoyaltyTier, error) {
+
+	var (
+		query = `
+			SELECT
+				id,
+				name,
+				min_spent,
+				earn_multiplier,
+				created_at,
+				updated_at
+			FROM loyalty_tier
+			WHERE id = $1
+		`
+	)
+
+	var (
+		ID             sql.NullString
+		Name           sql.NullString
+		MinSpent       sql.NullFloat64
+		EarnMultiplier sql.NullFloat64
+		CreatedAt      sql.NullString
+		UpdatedAt      sql.NullString
+	)
+
+	err := r.db.QueryRow(ctx, query, req.Id).Scan(
+		&ID,
+		&Name,
+		&MinSpent,
+		&EarnMultiplier,
+		&CreatedAt,
+		&UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.LoyaltyTier{
+		Id:             ID.String,
+		Name:           Name.String,
+		MinSpent:       MinSpent.Float64,
+		EarnMultiplier: EarnMultiplier.Float64,
+		CreatedAt:      CreatedAt.String,
+		UpdatedAt:      UpdatedAt.String,
+	}, nil
+}
+
+var loyaltyTierSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":              {Column: "id", Type: criteria.UUID},
+		"name":            {Column: "name", Type: criteria.Text},
+		"min_spent":       {Column: "min_spent", Type: criteria.Number},
+		"earn_multiplier": {Column: "earn_multiplier", Type: criteria.Number},
+		"created_at":      {Column: "created_at", Type: criteria.Time},
+		"updated_at":      {Column: "updated_at", Type: criteria.Time},
+	},
+	Search: []string{"name"},
+	Sort:   []criteria.Sort{{Field: "min_spent"}},
+}
+
+func (r *loyaltyTierRepo) GetList(ctx context.Context, req *models.GetListLoyaltyTierRequest) (*models.GetListLoyaltyTierResponse, error) {
+	var (
+		resp  models.GetListLoyaltyTierResponse
+		where = loyaltyTierSchema.Query()
+	)
+
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	where.Search(req.Search)
+
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var query = `
+		SELECT
+			COUNT(*) OVER(),
+			id,
+			name,
+			min_spent,
+			earn_multiplier,
+			created_at,
+			updated_at
+		FROM loyalty_tier
+	`
+
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			ID             sql.NullString
+			Name           sql.NullString
+			MinSpent       sql.NullFloat64
+			EarnMultiplier sql.NullFloat64
+			CreatedAt      sql.NullString
+			UpdatedAt      sql.NullString
+		)
+
+		err = rows.Scan(
+			&resp.Count,
+			&ID,
+			&Name,
+			&MinSpent,
+			&EarnMultiplier,
+			&CreatedAt,
+			&UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.LoyaltyTiers = append(resp.LoyaltyTiers, &models.LoyaltyTier{
+			Id:             ID.String,
+			Name:           Name.String,
+			MinSpent:       MinSpent.Float64,
+			EarnMultiplier: EarnMultiplier.Float64,
+			CreatedAt:      CreatedAt.String,
+			UpdatedAt:      UpdatedAt.String,
+		})
+	}
+
+	return &resp, rows.Err()
+}
+
+func (r *loyaltyTierRepo) Update(ctx context.Context, req *models.UpdateLoyaltyTier) (int64, error) {
+
+	query := `
+		UPDATE loyalty_tier
+			SET
+				name = $2,
+				min_spent = $3,
+				earn_multiplier = $4,
+				updated_at = NOW()
+		WHERE id = $1
+	`
+	rowsAffected, err := r.db.Exec(ctx,
+		query,
+		req.Id,
+		req.Name,
+		req.MinSpent,
+		req.EarnMultiplier,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.regrade(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
+
+func (r *loyaltyTierRepo) Delete(ctx context.Context, req *models.LoyaltyTierPrimaryKey) error {
+	_, err := r.db.Exec(ctx, "DELETE FROM loyalty_tier WHERE id = $1", req.Id)
+	if err != nil {
+		return err
+	}
+
+	return r.regrade(ctx)
+}
+
+// regrade moves every customer to the tier matching their total_spent after
+// the tier thresholds changed.
+func (r *loyaltyTierRepo) regrade(ctx context.Context) error {
+	_, err := r.db.Exec(ctx, `UPDATE customer SET tier_id = `+tierForSpentQuery("customer.total_spent"))
+	return err
+}
+
+// tierForSpentQuery selects the highest tier whose threshold the given
+// total spent reached.
+func tierForSpentQuery(totalSpent string) string {
+	return `(SELECT id FROM loyalty_tier WHERE min_spent <= ` + totalSpent + ` ORDER BY min_spent DESC LIMIT 1)`
+}
@@ -3,9 +3,9 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	"fmt"
 
 	"market_system/models"
+	"market_system/pkg/criteria"
 	"market_system/pkg/helpers"
 
 	"github.com/google/uuid"
@@ -34,9 +34,12 @@ func (r *paymentRepo) Create(ctx context.Context, req *models.CreatePayment) (*m
 			click,
 			humo,
 			apelsin,
+			points,
+			gift_card,
+			gift_card_code,
 			total_amount,
 			updated_at
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW())
 	`
 
 	_, err := r.db.Exec(ctx,
@@ -49,6 +52,9 @@ func (r *paymentRepo) Create(ctx context.Context, req *models.CreatePayment) (*m
 		req.Click,
 		req.Humo,
 		req.Apelsin,
+		req.Points,
+		req.GiftCard,
+		helpers.NewNullString(req.GiftCardCode),
 		req.TotalAmount,
 	)
 
@@ -70,6 +76,9 @@ func (r *paymentRepo) GetByID(ctx context.Context, req *models.PaymentPrimaryKey
 			click,
 			humo,
 			apelsin,
+			points,
+			gift_card,
+			gift_card_code,
 			total_amount,
 			created_at,
 			updated_at
@@ -77,17 +86,20 @@ func (r *paymentRepo) GetByID(ctx context.Context, req *models.PaymentPrimaryKey
 		WHERE id = $1
 	`
 	var (
-		Id          sql.NullString
-		SaleID      sql.NullString
-		Cash        sql.NullFloat64
-		Uzcard      sql.NullFloat64
-		Payme       sql.NullFloat64
-		Click       sql.NullFloat64
-		Humo        sql.NullFloat64
-		Apelsin     sql.NullFloat64
-		TotalAmount sql.NullFloat64
-		CreatedAt   sql.NullString
-		UpdatedAt   sql.NullString
+		Id           sql.NullString
+		SaleID       sql.NullString
+		Cash         sql.NullFloat64
+		Uzcard       sql.NullFloat64
+		Payme        sql.NullFloat64
+		Click        sql.NullFloat64
+		Humo         sql.NullFloat64
+		Apelsin      sql.NullFloat64
+		Points       sql.NullFloat64
+		GiftCard     sql.NullFloat64
+		GiftCardCode sql.NullString
+		TotalAmount  sql.NullFloat64
+		CreatedAt    sql.NullString
+		UpdatedAt    sql.NullString
 	)
 	err := r.db.QueryRow(ctx, query, req.Id).Scan(
 		&Id,
@@ -98,6 +110,9 @@ func (r *paymentRepo) GetByID(ctx context.Context, req *models.PaymentPrimaryKey
 		&Click,
 		&Humo,
 		&Apelsin,
+		&Points,
+		&GiftCard,
+		&GiftCardCode,
 		&TotalAmount,
 		&CreatedAt,
 		&UpdatedAt,
@@ -108,43 +123,59 @@ func (r *paymentRepo) GetByID(ctx context.Context, req *models.PaymentPrimaryKey
 	}
 
 	return &models.Payment{
-		Id:          Id.String,
-		SaleID:      SaleID.String,
-		Cash:        Cash.Float64,
-		Uzcard:      Uzcard.Float64,
-		Payme:       Payme.Float64,
-		Click:       Click.Float64,
-		Humo:        Humo.Float64,
-		Apelsin:     Apelsin.Float64,
-		TotalAmount: TotalAmount.Float64,
-		CreatedAt:   CreatedAt.String,
-		UpdatedAt:   UpdatedAt.String,
+		Id:           Id.String,
+		SaleID:       SaleID.String,
+		Cash:         Cash.Float64,
+		Uzcard:       Uzcard.Float64,
+		Payme:        Payme.Float64,
+		Click:        Click.Float64,
+		Humo:         Humo.Float64,
+		Apelsin:      Apelsin.Float64,
+		Points:       Points.Float64,
+		GiftCard:     GiftCard.Float64,
+		GiftCardCode: GiftCardCode.String,
+		TotalAmount:  TotalAmount.Float64,
+		CreatedAt:    CreatedAt.String,
+		UpdatedAt:    UpdatedAt.String,
 	}, nil
 }
 
+var paymentSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":             {Column: "id", Type: criteria.UUID},
+		"sale_id":        {Column: "sale_id", Type: criteria.UUID},
+		"cash":           {Column: "cash", Type: criteria.Number},
+		"uzcard":         {Column: "uzcard", Type: criteria.Number},
+		"payme":          {Column: "payme", Type: criteria.Number},
+		"click":          {Column: "click", Type: criteria.Number},
+		"humo":           {Column: "humo", Type: criteria.Number},
+		"apelsin":        {Column: "apelsin", Type: criteria.Number},
+		"points":         {Column: "points", Type: criteria.Number},
+		"gift_card":      {Column: "gift_card", Type: criteria.Number},
+		"gift_card_code": {Column: "gift_card_code", Type: criteria.Text},
+		"total_amount":   {Column: "total_amount", Type: criteria.Number},
+		"created_at":     {Column: "created_at", Type: criteria.Time},
+		"updated_at":     {Column: "updated_at", Type: criteria.Time},
+	},
+	Search: []string{"sale_id"},
+	Sort:   []criteria.Sort{{Field: "created_at", Desc: true}},
+}
+
 func (r *paymentRepo) GetList(ctx context.Context, req *models.GetListPaymentRequest) (*models.GetListPaymentResponse, error) {
 	var (
-		resp   models.GetListPaymentResponse
-		where  = " WHERE TRUE"
-		offset = " OFFSET 0"
-		limit  = " LIMIT 10"
-		sort   = " ORDER BY created_at DESC"
+		resp  models.GetListPaymentResponse
+		where = paymentSchema.Query()
 	)
 
-	if req.Offset > 0 {
-		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
-	}
-
-	if req.Limit > 0 {
-		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
-	}
-
-	if len(req.Search) > 0 {
-		where += fmt.Sprintf(" AND sale_id ILIKE '%%%s%%'", req.Search)
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
 	}
+	where.Search(req.Search)
 
-	if len(req.Query) > 0 {
-		where += req.Query
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
 	}
 
 	query := `
@@ -158,14 +189,17 @@ func (r *paymentRepo) GetList(ctx context.Context, req *models.GetListPaymentReq
 			click,
 			humo,
 			apelsin,
+			points,
+			gift_card,
+			gift_card_code,
 			total_amount,
 			created_at,
 			updated_at
 		FROM payment
 	`
 
-	query += where + sort + offset + limit
-	rows, err := r.db.Query(ctx, query)
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
 	if err != nil {
 		return nil, err
 	}
@@ -173,17 +207,20 @@ func (r *paymentRepo) GetList(ctx context.Context, req *models.GetListPaymentReq
 	for rows.Next() {
 
 		var (
-			Id          sql.NullString
-			SaleID      sql.NullString
-			Cash        sql.NullFloat64
-			Uzcard      sql.NullFloat64
-			Payme       sql.NullFloat64
-			Click       sql.NullFloat64
-			Humo        sql.NullFloat64
-			Apelsin     sql.NullFloat64
-			TotalAmount sql.NullFloat64
-			CreatedAt   sql.NullString
-			UpdatedAt   sql.NullString
+			Id           sql.NullString
+			SaleID       sql.NullString
+			Cash         sql.NullFloat64
+			Uzcard       sql.NullFloat64
+			Payme        sql.NullFloat64
+			Click        sql.NullFloat64
+			Humo         sql.NullFloat64
+			Apelsin      sql.NullFloat64
+			Points       sql.NullFloat64
+			GiftCard     sql.NullFloat64
+			GiftCardCode sql.NullString
+			TotalAmount  sql.NullFloat64
+			CreatedAt    sql.NullString
+			UpdatedAt    sql.NullString
 		)
 		err = rows.Scan(
 			&resp.Count,
@@ -195,6 +232,9 @@ func (r *paymentRepo) GetList(ctx context.Context, req *models.GetListPaymentReq
 			&Click,
 			&Humo,
 			&Apelsin,
+			&Points,
+			&GiftCard,
+			&GiftCardCode,
 			&TotalAmount,
 			&CreatedAt,
 			&UpdatedAt,
@@ -203,17 +243,20 @@ func (r *paymentRepo) GetList(ctx context.Context, req *models.GetListPaymentReq
 			return nil, err
 		}
 		resp.Payments = append(resp.Payments, &models.Payment{
-			Id:          Id.String,
-			SaleID:      SaleID.String,
-			Cash:        Cash.Float64,
-			Uzcard:      Uzcard.Float64,
-			Payme:       Payme.Float64,
-			Click:       Click.Float64,
-			Humo:        Humo.Float64,
-			Apelsin:     Apelsin.Float64,
-			TotalAmount: TotalAmount.Float64,
-			CreatedAt:   CreatedAt.String,
-			UpdatedAt:   UpdatedAt.String,
+			Id:           Id.String,
+			SaleID:       SaleID.String,
+			Cash:         Cash.Float64,
+			Uzcard:       Uzcard.Float64,
+			Payme:        Payme.Float64,
+			Click:        Click.Float64,
+			Humo:         Humo.Float64,
+			Apelsin:      Apelsin.Float64,
+			Points:       Points.Float64,
+			GiftCard:     GiftCard.Float64,
+			GiftCardCode: GiftCardCode.String,
+			TotalAmount:  TotalAmount.Float64,
+			CreatedAt:    CreatedAt.String,
+			UpdatedAt:    UpdatedAt.String,
 		})
 	}
 
@@ -230,7 +273,10 @@ func (r *paymentRepo) Update(ctx context.Context, req *models.UpdatePayment) (in
 			click = $5,
 			humo = $6,
 			apelsin = $7,
-			total_amount = $8,
+			points = $8,
+			gift_card = $9,
+			gift_card_code = $10,
+			total_amount = $11,
 			updated_at = NOW()
 		WHERE id = $1
 	`
@@ -244,6 +290,9 @@ func (r *paymentRepo) Update(ctx context.Context, req *models.UpdatePayment) (in
 		req.Click,
 		req.Humo,
 		req.Apelsin,
+		req.Points,
+		req.GiftCard,
+		helpers.NewNullString(req.GiftCardCode),
 		req.TotalAmount,
 	)
 	if err != nil {
@@ -11,22 +11,43 @@ import (
 )
 
 type Store struct {
-	db             *pgxpool.Pool
-	category       storage.CategoryRepoI
-	user           storage.UserRepoI
-	branch         storage.BranchRepoI
-	sale_point     storage.SalePointRepoI
-	supplier       storage.SupplierRepoI
-	product        storage.ProductRepoI
-	income         storage.IncomeRepoI
-	income_product storage.IncomeProductRepoI
-	remainder      storage.RemainderRepoI
-	sale           storage.SaleRepoI
-	sale_product   storage.SaleProductRepoI
-	payment        storage.PaymentRepoI
-	transaction    storage.TransactionRepoI
-	shift          storage.ShiftRepoI
-	brand          storage.BrandRepoI
+	db               *pgxpool.Pool
+	category         storage.CategoryRepoI
+	user             storage.UserRepoI
+	branch           storage.BranchRepoI
+	sale_point       storage.SalePointRepoI
+	supplier         storage.SupplierRepoI
+	product          storage.ProductRepoI
+	income           storage.IncomeRepoI
+	income_product   storage.IncomeProductRepoI
+	remainder        storage.RemainderRepoI
+	sale             storage.SaleRepoI
+	sale_product     storage.SaleProductRepoI
+	payment          storage.PaymentRepoI
+	transaction      storage.TransactionRepoI
+	shift            storage.ShiftRepoI
+	brand            storage.BrandRepoI
+	branch_price     storage.BranchPriceRepoI
+	price_change     storage.PriceChangeRepoI
+	promotion        storage.PromotionRepoI
+	customer         storage.CustomerRepoI
+	loyalty_tier     storage.LoyaltyTierRepoI
+	loyalty          storage.LoyaltyRepoI
+	gift_card        storage.GiftCardRepoI
+	purchase_order   storage.PurchaseOrderRepoI
+	supplier_payable storage.SupplierPayableRepoI
+	supplier_return  storage.SupplierReturnRepoI
+	transfer         storage.TransferRepoI
+	stock_movement   storage.StockMovementRepoI
+	inventory_count  storage.InventoryCountRepoI
+	write_off        storage.WriteOffRepoI
+	stock_lot        storage.StockLotRepoI
+	stock_level      storage.StockLevelRepoI
+	report           storage.ReportRepoI
+	product_class    storage.ProductClassRepoI
+	export_job       storage.ExportJobRepoI
+	product_import   storage.ProductImportRepoI
+	supplier_invoice storage.SupplierInvoiceRepoI
 }
 
 func NewConnectionPostgres(cfg *config.Config) (storage.StorageI, error) {
@@ -193,3 +214,192 @@ func (s *Store) Shift() storage.ShiftRepoI {
 
 	return s.shift
 }
+
+func (s *Store) BranchPrice() storage.BranchPriceRepoI {
+
+	if s.branch_price == nil {
+		s.branch_price = NewBranchPriceRepo(s.db)
+	}
+
+	return s.branch_price
+}
+
+func (s *Store) PriceChange() storage.PriceChangeRepoI {
+
+	if s.price_change == nil {
+		s.price_change = NewPriceChangeRepo(s.db)
+	}
+
+	return s.price_change
+}
+
+func (s *Store) Promotion() storage.PromotionRepoI {
+
+	if s.promotion == nil {
+		s.promotion = NewPromotionRepo(s.db)
+	}
+
+	return s.promotion
+}
+
+func (s *Store) Customer() storage.CustomerRepoI {
+
+	if s.customer == nil {
+		s.customer = NewCustomerRepo(s.db)
+	}
+
+	return s.customer
+}
+
+func (s *Store) LoyaltyTier() storage.LoyaltyTierRepoI {
+
+	if s.loyalty_tier == nil {
+		s.loyalty_tier = NewLoyaltyTierRepo(s.db)
+	}
+
+	return s.loyalty_tier
+}
+
+func (s *Store) Loyalty() storage.LoyaltyRepoI {
+
+	if s.loyalty == nil {
+		s.loyalty = NewLoyaltyRepo(s.db)
+	}
+
+	return s.loyalty
+}
+
+func (s *Store) GiftCard() storage.GiftCardRepoI {
+
+	if s.gift_card == nil {
+		s.gift_card = NewGiftCardRepo(s.db)
+	}
+
+	return s.gift_card
+}
+
+func (s *Store) PurchaseOrder() storage.PurchaseOrderRepoI {
+
+	if s.purchase_order == nil {
+		s.purchase_order = NewPurchaseOrderRepo(s.db)
+	}
+
+	return s.purchase_order
+}
+
+func (s *Store) SupplierPayable() storage.SupplierPayableRepoI {
+
+	if s.supplier_payable == nil {
+		s.supplier_payable = NewSupplierPayableRepo(s.db)
+	}
+
+	return s.supplier_payable
+}
+
+func (s *Store) SupplierReturn() storage.SupplierReturnRepoI {
+
+	if s.supplier_return == nil {
+		s.supplier_return = NewSupplierReturnRepo(s.db)
+	}
+
+	return s.supplier_return
+}
+
+func (s *Store) Transfer() storage.TransferRepoI {
+
+	if s.transfer == nil {
+		s.transfer = NewTransferRepo(s.db)
+	}
+
+	return s.transfer
+}
+
+func (s *Store) StockMovement() storage.StockMovementRepoI {
+
+	if s.stock_movement == nil {
+		s.stock_movement = NewStockMovementRepo(s.db)
+	}
+
+	return s.stock_movement
+}
+
+func (s *Store) InventoryCount() storage.InventoryCountRepoI {
+
+	if s.inventory_count == nil {
+		s.inventory_count = NewInventoryCountRepo(s.db)
+	}
+
+	return s.inventory_count
+}
+
+func (s *Store) WriteOff() storage.WriteOffRepoI {
+
+	if s.write_off == nil {
+		s.write_off = NewWriteOffRepo(s.db)
+	}
+
+	return s.write_off
+}
+
+func (s *Store) StockLot() storage.StockLotRepoI {
+
+	if s.stock_lot == nil {
+		s.stock_lot = NewStockLotRepo(s.db)
+	}
+
+	return s.stock_lot
+}
+
+func (s *Store) StockLevel() storage.StockLevelRepoI {
+
+	if s.stock_level == nil {
+		s.stock_level = NewStockLevelRepo(s.db)
+	}
+
+	return s.stock_level
+}
+
+func (s *Store) Report() storage.ReportRepoI {
+
+	if s.report == nil {
+		s.report = NewReportRepo(s.db)
+	}
+
+	return s.report
+}
+
+func (s *Store) ProductClass() storage.ProductClassRepoI {
+
+	if s.product_class == nil {
+		s.product_class = NewProductClassRepo(s.db)
+	}
+
+	return s.product_class
+}
+
+func (s *Store) ExportJob() storage.ExportJobRepoI {
+
+	if s.export_job == nil {
+		s.export_job = NewExportJobRepo(s.db)
+	}
+
+	return s.export_job
+}
+
+func (s *Store) ProductImport() storage.ProductImportRepoI {
+
+	if s.product_import == nil {
+		s.product_import = NewProductImportRepo(s.db)
+	}
+
+	return s.product_import
+}
+
+func (s *Store) SupplierInvoice() storage.SupplierInvoiceRepoI {
+
+	if s.supplier_invoice == nil {
+		s.supplier_invoice = NewSupplierInvoiceRepo(s.db)
+	}
+
+	return s.supplier_invoice
+}
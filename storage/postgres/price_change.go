@@ -0,0 +1,493 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type priceChangeRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPriceChangeRepo(db *pgxpool.Pool) *priceChangeRepo {
+	return &priceChangeRepo{
+		db: db,
+	}
+}
+
+func (r *priceChangeRepo) Create(ctx context.Context, req *models.CreatePriceChange) (*models.PriceChange, error) {
+
+	var (
+		priceChangeID = uuid.New().String()
+		query         = `
+			INSERT INTO price_change(
+				id,
+				branch_id,
+				effective_from,
+				status,
+				comment,
+				updated_at
+			) VALUES ($1, $2, COALESCE($3::timestamp, NOW()), $4, $5, NOW())`
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx,
+		query,
+		priceChangeID,
+		req.BranchID,
+		helpers.NewNullString(req.EffectiveFrom),
+		config.PriceChangeDraft,
+		helpers.NewNullString(req.Comment),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.insertProducts(ctx, tx, priceChangeID, req.Products)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, &models.PriceChangePrimaryKey{Id: priceChangeID})
+}
+
+// insertProducts stores the lines of a price change. old_price is the price
+// in effect at the branch at the moment the line is written, so the document
+// shows what it is going to change.
+func (r *priceChangeRepo) insertProducts(ctx context.Context, tx pgx.Tx, priceChangeID string, products []*models.CreatePriceChangeProduct) error {
+
+	var query = `
+		INSERT INTO price_change_product(
+			id,
+			price_change_id,
+			product_id,
+			old_price,
+			new_price,
+			updated_at
+		)
+		SELECT
+			$1,
+			pc.id,
+			p.id,
+			COALESCE((
+				SELECT bp.price
+				FROM branch_price AS bp
+				WHERE bp.branch_id = pc.branch_id AND bp.product_id = p.id AND bp.effective_from <= NOW()
+				ORDER BY bp.effective_from DESC, bp.created_at DESC
+				LIMIT 1
+			), p.price),
+			$4,
+			NOW()
+		FROM price_change AS pc, product AS p
+		WHERE pc.id = $2 AND p.id = $3
+	`
+
+	for _, product := range products {
+		result, err := tx.Exec(ctx,
+			query,
+			uuid.New().String(),
+			priceChangeID,
+			product.ProductID,
+			product.NewPrice,
+		)
+		if err != nil {
+			return err
+		}
+
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("product %s not found", product.ProductID)
+		}
+	}
+
+	return nil
+}
+
+func (r *priceChangeRepo) GetByID(ctx context.Context, req *models.PriceChangePrimaryKey) (*models.PriceChange, error) {
+
+	var (
+		query = `
+			SELECT
+				id,
+				branch_id,
+				effective_from,
+				status,
+				comment,
+				approved_by,
+				approved_at,
+				created_at,
+				updated_at
+			FROM price_change
+			WHERE id = $1
+		`
+	)
+
+	var (
+		ID            sql.NullString
+		BranchID      sql.NullString
+		EffectiveFrom sql.NullString
+		Status        sql.NullString
+		Comment       sql.NullString
+		ApprovedBy    sql.NullString
+		ApprovedAt    sql.NullString
+		CreatedAt     sql.NullString
+		UpdatedAt     sql.NullString
+	)
+
+	err := r.db.QueryRow(ctx, query, req.Id).Scan(
+		&ID,
+		&BranchID,
+		&EffectiveFrom,
+		&Status,
+		&Comment,
+		&ApprovedBy,
+		&ApprovedAt,
+		&CreatedAt,
+		&UpdatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	products, err := r.getProducts(ctx, ID.String)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PriceChange{
+		Id:            ID.String,
+		BranchID:      BranchID.String,
+		EffectiveFrom: EffectiveFrom.String,
+		Status:        Status.String,
+		Comment:       Comment.String,
+		ApprovedBy:    ApprovedBy.String,
+		ApprovedAt:    ApprovedAt.String,
+		Products:      products,
+		CreatedAt:     CreatedAt.String,
+		UpdatedAt:     UpdatedAt.String,
+	}, nil
+}
+
+func (r *priceChangeRepo) getProducts(ctx context.Context, priceChangeID string) ([]*models.PriceChangeProduct, error) {
+
+	var (
+		products []*models.PriceChangeProduct
+		query    = `
+			SELECT
+				id,
+				price_change_id,
+				product_id,
+				old_price,
+				new_price,
+				created_at,
+				updated_at
+			FROM price_change_product
+			WHERE price_change_id = $1
+			ORDER BY created_at
+		`
+	)
+
+	rows, err := r.db.Query(ctx, query, priceChangeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			ID            sql.NullString
+			PriceChangeID sql.NullString
+			ProductID     sql.NullString
+			OldPrice      sql.NullFloat64
+			NewPrice      sql.NullFloat64
+			CreatedAt     sql.NullString
+			UpdatedAt     sql.NullString
+		)
+
+		err = rows.Scan(
+			&ID,
+			&PriceChangeID,
+			&ProductID,
+			&OldPrice,
+			&NewPrice,
+			&CreatedAt,
+			&UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		products = append(products, &models.PriceChangeProduct{
+			Id:            ID.String,
+			PriceChangeID: PriceChangeID.String,
+			ProductID:     ProductID.String,
+			OldPrice:      OldPrice.Float64,
+			NewPrice:      NewPrice.Float64,
+			CreatedAt:     CreatedAt.String,
+			UpdatedAt:     UpdatedAt.String,
+		})
+	}
+
+	return products, rows.Err()
+}
+
+var priceChangeSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":             {Column: "id", Type: criteria.UUID},
+		"branch_id":      {Column: "branch_id", Type: criteria.UUID},
+		"effective_from": {Column: "effective_from", Type: criteria.Time},
+		"status":         {Column: "status", Type: criteria.Text},
+		"approved_by":    {Column: "approved_by", Type: criteria.UUID},
+		"approved_at":    {Column: "approved_at", Type: criteria.Time},
+		"created_at":     {Column: "created_at", Type: criteria.Time},
+		"updated_at":     {Column: "updated_at", Type: criteria.Time},
+	},
+	Sort: []criteria.Sort{{Field: "effective_from", Desc: true}},
+}
+
+func (r *priceChangeRepo) GetList(ctx context.Context, req *models.GetListPriceChangeRequest) (*models.GetListPriceChangeResponse, error) {
+	var (
+		resp  models.GetListPriceChangeResponse
+		where = priceChangeSchema.Query()
+	)
+
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var query = `
+		SELECT
+			COUNT(*) OVER(),
+			id,
+			branch_id,
+			effective_from,
+			status,
+			comment,
+			approved_by,
+			approved_at,
+			created_at,
+			updated_at
+		FROM price_change
+	`
+
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			ID            sql.NullString
+			BranchID      sql.NullString
+			EffectiveFrom sql.NullString
+			Status        sql.NullString
+			Comment       sql.NullString
+			ApprovedBy    sql.NullString
+			ApprovedAt    sql.NullString
+			CreatedAt     sql.NullString
+			UpdatedAt     sql.NullString
+		)
+
+		err = rows.Scan(
+			&resp.Count,
+			&ID,
+			&BranchID,
+			&EffectiveFrom,
+			&Status,
+			&Comment,
+			&ApprovedBy,
+			&ApprovedAt,
+			&CreatedAt,
+			&UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.PriceChanges = append(resp.PriceChanges, &models.PriceChange{
+			Id:            ID.String,
+			BranchID:      BranchID.String,
+			EffectiveFrom: EffectiveFrom.String,
+			Status:        Status.String,
+			Comment:       Comment.String,
+			ApprovedBy:    ApprovedBy.String,
+			ApprovedAt:    ApprovedAt.String,
+			CreatedAt:     CreatedAt.String,
+			UpdatedAt:     UpdatedAt.String,
+		})
+	}
+
+	return &resp, nil
+}
+
+// Update rewrites a draft price change. Approved and canceled documents are
+// left untouched and 0 rows affected is returned.
+func (r *priceChangeRepo) Update(ctx context.Context, req *models.UpdatePriceChange) (int64, error) {
+
+	query := `
+		UPDATE price_change
+			SET
+				effective_from = COALESCE($2::timestamp, effective_from),
+				comment = $3,
+				updated_at = NOW()
+		WHERE id = $1 AND status = $4
+	`
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	rowsAffected, err := tx.Exec(ctx,
+		query,
+		req.Id,
+		helpers.NewNullString(req.EffectiveFrom),
+		helpers.NewNullString(req.Comment),
+		config.PriceChangeDraft,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	if rowsAffected.RowsAffected() == 0 {
+		return 0, nil
+	}
+
+	_, err = tx.Exec(ctx, "DELETE FROM price_change_product WHERE price_change_id = $1", req.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.insertProducts(ctx, tx, req.Id, req.Products)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
+
+func (r *priceChangeRepo) Delete(ctx context.Context, req *models.PriceChangePrimaryKey) error {
+	_, err := r.db.Exec(ctx, "DELETE FROM price_change WHERE id = $1 AND status <> $2", req.Id, config.PriceChangeApproved)
+	return err
+}
+
+// Approve writes one branch_price row per line, effective from the
+// document's effective_from, so a change approved today for tomorrow's
+// opening is picked up by Resolve without any background job.
+func (r *priceChangeRepo) Approve(ctx context.Context, req *models.ApprovePriceChange) (int64, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	rowsAffected, err := tx.Exec(ctx, `
+		UPDATE price_change
+			SET
+				status = $2,
+				approved_by = $3,
+				approved_at = NOW(),
+				updated_at = NOW()
+		WHERE id = $1 AND status = $4
+	`,
+		req.Id,
+		config.PriceChangeApproved,
+		helpers.NewNullString(req.ApprovedBy),
+		config.PriceChangeDraft,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	if rowsAffected.RowsAffected() == 0 {
+		return 0, nil
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO branch_price(
+			id,
+			branch_id,
+			product_id,
+			price,
+			effective_from,
+			price_change_id,
+			updated_at
+		)
+		SELECT
+			gen_random_uuid(),
+			pc.branch_id,
+			pcp.product_id,
+			pcp.new_price,
+			pc.effective_from,
+			pc.id,
+			NOW()
+		FROM price_change_product AS pcp
+		JOIN price_change AS pc ON pc.id = pcp.price_change_id
+		WHERE pc.id = $1
+	`, req.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
+
+func (r *priceChangeRepo) Cancel(ctx context.Context, req *models.PriceChangePrimaryKey) (int64, error) {
+
+	rowsAffected, err := r.db.Exec(ctx, `
+		UPDATE price_change
+			SET
+				status = $2,
+				updated_at = NOW()
+		WHERE id = $1 AND status = $3
+	`,
+		req.Id,
+		config.PriceChangeCanceled,
+		config.PriceChangeDraft,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
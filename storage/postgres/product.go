@@ -4,9 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strings"
 
 	"market_system/models"
+	"market_system/pkg/criteria"
 	"market_system/pkg/helpers"
+	"market_system/pkg/productimport"
+	"market_system/pkg/textsearch"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v4/pgxpool"
@@ -34,8 +38,11 @@ func (r *productRepo) Create(ctx context.Context, req *models.CreateProduct) (*m
 				category_id, 
 				barcode,
 				price,
+				unit,
+				brand_id,
+				search_key,
 				updated_at
-			) VALUES ($1, $2, $3, $4, $5, $6, NOW())`
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, COALESCE($8, (SELECT brand_id FROM category WHERE id = $4)), $9, NOW())`
 	)
 
 	_, err := r.db.Exec(ctx,
@@ -46,6 +53,9 @@ func (r *productRepo) Create(ctx context.Context, req *models.CreateProduct) (*m
 		helpers.NewNullString(req.CategoryID),
 		helpers.NewNullString(req.Barcode),
 		req.Price,
+		productUnit(req.Unit),
+		helpers.NewNullString(req.BrandID),
+		textsearch.Key(req.Title),
 	)
 
 	if err != nil {
@@ -66,6 +76,8 @@ func (r *productRepo) GetByID(ctx context.Context, req *models.ProductPrimaryKey
 				category_id,
 				COALESCE(barcode, ''),
 				COALESCE(price, 0),
+				unit,
+				brand_id,
 				created_at,
 				updated_at
 			FROM  product
@@ -80,6 +92,8 @@ func (r *productRepo) GetByID(ctx context.Context, req *models.ProductPrimaryKey
 		CategoryID sql.NullString
 		Barcode    sql.NullString
 		Price      sql.NullFloat64
+		Unit       sql.NullString
+		BrandID    sql.NullString
 		CreatedAt  sql.NullString
 		UpdatedAt  sql.NullString
 	)
@@ -91,6 +105,8 @@ func (r *productRepo) GetByID(ctx context.Context, req *models.ProductPrimaryKey
 		&CategoryID,
 		&Barcode,
 		&Price,
+		&Unit,
+		&BrandID,
 		&CreatedAt,
 		&UpdatedAt,
 	)
@@ -106,33 +122,52 @@ func (r *productRepo) GetByID(ctx context.Context, req *models.ProductPrimaryKey
 		CategoryID: CategoryID.String,
 		Barcode:    Barcode.String,
 		Price:      Price.Float64,
+		Unit:       Unit.String,
+		BrandID:    BrandID.String,
 		CreatedAt:  CreatedAt.String,
 		UpdatedAt:  UpdatedAt.String,
 	}, nil
 }
 
+var productSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":          {Column: "id", Type: criteria.UUID},
+		"photo":       {Column: "photo", Type: criteria.Text},
+		"title":       {Column: "title", Type: criteria.Text},
+		"category_id": {Column: "category_id", Type: criteria.UUID, Tree: "category_id IN (SELECT id FROM category_subtree(%s))"},
+		"barcode":     {Column: "barcode", Type: criteria.Text},
+		"price":       {Column: "price", Type: criteria.Number},
+		"unit":        {Column: "unit", Type: criteria.Text},
+		"brand_id":    {Column: "brand_id", Type: criteria.UUID},
+		"created_at":  {Column: "created_at", Type: criteria.Time},
+		"updated_at":  {Column: "updated_at", Type: criteria.Time},
+	},
+	Sort: []criteria.Sort{{Field: "created_at", Desc: true}},
+}
+
 func (r *productRepo) GetList(ctx context.Context, req *models.GetListProductRequest) (*models.GetListProductResponse, error) {
 	var (
-		resp   models.GetListProductResponse
-		where  = " WHERE TRUE"
-		offset = " OFFSET 0"
-		limit  = " LIMIT 10"
-		sort   = " ORDER BY created_at DESC"
+		resp  models.GetListProductResponse
+		where = productSchema.Query()
 	)
 
-	if req.Offset > 0 {
-		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
 	}
+	productSearch(where, req.Search)
 
-	if req.Limit > 0 {
-		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
-	}
-	if len(req.Search) > 0 {
-		where += " AND (title ILIKE '%" + req.Search + "%' OR barcode ILIKE '%" + req.Search + "%')"
+	if len(req.ABC) > 0 || len(req.XYZ) > 0 {
+		var branch string
+		if len(req.BranchID) > 0 {
+			branch = where.Arg(req.BranchID)
+		}
+		where.And(latestProductClass(where, branch, "product.barcode", req.ABC, req.XYZ))
 	}
 
-	if len(req.Query) > 0 {
-		where += req.Query
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
 	}
 
 	var query = `
@@ -144,13 +179,15 @@ func (r *productRepo) GetList(ctx context.Context, req *models.GetListProductReq
 			category_id,
 			barcode,
 			price,
+			unit,
+			brand_id,
 			created_at,
 			updated_at
 		FROM product
 	`
 
-	query += where + sort + offset + limit
-	rows, err := r.db.Query(ctx, query)
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
 	if err != nil {
 		return nil, err
 	}
@@ -164,6 +201,8 @@ func (r *productRepo) GetList(ctx context.Context, req *models.GetListProductReq
 			CategoryID sql.NullString
 			Barcode    sql.NullString
 			Price      sql.NullFloat64
+			Unit       sql.NullString
+			BrandID    sql.NullString
 			CreatedAt  sql.NullString
 			UpdatedAt  sql.NullString
 		)
@@ -176,6 +215,8 @@ func (r *productRepo) GetList(ctx context.Context, req *models.GetListProductReq
 			&CategoryID,
 			&Barcode,
 			&Price,
+			&Unit,
+			&BrandID,
 			&CreatedAt,
 			&UpdatedAt,
 		)
@@ -190,6 +231,8 @@ func (r *productRepo) GetList(ctx context.Context, req *models.GetListProductReq
 			CategoryID: CategoryID.String,
 			Barcode:    Barcode.String,
 			Price:      Price.Float64,
+			Unit:       Unit.String,
+			BrandID:    BrandID.String,
 			CreatedAt:  CreatedAt.String,
 			UpdatedAt:  UpdatedAt.String,
 		})
@@ -208,6 +251,9 @@ func (r *productRepo) Update(ctx context.Context, req *models.UpdateProduct) (in
 				category_id = $4,
 				barcode = $5,
 				price = $6, 
+				unit = $7,
+				brand_id = $8,
+				search_key = $9,
 				updated_at = NOW()
 		WHERE id = $1
 	`
@@ -219,6 +265,9 @@ func (r *productRepo) Update(ctx context.Context, req *models.UpdateProduct) (in
 		helpers.NewNullString(req.CategoryID),
 		helpers.NewNullString(req.Barcode),
 		req.Price,
+		productUnit(req.Unit),
+		helpers.NewNullString(req.BrandID),
+		textsearch.Key(req.Title),
 	)
 	if err != nil {
 		return 0, err
@@ -227,7 +276,185 @@ func (r *productRepo) Update(ctx context.Context, req *models.UpdateProduct) (in
 	return rowsAffected.RowsAffected(), nil
 }
 
+// productSearch adds the condition of a product list search: the exact
+// barcode, or a title whose search key holds the search key of the text,
+// which the trigram index of search_key serves.
+func productSearch(where *criteria.Query, text string) {
+
+	if len(text) == 0 {
+		return
+	}
+
+	var (
+		barcode = "barcode = " + where.Arg(strings.TrimSpace(text))
+		key     = textsearch.Key(text)
+	)
+	if len(key) == 0 {
+		where.And(barcode)
+		return
+	}
+
+	where.And("(" + barcode + " OR search_key LIKE " + where.Arg("%"+key+"%") + ")")
+}
+
+// Search returns the products matching a lookup as it is typed at the point
+// of sale, best first: the product with the barcode, then titles starting
+// with the query, then titles with words starting with its words or similar
+// enough to it to take typos, ranked by how well they match.
+func (r *productRepo) Search(ctx context.Context, req *models.SearchProductRequest) (*models.SearchProductResponse, error) {
+	var (
+		resp  models.SearchProductResponse
+		where = productSchema.Query()
+		key   = textsearch.Key(req.Query)
+	)
+
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		barcode = where.Arg(strings.TrimSpace(req.Query))
+		matches = []string{"barcode = " + barcode}
+		rank    = "CASE WHEN barcode = " + barcode + " THEN 4 ELSE 0 END"
+	)
+	if len(key) > 0 {
+		var (
+			similar = where.Arg(key)
+			words   = "to_tsquery('simple', " + where.Arg(textsearch.Prefix(req.Query)) + ")"
+		)
+		matches = append(matches, "search_vector @@ "+words, similar+" <% search_key")
+		rank += " + CASE WHEN search_key LIKE " + where.Arg(key+"%") + " THEN 2 ELSE 0 END" +
+			" + ts_rank(search_vector, " + words + ")" +
+			" + word_similarity(" + similar + ", search_key)"
+	}
+	where.And("(" + strings.Join(matches, " OR ") + ")")
+
+	var query = `
+		SELECT
+			id,
+			photo,
+			title,
+			category_id,
+			barcode,
+			price,
+			unit,
+			brand_id,
+			created_at,
+			updated_at
+		FROM product
+	`
+
+	query += where.SQL() + " ORDER BY " + rank + " DESC, title" + fmt.Sprintf(" LIMIT %d", criteria.Limit(req.Limit))
+	rows, err := r.db.Query(ctx, query, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+
+		var (
+			ID         sql.NullString
+			Photo      sql.NullString
+			Title      sql.NullString
+			CategoryID sql.NullString
+			Barcode    sql.NullString
+			Price      sql.NullFloat64
+			Unit       sql.NullString
+			BrandID    sql.NullString
+			CreatedAt  sql.NullString
+			UpdatedAt  sql.NullString
+		)
+
+		err = rows.Scan(
+			&ID,
+			&Photo,
+			&Title,
+			&CategoryID,
+			&Barcode,
+			&Price,
+			&Unit,
+			&BrandID,
+			&CreatedAt,
+			&UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Products = append(resp.Products, &models.Product{
+			Id:         ID.String,
+			Photo:      Photo.String,
+			Title:      Title.String,
+			CategoryID: CategoryID.String,
+			Barcode:    Barcode.String,
+			Price:      Price.Float64,
+			Unit:       Unit.String,
+			BrandID:    BrandID.String,
+			CreatedAt:  CreatedAt.String,
+			UpdatedAt:  UpdatedAt.String,
+		})
+	}
+
+	return &resp, rows.Err()
+}
+
+// IndexSearch writes the search keys of the products that have none, those
+// made before search was added or written to the database directly, and
+// returns how many it wrote.
+func (r *productRepo) IndexSearch(ctx context.Context) (int64, error) {
+
+	var indexed int64
+	for {
+		rows, err := r.db.Query(ctx, "SELECT id, title FROM product WHERE search_key IS NULL LIMIT 1000")
+		if err != nil {
+			return indexed, err
+		}
+
+		var ids, keys []string
+		for rows.Next() {
+			var id, title string
+			if err = rows.Scan(&id, &title); err != nil {
+				rows.Close()
+				return indexed, err
+			}
+			ids = append(ids, id)
+			keys = append(keys, textsearch.Key(title))
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return indexed, err
+		}
+
+		if len(ids) == 0 {
+			return indexed, nil
+		}
+
+		rowsAffected, err := r.db.Exec(ctx,
+			`UPDATE product p
+				SET search_key = k.key
+			FROM UNNEST($1::TEXT[], $2::TEXT[]) AS k(id, key)
+			WHERE p.id = k.id::UUID`,
+			ids,
+			keys,
+		)
+		if err != nil {
+			return indexed, err
+		}
+		indexed += rowsAffected.RowsAffected()
+	}
+}
+
 func (r *productRepo) Delete(ctx context.Context, req *models.ProductPrimaryKey) error {
 	_, err := r.db.Exec(ctx, "DELETE FROM product WHERE id = $1", req.Id)
 	return err
 }
+
+// productUnit returns the unit of a product, pieces when none is given.
+func productUnit(unit string) string {
+	if len(unit) == 0 {
+		return productimport.Units[0]
+	}
+	return unit
+}
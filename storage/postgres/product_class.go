@@ -0,0 +1,519 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/classify"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type productClassRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewProductClassRepo(db *pgxpool.Pool) *productClassRepo {
+	return &productClassRepo{
+		db: db,
+	}
+}
+
+const productClassRunColumns = `
+	pr.id,
+	TO_CHAR(pr.run_date, 'YYYY-MM-DD'),
+	TO_CHAR(pr.from_date, 'YYYY-MM-DD'),
+	TO_CHAR(pr.to_date, 'YYYY-MM-DD'),
+	pr.history_weeks,
+	pr.products,
+	pr.created_by,
+	TO_CHAR(pr.created_at, 'YYYY-MM-DD HH24:MI:SS')
+`
+
+func scanProductClassRun(row pgx.Row, extra ...interface{}) (*models.ProductClassRun, error) {
+
+	var (
+		ID           sql.NullString
+		RunDate      sql.NullString
+		FromDate     sql.NullString
+		ToDate       sql.NullString
+		HistoryWeeks sql.NullInt64
+		Products     sql.NullInt64
+		CreatedBy    sql.NullString
+		CreatedAt    sql.NullString
+	)
+
+	dest := append(extra,
+		&ID,
+		&RunDate,
+		&FromDate,
+		&ToDate,
+		&HistoryWeeks,
+		&Products,
+		&CreatedBy,
+		&CreatedAt,
+	)
+
+	err := row.Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ProductClassRun{
+		Id:           ID.String,
+		RunDate:      RunDate.String,
+		FromDate:     FromDate.String,
+		ToDate:       ToDate.String,
+		HistoryWeeks: HistoryWeeks.Int64,
+		Products:     Products.Int64,
+		CreatedBy:    CreatedBy.String,
+		CreatedAt:    CreatedAt.String,
+	}, nil
+}
+
+// productClassSales lists, per branch and barcode, the revenue and units of
+// every week of [$1, $2) with finished sales, then every barcode the branch
+// has in stock with week -1 so that products that did not sell are classified
+// too.
+const productClassSales = `
+	SELECT
+		s.branch_id,
+		sp.barcode,
+		COALESCE(MAX(sp.product_name), ''),
+		(s.created_at::date - $1::date) / 7,
+		COALESCE(SUM(sp.total_amount), 0),
+		COALESCE(SUM(sp.quantity), 0)
+	FROM sale AS s
+	JOIN sale_products AS sp ON sp.sale_id = s.id
+	WHERE s.status = $3
+		AND s.created_at >= $1::date
+		AND s.created_at < $2::date
+		AND COALESCE(sp.barcode, '') <> ''
+	GROUP BY s.branch_id, sp.barcode, (s.created_at::date - $1::date) / 7
+	UNION ALL
+	SELECT
+		rm.branch_id,
+		rm.barcode,
+		COALESCE(MAX(rm.product_name), ''),
+		-1,
+		0,
+		0
+	FROM remainder AS rm
+	WHERE COALESCE(rm.quantity, 0) > 0 AND COALESCE(rm.barcode, '') <> ''
+	GROUP BY rm.branch_id, rm.barcode
+	ORDER BY 1, 2
+`
+
+// productClassItem is a barcode of a branch being classified.
+type productClassItem struct {
+	barcode string
+	name    string
+	units   int64
+	item    classify.Item
+}
+
+// Run classifies the products of every branch on the sales of the last
+// req.HistoryWeeks weeks before today and stores the result as a new run.
+func (r *productClassRepo) Run(ctx context.Context, req *models.RunProductClass) (*models.ProductClassRun, error) {
+
+	var (
+		runID    = uuid.New().String()
+		fromDate string
+		toDate   string
+	)
+
+	err := r.db.QueryRow(ctx,
+		"SELECT TO_CHAR(CURRENT_DATE - $1::int * 7, 'YYYY-MM-DD'), TO_CHAR(CURRENT_DATE, 'YYYY-MM-DD')",
+		req.HistoryWeeks,
+	).Scan(&fromDate, &toDate)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, productClassSales, fromDate, toDate, config.SaleStatusFinished)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		branches []string
+		items    = map[string][]*productClassItem{}
+		index    = map[string]*productClassItem{}
+	)
+	for rows.Next() {
+		var (
+			branchID string
+			barcode  string
+			name     string
+			week     int64
+			revenue  float64
+			units    int64
+		)
+
+		err = rows.Scan(&branchID, &barcode, &name, &week, &revenue, &units)
+		if err != nil {
+			return nil, err
+		}
+
+		item, ok := index[branchID+"/"+barcode]
+		if !ok {
+			if _, ok := items[branchID]; !ok {
+				branches = append(branches, branchID)
+			}
+
+			item = &productClassItem{
+				barcode: barcode,
+				name:    name,
+				item:    classify.Item{Demand: make([]float64, req.HistoryWeeks)},
+			}
+			index[branchID+"/"+barcode] = item
+			items[branchID] = append(items[branchID], item)
+		}
+
+		if week < 0 || week >= req.HistoryWeeks {
+			continue
+		}
+
+		item.units += units
+		item.item.Revenue += revenue
+		item.item.Demand[week] += float64(units)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO product_class_run(
+			id,
+			from_date,
+			to_date,
+			history_weeks,
+			products,
+			created_by
+		) VALUES ($1, $2, $3, $4, $5, $6)`,
+		runID,
+		fromDate,
+		toDate,
+		req.HistoryWeeks,
+		len(index),
+		helpers.NewNullString(req.CreatedBy),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var classRows [][]interface{}
+	for _, branchID := range branches {
+		var classItems = make([]classify.Item, len(items[branchID]))
+		for i, item := range items[branchID] {
+			classItems[i] = item.item
+		}
+
+		for i, class := range classify.Classify(classItems, classify.DefaultThresholds) {
+			var item = items[branchID][i]
+
+			classRows = append(classRows, []interface{}{
+				runID,
+				branchID,
+				item.barcode,
+				item.name,
+				item.item.Revenue,
+				item.units,
+				class.Share,
+				class.CumulativeShare,
+				class.ABC,
+				class.Mean,
+				class.CV,
+				class.XYZ,
+			})
+		}
+	}
+
+	_, err = tx.CopyFrom(ctx,
+		pgx.Identifier{"product_class"},
+		[]string{
+			"run_id",
+			"branch_id",
+			"barcode",
+			"product_name",
+			"revenue",
+			"units",
+			"share",
+			"cumulative_share",
+			"abc",
+			"weekly_mean",
+			"cv",
+			"xyz",
+		},
+		pgx.CopyFromRows(classRows),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetRun(ctx, &models.ProductClassRunPrimaryKey{Id: runID})
+}
+
+func (r *productClassRepo) GetRun(ctx context.Context, req *models.ProductClassRunPrimaryKey) (*models.ProductClassRun, error) {
+
+	var query = "SELECT " + productClassRunColumns + " FROM product_class_run AS pr WHERE pr.id = $1"
+
+	return scanProductClassRun(r.db.QueryRow(ctx, query, req.Id))
+}
+
+var productClassRunSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":            {Column: "pr.id", Type: criteria.UUID},
+		"run_date":      {Column: "pr.run_date", Type: criteria.Time},
+		"from_date":     {Column: "pr.from_date", Type: criteria.Time},
+		"to_date":       {Column: "pr.to_date", Type: criteria.Time},
+		"history_weeks": {Column: "pr.history_weeks", Type: criteria.Number},
+		"products":      {Column: "pr.products", Type: criteria.Number},
+		"created_by":    {Column: "pr.created_by", Type: criteria.UUID},
+		"created_at":    {Column: "pr.created_at", Type: criteria.Time},
+	},
+	Sort: []criteria.Sort{{Field: "created_at", Desc: true}},
+}
+
+func (r *productClassRepo) GetListRuns(ctx context.Context, req *models.GetListProductClassRunRequest) (*models.GetListProductClassRunResponse, error) {
+	var (
+		resp  models.GetListProductClassRunResponse
+		where = productClassRunSchema.Query()
+	)
+
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var query = "SELECT COUNT(*) OVER(), " + productClassRunColumns + " FROM product_class_run AS pr"
+
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		run, err := scanProductClassRun(rows, &resp.Count)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Runs = append(resp.Runs, run)
+	}
+
+	return &resp, rows.Err()
+}
+
+var productClassSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"branch_id":        {Column: "pc.branch_id", Type: criteria.UUID},
+		"branch_name":      {Column: "b.name", Type: criteria.Text},
+		"barcode":          {Column: "pc.barcode", Type: criteria.Text},
+		"product_name":     {Column: "pc.product_name", Type: criteria.Text},
+		"revenue":          {Column: "pc.revenue", Type: criteria.Number},
+		"units":            {Column: "pc.units", Type: criteria.Number},
+		"share":            {Column: "pc.share", Type: criteria.Number},
+		"cumulative_share": {Column: "pc.cumulative_share", Type: criteria.Number},
+		"abc":              {Column: "pc.abc", Type: criteria.Text},
+		"weekly_mean":      {Column: "pc.weekly_mean", Type: criteria.Number},
+		"cv":               {Column: "pc.cv", Type: criteria.Number},
+		"xyz":              {Column: "pc.xyz", Type: criteria.Text},
+	},
+	Search: []string{"barcode", "product_name"},
+	Sort: []criteria.Sort{
+		{Field: "branch_name"},
+		{Field: "revenue", Desc: true},
+		{Field: "barcode"},
+	},
+}
+
+// GetList lists the classes of a run, the latest run when req.RunID is empty,
+// with the number of products in every ABC/XYZ cell. The matrix honours the
+// filters and the search except the ones on the abc and xyz classes.
+func (r *productClassRepo) GetList(ctx context.Context, req *models.GetListProductClassRequest) (*models.GetListProductClassResponse, error) {
+	var (
+		resp = models.GetListProductClassResponse{
+			Matrix:  []*models.ProductClassCount{},
+			Classes: []*models.ProductClass{},
+		}
+		where   = productClassSchema.Query()
+		filters []criteria.Filter
+		classes []criteria.Filter
+		err     error
+	)
+
+	if len(req.RunID) > 0 {
+		resp.Run, err = r.GetRun(ctx, &models.ProductClassRunPrimaryKey{Id: req.RunID})
+	} else {
+		resp.Run, err = scanProductClassRun(r.db.QueryRow(ctx,
+			"SELECT "+productClassRunColumns+" FROM product_class_run AS pr ORDER BY pr.created_at DESC LIMIT 1",
+		))
+		if err == pgx.ErrNoRows {
+			return &resp, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	where.And("pc.run_id = " + where.Arg(resp.Run.Id))
+
+	for _, filter := range req.Filters {
+		if filter.Field == "abc" || filter.Field == "xyz" {
+			classes = append(classes, filter)
+		} else {
+			filters = append(filters, filter)
+		}
+	}
+
+	err = where.Filter(filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	where.Search(req.Search)
+
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	matrix, err := r.db.Query(ctx,
+		"SELECT pc.abc, pc.xyz, COUNT(*) FROM product_class AS pc JOIN branch AS b ON b.id = pc.branch_id"+
+			where.SQL()+" GROUP BY pc.abc, pc.xyz ORDER BY pc.abc, pc.xyz",
+		where.Args()...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer matrix.Close()
+
+	for matrix.Next() {
+		var cell models.ProductClassCount
+
+		err = matrix.Scan(&cell.ABC, &cell.XYZ, &cell.Products)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Matrix = append(resp.Matrix, &cell)
+	}
+	if err = matrix.Err(); err != nil {
+		return nil, err
+	}
+	matrix.Close()
+
+	err = where.Filter(classes...)
+	if err != nil {
+		return nil, err
+	}
+
+	var query = `
+		SELECT
+			COUNT(*) OVER(),
+			pc.run_id,
+			pc.branch_id,
+			b.name,
+			pc.barcode,
+			COALESCE(pc.product_name, ''),
+			pc.revenue,
+			pc.units,
+			pc.share,
+			pc.cumulative_share,
+			pc.abc,
+			pc.weekly_mean,
+			pc.cv,
+			pc.xyz
+		FROM product_class AS pc
+		JOIN branch AS b ON b.id = pc.branch_id
+	`
+
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var class = models.ProductClass{RunDate: resp.Run.RunDate}
+
+		err = rows.Scan(
+			&resp.Count,
+			&class.RunID,
+			&class.BranchID,
+			&class.BranchName,
+			&class.Barcode,
+			&class.ProductName,
+			&class.Revenue,
+			&class.Units,
+			&class.Share,
+			&class.CumulativeShare,
+			&class.ABC,
+			&class.WeeklyMean,
+			&class.CV,
+			&class.XYZ,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Classes = append(resp.Classes, &class)
+	}
+
+	return &resp, rows.Err()
+}
+
+// latestProductClass is a condition on the product with barcode column
+// barcode that holds when the latest classification run put it in the ABC
+// class abc and the XYZ class xyz, in the branch given by column branch or,
+// when branch is empty, in any branch. An empty class matches any class.
+func latestProductClass(where *criteria.Query, branch, barcode, abc, xyz string) string {
+
+	var condition = "pc.barcode = " + barcode
+
+	if len(branch) > 0 {
+		condition += " AND pc.branch_id = " + branch
+	}
+
+	if len(abc) > 0 {
+		condition += " AND pc.abc = " + where.Arg(abc)
+	}
+
+	if len(xyz) > 0 {
+		condition += " AND pc.xyz = " + where.Arg(xyz)
+	}
+
+	return `EXISTS (
+		SELECT 1
+		FROM product_class AS pc
+		WHERE pc.run_id = (SELECT id FROM product_class_run ORDER BY created_at DESC LIMIT 1)
+			AND ` + condition + `
+	)`
+}
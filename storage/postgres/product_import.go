@@ -0,0 +1,848 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+	"market_system/pkg/productimport"
+	"market_system/pkg/textsearch"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type productImportRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewProductImportRepo(db *pgxpool.Pool) *productImportRepo {
+	return &productImportRepo{
+		db: db,
+	}
+}
+
+const productImportColumns = `
+	id,
+	status,
+	file_name,
+	format,
+	total_rows,
+	valid_rows,
+	invalid_rows,
+	to_create,
+	to_update,
+	new_categories,
+	new_brands,
+	imported_rows,
+	created,
+	updated,
+	categories_created,
+	brands_created,
+	error,
+	created_by,
+	TO_CHAR(created_at, 'YYYY-MM-DD HH24:MI:SS'),
+	TO_CHAR(started_at, 'YYYY-MM-DD HH24:MI:SS'),
+	TO_CHAR(finished_at, 'YYYY-MM-DD HH24:MI:SS')
+`
+
+func scanProductImport(row pgx.Row, extra ...interface{}) (*models.ProductImport, error) {
+
+	var (
+		ID                sql.NullString
+		Status            sql.NullString
+		FileName          sql.NullString
+		Format            sql.NullString
+		TotalRows         sql.NullInt64
+		ValidRows         sql.NullInt64
+		InvalidRows       sql.NullInt64
+		ToCreate          sql.NullInt64
+		ToUpdate          sql.NullInt64
+		NewCategories     sql.NullInt64
+		NewBrands         sql.NullInt64
+		ImportedRows      sql.NullInt64
+		Created           sql.NullInt64
+		Updated           sql.NullInt64
+		CategoriesCreated sql.NullInt64
+		BrandsCreated     sql.NullInt64
+		Error             sql.NullString
+		CreatedBy         sql.NullString
+		CreatedAt         sql.NullString
+		StartedAt         sql.NullString
+		FinishedAt        sql.NullString
+	)
+
+	dest := append(extra,
+		&ID,
+		&Status,
+		&FileName,
+		&Format,
+		&TotalRows,
+		&ValidRows,
+		&InvalidRows,
+		&ToCreate,
+		&ToUpdate,
+		&NewCategories,
+		&NewBrands,
+		&ImportedRows,
+		&Created,
+		&Updated,
+		&CategoriesCreated,
+		&BrandsCreated,
+		&Error,
+		&CreatedBy,
+		&CreatedAt,
+		&StartedAt,
+		&FinishedAt,
+	)
+
+	err := row.Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ProductImport{
+		Id:                ID.String,
+		Status:            Status.String,
+		FileName:          FileName.String,
+		Format:            Format.String,
+		TotalRows:         TotalRows.Int64,
+		ValidRows:         ValidRows.Int64,
+		InvalidRows:       InvalidRows.Int64,
+		ToCreate:          ToCreate.Int64,
+		ToUpdate:          ToUpdate.Int64,
+		NewCategories:     NewCategories.Int64,
+		NewBrands:         NewBrands.Int64,
+		ImportedRows:      ImportedRows.Int64,
+		Created:           Created.Int64,
+		Updated:           Updated.Int64,
+		CategoriesCreated: CategoriesCreated.Int64,
+		BrandsCreated:     BrandsCreated.Int64,
+		Error:             Error.String,
+		CreatedBy:         CreatedBy.String,
+		CreatedAt:         CreatedAt.String,
+		StartedAt:         StartedAt.String,
+		FinishedAt:        FinishedAt.String,
+	}, nil
+}
+
+const productImportRowColumns = `
+	line,
+	COALESCE(title, ''),
+	COALESCE(barcode, ''),
+	category_path,
+	COALESCE(brand, ''),
+	COALESCE(price, 0),
+	COALESCE(unit, ''),
+	errors,
+	status,
+	product_id
+`
+
+func scanProductImportRow(row pgx.Row, extra ...interface{}) (*models.ProductImportRow, error) {
+
+	var (
+		Line      sql.NullInt64
+		Title     sql.NullString
+		Barcode   sql.NullString
+		Category  []string
+		Brand     sql.NullString
+		Price     sql.NullFloat64
+		Unit      sql.NullString
+		Errors    []string
+		Status    sql.NullString
+		ProductID sql.NullString
+	)
+
+	dest := append(extra,
+		&Line,
+		&Title,
+		&Barcode,
+		&Category,
+		&Brand,
+		&Price,
+		&Unit,
+		&Errors,
+		&Status,
+		&ProductID,
+	)
+
+	err := row.Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ProductImportRow{
+		Line:      Line.Int64,
+		Title:     Title.String,
+		Barcode:   Barcode.String,
+		Category:  Category,
+		Brand:     Brand.String,
+		Price:     Price.Float64,
+		Unit:      Unit.String,
+		Errors:    Errors,
+		Status:    Status.String,
+		ProductID: ProductID.String,
+	}, nil
+}
+
+// Create validates a product file and keeps its rows, counting what running
+// the import would create and update. A file that can not be read at all is
+// a *productimport.FileError.
+func (r *productImportRepo) Create(ctx context.Context, req *models.CreateProductImport) (*models.ProductImport, error) {
+
+	rows, err := productimport.Parse(req.File, req.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		importID = uuid.New().String()
+		copyRows = make([][]interface{}, 0, len(rows))
+		invalid  int
+	)
+
+	for _, row := range rows {
+		var status = config.ProductImportRowValid
+		if len(row.Errors) > 0 {
+			status = config.ProductImportRowInvalid
+			invalid++
+		}
+
+		var errors = row.Errors
+		if errors == nil {
+			errors = []string{}
+		}
+
+		var category = row.Category
+		if category == nil {
+			category = []string{}
+		}
+
+		copyRows = append(copyRows, []interface{}{
+			importID,
+			row.Line,
+			row.Title,
+			row.Barcode,
+			category,
+			row.Brand,
+			row.Price,
+			row.Unit,
+			errors,
+			status,
+		})
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO product_import(
+			id,
+			status,
+			file_name,
+			format,
+			total_rows,
+			valid_rows,
+			invalid_rows,
+			created_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`,
+		importID,
+		config.ProductImportValidated,
+		req.FileName,
+		req.Format,
+		len(rows),
+		len(rows)-invalid,
+		invalid,
+		helpers.NewNullString(req.CreatedBy),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.CopyFrom(ctx,
+		pgx.Identifier{"product_import_row"},
+		[]string{
+			"import_id",
+			"line",
+			"title",
+			"barcode",
+			"category_path",
+			"brand",
+			"price",
+			"unit",
+			"errors",
+			"status",
+		},
+		pgx.CopyFromRows(copyRows),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var toCreate, toUpdate, newBrands int64
+	err = tx.QueryRow(ctx,
+		`SELECT
+			COUNT(*) FILTER (WHERE p.id IS NULL),
+			COUNT(p.id),
+			COUNT(DISTINCT LOWER(r.brand)) FILTER (
+				WHERE r.brand <> '' AND NOT EXISTS (SELECT 1 FROM brand b WHERE LOWER(b.name) = LOWER(r.brand))
+			)
+		FROM product_import_row r
+		LEFT JOIN product p ON p.barcode = r.barcode
+		WHERE r.import_id = $1 AND r.status = $2`,
+		importID,
+		config.ProductImportRowValid,
+	).Scan(&toCreate, &toUpdate, &newBrands)
+	if err != nil {
+		return nil, err
+	}
+
+	categories, err := loadImportCategories(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	var newCategories int64
+	for _, row := range rows {
+		if len(row.Errors) > 0 {
+			continue
+		}
+
+		_, err = categories.resolve(row.Category, func(title, parentID string) (string, error) {
+			newCategories++
+			return "new:" + uuid.New().String(), nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.Exec(ctx,
+		`UPDATE product_import
+			SET
+				to_create = $2,
+				to_update = $3,
+				new_categories = $4,
+				new_brands = $5
+		WHERE id = $1`,
+		importID,
+		toCreate,
+		toUpdate,
+		newCategories,
+		newBrands,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, &models.ProductImportPrimaryKey{Id: importID})
+}
+
+func (r *productImportRepo) GetByID(ctx context.Context, req *models.ProductImportPrimaryKey) (*models.ProductImport, error) {
+
+	var query = "SELECT " + productImportColumns + " FROM product_import WHERE id = $1"
+
+	return scanProductImport(r.db.QueryRow(ctx, query, req.Id))
+}
+
+var productImportSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":                 {Column: "id", Type: criteria.UUID},
+		"status":             {Column: "status", Type: criteria.Text},
+		"file_name":          {Column: "file_name", Type: criteria.Text},
+		"format":             {Column: "format", Type: criteria.Text},
+		"total_rows":         {Column: "total_rows", Type: criteria.Number},
+		"valid_rows":         {Column: "valid_rows", Type: criteria.Number},
+		"invalid_rows":       {Column: "invalid_rows", Type: criteria.Number},
+		"imported_rows":      {Column: "imported_rows", Type: criteria.Number},
+		"created":            {Column: "created", Type: criteria.Number},
+		"updated":            {Column: "updated", Type: criteria.Number},
+		"categories_created": {Column: "categories_created", Type: criteria.Number},
+		"brands_created":     {Column: "brands_created", Type: criteria.Number},
+		"created_by":         {Column: "created_by", Type: criteria.UUID},
+		"created_at":         {Column: "created_at", Type: criteria.Time},
+		"started_at":         {Column: "started_at", Type: criteria.Time},
+		"finished_at":        {Column: "finished_at", Type: criteria.Time},
+	},
+	Search: []string{"file_name"},
+	Sort:   []criteria.Sort{{Field: "created_at", Desc: true}},
+}
+
+func (r *productImportRepo) GetList(ctx context.Context, req *models.GetListProductImportRequest) (*models.GetListProductImportResponse, error) {
+	var (
+		resp  models.GetListProductImportResponse
+		where = productImportSchema.Query()
+	)
+
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	where.Search(req.Search)
+
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var query = "SELECT COUNT(*) OVER(), " + productImportColumns + " FROM product_import"
+
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		productImport, err := scanProductImport(rows, &resp.Count)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.ProductImports = append(resp.ProductImports, productImport)
+	}
+
+	return &resp, rows.Err()
+}
+
+var productImportRowSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"line":       {Column: "line", Type: criteria.Number},
+		"title":      {Column: "title", Type: criteria.Text},
+		"barcode":    {Column: "barcode", Type: criteria.Text},
+		"brand":      {Column: "brand", Type: criteria.Text},
+		"price":      {Column: "price", Type: criteria.Number},
+		"unit":       {Column: "unit", Type: criteria.Text},
+		"status":     {Column: "status", Type: criteria.Text},
+		"product_id": {Column: "product_id", Type: criteria.UUID},
+	},
+	Search: []string{"title", "barcode"},
+	Sort:   []criteria.Sort{{Field: "line"}},
+}
+
+func (r *productImportRepo) GetRows(ctx context.Context, req *models.GetListProductImportRowRequest) (*models.GetListProductImportRowResponse, error) {
+	var (
+		resp  models.GetListProductImportRowResponse
+		where = productImportRowSchema.Query()
+	)
+
+	where.And("import_id = " + where.Arg(req.ImportID))
+
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	where.Search(req.Search)
+
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var query = "SELECT COUNT(*) OVER(), " + productImportRowColumns + " FROM product_import_row"
+
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row, err := scanProductImportRow(rows, &resp.Count)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Rows = append(resp.Rows, row)
+	}
+
+	return &resp, rows.Err()
+}
+
+// Start claims a validated or failed import for a run. It affects no rows
+// when the import is running or done.
+func (r *productImportRepo) Start(ctx context.Context, req *models.ProductImportPrimaryKey) (int64, error) {
+
+	rowsAffected, err := r.db.Exec(ctx,
+		`UPDATE product_import
+			SET
+				status = $2,
+				error = NULL,
+				started_at = COALESCE(started_at, NOW()),
+				finished_at = NULL
+		WHERE id = $1 AND status IN ($3, $4)`,
+		req.Id,
+		config.ProductImportRunning,
+		config.ProductImportValidated,
+		config.ProductImportFailed,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
+
+// Run imports the valid rows of a started import batch by batch, each batch
+// in a transaction. Imported rows are marked so a failed run resumes with the
+// first row left; the failure is recorded on the import.
+func (r *productImportRepo) Run(ctx context.Context, req *models.ProductImportPrimaryKey) (*models.ProductImport, error) {
+
+	err := r.run(ctx, req.Id)
+	if err != nil {
+		failCtx, cancel := context.WithTimeout(context.Background(), config.CtxTimeout)
+		defer cancel()
+
+		_, failErr := r.db.Exec(failCtx,
+			"UPDATE product_import SET status = $2, error = $3, finished_at = NOW() WHERE id = $1",
+			req.Id,
+			config.ProductImportFailed,
+			err.Error(),
+		)
+		if failErr != nil {
+			return nil, failErr
+		}
+
+		return nil, err
+	}
+
+	return r.GetByID(ctx, req)
+}
+
+func (r *productImportRepo) run(ctx context.Context, importID string) error {
+
+	categories, err := loadImportCategories(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	brands, err := loadImportBrands(ctx, r.db)
+	if err != nil {
+		return err
+	}
+
+	for {
+		done, err := r.runBatch(ctx, importID, categories, brands)
+		if err != nil {
+			return err
+		}
+		if done {
+			break
+		}
+	}
+
+	_, err = r.db.Exec(ctx,
+		"UPDATE product_import SET status = $2, finished_at = NOW() WHERE id = $1",
+		importID,
+		config.ProductImportDone,
+	)
+
+	return err
+}
+
+// runBatch imports the next batch of valid rows, reporting whether none were
+// left.
+func (r *productImportRepo) runBatch(ctx context.Context, importID string, categories *importCategories, brands map[string]string) (bool, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx,
+		"SELECT "+productImportRowColumns+" FROM product_import_row WHERE import_id = $1 AND status = $2 ORDER BY line LIMIT $3",
+		importID,
+		config.ProductImportRowValid,
+		config.ProductImportBatchSize,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	var batch []*models.ProductImportRow
+	for rows.Next() {
+		row, err := scanProductImportRow(rows)
+		if err != nil {
+			rows.Close()
+			return false, err
+		}
+
+		batch = append(batch, row)
+	}
+	if err = rows.Err(); err != nil {
+		return false, err
+	}
+	rows.Close()
+
+	if len(batch) == 0 {
+		return true, nil
+	}
+
+	var (
+		categoriesCreated int64
+		brandsCreated     int64
+		copyRows          = make([][]interface{}, 0, len(batch))
+	)
+
+	for _, row := range batch {
+		categoryID, err := categories.resolve(row.Category, func(title, parentID string) (string, error) {
+			var id = uuid.New().String()
+			_, err := tx.Exec(ctx,
+				"INSERT INTO category(id, title, parent_id, updated_at) VALUES ($1, $2, $3, NOW())",
+				id,
+				title,
+				helpers.NewNullString(parentID),
+			)
+			categoriesCreated++
+			return id, err
+		})
+		if err != nil {
+			return false, err
+		}
+
+		var brandID string
+		if len(row.Brand) > 0 {
+			var ok bool
+			if brandID, ok = brands[strings.ToLower(row.Brand)]; !ok {
+				brandID = uuid.New().String()
+				_, err = tx.Exec(ctx,
+					"INSERT INTO brand(id, name, updated_at) VALUES ($1, $2, NOW())",
+					brandID,
+					row.Brand,
+				)
+				if err != nil {
+					return false, err
+				}
+				brands[strings.ToLower(row.Brand)] = brandID
+				brandsCreated++
+			}
+		}
+
+		copyRows = append(copyRows, []interface{}{
+			row.Line,
+			uuid.New().String(),
+			row.Title,
+			row.Barcode,
+			categoryID,
+			helpers.NewNullString(brandID),
+			row.Price,
+			row.Unit,
+			textsearch.Key(row.Title),
+		})
+	}
+
+	_, err = tx.Exec(ctx,
+		`CREATE TEMP TABLE product_import_batch (
+			line INT,
+			id UUID,
+			title VARCHAR(255),
+			barcode VARCHAR(50),
+			category_id UUID,
+			brand_id UUID,
+			price DECIMAL(10, 2),
+			unit VARCHAR(10),
+			search_key TEXT
+		) ON COMMIT DROP`,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.CopyFrom(ctx,
+		pgx.Identifier{"product_import_batch"},
+		[]string{"line", "id", "title", "barcode", "category_id", "brand_id", "price", "unit", "search_key"},
+		pgx.CopyFromRows(copyRows),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	var created, updated int64
+	err = tx.QueryRow(ctx,
+		`WITH upserted AS (
+			INSERT INTO product(
+				id,
+				title,
+				category_id,
+				barcode,
+				price,
+				unit,
+				brand_id,
+				search_key,
+				updated_at
+			)
+			SELECT id, title, category_id, barcode, price, unit, brand_id, search_key, NOW()
+			FROM product_import_batch
+			ON CONFLICT (barcode) WHERE barcode IS NOT NULL AND barcode <> ''
+			DO UPDATE SET
+				title = EXCLUDED.title,
+				category_id = EXCLUDED.category_id,
+				price = EXCLUDED.price,
+				unit = EXCLUDED.unit,
+				brand_id = COALESCE(EXCLUDED.brand_id, product.brand_id),
+				search_key = EXCLUDED.search_key,
+				updated_at = NOW()
+			RETURNING id, barcode, (xmax = 0) AS inserted
+		), marked AS (
+			UPDATE product_import_row r
+				SET
+					status = $2,
+					product_id = u.id
+			FROM product_import_batch b
+			JOIN upserted u ON u.barcode = b.barcode
+			WHERE r.import_id = $1 AND r.line = b.line
+		)
+		SELECT
+			COUNT(*) FILTER (WHERE inserted),
+			COUNT(*) FILTER (WHERE NOT inserted)
+		FROM upserted`,
+		importID,
+		config.ProductImportRowImported,
+	).Scan(&created, &updated)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = tx.Exec(ctx,
+		`UPDATE product_import
+			SET
+				imported_rows = imported_rows + $2,
+				created = created + $3,
+				updated = updated + $4,
+				categories_created = categories_created + $5,
+				brands_created = brands_created + $6
+		WHERE id = $1`,
+		importID,
+		created+updated,
+		created,
+		updated,
+		categoriesCreated,
+		brandsCreated,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return false, nil
+}
+
+// FailUnfinished fails the imports a restart interrupted; they can be run
+// again to resume.
+func (r *productImportRepo) FailUnfinished(ctx context.Context) (int64, error) {
+
+	rowsAffected, err := r.db.Exec(ctx,
+		"UPDATE product_import SET status = $1, error = 'interrupted by restart', finished_at = NOW() WHERE status = $2",
+		config.ProductImportFailed,
+		config.ProductImportRunning,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
+
+type importQuerier interface {
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+}
+
+// importCategories finds categories by their parent and case-insensitive
+// title.
+type importCategories struct {
+	ids map[string]string
+}
+
+func loadImportCategories(ctx context.Context, db importQuerier) (*importCategories, error) {
+
+	rows, err := db.Query(ctx, "SELECT id, COALESCE(parent_id::TEXT, ''), title FROM category ORDER BY created_at")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories = &importCategories{ids: map[string]string{}}
+	for rows.Next() {
+		var id, parentID, title string
+		if err = rows.Scan(&id, &parentID, &title); err != nil {
+			return nil, err
+		}
+
+		var key = parentID + "/" + strings.ToLower(title)
+		if _, ok := categories.ids[key]; !ok {
+			categories.ids[key] = id
+		}
+	}
+
+	return categories, rows.Err()
+}
+
+// resolve returns the category at the end of path, calling create for each
+// category of it that does not exist yet.
+func (c *importCategories) resolve(path []string, create func(title, parentID string) (string, error)) (string, error) {
+
+	var parentID string
+	for _, title := range path {
+		var key = parentID + "/" + strings.ToLower(title)
+
+		id, ok := c.ids[key]
+		if !ok {
+			var err error
+			if id, err = create(title, parentID); err != nil {
+				return "", err
+			}
+			c.ids[key] = id
+		}
+
+		parentID = id
+	}
+
+	return parentID, nil
+}
+
+// loadImportBrands maps the lower-cased names of brands to their ids.
+func loadImportBrands(ctx context.Context, db importQuerier) (map[string]string, error) {
+
+	rows, err := db.Query(ctx, "SELECT id, name FROM brand")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var brands = map[string]string{}
+	for rows.Next() {
+		var id, name string
+		if err = rows.Scan(&id, &name); err != nil {
+			return nil, err
+		}
+
+		if _, ok := brands[strings.ToLower(name)]; !ok {
+			brands[strings.ToLower(name)] = id
+		}
+	}
+
+	return brands, rows.Err()
+}
@@ -0,0 +1,701 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type promotionRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPromotionRepo(db *pgxpool.Pool) *promotionRepo {
+	return &promotionRepo{
+		db: db,
+	}
+}
+
+const promotionColumns = `
+	id,
+	name,
+	type,
+	percent,
+	buy_quantity,
+	get_quantity,
+	bundle_price,
+	category_id,
+	brand_id,
+	min_basket_amount,
+	start_date,
+	end_date,
+	happy_hour_from,
+	happy_hour_to,
+	priority,
+	stackable,
+	is_active,
+	created_at,
+	updated_at
+`
+
+func (r *promotionRepo) Create(ctx context.Context, req *models.CreatePromotion) (*models.Promotion, error) {
+
+	var (
+		promotionID = uuid.New().String()
+		query       = `
+			INSERT INTO promotion(
+				id,
+				name,
+				type,
+				percent,
+				buy_quantity,
+				get_quantity,
+				bundle_price,
+				category_id,
+				brand_id,
+				min_basket_amount,
+				start_date,
+				end_date,
+				happy_hour_from,
+				happy_hour_to,
+				priority,
+				stackable,
+				is_active,
+				updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, NOW())`
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx,
+		query,
+		promotionID,
+		req.Name,
+		req.Type,
+		req.Percent,
+		req.BuyQuantity,
+		req.GetQuantity,
+		req.BundlePrice,
+		helpers.NewNullString(req.CategoryID),
+		helpers.NewNullString(req.BrandID),
+		req.MinBasketAmount,
+		helpers.NewNullString(req.StartDate),
+		helpers.NewNullString(req.EndDate),
+		helpers.NewNullString(req.HappyHourFrom),
+		helpers.NewNullString(req.HappyHourTo),
+		req.Priority,
+		req.Stackable,
+		req.IsActive,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.insertRelations(ctx, tx, promotionID, req.BranchIDs, req.Items)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, &models.PromotionPrimaryKey{Id: promotionID})
+}
+
+func (r *promotionRepo) insertRelations(ctx context.Context, tx pgx.Tx, promotionID string, branchIDs []string, items []*models.PromotionItem) error {
+
+	for _, branchID := range helpers.RemoveDuplicatesStrings(branchIDs) {
+		_, err := tx.Exec(ctx,
+			"INSERT INTO promotion_branch(promotion_id, branch_id) VALUES ($1, $2)",
+			promotionID,
+			branchID,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, item := range items {
+		var quantity = item.Quantity
+		if quantity <= 0 {
+			quantity = 1
+		}
+
+		_, err := tx.Exec(ctx,
+			"INSERT INTO promotion_item(promotion_id, barcode, quantity) VALUES ($1, $2, $3)",
+			promotionID,
+			item.Barcode,
+			quantity,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *promotionRepo) GetByID(ctx context.Context, req *models.PromotionPrimaryKey) (*models.Promotion, error) {
+
+	var query = `SELECT ` + promotionColumns + ` FROM promotion WHERE id = $1`
+
+	promotion, err := scanPromotion(r.db.QueryRow(ctx, query, req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.loadRelations(ctx, []*models.Promotion{promotion})
+	if err != nil {
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+var promotionSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":                {Column: "id", Type: criteria.UUID},
+		"name":              {Column: "name", Type: criteria.Text},
+		"type":              {Column: "type", Type: criteria.Text},
+		"percent":           {Column: "percent", Type: criteria.Number},
+		"category_id":       {Column: "category_id", Type: criteria.UUID},
+		"brand_id":          {Column: "brand_id", Type: criteria.UUID},
+		"min_basket_amount": {Column: "min_basket_amount", Type: criteria.Number},
+		"start_date":        {Column: "start_date", Type: criteria.Time},
+		"end_date":          {Column: "end_date", Type: criteria.Time},
+		"priority":          {Column: "priority", Type: criteria.Number},
+		"stackable":         {Column: "stackable", Type: criteria.Bool},
+		"is_active":         {Column: "is_active", Type: criteria.Bool},
+		"created_at":        {Column: "created_at", Type: criteria.Time},
+		"updated_at":        {Column: "updated_at", Type: criteria.Time},
+	},
+	Search: []string{"name"},
+	Sort:   []criteria.Sort{{Field: "priority", Desc: true}, {Field: "created_at", Desc: true}},
+}
+
+func (r *promotionRepo) GetList(ctx context.Context, req *models.GetListPromotionRequest) (*models.GetListPromotionResponse, error) {
+	var (
+		resp  models.GetListPromotionResponse
+		where = promotionSchema.Query()
+	)
+
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
+	}
+	where.Search(req.Search)
+
+	if len(req.BranchID) > 0 {
+		branchID := where.Arg(req.BranchID)
+		where.And(`(
+			NOT EXISTS (SELECT 1 FROM promotion_branch AS pb WHERE pb.promotion_id = promotion.id)
+			OR EXISTS (SELECT 1 FROM promotion_branch AS pb WHERE pb.promotion_id = promotion.id AND pb.branch_id = ` + branchID + `)
+		)`)
+	}
+
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var query = `SELECT COUNT(*) OVER(), ` + promotionColumns + ` FROM promotion`
+
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var count int
+		promotion, err := scanPromotion(rows, &count)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Count = count
+		resp.Promotions = append(resp.Promotions, promotion)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	err = r.loadRelations(ctx, resp.Promotions)
+	if err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
+
+func (r *promotionRepo) Update(ctx context.Context, req *models.UpdatePromotion) (int64, error) {
+
+	query := `
+		UPDATE promotion
+			SET
+				name = $2,
+				type = $3,
+				percent = $4,
+				buy_quantity = $5,
+				get_quantity = $6,
+				bundle_price = $7,
+				category_id = $8,
+				brand_id = $9,
+				min_basket_amount = $10,
+				start_date = $11,
+				end_date = $12,
+				happy_hour_from = $13,
+				happy_hour_to = $14,
+				priority = $15,
+				stackable = $16,
+				is_active = $17,
+				updated_at = NOW()
+		WHERE id = $1
+	`
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	rowsAffected, err := tx.Exec(ctx,
+		query,
+		req.Id,
+		req.Name,
+		req.Type,
+		req.Percent,
+		req.BuyQuantity,
+		req.GetQuantity,
+		req.BundlePrice,
+		helpers.NewNullString(req.CategoryID),
+		helpers.NewNullString(req.BrandID),
+		req.MinBasketAmount,
+		helpers.NewNullString(req.StartDate),
+		helpers.NewNullString(req.EndDate),
+		helpers.NewNullString(req.HappyHourFrom),
+		helpers.NewNullString(req.HappyHourTo),
+		req.Priority,
+		req.Stackable,
+		req.IsActive,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	if rowsAffected.RowsAffected() == 0 {
+		return 0, nil
+	}
+
+	_, err = tx.Exec(ctx, "DELETE FROM promotion_branch WHERE promotion_id = $1", req.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = tx.Exec(ctx, "DELETE FROM promotion_item WHERE promotion_id = $1", req.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.insertRelations(ctx, tx, req.Id, req.BranchIDs, req.Items)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
+
+// Delete removes a promotion that never produced a discount. Promotions used
+// in sales are deactivated instead so reports keep their history.
+func (r *promotionRepo) Delete(ctx context.Context, req *models.PromotionPrimaryKey) error {
+
+	_, err := r.db.Exec(ctx, `
+		UPDATE promotion SET is_active = false, updated_at = NOW()
+		WHERE id = $1 AND EXISTS (SELECT 1 FROM sale_product_promotion WHERE promotion_id = $1)
+	`, req.Id)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.db.Exec(ctx, `
+		DELETE FROM promotion
+		WHERE id = $1 AND NOT EXISTS (SELECT 1 FROM sale_product_promotion WHERE promotion_id = $1)
+	`, req.Id)
+	return err
+}
+
+func (r *promotionRepo) GetActive(ctx context.Context, req *models.GetActivePromotionRequest) ([]*models.Promotion, error) {
+
+	var (
+		promotions []*models.Promotion
+		query      = `SELECT ` + promotionColumns + `
+			FROM promotion
+			WHERE is_active
+				AND (start_date IS NULL OR start_date <= COALESCE($2::timestamp, NOW()))
+				AND (end_date IS NULL OR end_date >= COALESCE($2::timestamp, NOW()))
+				AND (
+					NOT EXISTS (SELECT 1 FROM promotion_branch AS pb WHERE pb.promotion_id = promotion.id)
+					OR EXISTS (SELECT 1 FROM promotion_branch AS pb WHERE pb.promotion_id = promotion.id AND pb.branch_id = $1)
+				)
+			ORDER BY priority DESC
+		`
+	)
+
+	rows, err := r.db.Query(ctx, query, req.BranchID, helpers.NewNullString(req.At))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		promotion, err := scanPromotion(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		promotions = append(promotions, promotion)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	err = r.loadRelations(ctx, promotions)
+	if err != nil {
+		return nil, err
+	}
+
+	return promotions, nil
+}
+
+// GetBasket returns the product lines of a sale with the category and brand
+// the promotion rules match on; the brand is that of the product, or of its
+// category when it has none. Gift card lines are never discounted.
+func (r *promotionRepo) GetBasket(ctx context.Context, req *models.SalePrimaryKey) ([]*models.BasketLine, error) {
+
+	var (
+		lines []*models.BasketLine
+		query = `
+			SELECT
+				sp.id,
+				COALESCE(sp.barcode, ''),
+				COALESCE(sp.category_id::text, ''),
+				COALESCE(p.brand_id::text, c.brand_id::text, ''),
+				COALESCE(sp.quantity, 0),
+				COALESCE(sp.price, 0),
+				COALESCE(sp.discount_type, ''),
+				COALESCE(sp.discount, 0)
+			FROM sale_products AS sp
+			LEFT JOIN category AS c ON c.id = sp.category_id
+			LEFT JOIN product AS p ON p.barcode = sp.barcode AND sp.barcode <> ''
+			WHERE sp.sale_id = $1 AND sp.gift_card_id IS NULL
+			ORDER BY sp.created_at
+		`
+	)
+
+	rows, err := r.db.Query(ctx, query, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var line models.BasketLine
+
+		err = rows.Scan(
+			&line.SaleProductID,
+			&line.Barcode,
+			&line.CategoryID,
+			&line.BrandID,
+			&line.Quantity,
+			&line.Price,
+			&line.DiscountType,
+			&line.Discount,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		lines = append(lines, &line)
+	}
+
+	return lines, rows.Err()
+}
+
+// ApplyToSale stores the promotion discounts of a sale and recalculates
+// discount and total_amount of the given lines in one transaction.
+func (r *promotionRepo) ApplyToSale(ctx context.Context, req *models.ApplySalePromotions) error {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, "DELETE FROM sale_product_promotion WHERE sale_id = $1", req.SaleID)
+	if err != nil {
+		return err
+	}
+
+	for _, discount := range req.Discounts {
+		_, err = tx.Exec(ctx, `
+			INSERT INTO sale_product_promotion(
+				id,
+				sale_id,
+				sale_product_id,
+				promotion_id,
+				discount
+			) VALUES ($1, $2, $3, $4, $5)
+		`,
+			uuid.New().String(),
+			req.SaleID,
+			discount.SaleProductID,
+			discount.PromotionID,
+			discount.Discount,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(req.SaleProductIDs) > 0 {
+		_, err = tx.Exec(ctx, `
+			UPDATE sale_products AS sp
+				SET
+					discount = d.amount,
+					discount_type = CASE WHEN d.amount > 0 THEN $3 ELSE '' END,
+					total_amount = sp.price * sp.quantity - d.amount,
+					updated_at = NOW()
+			FROM (
+				SELECT
+					ids.id,
+					COALESCE((SELECT SUM(spp.discount) FROM sale_product_promotion AS spp WHERE spp.sale_product_id = ids.id), 0) AS amount
+				FROM UNNEST($2::uuid[]) AS ids(id)
+			) AS d
+			WHERE sp.id = d.id AND sp.sale_id = $1
+		`,
+			req.SaleID,
+			req.SaleProductIDs,
+			config.DiscountTypePromotion,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Report measures promotions over finished sales: how many sales and units
+// they touched, how much they gave away and what the discounted lines
+// brought in.
+func (r *promotionRepo) Report(ctx context.Context, req *models.PromotionReportRequest) (*models.PromotionReportResponse, error) {
+
+	var (
+		resp  models.PromotionReportResponse
+		query = `
+			SELECT
+				p.id,
+				p.name,
+				COUNT(DISTINCT spp.sale_id),
+				COALESCE(SUM(sp.quantity), 0),
+				COALESCE(SUM(spp.discount), 0),
+				COALESCE(SUM(sp.total_amount), 0)
+			FROM sale_product_promotion AS spp
+			JOIN promotion AS p ON p.id = spp.promotion_id
+			JOIN sale_products AS sp ON sp.id = spp.sale_product_id
+			JOIN sale AS s ON s.id = spp.sale_id
+			WHERE s.status = $1
+				AND ($2::timestamp IS NULL OR s.created_at >= $2)
+				AND ($3::timestamp IS NULL OR s.created_at < $3)
+				AND ($4::uuid IS NULL OR s.branch_id = $4)
+			GROUP BY p.id, p.name
+			ORDER BY SUM(spp.discount) DESC
+		`
+	)
+
+	rows, err := r.db.Query(ctx, query,
+		config.SaleStatusFinished,
+		helpers.NewNullString(req.FromDate),
+		helpers.NewNullString(req.ToDate),
+		helpers.NewNullString(req.BranchID),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var report models.PromotionReport
+
+		err = rows.Scan(
+			&report.PromotionID,
+			&report.Name,
+			&report.SalesCount,
+			&report.Units,
+			&report.DiscountAmount,
+			&report.Revenue,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Promotions = append(resp.Promotions, &report)
+	}
+
+	return &resp, rows.Err()
+}
+
+func (r *promotionRepo) loadRelations(ctx context.Context, promotions []*models.Promotion) error {
+
+	if len(promotions) == 0 {
+		return nil
+	}
+
+	var (
+		ids  = make([]string, 0, len(promotions))
+		byID = make(map[string]*models.Promotion, len(promotions))
+	)
+
+	for _, promotion := range promotions {
+		ids = append(ids, promotion.Id)
+		byID[promotion.Id] = promotion
+	}
+
+	rows, err := r.db.Query(ctx, "SELECT promotion_id, branch_id FROM promotion_branch WHERE promotion_id = ANY($1::uuid[])", ids)
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		var promotionID, branchID string
+		err = rows.Scan(&promotionID, &branchID)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+
+		byID[promotionID].BranchIDs = append(byID[promotionID].BranchIDs, branchID)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	rows, err = r.db.Query(ctx, "SELECT promotion_id, barcode, quantity FROM promotion_item WHERE promotion_id = ANY($1::uuid[]) ORDER BY barcode", ids)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			promotionID string
+			item        models.PromotionItem
+		)
+
+		err = rows.Scan(&promotionID, &item.Barcode, &item.Quantity)
+		if err != nil {
+			return err
+		}
+
+		byID[promotionID].Items = append(byID[promotionID].Items, &item)
+	}
+
+	return rows.Err()
+}
+
+// scanPromotion reads promotionColumns, optionally preceded by extra
+// columns such as COUNT(*) OVER().
+func scanPromotion(row pgx.Row, extra ...interface{}) (*models.Promotion, error) {
+
+	var (
+		ID              sql.NullString
+		Name            sql.NullString
+		Type            sql.NullString
+		Percent         sql.NullFloat64
+		BuyQuantity     sql.NullInt64
+		GetQuantity     sql.NullInt64
+		BundlePrice     sql.NullFloat64
+		CategoryID      sql.NullString
+		BrandID         sql.NullString
+		MinBasketAmount sql.NullFloat64
+		StartDate       sql.NullString
+		EndDate         sql.NullString
+		HappyHourFrom   sql.NullString
+		HappyHourTo     sql.NullString
+		Priority        sql.NullInt64
+		Stackable       sql.NullBool
+		IsActive        sql.NullBool
+		CreatedAt       sql.NullString
+		UpdatedAt       sql.NullString
+	)
+
+	dest := append(extra,
+		&ID,
+		&Name,
+		&Type,
+		&Percent,
+		&BuyQuantity,
+		&GetQuantity,
+		&BundlePrice,
+		&CategoryID,
+		&BrandID,
+		&MinBasketAmount,
+		&StartDate,
+		&EndDate,
+		&HappyHourFrom,
+		&HappyHourTo,
+		&Priority,
+		&Stackable,
+		&IsActive,
+		&CreatedAt,
+		&UpdatedAt,
+	)
+
+	err := row.Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Promotion{
+		Id:              ID.String,
+		Name:            Name.String,
+		Type:            Type.String,
+		Percent:         Percent.Float64,
+		BuyQuantity:     int(BuyQuantity.Int64),
+		GetQuantity:     int(GetQuantity.Int64),
+		BundlePrice:     BundlePrice.Float64,
+		CategoryID:      CategoryID.String,
+		BrandID:         BrandID.String,
+		MinBasketAmount: MinBasketAmount.Float64,
+		StartDate:       StartDate.String,
+		EndDate:         EndDate.String,
+		HappyHourFrom:   HappyHourFrom.String,
+		HappyHourTo:     HappyHourTo.String,
+		Priority:        int(Priority.Int64),
+		Stackable:       Stackable.Bool,
+		IsActive:        IsActive.Bool,
+		CreatedAt:       CreatedAt.String,
+		UpdatedAt:       UpdatedAt.String,
+	}, nil
+}
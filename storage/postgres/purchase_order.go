@@ -0,0 +1,674 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+	"market_system/pkg/purchaseorder"
+	"market_system/storage"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type purchaseOrderRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewPurchaseOrderRepo(db *pgxpool.Pool) *purchaseOrderRepo {
+	return &purchaseOrderRepo{
+		db: db,
+	}
+}
+
+const purchaseOrderColumns = `
+	id,
+	supplier_id,
+	branch_id,
+	status,
+	expected_date,
+	comment,
+	created_by,
+	sent_at,
+	received_at,
+	closed_at,
+	created_at,
+	updated_at
+`
+
+func scanPurchaseOrder(row pgx.Row, extra ...interface{}) (*models.PurchaseOrder, error) {
+
+	var (
+		ID           sql.NullString
+		SupplierID   sql.NullString
+		BranchID     sql.NullString
+		Status       sql.NullString
+		ExpectedDate sql.NullString
+		Comment      sql.NullString
+		CreatedBy    sql.NullString
+		SentAt       sql.NullString
+		ReceivedAt   sql.NullString
+		ClosedAt     sql.NullString
+		CreatedAt    sql.NullString
+		UpdatedAt    sql.NullString
+	)
+
+	dest := append(extra,
+		&ID,
+		&SupplierID,
+		&BranchID,
+		&Status,
+		&ExpectedDate,
+		&Comment,
+		&CreatedBy,
+		&SentAt,
+		&ReceivedAt,
+		&ClosedAt,
+		&CreatedAt,
+		&UpdatedAt,
+	)
+
+	err := row.Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PurchaseOrder{
+		Id:           ID.String,
+		SupplierID:   SupplierID.String,
+		BranchID:     BranchID.String,
+		Status:       Status.String,
+		ExpectedDate: ExpectedDate.String,
+		Comment:      Comment.String,
+		CreatedBy:    CreatedBy.String,
+		SentAt:       SentAt.String,
+		ReceivedAt:   ReceivedAt.String,
+		ClosedAt:     ClosedAt.String,
+		CreatedAt:    CreatedAt.String,
+		UpdatedAt:    UpdatedAt.String,
+	}, nil
+}
+
+func (r *purchaseOrderRepo) Create(ctx context.Context, req *models.CreatePurchaseOrder) (*models.PurchaseOrder, error) {
+
+	var (
+		purchaseOrderID = uuid.New().String()
+		query           = `
+			INSERT INTO purchase_order(
+				id,
+				supplier_id,
+				branch_id,
+				status,
+				expected_date,
+				comment,
+				created_by,
+				updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())`
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx,
+		query,
+		purchaseOrderID,
+		req.SupplierID,
+		req.BranchID,
+		config.PurchaseOrderDraft,
+		helpers.NewNullString(req.ExpectedDate),
+		helpers.NewNullString(req.Comment),
+		helpers.NewNullString(req.CreatedBy),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.insertProducts(ctx, tx, purchaseOrderID, req.Products)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, &models.PurchaseOrderPrimaryKey{Id: purchaseOrderID})
+}
+
+func (r *purchaseOrderRepo) insertProducts(ctx context.Context, tx pgx.Tx, purchaseOrderID string, products []*models.CreatePurchaseOrderProduct) error {
+
+	var query = `
+		INSERT INTO purchase_order_product(
+			id,
+			purchase_order_id,
+			product_id,
+			quantity,
+			price,
+			updated_at
+		)
+		SELECT $1, $2, p.id, $4, $5, NOW()
+		FROM product AS p
+		WHERE p.id = $3
+	`
+
+	for _, product := range products {
+		result, err := tx.Exec(ctx,
+			query,
+			uuid.New().String(),
+			purchaseOrderID,
+			product.ProductID,
+			product.Quantity,
+			product.Price,
+		)
+		if err != nil {
+			return err
+		}
+
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("product %s not found", product.ProductID)
+		}
+	}
+
+	return nil
+}
+
+func (r *purchaseOrderRepo) GetByID(ctx context.Context, req *models.PurchaseOrderPrimaryKey) (*models.PurchaseOrder, error) {
+
+	var query = "SELECT " + purchaseOrderColumns + " FROM purchase_order WHERE id = $1"
+
+	order, err := scanPurchaseOrder(r.db.QueryRow(ctx, query, req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	order.Products, err = r.getProducts(ctx, order.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+func (r *purchaseOrderRepo) getProducts(ctx context.Context, purchaseOrderID string) ([]*models.PurchaseOrderProduct, error) {
+
+	var (
+		products []*models.PurchaseOrderProduct
+		query    = `
+			SELECT
+				pop.id,
+				pop.purchase_order_id,
+				pop.product_id,
+				p.title,
+				p.barcode,
+				pop.quantity,
+				pop.price,
+				pop.received_quantity,
+				pop.created_at,
+				pop.updated_at
+			FROM purchase_order_product AS pop
+			JOIN product AS p ON p.id = pop.product_id
+			WHERE pop.purchase_order_id = $1
+			ORDER BY pop.created_at
+		`
+	)
+
+	rows, err := r.db.Query(ctx, query, purchaseOrderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			ID               sql.NullString
+			PurchaseOrderID  sql.NullString
+			ProductID        sql.NullString
+			ProductName      sql.NullString
+			Barcode          sql.NullString
+			Quantity         sql.NullInt64
+			Price            sql.NullFloat64
+			ReceivedQuantity sql.NullInt64
+			CreatedAt        sql.NullString
+			UpdatedAt        sql.NullString
+		)
+
+		err = rows.Scan(
+			&ID,
+			&PurchaseOrderID,
+			&ProductID,
+			&ProductName,
+			&Barcode,
+			&Quantity,
+			&Price,
+			&ReceivedQuantity,
+			&CreatedAt,
+			&UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		products = append(products, &models.PurchaseOrderProduct{
+			Id:               ID.String,
+			PurchaseOrderID:  PurchaseOrderID.String,
+			ProductID:        ProductID.String,
+			ProductName:      ProductName.String,
+			Barcode:          Barcode.String,
+			Quantity:         Quantity.Int64,
+			Price:            Price.Float64,
+			ReceivedQuantity: ReceivedQuantity.Int64,
+			Difference:       ReceivedQuantity.Int64 - Quantity.Int64,
+			DeliveryStatus: purchaseorder.LineStatus(purchaseorder.Line{
+				Ordered:  Quantity.Int64,
+				Received: ReceivedQuantity.Int64,
+			}),
+			CreatedAt: CreatedAt.String,
+			UpdatedAt: UpdatedAt.String,
+		})
+	}
+
+	return products, rows.Err()
+}
+
+var purchaseOrderSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":            {Column: "id", Type: criteria.UUID},
+		"supplier_id":   {Column: "supplier_id", Type: criteria.UUID},
+		"branch_id":     {Column: "branch_id", Type: criteria.UUID},
+		"status":        {Column: "status", Type: criteria.Text},
+		"expected_date": {Column: "expected_date", Type: criteria.Time},
+		"created_by":    {Column: "created_by", Type: criteria.UUID},
+		"sent_at":       {Column: "sent_at", Type: criteria.Time},
+		"received_at":   {Column: "received_at", Type: criteria.Time},
+		"closed_at":     {Column: "closed_at", Type: criteria.Time},
+		"created_at":    {Column: "created_at", Type: criteria.Time},
+		"updated_at":    {Column: "updated_at", Type: criteria.Time},
+	},
+	Sort: []criteria.Sort{{Field: "created_at", Desc: true}},
+}
+
+func (r *purchaseOrderRepo) GetList(ctx context.Context, req *models.GetListPurchaseOrderRequest) (*models.GetListPurchaseOrderResponse, error) {
+	var (
+		resp  models.GetListPurchaseOrderResponse
+		where = purchaseOrderSchema.Query()
+	)
+
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var query = "SELECT COUNT(*) OVER(), " + purchaseOrderColumns + " FROM purchase_order"
+
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		order, err := scanPurchaseOrder(rows, &resp.Count)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.PurchaseOrders = append(resp.PurchaseOrders, order)
+	}
+
+	return &resp, rows.Err()
+}
+
+// Update rewrites a draft purchase order. Orders already sent to the
+// supplier are left untouched and 0 rows affected is returned.
+func (r *purchaseOrderRepo) Update(ctx context.Context, req *models.UpdatePurchaseOrder) (int64, error) {
+
+	query := `
+		UPDATE purchase_order
+			SET
+				supplier_id = $2,
+				branch_id = $3,
+				expected_date = $4,
+				comment = $5,
+				updated_at = NOW()
+		WHERE id = $1 AND status = $6
+	`
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	rowsAffected, err := tx.Exec(ctx,
+		query,
+		req.Id,
+		req.SupplierID,
+		req.BranchID,
+		helpers.NewNullString(req.ExpectedDate),
+		helpers.NewNullString(req.Comment),
+		config.PurchaseOrderDraft,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	if rowsAffected.RowsAffected() == 0 {
+		return 0, nil
+	}
+
+	_, err = tx.Exec(ctx, "DELETE FROM purchase_order_product WHERE purchase_order_id = $1", req.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.insertProducts(ctx, tx, req.Id, req.Products)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
+
+func (r *purchaseOrderRepo) Delete(ctx context.Context, req *models.PurchaseOrderPrimaryKey) error {
+	_, err := r.db.Exec(ctx, "DELETE FROM purchase_order WHERE id = $1 AND status = $2", req.Id, config.PurchaseOrderDraft)
+	return err
+}
+
+// SetStatus moves an order to req.To when it is currently in one of req.From
+// and stamps sent_at or closed_at accordingly.
+func (r *purchaseOrderRepo) SetStatus(ctx context.Context, req *models.SetPurchaseOrderStatus) (int64, error) {
+
+	query := `
+		UPDATE purchase_order
+			SET
+				status = $2,
+				sent_at = CASE WHEN $2 = $4 THEN NOW() ELSE sent_at END,
+				closed_at = CASE WHEN $2 = $5 THEN NOW() ELSE closed_at END,
+				updated_at = NOW()
+		WHERE id = $1 AND status = ANY($3)
+	`
+
+	rowsAffected, err := r.db.Exec(ctx,
+		query,
+		req.Id,
+		req.To,
+		req.From,
+		config.PurchaseOrderSent,
+		config.PurchaseOrderClosed,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
+
+// Receive records a delivery against an order in one transaction: a finished
+// income linked to the order, one income_product per line, the branch stock,
+// the received quantities of the order lines and the supplier payable. The
+// order then becomes received when every line got at least what was ordered,
+// partially received otherwise. Quantities above the order are accepted and
+// show up as over-delivery on the line.
+func (r *purchaseOrderRepo) Receive(ctx context.Context, req *models.ReceivePurchaseOrder) (string, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	var supplierID, branchID, status string
+	err = tx.QueryRow(ctx,
+		"SELECT supplier_id, branch_id, status FROM purchase_order WHERE id = $1 FOR UPDATE",
+		req.PurchaseOrderID,
+	).Scan(&supplierID, &branchID, &status)
+	if err != nil {
+		return "", err
+	}
+
+	if !purchaseorder.CanTransition(status, config.PurchaseOrderPartiallyReceived) {
+		return "", storage.ErrPurchaseOrderNotReceivable
+	}
+
+	var incomeID = uuid.New().String()
+	_, err = tx.Exec(ctx,
+		`INSERT INTO income(
+			id,
+			branch_id,
+			supplier_id,
+			date_time,
+			status,
+			purchase_order_id,
+			updated_at
+		) VALUES ($1, $2, $3, COALESCE($4::timestamp, NOW()), $5, $6, NOW())`,
+		incomeID,
+		branchID,
+		supplierID,
+		helpers.NewNullString(req.DateTime),
+		config.IncomeStatusFinished,
+		req.PurchaseOrderID,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	for _, product := range req.Products {
+		var line stockLine
+		err = tx.QueryRow(ctx, `
+			UPDATE purchase_order_product AS pop
+				SET
+					received_quantity = pop.received_quantity + $3,
+					updated_at = NOW()
+			FROM product AS p
+			WHERE pop.id = $1 AND pop.purchase_order_id = $2 AND p.id = pop.product_id
+			RETURNING p.category_id, p.title, COALESCE(p.barcode, ''), pop.price
+			`,
+			product.PurchaseOrderProductID,
+			req.PurchaseOrderID,
+			product.Quantity,
+		).Scan(&line.CategoryID, &line.ProductName, &line.Barcode, &line.PriceIncome)
+		if err == pgx.ErrNoRows {
+			return "", fmt.Errorf("purchase order line %s: %w", product.PurchaseOrderProductID, err)
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if product.Price > 0 {
+			line.PriceIncome = product.Price
+		}
+		line.BranchID = branchID
+		line.Type = config.StockMovementIncome
+		line.DocumentID = incomeID
+		line.Quantity = product.Quantity
+		line.LotNumber = product.LotNumber
+		line.ExpiryDate = product.ExpiryDate
+
+		_, err = tx.Exec(ctx,
+			`INSERT INTO income_product(
+				id,
+				income_id,
+				category_id,
+				product_name,
+				barcode,
+				quantity,
+				income_price,
+				purchase_order_product_id,
+				lot_number,
+				expiry_date,
+				updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10::date, NOW())`,
+			uuid.New().String(),
+			incomeID,
+			line.CategoryID,
+			line.ProductName,
+			line.Barcode,
+			line.Quantity,
+			line.PriceIncome,
+			product.PurchaseOrderProductID,
+			helpers.NewNullString(product.LotNumber),
+			helpers.NewNullString(product.ExpiryDate),
+		)
+		if err != nil {
+			return "", err
+		}
+
+		err = addStock(ctx, tx, line)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	rows, err := tx.Query(ctx, "SELECT quantity, received_quantity FROM purchase_order_product WHERE purchase_order_id = $1", req.PurchaseOrderID)
+	if err != nil {
+		return "", err
+	}
+
+	var lines []purchaseorder.Line
+	for rows.Next() {
+		var line purchaseorder.Line
+		err = rows.Scan(&line.Ordered, &line.Received)
+		if err != nil {
+			rows.Close()
+			return "", err
+		}
+		lines = append(lines, line)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return "", err
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE purchase_order
+			SET
+				status = $2,
+				received_at = CASE WHEN $2 = $3 THEN COALESCE(received_at, NOW()) ELSE NULL END,
+				updated_at = NOW()
+		WHERE id = $1
+		`,
+		req.PurchaseOrderID,
+		purchaseorder.ReceiptStatus(lines),
+		config.PurchaseOrderReceived,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	err = postIncomeInvoice(ctx, tx, incomeID)
+	if err != nil {
+		return "", err
+	}
+
+	return incomeID, tx.Commit(ctx)
+}
+
+// FillRateReport compares ordered and received quantities per supplier for
+// orders created in the period. Drafts are left out since they were never
+// placed with the supplier.
+func (r *purchaseOrderRepo) FillRateReport(ctx context.Context, req *models.SupplierFillRateRequest) (*models.SupplierFillRateResponse, error) {
+
+	var (
+		resp  models.SupplierFillRateResponse
+		query = `
+			WITH orders AS (
+				SELECT
+					po.id,
+					po.supplier_id,
+					po.expected_date IS NOT NULL AS has_due_date,
+					po.received_at IS NOT NULL AND po.received_at::date <= po.expected_date AS on_time
+				FROM purchase_order AS po
+				WHERE po.status <> $1
+					AND ($2::timestamp IS NULL OR po.created_at >= $2)
+					AND ($3::timestamp IS NULL OR po.created_at < $3)
+					AND ($4::uuid IS NULL OR po.supplier_id = $4)
+					AND ($5::uuid IS NULL OR po.branch_id = $5)
+			)
+			SELECT
+				s.id,
+				s.name,
+				COUNT(DISTINCT o.id),
+				COALESCE(SUM(pop.quantity), 0),
+				COALESCE(SUM(pop.received_quantity), 0),
+				COALESCE(SUM(GREATEST(pop.quantity - pop.received_quantity, 0)), 0),
+				COALESCE(SUM(GREATEST(pop.received_quantity - pop.quantity, 0)), 0),
+				COALESCE(SUM(LEAST(pop.received_quantity, pop.quantity)), 0),
+				COUNT(pop.id),
+				COUNT(pop.id) FILTER (WHERE pop.received_quantity >= pop.quantity),
+				COUNT(DISTINCT o.id) FILTER (WHERE o.has_due_date),
+				COUNT(DISTINCT o.id) FILTER (WHERE o.on_time)
+			FROM orders AS o
+			JOIN supplier AS s ON s.id = o.supplier_id
+			JOIN purchase_order_product AS pop ON pop.purchase_order_id = o.id
+			GROUP BY s.id, s.name
+			ORDER BY s.name
+		`
+	)
+
+	rows, err := r.db.Query(ctx, query,
+		config.PurchaseOrderDraft,
+		helpers.NewNullString(req.FromDate),
+		helpers.NewNullString(req.ToDate),
+		helpers.NewNullString(req.SupplierID),
+		helpers.NewNullString(req.BranchID),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			report                          models.SupplierFillRate
+			filled, lines, fullLines        int64
+			ordersWithDueDate, ordersOnTime int64
+		)
+
+		err = rows.Scan(
+			&report.SupplierID,
+			&report.SupplierName,
+			&report.Orders,
+			&report.OrderedQuantity,
+			&report.ReceivedQuantity,
+			&report.ShortQuantity,
+			&report.OverQuantity,
+			&filled,
+			&lines,
+			&fullLines,
+			&ordersWithDueDate,
+			&ordersOnTime,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		report.FillRate = purchaseorder.Rate(filled, report.OrderedQuantity)
+		report.LineFillRate = purchaseorder.Rate(fullLines, lines)
+		report.OnTimeRate = purchaseorder.Rate(ordersOnTime, ordersWithDueDate)
+
+		resp.Suppliers = append(resp.Suppliers, &report)
+	}
+
+	return &resp, rows.Err()
+}
@@ -3,10 +3,13 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"market_system/config"
 	"market_system/models"
+	"market_system/pkg/criteria"
 	"market_system/pkg/helpers"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
@@ -37,7 +40,13 @@ func (r *remainderRepo) Create(ctx context.Context, req *models.CreateRemainder)
 			) VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())`
 	)
 
-	_, err := r.db.Exec(ctx,
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx,
 		query,
 		remainderID,
 		helpers.NewNullString(req.BranchID),
@@ -52,6 +61,23 @@ func (r *remainderRepo) Create(ctx context.Context, req *models.CreateRemainder)
 		return nil, err
 	}
 
+	err = recordAdjustment(ctx, tx, stockLine{
+		BranchID:    req.BranchID,
+		ProductName: req.ProductName,
+		Barcode:     req.Barcode,
+		PriceIncome: req.PriceIncome,
+		Type:        config.StockMovementAdjustment,
+		DocumentID:  remainderID,
+	}, int64(req.Quantity), int64(req.Quantity))
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	return r.GetByID(ctx, &models.RemainderPrimaryKey{Id: remainderID})
 }
 
@@ -115,18 +141,46 @@ func (r *remainderRepo) GetByID(ctx context.Context, req *models.RemainderPrimar
 	}, nil
 }
 
+var remainderSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":           {Column: "id", Type: criteria.UUID},
+		"branch_id":    {Column: "branch_id", Type: criteria.UUID},
+		"category_id":  {Column: "category_id", Type: criteria.UUID, Tree: "category_id IN (SELECT id FROM category_subtree(%s))"},
+		"brand_id":     {Column: "(SELECT p.brand_id FROM product AS p WHERE p.barcode = remainder.barcode AND p.barcode <> '')", Type: criteria.UUID},
+		"product_name": {Column: "product_name", Type: criteria.Text},
+		"barcode":      {Column: "barcode", Type: criteria.Text},
+		"price_income": {Column: "price_income", Type: criteria.Number},
+		"quantity":     {Column: "quantity", Type: criteria.Number},
+		"created_at":   {Column: "created_at", Type: criteria.Time},
+		"updated_at":   {Column: "updated_at", Type: criteria.Time},
+	},
+	Search: []string{"branch_id", "category_id", "barcode"},
+	Sort:   []criteria.Sort{{Field: "created_at", Desc: true}},
+}
+
 func (r *remainderRepo) GetList(ctx context.Context, req *models.GetListRemainderRequest) (*models.GetListRemainderResponse, error) {
 	var (
 		resp  models.GetListRemainderResponse
-		where = " WHERE TRUE"
+		where = remainderSchema.Query()
 	)
 
-	if len(req.Search) > 0 {
-		where += " AND (branch_id ILIKE '%" + req.Search + "%' OR category_id ILIKE '%" + req.Search + "%' OR barcode ILIKE '%" + req.Search + "%')"
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
 	}
+	where.Search(req.Search)
 
-	if len(req.Query) > 0 {
-		where += req.Query
+	if len(req.BranchID) > 0 {
+		where.And("remainder.branch_id = " + where.Arg(req.BranchID))
+	}
+
+	if len(req.ABC) > 0 || len(req.XYZ) > 0 {
+		where.And(latestProductClass(where, "remainder.branch_id", "remainder.barcode", req.ABC, req.XYZ))
+	}
+
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
 	}
 
 	var query = `
@@ -144,8 +198,8 @@ func (r *remainderRepo) GetList(ctx context.Context, req *models.GetListRemainde
 		FROM remainder
 	`
 
-	query += where
-	rows, err := r.db.Query(ctx, query)
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
 	if err != nil {
 		return nil, err
 	}
@@ -195,6 +249,9 @@ func (r *remainderRepo) GetList(ctx context.Context, req *models.GetListRemainde
 	return &resp, nil
 }
 
+// Update edits a remainder row by hand. The change of quantity is recorded
+// as an adjustment movement, so reports that work back from the movements
+// still add up; a new barcode moves the whole quantity from the old one.
 func (r *remainderRepo) Update(ctx context.Context, req *models.UpdateRemainder) (int64, error) {
 
 	query := `
@@ -207,7 +264,21 @@ func (r *remainderRepo) Update(ctx context.Context, req *models.UpdateRemainder)
 				updated_at = NOW()
 		WHERE id = $1
 	`
-	rowsAffected, err := r.db.Exec(ctx,
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	old, oldQuantity, err := lockRemainder(ctx, tx, req.Id)
+	if err == pgx.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := tx.Exec(ctx,
 		query,
 		req.Id,
 		req.ProductName,
@@ -219,10 +290,131 @@ func (r *remainderRepo) Update(ctx context.Context, req *models.UpdateRemainder)
 		return 0, err
 	}
 
+	var updated = old
+	updated.ProductName = req.ProductName
+	updated.Barcode = req.Barcode
+	updated.PriceIncome = req.PriceIncome
+
+	if updated.Barcode != old.Barcode {
+		err = recordAdjustment(ctx, tx, old, -oldQuantity, 0)
+		if err == nil {
+			err = recordAdjustment(ctx, tx, updated, int64(req.Quantity), int64(req.Quantity))
+		}
+	} else {
+		err = recordAdjustment(ctx, tx, updated, int64(req.Quantity)-oldQuantity, int64(req.Quantity))
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
 	return rowsAffected.RowsAffected(), nil
 }
 
+// Delete removes a remainder row, recording its quantity as leaving the
+// branch.
 func (r *remainderRepo) Delete(ctx context.Context, req *models.RemainderPrimaryKey) error {
-	_, err := r.db.Exec(ctx, "DELETE FROM remainder WHERE id = $1", req.Id)
-	return err
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	old, quantity, err := lockRemainder(ctx, tx, req.Id)
+	if err == pgx.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, "DELETE FROM remainder WHERE id = $1", req.Id)
+	if err != nil {
+		return err
+	}
+
+	err = recordAdjustment(ctx, tx, old, -quantity, 0)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// lockRemainder locks a remainder row before a hand edit and returns it as
+// an adjustment line of its barcode, with the quantity it holds.
+func lockRemainder(ctx context.Context, tx pgx.Tx, id string) (stockLine, int64, error) {
+
+	var (
+		line     = stockLine{Type: config.StockMovementAdjustment, DocumentID: id}
+		quantity int64
+	)
+	err := tx.QueryRow(ctx, `
+		SELECT
+			branch_id,
+			COALESCE(product_name, ''),
+			COALESCE(barcode, ''),
+			COALESCE(price_income, 0),
+			COALESCE(quantity, 0)
+		FROM remainder
+		WHERE id = $1
+		FOR UPDATE`,
+		id,
+	).Scan(&line.BranchID, &line.ProductName, &line.Barcode, &line.PriceIncome, &quantity)
+
+	return line, quantity, err
+}
+
+// recordAdjustment records a hand edit of quantity on a remainder row as a
+// stock movement. Rows without a barcode are not tracked by the movements
+// and nothing is recorded for them.
+func recordAdjustment(ctx context.Context, tx pgx.Tx, line stockLine, quantity, balance int64) error {
+
+	if quantity == 0 || len(line.Barcode) == 0 {
+		return nil
+	}
+
+	return recordMovement(ctx, tx, line, quantity, balance)
+}
+
+// Deduct takes sold goods out of the branch stock in one transaction. Sales
+// are already paid, so stock may go below zero; barcodes the branch has never
+// stocked are skipped.
+func (r *remainderRepo) Deduct(ctx context.Context, req *models.DeductRemainder) error {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = deductSale(ctx, tx, req)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func deductSale(ctx context.Context, tx pgx.Tx, req *models.DeductRemainder) error {
+
+	for _, product := range req.Products {
+		_, err := removeStock(ctx, tx, stockLine{
+			BranchID:   req.BranchID,
+			Barcode:    product.Barcode,
+			Quantity:   int64(product.Quantity),
+			Type:       config.StockMovementSale,
+			DocumentID: req.SaleID,
+		})
+		if err != nil && err != pgx.ErrNoRows {
+			return err
+		}
+	}
+
+	return nil
 }
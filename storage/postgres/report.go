@@ -0,0 +1,903 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/helpers"
+	"market_system/pkg/report"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type reportRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewReportRepo(db *pgxpool.Pool) *reportRepo {
+	return &reportRepo{
+		db: db,
+	}
+}
+
+// saleLines joins every line of a sale to its product and to what one item
+// cost: the cost the sale's stock movement recorded, or the current income
+// price of the branch when the line never left stock.
+const saleLines = `
+	FROM sale AS s
+	JOIN sale_products AS sp ON sp.sale_id = s.id
+	LEFT JOIN category AS c ON c.id = sp.category_id
+	LEFT JOIN product AS p ON p.barcode = sp.barcode AND sp.barcode <> ''
+	LEFT JOIN LATERAL (
+		SELECT COALESCE(
+			(
+				SELECT sm.cost
+				FROM stock_movement AS sm
+				WHERE sm.document_id = s.id AND sm.barcode = sp.barcode AND sm.type = $3 AND sm.cost IS NOT NULL
+				LIMIT 1
+			),
+			(
+				SELECT AVG(rm.price_income)
+				FROM remainder AS rm
+				WHERE rm.branch_id = s.branch_id AND rm.barcode = sp.barcode
+			),
+			0
+		) AS unit
+	) AS cost ON TRUE
+`
+
+// saleLineBrand is the brand of a sale line: that of its product, or that of
+// its category when the product is gone or has none.
+const saleLineBrand = "COALESCE(p.brand_id, c.brand_id)"
+
+// saleLineMetrics aggregates revenue, cost, sale count and units of lines.
+const saleLineMetrics = `
+	COALESCE(SUM(sp.total_amount), 0),
+	COALESCE(SUM(sp.quantity * cost.unit), 0),
+	COUNT(DISTINCT s.id),
+	COALESCE(SUM(sp.quantity), 0)
+`
+
+// salesDimension is how a sales report groups by one of
+// config.SalesReportGroups.
+type salesDimension struct {
+	id   string
+	name string
+	join string
+}
+
+var salesDimensions = map[string]salesDimension{
+	"branch":     {"s.branch_id", "b.name", " LEFT JOIN branch AS b ON b.id = s.branch_id"},
+	"sale_point": {"s.salepoint_id", "pt.name", " LEFT JOIN sale_point AS pt ON pt.id = s.salepoint_id"},
+	"cashier":    {"s.employee_id", "u.first_name || ' ' || u.last_name", ` LEFT JOIN "user" AS u ON u.id = s.employee_id`},
+	"category":   {"sp.category_id", "c.title", ""},
+	"brand":      {saleLineBrand, "br.name", " LEFT JOIN brand AS br ON br.id = " + saleLineBrand},
+}
+
+// saleLineFilter narrows sale lines to the given branch, sale point,
+// cashier, category with its subcategories and brand, appending the values
+// to params.
+func saleLineFilter(params *[]interface{}, branchID, salePointID, cashierID, categoryID, brandID string) string {
+
+	var where string
+	for _, filter := range []struct {
+		condition string
+		value     string
+	}{
+		{"s.branch_id = $%d", branchID},
+		{"s.salepoint_id = $%d", salePointID},
+		{"s.employee_id = $%d", cashierID},
+		{"sp.category_id IN (SELECT id FROM category_subtree($%d))", categoryID},
+		{saleLineBrand + " = $%d", brandID},
+	} {
+		if filter.value != "" {
+			*params = append(*params, filter.value)
+			where += " AND " + fmt.Sprintf(filter.condition, len(*params))
+		}
+	}
+
+	return where
+}
+
+// Sales aggregates finished sales in one pass over both the report period
+// and, with req.Compare, the period before it. Grouping sets return the
+// totals alongside the groups, as sale counts can not be summed across
+// groups that share a sale.
+func (r *reportRepo) Sales(ctx context.Context, req *models.SalesReportRequest) (*models.SalesReportResponse, error) {
+
+	from, err := time.Parse("2006-01-02", req.FromDate)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := time.Parse("2006-01-02", req.ToDate)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		resp = models.SalesReportResponse{
+			FromDate: req.FromDate,
+			ToDate:   req.ToDate,
+			Rows:     []*models.SalesReportRow{},
+		}
+		start   = from
+		columns []string
+		group   []string
+		joins   string
+		sort    []string
+	)
+
+	if req.Compare {
+		start, _ = report.Previous(from, to)
+		resp.PrevFromDate = start.Format("2006-01-02")
+	}
+
+	for _, name := range req.GroupBy {
+		if name == "period" {
+			if !helpers.Contains(config.ReportIntervals, req.Interval) {
+				return nil, fmt.Errorf("invalid interval %q", req.Interval)
+			}
+
+			period := fmt.Sprintf("DATE_TRUNC('%s', s.created_at)", req.Interval)
+			columns = append(columns, "COALESCE(TO_CHAR("+period+", 'YYYY-MM-DD'), '')")
+			group = append(group, period)
+			sort = append(sort, period)
+			continue
+		}
+
+		dimension, ok := salesDimensions[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid group %q", name)
+		}
+
+		columns = append(columns, "COALESCE("+dimension.id+"::text, '')", "COALESCE("+dimension.name+", '')")
+		group = append(group, dimension.id, dimension.name)
+		sort = append(sort, dimension.name)
+		joins += dimension.join
+	}
+
+	var (
+		params = []interface{}{start, to, config.StockMovementSale, config.SaleStatusFinished, from}
+		where  = " WHERE s.status = $4 AND s.created_at >= $1 AND s.created_at < $2" +
+			saleLineFilter(&params, req.BranchID, req.SalePointID, req.CashierID, req.CategoryID, req.BrandID)
+		isTotal  = "TRUE"
+		grouping = " GROUP BY s.created_at >= $5"
+	)
+
+	if len(group) > 0 {
+		isTotal = "GROUPING(" + group[0] + ") = 1"
+		grouping = " GROUP BY GROUPING SETS ((s.created_at >= $5, " + strings.Join(group, ", ") + "), (s.created_at >= $5))"
+	}
+
+	var query = "SELECT " + strings.Join(append([]string{"s.created_at >= $5", isTotal}, columns...), ", ") + ", " +
+		saleLineMetrics + saleLines + joins + where + grouping
+
+	if len(sort) > 0 {
+		query += " ORDER BY " + strings.Join(sort, ", ") + ", SUM(sp.total_amount) DESC"
+	}
+
+	rows, err := r.db.Query(ctx, query, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var previous = map[string]*models.SalesMetrics{}
+	for rows.Next() {
+		var (
+			current bool
+			total   bool
+			values  = make([]string, len(columns))
+			metrics report.Metrics
+			dest    = []interface{}{&current, &total}
+		)
+
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+		dest = append(dest, &metrics.Revenue, &metrics.Cost, &metrics.Sales, &metrics.Units)
+
+		err = rows.Scan(dest...)
+		if err != nil {
+			return nil, err
+		}
+
+		var salesMetrics = newSalesMetrics(metrics)
+		switch {
+		case total && current:
+			resp.Total = *salesMetrics
+		case total:
+			resp.Previous = salesMetrics
+		case current:
+			resp.Rows = append(resp.Rows, newSalesReportRow(req.GroupBy, values, salesMetrics))
+		default:
+			previous[strings.Join(values, "\x00")] = salesMetrics
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if !req.Compare {
+		return &resp, nil
+	}
+
+	if resp.Previous == nil {
+		resp.Previous = &models.SalesMetrics{}
+	}
+	resp.Change = newSalesChange(&resp.Total, resp.Previous)
+
+	if helpers.Contains(req.GroupBy, "period") {
+		return &resp, nil
+	}
+
+	for _, row := range resp.Rows {
+		row.Previous = previous[strings.Join(salesReportKey(req.GroupBy, row), "\x00")]
+		if row.Previous == nil {
+			row.Previous = &models.SalesMetrics{}
+		}
+		row.Change = newSalesChange(&row.Metrics, row.Previous)
+	}
+
+	return &resp, nil
+}
+
+// TopProducts ranks the barcodes sold in the report period, within each
+// period when req.Interval is set.
+func (r *reportRepo) TopProducts(ctx context.Context, req *models.TopProductsRequest) (*models.TopProductsResponse, error) {
+
+	var (
+		resp   = models.TopProductsResponse{Products: []*models.TopProduct{}}
+		period = "''"
+		window = ""
+		group  = " GROUP BY sp.barcode"
+		order  string
+		params = []interface{}{
+			helpers.NewNullString(req.FromDate),
+			helpers.NewNullString(req.ToDate),
+			config.StockMovementSale,
+			config.SaleStatusFinished,
+			req.Limit,
+		}
+		where = " WHERE s.status = $4 AND ($1::timestamp IS NULL OR s.created_at >= $1) AND ($2::timestamp IS NULL OR s.created_at < $2)" +
+			saleLineFilter(&params, req.BranchID, req.SalePointID, req.CashierID, req.CategoryID, req.BrandID)
+	)
+
+	if req.Interval != "" {
+		if !helpers.Contains(config.ReportIntervals, req.Interval) {
+			return nil, fmt.Errorf("invalid interval %q", req.Interval)
+		}
+		period = fmt.Sprintf("TO_CHAR(DATE_TRUNC('%s', s.created_at), 'YYYY-MM-DD')", req.Interval)
+		window = "PARTITION BY " + period + " "
+		group = " GROUP BY " + period + ", sp.barcode"
+	}
+
+	switch req.OrderBy {
+	case "units":
+		order = "SUM(sp.quantity)"
+	case "margin":
+		order = "SUM(sp.total_amount) - SUM(sp.quantity * cost.unit)"
+	default:
+		order = "SUM(sp.total_amount)"
+	}
+
+	var query = `
+		SELECT period, rank, barcode, product_name, units, revenue, cost
+		FROM (
+			SELECT
+				` + period + ` AS period,
+				ROW_NUMBER() OVER (` + window + `ORDER BY COALESCE(` + order + `, 0) DESC, sp.barcode) AS rank,
+				COALESCE(sp.barcode, '') AS barcode,
+				COALESCE(MAX(sp.product_name), '') AS product_name,
+				COALESCE(SUM(sp.quantity), 0) AS units,
+				COALESCE(SUM(sp.total_amount), 0) AS revenue,
+				COALESCE(SUM(sp.quantity * cost.unit), 0) AS cost
+			` + saleLines + where + group + `
+		) AS ranked
+		WHERE rank <= $5
+		ORDER BY period, rank
+	`
+
+	rows, err := r.db.Query(ctx, query, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var product models.TopProduct
+
+		err = rows.Scan(
+			&product.Period,
+			&product.Rank,
+			&product.Barcode,
+			&product.ProductName,
+			&product.Units,
+			&product.Revenue,
+			&product.Cost,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		metrics := report.Metrics{Revenue: product.Revenue, Cost: product.Cost}
+		product.GrossMargin = report.GrossMargin(metrics)
+		product.MarginPercent = report.MarginPercent(metrics)
+
+		resp.Products = append(resp.Products, &product)
+	}
+
+	return &resp, rows.Err()
+}
+
+// Heatmap counts finished sales and their revenue per sale point, weekday
+// and hour of the day.
+func (r *reportRepo) Heatmap(ctx context.Context, req *models.HeatmapRequest) (*models.HeatmapResponse, error) {
+
+	var (
+		resp  = models.HeatmapResponse{Cells: []*models.HeatmapCell{}}
+		query = `
+			SELECT
+				s.branch_id,
+				b.name,
+				s.salepoint_id,
+				COALESCE(pt.name, ''),
+				EXTRACT(ISODOW FROM s.created_at)::int,
+				EXTRACT(HOUR FROM s.created_at)::int,
+				COUNT(*),
+				COALESCE(SUM(line.revenue), 0)
+			FROM sale AS s
+			JOIN branch AS b ON b.id = s.branch_id
+			LEFT JOIN sale_point AS pt ON pt.id = s.salepoint_id
+			LEFT JOIN LATERAL (
+				SELECT SUM(sp.total_amount) AS revenue
+				FROM sale_products AS sp
+				WHERE sp.sale_id = s.id
+			) AS line ON TRUE
+			WHERE s.status = $1
+				AND s.created_at >= $2 AND s.created_at < $3
+				AND ($4::uuid IS NULL OR s.branch_id = $4)
+				AND ($5::uuid IS NULL OR s.salepoint_id = $5)
+			GROUP BY 1, 2, 3, 4, 5, 6
+			ORDER BY 2, 4, 5, 6
+		`
+	)
+
+	rows, err := r.db.Query(ctx,
+		query,
+		config.SaleStatusFinished,
+		req.FromDate,
+		req.ToDate,
+		helpers.NewNullString(req.BranchID),
+		helpers.NewNullString(req.SalePointID),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cell models.HeatmapCell
+
+		err = rows.Scan(
+			&cell.BranchID,
+			&cell.BranchName,
+			&cell.SalePointID,
+			&cell.SalePointName,
+			&cell.Weekday,
+			&cell.Hour,
+			&cell.Sales,
+			&cell.Revenue,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Cells = append(resp.Cells, &cell)
+	}
+
+	return &resp, rows.Err()
+}
+
+// Cashiers reports every shift opened in the period and totals the shifts
+// per cashier. The cashier of a shift is the user it was opened for.
+func (r *reportRepo) Cashiers(ctx context.Context, req *models.CashierReportRequest) (*models.CashierReportResponse, error) {
+
+	var (
+		resp  = models.CashierReportResponse{Cashiers: []*models.CashierPerformance{}}
+		query = `
+			SELECT
+				sh.id,
+				sh.branch_id,
+				b.name,
+				COALESCE(sh.sale_point_id::text, ''),
+				COALESCE(pt.name, ''),
+				sh.user_id,
+				COALESCE(u.first_name || ' ' || u.last_name, ''),
+				TO_CHAR(sh.open_shift, 'YYYY-MM-DD HH24:MI:SS'),
+				COALESCE(TO_CHAR(sh.close_shift, 'YYYY-MM-DD HH24:MI:SS'), ''),
+				GREATEST(EXTRACT(EPOCH FROM COALESCE(sh.close_shift, NOW()::timestamp) - sh.open_shift) / 60, 0)::float8,
+				COALESCE(sales.sales, 0),
+				COALESCE(sales.revenue, 0),
+				COALESCE(sales.items, 0),
+				COALESCE(sales.voids, 0),
+				COALESCE(sales.returns, 0),
+				COALESCE(sh.opening_cash, 0),
+				COALESCE(tr.cash, 0),
+				sh.counted_cash
+			FROM shift AS sh
+			JOIN branch AS b ON b.id = sh.branch_id
+			LEFT JOIN sale_point AS pt ON pt.id = sh.sale_point_id
+			LEFT JOIN "user" AS u ON u.id = sh.user_id
+			LEFT JOIN LATERAL (
+				SELECT
+					COUNT(*) FILTER (WHERE s.status = $1) AS sales,
+					SUM(line.revenue) FILTER (WHERE s.status = $1) AS revenue,
+					SUM(line.items) FILTER (WHERE s.status = $1) AS items,
+					COUNT(*) FILTER (WHERE s.status = $2) AS voids,
+					COUNT(*) FILTER (WHERE s.status = $3) AS returns
+				FROM sale AS s
+				LEFT JOIN LATERAL (
+					SELECT SUM(sp.total_amount) AS revenue, SUM(sp.quantity) AS items
+					FROM sale_products AS sp
+					WHERE sp.sale_id = s.id
+				) AS line ON TRUE
+				WHERE s.shift_id = sh.id
+			) AS sales ON TRUE
+			LEFT JOIN LATERAL (
+				SELECT SUM(t.cash) AS cash
+				FROM transaction AS t
+				WHERE t.shift_id = sh.id
+			) AS tr ON TRUE
+			WHERE sh.open_shift >= $4 AND sh.open_shift < $5
+				AND ($6::uuid IS NULL OR sh.branch_id = $6)
+				AND ($7::uuid IS NULL OR sh.user_id = $7)
+			ORDER BY 7, sh.user_id, sh.open_shift
+		`
+	)
+
+	rows, err := r.db.Query(ctx,
+		query,
+		config.SaleStatusFinished,
+		config.SaleStatusCanceled,
+		config.SaleStatusReturned,
+		req.FromDate,
+		req.ToDate,
+		helpers.NewNullString(req.BranchID),
+		helpers.NewNullString(req.CashierID),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var byCashier = map[string]*models.CashierPerformance{}
+	for rows.Next() {
+		var (
+			shift       models.CashierShift
+			cashTaken   float64
+			countedCash sql.NullFloat64
+		)
+
+		err = rows.Scan(
+			&shift.ShiftID,
+			&shift.BranchID,
+			&shift.BranchName,
+			&shift.SalePointID,
+			&shift.SalePointName,
+			&shift.CashierID,
+			&shift.CashierName,
+			&shift.OpenShift,
+			&shift.CloseShift,
+			&shift.Minutes,
+			&shift.Sales,
+			&shift.Revenue,
+			&shift.Items,
+			&shift.Voids,
+			&shift.Returns,
+			&shift.OpeningCash,
+			&cashTaken,
+			&countedCash,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		shift.Minutes = math.Round(shift.Minutes*100) / 100
+		shift.ItemsPerMinute = report.ItemsPerMinute(shift.Items, shift.Minutes)
+		shift.AvgReceipt = report.AvgBasket(report.Metrics{Revenue: shift.Revenue, Sales: shift.Sales})
+		shift.ExpectedCash = shift.OpeningCash + cashTaken
+		if countedCash.Valid {
+			variance := report.CashVariance(shift.OpeningCash, cashTaken, countedCash.Float64)
+			shift.CountedCash = &countedCash.Float64
+			shift.CashVariance = &variance
+		}
+
+		cashier, ok := byCashier[shift.CashierID]
+		if !ok {
+			cashier = &models.CashierPerformance{
+				CashierID:   shift.CashierID,
+				CashierName: shift.CashierName,
+			}
+			byCashier[shift.CashierID] = cashier
+			resp.Cashiers = append(resp.Cashiers, cashier)
+		}
+		cashier.Shifts = append(cashier.Shifts, &shift)
+		cashier.Minutes += shift.Minutes
+		cashier.Sales += shift.Sales
+		cashier.Revenue += shift.Revenue
+		cashier.Items += shift.Items
+		cashier.Voids += shift.Voids
+		cashier.Returns += shift.Returns
+		if shift.CashVariance != nil {
+			cashier.CashVariance += *shift.CashVariance
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, cashier := range resp.Cashiers {
+		cashier.ItemsPerMinute = report.ItemsPerMinute(cashier.Items, cashier.Minutes)
+		cashier.AvgReceipt = report.AvgBasket(report.Metrics{Revenue: cashier.Revenue, Sales: cashier.Sales})
+	}
+
+	return &resp, nil
+}
+
+// branchStock sums the remainder of a branch per barcode, optionally for one
+// branch ($1).
+const branchStock = `
+	SELECT
+		rm.branch_id,
+		rm.barcode,
+		(ARRAY_AGG(rm.category_id ORDER BY rm.quantity DESC NULLS LAST))[1] AS category_id,
+		MAX(rm.product_name) AS product_name,
+		SUM(COALESCE(rm.quantity, 0)) AS quantity,
+		COALESCE(AVG(rm.price_income), 0) AS cost,
+		MIN(rm.created_at) AS created_at
+	FROM remainder AS rm
+	WHERE ($1::uuid IS NULL OR rm.branch_id = $1)
+	GROUP BY rm.branch_id, rm.barcode
+`
+
+// InventoryValuation values the stock of every branch and category at cost
+// and at retail price. For a past date the quantity is the current one less
+// every stock movement after that day, the cost is the last one a movement
+// recorded by then and the price the one in force then.
+func (r *reportRepo) InventoryValuation(ctx context.Context, req *models.InventoryValuationRequest) (*models.InventoryValuationResponse, error) {
+
+	var (
+		resp = models.InventoryValuationResponse{
+			AsOf: req.AsOf,
+			Rows: []*models.InventoryValuation{},
+		}
+		query = `
+			WITH stock AS (` + branchStock + `),
+			valued AS (
+				SELECT
+					st.branch_id,
+					st.category_id,
+					st.quantity - COALESCE((
+						SELECT SUM(sm.quantity)
+						FROM stock_movement AS sm
+						WHERE sm.branch_id = st.branch_id AND sm.barcode = st.barcode AND sm.created_at >= $3::date + 1
+					), 0) AS quantity,
+					CASE WHEN $3::date IS NULL THEN st.cost ELSE COALESCE((
+						SELECT sm.cost
+						FROM stock_movement AS sm
+						WHERE sm.branch_id = st.branch_id AND sm.barcode = st.barcode
+							AND sm.cost IS NOT NULL AND sm.created_at < $3::date + 1
+						ORDER BY sm.created_at DESC
+						LIMIT 1
+					), st.cost) END AS cost,
+					COALESCE(retail.price, 0) AS price
+				FROM stock AS st
+				LEFT JOIN LATERAL (
+					SELECT COALESCE(bp.price, p.price) AS price
+					FROM product AS p
+					LEFT JOIN LATERAL (
+						SELECT price
+						FROM branch_price
+						WHERE branch_id = st.branch_id AND product_id = p.id
+							AND effective_from < COALESCE($3::date + 1, NOW()::timestamp)
+						ORDER BY effective_from DESC, created_at DESC
+						LIMIT 1
+					) AS bp ON TRUE
+					WHERE p.barcode = st.barcode
+					LIMIT 1
+				) AS retail ON TRUE
+				WHERE ($2::uuid IS NULL OR st.category_id IN (SELECT id FROM category_subtree($2)))
+			)
+			SELECT
+				v.branch_id,
+				b.name,
+				COALESCE(v.category_id::text, ''),
+				COALESCE(c.title, ''),
+				COUNT(*) FILTER (WHERE v.quantity > 0),
+				SUM(v.quantity),
+				COALESCE(SUM(v.quantity * v.cost), 0),
+				COALESCE(SUM(v.quantity * v.price), 0)
+			FROM valued AS v
+			JOIN branch AS b ON b.id = v.branch_id
+			LEFT JOIN category AS c ON c.id = v.category_id
+			WHERE v.quantity <> 0
+			GROUP BY v.branch_id, b.name, v.category_id, c.title
+			ORDER BY b.name, c.title
+		`
+	)
+
+	rows, err := r.db.Query(ctx,
+		query,
+		helpers.NewNullString(req.BranchID),
+		helpers.NewNullString(req.CategoryID),
+		helpers.NewNullString(req.AsOf),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row models.InventoryValuation
+
+		err = rows.Scan(
+			&row.BranchID,
+			&row.BranchName,
+			&row.CategoryID,
+			&row.CategoryName,
+			&row.Products,
+			&row.Quantity,
+			&row.CostValue,
+			&row.RetailValue,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		metrics := report.Metrics{Revenue: row.RetailValue, Cost: row.CostValue}
+		row.PotentialMargin = report.GrossMargin(metrics)
+		row.MarginPercent = report.MarginPercent(metrics)
+
+		resp.Rows = append(resp.Rows, &row)
+		resp.Quantity += row.Quantity
+		resp.CostValue += row.CostValue
+		resp.RetailValue += row.RetailValue
+	}
+
+	resp.PotentialMargin = report.GrossMargin(report.Metrics{Revenue: resp.RetailValue, Cost: resp.CostValue})
+
+	return &resp, rows.Err()
+}
+
+// agedStock is the stock on hand with the date it was last received, by a
+// stock movement, a finished income or, failing both, when it was first
+// stocked, and the date it last sold in the branch.
+const agedStock = `
+	WITH stock AS (` + branchStock + `)
+	SELECT
+		st.*,
+		COALESCE(
+			(
+				SELECT MAX(sm.created_at)
+				FROM stock_movement AS sm
+				WHERE sm.branch_id = st.branch_id AND sm.barcode = st.barcode AND sm.type = ANY($3)
+			),
+			(
+				SELECT MAX(COALESCE(i.date_time, i.created_at))
+				FROM income_product AS ip
+				JOIN income AS i ON i.id = ip.income_id
+				WHERE i.branch_id = st.branch_id AND ip.barcode = st.barcode AND i.status = $4
+			),
+			st.created_at
+		) AS last_receipt,
+		(
+			SELECT MAX(s.created_at)
+			FROM sale_products AS sp
+			JOIN sale AS s ON s.id = sp.sale_id
+			WHERE s.branch_id = st.branch_id AND sp.barcode = st.barcode AND s.status = $5
+		) AS last_sale
+	FROM stock AS st
+	WHERE st.quantity > 0 AND ($2::uuid IS NULL OR st.category_id IN (SELECT id FROM category_subtree($2)))
+`
+
+// StockAging buckets the stock on hand by days since its last receipt and
+// lists the dead stock: received and unsold for req.DeadDays.
+func (r *reportRepo) StockAging(ctx context.Context, req *models.StockAgingRequest) (*models.StockAgingResponse, error) {
+
+	var (
+		resp = models.StockAgingResponse{
+			Buckets:   []*models.AgingBucket{},
+			DeadStock: []*models.DeadStock{},
+		}
+		params = []interface{}{
+			helpers.NewNullString(req.BranchID),
+			helpers.NewNullString(req.CategoryID),
+			[]string{config.StockMovementIncome, config.StockMovementTransferIn},
+			config.IncomeStatusFinished,
+			config.SaleStatusFinished,
+		}
+		bucketQuery = `
+			SELECT
+				WIDTH_BUCKET(EXTRACT(DAY FROM NOW()::timestamp - aged.last_receipt)::bigint, $6::bigint[]),
+				COUNT(*),
+				SUM(aged.quantity),
+				COALESCE(SUM(aged.quantity * aged.cost), 0)
+			FROM (` + agedStock + `) AS aged
+			GROUP BY 1
+			ORDER BY 1
+		`
+		deadQuery = `
+			SELECT
+				aged.branch_id,
+				b.name,
+				COALESCE(aged.barcode, ''),
+				COALESCE(aged.product_name, ''),
+				aged.quantity,
+				aged.quantity * aged.cost,
+				TO_CHAR(aged.last_receipt, 'YYYY-MM-DD'),
+				COALESCE(TO_CHAR(aged.last_sale, 'YYYY-MM-DD'), ''),
+				EXTRACT(DAY FROM NOW()::timestamp - COALESCE(aged.last_sale, aged.last_receipt))::bigint
+			FROM (` + agedStock + `) AS aged
+			JOIN branch AS b ON b.id = aged.branch_id
+			WHERE aged.last_receipt < NOW() - MAKE_INTERVAL(days => $6::int)
+				AND (aged.last_sale IS NULL OR aged.last_sale < NOW() - MAKE_INTERVAL(days => $6::int))
+			ORDER BY aged.quantity * aged.cost DESC, b.name, aged.product_name
+		`
+	)
+
+	rows, err := r.db.Query(ctx, bucketQuery, append(params, req.Buckets)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var buckets = make([]*models.AgingBucket, len(req.Buckets)+1)
+	for i := range buckets {
+		buckets[i] = &models.AgingBucket{Label: report.AgingLabel(req.Buckets, i)}
+	}
+
+	for rows.Next() {
+		var (
+			i      int
+			bucket models.AgingBucket
+		)
+
+		err = rows.Scan(&i, &bucket.Products, &bucket.Quantity, &bucket.CostValue)
+		if err != nil {
+			return nil, err
+		}
+
+		bucket.Label = buckets[i].Label
+		buckets[i] = &bucket
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	resp.Buckets = buckets
+
+	rows, err = r.db.Query(ctx, deadQuery, append(params, req.DeadDays)...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var dead models.DeadStock
+
+		err = rows.Scan(
+			&dead.BranchID,
+			&dead.BranchName,
+			&dead.Barcode,
+			&dead.ProductName,
+			&dead.Quantity,
+			&dead.CostValue,
+			&dead.LastReceipt,
+			&dead.LastSale,
+			&dead.DaysUnsold,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.DeadStock = append(resp.DeadStock, &dead)
+		resp.DeadStockCost += dead.CostValue
+	}
+
+	return &resp, rows.Err()
+}
+
+func newSalesMetrics(m report.Metrics) *models.SalesMetrics {
+	return &models.SalesMetrics{
+		Revenue:       m.Revenue,
+		Cost:          m.Cost,
+		GrossMargin:   report.GrossMargin(m),
+		MarginPercent: report.MarginPercent(m),
+		Sales:         m.Sales,
+		AvgBasket:     report.AvgBasket(m),
+		Units:         m.Units,
+	}
+}
+
+func newSalesChange(current, previous *models.SalesMetrics) *models.SalesChange {
+
+	change := func(current, previous float64) *float64 {
+		if growth, ok := report.Change(current, previous); ok {
+			return &growth
+		}
+		return nil
+	}
+
+	return &models.SalesChange{
+		Revenue:     change(current.Revenue, previous.Revenue),
+		GrossMargin: change(current.GrossMargin, previous.GrossMargin),
+		Sales:       change(float64(current.Sales), float64(previous.Sales)),
+		AvgBasket:   change(current.AvgBasket, previous.AvgBasket),
+		Units:       change(float64(current.Units), float64(previous.Units)),
+	}
+}
+
+// newSalesReportRow fills the dimensions of a row from the scanned values,
+// which come in groupBy order: one for period, an id and a name otherwise.
+func newSalesReportRow(groupBy, values []string, metrics *models.SalesMetrics) *models.SalesReportRow {
+
+	var row = models.SalesReportRow{Metrics: *metrics}
+	for _, name := range groupBy {
+		if name == "period" {
+			row.Period, values = values[0], values[1:]
+			continue
+		}
+
+		id, title := values[0], values[1]
+		values = values[2:]
+
+		switch name {
+		case "branch":
+			row.BranchID, row.BranchName = id, title
+		case "sale_point":
+			row.SalePointID, row.SalePointName = id, title
+		case "cashier":
+			row.CashierID, row.CashierName = id, title
+		case "category":
+			row.CategoryID, row.CategoryName = id, title
+		case "brand":
+			row.BrandID, row.BrandName = id, title
+		}
+	}
+
+	return &row
+}
+
+// salesReportKey is the inverse of newSalesReportRow, so rows of the two
+// periods can be matched.
+func salesReportKey(groupBy []string, row *models.SalesReportRow) []string {
+
+	var key []string
+	for _, name := range groupBy {
+		switch name {
+		case "branch":
+			key = append(key, row.BranchID, row.BranchName)
+		case "sale_point":
+			key = append(key, row.SalePointID, row.SalePointName)
+		case "cashier":
+			key = append(key, row.CashierID, row.CashierName)
+		case "category":
+			key = append(key, row.CategoryID, row.CategoryName)
+		case "brand":
+			key = append(key, row.BrandID, row.BrandName)
+		}
+	}
+
+	return key
+}
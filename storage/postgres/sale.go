@@ -3,10 +3,13 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	"fmt"
+	"math"
 
+	"market_system/config"
 	"market_system/models"
+	"market_system/pkg/criteria"
 	"market_system/pkg/helpers"
+	"market_system/storage"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v4/pgxpool"
@@ -73,6 +76,7 @@ func (r *saleRepo) GetByID(ctx context.Context, req *models.SalePrimaryKey) (*mo
 				employee_id,
 				barcode,
 				status,
+				customer_id,
 				created_at,
 				updated_at
 			FROM  sale
@@ -89,6 +93,7 @@ func (r *saleRepo) GetByID(ctx context.Context, req *models.SalePrimaryKey) (*mo
 		employeeId  sql.NullString
 		barcode     sql.NullString
 		status      sql.NullString
+		customerId  sql.NullString
 		createdAt   sql.NullString
 		updatedAt   sql.NullString
 	)
@@ -102,6 +107,7 @@ func (r *saleRepo) GetByID(ctx context.Context, req *models.SalePrimaryKey) (*mo
 		&employeeId,
 		&barcode,
 		&status,
+		&customerId,
 		&createdAt,
 		&updatedAt,
 	)
@@ -119,39 +125,50 @@ func (r *saleRepo) GetByID(ctx context.Context, req *models.SalePrimaryKey) (*mo
 		EmployeeID:  employeeId.String,
 		Barcode:     barcode.String,
 		Status:      status.String,
+		CustomerID:  customerId.String,
 		CreatedAt:   createdAt.String,
 		UpdatedAt:   updatedAt.String,
 	}, nil
 }
 
+var saleSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":           {Column: "id", Type: criteria.UUID},
+		"sale_id":      {Column: "sale_id", Type: criteria.Text},
+		"branch_id":    {Column: "branch_id", Type: criteria.UUID},
+		"salepoint_id": {Column: "salepoint_id", Type: criteria.UUID},
+		"shift_id":     {Column: "shift_id", Type: criteria.UUID},
+		"employee_id":  {Column: "employee_id", Type: criteria.UUID},
+		"barcode":      {Column: "barcode", Type: criteria.Text},
+		"status":       {Column: "status", Type: criteria.Text},
+		"customer_id":  {Column: "customer_id", Type: criteria.UUID},
+		"created_at":   {Column: "created_at", Type: criteria.Time},
+		"updated_at":   {Column: "updated_at", Type: criteria.Time},
+	},
+	Search: []string{"sale_id", "shift_id", "branch_id", "barcode", "employee_id"},
+	Sort:   []criteria.Sort{{Field: "created_at", Desc: true}},
+	Keyset: "created_at",
+}
+
 func (r *saleRepo) GetList(ctx context.Context, req *models.GetListSaleRequest) (*models.GetListSaleResponse, error) {
 	var (
-		resp   models.GetListSaleResponse
-		where  = " WHERE TRUE"
-		offset = " OFFSET 0"
-		limit  = " LIMIT 10"
-		sort   = " ORDER BY created_at DESC"
+		resp  models.GetListSaleResponse
+		where = saleSchema.Query()
 	)
 
-	if req.Offset > 0 {
-		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
-	}
-
-	if req.Limit > 0 {
-		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
-	}
-
-	if len(req.Search) > 0 {
-		where += " AND (sale_id ILIKE '%" + req.Search + "%' OR shift_id ILIKE '%" + req.Search + "%' OR branch_id ILIKE '%" + req.Search + "%' OR category_id ILIKE '%" + req.Search + "%' OR barcode ILIKE '%" + req.Search + "%' OR employee_id ILIKE '%" + req.Search + "%')"
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
 	}
+	where.Search(req.Search)
 
-	if len(req.Query) > 0 {
-		where += req.Query
+	sort, err := where.Cursor(req.Sort, req.Cursor)
+	if err != nil {
+		return nil, err
 	}
 
 	var query = `
 		SELECT
-			COUNT(*) OVER(),
 			id,
 			sale_id,
 			branch_id,
@@ -160,13 +177,14 @@ func (r *saleRepo) GetList(ctx context.Context, req *models.GetListSaleRequest)
 			employee_id,
 			barcode,
 			status,
+			customer_id,
 			created_at,
 			updated_at
 		FROM sale
 	`
 
-	query += where + sort + offset + limit
-	rows, err := r.db.Query(ctx, query)
+	query += where.SQL() + sort + where.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
 	if err != nil {
 		return nil, err
 	}
@@ -181,12 +199,12 @@ func (r *saleRepo) GetList(ctx context.Context, req *models.GetListSaleRequest)
 			employeeId  sql.NullString
 			barcode     sql.NullString
 			status      sql.NullString
+			customerId  sql.NullString
 			createdAt   sql.NullString
 			updatedAt   sql.NullString
 		)
 
 		err = rows.Scan(
-			&resp.Count,
 			&id,
 			&saleId,
 			&branchId,
@@ -195,6 +213,7 @@ func (r *saleRepo) GetList(ctx context.Context, req *models.GetListSaleRequest)
 			&employeeId,
 			&barcode,
 			&status,
+			&customerId,
 			&createdAt,
 			&updatedAt,
 		)
@@ -211,11 +230,27 @@ func (r *saleRepo) GetList(ctx context.Context, req *models.GetListSaleRequest)
 			EmployeeID:  employeeId.String,
 			Barcode:     barcode.String,
 			Status:      status.String,
+			CustomerID:  customerId.String,
 			CreatedAt:   createdAt.String,
 			UpdatedAt:   updatedAt.String,
 		})
 	}
 
+	if where.More(len(resp.Sales), req.Limit) {
+		resp.Sales = resp.Sales[:len(resp.Sales)-1]
+
+		var last = resp.Sales[len(resp.Sales)-1]
+		resp.NextCursor = where.NextCursor(last.CreatedAt, last.Id)
+	}
+
+	if req.Count {
+		total, args := where.Total()
+		err = r.db.QueryRow(ctx, `SELECT COUNT(*) FROM sale`+total, args...).Scan(&resp.Count)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &resp, nil
 }
 
@@ -255,3 +290,298 @@ func (r *saleRepo) Delete(ctx context.Context, req *models.SalePrimaryKey) error
 	_, err := r.db.Exec(ctx, "DELETE FROM sale WHERE id = $1", req.Id)
 	return err
 }
+
+// Finish closes a sale in one transaction, so that a sale is paid, taken out
+// of stock and booked on its customer once and all together. The sale is
+// locked first; a sale that is already finished is refused.
+func (r *saleRepo) Finish(ctx context.Context, req *models.FinishSale) error {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var status, customerID sql.NullString
+	err = tx.QueryRow(ctx,
+		"SELECT status, customer_id FROM sale WHERE id = $1 FOR UPDATE",
+		req.Id,
+	).Scan(&status, &customerID)
+	if err != nil {
+		return err
+	}
+
+	switch status.String {
+	case config.SaleStatusFinished:
+		return storage.ErrSaleFinished
+	case config.SaleStatusCanceled, config.SaleStatusReturned:
+		return storage.ErrSaleClosed
+	}
+
+	var payment = req.Payment
+	if payment.Points > 0 && !customerID.Valid {
+		return storage.ErrNotEnoughPoints
+	}
+
+	if payment.GiftCard > 0 {
+		_, err = redeemGiftCard(ctx, tx, &models.RedeemGiftCard{
+			Code:   payment.GiftCardCode,
+			SaleID: req.Id,
+			Amount: payment.GiftCard,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	giftCardSold, err := activateSaleGiftCards(ctx, tx, req.Id)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE transaction
+			SET
+				cash = COALESCE(cash, 0) + $2,
+				uzcard = COALESCE(uzcard, 0) + $3,
+				payme = COALESCE(payme, 0) + $4,
+				click = COALESCE(click, 0) + $5,
+				humo = COALESCE(humo, 0) + $6,
+				apelsin = COALESCE(apelsin, 0) + $7,
+				points = COALESCE(points, 0) + $8,
+				gift_card = COALESCE(gift_card, 0) + $9,
+				gift_card_sold = COALESCE(gift_card_sold, 0) + $10,
+				total_amount = COALESCE(total_amount, 0) + $11,
+				updated_at = NOW()
+		WHERE id = $1`,
+		req.TransactionID,
+		payment.Cash,
+		payment.Uzcard,
+		payment.Payme,
+		payment.Click,
+		payment.Humo,
+		payment.Apelsin,
+		payment.Points,
+		payment.GiftCard,
+		giftCardSold,
+		payment.TotalAmount,
+	)
+	if err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(ctx,
+		"SELECT barcode, COALESCE(quantity, 0) FROM sale_products WHERE sale_id = $1 AND COALESCE(barcode, '') <> ''",
+		req.Id,
+	)
+	if err != nil {
+		return err
+	}
+
+	var deduct = models.DeductRemainder{SaleID: req.Id, BranchID: req.BranchID}
+	for rows.Next() {
+		var product models.DeductRemainderProduct
+
+		err = rows.Scan(&product.Barcode, &product.Quantity)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+
+		deduct.Products = append(deduct.Products, &product)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	err = deductSale(ctx, tx, &deduct)
+	if err != nil {
+		return err
+	}
+
+	if customerID.Valid {
+		err = applySaleLoyalty(ctx, tx, &models.ApplySaleLoyalty{
+			SaleID:       req.Id,
+			CustomerID:   customerID.String,
+			RedeemPoints: payment.Points,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(ctx,
+		"UPDATE sale SET status = $2, updated_at = NOW() WHERE id = $1",
+		req.Id,
+		config.SaleStatusFinished,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Cancel voids a sale that was never paid. Nothing of it was booked yet, so
+// only its status changes.
+func (r *saleRepo) Cancel(ctx context.Context, req *models.SalePrimaryKey) error {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var status sql.NullString
+	err = tx.QueryRow(ctx, "SELECT status FROM sale WHERE id = $1 FOR UPDATE", req.Id).Scan(&status)
+	if err != nil {
+		return err
+	}
+
+	switch status.String {
+	case config.SaleStatusFinished:
+		return storage.ErrSaleFinished
+	case config.SaleStatusCanceled, config.SaleStatusReturned:
+		return storage.ErrSaleClosed
+	}
+
+	_, err = tx.Exec(ctx,
+		"UPDATE sale SET status = $2, updated_at = NOW() WHERE id = $1",
+		req.Id,
+		config.SaleStatusCanceled,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Return takes a finished sale back in one transaction. Its goods go back
+// into the branch stock, outside any lot, the points it earned and redeemed
+// are undone on its customer and what was paid for the goods in money is
+// refunded as store credit, whose id is returned ("" when nothing was paid
+// in money). Gift cards sold in the sale are cards of their own and stay
+// active.
+func (r *saleRepo) Return(ctx context.Context, req *models.ReturnSale) (string, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	var status, branchID, customerID sql.NullString
+	err = tx.QueryRow(ctx,
+		"SELECT status, branch_id, customer_id FROM sale WHERE id = $1 FOR UPDATE",
+		req.Id,
+	).Scan(&status, &branchID, &customerID)
+	if err != nil {
+		return "", err
+	}
+
+	switch status.String {
+	case config.SaleStatusFinished:
+	case config.SaleStatusCanceled, config.SaleStatusReturned:
+		return "", storage.ErrSaleClosed
+	default:
+		return "", storage.ErrSaleNotFinished
+	}
+
+	rows, err := tx.Query(ctx, `
+		SELECT
+			sp.category_id,
+			COALESCE(sp.product_name, ''),
+			sp.barcode,
+			COALESCE(sp.quantity, 0),
+			COALESCE((
+				SELECT r.price_income FROM remainder AS r
+				WHERE r.branch_id = $2 AND r.barcode = sp.barcode
+				ORDER BY r.created_at
+				LIMIT 1
+			), 0)
+		FROM sale_products AS sp
+		WHERE sp.sale_id = $1 AND sp.gift_card_id IS NULL AND COALESCE(sp.barcode, '') <> ''
+		`,
+		req.Id,
+		branchID.String,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	var lines []stockLine
+	for rows.Next() {
+		var line = stockLine{
+			BranchID:   branchID.String,
+			Type:       config.StockMovementSaleReturn,
+			DocumentID: req.Id,
+		}
+
+		err = rows.Scan(&line.CategoryID, &line.ProductName, &line.Barcode, &line.Quantity, &line.PriceIncome)
+		if err != nil {
+			rows.Close()
+			return "", err
+		}
+
+		lines = append(lines, line)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return "", err
+	}
+
+	for _, line := range lines {
+		if line.Quantity <= 0 {
+			continue
+		}
+
+		err = addStock(ctx, tx, line)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var total float64
+	err = tx.QueryRow(ctx,
+		"SELECT COALESCE(SUM(total_amount), 0) FROM sale_products WHERE sale_id = $1 AND gift_card_id IS NULL",
+		req.Id,
+	).Scan(&total)
+	if err != nil {
+		return "", err
+	}
+
+	var refund = total
+	if customerID.Valid {
+		redeemed, err := reverseSaleLoyalty(ctx, tx, req.Id, customerID.String, total)
+		if err != nil {
+			return "", err
+		}
+		refund = math.Max(total-redeemed, 0)
+	}
+
+	var storeCreditID string
+	if refund > 0 {
+		storeCreditID, err = issueStoreCredit(ctx, tx, &models.CreateStoreCredit{
+			Amount:     refund,
+			CustomerID: customerID.String,
+			SaleID:     req.Id,
+			Comment:    req.Comment,
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	_, err = tx.Exec(ctx,
+		"UPDATE sale SET status = $2, updated_at = NOW() WHERE id = $1",
+		req.Id,
+		config.SaleStatusReturned,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	return storeCreditID, tx.Commit(ctx)
+}
@@ -3,9 +3,9 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	"fmt"
 
 	"market_system/models"
+	"market_system/pkg/criteria"
 	"market_system/pkg/helpers"
 
 	"github.com/google/uuid"
@@ -94,29 +94,33 @@ func (r *SalePointRepo) GetByID(ctx context.Context, req *models.SalePointPrimar
 	}, nil
 }
 
+var salePointSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":         {Column: "id", Type: criteria.UUID},
+		"branch_id":  {Column: "branch_id", Type: criteria.UUID},
+		"name":       {Column: "name", Type: criteria.Text},
+		"created_at": {Column: "created_at", Type: criteria.Time},
+		"updated_at": {Column: "updated_at", Type: criteria.Time},
+	},
+	Search: []string{"name"},
+	Sort:   []criteria.Sort{{Field: "created_at", Desc: true}},
+}
+
 func (r *SalePointRepo) GetList(ctx context.Context, req *models.GetListSalePointRequest) (*models.GetListSalePointResponse, error) {
 	var (
-		resp   models.GetListSalePointResponse
-		where  = " WHERE TRUE"
-		offset = " OFFSET 0"
-		limit  = " LIMIT 10"
-		sort   = " ORDER BY created_at DESC"
+		resp  models.GetListSalePointResponse
+		where = salePointSchema.Query()
 	)
 
-	if req.Offset > 0 {
-		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
-	}
-
-	if req.Limit > 0 {
-		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
-	}
-
-	if len(req.Search) > 0 {
-		where += fmt.Sprintf(" AND name ILIKE '%%%s%%'", req.Search)
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
 	}
+	where.Search(req.Search)
 
-	if len(req.Query) > 0 {
-		where += req.Query
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
 	}
 
 	var query = `
@@ -130,8 +134,8 @@ func (r *SalePointRepo) GetList(ctx context.Context, req *models.GetListSalePoin
 		FROM sale_point
 	`
 
-	query += where + sort + offset + limit
-	rows, err := r.db.Query(ctx, query)
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
 	if err != nil {
 		return nil, err
 	}
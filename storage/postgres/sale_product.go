@@ -3,8 +3,8 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	"fmt"
 	"market_system/models"
+	"market_system/pkg/criteria"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v4/pgxpool"
@@ -144,35 +144,47 @@ func (r *saleProductRepo) GetByID(ctx context.Context, req *models.SaleProductPr
 	}, nil
 }
 
+var saleProductSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":                 {Column: "id", Type: criteria.UUID},
+		"sale_id":            {Column: "sale_id", Type: criteria.UUID},
+		"category_id":        {Column: "category_id", Type: criteria.UUID},
+		"product_name":       {Column: "product_name", Type: criteria.Text},
+		"barcode":            {Column: "barcode", Type: criteria.Text},
+		"remaining_quantity": {Column: "remaining_quantity", Type: criteria.Number},
+		"quantity":           {Column: "quantity", Type: criteria.Number},
+		"allow_discount":     {Column: "allow_discount", Type: criteria.Bool},
+		"discount_type":      {Column: "discount_type", Type: criteria.Text},
+		"discount":           {Column: "discount", Type: criteria.Number},
+		"price":              {Column: "price", Type: criteria.Number},
+		"total_amount":       {Column: "total_amount", Type: criteria.Number},
+		"created_at":         {Column: "created_at", Type: criteria.Time},
+		"updated_at":         {Column: "updated_at", Type: criteria.Time},
+	},
+	Search: []string{"category_id", "barcode"},
+	Sort:   []criteria.Sort{{Field: "created_at", Desc: true}},
+	Keyset: "created_at",
+}
+
 func (r *saleProductRepo) GetList(ctx context.Context, req *models.GetListSaleProductRequest) (*models.GetListSaleProductResponse, error) {
 	var (
-		resp   models.GetListSaleProductResponse
-		where  = " WHERE TRUE"
-		offset = " OFFSET 0"
-		limit  = " LIMIT 10"
-		sort   = " ORDER BY created_at DESC"
+		resp  models.GetListSaleProductResponse
+		where = saleProductSchema.Query()
 	)
 
-	if req.Offset > 0 {
-		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
-	}
-
-	if req.Limit > 0 {
-		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
-	}
-
-	if len(req.Search) > 0 {
-		where += " AND (category_id ILIKE '%" + req.Search + "%' OR barcode ILIKE '%" + req.Search + "%')"
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
 	}
+	where.Search(req.Search)
 
-
-	if len(req.Query) > 0 {
-		where += req.Query
+	sort, err := where.Cursor(req.Sort, req.Cursor)
+	if err != nil {
+		return nil, err
 	}
 
 	var query = `
 		SELECT
-			COUNT(*) OVER(),
 			id,
 			sale_id,
 			category_id,
@@ -190,8 +202,8 @@ func (r *saleProductRepo) GetList(ctx context.Context, req *models.GetListSalePr
 		FROM sale_products
 	`
 
-	query += where + sort + offset + limit
-	rows, err := r.db.Query(ctx, query)
+	query += where.SQL() + sort + where.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
 	if err != nil {
 		return nil, err
 	}
@@ -215,7 +227,6 @@ func (r *saleProductRepo) GetList(ctx context.Context, req *models.GetListSalePr
 		)
 
 		err = rows.Scan(
-			&resp.Count,
 			&ID,
 			&SaleID,
 			&CategoryID,
@@ -252,6 +263,21 @@ func (r *saleProductRepo) GetList(ctx context.Context, req *models.GetListSalePr
 		})
 	}
 
+	if where.More(len(resp.SaleProducts), req.Limit) {
+		resp.SaleProducts = resp.SaleProducts[:len(resp.SaleProducts)-1]
+
+		var last = resp.SaleProducts[len(resp.SaleProducts)-1]
+		resp.NextCursor = where.NextCursor(last.CreatedAt, last.Id)
+	}
+
+	if req.Count {
+		total, args := where.Total()
+		err = r.db.QueryRow(ctx, `SELECT COUNT(*) FROM sale_products`+total, args...).Scan(&resp.Count)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return &resp, nil
 }
 
@@ -3,9 +3,9 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	"fmt"
 
 	"market_system/models"
+	"market_system/pkg/criteria"
 	"market_system/pkg/helpers"
 
 	"github.com/google/uuid"
@@ -67,6 +67,8 @@ func (r *shiftRepo) GetByID(ctx context.Context, req *models.ShiftPrimaryKey) (*
 				status,
 				open_shift,
 				close_shift,
+				opening_cash,
+				counted_cash,
 				created_at,
 				updated_at
 			FROM shift
@@ -82,6 +84,8 @@ func (r *shiftRepo) GetByID(ctx context.Context, req *models.ShiftPrimaryKey) (*
 		Status      sql.NullString
 		OpenShift   sql.NullString
 		CloseShift  sql.NullString
+		OpeningCash sql.NullFloat64
+		CountedCash sql.NullFloat64
 		CreatedAt   sql.NullString
 		UpdatedAt   sql.NullString
 	)
@@ -94,6 +98,8 @@ func (r *shiftRepo) GetByID(ctx context.Context, req *models.ShiftPrimaryKey) (*
 		&Status,
 		&OpenShift,
 		&CloseShift,
+		&OpeningCash,
+		&CountedCash,
 		&CreatedAt,
 		&UpdatedAt,
 	)
@@ -110,34 +116,46 @@ func (r *shiftRepo) GetByID(ctx context.Context, req *models.ShiftPrimaryKey) (*
 		Status:      Status.String,
 		OpenShift:   OpenShift.String,
 		CloseShift:  CloseShift.String,
+		OpeningCash: OpeningCash.Float64,
+		CountedCash: nullFloat(CountedCash),
 		CreatedAt:   CreatedAt.String,
 		UpdatedAt:   UpdatedAt.String,
 	}, nil
 }
 
+var shiftSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":            {Column: "id", Type: criteria.UUID},
+		"branch_id":     {Column: "branch_id", Type: criteria.UUID},
+		"user_id":       {Column: "user_id", Type: criteria.UUID},
+		"sale_point_id": {Column: "sale_point_id", Type: criteria.UUID},
+		"status":        {Column: "status", Type: criteria.Text},
+		"open_shift":    {Column: "open_shift", Type: criteria.Time},
+		"close_shift":   {Column: "close_shift", Type: criteria.Time},
+		"opening_cash":  {Column: "opening_cash", Type: criteria.Number},
+		"counted_cash":  {Column: "counted_cash", Type: criteria.Number},
+		"created_at":    {Column: "created_at", Type: criteria.Time},
+		"updated_at":    {Column: "updated_at", Type: criteria.Time},
+	},
+	Search: []string{"branch_id", "user_id"},
+	Sort:   []criteria.Sort{{Field: "created_at", Desc: true}},
+}
+
 func (r *shiftRepo) GetList(ctx context.Context, req *models.GetListShiftRequest) (*models.GetListShiftResponse, error) {
 	var (
-		resp   models.GetListShiftResponse
-		where  = " WHERE TRUE"
-		offset = " OFFSET 0"
-		limit  = " LIMIT 10"
-		sort   = " ORDER BY created_at DESC"
+		resp  models.GetListShiftResponse
+		where = shiftSchema.Query()
 	)
 
-	if req.Offset > 0 {
-		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
-	}
-
-	if req.Limit > 0 {
-		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
-	}
-
-	if len(req.Search) > 0 {
-		where += " AND (branch_id ILIKE" + " '%" + req.Search + "%' OR employee_id ILIKE '%" + req.Search + "%')"
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
 	}
+	where.Search(req.Search)
 
-	if len(req.Query) > 0 {
-		where += req.Query
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
 	}
 
 	var query = `
@@ -150,13 +168,15 @@ func (r *shiftRepo) GetList(ctx context.Context, req *models.GetListShiftRequest
 			status,
 			open_shift,
 			close_shift,
+			opening_cash,
+			counted_cash,
 			created_at,
 			updated_at
 		FROM shift
 	`
 
-	query += where + sort + offset + limit
-	rows, err := r.db.Query(ctx, query)
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
 	if err != nil {
 		return nil, err
 	}
@@ -171,6 +191,8 @@ func (r *shiftRepo) GetList(ctx context.Context, req *models.GetListShiftRequest
 			Status      sql.NullString
 			OpenShift   sql.NullString
 			CloseShift  sql.NullString
+			OpeningCash sql.NullFloat64
+			CountedCash sql.NullFloat64
 			CreatedAt   sql.NullString
 			UpdatedAt   sql.NullString
 		)
@@ -184,6 +206,8 @@ func (r *shiftRepo) GetList(ctx context.Context, req *models.GetListShiftRequest
 			&Status,
 			&OpenShift,
 			&CloseShift,
+			&OpeningCash,
+			&CountedCash,
 			&CreatedAt,
 			&UpdatedAt,
 		)
@@ -199,6 +223,8 @@ func (r *shiftRepo) GetList(ctx context.Context, req *models.GetListShiftRequest
 			Status:      Status.String,
 			OpenShift:   OpenShift.String,
 			CloseShift:  CloseShift.String,
+			OpeningCash: OpeningCash.Float64,
+			CountedCash: nullFloat(CountedCash),
 			CreatedAt:   CreatedAt.String,
 			UpdatedAt:   UpdatedAt.String,
 		})
@@ -217,6 +243,8 @@ func (r *shiftRepo) Update(ctx context.Context, req *models.UpdateShift) (int64,
 				status = $5,
 				open_shift = $6,
 				close_shift = $7,
+				opening_cash = $8,
+				counted_cash = $9,
 				updated_at = NOW()
 		WHERE id = $1
 	`
@@ -229,6 +257,8 @@ func (r *shiftRepo) Update(ctx context.Context, req *models.UpdateShift) (int64,
 		helpers.NewNullString(req.Status),
 		helpers.NewNullString(req.OpenShift),
 		helpers.NewNullString(req.CloseShift),
+		req.OpeningCash,
+		req.CountedCash,
 	)
 	if err != nil {
 		return 0, err
@@ -241,3 +271,12 @@ func (r *shiftRepo) Delete(ctx context.Context, req *models.ShiftPrimaryKey) err
 	_, err := r.db.Exec(ctx, "DELETE FROM shift WHERE id = $1", req.Id)
 	return err
 }
+
+// nullFloat is the value of a nullable column, or nil when it is NULL.
+func nullFloat(f sql.NullFloat64) *float64 {
+	if !f.Valid {
+		return nil
+	}
+
+	return &f.Float64
+}
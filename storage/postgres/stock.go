@@ -0,0 +1,270 @@
+package postgres
+
+import (
+	"context"
+
+	"market_system/pkg/helpers"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+)
+
+// stockLine is a quantity of one product moving in or out of a branch.
+// Type and DocumentID say what moved it and end up in stock_movement.
+// LotNumber and ExpiryDate, when set on a line coming in, put it into a lot.
+type stockLine struct {
+	BranchID    string
+	CategoryID  string
+	ProductName string
+	Barcode     string
+	PriceIncome float64
+	Quantity    int64
+	Type        string
+	DocumentID  string
+	LotNumber   string
+	ExpiryDate  string
+}
+
+// stockLot is the part of a line that came out of one lot.
+type stockLot struct {
+	LotNumber  string
+	ExpiryDate string
+	Quantity   int64
+}
+
+// addStock puts a line into the branch remainder inside tx. The existing
+// remainder row for the barcode is increased and takes the new income price;
+// a row is created when the branch has never stocked the barcode.
+func addStock(ctx context.Context, tx pgx.Tx, line stockLine) error {
+
+	var query = `
+		UPDATE remainder
+			SET
+				quantity = COALESCE(quantity, 0) + $3,
+				price_income = $4,
+				updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM remainder
+			WHERE branch_id = $1 AND barcode = $2
+			ORDER BY created_at
+			LIMIT 1
+			FOR UPDATE
+		)
+		RETURNING quantity
+	`
+
+	var left int64
+	err := tx.QueryRow(ctx, query, line.BranchID, line.Barcode, line.Quantity, line.PriceIncome).Scan(&left)
+	if err == pgx.ErrNoRows {
+		left = line.Quantity
+		_, err = tx.Exec(ctx,
+			`INSERT INTO remainder(
+				id,
+				branch_id,
+				category_id,
+				product_name,
+				barcode,
+				price_income,
+				quantity,
+				updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())`,
+			uuid.New().String(),
+			line.BranchID,
+			line.CategoryID,
+			line.ProductName,
+			line.Barcode,
+			line.PriceIncome,
+			line.Quantity,
+		)
+	}
+	if err != nil {
+		return err
+	}
+
+	if line.LotNumber != "" || line.ExpiryDate != "" {
+		err = addLot(ctx, tx, line)
+		if err != nil {
+			return err
+		}
+	}
+
+	return recordMovement(ctx, tx, line, line.Quantity, left)
+}
+
+// addLot puts a line into its lot of the branch stock.
+func addLot(ctx context.Context, tx pgx.Tx, line stockLine) error {
+
+	_, err := tx.Exec(ctx, `
+		INSERT INTO stock_lot(
+			id,
+			branch_id,
+			barcode,
+			product_name,
+			lot_number,
+			expiry_date,
+			quantity,
+			cost,
+			updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6::date, $7, $8, NOW())
+		ON CONFLICT (branch_id, barcode, lot_number, COALESCE(expiry_date, 'infinity'::date)) DO UPDATE
+			SET
+				quantity = stock_lot.quantity + EXCLUDED.quantity,
+				cost = EXCLUDED.cost,
+				updated_at = NOW()
+		`,
+		uuid.New().String(),
+		line.BranchID,
+		line.Barcode,
+		line.ProductName,
+		line.LotNumber,
+		helpers.NewNullString(line.ExpiryDate),
+		line.Quantity,
+		line.PriceIncome,
+	)
+
+	return err
+}
+
+// removeStock takes a line out of the branch remainder inside tx and returns
+// the quantity left. pgx.ErrNoRows means the branch has no remainder row for
+// the barcode. Callers that must not oversell check the returned quantity;
+// the transaction is theirs to roll back.
+func removeStock(ctx context.Context, tx pgx.Tx, line stockLine) (int64, error) {
+	left, _, err := removeStockLots(ctx, tx, line)
+	return left, err
+}
+
+// removeStockLots is removeStock that also returns the lots the line was
+// taken from. Lots are consumed first-expiry-first-out; what the lots do not
+// cover comes out of stock received without a lot.
+func removeStockLots(ctx context.Context, tx pgx.Tx, line stockLine) (int64, []stockLot, error) {
+
+	var (
+		left  int64
+		query = `
+			UPDATE remainder
+				SET
+					quantity = COALESCE(quantity, 0) - $3,
+					updated_at = NOW()
+			WHERE id = (
+				SELECT id FROM remainder
+				WHERE branch_id = $1 AND barcode = $2
+				ORDER BY created_at
+				LIMIT 1
+				FOR UPDATE
+			)
+			RETURNING quantity, product_name, price_income
+		`
+	)
+
+	var (
+		productName *string
+		priceIncome *float64
+	)
+
+	err := tx.QueryRow(ctx, query, line.BranchID, line.Barcode, line.Quantity).Scan(&left, &productName, &priceIncome)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if line.ProductName == "" && productName != nil {
+		line.ProductName = *productName
+	}
+	if line.PriceIncome == 0 && priceIncome != nil {
+		line.PriceIncome = *priceIncome
+	}
+
+	lots, err := consumeLots(ctx, tx, line)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return left, lots, recordMovement(ctx, tx, line, -line.Quantity, left)
+}
+
+// consumeLots takes up to the line quantity out of the branch lots of the
+// barcode, earliest expiry first, and returns what it took from each.
+func consumeLots(ctx context.Context, tx pgx.Tx, line stockLine) ([]stockLot, error) {
+
+	rows, err := tx.Query(ctx, `
+		SELECT id, lot_number, COALESCE(TO_CHAR(expiry_date, 'YYYY-MM-DD'), ''), quantity
+		FROM stock_lot
+		WHERE branch_id = $1 AND barcode = $2 AND quantity > 0
+		ORDER BY expiry_date NULLS LAST, created_at
+		FOR UPDATE
+		`,
+		line.BranchID,
+		line.Barcode,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		ids       []string
+		lots      []stockLot
+		remaining = line.Quantity
+	)
+	for remaining > 0 && rows.Next() {
+		var (
+			id  string
+			lot stockLot
+		)
+		err = rows.Scan(&id, &lot.LotNumber, &lot.ExpiryDate, &lot.Quantity)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+
+		if lot.Quantity > remaining {
+			lot.Quantity = remaining
+		}
+		remaining -= lot.Quantity
+
+		ids = append(ids, id)
+		lots = append(lots, lot)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for i, id := range ids {
+		_, err = tx.Exec(ctx, "UPDATE stock_lot SET quantity = quantity - $2, updated_at = NOW() WHERE id = $1", id, lots[i].Quantity)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return lots, nil
+}
+
+// recordMovement appends a line to the stock movement history. quantity is
+// signed: positive into the branch, negative out of it.
+func recordMovement(ctx context.Context, tx pgx.Tx, line stockLine, quantity, balance int64) error {
+
+	_, err := tx.Exec(ctx,
+		`INSERT INTO stock_movement(
+			id,
+			branch_id,
+			barcode,
+			product_name,
+			type,
+			document_id,
+			quantity,
+			cost,
+			balance_after
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		uuid.New().String(),
+		line.BranchID,
+		line.Barcode,
+		line.ProductName,
+		line.Type,
+		line.DocumentID,
+		quantity,
+		line.PriceIncome,
+		balance,
+	)
+
+	return err
+}
@@ -0,0 +1,416 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+	"market_system/pkg/replenish"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type stockLevelRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewStockLevelRepo(db *pgxpool.Pool) *stockLevelRepo {
+	return &stockLevelRepo{
+		db: db,
+	}
+}
+
+const stockLevelColumns = `
+	sl.id,
+	sl.branch_id,
+	sl.product_id,
+	p.title,
+	p.barcode,
+	sl.min_quantity,
+	sl.max_quantity,
+	sl.created_at,
+	sl.updated_at
+`
+
+func scanStockLevel(row pgx.Row, extra ...interface{}) (*models.StockLevel, error) {
+
+	var (
+		ID          sql.NullString
+		BranchID    sql.NullString
+		ProductID   sql.NullString
+		ProductName sql.NullString
+		Barcode     sql.NullString
+		MinQuantity sql.NullInt64
+		MaxQuantity sql.NullInt64
+		CreatedAt   sql.NullString
+		UpdatedAt   sql.NullString
+	)
+
+	dest := append(extra,
+		&ID,
+		&BranchID,
+		&ProductID,
+		&ProductName,
+		&Barcode,
+		&MinQuantity,
+		&MaxQuantity,
+		&CreatedAt,
+		&UpdatedAt,
+	)
+
+	err := row.Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.StockLevel{
+		Id:          ID.String,
+		BranchID:    BranchID.String,
+		ProductID:   ProductID.String,
+		ProductName: ProductName.String,
+		Barcode:     Barcode.String,
+		MinQuantity: MinQuantity.Int64,
+		MaxQuantity: MaxQuantity.Int64,
+		CreatedAt:   CreatedAt.String,
+		UpdatedAt:   UpdatedAt.String,
+	}, nil
+}
+
+// Create sets the levels of a product in a branch, replacing the levels it
+// already has there.
+func (r *stockLevelRepo) Create(ctx context.Context, req *models.CreateStockLevel) (*models.StockLevel, error) {
+
+	var (
+		stockLevelID string
+		query        = `
+			INSERT INTO stock_level(
+				id,
+				branch_id,
+				product_id,
+				min_quantity,
+				max_quantity,
+				updated_at
+			) VALUES ($1, $2, $3, $4, $5, NOW())
+			ON CONFLICT (branch_id, product_id) DO UPDATE
+				SET
+					min_quantity = EXCLUDED.min_quantity,
+					max_quantity = EXCLUDED.max_quantity,
+					updated_at = NOW()
+			RETURNING id`
+	)
+
+	err := r.db.QueryRow(ctx,
+		query,
+		uuid.New().String(),
+		req.BranchID,
+		req.ProductID,
+		req.MinQuantity,
+		req.MaxQuantity,
+	).Scan(&stockLevelID)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, &models.StockLevelPrimaryKey{Id: stockLevelID})
+}
+
+func (r *stockLevelRepo) GetByID(ctx context.Context, req *models.StockLevelPrimaryKey) (*models.StockLevel, error) {
+
+	var query = "SELECT " + stockLevelColumns + " FROM stock_level AS sl JOIN product AS p ON p.id = sl.product_id WHERE sl.id = $1"
+
+	return scanStockLevel(r.db.QueryRow(ctx, query, req.Id))
+}
+
+var stockLevelSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":           {Column: "sl.id", Type: criteria.UUID},
+		"branch_id":    {Column: "sl.branch_id", Type: criteria.UUID},
+		"product_id":   {Column: "sl.product_id", Type: criteria.UUID},
+		"product_name": {Column: "p.title", Type: criteria.Text},
+		"barcode":      {Column: "p.barcode", Type: criteria.Text},
+		"min_quantity": {Column: "sl.min_quantity", Type: criteria.Number},
+		"max_quantity": {Column: "sl.max_quantity", Type: criteria.Number},
+		"created_at":   {Column: "sl.created_at", Type: criteria.Time},
+		"updated_at":   {Column: "sl.updated_at", Type: criteria.Time},
+	},
+	Sort: []criteria.Sort{{Field: "product_name"}},
+}
+
+func (r *stockLevelRepo) GetList(ctx context.Context, req *models.GetListStockLevelRequest) (*models.GetListStockLevelResponse, error) {
+	var (
+		resp  models.GetListStockLevelResponse
+		where = stockLevelSchema.Query()
+	)
+
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var query = "SELECT COUNT(*) OVER(), " + stockLevelColumns + " FROM stock_level AS sl JOIN product AS p ON p.id = sl.product_id"
+
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		stockLevel, err := scanStockLevel(rows, &resp.Count)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.StockLevels = append(resp.StockLevels, stockLevel)
+	}
+
+	return &resp, rows.Err()
+}
+
+func (r *stockLevelRepo) Update(ctx context.Context, req *models.UpdateStockLevel) (int64, error) {
+
+	query := `
+		UPDATE stock_level
+			SET
+				min_quantity = $2,
+				max_quantity = $3,
+				updated_at = NOW()
+		WHERE id = $1
+	`
+
+	rowsAffected, err := r.db.Exec(ctx,
+		query,
+		req.Id,
+		req.MinQuantity,
+		req.MaxQuantity,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
+
+func (r *stockLevelRepo) Delete(ctx context.Context, req *models.StockLevelPrimaryKey) error {
+	_, err := r.db.Exec(ctx, "DELETE FROM stock_level WHERE id = $1", req.Id)
+	return err
+}
+
+// stockPosition sums, for every stock level, what the branch has on hand and
+// what open purchase orders still have to deliver.
+const stockPosition = `
+	SELECT
+		sl.branch_id,
+		sl.product_id,
+		p.title,
+		COALESCE(p.barcode, '') AS barcode,
+		sl.min_quantity,
+		sl.max_quantity,
+		COALESCE((
+			SELECT SUM(COALESCE(rm.quantity, 0))
+			FROM remainder AS rm
+			WHERE rm.branch_id = sl.branch_id AND rm.barcode = p.barcode
+		), 0) AS on_hand,
+		COALESCE((
+			SELECT SUM(GREATEST(pop.quantity - pop.received_quantity, 0))
+			FROM purchase_order_product AS pop
+			JOIN purchase_order AS po ON po.id = pop.purchase_order_id
+			WHERE po.branch_id = sl.branch_id AND pop.product_id = sl.product_id AND po.status = ANY($1)
+		), 0) AS on_order
+	FROM stock_level AS sl
+	JOIN product AS p ON p.id = sl.product_id
+`
+
+// openPurchaseOrder lists the purchase order statuses whose undelivered
+// quantities count as on order.
+var openPurchaseOrder = []string{config.PurchaseOrderSent, config.PurchaseOrderPartiallyReceived}
+
+// LowStock lists products whose stock on hand is at or below their minimum.
+func (r *stockLevelRepo) LowStock(ctx context.Context, req *models.LowStockRequest) (*models.LowStockResponse, error) {
+
+	var (
+		resp  = models.LowStockResponse{Products: []*models.LowStock{}}
+		query = `
+			WITH position AS (` + stockPosition + `
+				WHERE ($2::uuid IS NULL OR sl.branch_id = $2)
+			)
+			SELECT
+				b.id,
+				b.name,
+				ps.product_id,
+				ps.title,
+				ps.barcode,
+				ps.on_hand,
+				ps.on_order,
+				ps.min_quantity,
+				ps.max_quantity
+			FROM position AS ps
+			JOIN branch AS b ON b.id = ps.branch_id
+			WHERE ps.on_hand <= ps.min_quantity
+			ORDER BY b.name, ps.on_hand - ps.min_quantity, ps.title
+		`
+	)
+
+	rows, err := r.db.Query(ctx, query, openPurchaseOrder, helpers.NewNullString(req.BranchID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var product models.LowStock
+
+		err = rows.Scan(
+			&product.BranchID,
+			&product.BranchName,
+			&product.ProductID,
+			&product.ProductName,
+			&product.Barcode,
+			&product.OnHand,
+			&product.OnOrder,
+			&product.MinQuantity,
+			&product.MaxQuantity,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Products = append(resp.Products, &product)
+	}
+	resp.Count = len(resp.Products)
+
+	return &resp, rows.Err()
+}
+
+// Replenishment proposes order quantities for the products of a branch that
+// have stock levels, grouped by the supplier each was last bought from
+// (preferably by this branch). Average daily sales come from finished sales
+// of the last req.HistoryDays days.
+func (r *stockLevelRepo) Replenishment(ctx context.Context, req *models.ReplenishmentRequest) (*models.ReplenishmentResponse, error) {
+
+	var (
+		resp = models.ReplenishmentResponse{
+			BranchID:  req.BranchID,
+			Suppliers: []*models.ReplenishmentSupplier{},
+		}
+		query = `
+			WITH position AS (` + stockPosition + `
+				WHERE sl.branch_id = $2
+			)
+			SELECT
+				ps.product_id,
+				ps.title,
+				ps.barcode,
+				ps.on_hand,
+				ps.on_order,
+				ps.min_quantity,
+				ps.max_quantity,
+				COALESCE((
+					SELECT SUM(sp.quantity)
+					FROM sale_products AS sp
+					JOIN sale AS s ON s.id = sp.sale_id
+					WHERE s.branch_id = $2
+						AND s.status = $3
+						AND sp.barcode = ps.barcode
+						AND s.created_at >= NOW() - MAKE_INTERVAL(days => $4::int)
+				), 0),
+				COALESCE(last.supplier_id::text, ''),
+				COALESCE(last.supplier_name, ''),
+				COALESCE(last.price, 0)
+			FROM position AS ps
+			LEFT JOIN LATERAL (
+				SELECT i.supplier_id, su.name AS supplier_name, ip.income_price AS price
+				FROM income_product AS ip
+				JOIN income AS i ON i.id = ip.income_id
+				JOIN supplier AS su ON su.id = i.supplier_id
+				WHERE ip.barcode = ps.barcode
+				ORDER BY i.branch_id = $2 DESC, COALESCE(i.date_time, i.created_at) DESC
+				LIMIT 1
+			) AS last ON TRUE
+			WHERE ($5::uuid[] IS NULL OR last.supplier_id = ANY($5))
+			ORDER BY last.supplier_name NULLS LAST, ps.title
+		`
+	)
+
+	var supplierIDs []string
+	if len(req.SupplierIDs) > 0 {
+		supplierIDs = req.SupplierIDs
+	}
+
+	rows, err := r.db.Query(ctx,
+		query,
+		openPurchaseOrder,
+		req.BranchID,
+		config.SaleStatusFinished,
+		req.HistoryDays,
+		supplierIDs,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var bySupplier = map[string]*models.ReplenishmentSupplier{}
+	for rows.Next() {
+		var (
+			line     models.ReplenishmentLine
+			sold     int64
+			supplier models.ReplenishmentSupplier
+		)
+
+		err = rows.Scan(
+			&line.ProductID,
+			&line.ProductName,
+			&line.Barcode,
+			&line.OnHand,
+			&line.OnOrder,
+			&line.MinQuantity,
+			&line.MaxQuantity,
+			&sold,
+			&supplier.SupplierID,
+			&supplier.SupplierName,
+			&line.Price,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		line.AvgDailySales = replenish.AvgDailySales(sold, req.HistoryDays)
+
+		item := replenish.Item{
+			OnHand:        line.OnHand,
+			OnOrder:       line.OnOrder,
+			AvgDailySales: line.AvgDailySales,
+			Min:           line.MinQuantity,
+			Max:           line.MaxQuantity,
+		}
+		line.ReorderPoint = replenish.ReorderPoint(item, req.LeadDays)
+		line.Quantity = replenish.Suggest(item, req.LeadDays)
+		if line.Quantity <= 0 {
+			continue
+		}
+
+		group, ok := bySupplier[supplier.SupplierID]
+		if !ok {
+			group = &supplier
+			bySupplier[supplier.SupplierID] = group
+			resp.Suppliers = append(resp.Suppliers, group)
+		}
+		group.Lines = append(group.Lines, &line)
+		group.Quantity += line.Quantity
+		group.Cost += float64(line.Quantity) * line.Price
+	}
+
+	return &resp, rows.Err()
+}
@@ -0,0 +1,193 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type stockLotRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewStockLotRepo(db *pgxpool.Pool) *stockLotRepo {
+	return &stockLotRepo{
+		db: db,
+	}
+}
+
+var stockLotSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":           {Column: "id", Type: criteria.UUID},
+		"branch_id":    {Column: "branch_id", Type: criteria.UUID},
+		"barcode":      {Column: "barcode", Type: criteria.Text},
+		"product_name": {Column: "product_name", Type: criteria.Text},
+		"lot_number":   {Column: "lot_number", Type: criteria.Text},
+		"expiry_date":  {Column: "expiry_date", Type: criteria.Time},
+		"quantity":     {Column: "quantity", Type: criteria.Number},
+		"cost":         {Column: "cost", Type: criteria.Number},
+		"created_at":   {Column: "created_at", Type: criteria.Time},
+		"updated_at":   {Column: "updated_at", Type: criteria.Time},
+	},
+	Sort: []criteria.Sort{{Field: "expiry_date"}, {Field: "created_at"}},
+}
+
+// GetList returns lots, by default in the order sales consume them: earliest
+// expiry first.
+func (r *stockLotRepo) GetList(ctx context.Context, req *models.GetListStockLotRequest) (*models.GetListStockLotResponse, error) {
+	var (
+		resp  models.GetListStockLotResponse
+		where = stockLotSchema.Query()
+	)
+
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.InStock {
+		where.And("quantity > 0")
+	}
+
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var query = `
+		SELECT
+			COUNT(*) OVER(),
+			id,
+			branch_id,
+			barcode,
+			product_name,
+			lot_number,
+			TO_CHAR(expiry_date, 'YYYY-MM-DD'),
+			quantity,
+			cost,
+			created_at,
+			updated_at
+		FROM stock_lot
+	`
+
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			ID          sql.NullString
+			BranchID    sql.NullString
+			Barcode     sql.NullString
+			ProductName sql.NullString
+			LotNumber   sql.NullString
+			ExpiryDate  sql.NullString
+			Quantity    sql.NullInt64
+			Cost        sql.NullFloat64
+			CreatedAt   sql.NullString
+			UpdatedAt   sql.NullString
+		)
+
+		err = rows.Scan(
+			&resp.Count,
+			&ID,
+			&BranchID,
+			&Barcode,
+			&ProductName,
+			&LotNumber,
+			&ExpiryDate,
+			&Quantity,
+			&Cost,
+			&CreatedAt,
+			&UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.StockLots = append(resp.StockLots, &models.StockLot{
+			Id:          ID.String,
+			BranchID:    BranchID.String,
+			Barcode:     Barcode.String,
+			ProductName: ProductName.String,
+			LotNumber:   LotNumber.String,
+			ExpiryDate:  ExpiryDate.String,
+			Quantity:    Quantity.Int64,
+			Cost:        Cost.Float64,
+			CreatedAt:   CreatedAt.String,
+			UpdatedAt:   UpdatedAt.String,
+		})
+	}
+
+	return &resp, rows.Err()
+}
+
+// Expiring lists lots still in stock that expire within req.Days days,
+// expired lots included, soonest first.
+func (r *stockLotRepo) Expiring(ctx context.Context, req *models.ExpiringStockLotRequest) (*models.ExpiringStockLotResponse, error) {
+
+	var (
+		resp  = models.ExpiringStockLotResponse{StockLots: []*models.ExpiringStockLot{}}
+		query = `
+			SELECT
+				sl.id,
+				b.id,
+				b.name,
+				sl.barcode,
+				COALESCE(sl.product_name, ''),
+				sl.lot_number,
+				TO_CHAR(sl.expiry_date, 'YYYY-MM-DD'),
+				sl.expiry_date - CURRENT_DATE,
+				sl.quantity,
+				COALESCE(sl.cost, 0)
+			FROM stock_lot AS sl
+			JOIN branch AS b ON b.id = sl.branch_id
+			WHERE sl.quantity > 0
+				AND sl.expiry_date <= CURRENT_DATE + $1::int
+				AND ($2::uuid IS NULL OR sl.branch_id = $2)
+			ORDER BY sl.expiry_date, b.name, sl.product_name
+		`
+	)
+
+	rows, err := r.db.Query(ctx, query, req.Days, helpers.NewNullString(req.BranchID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var lot models.ExpiringStockLot
+
+		err = rows.Scan(
+			&lot.Id,
+			&lot.BranchID,
+			&lot.BranchName,
+			&lot.Barcode,
+			&lot.ProductName,
+			&lot.LotNumber,
+			&lot.ExpiryDate,
+			&lot.DaysLeft,
+			&lot.Quantity,
+			&lot.Cost,
+		)
+		if err != nil {
+			return nil, err
+		}
+		lot.Expired = lot.DaysLeft < 0
+		lot.TotalCost = float64(lot.Quantity) * lot.Cost
+
+		resp.StockLots = append(resp.StockLots, &lot)
+		resp.TotalCost += lot.TotalCost
+	}
+	resp.Count = len(resp.StockLots)
+
+	return &resp, rows.Err()
+}
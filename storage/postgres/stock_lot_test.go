@@ -0,0 +1,145 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+
+	"github.com/google/uuid"
+)
+
+func Test_stockLotRepo_FEFO(t *testing.T) {
+
+	cfg := config.Load()
+
+	strg, err := NewConnectionPostgres(&cfg)
+	if err != nil {
+		t.Fatalf("failed to connect to PostgreSQL: %v", err)
+	}
+
+	var (
+		ctx  = context.Background()
+		item = newTestStockItem(t, strg, 0)
+		soon = time.Now().AddDate(0, 0, 5).Format("2006-01-02")
+		late = time.Now().AddDate(0, 0, 30).Format("2006-01-02")
+	)
+
+	supplier, err := strg.Supplier().Create(ctx, &models.CreateSupplier{Name: "lot " + uuid.NewString(), IsActive: true})
+	if err != nil {
+		t.Fatalf("supplierRepo.Create() error = %v", err)
+	}
+
+	income, err := strg.Income().Create(ctx, &models.CreateIncome{
+		BranchID:   item.BranchID,
+		SupplierID: supplier.Id,
+		DateTime:   time.Now().Format("2006-01-02 15:04:05"),
+		Status:     config.IncomeStatusDraft,
+	})
+	if err != nil {
+		t.Fatalf("incomeRepo.Create() error = %v", err)
+	}
+
+	// the later lot is received first, so FEFO and not the receiving order
+	// decides which lot a sale takes
+	for _, lot := range []struct {
+		number   string
+		expiry   string
+		quantity int64
+	}{
+		{number: "late", expiry: late, quantity: 5},
+		{number: "soon", expiry: soon, quantity: 4},
+	} {
+		_, err = strg.IncomeProduct().Create(ctx, &models.CreateIncomeProduct{
+			IncomeID:    income.Id,
+			CategoryID:  item.CategoryID,
+			ProductName: item.ProductName,
+			Barcode:     item.Barcode,
+			Quantity:    lot.quantity,
+			IncomePrice: item.PriceIncome,
+			LotNumber:   lot.number,
+			ExpiryDate:  lot.expiry,
+		})
+		if err != nil {
+			t.Fatalf("incomeProductRepo.Create() error = %v", err)
+		}
+	}
+
+	_, err = strg.Income().Finish(ctx, &models.IncomePrimaryKey{Id: income.Id})
+	if err != nil {
+		t.Fatalf("incomeRepo.Finish() error = %v", err)
+	}
+
+	lots := func() map[string]int64 {
+		resp, err := strg.StockLot().GetList(ctx, &models.GetListStockLotRequest{
+			Limit: 10,
+			Filters: []criteria.Filter{
+				criteria.Equal("branch_id", item.BranchID),
+				criteria.Equal("barcode", item.Barcode),
+			},
+		})
+		if err != nil {
+			t.Fatalf("stockLotRepo.GetList() error = %v", err)
+		}
+
+		var quantities = map[string]int64{}
+		for _, lot := range resp.StockLots {
+			quantities[lot.LotNumber] = lot.Quantity
+		}
+		return quantities
+	}
+
+	expiring := func() int64 {
+		resp, err := strg.StockLot().Expiring(ctx, &models.ExpiringStockLotRequest{BranchID: item.BranchID, Days: 10})
+		if err != nil {
+			t.Fatalf("stockLotRepo.Expiring() error = %v", err)
+		}
+
+		var quantity int64
+		for _, lot := range resp.StockLots {
+			if lot.Barcode != item.Barcode || lot.LotNumber != "soon" {
+				t.Errorf("stockLotRepo.Expiring() lot = %v %v, want %v soon", lot.Barcode, lot.LotNumber, item.Barcode)
+			}
+			quantity += lot.Quantity
+		}
+		return quantity
+	}
+
+	if got := testStock(t, strg, item.BranchID, item.Barcode); got != 9 {
+		t.Errorf("incomeRepo.Finish() stock = %v, want 9", got)
+	}
+	checkMovements(t, testMovements(t, strg, income.Id), config.StockMovementIncome, 4, 5)
+	if got := lots(); len(got) != 2 || got["late"] != 5 || got["soon"] != 4 {
+		t.Errorf("incomeRepo.Finish() lots = %v, want late 5 and soon 4", got)
+	}
+	if got := expiring(); got != 4 {
+		t.Errorf("stockLotRepo.Expiring() quantity = %v, want 4", got)
+	}
+
+	sale, transactionID := newTestSale(t, strg, item.BranchID)
+	addTestSaleProduct(t, strg, sale.Id, item, 6, 100)
+
+	err = strg.Sale().Finish(ctx, &models.FinishSale{
+		Id:            sale.Id,
+		BranchID:      item.BranchID,
+		TransactionID: transactionID,
+		Payment:       &models.Payment{Cash: 600, TotalAmount: 600},
+	})
+	if err != nil {
+		t.Fatalf("saleRepo.Finish() error = %v", err)
+	}
+
+	if got := testStock(t, strg, item.BranchID, item.Barcode); got != 3 {
+		t.Errorf("saleRepo.Finish() stock = %v, want 3", got)
+	}
+	checkMovements(t, testMovements(t, strg, sale.Id), config.StockMovementSale, -6)
+	if got := lots(); got["soon"] != 0 || got["late"] != 3 {
+		t.Errorf("saleRepo.Finish() lots = %v, want soon 0 and late 3", got)
+	}
+	if got := expiring(); got != 0 {
+		t.Errorf("stockLotRepo.Expiring() quantity after the sale = %v, want 0", got)
+	}
+}
@@ -0,0 +1,124 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"market_system/models"
+	"market_system/pkg/criteria"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type stockMovementRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewStockMovementRepo(db *pgxpool.Pool) *stockMovementRepo {
+	return &stockMovementRepo{
+		db: db,
+	}
+}
+
+var stockMovementSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":            {Column: "id", Type: criteria.UUID},
+		"branch_id":     {Column: "branch_id", Type: criteria.UUID},
+		"barcode":       {Column: "barcode", Type: criteria.Text},
+		"product_name":  {Column: "product_name", Type: criteria.Text},
+		"type":          {Column: "type", Type: criteria.Text},
+		"document_id":   {Column: "document_id", Type: criteria.UUID},
+		"quantity":      {Column: "quantity", Type: criteria.Number},
+		"cost":          {Column: "cost", Type: criteria.Number},
+		"balance_after": {Column: "balance_after", Type: criteria.Number},
+		"created_at":    {Column: "created_at", Type: criteria.Time},
+	},
+	Sort: []criteria.Sort{{Field: "created_at", Desc: true}},
+}
+
+func (r *stockMovementRepo) GetList(ctx context.Context, req *models.GetListStockMovementRequest) (*models.GetListStockMovementResponse, error) {
+	var (
+		resp  models.GetListStockMovementResponse
+		where = stockMovementSchema.Query()
+	)
+
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var query = `
+		SELECT
+			COUNT(*) OVER(),
+			id,
+			branch_id,
+			barcode,
+			product_name,
+			type,
+			document_id,
+			quantity,
+			cost,
+			balance_after,
+			created_at
+		FROM stock_movement
+	`
+
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			ID           sql.NullString
+			BranchID     sql.NullString
+			Barcode      sql.NullString
+			ProductName  sql.NullString
+			Type         sql.NullString
+			DocumentID   sql.NullString
+			Quantity     sql.NullInt64
+			Cost         sql.NullFloat64
+			BalanceAfter sql.NullInt64
+			CreatedAt    sql.NullString
+		)
+
+		err = rows.Scan(
+			&resp.Count,
+			&ID,
+			&BranchID,
+			&Barcode,
+			&ProductName,
+			&Type,
+			&DocumentID,
+			&Quantity,
+			&Cost,
+			&BalanceAfter,
+			&CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.StockMovements = append(resp.StockMovements, &models.StockMovement{
+			Id:           ID.String,
+			BranchID:     BranchID.String,
+			Barcode:      Barcode.String,
+			ProductName:  ProductName.String,
+			Type:         Type.String,
+			DocumentID:   DocumentID.String,
+			Quantity:     Quantity.Int64,
+			Cost:         Cost.Float64,
+			BalanceAfter: BalanceAfter.Int64,
+			CreatedAt:    CreatedAt.String,
+		})
+	}
+
+	return &resp, rows.Err()
+}
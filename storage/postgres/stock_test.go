@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/storage"
+
+	"github.com/google/uuid"
+)
+
+// testStockItem is a product stocked in a branch of its own, made for a test.
+type testStockItem struct {
+	BranchID    string
+	CategoryID  string
+	ProductID   string
+	ProductName string
+	Barcode     string
+	PriceIncome float64
+}
+
+// newTestStockItem creates a branch, a category and a product and puts
+// quantity of the product into the branch stock.
+func newTestStockItem(t *testing.T, strg storage.StorageI, quantity int) testStockItem {
+
+	var ctx = context.Background()
+
+	branch, err := strg.Branch().Create(ctx, &models.CreateBranch{Name: "stock " + uuid.NewString()})
+	if err != nil {
+		t.Fatalf("branchRepo.Create() error = %v", err)
+	}
+
+	category, err := strg.Category().Create(ctx, &models.CreateCategory{Title: "stock " + uuid.NewString()})
+	if err != nil {
+		t.Fatalf("categoryRepo.Create() error = %v", err)
+	}
+
+	product, err := strg.Product().Create(ctx, &models.CreateProduct{
+		Title:      "stock " + uuid.NewString(),
+		CategoryID: category.Id,
+		Barcode:    uuid.NewString(),
+		Price:      100,
+	})
+	if err != nil {
+		t.Fatalf("productRepo.Create() error = %v", err)
+	}
+
+	var item = testStockItem{
+		BranchID:    branch.Id,
+		CategoryID:  category.Id,
+		ProductID:   product.Id,
+		ProductName: product.Title,
+		Barcode:     product.Barcode,
+		PriceIncome: 50,
+	}
+
+	if quantity > 0 {
+		_, err = strg.Remainder().Create(ctx, &models.CreateRemainder{
+			BranchID:    item.BranchID,
+			CategoryID:  item.CategoryID,
+			ProductName: item.ProductName,
+			Barcode:     item.Barcode,
+			PriceIncome: item.PriceIncome,
+			Quantity:    quantity,
+		})
+		if err != nil {
+			t.Fatalf("remainderRepo.Create() error = %v", err)
+		}
+	}
+
+	return item
+}
+
+// testStock returns the quantity of barcode in the branch stock.
+func testStock(t *testing.T, strg storage.StorageI, branchID, barcode string) int {
+
+	resp, err := strg.Remainder().GetList(context.Background(), &models.GetListRemainderRequest{
+		Limit: 100,
+		Filters: []criteria.Filter{
+			criteria.Equal("branch_id", branchID),
+			criteria.Equal("barcode", barcode),
+		},
+	})
+	if err != nil {
+		t.Fatalf("remainderRepo.GetList() error = %v", err)
+	}
+
+	var quantity int
+	for _, remainder := range resp.Remainder {
+		quantity += remainder.Quantity
+	}
+
+	return quantity
+}
+
+// testMovements returns the stock movements a document made, oldest first
+// and, as the movements of one transaction share a time, smallest first.
+func testMovements(t *testing.T, strg storage.StorageI, documentID string) []*models.StockMovement {
+
+	resp, err := strg.StockMovement().GetList(context.Background(), &models.GetListStockMovementRequest{
+		Limit:   100,
+		Filters: []criteria.Filter{criteria.Equal("document_id", documentID)},
+		Sort:    []criteria.Sort{{Field: "created_at"}, {Field: "quantity"}},
+	})
+	if err != nil {
+		t.Fatalf("stockMovementRepo.GetList() error = %v", err)
+	}
+
+	return resp.StockMovements
+}
+
+// checkMovements fails the test unless the movements of a document are the
+// given type and signed quantities, in that order.
+func checkMovements(t *testing.T, got []*models.StockMovement, movementType string, quantities ...int64) {
+
+	if len(got) != len(quantities) {
+		t.Fatalf("stock movements = %d, want %d", len(got), len(quantities))
+	}
+
+	for i, movement := range got {
+		if movement.Type != movementType {
+			t.Errorf("stock movement %d type = %v, want %v", i, movement.Type, movementType)
+		}
+		if movement.Quantity != quantities[i] {
+			t.Errorf("stock movement %d quantity = %v, want %v", i, movement.Quantity, quantities[i])
+		}
+	}
+}
+
+// newTestSale opens a shift on a new sale point of the branch and starts a
+// sale on it. It returns the sale and the shift transaction it is paid into.
+func newTestSale(t *testing.T, strg storage.StorageI, branchID string) (*models.Sale, string) {
+
+	var ctx = context.Background()
+
+	user, err := strg.User().Create(ctx, &models.CreateUser{
+		FirstName:  "cashier",
+		Login:      "cashier " + uuid.NewString(),
+		Password:   uuid.NewString(),
+		Active:     true,
+		ClientType: config.ClientTypes[1],
+	})
+	if err != nil {
+		t.Fatalf("userRepo.Create() error = %v", err)
+	}
+
+	salePoint, err := strg.Sale_Point().Create(ctx, &models.CreateSalePoint{Branch_id: branchID, Name: "sale " + uuid.NewString()})
+	if err != nil {
+		t.Fatalf("salePointRepo.Create() error = %v", err)
+	}
+
+	shift, err := strg.Shift().Create(ctx, &models.CreateShift{
+		BranchID:    branchID,
+		UserID:      user.Id,
+		SalePointID: salePoint.Id,
+		Status:      "Open",
+	})
+	if err != nil {
+		t.Fatalf("shiftRepo.Create() error = %v", err)
+	}
+
+	transaction, err := strg.Transaction().Create(ctx, &models.CreateTransaction{ShiftID: shift.Id})
+	if err != nil {
+		t.Fatalf("transactionRepo.Create() error = %v", err)
+	}
+
+	sale, err := strg.Sale().Create(ctx, &models.CreateSale{
+		SaleID:      uuid.NewString(),
+		BranchID:    branchID,
+		SalePointID: salePoint.Id,
+		ShiftID:     shift.Id,
+		EmployeeID:  user.Id,
+	})
+	if err != nil {
+		t.Fatalf("saleRepo.Create() error = %v", err)
+	}
+
+	return sale, transaction.Id
+}
+
+// addTestSaleProduct puts quantity of the item into the sale at price.
+func addTestSaleProduct(t *testing.T, strg storage.StorageI, saleID string, item testStockItem, quantity int, price float64) {
+
+	_, err := strg.Sale_Product().Create(context.Background(), &models.CreateSaleProduct{
+		SaleID:      saleID,
+		CategoryID:  item.CategoryID,
+		ProductName: item.ProductName,
+		Barcode:     item.Barcode,
+		Quantity:    quantity,
+		Price:       price,
+		TotalAmount: price * float64(quantity),
+	})
+	if err != nil {
+		t.Fatalf("saleProductRepo.Create() error = %v", err)
+	}
+}
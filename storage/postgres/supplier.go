@@ -3,9 +3,9 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	"fmt"
 
 	"market_system/models"
+	"market_system/pkg/criteria"
 	"market_system/pkg/helpers"
 
 	"github.com/google/uuid"
@@ -99,29 +99,34 @@ func (r *supplierRepo) GetByID(ctx context.Context, req *models.SupplierPrimaryK
 	}, nil
 }
 
+var supplierSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":           {Column: "id", Type: criteria.UUID},
+		"name":         {Column: "name", Type: criteria.Text},
+		"phone_number": {Column: "phone_number", Type: criteria.Text},
+		"is_active":    {Column: "is_active", Type: criteria.Bool},
+		"created_at":   {Column: "created_at", Type: criteria.Time},
+		"updated_at":   {Column: "updated_at", Type: criteria.Time},
+	},
+	Search: []string{"name", "phone_number"},
+	Sort:   []criteria.Sort{{Field: "created_at", Desc: true}},
+}
+
 func (r *supplierRepo) GetList(ctx context.Context, req *models.GetListSupplierRequest) (*models.GetListSupplierResponse, error) {
 	var (
-		resp   models.GetListSupplierResponse
-		where  = " WHERE TRUE"
-		offset = " OFFSET 0"
-		limit  = " LIMIT 10"
-		sort   = " ORDER BY created_at DESC"
+		resp  models.GetListSupplierResponse
+		where = supplierSchema.Query()
 	)
 
-	if req.Offset > 0 {
-		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
-	}
-
-	if req.Limit > 0 {
-		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
-	}
-
-	if len(req.Search) > 0 {
-		where += " AND (name ILIKE '%" + req.Search + "%' OR phone_number ILIKE '%" + req.Search + "%')"
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
 	}
+	where.Search(req.Search)
 
-	if len(req.Query) > 0 {
-		where += req.Query
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
 	}
 
 	var query = `
@@ -136,8 +141,8 @@ func (r *supplierRepo) GetList(ctx context.Context, req *models.GetListSupplierR
 		FROM supplier
 	`
 
-	query += where + sort + offset + limit
-	rows, err := r.db.Query(ctx, query)
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
 	if err != nil {
 		return nil, err
 	}
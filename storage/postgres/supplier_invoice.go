@@ -0,0 +1,821 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"math"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/einvoice"
+	"market_system/pkg/helpers"
+	"market_system/storage"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type supplierInvoiceRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewSupplierInvoiceRepo(db *pgxpool.Pool) *supplierInvoiceRepo {
+	return &supplierInvoiceRepo{
+		db: db,
+	}
+}
+
+const supplierInvoiceColumns = `
+	si.id,
+	si.income_id,
+	COALESCE(i.status, ''),
+	si.supplier_id,
+	si.branch_id,
+	si.format,
+	si.file_name,
+	COALESCE(si.number, ''),
+	TO_CHAR(si.invoice_date, 'YYYY-MM-DD'),
+	COALESCE(si.seller_tin, ''),
+	COALESCE(si.seller_name, ''),
+	si.total_amount,
+	l.lines,
+	l.matched,
+	l.with_issues,
+	l.unresolved,
+	si.created_by,
+	TO_CHAR(si.created_at, 'YYYY-MM-DD HH24:MI:SS')
+`
+
+const supplierInvoiceFrom = `
+	FROM supplier_invoice AS si
+	JOIN income AS i ON i.id = si.income_id
+	JOIN LATERAL (
+		SELECT
+			COUNT(*) AS lines,
+			COUNT(*) FILTER (WHERE status = 'matched') AS matched,
+			COUNT(*) FILTER (WHERE status <> 'skipped' AND CARDINALITY(issues) > 0) AS with_issues,
+			COUNT(*) FILTER (WHERE status IN ('unmatched', 'invalid')) AS unresolved
+		FROM supplier_invoice_line
+		WHERE invoice_id = si.id
+	) AS l ON TRUE
+`
+
+func scanSupplierInvoice(row pgx.Row, extra ...interface{}) (*models.SupplierInvoice, error) {
+
+	var (
+		ID           sql.NullString
+		IncomeID     sql.NullString
+		IncomeStatus sql.NullString
+		SupplierID   sql.NullString
+		BranchID     sql.NullString
+		Format       sql.NullString
+		FileName     sql.NullString
+		Number       sql.NullString
+		InvoiceDate  sql.NullString
+		SellerTIN    sql.NullString
+		SellerName   sql.NullString
+		TotalAmount  sql.NullFloat64
+		Lines        sql.NullInt64
+		Matched      sql.NullInt64
+		WithIssues   sql.NullInt64
+		Unresolved   sql.NullInt64
+		CreatedBy    sql.NullString
+		CreatedAt    sql.NullString
+	)
+
+	dest := append(extra,
+		&ID,
+		&IncomeID,
+		&IncomeStatus,
+		&SupplierID,
+		&BranchID,
+		&Format,
+		&FileName,
+		&Number,
+		&InvoiceDate,
+		&SellerTIN,
+		&SellerName,
+		&TotalAmount,
+		&Lines,
+		&Matched,
+		&WithIssues,
+		&Unresolved,
+		&CreatedBy,
+		&CreatedAt,
+	)
+
+	err := row.Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SupplierInvoice{
+		Id:           ID.String,
+		IncomeID:     IncomeID.String,
+		IncomeStatus: IncomeStatus.String,
+		SupplierID:   SupplierID.String,
+		BranchID:     BranchID.String,
+		Format:       Format.String,
+		FileName:     FileName.String,
+		Number:       Number.String,
+		InvoiceDate:  InvoiceDate.String,
+		SellerTIN:    SellerTIN.String,
+		SellerName:   SellerName.String,
+		TotalAmount:  TotalAmount.Float64,
+		Lines:        Lines.Int64,
+		Matched:      Matched.Int64,
+		WithIssues:   WithIssues.Int64,
+		Unresolved:   Unresolved.Int64,
+		CreatedBy:    CreatedBy.String,
+		CreatedAt:    CreatedAt.String,
+	}, nil
+}
+
+const supplierInvoiceLineColumns = `
+	l.line,
+	COALESCE(l.name, ''),
+	COALESCE(l.barcode, ''),
+	COALESCE(l.catalog_code, ''),
+	COALESCE(l.unit, ''),
+	l.quantity,
+	l.price,
+	l.vat_rate,
+	l.amount,
+	l.supplier_code,
+	l.product_id,
+	COALESCE(p.title, ''),
+	COALESCE(l.matched_by, ''),
+	l.status,
+	l.issues,
+	l.income_product_id
+`
+
+func scanSupplierInvoiceLine(row pgx.Row, extra ...interface{}) (*models.SupplierInvoiceLine, error) {
+
+	var (
+		Line            sql.NullInt64
+		Name            sql.NullString
+		Barcode         sql.NullString
+		CatalogCode     sql.NullString
+		Unit            sql.NullString
+		Quantity        sql.NullFloat64
+		Price           sql.NullFloat64
+		VATRate         sql.NullFloat64
+		Amount          sql.NullFloat64
+		SupplierCode    sql.NullString
+		ProductID       sql.NullString
+		ProductName     sql.NullString
+		MatchedBy       sql.NullString
+		Status          sql.NullString
+		Issues          []string
+		IncomeProductID sql.NullString
+	)
+
+	dest := append(extra,
+		&Line,
+		&Name,
+		&Barcode,
+		&CatalogCode,
+		&Unit,
+		&Quantity,
+		&Price,
+		&VATRate,
+		&Amount,
+		&SupplierCode,
+		&ProductID,
+		&ProductName,
+		&MatchedBy,
+		&Status,
+		&Issues,
+		&IncomeProductID,
+	)
+
+	err := row.Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+
+	var line = &models.SupplierInvoiceLine{
+		Line:            Line.Int64,
+		Name:            Name.String,
+		Barcode:         Barcode.String,
+		CatalogCode:     CatalogCode.String,
+		Unit:            Unit.String,
+		Quantity:        Quantity.Float64,
+		Price:           Price.Float64,
+		VATRate:         VATRate.Float64,
+		Amount:          Amount.Float64,
+		SupplierCode:    SupplierCode.String,
+		ProductID:       ProductID.String,
+		ProductName:     ProductName.String,
+		MatchedBy:       MatchedBy.String,
+		Status:          Status.String,
+		Issues:          Issues,
+		IncomeProductID: IncomeProductID.String,
+	}
+	line.IncomePrice = invoiceLineOf(line).IncomePrice()
+
+	return line, nil
+}
+
+// invoiceLineOf returns a stored line as einvoice reads it.
+func invoiceLineOf(line *models.SupplierInvoiceLine) *einvoice.Line {
+	return &einvoice.Line{
+		Line:        int(line.Line),
+		Name:        line.Name,
+		Barcode:     line.Barcode,
+		CatalogCode: line.CatalogCode,
+		Unit:        line.Unit,
+		Quantity:    line.Quantity,
+		Price:       line.Price,
+		VATRate:     line.VATRate,
+		Amount:      line.Amount,
+	}
+}
+
+// invoiceProduct is the catalog product an invoice line is received as.
+type invoiceProduct struct {
+	ID         string
+	Title      string
+	Barcode    string
+	CategoryID string
+}
+
+const invoiceProductColumns = "p.id, p.title, COALESCE(p.barcode, ''), p.category_id"
+
+// queryInvoiceProduct returns the product a query finds, nil when it finds
+// none or, with unique set, more than one.
+func queryInvoiceProduct(ctx context.Context, tx pgx.Tx, unique bool, query string, args ...interface{}) (*invoiceProduct, error) {
+
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []*invoiceProduct
+	for rows.Next() {
+		var product invoiceProduct
+		err = rows.Scan(&product.ID, &product.Title, &product.Barcode, &product.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		products = append(products, &product)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(products) == 0 || (unique && len(products) > 1) {
+		return nil, nil
+	}
+
+	return products[0], nil
+}
+
+// matchInvoiceLine finds the product of an invoice line by its barcode, then
+// by the mapping saved for the supplier, then by its name when exactly one
+// product has it.
+func matchInvoiceLine(ctx context.Context, tx pgx.Tx, supplierID, branchID string, line *einvoice.Line) (*einvoice.Match, *invoiceProduct, error) {
+
+	var (
+		product *invoiceProduct
+		by      string
+		err     error
+	)
+
+	if len(line.Barcode) > 0 {
+		product, err = queryInvoiceProduct(ctx, tx, false,
+			"SELECT "+invoiceProductColumns+" FROM product AS p WHERE p.barcode = $1",
+			line.Barcode,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		by = config.SupplierInvoiceMatchBarcode
+	}
+
+	if product == nil {
+		product, err = queryInvoiceProduct(ctx, tx, false,
+			"SELECT "+invoiceProductColumns+" FROM supplier_item_map AS m JOIN product AS p ON p.id = m.product_id WHERE m.supplier_id = $1 AND m.supplier_code = $2",
+			supplierID,
+			line.SupplierCode(),
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		by = config.SupplierInvoiceMatchMapping
+	}
+
+	if product == nil && len(line.Name) > 0 {
+		product, err = queryInvoiceProduct(ctx, tx, true,
+			"SELECT "+invoiceProductColumns+" FROM product AS p WHERE LOWER(p.title) = LOWER($1) LIMIT 2",
+			line.Name,
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+		by = config.SupplierInvoiceMatchName
+	}
+
+	if product == nil {
+		return nil, nil, nil
+	}
+
+	match, err := invoiceMatch(ctx, tx, branchID, product, by)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return match, product, nil
+}
+
+// invoiceMatch returns the match of a line to product with the price the
+// product was last received at in the branch.
+func invoiceMatch(ctx context.Context, tx pgx.Tx, branchID string, product *invoiceProduct, by string) (*einvoice.Match, error) {
+
+	var match = &einvoice.Match{ProductID: product.ID, Title: product.Title, By: by}
+	if len(product.Barcode) == 0 {
+		return match, nil
+	}
+
+	err := tx.QueryRow(ctx, `
+		SELECT COALESCE(ip.income_price, 0)
+		FROM income_product AS ip
+		JOIN income AS i ON i.id = ip.income_id
+		WHERE i.branch_id = $1 AND i.status = $2 AND ip.barcode = $3
+		ORDER BY i.date_time DESC NULLS LAST, ip.created_at DESC
+		LIMIT 1
+		`,
+		branchID,
+		config.IncomeStatusFinished,
+		product.Barcode,
+	).Scan(&match.LastPrice)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, err
+	}
+
+	return match, nil
+}
+
+// saveInvoiceIncomeProduct writes the income_product a matched line is
+// received as, creating it when incomeProductID is empty, and returns its id.
+func saveInvoiceIncomeProduct(ctx context.Context, tx pgx.Tx, incomeID, incomeProductID string, product *invoiceProduct, line *einvoice.Line) (string, error) {
+
+	var quantity = int64(math.Round(line.Quantity))
+
+	if len(incomeProductID) > 0 {
+		_, err := tx.Exec(ctx,
+			`UPDATE income_product
+				SET
+					category_id = $2,
+					product_name = $3,
+					barcode = $4,
+					quantity = $5,
+					income_price = $6,
+					updated_at = NOW()
+			WHERE id = $1`,
+			incomeProductID,
+			product.CategoryID,
+			product.Title,
+			product.Barcode,
+			quantity,
+			line.IncomePrice(),
+		)
+		return incomeProductID, err
+	}
+
+	incomeProductID = uuid.New().String()
+	_, err := tx.Exec(ctx,
+		`INSERT INTO income_product(
+			id,
+			income_id,
+			category_id,
+			product_name,
+			barcode,
+			quantity,
+			income_price,
+			updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())`,
+		incomeProductID,
+		incomeID,
+		product.CategoryID,
+		product.Title,
+		product.Barcode,
+		quantity,
+		line.IncomePrice(),
+	)
+
+	return incomeProductID, err
+}
+
+// Import reads a supplier invoice into a new draft income of the branch. Lines
+// that match a catalog product become income_product lines at once; the
+// others wait for ResolveLine. A file that can not be read at all is an
+// *einvoice.FileError.
+func (r *supplierInvoiceRepo) Import(ctx context.Context, req *models.ImportSupplierInvoice) (*models.SupplierInvoice, error) {
+
+	invoice, err := einvoice.Parse(req.File, req.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(invoice.Number) == 0 {
+		invoice.Number = req.Number
+	}
+	if len(invoice.Date) == 0 {
+		invoice.Date = req.Date
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var incomeID = uuid.New().String()
+	_, err = tx.Exec(ctx,
+		`INSERT INTO income(
+			id,
+			branch_id,
+			supplier_id,
+			date_time,
+			status,
+			updated_at
+		) VALUES ($1, $2, $3, COALESCE($4::timestamp, NOW()), $5, NOW())`,
+		incomeID,
+		req.BranchID,
+		req.SupplierID,
+		helpers.NewNullString(invoice.Date),
+		config.IncomeStatusDraft,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var invoiceID = uuid.New().String()
+	_, err = tx.Exec(ctx,
+		`INSERT INTO supplier_invoice(
+			id,
+			income_id,
+			supplier_id,
+			branch_id,
+			format,
+			file_name,
+			number,
+			invoice_date,
+			seller_tin,
+			seller_name,
+			created_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8::date, $9, $10, $11)`,
+		invoiceID,
+		incomeID,
+		req.SupplierID,
+		req.BranchID,
+		req.Format,
+		req.FileName,
+		helpers.NewNullString(invoice.Number),
+		helpers.NewNullString(invoice.Date),
+		helpers.NewNullString(invoice.SellerTIN),
+		helpers.NewNullString(invoice.SellerName),
+		helpers.NewNullString(req.CreatedBy),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var total float64
+	for _, line := range invoice.Lines {
+		var (
+			match           *einvoice.Match
+			product         *invoiceProduct
+			status          = config.SupplierInvoiceLineInvalid
+			incomeProductID string
+		)
+
+		if len(line.Errors) == 0 {
+			match, product, err = matchInvoiceLine(ctx, tx, req.SupplierID, req.BranchID, line)
+			if err != nil {
+				return nil, err
+			}
+
+			status = config.SupplierInvoiceLineUnmatched
+			if product != nil {
+				status = config.SupplierInvoiceLineMatched
+				incomeProductID, err = saveInvoiceIncomeProduct(ctx, tx, incomeID, "", product, line)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			total += line.IncomePrice() * line.Quantity
+		}
+
+		var productID, matchedBy string
+		if match != nil {
+			productID, matchedBy = match.ProductID, match.By
+		}
+
+		_, err = tx.Exec(ctx,
+			`INSERT INTO supplier_invoice_line(
+				invoice_id,
+				line,
+				name,
+				barcode,
+				catalog_code,
+				unit,
+				quantity,
+				price,
+				vat_rate,
+				amount,
+				supplier_code,
+				product_id,
+				matched_by,
+				status,
+				issues,
+				income_product_id
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)`,
+			invoiceID,
+			line.Line,
+			line.Name,
+			helpers.NewNullString(line.Barcode),
+			helpers.NewNullString(line.CatalogCode),
+			helpers.NewNullString(line.Unit),
+			line.Quantity,
+			line.Price,
+			line.VATRate,
+			line.Amount,
+			line.SupplierCode(),
+			helpers.NewNullString(productID),
+			helpers.NewNullString(matchedBy),
+			status,
+			einvoice.Issues(line, match, config.SupplierInvoicePriceTolerance),
+			helpers.NewNullString(incomeProductID),
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = tx.Exec(ctx, "UPDATE supplier_invoice SET total_amount = $2 WHERE id = $1", invoiceID, math.Round(total*100)/100)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, &models.SupplierInvoicePrimaryKey{Id: invoiceID})
+}
+
+// GetByID returns an invoice with its lines.
+func (r *supplierInvoiceRepo) GetByID(ctx context.Context, req *models.SupplierInvoicePrimaryKey) (*models.SupplierInvoice, error) {
+
+	var query = "SELECT " + supplierInvoiceColumns + supplierInvoiceFrom + " WHERE si.id = $1"
+
+	invoice, err := scanSupplierInvoice(r.db.QueryRow(ctx, query, req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx,
+		"SELECT "+supplierInvoiceLineColumns+" FROM supplier_invoice_line AS l LEFT JOIN product AS p ON p.id = l.product_id WHERE l.invoice_id = $1 ORDER BY l.line",
+		req.Id,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		line, err := scanSupplierInvoiceLine(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		invoice.InvoiceLines = append(invoice.InvoiceLines, line)
+	}
+
+	return invoice, rows.Err()
+}
+
+var supplierInvoiceSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":            {Column: "si.id", Type: criteria.UUID},
+		"income_id":     {Column: "si.income_id", Type: criteria.UUID},
+		"income_status": {Column: "i.status", Type: criteria.Text},
+		"supplier_id":   {Column: "si.supplier_id", Type: criteria.UUID},
+		"branch_id":     {Column: "si.branch_id", Type: criteria.UUID},
+		"format":        {Column: "si.format", Type: criteria.Text},
+		"file_name":     {Column: "si.file_name", Type: criteria.Text},
+		"number":        {Column: "si.number", Type: criteria.Text},
+		"invoice_date":  {Column: "si.invoice_date", Type: criteria.Time},
+		"seller_tin":    {Column: "si.seller_tin", Type: criteria.Text},
+		"seller_name":   {Column: "si.seller_name", Type: criteria.Text},
+		"total_amount":  {Column: "si.total_amount", Type: criteria.Number},
+		"unresolved":    {Column: "l.unresolved", Type: criteria.Number},
+		"created_by":    {Column: "si.created_by", Type: criteria.UUID},
+		"created_at":    {Column: "si.created_at", Type: criteria.Time},
+	},
+	Sort: []criteria.Sort{{Field: "created_at", Desc: true}},
+}
+
+func (r *supplierInvoiceRepo) GetList(ctx context.Context, req *models.GetListSupplierInvoiceRequest) (*models.GetListSupplierInvoiceResponse, error) {
+	var (
+		resp  models.GetListSupplierInvoiceResponse
+		where = supplierInvoiceSchema.Query()
+	)
+
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var query = "SELECT COUNT(*) OVER(), " + supplierInvoiceColumns + supplierInvoiceFrom
+
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		invoice, err := scanSupplierInvoice(rows, &resp.Count)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.SupplierInvoices = append(resp.SupplierInvoices, invoice)
+	}
+
+	return &resp, rows.Err()
+}
+
+// ResolveLine matches a line of an invoice whose income is not posted yet to
+// a product, or skips it. A matched line is received as an income_product
+// line, a skipped one is not received.
+func (r *supplierInvoiceRepo) ResolveLine(ctx context.Context, req *models.ResolveSupplierInvoiceLine) (*models.SupplierInvoiceLine, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var incomeID, supplierID, branchID, incomeStatus string
+	err = tx.QueryRow(ctx, `
+		SELECT si.income_id, si.supplier_id, si.branch_id, COALESCE(i.status, '')
+		FROM supplier_invoice AS si
+		JOIN income AS i ON i.id = si.income_id
+		WHERE si.id = $1
+		FOR UPDATE OF i
+		`,
+		req.InvoiceID,
+	).Scan(&incomeID, &supplierID, &branchID, &incomeStatus)
+	if err != nil {
+		return nil, err
+	}
+
+	if incomeStatus == config.IncomeStatusFinished {
+		return nil, storage.ErrIncomeFinished
+	}
+
+	var lineQuery = "SELECT " + supplierInvoiceLineColumns + " FROM supplier_invoice_line AS l LEFT JOIN product AS p ON p.id = l.product_id WHERE l.invoice_id = $1 AND l.line = $2"
+
+	stored, err := scanSupplierInvoiceLine(tx.QueryRow(ctx, lineQuery, req.InvoiceID, req.Line))
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Skip {
+		if len(stored.IncomeProductID) > 0 {
+			_, err = tx.Exec(ctx, "DELETE FROM income_product WHERE id = $1", stored.IncomeProductID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		_, err = tx.Exec(ctx,
+			"UPDATE supplier_invoice_line SET status = $3, income_product_id = NULL WHERE invoice_id = $1 AND line = $2",
+			req.InvoiceID,
+			req.Line,
+			config.SupplierInvoiceLineSkipped,
+		)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		var line = invoiceLineOf(stored)
+
+		einvoice.Validate(line)
+		if stored.Status == config.SupplierInvoiceLineInvalid || len(line.Errors) > 0 {
+			return nil, storage.ErrSupplierInvoiceLineInvalid
+		}
+
+		product, err := queryInvoiceProduct(ctx, tx, false,
+			"SELECT "+invoiceProductColumns+" FROM product AS p WHERE p.id = $1",
+			req.ProductID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if product == nil {
+			return nil, pgx.ErrNoRows
+		}
+
+		match, err := invoiceMatch(ctx, tx, branchID, product, config.SupplierInvoiceMatchManual)
+		if err != nil {
+			return nil, err
+		}
+
+		incomeProductID, err := saveInvoiceIncomeProduct(ctx, tx, incomeID, stored.IncomeProductID, product, line)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tx.Exec(ctx,
+			`UPDATE supplier_invoice_line
+				SET
+					product_id = $3,
+					matched_by = $4,
+					status = $5,
+					issues = $6,
+					income_product_id = $7
+			WHERE invoice_id = $1 AND line = $2`,
+			req.InvoiceID,
+			req.Line,
+			product.ID,
+			match.By,
+			config.SupplierInvoiceLineMatched,
+			einvoice.Issues(line, match, config.SupplierInvoicePriceTolerance),
+			incomeProductID,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if req.Remember {
+			_, err = tx.Exec(ctx,
+				`INSERT INTO supplier_item_map(
+					supplier_id,
+					supplier_code,
+					product_id,
+					created_by,
+					updated_at
+				) VALUES ($1, $2, $3, $4, NOW())
+				ON CONFLICT (supplier_id, supplier_code) DO UPDATE
+					SET
+						product_id = EXCLUDED.product_id,
+						updated_at = NOW()`,
+				supplierID,
+				stored.SupplierCode,
+				product.ID,
+				helpers.NewNullString(req.CreatedBy),
+			)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	line, err := scanSupplierInvoiceLine(tx.QueryRow(ctx, lineQuery, req.InvoiceID, req.Line))
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return line, nil
+}
+
+// unresolvedInvoiceLines counts the unmatched and invalid invoice lines of an
+// income, which have to be matched or skipped before it is posted.
+func unresolvedInvoiceLines(ctx context.Context, tx pgx.Tx, incomeID string) (int64, error) {
+
+	var count int64
+	err := tx.QueryRow(ctx, `
+		SELECT COUNT(*)
+		FROM supplier_invoice AS si
+		JOIN supplier_invoice_line AS l ON l.invoice_id = si.id
+		WHERE si.income_id = $1 AND l.status IN ($2, $3)
+		`,
+		incomeID,
+		config.SupplierInvoiceLineUnmatched,
+		config.SupplierInvoiceLineInvalid,
+	).Scan(&count)
+
+	return count, err
+}
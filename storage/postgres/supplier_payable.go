@@ -0,0 +1,432 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/helpers"
+	"market_system/pkg/payable"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type supplierPayableRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewSupplierPayableRepo(db *pgxpool.Pool) *supplierPayableRepo {
+	return &supplierPayableRepo{
+		db: db,
+	}
+}
+
+// postIncomeInvoice raises the payable for a finished income at the total
+// cost of its lines. An income is invoiced once; posting it again, or
+// posting an income that is not finished or has no lines, does nothing.
+func postIncomeInvoice(ctx context.Context, tx pgx.Tx, incomeID string) error {
+
+	var query = `
+		INSERT INTO supplier_transaction(
+			id,
+			supplier_id,
+			branch_id,
+			type,
+			amount,
+			income_id,
+			date_time,
+			updated_at
+		)
+		SELECT
+			$1,
+			i.supplier_id,
+			i.branch_id,
+			$3,
+			SUM(ip.quantity * ip.income_price),
+			i.id,
+			COALESCE(i.date_time, NOW()),
+			NOW()
+		FROM income AS i
+		JOIN income_product AS ip ON ip.income_id = i.id
+		WHERE i.id = $2 AND i.status = $4
+		GROUP BY i.id
+		HAVING SUM(ip.quantity * ip.income_price) > 0
+		ON CONFLICT (income_id) WHERE type = 'invoice' DO NOTHING
+	`
+
+	_, err := tx.Exec(ctx, query,
+		uuid.New().String(),
+		incomeID,
+		config.SupplierInvoice,
+		config.IncomeStatusFinished,
+	)
+
+	return err
+}
+
+// postSupplierReturn credits the supplier with the cost of a posted return.
+// A return is credited once.
+func postSupplierReturn(ctx context.Context, tx pgx.Tx, supplierReturnID string) error {
+
+	var query = `
+		INSERT INTO supplier_transaction(
+			id,
+			supplier_id,
+			branch_id,
+			type,
+			amount,
+			supplier_return_id,
+			date_time,
+			updated_at
+		)
+		SELECT
+			$1,
+			sr.supplier_id,
+			sr.branch_id,
+			$3,
+			SUM(srp.quantity * srp.cost),
+			sr.id,
+			COALESCE(sr.posted_at, NOW()),
+			NOW()
+		FROM supplier_return AS sr
+		JOIN supplier_return_product AS srp ON srp.supplier_return_id = sr.id
+		WHERE sr.id = $2
+		GROUP BY sr.id
+		HAVING SUM(srp.quantity * srp.cost) > 0
+		ON CONFLICT (supplier_return_id) WHERE type = 'return' DO NOTHING
+	`
+
+	_, err := tx.Exec(ctx, query,
+		uuid.New().String(),
+		supplierReturnID,
+		config.SupplierReturn,
+	)
+
+	return err
+}
+
+// signedAmount is the ledger amount as it moves the balance owed.
+const signedAmount = "CASE WHEN type = 'invoice' THEN amount ELSE -amount END"
+
+func (r *supplierPayableRepo) CreatePayment(ctx context.Context, req *models.CreateSupplierPayment) (*models.SupplierTransaction, error) {
+
+	var (
+		id    = uuid.New().String()
+		query = `
+			INSERT INTO supplier_transaction(
+				id,
+				supplier_id,
+				branch_id,
+				type,
+				amount,
+				method,
+				date_time,
+				comment,
+				created_by,
+				updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, COALESCE($7::timestamp, NOW()), $8, $9, NOW())`
+	)
+
+	_, err := r.db.Exec(ctx,
+		query,
+		id,
+		req.SupplierID,
+		helpers.NewNullString(req.BranchID),
+		config.SupplierPayment,
+		req.Amount,
+		req.Method,
+		helpers.NewNullString(req.DateTime),
+		helpers.NewNullString(req.Comment),
+		helpers.NewNullString(req.CreatedBy),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		transaction = models.SupplierTransaction{Id: id}
+		BranchID    sql.NullString
+		Method      sql.NullString
+		Comment     sql.NullString
+		CreatedBy   sql.NullString
+	)
+
+	err = r.db.QueryRow(ctx, `
+		SELECT
+			supplier_id,
+			branch_id,
+			type,
+			amount,
+			method,
+			date_time::text,
+			comment,
+			created_by,
+			created_at::text,
+			(SELECT COALESCE(SUM(`+signedAmount+`), 0) FROM supplier_transaction WHERE supplier_id = st.supplier_id)
+		FROM supplier_transaction AS st
+		WHERE id = $1
+		`, id,
+	).Scan(
+		&transaction.SupplierID,
+		&BranchID,
+		&transaction.Type,
+		&transaction.Amount,
+		&Method,
+		&transaction.DateTime,
+		&Comment,
+		&CreatedBy,
+		&transaction.CreatedAt,
+		&transaction.Balance,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	transaction.BranchID = BranchID.String
+	transaction.Method = Method.String
+	transaction.Comment = Comment.String
+	transaction.CreatedBy = CreatedBy.String
+
+	return &transaction, nil
+}
+
+// Statement lists the supplier ledger for [from_date, to_date) with a
+// running balance. An empty from_date starts at the first entry, an empty
+// to_date runs up to now.
+func (r *supplierPayableRepo) Statement(ctx context.Context, req *models.SupplierStatementRequest) (*models.SupplierStatement, error) {
+
+	var (
+		resp = models.SupplierStatement{
+			SupplierID:   req.SupplierID,
+			FromDate:     req.FromDate,
+			ToDate:       req.ToDate,
+			Transactions: []*models.SupplierTransaction{},
+		}
+		to time.Time
+	)
+
+	err := r.db.QueryRow(ctx, `
+		SELECT
+			s.name,
+			COALESCE($3::timestamp, NOW()),
+			COALESCE((
+				SELECT SUM(`+signedAmount+`)
+				FROM supplier_transaction
+				WHERE supplier_id = s.id AND $2::timestamp IS NOT NULL AND date_time < $2
+			), 0)
+		FROM supplier AS s
+		WHERE s.id = $1
+		`,
+		req.SupplierID,
+		helpers.NewNullString(req.FromDate),
+		helpers.NewNullString(req.ToDate),
+	).Scan(&resp.SupplierName, &to, &resp.OpeningBalance)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, `
+		SELECT
+			id,
+			supplier_id,
+			branch_id,
+			type,
+			amount,
+			method,
+			income_id,
+			supplier_return_id,
+			date_time::text,
+			comment,
+			created_by,
+			created_at::text
+		FROM supplier_transaction
+		WHERE supplier_id = $1
+			AND ($2::timestamp IS NULL OR date_time >= $2)
+			AND date_time < $3
+		ORDER BY date_time, created_at
+		`,
+		req.SupplierID,
+		helpers.NewNullString(req.FromDate),
+		to,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var balance = resp.OpeningBalance
+	for rows.Next() {
+		var (
+			transaction models.SupplierTransaction
+			BranchID    sql.NullString
+			Method      sql.NullString
+			IncomeID    sql.NullString
+			ReturnID    sql.NullString
+			Comment     sql.NullString
+			CreatedBy   sql.NullString
+		)
+
+		err = rows.Scan(
+			&transaction.Id,
+			&transaction.SupplierID,
+			&BranchID,
+			&transaction.Type,
+			&transaction.Amount,
+			&Method,
+			&IncomeID,
+			&ReturnID,
+			&transaction.DateTime,
+			&Comment,
+			&CreatedBy,
+			&transaction.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		switch transaction.Type {
+		case config.SupplierInvoice:
+			resp.Invoiced += transaction.Amount
+			balance += transaction.Amount
+		case config.SupplierPayment:
+			resp.Paid += transaction.Amount
+			balance -= transaction.Amount
+		case config.SupplierReturn:
+			resp.Returned += transaction.Amount
+			balance -= transaction.Amount
+		}
+
+		transaction.BranchID = BranchID.String
+		transaction.Method = Method.String
+		transaction.IncomeID = IncomeID.String
+		transaction.SupplierReturnID = ReturnID.String
+		transaction.Comment = Comment.String
+		transaction.CreatedBy = CreatedBy.String
+		transaction.Balance = balance
+
+		resp.Transactions = append(resp.Transactions, &transaction)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	resp.ClosingBalance = balance
+
+	aging, err := r.aging(ctx, to, req.SupplierID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Aging = &models.SupplierAging{SupplierID: req.SupplierID, SupplierName: resp.SupplierName}
+	if len(aging) > 0 {
+		resp.Aging = aging[0]
+	}
+
+	return &resp, nil
+}
+
+// Aging buckets what is owed to every supplier with ledger entries, as of
+// req.At (now when empty), by the age of the invoices still open once
+// payments and returns are settled against the oldest invoices.
+func (r *supplierPayableRepo) Aging(ctx context.Context, req *models.SupplierAgingRequest) (*models.SupplierAgingResponse, error) {
+
+	var at = time.Now()
+	if req.At != "" {
+		err := r.db.QueryRow(ctx, "SELECT $1::timestamp", req.At).Scan(&at)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	suppliers, err := r.aging(ctx, at, req.SupplierID)
+	if err != nil {
+		return nil, err
+	}
+
+	var total = models.SupplierAging{}
+	for _, supplier := range suppliers {
+		total.Current += supplier.Current
+		total.Days31To60 += supplier.Days31To60
+		total.Days61To90 += supplier.Days61To90
+		total.Over90 += supplier.Over90
+		total.Advance += supplier.Advance
+		total.Total += supplier.Total
+	}
+
+	return &models.SupplierAgingResponse{
+		Suppliers: suppliers,
+		Total:     &total,
+	}, nil
+}
+
+func (r *supplierPayableRepo) aging(ctx context.Context, at time.Time, supplierID string) ([]*models.SupplierAging, error) {
+
+	rows, err := r.db.Query(ctx, `
+		SELECT
+			s.id,
+			s.name,
+			st.type,
+			st.date_time,
+			st.amount
+		FROM supplier_transaction AS st
+		JOIN supplier AS s ON s.id = st.supplier_id
+		WHERE st.date_time < $1
+			AND ($2::uuid IS NULL OR st.supplier_id = $2)
+		ORDER BY s.name, s.id
+		`,
+		at,
+		helpers.NewNullString(supplierID),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var (
+		suppliers []*models.SupplierAging
+		invoices  = map[string][]payable.Invoice{}
+		credits   = map[string]float64{}
+	)
+
+	for rows.Next() {
+		var (
+			id, name, kind string
+			date           time.Time
+			amount         float64
+		)
+
+		err = rows.Scan(&id, &name, &kind, &date, &amount)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(suppliers) == 0 || suppliers[len(suppliers)-1].SupplierID != id {
+			suppliers = append(suppliers, &models.SupplierAging{SupplierID: id, SupplierName: name})
+		}
+
+		if kind == config.SupplierInvoice {
+			invoices[id] = append(invoices[id], payable.Invoice{Date: date, Amount: amount})
+		} else {
+			credits[id] += amount
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, supplier := range suppliers {
+		aging := payable.Age(invoices[supplier.SupplierID], credits[supplier.SupplierID], at)
+
+		supplier.Current = aging.Current
+		supplier.Days31To60 = aging.Days31To60
+		supplier.Days61To90 = aging.Days61To90
+		supplier.Over90 = aging.Over90
+		supplier.Advance = aging.Advance
+		supplier.Total = aging.Total
+	}
+
+	return suppliers, nil
+}
@@ -0,0 +1,490 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+	"market_system/storage"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type supplierReturnRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewSupplierReturnRepo(db *pgxpool.Pool) *supplierReturnRepo {
+	return &supplierReturnRepo{
+		db: db,
+	}
+}
+
+const supplierReturnColumns = `
+	sr.id,
+	sr.supplier_id,
+	sr.branch_id,
+	sr.income_id,
+	sr.status,
+	sr.comment,
+	sr.created_by,
+	sr.posted_at,
+	COALESCE((SELECT SUM(quantity * cost) FROM supplier_return_product WHERE supplier_return_id = sr.id), 0),
+	sr.created_at,
+	sr.updated_at
+`
+
+func scanSupplierReturn(row pgx.Row, extra ...interface{}) (*models.SupplierReturn, error) {
+
+	var (
+		ID         sql.NullString
+		SupplierID sql.NullString
+		BranchID   sql.NullString
+		IncomeID   sql.NullString
+		Status     sql.NullString
+		Comment    sql.NullString
+		CreatedBy  sql.NullString
+		PostedAt   sql.NullString
+		TotalCost  sql.NullFloat64
+		CreatedAt  sql.NullString
+		UpdatedAt  sql.NullString
+	)
+
+	dest := append(extra,
+		&ID,
+		&SupplierID,
+		&BranchID,
+		&IncomeID,
+		&Status,
+		&Comment,
+		&CreatedBy,
+		&PostedAt,
+		&TotalCost,
+		&CreatedAt,
+		&UpdatedAt,
+	)
+
+	err := row.Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.SupplierReturn{
+		Id:         ID.String,
+		SupplierID: SupplierID.String,
+		BranchID:   BranchID.String,
+		IncomeID:   IncomeID.String,
+		Status:     Status.String,
+		Comment:    Comment.String,
+		CreatedBy:  CreatedBy.String,
+		PostedAt:   PostedAt.String,
+		TotalCost:  TotalCost.Float64,
+		CreatedAt:  CreatedAt.String,
+		UpdatedAt:  UpdatedAt.String,
+	}, nil
+}
+
+func (r *supplierReturnRepo) Create(ctx context.Context, req *models.CreateSupplierReturn) (*models.SupplierReturn, error) {
+
+	var (
+		supplierReturnID = uuid.New().String()
+		query            = `
+			INSERT INTO supplier_return(
+				id,
+				supplier_id,
+				branch_id,
+				income_id,
+				status,
+				comment,
+				created_by,
+				updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())`
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx,
+		query,
+		supplierReturnID,
+		req.SupplierID,
+		req.BranchID,
+		helpers.NewNullString(req.IncomeID),
+		config.SupplierReturnDraft,
+		helpers.NewNullString(req.Comment),
+		helpers.NewNullString(req.CreatedBy),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.insertProducts(ctx, tx, supplierReturnID, req.Products)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, &models.SupplierReturnPrimaryKey{Id: supplierReturnID})
+}
+
+// insertProducts stores returned lines with the name and category the branch
+// stocks the barcode under. Without an explicit cost the line is valued at
+// the price on the linked income, falling back to the branch income price.
+func (r *supplierReturnRepo) insertProducts(ctx context.Context, tx pgx.Tx, supplierReturnID string, products []*models.CreateSupplierReturnProduct) error {
+
+	var query = `
+		INSERT INTO supplier_return_product(
+			id,
+			supplier_return_id,
+			category_id,
+			product_name,
+			barcode,
+			quantity,
+			cost,
+			updated_at
+		)
+		SELECT
+			$1,
+			sr.id,
+			rm.category_id,
+			rm.product_name,
+			rm.barcode,
+			$4,
+			COALESCE($5::numeric, (
+				SELECT ip.income_price
+				FROM income_product AS ip
+				WHERE ip.income_id = sr.income_id AND ip.barcode = rm.barcode
+				ORDER BY ip.created_at
+				LIMIT 1
+			), rm.price_income, 0),
+			NOW()
+		FROM supplier_return AS sr
+		JOIN remainder AS rm ON rm.branch_id = sr.branch_id AND rm.barcode = $3
+		WHERE sr.id = $2
+		ORDER BY rm.created_at
+		LIMIT 1
+	`
+
+	for _, product := range products {
+		result, err := tx.Exec(ctx,
+			query,
+			uuid.New().String(),
+			supplierReturnID,
+			product.Barcode,
+			product.Quantity,
+			sql.NullFloat64{Float64: product.Cost, Valid: product.Cost > 0},
+		)
+		if err != nil {
+			return err
+		}
+
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("barcode %s is not stocked at the branch: %w", product.Barcode, pgx.ErrNoRows)
+		}
+	}
+
+	return nil
+}
+
+func (r *supplierReturnRepo) GetByID(ctx context.Context, req *models.SupplierReturnPrimaryKey) (*models.SupplierReturn, error) {
+
+	var query = "SELECT " + supplierReturnColumns + " FROM supplier_return AS sr WHERE sr.id = $1"
+
+	supplierReturn, err := scanSupplierReturn(r.db.QueryRow(ctx, query, req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	supplierReturn.Products, err = r.getProducts(ctx, supplierReturn.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return supplierReturn, nil
+}
+
+func (r *supplierReturnRepo) getProducts(ctx context.Context, supplierReturnID string) ([]*models.SupplierReturnProduct, error) {
+
+	var (
+		products []*models.SupplierReturnProduct
+		query    = `
+			SELECT
+				id,
+				supplier_return_id,
+				category_id,
+				product_name,
+				barcode,
+				quantity,
+				cost,
+				created_at,
+				updated_at
+			FROM supplier_return_product
+			WHERE supplier_return_id = $1
+			ORDER BY created_at
+		`
+	)
+
+	rows, err := r.db.Query(ctx, query, supplierReturnID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			ID               sql.NullString
+			SupplierReturnID sql.NullString
+			CategoryID       sql.NullString
+			ProductName      sql.NullString
+			Barcode          sql.NullString
+			Quantity         sql.NullInt64
+			Cost             sql.NullFloat64
+			CreatedAt        sql.NullString
+			UpdatedAt        sql.NullString
+		)
+
+		err = rows.Scan(
+			&ID,
+			&SupplierReturnID,
+			&CategoryID,
+			&ProductName,
+			&Barcode,
+			&Quantity,
+			&Cost,
+			&CreatedAt,
+			&UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		products = append(products, &models.SupplierReturnProduct{
+			Id:               ID.String,
+			SupplierReturnID: SupplierReturnID.String,
+			CategoryID:       CategoryID.String,
+			ProductName:      ProductName.String,
+			Barcode:          Barcode.String,
+			Quantity:         Quantity.Int64,
+			Cost:             Cost.Float64,
+			TotalCost:        float64(Quantity.Int64) * Cost.Float64,
+			CreatedAt:        CreatedAt.String,
+			UpdatedAt:        UpdatedAt.String,
+		})
+	}
+
+	return products, rows.Err()
+}
+
+var supplierReturnSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":          {Column: "sr.id", Type: criteria.UUID},
+		"supplier_id": {Column: "sr.supplier_id", Type: criteria.UUID},
+		"branch_id":   {Column: "sr.branch_id", Type: criteria.UUID},
+		"income_id":   {Column: "sr.income_id", Type: criteria.UUID},
+		"status":      {Column: "sr.status", Type: criteria.Text},
+		"created_by":  {Column: "sr.created_by", Type: criteria.UUID},
+		"posted_at":   {Column: "sr.posted_at", Type: criteria.Time},
+		"created_at":  {Column: "sr.created_at", Type: criteria.Time},
+		"updated_at":  {Column: "sr.updated_at", Type: criteria.Time},
+	},
+	Sort: []criteria.Sort{{Field: "created_at", Desc: true}},
+}
+
+func (r *supplierReturnRepo) GetList(ctx context.Context, req *models.GetListSupplierReturnRequest) (*models.GetListSupplierReturnResponse, error) {
+	var (
+		resp  models.GetListSupplierReturnResponse
+		where = supplierReturnSchema.Query()
+	)
+
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var query = "SELECT COUNT(*) OVER(), " + supplierReturnColumns + " FROM supplier_return AS sr"
+
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		supplierReturn, err := scanSupplierReturn(rows, &resp.Count)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.SupplierReturns = append(resp.SupplierReturns, supplierReturn)
+	}
+
+	return &resp, rows.Err()
+}
+
+// Update rewrites a draft return. Posted and canceled returns are left
+// untouched and 0 rows affected is returned.
+func (r *supplierReturnRepo) Update(ctx context.Context, req *models.UpdateSupplierReturn) (int64, error) {
+
+	query := `
+		UPDATE supplier_return
+			SET
+				income_id = $2,
+				comment = $3,
+				updated_at = NOW()
+		WHERE id = $1 AND status = $4
+	`
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	rowsAffected, err := tx.Exec(ctx,
+		query,
+		req.Id,
+		helpers.NewNullString(req.IncomeID),
+		helpers.NewNullString(req.Comment),
+		config.SupplierReturnDraft,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	if rowsAffected.RowsAffected() == 0 {
+		return 0, nil
+	}
+
+	_, err = tx.Exec(ctx, "DELETE FROM supplier_return_product WHERE supplier_return_id = $1", req.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.insertProducts(ctx, tx, req.Id, req.Products)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
+
+func (r *supplierReturnRepo) Delete(ctx context.Context, req *models.SupplierReturnPrimaryKey) error {
+	_, err := r.db.Exec(ctx, "DELETE FROM supplier_return WHERE id = $1 AND status = $2", req.Id, config.SupplierReturnDraft)
+	return err
+}
+
+// Post sends a draft return out in one transaction: every line leaves the
+// branch stock through the same path as sales, the supplier is credited with
+// the return cost and the document becomes posted. A line the branch does not
+// have enough of fails the whole post with storage.ErrNotEnoughStock.
+func (r *supplierReturnRepo) Post(ctx context.Context, req *models.SupplierReturnPrimaryKey) (int64, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var branchID string
+	err = tx.QueryRow(ctx,
+		"SELECT branch_id FROM supplier_return WHERE id = $1 AND status = $2 FOR UPDATE",
+		req.Id,
+		config.SupplierReturnDraft,
+	).Scan(&branchID)
+	if err == pgx.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := tx.Query(ctx, "SELECT barcode, quantity FROM supplier_return_product WHERE supplier_return_id = $1", req.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	var lines []stockLine
+	for rows.Next() {
+		var line = stockLine{BranchID: branchID, Type: config.StockMovementSupplierReturn, DocumentID: req.Id}
+		err = rows.Scan(&line.Barcode, &line.Quantity)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		lines = append(lines, line)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, line := range lines {
+		left, err := removeStock(ctx, tx, line)
+		if err == pgx.ErrNoRows || (err == nil && left < 0) {
+			return 0, fmt.Errorf("barcode %s: %w", line.Barcode, storage.ErrNotEnoughStock)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	rowsAffected, err := tx.Exec(ctx,
+		"UPDATE supplier_return SET status = $2, posted_at = NOW(), updated_at = NOW() WHERE id = $1",
+		req.Id,
+		config.SupplierReturnPosted,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	err = postSupplierReturn(ctx, tx, req.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
+
+func (r *supplierReturnRepo) Cancel(ctx context.Context, req *models.SupplierReturnPrimaryKey) (int64, error) {
+
+	rowsAffected, err := r.db.Exec(ctx,
+		"UPDATE supplier_return SET status = $2, updated_at = NOW() WHERE id = $1 AND status = $3",
+		req.Id,
+		config.SupplierReturnCanceled,
+		config.SupplierReturnDraft,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
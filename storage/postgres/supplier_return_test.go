@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/storage"
+
+	"github.com/google/uuid"
+)
+
+func Test_supplierReturnRepo_Post(t *testing.T) {
+
+	cfg := config.Load()
+
+	strg, err := NewConnectionPostgres(&cfg)
+	if err != nil {
+		t.Fatalf("failed to connect to PostgreSQL: %v", err)
+	}
+
+	var (
+		ctx  = context.Background()
+		item = newTestStockItem(t, strg, 10)
+	)
+
+	supplier, err := strg.Supplier().Create(ctx, &models.CreateSupplier{Name: "return " + uuid.NewString(), IsActive: true})
+	if err != nil {
+		t.Fatalf("supplierRepo.Create() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		quantity int64
+		wantErr  error
+		stock    int
+		returned float64
+	}{
+		{
+			name:     "more than the branch has",
+			quantity: 15,
+			wantErr:  storage.ErrNotEnoughStock,
+			stock:    10,
+		},
+		{
+			name:     "part of the stock",
+			quantity: 3,
+			stock:    7,
+			returned: 120,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			supplierReturn, err := strg.SupplierReturn().Create(ctx, &models.CreateSupplierReturn{
+				SupplierID: supplier.Id,
+				BranchID:   item.BranchID,
+				Products:   []*models.CreateSupplierReturnProduct{{Barcode: item.Barcode, Quantity: tt.quantity, Cost: 40}},
+			})
+			if err != nil {
+				t.Fatalf("supplierReturnRepo.Create() error = %v", err)
+			}
+
+			var key = &models.SupplierReturnPrimaryKey{Id: supplierReturn.Id}
+
+			rowsAffected, err := strg.SupplierReturn().Post(ctx, key)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("supplierReturnRepo.Post() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got := testStock(t, strg, item.BranchID, item.Barcode); got != tt.stock {
+				t.Errorf("supplierReturnRepo.Post() stock = %v, want %v", got, tt.stock)
+			}
+
+			statement, err := strg.SupplierPayable().Statement(ctx, &models.SupplierStatementRequest{SupplierID: supplier.Id})
+			if err != nil {
+				t.Fatalf("supplierPayableRepo.Statement() error = %v", err)
+			}
+			if statement.Returned != tt.returned {
+				t.Errorf("supplierReturnRepo.Post() returned to supplier = %v, want %v", statement.Returned, tt.returned)
+			}
+
+			var movements = testMovements(t, strg, supplierReturn.Id)
+
+			if tt.wantErr != nil {
+				checkMovements(t, movements, config.StockMovementSupplierReturn)
+
+				got, err := strg.SupplierReturn().GetByID(ctx, key)
+				if err != nil {
+					t.Fatalf("supplierReturnRepo.GetByID() error = %v", err)
+				}
+				if got.Status != config.SupplierReturnDraft {
+					t.Errorf("supplierReturnRepo.Post() status = %v, want %v", got.Status, config.SupplierReturnDraft)
+				}
+				return
+			}
+
+			if rowsAffected != 1 {
+				t.Errorf("supplierReturnRepo.Post() rows affected = %d, want 1", rowsAffected)
+			}
+
+			checkMovements(t, movements, config.StockMovementSupplierReturn, -tt.quantity)
+			if movements[0].BalanceAfter != int64(tt.stock) {
+				t.Errorf("stock movement balance after = %v, want %v", movements[0].BalanceAfter, tt.stock)
+			}
+
+			rowsAffected, err = strg.SupplierReturn().Post(ctx, key)
+			if err != nil || rowsAffected != 0 {
+				t.Errorf("supplierReturnRepo.Post() again = %d, %v, want 0, nil", rowsAffected, err)
+			}
+			if got := testStock(t, strg, item.BranchID, item.Barcode); got != tt.stock {
+				t.Errorf("supplierReturnRepo.Post() again stock = %v, want %v", got, tt.stock)
+			}
+			checkMovements(t, testMovements(t, strg, supplierReturn.Id), config.StockMovementSupplierReturn, -tt.quantity)
+		})
+	}
+}
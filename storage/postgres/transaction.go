@@ -3,9 +3,9 @@ package postgres
 import (
 	"context"
 	"database/sql"
-	"fmt"
 
 	"market_system/models"
+	"market_system/pkg/criteria"
 	"market_system/pkg/helpers"
 
 	"github.com/google/uuid"
@@ -36,10 +36,13 @@ func (r *transactionRepo) Create(ctx context.Context, req *models.CreateTransact
 				click,
 				humo,
 				apelsin,
+				points,
+				gift_card,
+				gift_card_sold,
 				total_amount,
 				created_at,
 				updated_at
-			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())`
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, NOW(), NOW())`
 	)
 
 	_, err := r.db.Exec(ctx,
@@ -52,6 +55,9 @@ func (r *transactionRepo) Create(ctx context.Context, req *models.CreateTransact
 		req.Click,
 		req.Humo,
 		req.Apelsin,
+		req.Points,
+		req.GiftCard,
+		req.GiftCardSold,
 		req.TotalAmount,
 	)
 
@@ -75,6 +81,9 @@ func (r *transactionRepo) GetByID(ctx context.Context, req *models.TransactionPr
 				click,
 				humo,
 				apelsin,
+				points,
+				gift_card,
+				gift_card_sold,
 				total_amount,
 				created_at,
 				updated_at
@@ -84,17 +93,20 @@ func (r *transactionRepo) GetByID(ctx context.Context, req *models.TransactionPr
 	)
 
 	var (
-		ID          sql.NullString
-		ShiftID     sql.NullString
-		Cash        sql.NullFloat64
-		Uzcard      sql.NullFloat64
-		Payme       sql.NullFloat64
-		Click       sql.NullFloat64
-		Humo        sql.NullFloat64
-		Apelsin     sql.NullFloat64
-		TotalAmount sql.NullFloat64
-		CreatedAt   sql.NullString
-		UpdatedAt   sql.NullString
+		ID           sql.NullString
+		ShiftID      sql.NullString
+		Cash         sql.NullFloat64
+		Uzcard       sql.NullFloat64
+		Payme        sql.NullFloat64
+		Click        sql.NullFloat64
+		Humo         sql.NullFloat64
+		Apelsin      sql.NullFloat64
+		Points       sql.NullFloat64
+		GiftCard     sql.NullFloat64
+		GiftCardSold sql.NullFloat64
+		TotalAmount  sql.NullFloat64
+		CreatedAt    sql.NullString
+		UpdatedAt    sql.NullString
 	)
 
 	err := r.db.QueryRow(ctx, query, req.Id).Scan(
@@ -106,6 +118,9 @@ func (r *transactionRepo) GetByID(ctx context.Context, req *models.TransactionPr
 		&Click,
 		&Humo,
 		&Apelsin,
+		&Points,
+		&GiftCard,
+		&GiftCardSold,
 		&TotalAmount,
 		&CreatedAt,
 		&UpdatedAt,
@@ -116,48 +131,64 @@ func (r *transactionRepo) GetByID(ctx context.Context, req *models.TransactionPr
 	}
 
 	return &models.Transaction{
-		Id:          ID.String,
-		ShiftID:     ShiftID.String,
-		Cash:        Cash.Float64,
-		Uzcard:      Uzcard.Float64,
-		Payme:       Payme.Float64,
-		Click:       Click.Float64,
-		Humo:        Humo.Float64,
-		Apelsin:     Apelsin.Float64,
-		TotalAmount: TotalAmount.Float64,
-		CreatedAt:   CreatedAt.String,
-		UpdatedAt:   UpdatedAt.String,
+		Id:           ID.String,
+		ShiftID:      ShiftID.String,
+		Cash:         Cash.Float64,
+		Uzcard:       Uzcard.Float64,
+		Payme:        Payme.Float64,
+		Click:        Click.Float64,
+		Humo:         Humo.Float64,
+		Apelsin:      Apelsin.Float64,
+		Points:       Points.Float64,
+		GiftCard:     GiftCard.Float64,
+		GiftCardSold: GiftCardSold.Float64,
+		TotalAmount:  TotalAmount.Float64,
+		CreatedAt:    CreatedAt.String,
+		UpdatedAt:    UpdatedAt.String,
 	}, nil
 }
 
+var transactionSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":             {Column: "id", Type: criteria.UUID},
+		"shift_id":       {Column: "shift_id", Type: criteria.UUID},
+		"cash":           {Column: "cash", Type: criteria.Number},
+		"uzcard":         {Column: "uzcard", Type: criteria.Number},
+		"payme":          {Column: "payme", Type: criteria.Number},
+		"click":          {Column: "click", Type: criteria.Number},
+		"humo":           {Column: "humo", Type: criteria.Number},
+		"apelsin":        {Column: "apelsin", Type: criteria.Number},
+		"points":         {Column: "points", Type: criteria.Number},
+		"gift_card":      {Column: "gift_card", Type: criteria.Number},
+		"gift_card_sold": {Column: "gift_card_sold", Type: criteria.Number},
+		"total_amount":   {Column: "total_amount", Type: criteria.Number},
+		"created_at":     {Column: "created_at", Type: criteria.Time},
+		"updated_at":     {Column: "updated_at", Type: criteria.Time},
+	},
+	Search: []string{"shift_id"},
+	Sort:   []criteria.Sort{{Field: "created_at", Desc: true}},
+	Keyset: "created_at",
+}
+
 func (r *transactionRepo) GetList(ctx context.Context, req *models.GetListTransactonRequest) (*models.GetListTransactionResponse, error) {
 	var (
-		resp   models.GetListTransactionResponse
-		where  = " WHERE TRUE"
-		offset = " OFFSET 0"
-		limit  = " LIMIT 10"
-		sort   = " ORDER BY created_at DESC"
+		resp  models.GetListTransactionResponse
+		where = transactionSchema.Query()
 	)
 
-	if req.Offset > 0 {
-		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
-	}
-
-	if req.Limit > 0 {
-		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
-	}
-
-	if len(req.Search) > 0 {
-		where += " AND (shift_id ILIKE '%" + req.Search + "%')"
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
 	}
+	where.Search(req.Search)
 
-	if len(req.Query) > 0 {
-		where += req.Query
+	sort, err := where.Cursor(req.Sort, req.Cursor)
+	if err != nil {
+		return nil, err
 	}
 
 	var query = `
 		SELECT
-			COUNT(*) OVER(),
 			id,
 			shift_id,
 			cash,
@@ -166,35 +197,41 @@ func (r *transactionRepo) GetList(ctx context.Context, req *models.GetListTransa
 			click,
 			humo,
 			apelsin,
+			points,
+			gift_card,
+			gift_card_sold,
 			total_amount,
 			created_at,
 			updated_at
 		FROM transaction
 	`
 
-	query += where + sort + offset + limit
-	rows, err := r.db.Query(ctx, query)
+	query += where.SQL() + sort + where.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var (
-			Id          sql.NullString
-			shiftID     sql.NullString
-			cash        sql.NullFloat64
-			uzcard      sql.NullFloat64
-			payme       sql.NullFloat64
-			click       sql.NullFloat64
-			humo        sql.NullFloat64
-			apelsin     sql.NullFloat64
-			totalAmount sql.NullFloat64
-			createdAt   sql.NullString
-			updatedAt   sql.NullString
+			Id           sql.NullString
+			shiftID      sql.NullString
+			cash         sql.NullFloat64
+			uzcard       sql.NullFloat64
+			payme        sql.NullFloat64
+			click        sql.NullFloat64
+			humo         sql.NullFloat64
+			apelsin      sql.NullFloat64
+			points       sql.NullFloat64
+			giftCard     sql.NullFloat64
+			giftCardSold sql.NullFloat64
+			totalAmount  sql.NullFloat64
+			createdAt    sql.NullString
+			updatedAt    sql.NullString
 		)
 
 		err = rows.Scan(
-			&resp.Count,
 			&Id,
 			&shiftID,
 			&cash,
@@ -203,6 +240,9 @@ func (r *transactionRepo) GetList(ctx context.Context, req *models.GetListTransa
 			&click,
 			&humo,
 			&apelsin,
+			&points,
+			&giftCard,
+			&giftCardSold,
 			&totalAmount,
 			&createdAt,
 			&updatedAt,
@@ -212,19 +252,40 @@ func (r *transactionRepo) GetList(ctx context.Context, req *models.GetListTransa
 		}
 
 		resp.Transactions = append(resp.Transactions, &models.Transaction{
-			Id:          Id.String,
-			ShiftID:     shiftID.String,
-			Cash:        cash.Float64,
-			Uzcard:      uzcard.Float64,
-			Payme:       payme.Float64,
-			Click:       click.Float64,
-			Humo:        humo.Float64,
-			Apelsin:     apelsin.Float64,
-			TotalAmount: totalAmount.Float64,
-			CreatedAt:   createdAt.String,
-			UpdatedAt:   updatedAt.String,
+			Id:           Id.String,
+			ShiftID:      shiftID.String,
+			Cash:         cash.Float64,
+			Uzcard:       uzcard.Float64,
+			Payme:        payme.Float64,
+			Click:        click.Float64,
+			Humo:         humo.Float64,
+			Apelsin:      apelsin.Float64,
+			Points:       points.Float64,
+			GiftCard:     giftCard.Float64,
+			GiftCardSold: giftCardSold.Float64,
+			TotalAmount:  totalAmount.Float64,
+			CreatedAt:    createdAt.String,
+			UpdatedAt:    updatedAt.String,
 		})
 	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if where.More(len(resp.Transactions), req.Limit) {
+		resp.Transactions = resp.Transactions[:len(resp.Transactions)-1]
+
+		var last = resp.Transactions[len(resp.Transactions)-1]
+		resp.NextCursor = where.NextCursor(last.CreatedAt, last.Id)
+	}
+
+	if req.Count {
+		total, args := where.Total()
+		err = r.db.QueryRow(ctx, `SELECT COUNT(*) FROM transaction`+total, args...).Scan(&resp.Count)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return &resp, nil
 }
@@ -240,7 +301,10 @@ func (r *transactionRepo) Update(ctx context.Context, req *models.UpdateTransact
 				click = $5,
 				humo = $6,
 				apelsin = $7,
-				total_amount = $8,
+				points = $8,
+				gift_card = $9,
+				gift_card_sold = $10,
+				total_amount = $11,
 				updated_at = NOW()
 		WHERE id = $1
 	`
@@ -253,6 +317,9 @@ func (r *transactionRepo) Update(ctx context.Context, req *models.UpdateTransact
 		req.Click,
 		req.Humo,
 		req.Apelsin,
+		req.Points,
+		req.GiftCard,
+		req.GiftCardSold,
 		req.TotalAmount,
 	)
 	if err != nil {
@@ -0,0 +1,756 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+	"market_system/storage"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type transferRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewTransferRepo(db *pgxpool.Pool) *transferRepo {
+	return &transferRepo{
+		db: db,
+	}
+}
+
+const transferColumns = `
+	id,
+	from_branch_id,
+	to_branch_id,
+	status,
+	comment,
+	created_by,
+	dispatched_at,
+	received_by,
+	received_at,
+	created_at,
+	updated_at
+`
+
+func scanTransfer(row pgx.Row, extra ...interface{}) (*models.Transfer, error) {
+
+	var (
+		ID           sql.NullString
+		FromBranchID sql.NullString
+		ToBranchID   sql.NullString
+		Status       sql.NullString
+		Comment      sql.NullString
+		CreatedBy    sql.NullString
+		DispatchedAt sql.NullString
+		ReceivedBy   sql.NullString
+		ReceivedAt   sql.NullString
+		CreatedAt    sql.NullString
+		UpdatedAt    sql.NullString
+	)
+
+	dest := append(extra,
+		&ID,
+		&FromBranchID,
+		&ToBranchID,
+		&Status,
+		&Comment,
+		&CreatedBy,
+		&DispatchedAt,
+		&ReceivedBy,
+		&ReceivedAt,
+		&CreatedAt,
+		&UpdatedAt,
+	)
+
+	err := row.Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.Transfer{
+		Id:           ID.String,
+		FromBranchID: FromBranchID.String,
+		ToBranchID:   ToBranchID.String,
+		Status:       Status.String,
+		Comment:      Comment.String,
+		CreatedBy:    CreatedBy.String,
+		DispatchedAt: DispatchedAt.String,
+		ReceivedBy:   ReceivedBy.String,
+		ReceivedAt:   ReceivedAt.String,
+		CreatedAt:    CreatedAt.String,
+		UpdatedAt:    UpdatedAt.String,
+	}, nil
+}
+
+func (r *transferRepo) Create(ctx context.Context, req *models.CreateTransfer) (*models.Transfer, error) {
+
+	var (
+		transferID = uuid.New().String()
+		query      = `
+			INSERT INTO transfer(
+				id,
+				from_branch_id,
+				to_branch_id,
+				status,
+				comment,
+				created_by,
+				updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, NOW())`
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx,
+		query,
+		transferID,
+		req.FromBranchID,
+		req.ToBranchID,
+		config.TransferDraft,
+		helpers.NewNullString(req.Comment),
+		helpers.NewNullString(req.CreatedBy),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.insertProducts(ctx, tx, transferID, req.Products)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, &models.TransferPrimaryKey{Id: transferID})
+}
+
+// insertProducts stores transfer lines with the name, category and income
+// price the source branch stocks the barcode under.
+func (r *transferRepo) insertProducts(ctx context.Context, tx pgx.Tx, transferID string, products []*models.CreateTransferProduct) error {
+
+	var query = `
+		INSERT INTO transfer_product(
+			id,
+			transfer_id,
+			category_id,
+			product_name,
+			barcode,
+			quantity,
+			cost,
+			updated_at
+		)
+		SELECT $1, t.id, rm.category_id, rm.product_name, rm.barcode, $4, rm.price_income, NOW()
+		FROM transfer AS t
+		JOIN remainder AS rm ON rm.branch_id = t.from_branch_id AND rm.barcode = $3
+		WHERE t.id = $2
+		ORDER BY rm.created_at
+		LIMIT 1
+	`
+
+	for _, product := range products {
+		result, err := tx.Exec(ctx,
+			query,
+			uuid.New().String(),
+			transferID,
+			product.Barcode,
+			product.Quantity,
+		)
+		if err != nil {
+			return err
+		}
+
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("barcode %s is not stocked at the source branch: %w", product.Barcode, pgx.ErrNoRows)
+		}
+	}
+
+	return nil
+}
+
+func (r *transferRepo) GetByID(ctx context.Context, req *models.TransferPrimaryKey) (*models.Transfer, error) {
+
+	var query = "SELECT " + transferColumns + " FROM transfer WHERE id = $1"
+
+	transfer, err := scanTransfer(r.db.QueryRow(ctx, query, req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	transfer.Products, err = r.getProducts(ctx, transfer.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+func (r *transferRepo) getProducts(ctx context.Context, transferID string) ([]*models.TransferProduct, error) {
+
+	var (
+		products []*models.TransferProduct
+		query    = `
+			SELECT
+				id,
+				transfer_id,
+				category_id,
+				product_name,
+				barcode,
+				quantity,
+				cost,
+				received_quantity,
+				discrepancy_comment,
+				created_at,
+				updated_at
+			FROM transfer_product
+			WHERE transfer_id = $1
+			ORDER BY created_at
+		`
+	)
+
+	rows, err := r.db.Query(ctx, query, transferID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			ID                 sql.NullString
+			TransferID         sql.NullString
+			CategoryID         sql.NullString
+			ProductName        sql.NullString
+			Barcode            sql.NullString
+			Quantity           sql.NullInt64
+			Cost               sql.NullFloat64
+			ReceivedQuantity   sql.NullInt64
+			DiscrepancyComment sql.NullString
+			CreatedAt          sql.NullString
+			UpdatedAt          sql.NullString
+		)
+
+		err = rows.Scan(
+			&ID,
+			&TransferID,
+			&CategoryID,
+			&ProductName,
+			&Barcode,
+			&Quantity,
+			&Cost,
+			&ReceivedQuantity,
+			&DiscrepancyComment,
+			&CreatedAt,
+			&UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		var shortage int64
+		if ReceivedQuantity.Valid {
+			shortage = Quantity.Int64 - ReceivedQuantity.Int64
+		}
+
+		products = append(products, &models.TransferProduct{
+			Id:                 ID.String,
+			TransferID:         TransferID.String,
+			CategoryID:         CategoryID.String,
+			ProductName:        ProductName.String,
+			Barcode:            Barcode.String,
+			Quantity:           Quantity.Int64,
+			Cost:               Cost.Float64,
+			ReceivedQuantity:   ReceivedQuantity.Int64,
+			Shortage:           shortage,
+			DiscrepancyComment: DiscrepancyComment.String,
+			CreatedAt:          CreatedAt.String,
+			UpdatedAt:          UpdatedAt.String,
+		})
+	}
+
+	return products, rows.Err()
+}
+
+var transferSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":             {Column: "id", Type: criteria.UUID},
+		"from_branch_id": {Column: "from_branch_id", Type: criteria.UUID},
+		"to_branch_id":   {Column: "to_branch_id", Type: criteria.UUID},
+		"status":         {Column: "status", Type: criteria.Text},
+		"created_by":     {Column: "created_by", Type: criteria.UUID},
+		"dispatched_at":  {Column: "dispatched_at", Type: criteria.Time},
+		"received_by":    {Column: "received_by", Type: criteria.UUID},
+		"received_at":    {Column: "received_at", Type: criteria.Time},
+		"created_at":     {Column: "created_at", Type: criteria.Time},
+		"updated_at":     {Column: "updated_at", Type: criteria.Time},
+	},
+	Sort: []criteria.Sort{{Field: "created_at", Desc: true}},
+}
+
+func (r *transferRepo) GetList(ctx context.Context, req *models.GetListTransferRequest) (*models.GetListTransferResponse, error) {
+	var (
+		resp  models.GetListTransferResponse
+		where = transferSchema.Query()
+	)
+
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.BranchID) > 0 {
+		branchID := where.Arg(req.BranchID)
+		where.And("(from_branch_id = " + branchID + " OR to_branch_id = " + branchID + ")")
+	}
+
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var query = "SELECT COUNT(*) OVER(), " + transferColumns + " FROM transfer"
+
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		transfer, err := scanTransfer(rows, &resp.Count)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Transfers = append(resp.Transfers, transfer)
+	}
+
+	return &resp, rows.Err()
+}
+
+// Update rewrites a draft transfer. Dispatched transfers are left untouched
+// and 0 rows affected is returned.
+func (r *transferRepo) Update(ctx context.Context, req *models.UpdateTransfer) (int64, error) {
+
+	query := `
+		UPDATE transfer
+			SET
+				to_branch_id = $2,
+				comment = $3,
+				updated_at = NOW()
+		WHERE id = $1 AND status = $4
+	`
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	rowsAffected, err := tx.Exec(ctx,
+		query,
+		req.Id,
+		req.ToBranchID,
+		helpers.NewNullString(req.Comment),
+		config.TransferDraft,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	if rowsAffected.RowsAffected() == 0 {
+		return 0, nil
+	}
+
+	_, err = tx.Exec(ctx, "DELETE FROM transfer_product WHERE transfer_id = $1", req.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.insertProducts(ctx, tx, req.Id, req.Products)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
+
+func (r *transferRepo) Delete(ctx context.Context, req *models.TransferPrimaryKey) error {
+	_, err := r.db.Exec(ctx, "DELETE FROM transfer WHERE id = $1 AND status = $2", req.Id, config.TransferDraft)
+	return err
+}
+
+// Dispatch takes every line out of the source branch and puts the transfer
+// in transit. Lines are valued at the source income price at this moment.
+// A line the source does not have enough of fails the whole dispatch with
+// storage.ErrNotEnoughStock.
+func (r *transferRepo) Dispatch(ctx context.Context, req *models.TransferPrimaryKey) (int64, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var fromBranchID string
+	err = tx.QueryRow(ctx,
+		"SELECT from_branch_id FROM transfer WHERE id = $1 AND status = $2 FOR UPDATE",
+		req.Id,
+		config.TransferDraft,
+	).Scan(&fromBranchID)
+	if err == pgx.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	lines, err := r.lines(ctx, tx, req.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	for id, line := range lines {
+		line.BranchID = fromBranchID
+		line.Type = config.StockMovementTransferOut
+		line.DocumentID = req.Id
+		line.PriceIncome = 0
+
+		left, lots, err := removeStockLots(ctx, tx, line)
+		if err == pgx.ErrNoRows || (err == nil && left < 0) {
+			return 0, fmt.Errorf("barcode %s: %w", line.Barcode, storage.ErrNotEnoughStock)
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		for _, lot := range lots {
+			_, err = tx.Exec(ctx,
+				"INSERT INTO transfer_product_lot(id, transfer_product_id, lot_number, expiry_date, quantity) VALUES ($1, $2, $3, $4::date, $5)",
+				uuid.New().String(),
+				id,
+				lot.LotNumber,
+				helpers.NewNullString(lot.ExpiryDate),
+				lot.Quantity,
+			)
+			if err != nil {
+				return 0, err
+			}
+		}
+
+		_, err = tx.Exec(ctx, `
+			UPDATE transfer_product
+				SET
+					cost = (SELECT price_income FROM remainder WHERE branch_id = $2 AND barcode = $3 ORDER BY created_at LIMIT 1),
+					updated_at = NOW()
+			WHERE id = $1
+			`,
+			id,
+			fromBranchID,
+			line.Barcode,
+		)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	rowsAffected, err := tx.Exec(ctx,
+		"UPDATE transfer SET status = $2, dispatched_at = NOW(), updated_at = NOW() WHERE id = $1",
+		req.Id,
+		config.TransferInTransit,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
+
+// Receive puts an in-transit transfer into the destination branch. Each line
+// is received at the reported quantity (in full when not reported); what did
+// not arrive stays on the line as a shortage and does not return to the
+// source.
+func (r *transferRepo) Receive(ctx context.Context, req *models.ReceiveTransfer) (int64, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var toBranchID string
+	err = tx.QueryRow(ctx,
+		"SELECT to_branch_id FROM transfer WHERE id = $1 AND status = $2 FOR UPDATE",
+		req.Id,
+		config.TransferInTransit,
+	).Scan(&toBranchID)
+	if err == pgx.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	lines, err := r.lines(ctx, tx, req.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	var reported = make(map[string]*models.ReceiveTransferProduct, len(req.Products))
+	for _, product := range req.Products {
+		line, ok := lines[product.TransferProductID]
+		if !ok {
+			return 0, fmt.Errorf("transfer line %s: %w", product.TransferProductID, pgx.ErrNoRows)
+		}
+		if product.ReceivedQuantity > line.Quantity {
+			return 0, fmt.Errorf("transfer line %s: received %d of %d dispatched", product.TransferProductID, product.ReceivedQuantity, line.Quantity)
+		}
+		reported[product.TransferProductID] = product
+	}
+
+	for id, line := range lines {
+		var (
+			received = line.Quantity
+			comment  string
+		)
+		if product, ok := reported[id]; ok {
+			received = product.ReceivedQuantity
+			comment = product.DiscrepancyComment
+		}
+
+		_, err = tx.Exec(ctx,
+			"UPDATE transfer_product SET received_quantity = $2, discrepancy_comment = $3, updated_at = NOW() WHERE id = $1",
+			id,
+			received,
+			helpers.NewNullString(comment),
+		)
+		if err != nil {
+			return 0, err
+		}
+
+		if received == 0 {
+			continue
+		}
+
+		line.BranchID = toBranchID
+		line.Type = config.StockMovementTransferIn
+		line.DocumentID = req.Id
+
+		err = r.receiveLine(ctx, tx, id, line, received)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	rowsAffected, err := tx.Exec(ctx,
+		"UPDATE transfer SET status = $2, received_by = $3, received_at = NOW(), updated_at = NOW() WHERE id = $1",
+		req.Id,
+		config.TransferReceived,
+		helpers.NewNullString(req.ReceivedBy),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
+
+// receiveLine puts received items of a transfer line into the destination,
+// in the lots they left the source with, earliest expiry first. Items the
+// lots do not cover were sent without a lot and arrive without one.
+func (r *transferRepo) receiveLine(ctx context.Context, tx pgx.Tx, transferProductID string, line stockLine, received int64) error {
+
+	rows, err := tx.Query(ctx, `
+		SELECT lot_number, COALESCE(TO_CHAR(expiry_date, 'YYYY-MM-DD'), ''), quantity
+		FROM transfer_product_lot
+		WHERE transfer_product_id = $1
+		ORDER BY expiry_date NULLS LAST
+		`,
+		transferProductID,
+	)
+	if err != nil {
+		return err
+	}
+
+	var lots []stockLot
+	for rows.Next() {
+		var lot stockLot
+		err = rows.Scan(&lot.LotNumber, &lot.ExpiryDate, &lot.Quantity)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		lots = append(lots, lot)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, lot := range lots {
+		if received <= 0 {
+			break
+		}
+
+		if lot.Quantity > received {
+			lot.Quantity = received
+		}
+		received -= lot.Quantity
+
+		lotLine := line
+		lotLine.Quantity = lot.Quantity
+		lotLine.LotNumber = lot.LotNumber
+		lotLine.ExpiryDate = lot.ExpiryDate
+
+		err = addStock(ctx, tx, lotLine)
+		if err != nil {
+			return err
+		}
+	}
+
+	if received <= 0 {
+		return nil
+	}
+
+	line.Quantity = received
+	return addStock(ctx, tx, line)
+}
+
+func (r *transferRepo) lines(ctx context.Context, tx pgx.Tx, transferID string) (map[string]stockLine, error) {
+
+	rows, err := tx.Query(ctx, `
+		SELECT id, category_id, COALESCE(product_name, ''), barcode, quantity, COALESCE(cost, 0)
+		FROM transfer_product
+		WHERE transfer_id = $1
+		`,
+		transferID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lines = map[string]stockLine{}
+	for rows.Next() {
+		var (
+			id         string
+			categoryID sql.NullString
+			line       stockLine
+		)
+
+		err = rows.Scan(&id, &categoryID, &line.ProductName, &line.Barcode, &line.Quantity, &line.PriceIncome)
+		if err != nil {
+			return nil, err
+		}
+		line.CategoryID = categoryID.String
+
+		lines[id] = line
+	}
+
+	return lines, rows.Err()
+}
+
+func (r *transferRepo) Cancel(ctx context.Context, req *models.TransferPrimaryKey) (int64, error) {
+
+	rowsAffected, err := r.db.Exec(ctx,
+		"UPDATE transfer SET status = $2, updated_at = NOW() WHERE id = $1 AND status = $3",
+		req.Id,
+		config.TransferCanceled,
+		config.TransferDraft,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
+
+// InTransit sums dispatched but not yet received transfers per branch, both
+// what left the branch and what is on its way to it.
+func (r *transferRepo) InTransit(ctx context.Context, req *models.InTransitRequest) (*models.InTransitResponse, error) {
+
+	var (
+		resp  = models.InTransitResponse{Branches: []*models.InTransit{}}
+		query = `
+			WITH moving AS (
+				SELECT
+					t.id,
+					t.from_branch_id,
+					t.to_branch_id,
+					tp.quantity,
+					tp.quantity * COALESCE(tp.cost, 0) AS cost
+				FROM transfer AS t
+				JOIN transfer_product AS tp ON tp.transfer_id = t.id
+				WHERE t.status = $1
+			)
+			SELECT
+				b.id,
+				b.name,
+				COALESCE(SUM(m.quantity) FILTER (WHERE m.from_branch_id = b.id), 0),
+				COALESCE(SUM(m.cost) FILTER (WHERE m.from_branch_id = b.id), 0),
+				COALESCE(SUM(m.quantity) FILTER (WHERE m.to_branch_id = b.id), 0),
+				COALESCE(SUM(m.cost) FILTER (WHERE m.to_branch_id = b.id), 0),
+				COUNT(DISTINCT m.id)
+			FROM branch AS b
+			JOIN moving AS m ON m.from_branch_id = b.id OR m.to_branch_id = b.id
+			WHERE ($2::uuid IS NULL OR b.id = $2)
+			GROUP BY b.id, b.name
+			ORDER BY b.name
+		`
+	)
+
+	rows, err := r.db.Query(ctx, query, config.TransferInTransit, helpers.NewNullString(req.BranchID))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var branch models.InTransit
+
+		err = rows.Scan(
+			&branch.BranchID,
+			&branch.BranchName,
+			&branch.OutgoingQuantity,
+			&branch.OutgoingCost,
+			&branch.IncomingQuantity,
+			&branch.IncomingCost,
+			&branch.Transfers,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Branches = append(resp.Branches, &branch)
+	}
+
+	return &resp, rows.Err()
+}
@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/storage"
+
+	"github.com/google/uuid"
+)
+
+func Test_transferRepo_DispatchReceive(t *testing.T) {
+
+	cfg := config.Load()
+
+	strg, err := NewConnectionPostgres(&cfg)
+	if err != nil {
+		t.Fatalf("failed to connect to PostgreSQL: %v", err)
+	}
+
+	var (
+		ctx  = context.Background()
+		item = newTestStockItem(t, strg, 10)
+	)
+
+	to, err := strg.Branch().Create(ctx, &models.CreateBranch{Name: "transfer " + uuid.NewString()})
+	if err != nil {
+		t.Fatalf("branchRepo.Create() error = %v", err)
+	}
+
+	inTransit := func(branchID string) *models.InTransit {
+		resp, err := strg.Transfer().InTransit(ctx, &models.InTransitRequest{BranchID: branchID})
+		if err != nil {
+			t.Fatalf("transferRepo.InTransit() error = %v", err)
+		}
+		for _, branch := range resp.Branches {
+			if branch.BranchID == branchID {
+				return branch
+			}
+		}
+		return &models.InTransit{BranchID: branchID}
+	}
+
+	create := func(quantity int64) *models.Transfer {
+		transfer, err := strg.Transfer().Create(ctx, &models.CreateTransfer{
+			FromBranchID: item.BranchID,
+			ToBranchID:   to.Id,
+			Products:     []*models.CreateTransferProduct{{Barcode: item.Barcode, Quantity: quantity}},
+		})
+		if err != nil {
+			t.Fatalf("transferRepo.Create() error = %v", err)
+		}
+		return transfer
+	}
+
+	t.Run("more than the branch has", func(t *testing.T) {
+		transfer := create(15)
+
+		_, err := strg.Transfer().Dispatch(ctx, &models.TransferPrimaryKey{Id: transfer.Id})
+		if !errors.Is(err, storage.ErrNotEnoughStock) {
+			t.Fatalf("transferRepo.Dispatch() error = %v, want %v", err, storage.ErrNotEnoughStock)
+		}
+
+		if got := testStock(t, strg, item.BranchID, item.Barcode); got != 10 {
+			t.Errorf("transferRepo.Dispatch() stock = %v, want 10", got)
+		}
+		checkMovements(t, testMovements(t, strg, transfer.Id), config.StockMovementTransferOut)
+		if got := inTransit(item.BranchID).OutgoingQuantity; got != 0 {
+			t.Errorf("transferRepo.InTransit() outgoing = %v, want 0", got)
+		}
+	})
+
+	t.Run("dispatched and received short", func(t *testing.T) {
+		transfer := create(4)
+
+		rowsAffected, err := strg.Transfer().Dispatch(ctx, &models.TransferPrimaryKey{Id: transfer.Id})
+		if err != nil || rowsAffected != 1 {
+			t.Fatalf("transferRepo.Dispatch() = %d, %v, want 1, nil", rowsAffected, err)
+		}
+
+		if got := testStock(t, strg, item.BranchID, item.Barcode); got != 6 {
+			t.Errorf("transferRepo.Dispatch() source stock = %v, want 6", got)
+		}
+		if got := testStock(t, strg, to.Id, item.Barcode); got != 0 {
+			t.Errorf("transferRepo.Dispatch() destination stock = %v, want 0", got)
+		}
+		checkMovements(t, testMovements(t, strg, transfer.Id), config.StockMovementTransferOut, -4)
+		if got := inTransit(item.BranchID).OutgoingQuantity; got != 4 {
+			t.Errorf("transferRepo.InTransit() outgoing = %v, want 4", got)
+		}
+		if got := inTransit(to.Id).IncomingQuantity; got != 4 {
+			t.Errorf("transferRepo.InTransit() incoming = %v, want 4", got)
+		}
+
+		dispatched, err := strg.Transfer().GetByID(ctx, &models.TransferPrimaryKey{Id: transfer.Id})
+		if err != nil {
+			t.Fatalf("transferRepo.GetByID() error = %v", err)
+		}
+
+		rowsAffected, err = strg.Transfer().Receive(ctx, &models.ReceiveTransfer{
+			Id: transfer.Id,
+			Products: []*models.ReceiveTransferProduct{{
+				TransferProductID:  dispatched.Products[0].Id,
+				ReceivedQuantity:   3,
+				DiscrepancyComment: "broken",
+			}},
+		})
+		if err != nil || rowsAffected != 1 {
+			t.Fatalf("transferRepo.Receive() = %d, %v, want 1, nil", rowsAffected, err)
+		}
+
+		if got := testStock(t, strg, item.BranchID, item.Barcode); got != 6 {
+			t.Errorf("transferRepo.Receive() source stock = %v, want 6", got)
+		}
+		if got := testStock(t, strg, to.Id, item.Barcode); got != 3 {
+			t.Errorf("transferRepo.Receive() destination stock = %v, want 3", got)
+		}
+
+		var out, in []*models.StockMovement
+		for _, movement := range testMovements(t, strg, transfer.Id) {
+			if movement.BranchID == item.BranchID {
+				out = append(out, movement)
+			} else {
+				in = append(in, movement)
+			}
+		}
+		checkMovements(t, out, config.StockMovementTransferOut, -4)
+		checkMovements(t, in, config.StockMovementTransferIn, 3)
+		if in[0].BranchID != to.Id || in[0].BalanceAfter != 3 {
+			t.Errorf("transfer in movement = %v balance %v, want %v balance 3", in[0].BranchID, in[0].BalanceAfter, to.Id)
+		}
+
+		received, err := strg.Transfer().GetByID(ctx, &models.TransferPrimaryKey{Id: transfer.Id})
+		if err != nil {
+			t.Fatalf("transferRepo.GetByID() error = %v", err)
+		}
+		if got := received.Products[0]; got.ReceivedQuantity != 3 || got.Shortage != 1 {
+			t.Errorf("transferRepo.Receive() received = %v, shortage = %v, want 3 and 1", got.ReceivedQuantity, got.Shortage)
+		}
+
+		if got := inTransit(item.BranchID).OutgoingQuantity; got != 0 {
+			t.Errorf("transferRepo.InTransit() outgoing after receive = %v, want 0", got)
+		}
+		if got := inTransit(to.Id).IncomingQuantity; got != 0 {
+			t.Errorf("transferRepo.InTransit() incoming after receive = %v, want 0", got)
+		}
+	})
+}
@@ -4,10 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"fmt"
 
 	"market_system/config"
 	"market_system/models"
+	"market_system/pkg/criteria"
 	"market_system/pkg/helpers"
 
 	"github.com/google/uuid"
@@ -128,27 +128,38 @@ func (r *userRepo) GetByID(ctx context.Context, req *models.UserPrimaryKey) (*mo
 	}, nil
 }
 
+var userSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":          {Column: "id", Type: criteria.UUID},
+		"first_name":  {Column: "first_name", Type: criteria.Text},
+		"last_name":   {Column: "last_name", Type: criteria.Text},
+		"login":       {Column: "login", Type: criteria.Text},
+		"active":      {Column: "active", Type: criteria.Bool},
+		"client_type": {Column: "client_type", Type: criteria.Text},
+		"created_at":  {Column: "created_at", Type: criteria.Time},
+		"updated_at":  {Column: "updated_at", Type: criteria.Time},
+	},
+	Search: []string{"first_name", "last_name", "login"},
+	Sort:   []criteria.Sort{{Field: "created_at", Desc: true}},
+}
+
 func (r *userRepo) GetList(ctx context.Context, req *models.GetListUserRequest) (*models.GetListUserResponse, error) {
 	var (
-		resp   models.GetListUserResponse
-		where  = " WHERE TRUE"
-		offset = " OFFSET 0"
-		limit  = " LIMIT 10"
-		sort   = " ORDER BY created_at DESC"
+		resp  models.GetListUserResponse
+		where = userSchema.Query()
 	)
 
-	if req.Offset > 0 {
-		offset = fmt.Sprintf(" OFFSET %d", req.Offset)
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
 	}
+	where.Search(req.Search)
 
-	if req.Limit > 0 {
-		limit = fmt.Sprintf(" LIMIT %d", req.Limit)
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
 	}
 
-	// if len(req.Search) > 0 {
-	// 	where += " AND first_name ILIKE" + " '%" + req.Search + "%'"
-	// }
-
 	var query = `
 		SELECT
 			COUNT(*) OVER(),
@@ -164,8 +175,8 @@ func (r *userRepo) GetList(ctx context.Context, req *models.GetListUserRequest)
 		FROM "user"
 	`
 
-	query += where + sort + offset + limit
-	rows, err := r.db.Query(ctx, query)
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
 	if err != nil {
 		return nil, err
 	}
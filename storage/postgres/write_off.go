@@ -0,0 +1,662 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/pkg/criteria"
+	"market_system/pkg/helpers"
+	"market_system/storage"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type writeOffRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewWriteOffRepo(db *pgxpool.Pool) *writeOffRepo {
+	return &writeOffRepo{
+		db: db,
+	}
+}
+
+const writeOffColumns = `
+	wo.id,
+	wo.branch_id,
+	wo.reason,
+	wo.status,
+	wo.comment,
+	wo.created_by,
+	wo.reviewed_by,
+	wo.reviewed_at,
+	wo.posted_at,
+	COALESCE((SELECT SUM(quantity * cost) FROM write_off_product WHERE write_off_id = wo.id), 0),
+	wo.created_at,
+	wo.updated_at
+`
+
+func scanWriteOff(row pgx.Row, extra ...interface{}) (*models.WriteOff, error) {
+
+	var (
+		ID         sql.NullString
+		BranchID   sql.NullString
+		Reason     sql.NullString
+		Status     sql.NullString
+		Comment    sql.NullString
+		CreatedBy  sql.NullString
+		ReviewedBy sql.NullString
+		ReviewedAt sql.NullString
+		PostedAt   sql.NullString
+		TotalCost  sql.NullFloat64
+		CreatedAt  sql.NullString
+		UpdatedAt  sql.NullString
+	)
+
+	dest := append(extra,
+		&ID,
+		&BranchID,
+		&Reason,
+		&Status,
+		&Comment,
+		&CreatedBy,
+		&ReviewedBy,
+		&ReviewedAt,
+		&PostedAt,
+		&TotalCost,
+		&CreatedAt,
+		&UpdatedAt,
+	)
+
+	err := row.Scan(dest...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.WriteOff{
+		Id:         ID.String,
+		BranchID:   BranchID.String,
+		Reason:     Reason.String,
+		Status:     Status.String,
+		Comment:    Comment.String,
+		CreatedBy:  CreatedBy.String,
+		ReviewedBy: ReviewedBy.String,
+		ReviewedAt: ReviewedAt.String,
+		PostedAt:   PostedAt.String,
+		TotalCost:  TotalCost.Float64,
+		CreatedAt:  CreatedAt.String,
+		UpdatedAt:  UpdatedAt.String,
+	}, nil
+}
+
+func (r *writeOffRepo) Create(ctx context.Context, req *models.CreateWriteOff) (*models.WriteOff, error) {
+
+	var (
+		writeOffID = uuid.New().String()
+		query      = `
+			INSERT INTO write_off(
+				id,
+				branch_id,
+				reason,
+				status,
+				comment,
+				created_by,
+				updated_at
+			) VALUES ($1, $2, $3, $4, $5, $6, NOW())`
+	)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx,
+		query,
+		writeOffID,
+		req.BranchID,
+		req.Reason,
+		config.WriteOffDraft,
+		helpers.NewNullString(req.Comment),
+		helpers.NewNullString(req.CreatedBy),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.insertProducts(ctx, tx, writeOffID, req.Products)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, &models.WriteOffPrimaryKey{Id: writeOffID})
+}
+
+// insertProducts stores write-off lines valued at the income price the branch
+// stocks the barcode under.
+func (r *writeOffRepo) insertProducts(ctx context.Context, tx pgx.Tx, writeOffID string, products []*models.CreateWriteOffProduct) error {
+
+	var query = `
+		INSERT INTO write_off_product(
+			id,
+			write_off_id,
+			category_id,
+			product_name,
+			barcode,
+			quantity,
+			cost,
+			updated_at
+		)
+		SELECT $1, wo.id, rm.category_id, rm.product_name, rm.barcode, $4, rm.price_income, NOW()
+		FROM write_off AS wo
+		JOIN remainder AS rm ON rm.branch_id = wo.branch_id AND rm.barcode = $3
+		WHERE wo.id = $2
+		ORDER BY rm.created_at
+		LIMIT 1
+	`
+
+	for _, product := range products {
+		result, err := tx.Exec(ctx,
+			query,
+			uuid.New().String(),
+			writeOffID,
+			product.Barcode,
+			product.Quantity,
+		)
+		if err != nil {
+			return err
+		}
+
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("barcode %s is not stocked at the branch: %w", product.Barcode, pgx.ErrNoRows)
+		}
+	}
+
+	return nil
+}
+
+func (r *writeOffRepo) GetByID(ctx context.Context, req *models.WriteOffPrimaryKey) (*models.WriteOff, error) {
+
+	var query = "SELECT " + writeOffColumns + " FROM write_off AS wo WHERE wo.id = $1"
+
+	writeOff, err := scanWriteOff(r.db.QueryRow(ctx, query, req.Id))
+	if err != nil {
+		return nil, err
+	}
+
+	writeOff.Products, err = r.getProducts(ctx, writeOff.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return writeOff, nil
+}
+
+func (r *writeOffRepo) getProducts(ctx context.Context, writeOffID string) ([]*models.WriteOffProduct, error) {
+
+	var (
+		products []*models.WriteOffProduct
+		query    = `
+			SELECT
+				id,
+				write_off_id,
+				category_id,
+				product_name,
+				barcode,
+				quantity,
+				cost,
+				created_at,
+				updated_at
+			FROM write_off_product
+			WHERE write_off_id = $1
+			ORDER BY created_at
+		`
+	)
+
+	rows, err := r.db.Query(ctx, query, writeOffID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			ID          sql.NullString
+			WriteOffID  sql.NullString
+			CategoryID  sql.NullString
+			ProductName sql.NullString
+			Barcode     sql.NullString
+			Quantity    sql.NullInt64
+			Cost        sql.NullFloat64
+			CreatedAt   sql.NullString
+			UpdatedAt   sql.NullString
+		)
+
+		err = rows.Scan(
+			&ID,
+			&WriteOffID,
+			&CategoryID,
+			&ProductName,
+			&Barcode,
+			&Quantity,
+			&Cost,
+			&CreatedAt,
+			&UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		products = append(products, &models.WriteOffProduct{
+			Id:          ID.String,
+			WriteOffID:  WriteOffID.String,
+			CategoryID:  CategoryID.String,
+			ProductName: ProductName.String,
+			Barcode:     Barcode.String,
+			Quantity:    Quantity.Int64,
+			Cost:        Cost.Float64,
+			TotalCost:   float64(Quantity.Int64) * Cost.Float64,
+			CreatedAt:   CreatedAt.String,
+			UpdatedAt:   UpdatedAt.String,
+		})
+	}
+
+	return products, rows.Err()
+}
+
+var writeOffSchema = &criteria.Schema{
+	Fields: map[string]criteria.Field{
+		"id":          {Column: "wo.id", Type: criteria.UUID},
+		"branch_id":   {Column: "wo.branch_id", Type: criteria.UUID},
+		"reason":      {Column: "wo.reason", Type: criteria.Text},
+		"status":      {Column: "wo.status", Type: criteria.Text},
+		"created_by":  {Column: "wo.created_by", Type: criteria.UUID},
+		"reviewed_by": {Column: "wo.reviewed_by", Type: criteria.UUID},
+		"reviewed_at": {Column: "wo.reviewed_at", Type: criteria.Time},
+		"posted_at":   {Column: "wo.posted_at", Type: criteria.Time},
+		"created_at":  {Column: "wo.created_at", Type: criteria.Time},
+		"updated_at":  {Column: "wo.updated_at", Type: criteria.Time},
+	},
+	Sort: []criteria.Sort{{Field: "created_at", Desc: true}},
+}
+
+func (r *writeOffRepo) GetList(ctx context.Context, req *models.GetListWriteOffRequest) (*models.GetListWriteOffResponse, error) {
+	var (
+		resp  models.GetListWriteOffResponse
+		where = writeOffSchema.Query()
+	)
+
+	err := where.Filter(req.Filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	sort, err := where.OrderBy(req.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var query = "SELECT COUNT(*) OVER(), " + writeOffColumns + " FROM write_off AS wo"
+
+	query += where.SQL() + sort + criteria.Page(req.Offset, req.Limit)
+	rows, err := r.db.Query(ctx, query, where.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		writeOff, err := scanWriteOff(rows, &resp.Count)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.WriteOffs = append(resp.WriteOffs, writeOff)
+	}
+
+	return &resp, rows.Err()
+}
+
+// Update rewrites a draft write-off. Submitted write-offs are left untouched
+// and 0 rows affected is returned.
+func (r *writeOffRepo) Update(ctx context.Context, req *models.UpdateWriteOff) (int64, error) {
+
+	query := `
+		UPDATE write_off
+			SET
+				reason = $2,
+				comment = $3,
+				updated_at = NOW()
+		WHERE id = $1 AND status = $4
+	`
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	rowsAffected, err := tx.Exec(ctx,
+		query,
+		req.Id,
+		req.Reason,
+		helpers.NewNullString(req.Comment),
+		config.WriteOffDraft,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	if rowsAffected.RowsAffected() == 0 {
+		return 0, nil
+	}
+
+	_, err = tx.Exec(ctx, "DELETE FROM write_off_product WHERE write_off_id = $1", req.Id)
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.insertProducts(ctx, tx, req.Id, req.Products)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
+
+func (r *writeOffRepo) Delete(ctx context.Context, req *models.WriteOffPrimaryKey) error {
+	_, err := r.db.Exec(ctx, "DELETE FROM write_off WHERE id = $1 AND status = $2", req.Id, config.WriteOffDraft)
+	return err
+}
+
+// Post submits a draft write-off. One worth no more than the approval
+// threshold leaves stock right away; a costlier one goes to pending_approval
+// and leaves stock when approved.
+func (r *writeOffRepo) Post(ctx context.Context, req *models.PostWriteOff) (int64, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		branchID  string
+		totalCost float64
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT
+			wo.branch_id,
+			COALESCE((SELECT SUM(quantity * cost) FROM write_off_product WHERE write_off_id = wo.id), 0)
+		FROM write_off AS wo
+		WHERE wo.id = $1 AND wo.status = $2
+		FOR UPDATE
+		`,
+		req.Id,
+		config.WriteOffDraft,
+	).Scan(&branchID, &totalCost)
+	if err == pgx.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if totalCost > req.ApprovalThreshold {
+		rowsAffected, err := tx.Exec(ctx,
+			"UPDATE write_off SET status = $2, updated_at = NOW() WHERE id = $1",
+			req.Id,
+			config.WriteOffPendingApproval,
+		)
+		if err != nil {
+			return 0, err
+		}
+
+		return rowsAffected.RowsAffected(), tx.Commit(ctx)
+	}
+
+	err = r.deduct(ctx, tx, req.Id, branchID)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := tx.Exec(ctx,
+		"UPDATE write_off SET status = $2, posted_at = NOW(), updated_at = NOW() WHERE id = $1",
+		req.Id,
+		config.WriteOffPosted,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
+
+// Approve posts a write-off pending approval.
+func (r *writeOffRepo) Approve(ctx context.Context, req *models.ReviewWriteOff) (int64, error) {
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var branchID string
+	err = tx.QueryRow(ctx,
+		"SELECT branch_id FROM write_off WHERE id = $1 AND status = $2 FOR UPDATE",
+		req.Id,
+		config.WriteOffPendingApproval,
+	).Scan(&branchID)
+	if err == pgx.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	err = r.deduct(ctx, tx, req.Id, branchID)
+	if err != nil {
+		return 0, err
+	}
+
+	rowsAffected, err := tx.Exec(ctx, `
+		UPDATE write_off
+			SET
+				status = $2,
+				reviewed_by = $3,
+				reviewed_at = NOW(),
+				posted_at = NOW(),
+				updated_at = NOW()
+		WHERE id = $1
+		`,
+		req.Id,
+		config.WriteOffPosted,
+		helpers.NewNullString(req.ReviewedBy),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
+
+// deduct takes every line of a write-off out of the branch stock. A line the
+// branch does not have enough of fails with storage.ErrNotEnoughStock.
+func (r *writeOffRepo) deduct(ctx context.Context, tx pgx.Tx, writeOffID, branchID string) error {
+
+	rows, err := tx.Query(ctx, "SELECT barcode, quantity, COALESCE(cost, 0) FROM write_off_product WHERE write_off_id = $1", writeOffID)
+	if err != nil {
+		return err
+	}
+
+	var lines []stockLine
+	for rows.Next() {
+		var line = stockLine{BranchID: branchID, Type: config.StockMovementWriteOff, DocumentID: writeOffID}
+		err = rows.Scan(&line.Barcode, &line.Quantity, &line.PriceIncome)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		lines = append(lines, line)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, line := range lines {
+		left, err := removeStock(ctx, tx, line)
+		if err == pgx.ErrNoRows || (err == nil && left < 0) {
+			return fmt.Errorf("barcode %s: %w", line.Barcode, storage.ErrNotEnoughStock)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *writeOffRepo) Reject(ctx context.Context, req *models.ReviewWriteOff) (int64, error) {
+
+	rowsAffected, err := r.db.Exec(ctx,
+		"UPDATE write_off SET status = $2, reviewed_by = $3, reviewed_at = NOW(), updated_at = NOW() WHERE id = $1 AND status = $4",
+		req.Id,
+		config.WriteOffRejected,
+		helpers.NewNullString(req.ReviewedBy),
+		config.WriteOffPendingApproval,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
+
+func (r *writeOffRepo) Cancel(ctx context.Context, req *models.WriteOffPrimaryKey) (int64, error) {
+
+	rowsAffected, err := r.db.Exec(ctx,
+		"UPDATE write_off SET status = $2, updated_at = NOW() WHERE id = $1 AND status = $3",
+		req.Id,
+		config.WriteOffCanceled,
+		config.WriteOffDraft,
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	return rowsAffected.RowsAffected(), nil
+}
+
+// ShrinkageReport sums posted write-offs per period, branch, category and
+// reason, and per reason over the whole range.
+func (r *writeOffRepo) ShrinkageReport(ctx context.Context, req *models.ShrinkageReportRequest) (*models.ShrinkageReportResponse, error) {
+
+	var (
+		resp = models.ShrinkageReportResponse{
+			Rows:     []*models.Shrinkage{},
+			ByReason: []*models.ShrinkageByReason{},
+		}
+		query = `
+			SELECT
+				TO_CHAR(DATE_TRUNC($1, wo.posted_at), 'YYYY-MM-DD'),
+				b.id,
+				b.name,
+				COALESCE(c.id::text, ''),
+				COALESCE(c.title, ''),
+				wo.reason,
+				SUM(wp.quantity),
+				COALESCE(SUM(wp.quantity * wp.cost), 0)
+			FROM write_off AS wo
+			JOIN write_off_product AS wp ON wp.write_off_id = wo.id
+			JOIN branch AS b ON b.id = wo.branch_id
+			LEFT JOIN category AS c ON c.id = wp.category_id
+			WHERE wo.status = $2
+				AND ($3::uuid IS NULL OR wo.branch_id = $3)
+				AND ($4::timestamp IS NULL OR wo.posted_at >= $4)
+				AND ($5::timestamp IS NULL OR wo.posted_at < $5)
+			GROUP BY 1, b.id, b.name, c.id, c.title, wo.reason
+			ORDER BY 1, b.name, c.title, wo.reason
+		`
+	)
+
+	rows, err := r.db.Query(ctx,
+		query,
+		req.Interval,
+		config.WriteOffPosted,
+		helpers.NewNullString(req.BranchID),
+		helpers.NewNullString(req.FromDate),
+		helpers.NewNullString(req.ToDate),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var byReason = map[string]*models.ShrinkageByReason{}
+	for rows.Next() {
+		var row models.Shrinkage
+
+		err = rows.Scan(
+			&row.Period,
+			&row.BranchID,
+			&row.BranchName,
+			&row.CategoryID,
+			&row.CategoryName,
+			&row.Reason,
+			&row.Quantity,
+			&row.Cost,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Rows = append(resp.Rows, &row)
+		resp.TotalCost += row.Cost
+
+		reason, ok := byReason[row.Reason]
+		if !ok {
+			reason = &models.ShrinkageByReason{Reason: row.Reason}
+			byReason[row.Reason] = reason
+		}
+		reason.Quantity += row.Quantity
+		reason.Cost += row.Cost
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, reason := range config.WriteOffReasons {
+		if shrinkage, ok := byReason[reason]; ok {
+			resp.ByReason = append(resp.ByReason, shrinkage)
+		}
+	}
+
+	return &resp, nil
+}
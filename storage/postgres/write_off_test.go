@@ -0,0 +1,144 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"market_system/config"
+	"market_system/models"
+	"market_system/storage"
+)
+
+func Test_writeOffRepo_Post(t *testing.T) {
+
+	cfg := config.Load()
+
+	strg, err := NewConnectionPostgres(&cfg)
+	if err != nil {
+		t.Fatalf("failed to connect to PostgreSQL: %v", err)
+	}
+
+	var (
+		ctx  = context.Background()
+		item = newTestStockItem(t, strg, 10)
+	)
+
+	// the item is stocked at 50, so a line of n costs 50n against the threshold
+	tests := []struct {
+		name      string
+		reason    string
+		quantity  int64
+		threshold float64
+		review    string
+		wantErr   error
+		status    string
+		stock     int
+		moved     []int64
+	}{
+		{
+			name:      "more than the branch has",
+			reason:    "theft",
+			quantity:  15,
+			threshold: 1000,
+			wantErr:   storage.ErrNotEnoughStock,
+			status:    config.WriteOffDraft,
+			stock:     10,
+		},
+		{
+			name:      "under the threshold posts at once",
+			reason:    "damaged",
+			quantity:  2,
+			threshold: 1000,
+			status:    config.WriteOffPosted,
+			stock:     8,
+			moved:     []int64{-2},
+		},
+		{
+			name:      "over the threshold waits for approval",
+			reason:    "expired",
+			quantity:  3,
+			threshold: 100,
+			status:    config.WriteOffPendingApproval,
+			stock:     8,
+		},
+		{
+			name:      "approved",
+			reason:    "expired",
+			quantity:  3,
+			threshold: 100,
+			review:    config.WriteOffPosted,
+			status:    config.WriteOffPosted,
+			stock:     5,
+			moved:     []int64{-3},
+		},
+		{
+			name:      "rejected",
+			reason:    "internal_use",
+			quantity:  1,
+			threshold: 10,
+			review:    config.WriteOffRejected,
+			status:    config.WriteOffRejected,
+			stock:     5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeOff, err := strg.WriteOff().Create(ctx, &models.CreateWriteOff{
+				BranchID: item.BranchID,
+				Reason:   tt.reason,
+				Products: []*models.CreateWriteOffProduct{{Barcode: item.Barcode, Quantity: tt.quantity}},
+			})
+			if err != nil {
+				t.Fatalf("writeOffRepo.Create() error = %v", err)
+			}
+
+			_, err = strg.WriteOff().Post(ctx, &models.PostWriteOff{Id: writeOff.Id, ApprovalThreshold: tt.threshold})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("writeOffRepo.Post() error = %v, want %v", err, tt.wantErr)
+			}
+
+			switch tt.review {
+			case config.WriteOffPosted:
+				_, err = strg.WriteOff().Approve(ctx, &models.ReviewWriteOff{Id: writeOff.Id})
+			case config.WriteOffRejected:
+				_, err = strg.WriteOff().Reject(ctx, &models.ReviewWriteOff{Id: writeOff.Id})
+			}
+			if err != nil {
+				t.Fatalf("writeOff review %s error = %v", tt.review, err)
+			}
+
+			got, err := strg.WriteOff().GetByID(ctx, &models.WriteOffPrimaryKey{Id: writeOff.Id})
+			if err != nil {
+				t.Fatalf("writeOffRepo.GetByID() error = %v", err)
+			}
+			if got.Status != tt.status {
+				t.Errorf("writeOffRepo.Post() status = %v, want %v", got.Status, tt.status)
+			}
+
+			if got := testStock(t, strg, item.BranchID, item.Barcode); got != tt.stock {
+				t.Errorf("writeOffRepo.Post() stock = %v, want %v", got, tt.stock)
+			}
+
+			var movements = testMovements(t, strg, writeOff.Id)
+			checkMovements(t, movements, config.StockMovementWriteOff, tt.moved...)
+			if len(movements) > 0 && movements[0].BalanceAfter != int64(tt.stock) {
+				t.Errorf("stock movement balance after = %v, want %v", movements[0].BalanceAfter, tt.stock)
+			}
+		})
+	}
+
+	report, err := strg.WriteOff().ShrinkageReport(ctx, &models.ShrinkageReportRequest{BranchID: item.BranchID, Interval: "month"})
+	if err != nil {
+		t.Fatalf("writeOffRepo.ShrinkageReport() error = %v", err)
+	}
+
+	var byReason = map[string]float64{}
+	for _, reason := range report.ByReason {
+		byReason[reason.Reason] = reason.Cost
+	}
+	if len(byReason) != 2 || byReason["damaged"] != 100 || byReason["expired"] != 150 || report.TotalCost != 250 {
+		t.Errorf("writeOffRepo.ShrinkageReport() by reason = %v, total = %v, want damaged 100, expired 150, total 250", byReason, report.TotalCost)
+	}
+}
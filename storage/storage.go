@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"errors"
 
 	"market_system/models"
 )
@@ -22,21 +23,117 @@ type StorageI interface {
 	Transaction() TransactionRepoI
 	Shift() ShiftRepoI
 	Brand() BrandRepoI
+	BranchPrice() BranchPriceRepoI
+	PriceChange() PriceChangeRepoI
+	Promotion() PromotionRepoI
+	Customer() CustomerRepoI
+	LoyaltyTier() LoyaltyTierRepoI
+	Loyalty() LoyaltyRepoI
+	GiftCard() GiftCardRepoI
+	PurchaseOrder() PurchaseOrderRepoI
+	SupplierPayable() SupplierPayableRepoI
+	SupplierReturn() SupplierReturnRepoI
+	Transfer() TransferRepoI
+	StockMovement() StockMovementRepoI
+	InventoryCount() InventoryCountRepoI
+	WriteOff() WriteOffRepoI
+	StockLot() StockLotRepoI
+	StockLevel() StockLevelRepoI
+	Report() ReportRepoI
+	ProductClass() ProductClassRepoI
+	ExportJob() ExportJobRepoI
+	ProductImport() ProductImportRepoI
+	SupplierInvoice() SupplierInvoiceRepoI
 }
 
+// ErrPriceProductMissing is returned when a price is resolved without a
+// product id or barcode to pick the product by.
+var ErrPriceProductMissing = errors.New("product id or barcode is required")
+
+// ErrNotEnoughPoints is returned when a redemption or adjustment would take a
+// customer's points balance below zero.
+var ErrNotEnoughPoints = errors.New("not enough loyalty points")
+
+// ErrSaleFinished is returned when a sale that is already finished would be
+// finished again or canceled. ErrSaleClosed is returned when a canceled or
+// returned sale would be finished, canceled or returned, ErrSaleNotFinished
+// when a sale that was never paid would be returned.
+var (
+	ErrSaleFinished    = errors.New("sale is finished")
+	ErrSaleClosed      = errors.New("sale is canceled or returned")
+	ErrSaleNotFinished = errors.New("sale is not finished")
+)
+
+// ErrGiftCardNotUsable is returned when a gift card is not active or has
+// expired, ErrNotEnoughGiftCardBalance when it can not cover the amount.
+var (
+	ErrGiftCardNotUsable        = errors.New("gift card is not active or expired")
+	ErrNotEnoughGiftCardBalance = errors.New("not enough gift card balance")
+)
+
+// ErrPurchaseOrderNotReceivable is returned when goods are received against a
+// purchase order that has not been sent or is already closed.
+var ErrPurchaseOrderNotReceivable = errors.New("purchase order is not open for receiving")
+
+// ErrNotEnoughStock is returned when a document would take more of a barcode
+// out of a branch than the branch has in stock.
+var ErrNotEnoughStock = errors.New("not enough stock")
+
+// ErrInventoryCountInProgress is returned when a count is opened for a branch
+// that already has an open count. ErrNegativeCount is returned when scan
+// corrections would take the counted quantity of a barcode below zero.
+var (
+	ErrInventoryCountInProgress = errors.New("branch already has an open inventory count")
+	ErrNegativeCount            = errors.New("counted quantity can not be negative")
+)
+
+// ErrIncomeFinished is returned when the lines of a posted income would be
+// changed. ErrSupplierInvoiceLineInvalid is returned when an invoice line
+// the file gave wrong is matched to a product; it can only be skipped.
+// ErrSupplierInvoiceUnresolved is returned when an income is posted while
+// lines of its supplier invoice are neither matched nor skipped.
+// ErrIncomeFinishedStatus is returned when an income would be saved as
+// finished instead of being posted.
+var (
+	ErrIncomeFinished             = errors.New("income is finished")
+	ErrSupplierInvoiceLineInvalid = errors.New("invoice line is invalid and can only be skipped")
+	ErrSupplierInvoiceUnresolved  = errors.New("supplier invoice lines are not matched or skipped")
+	ErrIncomeFinishedStatus       = errors.New("an income is finished only by posting it")
+)
+
+// ErrCategoryNotFound is returned when the parent a category is created or
+// moved under does not exist. ErrCategoryCycle is returned when a category
+// would be moved under itself or one of its descendants.
+var (
+	ErrCategoryNotFound = errors.New("parent category not found")
+	ErrCategoryCycle    = errors.New("category can not be moved under itself or its subcategory")
+)
+
 type CategoryRepoI interface {
 	Create(ctx context.Context, req *models.CreateCategory) (*models.Category, error)
 	GetByID(ctx context.Context, req *models.CategoryPrimaryKey) (*models.Category, error)
 	GetList(ctx context.Context, req *models.GetListCategoryRequest) (*models.GetListCategoryResponse, error)
 	Update(ctx context.Context, req *models.UpdateCategory) (int64, error)
 	Delete(ctx context.Context, req *models.CategoryPrimaryKey) error
+	Move(ctx context.Context, req *models.MoveCategory) (int64, error)
+	Tree(ctx context.Context, req *models.GetCategoryTreeRequest) (*models.GetCategoryTreeResponse, error)
+	Breadcrumbs(ctx context.Context, req *models.CategoryPrimaryKey) (*models.GetCategoryBreadcrumbsResponse, error)
 }
+
+// ErrBrandInUse is returned when a brand that products, categories or
+// promotions refer to is deleted without a brand to move them to.
+// ErrBrandNotFound is returned when that brand does not exist.
+var (
+	ErrBrandInUse    = errors.New("brand is used")
+	ErrBrandNotFound = errors.New("brand to reassign to not found")
+)
+
 type BrandRepoI interface {
 	Create(ctx context.Context, req *models.CreateBrand) (*models.Brand, error)
 	GetByID(ctx context.Context, req *models.BrandPrimaryKey) (*models.Brand, error)
 	GetList(ctx context.Context, req *models.GetListBrandRequest) (*models.GetListBrandResponse, error)
 	Update(ctx context.Context, req *models.UpdateBrand) (int64, error)
-	Delete(ctx context.Context, req *models.BrandPrimaryKey) error
+	Delete(ctx context.Context, req *models.DeleteBrand) error
 }
 type UserRepoI interface {
 	Create(ctx context.Context, req *models.CreateUser) (*models.User, error)
@@ -74,8 +171,10 @@ type ProductRepoI interface {
 	Create(ctx context.Context, req *models.CreateProduct) (*models.Product, error)
 	GetByID(ctx context.Context, req *models.ProductPrimaryKey) (*models.Product, error)
 	GetList(ctx context.Context, req *models.GetListProductRequest) (*models.GetListProductResponse, error)
+	Search(ctx context.Context, req *models.SearchProductRequest) (*models.SearchProductResponse, error)
 	Update(ctx context.Context, req *models.UpdateProduct) (int64, error)
 	Delete(ctx context.Context, req *models.ProductPrimaryKey) error
+	IndexSearch(ctx context.Context) (int64, error)
 }
 
 type IncomeRepoI interface {
@@ -84,6 +183,7 @@ type IncomeRepoI interface {
 	GetList(ctx context.Context, req *models.GetListIncomeRequest) (*models.GetListIncomeResponse, error)
 	Update(ctx context.Context, req *models.UpdateIncome) (int64, error)
 	Delete(ctx context.Context, req *models.IncomePrimaryKey) error
+	Finish(ctx context.Context, req *models.IncomePrimaryKey) (int64, error)
 }
 
 type IncomeProductRepoI interface {
@@ -100,6 +200,7 @@ type RemainderRepoI interface {
 	GetList(ctx context.Context, req *models.GetListRemainderRequest) (*models.GetListRemainderResponse, error)
 	Update(ctx context.Context, req *models.UpdateRemainder) (int64, error)
 	Delete(ctx context.Context, req *models.RemainderPrimaryKey) error
+	Deduct(ctx context.Context, req *models.DeductRemainder) error
 }
 
 type SaleRepoI interface {
@@ -108,6 +209,9 @@ type SaleRepoI interface {
 	GetList(ctx context.Context, req *models.GetListSaleRequest) (*models.GetListSaleResponse, error)
 	Update(ctx context.Context, req *models.UpdateSale) (int64, error)
 	Delete(ctx context.Context, req *models.SalePrimaryKey) error
+	Finish(ctx context.Context, req *models.FinishSale) error
+	Cancel(ctx context.Context, req *models.SalePrimaryKey) error
+	Return(ctx context.Context, req *models.ReturnSale) (string, error)
 }
 
 type SaleProductRepoI interface {
@@ -141,3 +245,196 @@ type ShiftRepoI interface {
 	Update(ctx context.Context, req *models.UpdateShift) (int64, error)
 	Delete(ctx context.Context, req *models.ShiftPrimaryKey) error
 }
+
+type BranchPriceRepoI interface {
+	Create(ctx context.Context, req *models.CreateBranchPrice) (*models.BranchPrice, error)
+	GetByID(ctx context.Context, req *models.BranchPricePrimaryKey) (*models.BranchPrice, error)
+	GetList(ctx context.Context, req *models.GetListBranchPriceRequest) (*models.GetListBranchPriceResponse, error)
+	Update(ctx context.Context, req *models.UpdateBranchPrice) (int64, error)
+	Delete(ctx context.Context, req *models.BranchPricePrimaryKey) error
+	Resolve(ctx context.Context, req *models.ResolvePriceRequest) (*models.ResolvedPrice, error)
+	History(ctx context.Context, req *models.BranchPriceHistoryRequest) (*models.BranchPriceHistoryResponse, error)
+}
+
+type PriceChangeRepoI interface {
+	Create(ctx context.Context, req *models.CreatePriceChange) (*models.PriceChange, error)
+	GetByID(ctx context.Context, req *models.PriceChangePrimaryKey) (*models.PriceChange, error)
+	GetList(ctx context.Context, req *models.GetListPriceChangeRequest) (*models.GetListPriceChangeResponse, error)
+	Update(ctx context.Context, req *models.UpdatePriceChange) (int64, error)
+	Delete(ctx context.Context, req *models.PriceChangePrimaryKey) error
+	Approve(ctx context.Context, req *models.ApprovePriceChange) (int64, error)
+	Cancel(ctx context.Context, req *models.PriceChangePrimaryKey) (int64, error)
+}
+
+type PromotionRepoI interface {
+	Create(ctx context.Context, req *models.CreatePromotion) (*models.Promotion, error)
+	GetByID(ctx context.Context, req *models.PromotionPrimaryKey) (*models.Promotion, error)
+	GetList(ctx context.Context, req *models.GetListPromotionRequest) (*models.GetListPromotionResponse, error)
+	Update(ctx context.Context, req *models.UpdatePromotion) (int64, error)
+	Delete(ctx context.Context, req *models.PromotionPrimaryKey) error
+	GetActive(ctx context.Context, req *models.GetActivePromotionRequest) ([]*models.Promotion, error)
+	GetBasket(ctx context.Context, req *models.SalePrimaryKey) ([]*models.BasketLine, error)
+	ApplyToSale(ctx context.Context, req *models.ApplySalePromotions) error
+	Report(ctx context.Context, req *models.PromotionReportRequest) (*models.PromotionReportResponse, error)
+}
+
+type CustomerRepoI interface {
+	Create(ctx context.Context, req *models.CreateCustomer) (*models.Customer, error)
+	GetByID(ctx context.Context, req *models.CustomerPrimaryKey) (*models.Customer, error)
+	GetList(ctx context.Context, req *models.GetListCustomerRequest) (*models.GetListCustomerResponse, error)
+	Update(ctx context.Context, req *models.UpdateCustomer) (int64, error)
+	Delete(ctx context.Context, req *models.CustomerPrimaryKey) error
+	AttachToSale(ctx context.Context, req *models.SaleCustomer) (int64, error)
+}
+
+type LoyaltyTierRepoI interface {
+	Create(ctx context.Context, req *models.CreateLoyaltyTier) (*models.LoyaltyTier, error)
+	GetByID(ctx context.Context, req *models.LoyaltyTierPrimaryKey) (*models.LoyaltyTier, error)
+	GetList(ctx context.Context, req *models.GetListLoyaltyTierRequest) (*models.GetListLoyaltyTierResponse, error)
+	Update(ctx context.Context, req *models.UpdateLoyaltyTier) (int64, error)
+	Delete(ctx context.Context, req *models.LoyaltyTierPrimaryKey) error
+}
+
+type LoyaltyRepoI interface {
+	SetEarnRate(ctx context.Context, req *models.SetLoyaltyEarnRate) (*models.LoyaltyEarnRate, error)
+	GetListEarnRate(ctx context.Context, req *models.GetListLoyaltyEarnRateRequest) (*models.GetListLoyaltyEarnRateResponse, error)
+	DeleteEarnRate(ctx context.Context, req *models.LoyaltyEarnRatePrimaryKey) error
+	ApplySale(ctx context.Context, req *models.ApplySaleLoyalty) error
+	Adjust(ctx context.Context, req *models.AdjustLoyaltyPoints) error
+	History(ctx context.Context, req *models.LoyaltyHistoryRequest) (*models.LoyaltyHistoryResponse, error)
+	RepeatCustomerReport(ctx context.Context, req *models.RepeatCustomerReportRequest) (*models.RepeatCustomerReportResponse, error)
+}
+
+type GiftCardRepoI interface {
+	Sell(ctx context.Context, req *models.SellGiftCard) (*models.GiftCard, error)
+	CreateStoreCredit(ctx context.Context, req *models.CreateStoreCredit) (*models.GiftCard, error)
+	GetByID(ctx context.Context, req *models.GiftCardPrimaryKey) (*models.GiftCard, error)
+	GetByCode(ctx context.Context, req *models.GiftCardCode) (*models.GiftCard, error)
+	GetList(ctx context.Context, req *models.GetListGiftCardRequest) (*models.GetListGiftCardResponse, error)
+	ActivateSale(ctx context.Context, req *models.SalePrimaryKey) (float64, error)
+	Redeem(ctx context.Context, req *models.RedeemGiftCard) (*models.GiftCard, error)
+	History(ctx context.Context, req *models.GiftCardPrimaryKey) (*models.GiftCardHistoryResponse, error)
+	LiabilityReport(ctx context.Context, req *models.GiftCardLiabilityRequest) (*models.GiftCardLiabilityResponse, error)
+}
+
+type PurchaseOrderRepoI interface {
+	Create(ctx context.Context, req *models.CreatePurchaseOrder) (*models.PurchaseOrder, error)
+	GetByID(ctx context.Context, req *models.PurchaseOrderPrimaryKey) (*models.PurchaseOrder, error)
+	GetList(ctx context.Context, req *models.GetListPurchaseOrderRequest) (*models.GetListPurchaseOrderResponse, error)
+	Update(ctx context.Context, req *models.UpdatePurchaseOrder) (int64, error)
+	Delete(ctx context.Context, req *models.PurchaseOrderPrimaryKey) error
+	SetStatus(ctx context.Context, req *models.SetPurchaseOrderStatus) (int64, error)
+	Receive(ctx context.Context, req *models.ReceivePurchaseOrder) (string, error)
+	FillRateReport(ctx context.Context, req *models.SupplierFillRateRequest) (*models.SupplierFillRateResponse, error)
+}
+
+type SupplierPayableRepoI interface {
+	CreatePayment(ctx context.Context, req *models.CreateSupplierPayment) (*models.SupplierTransaction, error)
+	Statement(ctx context.Context, req *models.SupplierStatementRequest) (*models.SupplierStatement, error)
+	Aging(ctx context.Context, req *models.SupplierAgingRequest) (*models.SupplierAgingResponse, error)
+}
+
+type SupplierReturnRepoI interface {
+	Create(ctx context.Context, req *models.CreateSupplierReturn) (*models.SupplierReturn, error)
+	GetByID(ctx context.Context, req *models.SupplierReturnPrimaryKey) (*models.SupplierReturn, error)
+	GetList(ctx context.Context, req *models.GetListSupplierReturnRequest) (*models.GetListSupplierReturnResponse, error)
+	Update(ctx context.Context, req *models.UpdateSupplierReturn) (int64, error)
+	Delete(ctx context.Context, req *models.SupplierReturnPrimaryKey) error
+	Post(ctx context.Context, req *models.SupplierReturnPrimaryKey) (int64, error)
+	Cancel(ctx context.Context, req *models.SupplierReturnPrimaryKey) (int64, error)
+}
+
+type TransferRepoI interface {
+	Create(ctx context.Context, req *models.CreateTransfer) (*models.Transfer, error)
+	GetByID(ctx context.Context, req *models.TransferPrimaryKey) (*models.Transfer, error)
+	GetList(ctx context.Context, req *models.GetListTransferRequest) (*models.GetListTransferResponse, error)
+	Update(ctx context.Context, req *models.UpdateTransfer) (int64, error)
+	Delete(ctx context.Context, req *models.TransferPrimaryKey) error
+	Dispatch(ctx context.Context, req *models.TransferPrimaryKey) (int64, error)
+	Receive(ctx context.Context, req *models.ReceiveTransfer) (int64, error)
+	Cancel(ctx context.Context, req *models.TransferPrimaryKey) (int64, error)
+	InTransit(ctx context.Context, req *models.InTransitRequest) (*models.InTransitResponse, error)
+}
+
+type StockMovementRepoI interface {
+	GetList(ctx context.Context, req *models.GetListStockMovementRequest) (*models.GetListStockMovementResponse, error)
+}
+
+type InventoryCountRepoI interface {
+	Create(ctx context.Context, req *models.CreateInventoryCount) (*models.InventoryCount, error)
+	GetByID(ctx context.Context, req *models.InventoryCountPrimaryKey) (*models.InventoryCount, error)
+	GetList(ctx context.Context, req *models.GetListInventoryCountRequest) (*models.GetListInventoryCountResponse, error)
+	Scan(ctx context.Context, req *models.ScanInventoryCount) (int64, error)
+	Post(ctx context.Context, req *models.PostInventoryCount) (int64, error)
+	Cancel(ctx context.Context, req *models.InventoryCountPrimaryKey) (int64, error)
+}
+
+type WriteOffRepoI interface {
+	Create(ctx context.Context, req *models.CreateWriteOff) (*models.WriteOff, error)
+	GetByID(ctx context.Context, req *models.WriteOffPrimaryKey) (*models.WriteOff, error)
+	GetList(ctx context.Context, req *models.GetListWriteOffRequest) (*models.GetListWriteOffResponse, error)
+	Update(ctx context.Context, req *models.UpdateWriteOff) (int64, error)
+	Delete(ctx context.Context, req *models.WriteOffPrimaryKey) error
+	Post(ctx context.Context, req *models.PostWriteOff) (int64, error)
+	Approve(ctx context.Context, req *models.ReviewWriteOff) (int64, error)
+	Reject(ctx context.Context, req *models.ReviewWriteOff) (int64, error)
+	Cancel(ctx context.Context, req *models.WriteOffPrimaryKey) (int64, error)
+	ShrinkageReport(ctx context.Context, req *models.ShrinkageReportRequest) (*models.ShrinkageReportResponse, error)
+}
+
+type StockLotRepoI interface {
+	GetList(ctx context.Context, req *models.GetListStockLotRequest) (*models.GetListStockLotResponse, error)
+	Expiring(ctx context.Context, req *models.ExpiringStockLotRequest) (*models.ExpiringStockLotResponse, error)
+}
+
+type StockLevelRepoI interface {
+	Create(ctx context.Context, req *models.CreateStockLevel) (*models.StockLevel, error)
+	GetByID(ctx context.Context, req *models.StockLevelPrimaryKey) (*models.StockLevel, error)
+	GetList(ctx context.Context, req *models.GetListStockLevelRequest) (*models.GetListStockLevelResponse, error)
+	Update(ctx context.Context, req *models.UpdateStockLevel) (int64, error)
+	Delete(ctx context.Context, req *models.StockLevelPrimaryKey) error
+	LowStock(ctx context.Context, req *models.LowStockRequest) (*models.LowStockResponse, error)
+	Replenishment(ctx context.Context, req *models.ReplenishmentRequest) (*models.ReplenishmentResponse, error)
+}
+
+type ReportRepoI interface {
+	Sales(ctx context.Context, req *models.SalesReportRequest) (*models.SalesReportResponse, error)
+	TopProducts(ctx context.Context, req *models.TopProductsRequest) (*models.TopProductsResponse, error)
+	Heatmap(ctx context.Context, req *models.HeatmapRequest) (*models.HeatmapResponse, error)
+	Cashiers(ctx context.Context, req *models.CashierReportRequest) (*models.CashierReportResponse, error)
+	InventoryValuation(ctx context.Context, req *models.InventoryValuationRequest) (*models.InventoryValuationResponse, error)
+	StockAging(ctx context.Context, req *models.StockAgingRequest) (*models.StockAgingResponse, error)
+}
+
+type ProductClassRepoI interface {
+	Run(ctx context.Context, req *models.RunProductClass) (*models.ProductClassRun, error)
+	GetRun(ctx context.Context, req *models.ProductClassRunPrimaryKey) (*models.ProductClassRun, error)
+	GetListRuns(ctx context.Context, req *models.GetListProductClassRunRequest) (*models.GetListProductClassRunResponse, error)
+	GetList(ctx context.Context, req *models.GetListProductClassRequest) (*models.GetListProductClassResponse, error)
+}
+
+type ExportJobRepoI interface {
+	Create(ctx context.Context, req *models.CreateExportJob) (*models.ExportJob, error)
+	GetByID(ctx context.Context, req *models.ExportJobPrimaryKey) (*models.ExportJob, error)
+	GetList(ctx context.Context, req *models.GetListExportJobRequest) (*models.GetListExportJobResponse, error)
+	Update(ctx context.Context, req *models.UpdateExportJob) (int64, error)
+	Delete(ctx context.Context, req *models.ExportJobPrimaryKey) error
+	FailUnfinished(ctx context.Context) (int64, error)
+}
+
+type ProductImportRepoI interface {
+	Create(ctx context.Context, req *models.CreateProductImport) (*models.ProductImport, error)
+	GetByID(ctx context.Context, req *models.ProductImportPrimaryKey) (*models.ProductImport, error)
+	GetList(ctx context.Context, req *models.GetListProductImportRequest) (*models.GetListProductImportResponse, error)
+	GetRows(ctx context.Context, req *models.GetListProductImportRowRequest) (*models.GetListProductImportRowResponse, error)
+	Start(ctx context.Context, req *models.ProductImportPrimaryKey) (int64, error)
+	Run(ctx context.Context, req *models.ProductImportPrimaryKey) (*models.ProductImport, error)
+	FailUnfinished(ctx context.Context) (int64, error)
+}
+
+type SupplierInvoiceRepoI interface {
+	Import(ctx context.Context, req *models.ImportSupplierInvoice) (*models.SupplierInvoice, error)
+	GetByID(ctx context.Context, req *models.SupplierInvoicePrimaryKey) (*models.SupplierInvoice, error)
+	GetList(ctx context.Context, req *models.GetListSupplierInvoiceRequest) (*models.GetListSupplierInvoiceResponse, error)
+	ResolveLine(ctx context.Context, req *models.ResolveSupplierInvoiceLine) (*models.SupplierInvoiceLine, error)
+}